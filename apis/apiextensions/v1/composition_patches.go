@@ -18,6 +18,7 @@ package v1
 
 import (
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -36,6 +37,18 @@ const (
 	PatchTypeToCompositeFieldPath   PatchType = "ToCompositeFieldPath"
 	PatchTypeCombineFromComposite   PatchType = "CombineFromComposite"
 	PatchTypeCombineToComposite     PatchType = "CombineToComposite"
+	PatchTypeFromReferencedKey      PatchType = "FromReferencedKey"
+	PatchTypeToEnvironmentConfig    PatchType = "ToEnvironmentConfig"
+)
+
+// A ReferencedKeyKind is the kind of resource a FromReferencedKey patch reads
+// a key from.
+type ReferencedKeyKind string
+
+// Referenced key kinds.
+const (
+	ReferencedKeyKindSecret    ReferencedKeyKind = "Secret"
+	ReferencedKeyKindConfigMap ReferencedKeyKind = "ConfigMap"
 )
 
 // A FromFieldPathPolicy determines how to patch from a field path.
@@ -75,13 +88,13 @@ type Patch struct {
 	// Type sets the patching behaviour to be used. Each patch type may require
 	// its own fields to be set on the Patch object.
 	// +optional
-	// +kubebuilder:validation:Enum=FromCompositeFieldPath;PatchSet;ToCompositeFieldPath;CombineFromComposite;CombineToComposite
+	// +kubebuilder:validation:Enum=FromCompositeFieldPath;PatchSet;ToCompositeFieldPath;CombineFromComposite;CombineToComposite;FromReferencedKey;ToEnvironmentConfig
 	// +kubebuilder:default=FromCompositeFieldPath
 	Type PatchType `json:"type,omitempty"`
 
 	// FromFieldPath is the path of the field on the resource whose value is
-	// to be used as input. Required when type is FromCompositeFieldPath or
-	// ToCompositeFieldPath.
+	// to be used as input. Required when type is FromCompositeFieldPath,
+	// ToCompositeFieldPath, or ToEnvironmentConfig.
 	// +optional
 	FromFieldPath *string `json:"fromFieldPath,omitempty"`
 
@@ -90,6 +103,15 @@ type Patch struct {
 	// +optional
 	Combine *Combine `json:"combine,omitempty"`
 
+	// ReferencedKey is the patch configuration for a FromReferencedKey patch.
+	// +optional
+	ReferencedKey *ReferencedKey `json:"referencedKey,omitempty"`
+
+	// EnvironmentConfig is the patch configuration for a ToEnvironmentConfig
+	// patch.
+	// +optional
+	EnvironmentConfig *EnvironmentConfigKey `json:"environmentConfig,omitempty"`
+
 	// ToFieldPath is the path of the field on the resource whose value will
 	// be changed with the result of transforms. Leave empty if you'd like to
 	// propagate to the same path as fromFieldPath.
@@ -108,6 +130,19 @@ type Patch struct {
 	// Policy configures the specifics of patching behaviour.
 	// +optional
 	Policy *PatchPolicy `json:"policy,omitempty"`
+
+	// MatchCondition is a CEL expression that must evaluate to true for the
+	// patch to be applied. The composite resource is available to the
+	// expression as the variable 'xr', for example
+	// xr.spec.parameters.environment == "production". The patch is always
+	// applied if no condition is set. A skipped patch is skipped entirely, so
+	// a Required fromFieldPath policy doesn't apply, and any field the patch
+	// set previously is removed the next time the patched resource is
+	// applied. Use has() to test for optional fields; an expression that
+	// can't be evaluated is an error. A condition on a PatchSet patch applies
+	// to every patch in the set.
+	// +optional
+	MatchCondition *string `json:"matchCondition,omitempty"`
 }
 
 // GetFromFieldPath returns the FromFieldPath for this Patch, or an empty string if it is nil.
@@ -126,6 +161,14 @@ func (p *Patch) GetToFieldPath() string {
 	return *p.ToFieldPath
 }
 
+// GetMatchCondition returns the MatchCondition for this Patch, or an empty string if it is nil.
+func (p *Patch) GetMatchCondition() string {
+	if p.MatchCondition == nil {
+		return ""
+	}
+	return *p.MatchCondition
+}
+
 // GetType returns the patch type. If the type is not set, it returns the default type.
 func (p *Patch) GetType() PatchType {
 	if p.Type == "" {
@@ -141,6 +184,26 @@ func (p *Patch) Validate() *field.Error {
 		if p.FromFieldPath == nil {
 			return field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for patch type %s", p.Type))
 		}
+	case PatchTypeFromReferencedKey:
+		if p.ReferencedKey == nil {
+			return field.Required(field.NewPath("referencedKey"), fmt.Sprintf("referencedKey must be set for patch type %s", p.Type))
+		}
+		if p.ToFieldPath == nil {
+			return field.Required(field.NewPath("toFieldPath"), fmt.Sprintf("toFieldPath must be set for patch type %s", p.Type))
+		}
+		if err := p.ReferencedKey.Validate(); err != nil {
+			return verrors.WrapFieldError(err, field.NewPath("referencedKey"))
+		}
+	case PatchTypeToEnvironmentConfig:
+		if p.FromFieldPath == nil {
+			return field.Required(field.NewPath("fromFieldPath"), fmt.Sprintf("fromFieldPath must be set for patch type %s", p.Type))
+		}
+		if p.EnvironmentConfig == nil {
+			return field.Required(field.NewPath("environmentConfig"), fmt.Sprintf("environmentConfig must be set for patch type %s", p.Type))
+		}
+		if err := p.EnvironmentConfig.Validate(); err != nil {
+			return verrors.WrapFieldError(err, field.NewPath("environmentConfig"))
+		}
 	case PatchTypePatchSet:
 		if p.PatchSetName == nil {
 			return field.Required(field.NewPath("patchSetName"), fmt.Sprintf("patchSetName must be set for patch type %s", p.Type))
@@ -165,6 +228,118 @@ func (p *Patch) Validate() *field.Error {
 	return nil
 }
 
+// A ReferencedKey identifies a key of a Secret or ConfigMap that is referenced
+// by the composite resource. Crossplane reads the value of the key and patches
+// it into the composed resource.
+//
+// The referenced resource must be in the namespace of the composite resource's
+// claim. A composite resource that isn't bound to a claim can't reference keys.
+// Values read from a Secret remain base64 encoded, and may only be patched into
+// the data of a composed Secret.
+type ReferencedKey struct {
+	// Kind of the referenced resource.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind ReferencedKeyKind `json:"kind"`
+
+	// NameFieldPath is the path of the field on the composite resource whose
+	// value is the name of the referenced resource.
+	NameFieldPath string `json:"nameFieldPath"`
+
+	// Key of the referenced resource's data whose value is to be used as
+	// input.
+	Key string `json:"key"`
+}
+
+// Validate the ReferencedKey object.
+func (r *ReferencedKey) Validate() *field.Error {
+	switch r.Kind {
+	case ReferencedKeyKindSecret, ReferencedKeyKindConfigMap:
+	default:
+		return field.NotSupported(field.NewPath("kind"), r.Kind, []string{string(ReferencedKeyKindSecret), string(ReferencedKeyKindConfigMap)})
+	}
+	if r.NameFieldPath == "" {
+		return field.Required(field.NewPath("nameFieldPath"), "nameFieldPath must be set")
+	}
+	if r.Key == "" {
+		return field.Required(field.NewPath("key"), "key must be set")
+	}
+	return nil
+}
+
+// An EnvironmentConfigKey identifies a key of an EnvironmentConfig's data.
+// Crossplane writes the value read from the composed resource to the key.
+//
+// Crossplane only writes keys that the EnvironmentConfig lists in its
+// apiextensions.crossplane.io/composition-writable-keys annotation. Values are
+// written at most once: Crossplane won't overwrite a key that already has a
+// different value, even if it wrote the existing value. When several composite
+// resources write the same key the first write wins, and the others report a
+// conflict until their value matches or the key is removed.
+type EnvironmentConfigKey struct {
+	// Name of the EnvironmentConfig to write to.
+	Name string `json:"name"`
+
+	// Key of the EnvironmentConfig's data to write to.
+	Key string `json:"key"`
+}
+
+// Validate the EnvironmentConfigKey object.
+func (k *EnvironmentConfigKey) Validate() *field.Error {
+	if k.Name == "" {
+		return field.Required(field.NewPath("name"), "name must be set")
+	}
+	if k.Key == "" {
+		return field.Required(field.NewPath("key"), "key must be set")
+	}
+	return nil
+}
+
+// IsSecretDataFieldPath returns true if the supplied field path is within the
+// data of a resource with the supplied API version and kind, and that resource
+// is a Secret.
+func IsSecretDataFieldPath(apiVersion, kind, path string) bool {
+	if apiVersion != "v1" || kind != "Secret" {
+		return false
+	}
+	return strings.HasPrefix(path, "data.") || strings.HasPrefix(path, "data[")
+}
+
+// ReadsSecretData returns true if the supplied patch copies a value from the
+// data or stringData of a composed resource to the composite resource. Such a
+// patch could expose a value read from a Secret by a FromReferencedKey patch.
+func ReadsSecretData(p Patch) bool {
+	var paths []string
+	switch p.GetType() { //nolint:exhaustive // Only patches to the composite resource read from the composed resource.
+	case PatchTypeToCompositeFieldPath:
+		paths = append(paths, p.GetFromFieldPath())
+	case PatchTypeCombineToComposite:
+		if p.Combine != nil {
+			for _, v := range p.Combine.Variables {
+				paths = append(paths, v.FromFieldPath)
+			}
+		}
+	}
+	for _, path := range paths {
+		for _, f := range []string{"data", "stringData"} {
+			if path == f || strings.HasPrefix(path, f+".") || strings.HasPrefix(path, f+"[") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReceivesReferencedSecret returns true if any of the supplied patches reads a
+// value from a referenced Secret.
+func ReceivesReferencedSecret(ps []Patch) bool {
+	for _, p := range ps {
+		if p.GetType() == PatchTypeFromReferencedKey && p.ReferencedKey != nil && p.ReferencedKey.Kind == ReferencedKeyKindSecret {
+			return true
+		}
+	}
+	return false
+}
+
 // A CombineVariable defines the source of a value that is combined with
 // others to form and patch an output value. Currently, this only supports
 // retrieving values from a field path.
@@ -99,6 +99,95 @@ func TestPatchValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidFromReferencedKey": {
+			reason: "FromReferencedKey patch with ReferencedKey and ToFieldPath set should be valid",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeFromReferencedKey,
+					ReferencedKey: &ReferencedKey{
+						Kind:          ReferencedKeyKindConfigMap,
+						NameFieldPath: "spec.configMapRef.name",
+						Key:           "region",
+					},
+					ToFieldPath: ptr.To("spec.forProvider.region"),
+				},
+			},
+		},
+		"InvalidFromReferencedKeyMissingReferencedKey": {
+			reason: "Invalid FromReferencedKey missing ReferencedKey should return error",
+			args: args{
+				patch: &Patch{
+					Type:        PatchTypeFromReferencedKey,
+					ToFieldPath: ptr.To("spec.forProvider.region"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "referencedKey",
+				},
+			},
+		},
+		"InvalidFromReferencedKeyMissingKey": {
+			reason: "Invalid FromReferencedKey missing the referenced key should return error",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeFromReferencedKey,
+					ReferencedKey: &ReferencedKey{
+						Kind:          ReferencedKeyKindConfigMap,
+						NameFieldPath: "spec.configMapRef.name",
+					},
+					ToFieldPath: ptr.To("spec.forProvider.region"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "referencedKey.key",
+				},
+			},
+		},
+		"ValidToEnvironmentConfig": {
+			reason: "ToEnvironmentConfig patch with FromFieldPath and EnvironmentConfig set should be valid",
+			args: args{
+				patch: &Patch{
+					Type:              PatchTypeToEnvironmentConfig,
+					FromFieldPath:     ptr.To("status.atProvider.id"),
+					EnvironmentConfig: &EnvironmentConfigKey{Name: "shared", Key: "id"},
+				},
+			},
+		},
+		"InvalidToEnvironmentConfigMissingEnvironmentConfig": {
+			reason: "Invalid ToEnvironmentConfig missing EnvironmentConfig should return error",
+			args: args{
+				patch: &Patch{
+					Type:          PatchTypeToEnvironmentConfig,
+					FromFieldPath: ptr.To("status.atProvider.id"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "environmentConfig",
+				},
+			},
+		},
+		"InvalidToEnvironmentConfigMissingKey": {
+			reason: "Invalid ToEnvironmentConfig missing the EnvironmentConfig key should return error",
+			args: args{
+				patch: &Patch{
+					Type:              PatchTypeToEnvironmentConfig,
+					FromFieldPath:     ptr.To("status.atProvider.id"),
+					EnvironmentConfig: &EnvironmentConfigKey{Name: "shared"},
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeRequired,
+					Field: "environmentConfig.key",
+				},
+			},
+		},
 		"InvalidPatchSetMissingPatchSetName": {
 			reason: "Invalid PatchSet missing PatchSetName should return error",
 			args: args{
@@ -19,8 +19,10 @@ package v1
 import (
 	"encoding/json"
 	"regexp"
+	"time"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -118,6 +120,17 @@ func (t *ConvertTransform) GetFormat() ConvertTransformFormat {
 	return ConvertTransformFormatNone
 }
 
+// GetUnit returns the unit of the transform.
+func (t *ConvertTransform) GetUnit() string {
+	if t.Unit != nil {
+		return *t.Unit
+	}
+	if t.GetFormat() == ConvertTransformFormatDuration {
+		return "s"
+	}
+	return ""
+}
+
 // GetOutputType returns the output type of the transform.
 // It returns an error if the transform type is unknown.
 // It returns nil if the output type is not known.
@@ -472,12 +485,13 @@ const (
 	ConvertTransformFormatNone     ConvertTransformFormat = "none"
 	ConvertTransformFormatQuantity ConvertTransformFormat = "quantity"
 	ConvertTransformFormatJSON     ConvertTransformFormat = "json"
+	ConvertTransformFormatDuration ConvertTransformFormat = "duration"
 )
 
 // IsValid returns true if the format is valid.
 func (c ConvertTransformFormat) IsValid() bool {
 	switch c {
-	case ConvertTransformFormatNone, ConvertTransformFormatQuantity, ConvertTransformFormatJSON:
+	case ConvertTransformFormatNone, ConvertTransformFormatQuantity, ConvertTransformFormatJSON, ConvertTransformFormatDuration:
 		return true
 	}
 	return false
@@ -499,15 +513,30 @@ type ConvertTransform struct {
 	// The expected input format.
 	//
 	// * `quantity` - parses the input as a K8s [`resource.Quantity`](https://pkg.go.dev/k8s.io/apimachinery/pkg/api/resource#Quantity).
-	// Only used during `string -> float64` conversions.
+	// Used during `string -> float64` conversions. Also formats the input as
+	// a K8s quantity during `int64 -> string` and `float64 -> string`
+	// conversions.
+	// * `duration` - parses the input as a Go [`time.Duration`](https://pkg.go.dev/time#ParseDuration)
+	// during `string -> int64` and `string -> float64` conversions, and
+	// formats the input as a duration during `int64 -> string` and
+	// `float64 -> string` conversions.
 	// * `json` - parses the input as a JSON string.
 	// Only used during `string -> object` or `string -> list` conversions.
 	//
 	// If this property is null, the default conversion is applied.
 	//
-	// +kubebuilder:validation:Enum=none;quantity;json
+	// +kubebuilder:validation:Enum=none;quantity;duration;json
 	// +kubebuilder:validation:Default=none
 	Format *ConvertTransformFormat `json:"format,omitempty"`
+
+	// Unit of the number being converted from or to when using the
+	// `quantity` or `duration` formats. For `quantity` this is a K8s
+	// quantity suffix, e.g. `Gi`, and defaults to no suffix. For `duration`
+	// this is a Go duration unit, e.g. `ms`, and defaults to `s`. For example
+	// an int64 of 10 converts to the string `10Gi` with the unit `Gi`, and to
+	// the string `10s` with the format `duration`.
+	// +optional
+	Unit *string `json:"unit,omitempty"`
 }
 
 // Validate returns an error if the ConvertTransform is invalid.
@@ -518,5 +547,20 @@ func (t *ConvertTransform) Validate() *field.Error {
 	if !t.ToType.IsValid() {
 		return field.Invalid(field.NewPath("toType"), t.ToType, "invalid type")
 	}
+	if t.Unit == nil {
+		return nil
+	}
+	switch t.GetFormat() {
+	case ConvertTransformFormatQuantity:
+		if _, err := resource.ParseQuantity("1" + *t.Unit); err != nil {
+			return field.Invalid(field.NewPath("unit"), *t.Unit, "invalid quantity unit")
+		}
+	case ConvertTransformFormatDuration:
+		if _, err := time.ParseDuration("1" + *t.Unit); err != nil {
+			return field.Invalid(field.NewPath("unit"), *t.Unit, "invalid duration unit")
+		}
+	case ConvertTransformFormatNone, ConvertTransformFormatJSON:
+		return field.Invalid(field.NewPath("unit"), *t.Unit, "unit is only supported with the quantity and duration formats")
+	}
 	return nil
 }
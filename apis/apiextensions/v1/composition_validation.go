@@ -17,6 +17,11 @@ limitations under the License.
 package v1
 
 import (
+	"encoding/json"
+	"strings"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -31,7 +36,10 @@ func (c *Composition) Validate() (warns []string, errs field.ErrorList) {
 		c.validateMode,
 		c.validatePatchSets,
 		c.validateResources,
+		c.validateReferencedSecretPatches,
 		c.validatePipeline,
+		c.validateDeletionOrder,
+		c.validateCompositeConnectionDetails,
 	}
 	for _, f := range validations {
 		errs = append(errs, f()...)
@@ -52,6 +60,11 @@ func (c *Composition) validateMode() (errs field.ErrorList) {
 		if len(c.Spec.Resources) == 0 {
 			errs = append(errs, field.Required(field.NewPath("spec", "resources"), "an array of resources is required in Resources mode (the default if no mode is specified)"))
 		}
+		// Only a Composition Function pipeline can explicitly mark a
+		// composite resource ready.
+		if c.Spec.CompositeReadiness != nil && *c.Spec.CompositeReadiness == CompositeReadinessExplicit {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "compositeReadiness"), *c.Spec.CompositeReadiness, "Explicit composite readiness requires Pipeline mode"))
+		}
 	case CompositionModePipeline:
 		if len(c.Spec.Pipeline) == 0 {
 			errs = append(errs, field.Required(field.NewPath("spec", "pipeline"), "an array of pipeline steps is required in Pipeline mode"))
@@ -89,6 +102,47 @@ func (c *Composition) validatePipeline() (errs field.ErrorList) {
 	return errs
 }
 
+// validateDeletionOrder checks that each composed resource is deleted in at
+// most one phase, and that phase timeouts are positive.
+func (c *Composition) validateDeletionOrder() (errs field.ErrorList) {
+	seen := map[string]bool{}
+	for i, p := range c.Spec.DeletionOrder {
+		if len(p.Resources) == 0 {
+			errs = append(errs, field.Required(field.NewPath("spec", "deletionOrder").Index(i).Child("resources"), "a deletion phase must include at least one resource"))
+		}
+		for j, r := range p.Resources {
+			if seen[r] {
+				errs = append(errs, field.Duplicate(field.NewPath("spec", "deletionOrder").Index(i).Child("resources").Index(j), r))
+			}
+			seen[r] = true
+		}
+		if p.Timeout != nil && p.Timeout.Duration <= 0 {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "deletionOrder").Index(i).Child("timeout"), p.Timeout.Duration.String(), "must be positive"))
+		}
+	}
+	return errs
+}
+
+// validateCompositeConnectionDetails checks that composite connection details
+// have unique names, and are only read from the composite resource's spec.
+func (c *Composition) validateCompositeConnectionDetails() (errs field.ErrorList) {
+	seen := map[string]bool{}
+	for i, d := range c.Spec.CompositeConnectionDetails {
+		p := field.NewPath("spec", "compositeConnectionDetails").Index(i)
+		if d.Name == "" {
+			errs = append(errs, field.Required(p.Child("name"), "a composite connection detail must have a name"))
+		}
+		if seen[d.Name] {
+			errs = append(errs, field.Duplicate(p.Child("name"), d.Name))
+		}
+		seen[d.Name] = true
+		if !strings.HasPrefix(d.FromFieldPath, "spec.") {
+			errs = append(errs, field.Invalid(p.Child("fromFieldPath"), d.FromFieldPath, "must be a field path within the composite resource's spec"))
+		}
+	}
+	return errs
+}
+
 // validatePatchSets checks that:
 // - patchSets are composed of valid patches
 // - there are no nested patchSets
@@ -130,21 +184,89 @@ func (c *Composition) validateResources() (errs field.ErrorList) {
 		errs = append(errs, err...)
 	}
 	for i, res := range c.Spec.Resources {
+		if res.NameTemplate != nil {
+			if _, err := template.New("").Parse(*res.NameTemplate); err != nil {
+				errs = append(errs, field.Invalid(field.NewPath("spec", "resources").Index(i).Child("nameTemplate"), *res.NameTemplate, err.Error()))
+			}
+		}
 		for j, patch := range res.Patches {
 			if err := patch.Validate(); err != nil {
 				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("patches").Index(j)))
 			}
 		}
+		for j, mp := range res.MergePolicies {
+			if mp.FieldPath == "" {
+				errs = append(errs, field.Required(field.NewPath("spec", "resources").Index(i).Child("mergePolicies").Index(j).Child("fieldPath"), "fieldPath must be set"))
+			}
+		}
+		for j, fp := range res.InitFieldPaths {
+			switch fp {
+			case "":
+				errs = append(errs, field.Required(field.NewPath("spec", "resources").Index(i).Child("initFieldPaths").Index(j), "field path must be set"))
+			case "apiVersion", "kind", "metadata", "metadata.name", "metadata.namespace":
+				errs = append(errs, field.Invalid(field.NewPath("spec", "resources").Index(i).Child("initFieldPaths").Index(j), fp, "field path identifies the composed resource"))
+			}
+		}
 		for j, rd := range res.ReadinessChecks {
 			if err := rd.Validate(); err != nil {
 				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("readinessChecks").Index(j)))
 			}
 		}
+		for j, cd := range res.ConnectionDetails {
+			for k, t := range cd.Transforms {
+				if err := t.Validate(); err != nil {
+					errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("connectionDetails").Index(j).Child("transforms").Index(k)))
+				}
+			}
+		}
 		// TODO(phisco): we should validate also ConnectionDetails, but would need a major refactoring
 	}
 	return errs
 }
 
+// validateReferencedSecretPatches checks that values read from a referenced
+// Secret are only patched into the data of a composed Secret, and that they
+// aren't copied from a composed resource back to the composite resource, to
+// avoid exposing them in fields that aren't secret.
+func (c *Composition) validateReferencedSecretPatches() (errs field.ErrorList) {
+	sets := make(map[string][]Patch, len(c.Spec.PatchSets))
+	for _, s := range c.Spec.PatchSets {
+		sets[s.Name] = s.Patches
+	}
+	for i, res := range c.Spec.Resources {
+		// Invalid bases are caught by other validation.
+		base := &metav1.TypeMeta{}
+		_ = json.Unmarshal(res.Base.Raw, base)
+
+		resolved := make([][]Patch, len(res.Patches))
+		all := make([]Patch, 0, len(res.Patches))
+		for j, p := range res.Patches {
+			resolved[j] = []Patch{p}
+			if p.Type == PatchTypePatchSet && p.PatchSetName != nil {
+				resolved[j] = sets[*p.PatchSetName]
+			}
+			all = append(all, resolved[j]...)
+		}
+		secret := ReceivesReferencedSecret(all)
+
+		for j, ps := range resolved {
+			for _, pp := range ps {
+				if secret && ReadsSecretData(pp) {
+					errs = append(errs, field.Invalid(field.NewPath("spec", "resources").Index(i).Child("patches").Index(j), pp.GetFromFieldPath(), "values may not be patched from the data of a composed resource that receives values read from a referenced Secret"))
+					continue
+				}
+				if pp.GetType() != PatchTypeFromReferencedKey || pp.ReferencedKey == nil || pp.ReferencedKey.Kind != ReferencedKeyKindSecret {
+					continue
+				}
+				if !IsSecretDataFieldPath(base.APIVersion, base.Kind, pp.GetToFieldPath()) {
+					errs = append(errs, field.Invalid(field.NewPath("spec", "resources").Index(i).Child("patches").Index(j), pp.GetToFieldPath(), "values read from a referenced Secret may only be patched into the data of a composed Secret"))
+				}
+			}
+		}
+	}
+	return errs
+}
+
 // validateResourceNames checks that:
 //  1. Either all resources have a name or they are all anonymous: because if some but not all templates are named it's
 //     safest to refuse to operate. We don't have enough information to use the named composer, but using the anonymous
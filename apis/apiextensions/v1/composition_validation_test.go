@@ -19,9 +19,12 @@ package v1
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 )
@@ -97,6 +100,38 @@ func TestCompositionValidateMode(t *testing.T) {
 				output: field.ErrorList{field.Required(field.NewPath("spec", "resources"), "this test ignores this field")},
 			},
 		},
+		"InvalidExplicitReadinessResources": {
+			reason: "A Resources mode Composition can't require explicit composite readiness",
+			args: args{
+				spec: CompositionSpec{
+					Mode: &resources,
+					Resources: []ComposedTemplate{
+						{Name: ptr.To("cool-template")},
+					},
+					CompositeReadiness: ptr.To(CompositeReadinessExplicit),
+				},
+			},
+			want: want{
+				output: field.ErrorList{field.Invalid(field.NewPath("spec", "compositeReadiness"), "this test ignores this field", "this test ignores this field")},
+			},
+		},
+		"ValidExplicitReadinessPipeline": {
+			reason: "A Pipeline mode Composition may require explicit composite readiness",
+			args: args{
+				spec: CompositionSpec{
+					Mode: &pipeline,
+					Pipeline: []PipelineStep{
+						{
+							Step: "razor",
+						},
+					},
+					CompositeReadiness: ptr.To(CompositeReadinessExplicit),
+				},
+			},
+			want: want{
+				output: nil,
+			},
+		},
 		"ValidPipeline": {
 			reason: "A Pipeline mode Composition with an array of pipeline steps is valid",
 			args: args{
@@ -631,6 +666,117 @@ func TestCompositionValidateResources(t *testing.T) {
 				},
 			},
 		},
+		"InvalidNameTemplate": {
+			reason: "a name template must be a valid Go template",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name:         ptr.To("foo"),
+								NameTemplate: ptr.To("{{ .metadata.name }}-foo"),
+							},
+							{
+								Name:         ptr.To("bar"),
+								NameTemplate: ptr.To("{{ .metadata.name -bar"),
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[1].nameTemplate",
+					},
+				},
+			},
+		},
+		"InvalidInitFieldPaths": {
+			reason: "initialization-only field paths must be set and mustn't identify the composed resource",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name:           ptr.To("foo"),
+								InitFieldPaths: []string{"spec.forProvider.region", "", "metadata.name"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].initFieldPaths[1]",
+					},
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].initFieldPaths[2]",
+					},
+				},
+			},
+		},
+		"InvalidMergePolicyMissingFieldPath": {
+			reason: "a merge policy must specify a field path",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: ptr.To("foo"),
+								MergePolicies: []MergePolicy{
+									{FieldPath: "metadata.labels"},
+									{},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].mergePolicies[1].fieldPath",
+					},
+				},
+			},
+		},
+		"InvalidConnectionDetailTransform": {
+			reason: "a connection detail's transforms must be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name: ptr.To("foo"),
+								ConnectionDetails: []ConnectionDetail{
+									{
+										Name:  ptr.To("port"),
+										Value: ptr.To("5432"),
+										Transforms: []Transform{
+											{Type: TransformTypeString},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].connectionDetails[0].transforms[0].string",
+					},
+				},
+			},
+		},
 		"InvalidComplexNamedResourcesDueToDuplicateNames": {
 			reason: "complex named resources with duplicate names should be invalid",
 			args: args{
@@ -754,3 +900,372 @@ func TestCompositionValidateResources(t *testing.T) {
 		})
 	}
 }
+
+func TestCompositionValidateDeletionOrder(t *testing.T) {
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidNoDeletionOrder": {
+			reason: "no deletion order should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{},
+				},
+			},
+		},
+		"ValidDeletionOrder": {
+			reason: "phases with distinct resources and positive timeouts should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						DeletionOrder: []DeletionPhase{
+							{
+								Resources: []string{"app"},
+								Timeout:   &metav1.Duration{Duration: time.Minute},
+							},
+							{
+								Resources: []string{"database", "network"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidEmptyPhase": {
+			reason: "a phase must include at least one resource",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						DeletionOrder: []DeletionPhase{{}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.deletionOrder[0].resources",
+					},
+				},
+			},
+		},
+		"InvalidDuplicateResource": {
+			reason: "a resource must be deleted in at most one phase",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						DeletionOrder: []DeletionPhase{
+							{
+								Resources: []string{"app"},
+							},
+							{
+								Resources: []string{"database", "app"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeDuplicate,
+						Field: "spec.deletionOrder[1].resources[1]",
+					},
+				},
+			},
+		},
+		"InvalidTimeout": {
+			reason: "a phase's timeout must be positive",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						DeletionOrder: []DeletionPhase{
+							{
+								Resources: []string{"app"},
+								Timeout:   &metav1.Duration{},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.deletionOrder[0].timeout",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErrs := tc.args.comp.validateDeletionOrder()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateDeletionOrder(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionValidateCompositeConnectionDetails(t *testing.T) {
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Valid": {
+			reason: "uniquely named connection details read from the spec should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						CompositeConnectionDetails: []CompositeConnectionDetail{
+							{Name: "endpoint", FromFieldPath: "spec.parameters.endpoint"},
+							{Name: "port", FromFieldPath: "spec.parameters.port"},
+						},
+					},
+				},
+			},
+		},
+		"InvalidMissingName": {
+			reason: "a connection detail must have a name",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						CompositeConnectionDetails: []CompositeConnectionDetail{
+							{FromFieldPath: "spec.parameters.endpoint"},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.compositeConnectionDetails[0].name",
+					},
+				},
+			},
+		},
+		"InvalidDuplicateName": {
+			reason: "connection detail names must be unique",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						CompositeConnectionDetails: []CompositeConnectionDetail{
+							{Name: "endpoint", FromFieldPath: "spec.parameters.endpoint"},
+							{Name: "endpoint", FromFieldPath: "spec.parameters.host"},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeDuplicate,
+						Field: "spec.compositeConnectionDetails[1].name",
+					},
+				},
+			},
+		},
+		"InvalidNotSpec": {
+			reason: "a connection detail must be read from the spec",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						CompositeConnectionDetails: []CompositeConnectionDetail{
+							{Name: "endpoint", FromFieldPath: "status.endpoint"},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.compositeConnectionDetails[0].fromFieldPath",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErrs := tc.args.comp.validateCompositeConnectionDetails()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateCompositeConnectionDetails(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionValidateReferencedSecretPatches(t *testing.T) {
+	secretPatch := Patch{
+		Type:          PatchTypeFromReferencedKey,
+		ReferencedKey: &ReferencedKey{Kind: ReferencedKeyKindSecret, NameFieldPath: "spec.secretRef.name", Key: "password"},
+		ToFieldPath:   ptr.To("data.password"),
+	}
+	toSpec := secretPatch
+	toSpec.ToFieldPath = ptr.To("spec.password")
+	toXR := Patch{
+		Type:          PatchTypeToCompositeFieldPath,
+		FromFieldPath: ptr.To("data.password"),
+		ToFieldPath:   ptr.To("status.password"),
+	}
+	combineToXR := Patch{
+		Type: PatchTypeCombineToComposite,
+		Combine: &Combine{
+			Variables: []CombineVariable{{FromFieldPath: "metadata.name"}, {FromFieldPath: "stringData.password"}},
+			Strategy:  CombineStrategyString,
+			String:    &StringCombine{Format: "%s-%s"},
+		},
+		ToFieldPath: ptr.To("status.password"),
+	}
+
+	type args struct {
+		comp *Composition
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidSecretData": {
+			reason: "a value read from a Secret should be allowed into the data of a composed Secret",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{{
+							Base:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret"}`)},
+							Patches: []Patch{secretPatch},
+						}},
+					},
+				},
+			},
+		},
+		"InvalidNotASecret": {
+			reason: "a value read from a Secret shouldn't be allowed into a composed resource that isn't a Secret",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{{
+							Base:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"Database"}`)},
+							Patches: []Patch{toSpec},
+						}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0]",
+					},
+				},
+			},
+		},
+		"ValidToCompositeWithoutReferencedSecret": {
+			reason: "a composed resource that doesn't receive values read from a Secret may patch its data to the composite resource",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{{
+							Base:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret"}`)},
+							Patches: []Patch{toXR},
+						}},
+					},
+				},
+			},
+		},
+		"InvalidToCompositeSecretData": {
+			reason: "a composed Secret that receives values read from a Secret shouldn't patch its data to the composite resource",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{{
+							Base:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret"}`)},
+							Patches: []Patch{secretPatch, toXR},
+						}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[1]",
+					},
+				},
+			},
+		},
+		"InvalidPatchSetCombineToCompositeSecretData": {
+			reason: "a composed Secret that receives values read from a Secret shouldn't combine its data into the composite resource, even using a patch set",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						PatchSets: []PatchSet{{Name: "status", Patches: []Patch{combineToXR}}},
+						Resources: []ComposedTemplate{{
+							Base:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret"}`)},
+							Patches: []Patch{secretPatch, {Type: PatchTypePatchSet, PatchSetName: ptr.To("status")}},
+						}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[1]",
+					},
+				},
+			},
+		},
+		"InvalidPatchSetNotSecretData": {
+			reason: "a value read from a Secret by a patch set shouldn't be allowed outside the data of a composed Secret",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						PatchSets: []PatchSet{{Name: "secrets", Patches: []Patch{toSpec}}},
+						Resources: []ComposedTemplate{{
+							Base:    runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"Secret"}`)},
+							Patches: []Patch{{Type: PatchTypePatchSet, PatchSetName: ptr.To("secrets")}},
+						}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0]",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotErrs := tc.args.comp.validateReferencedSecretPatches()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateReferencedSecretPatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
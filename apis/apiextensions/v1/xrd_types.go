@@ -25,6 +25,58 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
+// A ClaimPolicy determines whether a composite resource may be claimed.
+type ClaimPolicy string
+
+const (
+	// ClaimPolicyOptional indicates that the composite resource may be
+	// claimed if claim names are specified.
+	ClaimPolicyOptional ClaimPolicy = "Optional"
+
+	// ClaimPolicyRequired indicates that claim names must be specified, so
+	// that the composite resource may be claimed.
+	ClaimPolicyRequired ClaimPolicy = "Required"
+
+	// ClaimPolicyDisallowed indicates that the composite resource may not be
+	// claimed. Claim names must not be specified, no claim CRD is created, and
+	// the composite resource's schema doesn't include claim fields.
+	ClaimPolicyDisallowed ClaimPolicy = "Disallowed"
+)
+
+// A CompositionRefPolicy determines whether a composite resource may switch
+// Compositions after it's created.
+type CompositionRefPolicy string
+
+const (
+	// CompositionRefPolicyMutable indicates that a composite resource's
+	// compositionRef and compositionSelector may be changed at any time.
+	CompositionRefPolicyMutable CompositionRefPolicy = "Mutable"
+
+	// CompositionRefPolicyImmutable indicates that a composite resource's
+	// compositionRef can't be changed once it's set, and its
+	// compositionSelector can't be changed after it's created.
+	CompositionRefPolicyImmutable CompositionRefPolicy = "Immutable"
+)
+
+// ClaimReadinessDetail determines how much detail about a composite
+// resource's readiness is exposed to its claim.
+type ClaimReadinessDetail string
+
+const (
+	// ClaimReadinessDetailNone indicates that a claim only reports whether
+	// its composite resource is ready.
+	ClaimReadinessDetailNone ClaimReadinessDetail = "None"
+
+	// ClaimReadinessDetailCounts indicates that a claim reports how many of
+	// its composite resource's composed resources are ready.
+	ClaimReadinessDetailCounts ClaimReadinessDetail = "Counts"
+
+	// ClaimReadinessDetailResources indicates that a claim reports how many
+	// of its composite resource's composed resources are ready, and the
+	// Composition names of the composed resources that aren't ready.
+	ClaimReadinessDetailResources ClaimReadinessDetail = "Resources"
+)
+
 // CompositeResourceDefinitionSpec specifies the desired state of the definition.
 type CompositeResourceDefinitionSpec struct {
 	// Group specifies the API group of the defined composite resource.
@@ -34,7 +86,9 @@ type CompositeResourceDefinitionSpec struct {
 	Group string `json:"group"`
 
 	// Names specifies the resource and kind names of the defined composite
-	// resource.
+	// resource. Any short names are propagated to the generated CRD, and must
+	// not collide with the categories Crossplane assigns to composite resources
+	// and claims.
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	Names extv1.CustomResourceDefinitionNames `json:"names"`
 
@@ -50,18 +104,76 @@ type CompositeResourceDefinitionSpec struct {
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	ClaimNames *extv1.CustomResourceDefinitionNames `json:"claimNames,omitempty"`
 
+	// ClaimPolicy specifies whether the defined composite resource may be
+	// claimed. Optional, the default, means a claim is offered if claim names
+	// are specified. Required means claim names must be specified. Disallowed
+	// means claim names must not be specified; Crossplane won't create a claim
+	// CRD or add claim fields to the composite resource's schema.
+	// +optional
+	// +kubebuilder:validation:Enum=Optional;Required;Disallowed
+	// +kubebuilder:default=Optional
+	ClaimPolicy *ClaimPolicy `json:"claimPolicy,omitempty"`
+
+	// CompositionRefPolicy specifies whether the defined composite resource
+	// and its claim may switch Compositions after they're created. Mutable,
+	// the default, allows compositionRef and compositionSelector to change at
+	// any time. Immutable means compositionRef can't be changed or removed
+	// once it's set, whether by the user or by Crossplane selecting a
+	// Composition, and compositionSelector can't be changed after creation.
+	// The policy doesn't affect Composition revisions; a composite resource
+	// still moves to new revisions of its Composition according to its
+	// compositionUpdatePolicy and compositionRevisionSelector.
+	// +optional
+	// +kubebuilder:validation:Enum=Mutable;Immutable
+	// +kubebuilder:default=Mutable
+	CompositionRefPolicy *CompositionRefPolicy `json:"compositionRefPolicy,omitempty"`
+
+	// ClaimReadinessDetail specifies how much detail about the defined
+	// composite resource's readiness is exposed to its claim while it isn't
+	// ready. None means the claim only reports that it's waiting for the
+	// composite resource. Counts, the default, means the claim also reports
+	// how many composed resources are ready. Resources means the claim also
+	// reports which composed resources aren't ready, using the resource
+	// names from the Composition rather than the names of the composed
+	// resources themselves.
+	// +optional
+	// +kubebuilder:validation:Enum=None;Counts;Resources
+	// +kubebuilder:default=Counts
+	ClaimReadinessDetail *ClaimReadinessDetail `json:"claimReadinessDetail,omitempty"`
+
 	// ConnectionSecretKeys is the list of keys that will be exposed to the end
 	// user of the defined kind.
 	// If the list is empty, all keys will be published.
 	// +optional
 	ConnectionSecretKeys []string `json:"connectionSecretKeys,omitempty"`
 
+	// ClaimConnectionSecretKeyRenames renames keys of a composite resource's
+	// connection secret when it's propagated to its claim. Each key of the
+	// map is a key of the composite resource's connection secret, and each
+	// value is the key it's written to in the claim's connection secret.
+	// Keys that aren't renamed are propagated unchanged. A renamed key takes
+	// precedence over a key of the same name that isn't renamed. Two keys
+	// can't be renamed to the same key.
+	// +optional
+	ClaimConnectionSecretKeyRenames map[string]string `json:"claimConnectionSecretKeyRenames,omitempty"`
+
 	// DefaultCompositeDeletePolicy is the policy used when deleting the Composite
 	// that is associated with the Claim if no policy has been specified.
 	// +optional
 	// +kubebuilder:default=Background
 	DefaultCompositeDeletePolicy *xpv1.CompositeDeletePolicy `json:"defaultCompositeDeletePolicy,omitempty"`
 
+	// DefaultComposedManagementPolicies are the management policies
+	// Crossplane sets on managed resources composed by composite resources of
+	// this type. They only apply to composed managed resources that don't
+	// specify their own spec.managementPolicies; management policies set by a
+	// Composition always take precedence. They're not set on
+	// composed resources that aren't managed resources, or on managed
+	// resources whose provider declares capabilities that don't include
+	// ManagementPolicies.
+	// +optional
+	DefaultComposedManagementPolicies xpv1.ManagementPolicies `json:"defaultComposedManagementPolicies,omitempty"`
+
 	// DefaultCompositionRef refers to the Composition resource that will be used
 	// in case no composition selector is given.
 	// +optional
@@ -79,6 +191,28 @@ type CompositeResourceDefinitionSpec struct {
 	// +kubebuilder:default=Automatic
 	DefaultCompositionUpdatePolicy *xpv1.UpdatePolicy `json:"defaultCompositionUpdatePolicy,omitempty"`
 
+	// MaxConcurrentReconciles is the maximum number of composite resources
+	// of this type that Crossplane will reconcile concurrently. It overrides
+	// Crossplane's global --max-reconcile-rate for this definition. Crossplane
+	// restarts the composite resource controller to apply a new value, so
+	// changing it briefly pauses reconciliation of composite resources of
+	// this type.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentReconciles *int `json:"maxConcurrentReconciles,omitempty"`
+
+	// FunctionContext seeds the Composition Function pipeline context of
+	// every composite resource of this type. Each key is a context key, and
+	// each value is that key's initial value. Crossplane sends these entries
+	// to the first step of a Pipeline mode Composition. The first step and
+	// every later step may overwrite them, and each step sees the context
+	// returned by the step before it. The context key
+	// apiextensions.crossplane.io/function-state is reserved, and can't be
+	// seeded.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	FunctionContext *runtime.RawExtension `json:"functionContext,omitempty"`
+
 	// Versions is the list of all API versions of the defined composite
 	// resource. Version names are used to compute the order in which served
 	// versions are listed in API discovery. If the version string is
@@ -205,6 +339,26 @@ type CompositeResourceDefinitionControllerStatus struct {
 	// version. Note that clients may interact with any served type; this is
 	// simply the type that Crossplane interacts with.
 	CompositeResourceClaimTypeRef TypeReference `json:"compositeResourceClaimType,omitempty"`
+
+	// The CompositeResourceMaxConcurrentReconciles is the maximum number of
+	// composite resources the currently running composite resource controller
+	// reconciles concurrently.
+	CompositeResourceMaxConcurrentReconciles int `json:"compositeResourceMaxConcurrentReconciles,omitempty"`
+
+	// The CompositeResourceDefaultComposedManagementPolicies are the default
+	// composed management policies the currently running composite resource
+	// controller sets.
+	CompositeResourceDefaultComposedManagementPolicies xpv1.ManagementPolicies `json:"compositeResourceDefaultComposedManagementPolicies,omitempty"`
+
+	// The CompositeResourceClaimReadinessDetail is the claim readiness
+	// detail the currently running composite resource claim controller
+	// exposes.
+	CompositeResourceClaimReadinessDetail ClaimReadinessDetail `json:"compositeResourceClaimReadinessDetail,omitempty"`
+
+	// The CompositeResourceClaimConnectionSecretKeyRenames are the connection
+	// secret key renames the currently running composite resource claim
+	// controller applies.
+	CompositeResourceClaimConnectionSecretKeyRenames map[string]string `json:"compositeResourceClaimConnectionSecretKeyRenames,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -255,7 +409,28 @@ func (c *CompositeResourceDefinition) GetCompositeGroupVersionKind() schema.Grou
 // OffersClaim is true when a CompositeResourceDefinition offers a claim for the
 // composite resource it defines.
 func (c *CompositeResourceDefinition) OffersClaim() bool {
-	return c.Spec.ClaimNames != nil
+	return c.Spec.ClaimNames != nil && !c.DisallowsClaim()
+}
+
+// DisallowsClaim is true when a CompositeResourceDefinition explicitly
+// disallows claiming the composite resource it defines.
+func (c *CompositeResourceDefinition) DisallowsClaim() bool {
+	return c.Spec.ClaimPolicy != nil && *c.Spec.ClaimPolicy == ClaimPolicyDisallowed
+}
+
+// LocksCompositionRef is true when a CompositeResourceDefinition prevents the
+// composite resource it defines from switching Compositions.
+func (c *CompositeResourceDefinition) LocksCompositionRef() bool {
+	return c.Spec.CompositionRefPolicy != nil && *c.Spec.CompositionRefPolicy == CompositionRefPolicyImmutable
+}
+
+// GetClaimReadinessDetail returns how much detail about the defined composite
+// resource's readiness is exposed to its claim.
+func (c *CompositeResourceDefinition) GetClaimReadinessDetail() ClaimReadinessDetail {
+	if c.Spec.ClaimReadinessDetail == nil {
+		return ClaimReadinessDetailCounts
+	}
+	return *c.Spec.ClaimReadinessDetail
 }
 
 // GetClaimGroupVersionKind returns the schema.GroupVersionKind of the CRD for
@@ -282,3 +457,15 @@ func (c *CompositeResourceDefinition) GetClaimGroupVersionKind() schema.GroupVer
 func (c *CompositeResourceDefinition) GetConnectionSecretKeys() []string {
 	return c.Spec.ConnectionSecretKeys
 }
+
+// GetClaimConnectionSecretKeyRenames returns the keys to rename when a
+// composite resource's connection secret is propagated to its claim.
+func (c *CompositeResourceDefinition) GetClaimConnectionSecretKeyRenames() map[string]string {
+	return c.Spec.ClaimConnectionSecretKeyRenames
+}
+
+// GetDefaultComposedManagementPolicies returns the management policies to set
+// on composed managed resources that don't specify their own.
+func (c *CompositeResourceDefinition) GetDefaultComposedManagementPolicies() xpv1.ManagementPolicies {
+	return c.Spec.DefaultComposedManagementPolicies
+}
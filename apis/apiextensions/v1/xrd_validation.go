@@ -2,8 +2,10 @@ package v1
 
 import (
 	"fmt"
+	"sort"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -12,6 +14,8 @@ func (c *CompositeResourceDefinition) Validate() (warns []string, errs field.Err
 	type validationFunc func() field.ErrorList
 	validations := []validationFunc{
 		c.validateConversion,
+		c.validateClaimPolicy,
+		c.validateClaimConnectionSecretKeyRenames,
 	}
 	for _, f := range validations {
 		errs = append(errs, f()...)
@@ -29,6 +33,51 @@ func (c *CompositeResourceDefinition) validateConversion() (errs field.ErrorList
 	return errs
 }
 
+// validateClaimPolicy checks that the supplied CompositeResourceDefinition spec
+// is valid w.r.t. its claim policy.
+func (c *CompositeResourceDefinition) validateClaimPolicy() (errs field.ErrorList) {
+	if c.Spec.ClaimPolicy == nil {
+		return nil
+	}
+	switch *c.Spec.ClaimPolicy {
+	case ClaimPolicyRequired:
+		if c.Spec.ClaimNames == nil {
+			errs = append(errs, field.Required(field.NewPath("spec", "claimNames"), fmt.Sprintf("claim names are required when claim policy is %q", ClaimPolicyRequired)))
+		}
+	case ClaimPolicyDisallowed:
+		if c.Spec.ClaimNames != nil {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "claimNames"), fmt.Sprintf("claim names must not be specified when claim policy is %q", ClaimPolicyDisallowed)))
+		}
+	case ClaimPolicyOptional:
+	}
+	return errs
+}
+
+// validateClaimConnectionSecretKeyRenames checks that the supplied
+// CompositeResourceDefinition spec doesn't rename connection secret keys to
+// invalid or duplicate keys.
+func (c *CompositeResourceDefinition) validateClaimConnectionSecretKeyRenames() (errs field.ErrorList) {
+	renamed := make(map[string]bool, len(c.Spec.ClaimConnectionSecretKeyRenames))
+	// Sort the keys so errors are returned in a deterministic order.
+	keys := make([]string, 0, len(c.Spec.ClaimConnectionSecretKeyRenames))
+	for k := range c.Spec.ClaimConnectionSecretKeyRenames {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		to := c.Spec.ClaimConnectionSecretKeyRenames[k]
+		p := field.NewPath("spec", "claimConnectionSecretKeyRenames").Key(k)
+		for _, msg := range validation.IsConfigMapKey(to) {
+			errs = append(errs, field.Invalid(p, to, msg))
+		}
+		if renamed[to] {
+			errs = append(errs, field.Duplicate(p, to))
+		}
+		renamed[to] = true
+	}
+	return errs
+}
+
 // ValidateUpdate checks that the supplied CompositeResourceDefinition update is valid w.r.t. the old one.
 func (c *CompositeResourceDefinition) ValidateUpdate(old *CompositeResourceDefinition) (warns []string, errs field.ErrorList) {
 	// Validate the update
@@ -76,6 +76,130 @@ func TestValidateConversion(t *testing.T) {
 	}
 }
 
+func TestValidateClaimPolicy(t *testing.T) {
+	required := ClaimPolicyRequired
+	disallowed := ClaimPolicyDisallowed
+	optional := ClaimPolicyOptional
+
+	cases := map[string]struct {
+		reason string
+		c      *CompositeResourceDefinition
+		want   field.ErrorList
+	}{
+		"NoPolicy": {
+			reason: "A CompositeResourceDefinition with no claim policy should be accepted",
+			c:      &CompositeResourceDefinition{},
+		},
+		"OptionalWithoutClaimNames": {
+			reason: "A CompositeResourceDefinition with an optional claim and no claim names should be accepted",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimPolicy: &optional,
+				},
+			},
+		},
+		"RequiredWithClaimNames": {
+			reason: "A CompositeResourceDefinition with a required claim and claim names should be accepted",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimPolicy: &required,
+					ClaimNames:  &extv1.CustomResourceDefinitionNames{Kind: "Claim", Plural: "claims"},
+				},
+			},
+		},
+		"RequiredWithoutClaimNames": {
+			reason: "A CompositeResourceDefinition with a required claim and no claim names should be rejected",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimPolicy: &required,
+				},
+			},
+			want: field.ErrorList{
+				field.Required(field.NewPath("spec", "claimNames"), ""),
+			},
+		},
+		"DisallowedWithoutClaimNames": {
+			reason: "A CompositeResourceDefinition that disallows claims and has no claim names should be accepted",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimPolicy: &disallowed,
+				},
+			},
+		},
+		"DisallowedWithClaimNames": {
+			reason: "A CompositeResourceDefinition that disallows claims but has claim names should be rejected",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimPolicy: &disallowed,
+					ClaimNames:  &extv1.CustomResourceDefinitionNames{Kind: "Claim", Plural: "claims"},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "claimNames"), ""),
+			},
+		},
+	}
+	for tcName, tc := range cases {
+		t.Run(tcName, func(t *testing.T) {
+			got := tc.c.validateClaimPolicy()
+			if diff := cmp.Diff(tc.want, got, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail")); diff != "" {
+				t.Errorf("\n%s\nvalidateClaimPolicy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateClaimConnectionSecretKeyRenames(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		c      *CompositeResourceDefinition
+		want   field.ErrorList
+	}{
+		"NoRenames": {
+			reason: "A CompositeResourceDefinition that doesn't rename connection secret keys should be accepted",
+			c:      &CompositeResourceDefinition{},
+		},
+		"Valid": {
+			reason: "A CompositeResourceDefinition that renames connection secret keys to distinct, valid keys should be accepted",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimConnectionSecretKeyRenames: map[string]string{"endpoint": "DB_HOST", "port": "DB_PORT"},
+				},
+			},
+		},
+		"InvalidKey": {
+			reason: "A CompositeResourceDefinition that renames a connection secret key to an invalid key should be rejected",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimConnectionSecretKeyRenames: map[string]string{"endpoint": "DB HOST"},
+				},
+			},
+			want: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "claimConnectionSecretKeyRenames").Key("endpoint"), "DB HOST", ""),
+			},
+		},
+		"DuplicateKey": {
+			reason: "A CompositeResourceDefinition that renames two connection secret keys to the same key should be rejected",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimConnectionSecretKeyRenames: map[string]string{"endpoint": "DB_HOST", "host": "DB_HOST"},
+				},
+			},
+			want: field.ErrorList{
+				field.Duplicate(field.NewPath("spec", "claimConnectionSecretKeyRenames").Key("host"), "DB_HOST"),
+			},
+		},
+	}
+	for tcName, tc := range cases {
+		t.Run(tcName, func(t *testing.T) {
+			got := tc.c.validateClaimConnectionSecretKeyRenames()
+			if diff := cmp.Diff(tc.want, got, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail")); diff != "" {
+				t.Errorf("\n%s\nvalidateClaimConnectionSecretKeyRenames(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestValidateUpdate(t *testing.T) {
 	type args struct {
 		old *CompositeResourceDefinition
@@ -7,6 +7,7 @@ import (
 	v11 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	v12 "k8s.io/api/core/v1"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	v13 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -52,6 +53,42 @@ func (c *GeneratedRevisionSpecConverter) FromRevisionSpec(source CompositionRevi
 	}
 	v1CompositionSpec.WriteConnectionSecretsToNamespace = pString
 	v1CompositionSpec.PublishConnectionDetailsWithStoreConfigRef = c.pV1StoreConfigReferenceToPV1StoreConfigReference(source.PublishConnectionDetailsWithStoreConfigRef)
+	v1CompositionSpec.DisableConnectionSecrets = source.DisableConnectionSecrets
+	var v1CompositeConnectionDetailList []CompositeConnectionDetail
+	if source.CompositeConnectionDetails != nil {
+		v1CompositeConnectionDetailList = make([]CompositeConnectionDetail, len(source.CompositeConnectionDetails))
+		for l := 0; l < len(source.CompositeConnectionDetails); l++ {
+			v1CompositeConnectionDetailList[l] = c.v1CompositeConnectionDetailToV1CompositeConnectionDetail(source.CompositeConnectionDetails[l])
+		}
+	}
+	v1CompositionSpec.CompositeConnectionDetails = v1CompositeConnectionDetailList
+	var v1DeletionPhaseList []DeletionPhase
+	if source.DeletionOrder != nil {
+		v1DeletionPhaseList = make([]DeletionPhase, len(source.DeletionOrder))
+		for m := 0; m < len(source.DeletionOrder); m++ {
+			v1DeletionPhaseList[m] = c.v1DeletionPhaseToV1DeletionPhase(source.DeletionOrder[m])
+		}
+	}
+	v1CompositionSpec.DeletionOrder = v1DeletionPhaseList
+	var pV1ComposedOwnerReferencePolicy *ComposedOwnerReferencePolicy
+	if source.ComposedOwnerReference != nil {
+		v1ComposedOwnerReferencePolicy := ComposedOwnerReferencePolicy(*source.ComposedOwnerReference)
+		pV1ComposedOwnerReferencePolicy = &v1ComposedOwnerReferencePolicy
+	}
+	v1CompositionSpec.ComposedOwnerReference = pV1ComposedOwnerReferencePolicy
+	v1CompositionSpec.ClaimNamespaceSelector = c.pV1LabelSelectorToPV1LabelSelector(source.ClaimNamespaceSelector)
+	var pV1Duration *v13.Duration
+	if source.ReadinessStableFor != nil {
+		v1Duration := *source.ReadinessStableFor
+		pV1Duration = &v1Duration
+	}
+	v1CompositionSpec.ReadinessStableFor = pV1Duration
+	var pV1CompositeReadinessPolicy *CompositeReadinessPolicy
+	if source.CompositeReadiness != nil {
+		v1CompositeReadinessPolicy := CompositeReadinessPolicy(*source.CompositeReadiness)
+		pV1CompositeReadinessPolicy = &v1CompositeReadinessPolicy
+	}
+	v1CompositionSpec.CompositeReadiness = pV1CompositeReadinessPolicy
 	return v1CompositionSpec
 }
 func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec) CompositionRevisionSpec {
@@ -94,6 +131,42 @@ func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec)
 	}
 	v1CompositionRevisionSpec.WriteConnectionSecretsToNamespace = pString
 	v1CompositionRevisionSpec.PublishConnectionDetailsWithStoreConfigRef = c.pV1StoreConfigReferenceToPV1StoreConfigReference(source.PublishConnectionDetailsWithStoreConfigRef)
+	v1CompositionRevisionSpec.DisableConnectionSecrets = source.DisableConnectionSecrets
+	var v1CompositeConnectionDetailList []CompositeConnectionDetail
+	if source.CompositeConnectionDetails != nil {
+		v1CompositeConnectionDetailList = make([]CompositeConnectionDetail, len(source.CompositeConnectionDetails))
+		for l := 0; l < len(source.CompositeConnectionDetails); l++ {
+			v1CompositeConnectionDetailList[l] = c.v1CompositeConnectionDetailToV1CompositeConnectionDetail(source.CompositeConnectionDetails[l])
+		}
+	}
+	v1CompositionRevisionSpec.CompositeConnectionDetails = v1CompositeConnectionDetailList
+	var v1DeletionPhaseList []DeletionPhase
+	if source.DeletionOrder != nil {
+		v1DeletionPhaseList = make([]DeletionPhase, len(source.DeletionOrder))
+		for m := 0; m < len(source.DeletionOrder); m++ {
+			v1DeletionPhaseList[m] = c.v1DeletionPhaseToV1DeletionPhase(source.DeletionOrder[m])
+		}
+	}
+	v1CompositionRevisionSpec.DeletionOrder = v1DeletionPhaseList
+	var pV1ComposedOwnerReferencePolicy *ComposedOwnerReferencePolicy
+	if source.ComposedOwnerReference != nil {
+		v1ComposedOwnerReferencePolicy := ComposedOwnerReferencePolicy(*source.ComposedOwnerReference)
+		pV1ComposedOwnerReferencePolicy = &v1ComposedOwnerReferencePolicy
+	}
+	v1CompositionRevisionSpec.ComposedOwnerReference = pV1ComposedOwnerReferencePolicy
+	v1CompositionRevisionSpec.ClaimNamespaceSelector = c.pV1LabelSelectorToPV1LabelSelector(source.ClaimNamespaceSelector)
+	var pV1Duration *v13.Duration
+	if source.ReadinessStableFor != nil {
+		v1Duration := *source.ReadinessStableFor
+		pV1Duration = &v1Duration
+	}
+	v1CompositionRevisionSpec.ReadinessStableFor = pV1Duration
+	var pV1CompositeReadinessPolicy *CompositeReadinessPolicy
+	if source.CompositeReadiness != nil {
+		v1CompositeReadinessPolicy := CompositeReadinessPolicy(*source.CompositeReadiness)
+		pV1CompositeReadinessPolicy = &v1CompositeReadinessPolicy
+	}
+	v1CompositionRevisionSpec.CompositeReadiness = pV1CompositeReadinessPolicy
 	return v1CompositionRevisionSpec
 }
 func (c *GeneratedRevisionSpecConverter) pRuntimeRawExtensionToPRuntimeRawExtension(source *runtime.RawExtension) *runtime.RawExtension {
@@ -122,6 +195,20 @@ func (c *GeneratedRevisionSpecConverter) pV1CombineToPV1Combine(source *Combine)
 	}
 	return pV1Combine
 }
+func (c *GeneratedRevisionSpecConverter) pV1CompositeConnectionDetailPolicyToPV1CompositeConnectionDetailPolicy(source *CompositeConnectionDetailPolicy) *CompositeConnectionDetailPolicy {
+	var pV1CompositeConnectionDetailPolicy *CompositeConnectionDetailPolicy
+	if source != nil {
+		var v1CompositeConnectionDetailPolicy CompositeConnectionDetailPolicy
+		var pV1FromFieldPathPolicy *FromFieldPathPolicy
+		if (*source).FromFieldPath != nil {
+			v1FromFieldPathPolicy := FromFieldPathPolicy(*(*source).FromFieldPath)
+			pV1FromFieldPathPolicy = &v1FromFieldPathPolicy
+		}
+		v1CompositeConnectionDetailPolicy.FromFieldPath = pV1FromFieldPathPolicy
+		pV1CompositeConnectionDetailPolicy = &v1CompositeConnectionDetailPolicy
+	}
+	return pV1CompositeConnectionDetailPolicy
+}
 func (c *GeneratedRevisionSpecConverter) pV1ConvertTransformToPV1ConvertTransform(source *ConvertTransform) *ConvertTransform {
 	var pV1ConvertTransform *ConvertTransform
 	if source != nil {
@@ -133,10 +220,58 @@ func (c *GeneratedRevisionSpecConverter) pV1ConvertTransformToPV1ConvertTransfor
 			pV1ConvertTransformFormat = &v1ConvertTransformFormat
 		}
 		v1ConvertTransform.Format = pV1ConvertTransformFormat
+		var pString *string
+		if (*source).Unit != nil {
+			xstring := *(*source).Unit
+			pString = &xstring
+		}
+		v1ConvertTransform.Unit = pString
 		pV1ConvertTransform = &v1ConvertTransform
 	}
 	return pV1ConvertTransform
 }
+func (c *GeneratedRevisionSpecConverter) v1DeletionPhaseToV1DeletionPhase(source DeletionPhase) DeletionPhase {
+	var v1DeletionPhase DeletionPhase
+	var stringList []string
+	if source.Resources != nil {
+		stringList = make([]string, len(source.Resources))
+		for i := 0; i < len(source.Resources); i++ {
+			stringList[i] = source.Resources[i]
+		}
+	}
+	v1DeletionPhase.Resources = stringList
+	var pV1Duration *v13.Duration
+	if source.Timeout != nil {
+		v1Duration := *source.Timeout
+		pV1Duration = &v1Duration
+	}
+	v1DeletionPhase.Timeout = pV1Duration
+	return v1DeletionPhase
+}
+func (c *GeneratedRevisionSpecConverter) pV1LabelSelectorToPV1LabelSelector(source *v13.LabelSelector) *v13.LabelSelector {
+	var pV1LabelSelector *v13.LabelSelector
+	if source != nil {
+		var v1LabelSelector v13.LabelSelector
+		var mapStringString map[string]string
+		if (*source).MatchLabels != nil {
+			mapStringString = make(map[string]string, len((*source).MatchLabels))
+			for key, value := range (*source).MatchLabels {
+				mapStringString[key] = value
+			}
+		}
+		v1LabelSelector.MatchLabels = mapStringString
+		var v1LabelSelectorRequirementList []v13.LabelSelectorRequirement
+		if (*source).MatchExpressions != nil {
+			v1LabelSelectorRequirementList = make([]v13.LabelSelectorRequirement, len((*source).MatchExpressions))
+			for i := 0; i < len((*source).MatchExpressions); i++ {
+				v1LabelSelectorRequirementList[i] = c.v1LabelSelectorRequirementToV1LabelSelectorRequirement((*source).MatchExpressions[i])
+			}
+		}
+		v1LabelSelector.MatchExpressions = v1LabelSelectorRequirementList
+		pV1LabelSelector = &v1LabelSelector
+	}
+	return pV1LabelSelector
+}
 func (c *GeneratedRevisionSpecConverter) pV1MapTransformToPV1MapTransform(source *MapTransform) *MapTransform {
 	var pV1MapTransform *MapTransform
 	if source != nil {
@@ -228,6 +363,30 @@ func (c *GeneratedRevisionSpecConverter) pV1MergeOptionsToPV1MergeOptions(source
 	}
 	return pV1MergeOptions
 }
+func (c *GeneratedRevisionSpecConverter) pV1ObservedResourceSelectorToPV1ObservedResourceSelector(source *ObservedResourceSelector) *ObservedResourceSelector {
+	var pV1ObservedResourceSelector *ObservedResourceSelector
+	if source != nil {
+		var v1ObservedResourceSelector ObservedResourceSelector
+		var stringList []string
+		if (*source).Names != nil {
+			stringList = make([]string, len((*source).Names))
+			for i := 0; i < len((*source).Names); i++ {
+				stringList[i] = (*source).Names[i]
+			}
+		}
+		v1ObservedResourceSelector.Names = stringList
+		var mapStringString map[string]string
+		if (*source).MatchLabels != nil {
+			mapStringString = make(map[string]string, len((*source).MatchLabels))
+			for key, value := range (*source).MatchLabels {
+				mapStringString[key] = value
+			}
+		}
+		v1ObservedResourceSelector.MatchLabels = mapStringString
+		pV1ObservedResourceSelector = &v1ObservedResourceSelector
+	}
+	return pV1ObservedResourceSelector
+}
 func (c *GeneratedRevisionSpecConverter) pV1PatchPolicyToPV1PatchPolicy(source *PatchPolicy) *PatchPolicy {
 	var pV1PatchPolicy *PatchPolicy
 	if source != nil {
@@ -337,6 +496,12 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 		pString = &xstring
 	}
 	v1ComposedTemplate.Name = pString
+	var pString2 *string
+	if source.NameTemplate != nil {
+		xstring2 := *source.NameTemplate
+		pString2 = &xstring2
+	}
+	v1ComposedTemplate.NameTemplate = pString2
 	v1ComposedTemplate.Base = ConvertRawExtension(source.Base)
 	var v1PatchList []Patch
 	if source.Patches != nil {
@@ -354,6 +519,12 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 		}
 	}
 	v1ComposedTemplate.ConnectionDetails = v1ConnectionDetailList
+	var pBool *bool
+	if source.PublishConnectionDetails != nil {
+		xbool := *source.PublishConnectionDetails
+		pBool = &xbool
+	}
+	v1ComposedTemplate.PublishConnectionDetails = pBool
 	var v1ReadinessCheckList []ReadinessCheck
 	if source.ReadinessChecks != nil {
 		v1ReadinessCheckList = make([]ReadinessCheck, len(source.ReadinessChecks))
@@ -362,8 +533,37 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 		}
 	}
 	v1ComposedTemplate.ReadinessChecks = v1ReadinessCheckList
+	var v1MergePolicyList []MergePolicy
+	if source.MergePolicies != nil {
+		v1MergePolicyList = make([]MergePolicy, len(source.MergePolicies))
+		for l := 0; l < len(source.MergePolicies); l++ {
+			v1MergePolicyList[l] = c.v1MergePolicyToV1MergePolicy(source.MergePolicies[l])
+		}
+	}
+	v1ComposedTemplate.MergePolicies = v1MergePolicyList
+	var pV1ComposedUpdatePolicy *ComposedUpdatePolicy
+	if source.UpdatePolicy != nil {
+		v1ComposedUpdatePolicy := ComposedUpdatePolicy(*source.UpdatePolicy)
+		pV1ComposedUpdatePolicy = &v1ComposedUpdatePolicy
+	}
+	v1ComposedTemplate.UpdatePolicy = pV1ComposedUpdatePolicy
+	var stringList []string
+	if source.InitFieldPaths != nil {
+		stringList = make([]string, len(source.InitFieldPaths))
+		for m := 0; m < len(source.InitFieldPaths); m++ {
+			stringList[m] = source.InitFieldPaths[m]
+		}
+	}
+	v1ComposedTemplate.InitFieldPaths = stringList
 	return v1ComposedTemplate
 }
+func (c *GeneratedRevisionSpecConverter) v1CompositeConnectionDetailToV1CompositeConnectionDetail(source CompositeConnectionDetail) CompositeConnectionDetail {
+	var v1CompositeConnectionDetail CompositeConnectionDetail
+	v1CompositeConnectionDetail.Name = source.Name
+	v1CompositeConnectionDetail.FromFieldPath = source.FromFieldPath
+	v1CompositeConnectionDetail.Policy = c.pV1CompositeConnectionDetailPolicyToPV1CompositeConnectionDetailPolicy(source.Policy)
+	return v1CompositeConnectionDetail
+}
 func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(source ConnectionDetail) ConnectionDetail {
 	var v1ConnectionDetail ConnectionDetail
 	var pString *string
@@ -396,6 +596,14 @@ func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(
 		pString4 = &xstring4
 	}
 	v1ConnectionDetail.Value = pString4
+	var v1TransformList []Transform
+	if source.Transforms != nil {
+		v1TransformList = make([]Transform, len(source.Transforms))
+		for i := 0; i < len(source.Transforms); i++ {
+			v1TransformList[i] = c.v1TransformToV1Transform(source.Transforms[i])
+		}
+	}
+	v1ConnectionDetail.Transforms = v1TransformList
 	return v1ConnectionDetail
 }
 func (c *GeneratedRevisionSpecConverter) v1FunctionCredentialsToV1FunctionCredentials(source FunctionCredentials) FunctionCredentials {
@@ -422,6 +630,20 @@ func (c *GeneratedRevisionSpecConverter) v1JSONToV1JSON(source v1.JSON) v1.JSON
 	v1JSON.Raw = byteList
 	return v1JSON
 }
+func (c *GeneratedRevisionSpecConverter) v1LabelSelectorRequirementToV1LabelSelectorRequirement(source v13.LabelSelectorRequirement) v13.LabelSelectorRequirement {
+	var v1LabelSelectorRequirement v13.LabelSelectorRequirement
+	v1LabelSelectorRequirement.Key = source.Key
+	v1LabelSelectorRequirement.Operator = v13.LabelSelectorOperator(source.Operator)
+	var stringList []string
+	if source.Values != nil {
+		stringList = make([]string, len(source.Values))
+		for i := 0; i < len(source.Values); i++ {
+			stringList[i] = source.Values[i]
+		}
+	}
+	v1LabelSelectorRequirement.Values = stringList
+	return v1LabelSelectorRequirement
+}
 func (c *GeneratedRevisionSpecConverter) v1MatchTransformPatternToV1MatchTransformPattern(source MatchTransformPattern) MatchTransformPattern {
 	var v1MatchTransformPattern MatchTransformPattern
 	v1MatchTransformPattern.Type = MatchTransformPatternType(source.Type)
@@ -453,6 +675,12 @@ func (c *GeneratedRevisionSpecConverter) v1PatchSetToV1PatchSet(source PatchSet)
 	v1PatchSet.Patches = v1PatchList
 	return v1PatchSet
 }
+func (c *GeneratedRevisionSpecConverter) v1MergePolicyToV1MergePolicy(source MergePolicy) MergePolicy {
+	var v1MergePolicy MergePolicy
+	v1MergePolicy.FieldPath = source.FieldPath
+	v1MergePolicy.MergeOptions = c.pV1MergeOptionsToPV1MergeOptions(source.MergeOptions)
+	return v1MergePolicy
+}
 func (c *GeneratedRevisionSpecConverter) v1PatchToV1Patch(source Patch) Patch {
 	var v1Patch Patch
 	v1Patch.Type = PatchType(source.Type)
@@ -463,6 +691,8 @@ func (c *GeneratedRevisionSpecConverter) v1PatchToV1Patch(source Patch) Patch {
 	}
 	v1Patch.FromFieldPath = pString
 	v1Patch.Combine = c.pV1CombineToPV1Combine(source.Combine)
+	v1Patch.ReferencedKey = c.pV1ReferencedKeyToPV1ReferencedKey(source.ReferencedKey)
+	v1Patch.EnvironmentConfig = c.pV1EnvironmentConfigKeyToPV1EnvironmentConfigKey(source.EnvironmentConfig)
 	var pString2 *string
 	if source.ToFieldPath != nil {
 		xstring2 := *source.ToFieldPath
@@ -484,13 +714,46 @@ func (c *GeneratedRevisionSpecConverter) v1PatchToV1Patch(source Patch) Patch {
 	}
 	v1Patch.Transforms = v1TransformList
 	v1Patch.Policy = c.pV1PatchPolicyToPV1PatchPolicy(source.Policy)
+	var pString4 *string
+	if source.MatchCondition != nil {
+		xstring4 := *source.MatchCondition
+		pString4 = &xstring4
+	}
+	v1Patch.MatchCondition = pString4
 	return v1Patch
 }
+func (c *GeneratedRevisionSpecConverter) pV1ReferencedKeyToPV1ReferencedKey(source *ReferencedKey) *ReferencedKey {
+	var pV1ReferencedKey *ReferencedKey
+	if source != nil {
+		var v1ReferencedKey ReferencedKey
+		v1ReferencedKey.Kind = ReferencedKeyKind((*source).Kind)
+		v1ReferencedKey.NameFieldPath = (*source).NameFieldPath
+		v1ReferencedKey.Key = (*source).Key
+		pV1ReferencedKey = &v1ReferencedKey
+	}
+	return pV1ReferencedKey
+}
+func (c *GeneratedRevisionSpecConverter) pV1EnvironmentConfigKeyToPV1EnvironmentConfigKey(source *EnvironmentConfigKey) *EnvironmentConfigKey {
+	var pV1EnvironmentConfigKey *EnvironmentConfigKey
+	if source != nil {
+		var v1EnvironmentConfigKey EnvironmentConfigKey
+		v1EnvironmentConfigKey.Name = (*source).Name
+		v1EnvironmentConfigKey.Key = (*source).Key
+		pV1EnvironmentConfigKey = &v1EnvironmentConfigKey
+	}
+	return pV1EnvironmentConfigKey
+}
 func (c *GeneratedRevisionSpecConverter) v1PipelineStepToV1PipelineStep(source PipelineStep) PipelineStep {
 	var v1PipelineStep PipelineStep
 	v1PipelineStep.Step = source.Step
 	v1PipelineStep.FunctionRef = c.v1FunctionReferenceToV1FunctionReference(source.FunctionRef)
 	v1PipelineStep.Input = c.pRuntimeRawExtensionToPRuntimeRawExtension(source.Input)
+	var pBool *bool
+	if source.InterpolateInput != nil {
+		xbool := *source.InterpolateInput
+		pBool = &xbool
+	}
+	v1PipelineStep.InterpolateInput = pBool
 	var v1FunctionCredentialsList []FunctionCredentials
 	if source.Credentials != nil {
 		v1FunctionCredentialsList = make([]FunctionCredentials, len(source.Credentials))
@@ -499,6 +762,19 @@ func (c *GeneratedRevisionSpecConverter) v1PipelineStepToV1PipelineStep(source P
 		}
 	}
 	v1PipelineStep.Credentials = v1FunctionCredentialsList
+	v1PipelineStep.ObservedResources = c.pV1ObservedResourceSelectorToPV1ObservedResourceSelector(source.ObservedResources)
+	var pBool2 *bool
+	if source.Enabled != nil {
+		xbool2 := *source.Enabled
+		pBool2 = &xbool2
+	}
+	v1PipelineStep.Enabled = pBool2
+	var pString *string
+	if source.MatchCondition != nil {
+		xstring := *source.MatchCondition
+		pString = &xstring
+	}
+	v1PipelineStep.MatchCondition = pString
 	return v1PipelineStep
 }
 func (c *GeneratedRevisionSpecConverter) v1ReadinessCheckToV1ReadinessCheck(source ReadinessCheck) ReadinessCheck {
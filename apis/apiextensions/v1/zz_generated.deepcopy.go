@@ -23,6 +23,7 @@ package v1
 import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -74,6 +75,11 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
 	in.Base.DeepCopyInto(&out.Base)
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
@@ -89,6 +95,11 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PublishConnectionDetails != nil {
+		in, out := &in.PublishConnectionDetails, &out.PublishConnectionDetails
+		*out = new(bool)
+		**out = **in
+	}
 	if in.ReadinessChecks != nil {
 		in, out := &in.ReadinessChecks, &out.ReadinessChecks
 		*out = make([]ReadinessCheck, len(*in))
@@ -96,6 +107,23 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MergePolicies != nil {
+		in, out := &in.MergePolicies, &out.MergePolicies
+		*out = make([]MergePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpdatePolicy != nil {
+		in, out := &in.UpdatePolicy, &out.UpdatePolicy
+		*out = new(ComposedUpdatePolicy)
+		**out = **in
+	}
+	if in.InitFieldPaths != nil {
+		in, out := &in.InitFieldPaths, &out.InitFieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedTemplate.
@@ -108,6 +136,46 @@ func (in *ComposedTemplate) DeepCopy() *ComposedTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeConnectionDetail) DeepCopyInto(out *CompositeConnectionDetail) {
+	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(CompositeConnectionDetailPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeConnectionDetail.
+func (in *CompositeConnectionDetail) DeepCopy() *CompositeConnectionDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeConnectionDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeConnectionDetailPolicy) DeepCopyInto(out *CompositeConnectionDetailPolicy) {
+	*out = *in
+	if in.FromFieldPath != nil {
+		in, out := &in.FromFieldPath, &out.FromFieldPath
+		*out = new(FromFieldPathPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeConnectionDetailPolicy.
+func (in *CompositeConnectionDetailPolicy) DeepCopy() *CompositeConnectionDetailPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeConnectionDetailPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositeResourceDefinition) DeepCopyInto(out *CompositeResourceDefinition) {
 	*out = *in
@@ -140,6 +208,18 @@ func (in *CompositeResourceDefinitionControllerStatus) DeepCopyInto(out *Composi
 	*out = *in
 	out.CompositeResourceTypeRef = in.CompositeResourceTypeRef
 	out.CompositeResourceClaimTypeRef = in.CompositeResourceClaimTypeRef
+	if in.CompositeResourceDefaultComposedManagementPolicies != nil {
+		in, out := &in.CompositeResourceDefaultComposedManagementPolicies, &out.CompositeResourceDefaultComposedManagementPolicies
+		*out = make(commonv1.ManagementPolicies, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompositeResourceClaimConnectionSecretKeyRenames != nil {
+		in, out := &in.CompositeResourceClaimConnectionSecretKeyRenames, &out.CompositeResourceClaimConnectionSecretKeyRenames
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceDefinitionControllerStatus.
@@ -193,16 +273,43 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(apiextensionsv1.CustomResourceDefinitionNames)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClaimPolicy != nil {
+		in, out := &in.ClaimPolicy, &out.ClaimPolicy
+		*out = new(ClaimPolicy)
+		**out = **in
+	}
+	if in.CompositionRefPolicy != nil {
+		in, out := &in.CompositionRefPolicy, &out.CompositionRefPolicy
+		*out = new(CompositionRefPolicy)
+		**out = **in
+	}
+	if in.ClaimReadinessDetail != nil {
+		in, out := &in.ClaimReadinessDetail, &out.ClaimReadinessDetail
+		*out = new(ClaimReadinessDetail)
+		**out = **in
+	}
 	if in.ConnectionSecretKeys != nil {
 		in, out := &in.ConnectionSecretKeys, &out.ConnectionSecretKeys
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ClaimConnectionSecretKeyRenames != nil {
+		in, out := &in.ClaimConnectionSecretKeyRenames, &out.ClaimConnectionSecretKeyRenames
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.DefaultCompositeDeletePolicy != nil {
 		in, out := &in.DefaultCompositeDeletePolicy, &out.DefaultCompositeDeletePolicy
 		*out = new(commonv1.CompositeDeletePolicy)
 		**out = **in
 	}
+	if in.DefaultComposedManagementPolicies != nil {
+		in, out := &in.DefaultComposedManagementPolicies, &out.DefaultComposedManagementPolicies
+		*out = make(commonv1.ManagementPolicies, len(*in))
+		copy(*out, *in)
+	}
 	if in.DefaultCompositionRef != nil {
 		in, out := &in.DefaultCompositionRef, &out.DefaultCompositionRef
 		*out = new(CompositionReference)
@@ -218,6 +325,16 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(commonv1.UpdatePolicy)
 		**out = **in
 	}
+	if in.MaxConcurrentReconciles != nil {
+		in, out := &in.MaxConcurrentReconciles, &out.MaxConcurrentReconciles
+		*out = new(int)
+		**out = **in
+	}
+	if in.FunctionContext != nil {
+		in, out := &in.FunctionContext, &out.FunctionContext
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Versions != nil {
 		in, out := &in.Versions, &out.Versions
 		*out = make([]CompositeResourceDefinitionVersion, len(*in))
@@ -280,7 +397,7 @@ func (in *CompositeResourceDefinitionSpecMetadata) DeepCopy() *CompositeResource
 func (in *CompositeResourceDefinitionStatus) DeepCopyInto(out *CompositeResourceDefinitionStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.Controllers = in.Controllers
+	in.Controllers.DeepCopyInto(&out.Controllers)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceDefinitionStatus.
@@ -516,6 +633,40 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.CompositeConnectionDetails != nil {
+		in, out := &in.CompositeConnectionDetails, &out.CompositeConnectionDetails
+		*out = make([]CompositeConnectionDetail, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionOrder != nil {
+		in, out := &in.DeletionOrder, &out.DeletionOrder
+		*out = make([]DeletionPhase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ComposedOwnerReference != nil {
+		in, out := &in.ComposedOwnerReference, &out.ComposedOwnerReference
+		*out = new(ComposedOwnerReferencePolicy)
+		**out = **in
+	}
+	if in.ClaimNamespaceSelector != nil {
+		in, out := &in.ClaimNamespaceSelector, &out.ClaimNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessStableFor != nil {
+		in, out := &in.ReadinessStableFor, &out.ReadinessStableFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CompositeReadiness != nil {
+		in, out := &in.CompositeReadiness, &out.CompositeReadiness
+		*out = new(CompositeReadinessPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRevisionSpec.
@@ -584,6 +735,40 @@ func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.CompositeConnectionDetails != nil {
+		in, out := &in.CompositeConnectionDetails, &out.CompositeConnectionDetails
+		*out = make([]CompositeConnectionDetail, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionOrder != nil {
+		in, out := &in.DeletionOrder, &out.DeletionOrder
+		*out = make([]DeletionPhase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ComposedOwnerReference != nil {
+		in, out := &in.ComposedOwnerReference, &out.ComposedOwnerReference
+		*out = new(ComposedOwnerReferencePolicy)
+		**out = **in
+	}
+	if in.ClaimNamespaceSelector != nil {
+		in, out := &in.ClaimNamespaceSelector, &out.ClaimNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessStableFor != nil {
+		in, out := &in.ReadinessStableFor, &out.ReadinessStableFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CompositeReadiness != nil {
+		in, out := &in.CompositeReadiness, &out.CompositeReadiness
+		*out = new(CompositeReadinessPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionSpec.
@@ -624,6 +809,13 @@ func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Transforms != nil {
+		in, out := &in.Transforms, &out.Transforms
+		*out = make([]Transform, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetail.
@@ -644,6 +836,11 @@ func (in *ConvertTransform) DeepCopyInto(out *ConvertTransform) {
 		*out = new(ConvertTransformFormat)
 		**out = **in
 	}
+	if in.Unit != nil {
+		in, out := &in.Unit, &out.Unit
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConvertTransform.
@@ -656,6 +853,46 @@ func (in *ConvertTransform) DeepCopy() *ConvertTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPhase) DeepCopyInto(out *DeletionPhase) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPhase.
+func (in *DeletionPhase) DeepCopy() *DeletionPhase {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPhase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentConfigKey) DeepCopyInto(out *EnvironmentConfigKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentConfigKey.
+func (in *EnvironmentConfigKey) DeepCopy() *EnvironmentConfigKey {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentConfigKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionCredentials) DeepCopyInto(out *FunctionCredentials) {
 	*out = *in
@@ -822,6 +1059,53 @@ func (in *MathTransform) DeepCopy() *MathTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergePolicy) DeepCopyInto(out *MergePolicy) {
+	*out = *in
+	if in.MergeOptions != nil {
+		in, out := &in.MergeOptions, &out.MergeOptions
+		*out = new(commonv1.MergeOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergePolicy.
+func (in *MergePolicy) DeepCopy() *MergePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MergePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedResourceSelector) DeepCopyInto(out *ObservedResourceSelector) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedResourceSelector.
+func (in *ObservedResourceSelector) DeepCopy() *ObservedResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Patch) DeepCopyInto(out *Patch) {
 	*out = *in
@@ -835,6 +1119,16 @@ func (in *Patch) DeepCopyInto(out *Patch) {
 		*out = new(Combine)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReferencedKey != nil {
+		in, out := &in.ReferencedKey, &out.ReferencedKey
+		*out = new(ReferencedKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnvironmentConfig != nil {
+		in, out := &in.EnvironmentConfig, &out.EnvironmentConfig
+		*out = new(EnvironmentConfigKey)
+		**out = **in
+	}
 	if in.ToFieldPath != nil {
 		in, out := &in.ToFieldPath, &out.ToFieldPath
 		*out = new(string)
@@ -857,6 +1151,11 @@ func (in *Patch) DeepCopyInto(out *Patch) {
 		*out = new(PatchPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MatchCondition != nil {
+		in, out := &in.MatchCondition, &out.MatchCondition
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Patch.
@@ -925,6 +1224,11 @@ func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InterpolateInput != nil {
+		in, out := &in.InterpolateInput, &out.InterpolateInput
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Credentials != nil {
 		in, out := &in.Credentials, &out.Credentials
 		*out = make([]FunctionCredentials, len(*in))
@@ -932,6 +1236,21 @@ func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ObservedResources != nil {
+		in, out := &in.ObservedResources, &out.ObservedResources
+		*out = new(ObservedResourceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MatchCondition != nil {
+		in, out := &in.MatchCondition, &out.MatchCondition
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStep.
@@ -964,6 +1283,21 @@ func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReferencedKey) DeepCopyInto(out *ReferencedKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReferencedKey.
+func (in *ReferencedKey) DeepCopy() *ReferencedKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ReferencedKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreConfigReference) DeepCopyInto(out *StoreConfigReference) {
 	*out = *in
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A CompositeTypeReference references a type of composite resource.
+type CompositeTypeReference struct {
+	// APIVersion of the type.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the type.
+	Kind string `json:"kind"`
+}
+
+// A CompositionReference references a Composition.
+type CompositionReference struct {
+	// Name of the Composition.
+	Name string `json:"name"`
+}
+
+// DefaultCompositionSpec specifies the default Composition for a type of
+// composite resource.
+type DefaultCompositionSpec struct {
+	// CompositeTypeRef specifies the type of composite resource this default
+	// applies to.
+	CompositeTypeRef CompositeTypeReference `json:"compositeTypeRef"`
+
+	// CompositionRef specifies the Composition to use by default.
+	CompositionRef CompositionReference `json:"compositionRef"`
+}
+
+// A DefaultComposition specifies the Composition that composite resources
+// claimed in its namespace use when their claim doesn't specify one.
+//
+// A DefaultComposition takes precedence over the default Composition of the
+// CompositeResourceDefinition, but not over an enforced Composition or a
+// Composition the claim references or selects. A namespace should contain at
+// most one DefaultComposition per type of composite resource.
+// +kubebuilder:object:root=true
+// +genclient
+// +kubebuilder:printcolumn:name="KIND",type="string",JSONPath=".spec.compositeTypeRef.kind"
+// +kubebuilder:printcolumn:name="COMPOSITION",type="string",JSONPath=".spec.compositionRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced,categories=crossplane
+type DefaultComposition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DefaultCompositionSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// DefaultCompositionList contains a list of DefaultCompositions.
+type DefaultCompositionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DefaultComposition `json:"items"`
+}
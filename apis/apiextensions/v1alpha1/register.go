@@ -48,6 +48,14 @@ var (
 	EnvironmentConfigGroupVersionKind = SchemeGroupVersion.WithKind(EnvironmentConfigKind)
 )
 
+// DefaultComposition type metadata.
+var (
+	DefaultCompositionKind             = reflect.TypeOf(DefaultComposition{}).Name()
+	DefaultCompositionGroupKind        = schema.GroupKind{Group: Group, Kind: DefaultCompositionKind}.String()
+	DefaultCompositionKindAPIVersion   = DefaultCompositionKind + "." + SchemeGroupVersion.String()
+	DefaultCompositionGroupVersionKind = SchemeGroupVersion.WithKind(DefaultCompositionKind)
+)
+
 // Usage type metadata.
 var (
 	UsageKind             = reflect.TypeOf(Usage{}).Name()
@@ -59,4 +67,5 @@ var (
 func init() {
 	SchemeBuilder.Register(&Usage{}, &UsageList{})
 	SchemeBuilder.Register(&EnvironmentConfig{}, &EnvironmentConfigList{})
+	SchemeBuilder.Register(&DefaultComposition{}, &DefaultCompositionList{})
 }
@@ -25,6 +25,111 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeTypeReference) DeepCopyInto(out *CompositeTypeReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeTypeReference.
+func (in *CompositeTypeReference) DeepCopy() *CompositeTypeReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeTypeReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositionReference) DeepCopyInto(out *CompositionReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionReference.
+func (in *CompositionReference) DeepCopy() *CompositionReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositionReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultComposition) DeepCopyInto(out *DefaultComposition) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultComposition.
+func (in *DefaultComposition) DeepCopy() *DefaultComposition {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultComposition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DefaultComposition) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultCompositionList) DeepCopyInto(out *DefaultCompositionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DefaultComposition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultCompositionList.
+func (in *DefaultCompositionList) DeepCopy() *DefaultCompositionList {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultCompositionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DefaultCompositionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultCompositionSpec) DeepCopyInto(out *DefaultCompositionSpec) {
+	*out = *in
+	out.CompositeTypeRef = in.CompositeTypeRef
+	out.CompositionRef = in.CompositionRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultCompositionSpec.
+func (in *DefaultCompositionSpec) DeepCopy() *DefaultCompositionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultCompositionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentConfig) DeepCopyInto(out *EnvironmentConfig) {
 	*out = *in
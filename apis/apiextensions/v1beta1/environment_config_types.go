@@ -21,6 +21,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// AnnotationKeyCompositionWritableKeys is a comma separated list of the keys of
+// an EnvironmentConfig's data that ToEnvironmentConfig patches may write.
+const AnnotationKeyCompositionWritableKeys = "apiextensions.crossplane.io/composition-writable-keys"
+
 // +kubebuilder:object:root=true
 // +genclient
 // +genclient:nonNamespaced
@@ -20,6 +20,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -91,7 +92,22 @@ type ComposedTemplate struct {
 	// +optional
 	Name *string `json:"name,omitempty"`
 
-	// Base is the target resource that the patches will be applied on.
+	// NameTemplate is a Go template that renders the name of the composed
+	// resource when it's created. The template's data is the composite
+	// resource, for example '{{ .metadata.name }}-bucket'. If the rendered
+	// name is already in use by another resource of the same kind, whether
+	// composed by this or another composite resource, Crossplane appends a
+	// hyphen and a short hash of the composite resource's UID and this
+	// template's name. Crossplane returns an error if the hashed name is in
+	// use too. A name set by the base or a patch takes precedence over the
+	// name template. Only applies to composed resources that don't exist yet.
+	// +optional
+	NameTemplate *string `json:"nameTemplate,omitempty"`
+
+	// Base is the target resource that the patches will be applied on. When
+	// the alpha composed claim namespace feature is enabled a namespaced
+	// resource that isn't given a namespace via a patch is created in the
+	// namespace of the claim.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:EmbeddedResource
 	Base runtime.RawExtension `json:"base"`
@@ -105,12 +121,127 @@ type ComposedTemplate struct {
 	// +optional
 	ConnectionDetails []ConnectionDetail `json:"connectionDetails,omitempty"`
 
+	// PublishConnectionDetails determines whether the connection details
+	// this resource's connectionDetails declare are published to the
+	// composite resource's connection secret, and thus to its claim's
+	// connection secret. Set it to false for resources whose connection
+	// details are internal to the composition. The resource still writes its
+	// own connection secret, so other resources can use it. Defaults to true.
+	// +optional
+	// +kubebuilder:default=true
+	PublishConnectionDetails *bool `json:"publishConnectionDetails,omitempty"`
+
 	// ReadinessChecks allows users to define custom readiness checks. All checks
 	// have to return true in order for resource to be considered ready. The
 	// default readiness check is to have the "Ready" condition to be "True".
+	// A "None" readiness check always passes. A resource whose only readiness
+	// check is "None" is always considered ready, so it doesn't hold up the
+	// readiness of its composite resource.
 	// +optional
 	// +kubebuilder:default={{type:"MatchCondition",matchCondition:{type:"Ready",status:"True"}}}
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+
+	// MergePolicies configure fields of the composed resource that should be
+	// merged with, rather than replace, the field's existing value when the
+	// composed resource is applied. Use them for fields that are partly
+	// managed by something other than this Composition, like a provider.
+	// +optional
+	MergePolicies []MergePolicy `json:"mergePolicies,omitempty"`
+
+	// UpdatePolicy determines whether Crossplane updates the composed resource
+	// after creating it. Always, the default, applies the rendered resource
+	// every time the composite resource is reconciled. CreateOnly creates the
+	// composed resource if it doesn't exist, but never updates it afterwards,
+	// even if the Composition changes. Crossplane doesn't correct drift of a
+	// CreateOnly resource, but still observes it to derive connection
+	// details, readiness, and ToCompositeFieldPath patches. If a CreateOnly
+	// resource is deleted Crossplane creates it again. It's deleted along with
+	// the composite resource, like any other composed resource.
+	// +optional
+	// +kubebuilder:validation:Enum=Always;CreateOnly
+	UpdatePolicy *ComposedUpdatePolicy `json:"updatePolicy,omitempty"`
+
+	// InitFieldPaths are field paths of the composed resource that Crossplane
+	// only sets when it creates the composed resource, like a managed
+	// resource's initProvider. The values may come from the base or from
+	// patches. Once the composed resource exists Crossplane omits these
+	// fields when it applies the composed resource, so they may drift or be
+	// managed by something else. This overrides any patch to the same field.
+	// A composed resource is created if it doesn't exist when the composite
+	// resource is reconciled, including if it was deleted after creation.
+	// +optional
+	InitFieldPaths []string `json:"initFieldPaths,omitempty"`
+}
+
+// A ComposedUpdatePolicy determines whether Crossplane updates a composed
+// resource after creating it.
+type ComposedUpdatePolicy string
+
+// Composed resource update policies.
+const (
+	// ComposedUpdatePolicyAlways updates the composed resource every time the
+	// composite resource is reconciled.
+	ComposedUpdatePolicyAlways ComposedUpdatePolicy = "Always"
+
+	// ComposedUpdatePolicyCreateOnly creates the composed resource if it
+	// doesn't exist, but never updates it.
+	ComposedUpdatePolicyCreateOnly ComposedUpdatePolicy = "CreateOnly"
+)
+
+// A ComposedOwnerReferencePolicy determines how composed resources reference
+// the composite resource that owns them.
+type ComposedOwnerReferencePolicy string
+
+// Composed resource owner reference policies.
+const (
+	// ComposedOwnerReferenceController makes the composite resource the
+	// controller of its composed resources, blocking its foreground deletion.
+	ComposedOwnerReferenceController ComposedOwnerReferencePolicy = "Controller"
+
+	// ComposedOwnerReferenceNonBlockingController makes the composite
+	// resource the controller of its composed resources, without blocking its
+	// foreground deletion.
+	ComposedOwnerReferenceNonBlockingController ComposedOwnerReferencePolicy = "NonBlockingController"
+
+	// ComposedOwnerReferenceNone doesn't add an owner reference to composed
+	// resources.
+	ComposedOwnerReferenceNone ComposedOwnerReferencePolicy = "None"
+)
+
+// A CompositeReadinessPolicy determines how Crossplane decides whether a
+// composite resource is ready.
+type CompositeReadinessPolicy string
+
+// Composite resource readiness policies.
+const (
+	// CompositeReadinessComposed considers a composite resource ready when
+	// all of its composed resources are ready.
+	CompositeReadinessComposed CompositeReadinessPolicy = "Composed"
+
+	// CompositeReadinessExplicit only considers a composite resource ready
+	// when its Composition Function pipeline explicitly marks it ready.
+	CompositeReadinessExplicit CompositeReadinessPolicy = "Explicit"
+)
+
+// A MergePolicy configures how a map or array field of a composed resource is
+// merged with its existing value when the composed resource is applied.
+//
+// For a map, entries of the existing value that aren't in the rendered value
+// are preserved. Rendered entries replace existing entries with the same key,
+// unless keepMapValues is true. For an array, the rendered value replaces the
+// existing value, unless appendSlice is true. If appendSlice is true, rendered
+// elements that aren't already in the existing array are appended to it.
+//
+// Merged fields never have entries removed. Removing an entry from the
+// Composition doesn't remove it from the composed resource, because Crossplane
+// can't tell it apart from an entry that was added by something else.
+type MergePolicy struct {
+	// FieldPath of the map or array field of the composed resource to merge.
+	FieldPath string `json:"fieldPath"`
+
+	// MergeOptions configure how the field is merged.
+	// +optional
+	MergeOptions *xpv1.MergeOptions `json:"mergeOptions,omitempty"`
 }
 
 // GetName returns the name of the composed template or an empty string if it is nil.
@@ -132,7 +263,10 @@ const (
 	ReadinessCheckTypeMatchTrue      ReadinessCheckType = "MatchTrue"
 	ReadinessCheckTypeMatchFalse     ReadinessCheckType = "MatchFalse"
 	ReadinessCheckTypeMatchCondition ReadinessCheckType = "MatchCondition"
-	ReadinessCheckTypeNone           ReadinessCheckType = "None"
+
+	// ReadinessCheckTypeNone always passes. A resource whose only readiness
+	// check is None is always ready.
+	ReadinessCheckTypeNone ReadinessCheckType = "None"
 )
 
 // IsValid returns nil if the readiness check type is valid, or an error otherwise.
@@ -279,6 +413,57 @@ type ConnectionDetail struct {
 	// value, for example a well-known port.
 	// +optional
 	Value *string `json:"value,omitempty"`
+
+	// Transforms are the list of functions that are used as a FIFO pipe for
+	// the connection detail's value. They're applied after the value is
+	// extracted and before it's written to the connection secret of the
+	// composite resource. The first transform's input is the value as a
+	// string. If the last transform doesn't output a string its output is
+	// written as JSON. If a transform fails none of the composed resource's
+	// connection details are written, and the composite resource reports an
+	// error.
+	// +optional
+	Transforms []Transform `json:"transforms,omitempty"`
+}
+
+// A CompositeConnectionDetail is a connection detail that's read from the
+// composite resource itself, rather than from one of its composed resources.
+type CompositeConnectionDetail struct {
+	// Name of the connection secret key the value is written to.
+	Name string `json:"name"`
+
+	// FromFieldPath is the path of the field on the composite resource whose
+	// value is written to the connection secret. The path must be within the
+	// composite resource's spec. Values that aren't strings are written as
+	// JSON. Values are copied from the composite resource as is, so the field
+	// mustn't contain sensitive data.
+	FromFieldPath string `json:"fromFieldPath"`
+
+	// Policy configures the specifics of reading the field path.
+	// +optional
+	Policy *CompositeConnectionDetailPolicy `json:"policy,omitempty"`
+}
+
+// A CompositeConnectionDetailPolicy configures the specifics of reading a
+// connection detail from the composite resource.
+type CompositeConnectionDetailPolicy struct {
+	// FromFieldPath specifies how to read from the field path. The default is
+	// 'Optional', which means the connection detail is omitted if the
+	// specified fromFieldPath does not exist. Use 'Required' if the composite
+	// resource should report an error if the specified path does not exist.
+	// +kubebuilder:validation:Enum=Optional;Required
+	// +optional
+	FromFieldPath *FromFieldPathPolicy `json:"fromFieldPath,omitempty"`
+}
+
+// GetFromFieldPathPolicy returns the FromFieldPathPolicy for this
+// CompositeConnectionDetail, defaulting to FromFieldPathPolicyOptional if not
+// specified.
+func (d *CompositeConnectionDetail) GetFromFieldPathPolicy() FromFieldPathPolicy {
+	if d.Policy == nil || d.Policy.FromFieldPath == nil {
+		return FromFieldPathPolicyOptional
+	}
+	return *d.Policy.FromFieldPath
 }
 
 // A PipelineStep in a Composition Function pipeline.
@@ -298,11 +483,60 @@ type PipelineStep struct {
 	// +kubebuilder:validation:EmbeddedResource
 	Input *runtime.RawExtension `json:"input,omitempty"`
 
+	// InterpolateInput enables references to composite resource (XR) fields
+	// in the step's input. When enabled, each ${xr.<fieldpath>} in a string
+	// value of the input is replaced by the value of that XR field before the
+	// input is sent to the Function, for example ${xr.spec.region}. Write
+	// $${xr.<fieldpath>} to send a reference literally. The Composition fails
+	// if a reference is to a field that doesn't exist.
+	// +optional
+	InterpolateInput *bool `json:"interpolateInput,omitempty"`
+
 	// Credentials are optional credentials that the Composition Function needs.
 	// +optional
 	// +listType=map
 	// +listMapKey=name
 	Credentials []FunctionCredentials `json:"credentials,omitempty"`
+
+	// ObservedResources selects the observed composed resources that are sent
+	// to the Composition Function. Selecting only the composed resources a
+	// Function needs reduces the size of its RunFunctionRequest. All observed
+	// composed resources are sent if no selector is specified. The observed
+	// composite resource is always sent.
+	// +optional
+	ObservedResources *ObservedResourceSelector `json:"observedResources,omitempty"`
+
+	// Enabled determines whether the step runs. A disabled step is skipped
+	// as if it weren't part of the pipeline, so any composed resources that
+	// only it produced are deleted. Function state the step persisted is
+	// kept, and sent to the step when it's enabled again. Steps are enabled
+	// by default.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MatchCondition is a CEL expression that must evaluate to true for the
+	// step to run. The composite resource is available to the expression as
+	// the variable 'xr', for example xr.spec.parameters.beta == true. A step
+	// whose condition is false is skipped as if it were disabled. Use has()
+	// to test for optional fields; an expression that can't be evaluated is
+	// an error.
+	// +optional
+	MatchCondition *string `json:"matchCondition,omitempty"`
+}
+
+// An ObservedResourceSelector selects observed composed resources. A composed
+// resource is selected if its name is one of the selector's names, or if its
+// labels match all of the selector's labels. A selector that specifies
+// neither names nor labels selects no composed resources.
+type ObservedResourceSelector struct {
+	// Names of composed resources to select, i.e. the name of each resource
+	// within the Composition.
+	// +optional
+	Names []string `json:"names,omitempty"`
+
+	// MatchLabels selects composed resources that have all of these labels.
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
 }
 
 // A FunctionReference references a Composition Function that may be used in a
@@ -342,6 +576,26 @@ const (
 	FunctionCredentialsSourceSecret FunctionCredentialsSource = "Secret"
 )
 
+// A DeletionPhase is a set of composed resources that are deleted together,
+// when a composite resource is deleted.
+type DeletionPhase struct {
+	// Resources to delete in this phase, by the name of the composed resource
+	// within the composition, i.e. the name of a resource template or of a
+	// composed resource produced by a Composition Function.
+	// +kubebuilder:validation:MinItems=1
+	Resources []string `json:"resources"`
+
+	// Timeout is how long to wait for this phase's composed resources to be
+	// deleted, measured from when they were first deleted. Once the timeout
+	// is exceeded Crossplane emits a warning event and starts the next phase,
+	// without waiting for this phase's composed resources to be deleted. If
+	// no timeout is specified Crossplane waits for them indefinitely, and
+	// reports which composed resources it's waiting for in the composite
+	// resource's Ready condition.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
 // A StoreConfigReference references a secret store config that may be used to
 // write connection details.
 type StoreConfigReference struct {
@@ -119,6 +119,89 @@ type CompositionRevisionSpec struct {
 	// +kubebuilder:default={"name": "default"}
 	PublishConnectionDetailsWithStoreConfigRef *StoreConfigReference `json:"publishConnectionDetailsWithStoreConfigRef,omitempty"`
 
+	// DisableConnectionSecrets disables connection secret publishing for
+	// composite resources that use this composition. Crossplane won't write a
+	// connection secret, and won't record when connection details were last
+	// published. A composite resource's writeConnectionSecretToRef is ignored
+	// when connection secrets are disabled, with a warning event. A connection
+	// secret that was published before connection secrets were disabled
+	// isn't deleted. It's garbage collected when its composite resource is
+	// deleted.
+	// +optional
+	DisableConnectionSecrets bool `json:"disableConnectionSecrets,omitempty"`
+
+	// CompositeConnectionDetails are connection details that are read from
+	// the composite resource's spec, and published alongside the connection
+	// details of its composed resources. A connection detail from a composed
+	// resource takes precedence over a composite connection detail with the
+	// same name. Composite connection details are subject to the same rules
+	// as other connection details - for example they're filtered by the
+	// CompositeResourceDefinition's connectionSecretKeys, and aren't published
+	// when connection secrets are disabled.
+	// +optional
+	CompositeConnectionDetails []CompositeConnectionDetail `json:"compositeConnectionDetails,omitempty"`
+
+	// DeletionOrder specifies the order in which composed resources are
+	// deleted when a composite resource that uses this composition is
+	// deleted. Each phase's composed resources are deleted, and must be gone,
+	// before the next phase's composed resources are deleted. Composed
+	// resources that aren't in any phase are deleted once all phases have
+	// completed. Deletion ordering only applies when the composite resource is
+	// deleted using background cascading deletion, which is the default. When
+	// foreground cascading deletion is used Kubernetes deletes all composed
+	// resources concurrently.
+	// +optional
+	DeletionOrder []DeletionPhase `json:"deletionOrder,omitempty"`
+
+	// ComposedOwnerReference determines how composed resources reference the
+	// composite resource that owns them. Controller, the default, makes the
+	// composite resource the controller of each composed resource, and blocks
+	// foreground deletion of the composite resource until its composed
+	// resources are gone. NonBlockingController makes the composite resource
+	// the controller of each composed resource without blocking its foreground
+	// deletion. None doesn't add an owner reference to composed resources.
+	// Instead it labels them with the UID of the composite resource.
+	// Crossplane still creates, updates, and garbage collects them while the
+	// composite resource exists, but neither Crossplane nor Kubernetes deletes
+	// them when the composite resource is deleted. Use None to hand off
+	// composed resources when their composite resource is deleted. Changing
+	// this field to None isn't guaranteed to remove owner references that
+	// Crossplane already added to composed resources.
+	// +optional
+	// +kubebuilder:validation:Enum=Controller;NonBlockingController;None
+	ComposedOwnerReference *ComposedOwnerReferencePolicy `json:"composedOwnerReference,omitempty"`
+
+	// ClaimNamespaceSelector restricts which claims may use this composition.
+	// If set, a composite resource that is bound to a claim may only use this
+	// composition if the labels of the claim's namespace match the selector.
+	// This applies whether the composition is selected by labels, referenced
+	// directly, or set as a default or enforced composition. Composite
+	// resources that aren't bound to a claim may use this composition
+	// regardless of the selector.
+	// +optional
+	ClaimNamespaceSelector *metav1.LabelSelector `json:"claimNamespaceSelector,omitempty"`
+
+	// ReadinessStableFor specifies how long each composed resource must be
+	// continuously ready before a composite resource that uses this composition
+	// is considered ready. A composed resource that becomes unready must be ready
+	// for this long again. If unset, the duration configured by Crossplane's
+	// --readiness-stable-for flag is used. Zero disables it.
+	// +optional
+	ReadinessStableFor *metav1.Duration `json:"readinessStableFor,omitempty"`
+
+	// CompositeReadiness determines how Crossplane decides whether a composite
+	// resource that uses this composition is ready. Composed, the default,
+	// considers the composite resource ready when all of its composed
+	// resources are ready. A composed resource whose only readiness check is
+	// None is always ready, so it doesn't hold up the composite resource.
+	// Explicit only considers the composite resource ready when the
+	// Composition Function pipeline explicitly marks it ready, regardless of
+	// whether its composed resources are ready. Explicit requires Pipeline
+	// mode.
+	// +optional
+	// +kubebuilder:validation:Enum=Composed;Explicit
+	CompositeReadiness *CompositeReadinessPolicy `json:"compositeReadiness,omitempty"`
+
 	// Revision number. Newer revisions have larger numbers.
 	//
 	// This number can change. When a Composition transitions from state A
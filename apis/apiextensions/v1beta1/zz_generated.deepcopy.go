@@ -23,6 +23,7 @@ package v1beta1
 import (
 	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -74,6 +75,11 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
 	in.Base.DeepCopyInto(&out.Base)
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
@@ -89,6 +95,11 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PublishConnectionDetails != nil {
+		in, out := &in.PublishConnectionDetails, &out.PublishConnectionDetails
+		*out = new(bool)
+		**out = **in
+	}
 	if in.ReadinessChecks != nil {
 		in, out := &in.ReadinessChecks, &out.ReadinessChecks
 		*out = make([]ReadinessCheck, len(*in))
@@ -96,6 +107,23 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MergePolicies != nil {
+		in, out := &in.MergePolicies, &out.MergePolicies
+		*out = make([]MergePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpdatePolicy != nil {
+		in, out := &in.UpdatePolicy, &out.UpdatePolicy
+		*out = new(ComposedUpdatePolicy)
+		**out = **in
+	}
+	if in.InitFieldPaths != nil {
+		in, out := &in.InitFieldPaths, &out.InitFieldPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedTemplate.
@@ -108,6 +136,46 @@ func (in *ComposedTemplate) DeepCopy() *ComposedTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeConnectionDetail) DeepCopyInto(out *CompositeConnectionDetail) {
+	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(CompositeConnectionDetailPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeConnectionDetail.
+func (in *CompositeConnectionDetail) DeepCopy() *CompositeConnectionDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeConnectionDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeConnectionDetailPolicy) DeepCopyInto(out *CompositeConnectionDetailPolicy) {
+	*out = *in
+	if in.FromFieldPath != nil {
+		in, out := &in.FromFieldPath, &out.FromFieldPath
+		*out = new(FromFieldPathPolicy)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeConnectionDetailPolicy.
+func (in *CompositeConnectionDetailPolicy) DeepCopy() *CompositeConnectionDetailPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeConnectionDetailPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositionRevision) DeepCopyInto(out *CompositionRevision) {
 	*out = *in
@@ -207,6 +275,40 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.CompositeConnectionDetails != nil {
+		in, out := &in.CompositeConnectionDetails, &out.CompositeConnectionDetails
+		*out = make([]CompositeConnectionDetail, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionOrder != nil {
+		in, out := &in.DeletionOrder, &out.DeletionOrder
+		*out = make([]DeletionPhase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ComposedOwnerReference != nil {
+		in, out := &in.ComposedOwnerReference, &out.ComposedOwnerReference
+		*out = new(ComposedOwnerReferencePolicy)
+		**out = **in
+	}
+	if in.ClaimNamespaceSelector != nil {
+		in, out := &in.ClaimNamespaceSelector, &out.ClaimNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadinessStableFor != nil {
+		in, out := &in.ReadinessStableFor, &out.ReadinessStableFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CompositeReadiness != nil {
+		in, out := &in.CompositeReadiness, &out.CompositeReadiness
+		*out = new(CompositeReadinessPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRevisionSpec.
@@ -263,6 +365,13 @@ func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Transforms != nil {
+		in, out := &in.Transforms, &out.Transforms
+		*out = make([]Transform, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionDetail.
@@ -283,6 +392,11 @@ func (in *ConvertTransform) DeepCopyInto(out *ConvertTransform) {
 		*out = new(ConvertTransformFormat)
 		**out = **in
 	}
+	if in.Unit != nil {
+		in, out := &in.Unit, &out.Unit
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConvertTransform.
@@ -295,6 +409,31 @@ func (in *ConvertTransform) DeepCopy() *ConvertTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPhase) DeepCopyInto(out *DeletionPhase) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPhase.
+func (in *DeletionPhase) DeepCopy() *DeletionPhase {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPhase)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentConfig) DeepCopyInto(out *EnvironmentConfig) {
 	*out = *in
@@ -359,6 +498,21 @@ func (in *EnvironmentConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentConfigKey) DeepCopyInto(out *EnvironmentConfigKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentConfigKey.
+func (in *EnvironmentConfigKey) DeepCopy() *EnvironmentConfigKey {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentConfigKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FunctionCredentials) DeepCopyInto(out *FunctionCredentials) {
 	*out = *in
@@ -510,6 +664,53 @@ func (in *MathTransform) DeepCopy() *MathTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MergePolicy) DeepCopyInto(out *MergePolicy) {
+	*out = *in
+	if in.MergeOptions != nil {
+		in, out := &in.MergeOptions, &out.MergeOptions
+		*out = new(commonv1.MergeOptions)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MergePolicy.
+func (in *MergePolicy) DeepCopy() *MergePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MergePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedResourceSelector) DeepCopyInto(out *ObservedResourceSelector) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedResourceSelector.
+func (in *ObservedResourceSelector) DeepCopy() *ObservedResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Patch) DeepCopyInto(out *Patch) {
 	*out = *in
@@ -523,6 +724,16 @@ func (in *Patch) DeepCopyInto(out *Patch) {
 		*out = new(Combine)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReferencedKey != nil {
+		in, out := &in.ReferencedKey, &out.ReferencedKey
+		*out = new(ReferencedKey)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnvironmentConfig != nil {
+		in, out := &in.EnvironmentConfig, &out.EnvironmentConfig
+		*out = new(EnvironmentConfigKey)
+		**out = **in
+	}
 	if in.ToFieldPath != nil {
 		in, out := &in.ToFieldPath, &out.ToFieldPath
 		*out = new(string)
@@ -545,6 +756,11 @@ func (in *Patch) DeepCopyInto(out *Patch) {
 		*out = new(PatchPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MatchCondition != nil {
+		in, out := &in.MatchCondition, &out.MatchCondition
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Patch.
@@ -613,6 +829,11 @@ func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InterpolateInput != nil {
+		in, out := &in.InterpolateInput, &out.InterpolateInput
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Credentials != nil {
 		in, out := &in.Credentials, &out.Credentials
 		*out = make([]FunctionCredentials, len(*in))
@@ -620,6 +841,21 @@ func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ObservedResources != nil {
+		in, out := &in.ObservedResources, &out.ObservedResources
+		*out = new(ObservedResourceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MatchCondition != nil {
+		in, out := &in.MatchCondition, &out.MatchCondition
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStep.
@@ -652,6 +888,21 @@ func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReferencedKey) DeepCopyInto(out *ReferencedKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReferencedKey.
+func (in *ReferencedKey) DeepCopy() *ReferencedKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ReferencedKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resource) DeepCopyInto(out *Resource) {
 	*out = *in
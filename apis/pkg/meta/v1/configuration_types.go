@@ -23,6 +23,35 @@ import (
 // ConfigurationSpec specifies the configuration of a Configuration.
 type ConfigurationSpec struct {
 	MetaSpec `json:",inline"`
+
+	// DependencyLock pins the Configuration's dependencies, and their
+	// transitive dependencies, to the exact packages they resolved to when
+	// the lock was generated. The package manager installs locked packages
+	// instead of resolving dependency version constraints. Generate the lock
+	// using crossplane xpkg lock.
+	// +optional
+	DependencyLock []LockedDependency `json:"dependencyLock,omitempty"`
+}
+
+// A LockedDependency pins a dependency to an exact package.
+type LockedDependency struct {
+	// APIVersion of the dependency.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the dependency.
+	Kind string `json:"kind"`
+
+	// Package OCI reference of the dependency, without a tag or digest.
+	Package string `json:"package"`
+
+	// Version the dependency resolved to. Transitive dependencies are pinned
+	// to this version, because the packages that depend on them constrain
+	// them by semantic version.
+	Version string `json:"version"`
+
+	// Digest of the package the dependency resolved to. Direct dependencies
+	// are pinned to this digest.
+	Digest string `json:"digest"`
 }
 
 // +kubebuilder:object:root=true
@@ -35,6 +35,8 @@ type Pkg interface {
 
 	GetCrossplaneConstraints() *CrossplaneConstraints
 	GetDependencies() []Dependency
+	GetConflicts() []Conflict
+	GetRequiredAPIs() []RequiredAPI
 }
 
 // GetCrossplaneConstraints gets the Configuration package's Crossplane version
@@ -48,6 +50,16 @@ func (c *Configuration) GetDependencies() []Dependency {
 	return c.Spec.MetaSpec.DependsOn
 }
 
+// GetConflicts gets the packages the Configuration package conflicts with.
+func (c *Configuration) GetConflicts() []Conflict {
+	return c.Spec.MetaSpec.Conflicts
+}
+
+// GetRequiredAPIs gets the Kubernetes APIs the Configuration package requires.
+func (c *Configuration) GetRequiredAPIs() []RequiredAPI {
+	return c.Spec.MetaSpec.RequiredAPIs
+}
+
 // GetCrossplaneConstraints gets the Provider package's Crossplane version
 // constraints.
 func (p *Provider) GetCrossplaneConstraints() *CrossplaneConstraints {
@@ -59,6 +71,16 @@ func (p *Provider) GetDependencies() []Dependency {
 	return p.Spec.MetaSpec.DependsOn
 }
 
+// GetConflicts gets the packages the Provider package conflicts with.
+func (p *Provider) GetConflicts() []Conflict {
+	return p.Spec.MetaSpec.Conflicts
+}
+
+// GetRequiredAPIs gets the Kubernetes APIs the Provider package requires.
+func (p *Provider) GetRequiredAPIs() []RequiredAPI {
+	return p.Spec.MetaSpec.RequiredAPIs
+}
+
 // GetCrossplaneConstraints gets the Function package's Crossplane version constraints.
 func (f *Function) GetCrossplaneConstraints() *CrossplaneConstraints {
 	return f.Spec.MetaSpec.Crossplane
@@ -68,3 +90,13 @@ func (f *Function) GetCrossplaneConstraints() *CrossplaneConstraints {
 func (f *Function) GetDependencies() []Dependency {
 	return f.Spec.DependsOn
 }
+
+// GetConflicts gets the packages the Function package conflicts with.
+func (f *Function) GetConflicts() []Conflict {
+	return f.Spec.Conflicts
+}
+
+// GetRequiredAPIs gets the Kubernetes APIs the Function package requires.
+func (f *Function) GetRequiredAPIs() []RequiredAPI {
+	return f.Spec.RequiredAPIs
+}
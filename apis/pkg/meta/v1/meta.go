@@ -23,11 +23,25 @@ type MetaSpec struct {
 
 	// Dependencies on other packages.
 	DependsOn []Dependency `json:"dependsOn,omitempty"`
+
+	// Conflicts with other packages. The package manager refuses to activate
+	// a package that conflicts with an installed package, whichever of the
+	// two packages declares the conflict.
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+
+	// RequiredAPIs are Kubernetes APIs that must be served by the cluster
+	// before the package can be activated. The package manager refuses to
+	// activate a package while any of its required APIs are missing.
+	RequiredAPIs []RequiredAPI `json:"requiredAPIs,omitempty"`
 }
 
 // CrossplaneConstraints specifies a packages compatibility with Crossplane versions.
 type CrossplaneConstraints struct {
 	// Semantic version constraints of Crossplane that package is compatible with.
+	// This may be a single minimum version, e.g. ">=v1.14.0", or a range,
+	// e.g. ">=v1.14.0 <v2.0.0". Constraints separated by spaces or commas
+	// must all be satisfied, while constraints separated by "||" are
+	// alternatives.
 	Version string `json:"version"`
 }
 
@@ -68,3 +82,24 @@ type Dependency struct {
 	// Version is the semantic version constraints of the dependency image.
 	Version string `json:"version"`
 }
+
+// A Conflict is another package that can't be installed alongside this one,
+// for example because both packages define the same CRDs.
+type Conflict struct {
+	// Package OCI reference of the conflicting package, without a tag or
+	// digest. For example xpkg.crossplane.io/crossplane-contrib/provider-nop.
+	Package string `json:"package"`
+}
+
+// A RequiredAPI is a Kubernetes API that must be served by the cluster, for
+// example the gateway.networking.k8s.io API group.
+type RequiredAPI struct {
+	// Group of the required API, for example gateway.networking.k8s.io. Use
+	// an empty string to refer to the core API group.
+	Group string `json:"group"`
+
+	// Version of the required API group, for example v1. Any served version
+	// of the group satisfies the requirement if the version is omitted.
+	// +optional
+	Version *string `json:"version,omitempty"`
+}
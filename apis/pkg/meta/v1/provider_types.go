@@ -26,9 +26,28 @@ type ProviderSpec struct {
 	// Configuration for the packaged Provider's controller.
 	Controller ControllerSpec `json:"controller"`
 
+	// Capabilities of the packaged Provider. Crossplane warns when a
+	// composed resource uses a feature that requires a capability its
+	// Provider doesn't declare. Well-known capabilities are
+	// ManagementPolicies and InitProvider. A Provider that declares no
+	// capabilities isn't checked.
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+
 	MetaSpec `json:",inline"`
 }
 
+// Well-known Provider capabilities.
+const (
+	// ProviderCapabilityManagementPolicies indicates that a Provider's
+	// managed resources support spec.managementPolicies.
+	ProviderCapabilityManagementPolicies = "ManagementPolicies"
+
+	// ProviderCapabilityInitProvider indicates that a Provider's managed
+	// resources support spec.initProvider.
+	ProviderCapabilityInitProvider = "InitProvider"
+)
+
 // ControllerSpec specifies the configuration for the packaged Provider
 // controller.
 type ControllerSpec struct {
@@ -40,6 +59,48 @@ type ControllerSpec struct {
 	// permissions.
 	// +optional
 	PermissionRequests []rbacv1.PolicyRule `json:"permissionRequests,omitempty"`
+
+	// WritablePaths are paths the packaged Provider controller needs to write
+	// to. They remain writable when the controller runs with a read-only root
+	// filesystem.
+	// +optional
+	WritablePaths []string `json:"writablePaths,omitempty"`
+
+	// InstallHook is a Job Crossplane runs once per revision of the
+	// packaged Provider, after its controller is available and before the
+	// revision is marked healthy.
+	// +optional
+	InstallHook *InstallHook `json:"installHook,omitempty"`
+}
+
+// An InstallHook is a one-time setup Job for a packaged Provider, for example
+// to register a webhook. The Job runs the Provider's controller image with the
+// same service account, pull secrets, and runtime configuration as the
+// controller.
+//
+// Crossplane runs the hook once per Provider revision, so it runs again when
+// the Provider is upgraded. The hook must therefore be idempotent. The revision
+// is unhealthy until the hook's Job completes. If the Job fails Crossplane
+// doesn't retry it. Delete the failed Job to run the hook again.
+type InstallHook struct {
+	// Command to run instead of the controller image's entrypoint. It's
+	// required, because the entrypoint runs the controller, which never
+	// exits.
+	Command []string `json:"command"`
+
+	// Args to pass to the command.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// TimeoutSeconds is how long the Job may run before it fails. Defaults
+	// to 600 seconds.
+	// +optional
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+
+	// BackoffLimit is how many times the Job retries a failed pod before
+	// the Job fails. Defaults to 3.
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -42,11 +42,60 @@ func (c *GeneratedFromHubConverter) pV1CrossplaneConstraintsToPV1alpha1Crossplan
 	}
 	return pV1alpha1CrossplaneConstraints
 }
+func (c *GeneratedFromHubConverter) pV1InstallHookToPV1alpha1InstallHook(source *v1.InstallHook) *InstallHook {
+	var pV1alpha1InstallHook *InstallHook
+	if source != nil {
+		var v1alpha1InstallHook InstallHook
+		var stringList []string
+		if (*source).Command != nil {
+			stringList = make([]string, len((*source).Command))
+			for i := 0; i < len((*source).Command); i++ {
+				stringList[i] = (*source).Command[i]
+			}
+		}
+		v1alpha1InstallHook.Command = stringList
+		var stringList2 []string
+		if (*source).Args != nil {
+			stringList2 = make([]string, len((*source).Args))
+			for j := 0; j < len((*source).Args); j++ {
+				stringList2[j] = (*source).Args[j]
+			}
+		}
+		v1alpha1InstallHook.Args = stringList2
+		var pInt64 *int64
+		if (*source).TimeoutSeconds != nil {
+			xint64 := *(*source).TimeoutSeconds
+			pInt64 = &xint64
+		}
+		v1alpha1InstallHook.TimeoutSeconds = pInt64
+		var pInt32 *int32
+		if (*source).BackoffLimit != nil {
+			xint32 := *(*source).BackoffLimit
+			pInt32 = &xint32
+		}
+		v1alpha1InstallHook.BackoffLimit = pInt32
+		pV1alpha1InstallHook = &v1alpha1InstallHook
+	}
+	return pV1alpha1InstallHook
+}
 func (c *GeneratedFromHubConverter) v1ConfigurationSpecToV1alpha1ConfigurationSpec(source v1.ConfigurationSpec) ConfigurationSpec {
 	var v1alpha1ConfigurationSpec ConfigurationSpec
 	v1alpha1ConfigurationSpec.MetaSpec = c.v1MetaSpecToV1alpha1MetaSpec(source.MetaSpec)
+	var v1alpha1LockedDependencyList []LockedDependency
+	if source.DependencyLock != nil {
+		v1alpha1LockedDependencyList = make([]LockedDependency, len(source.DependencyLock))
+		for i := 0; i < len(source.DependencyLock); i++ {
+			v1alpha1LockedDependencyList[i] = c.v1LockedDependencyToV1alpha1LockedDependency(source.DependencyLock[i])
+		}
+	}
+	v1alpha1ConfigurationSpec.DependencyLock = v1alpha1LockedDependencyList
 	return v1alpha1ConfigurationSpec
 }
+func (c *GeneratedFromHubConverter) v1ConflictToV1alpha1Conflict(source v1.Conflict) Conflict {
+	var v1alpha1Conflict Conflict
+	v1alpha1Conflict.Package = source.Package
+	return v1alpha1Conflict
+}
 func (c *GeneratedFromHubConverter) v1ControllerSpecToV1alpha1ControllerSpec(source v1.ControllerSpec) ControllerSpec {
 	var v1alpha1ControllerSpec ControllerSpec
 	var pString *string
@@ -63,6 +112,15 @@ func (c *GeneratedFromHubConverter) v1ControllerSpecToV1alpha1ControllerSpec(sou
 		}
 	}
 	v1alpha1ControllerSpec.PermissionRequests = v1PolicyRuleList
+	var stringList []string
+	if source.WritablePaths != nil {
+		stringList = make([]string, len(source.WritablePaths))
+		for j := 0; j < len(source.WritablePaths); j++ {
+			stringList[j] = source.WritablePaths[j]
+		}
+	}
+	v1alpha1ControllerSpec.WritablePaths = stringList
+	v1alpha1ControllerSpec.InstallHook = c.pV1InstallHookToPV1alpha1InstallHook(source.InstallHook)
 	return v1alpha1ControllerSpec
 }
 func (c *GeneratedFromHubConverter) v1DependencyToV1alpha1Dependency(source v1.Dependency) Dependency {
@@ -106,6 +164,15 @@ func (c *GeneratedFromHubConverter) v1DependencyToV1alpha1Dependency(source v1.D
 	v1alpha1Dependency.Version = source.Version
 	return v1alpha1Dependency
 }
+func (c *GeneratedFromHubConverter) v1LockedDependencyToV1alpha1LockedDependency(source v1.LockedDependency) LockedDependency {
+	var v1alpha1LockedDependency LockedDependency
+	v1alpha1LockedDependency.APIVersion = source.APIVersion
+	v1alpha1LockedDependency.Kind = source.Kind
+	v1alpha1LockedDependency.Package = source.Package
+	v1alpha1LockedDependency.Version = source.Version
+	v1alpha1LockedDependency.Digest = source.Digest
+	return v1alpha1LockedDependency
+}
 func (c *GeneratedFromHubConverter) v1MetaSpecToV1alpha1MetaSpec(source v1.MetaSpec) MetaSpec {
 	var v1alpha1MetaSpec MetaSpec
 	v1alpha1MetaSpec.Crossplane = c.pV1CrossplaneConstraintsToPV1alpha1CrossplaneConstraints(source.Crossplane)
@@ -117,6 +184,22 @@ func (c *GeneratedFromHubConverter) v1MetaSpecToV1alpha1MetaSpec(source v1.MetaS
 		}
 	}
 	v1alpha1MetaSpec.DependsOn = v1alpha1DependencyList
+	var v1alpha1ConflictList []Conflict
+	if source.Conflicts != nil {
+		v1alpha1ConflictList = make([]Conflict, len(source.Conflicts))
+		for j := 0; j < len(source.Conflicts); j++ {
+			v1alpha1ConflictList[j] = c.v1ConflictToV1alpha1Conflict(source.Conflicts[j])
+		}
+	}
+	v1alpha1MetaSpec.Conflicts = v1alpha1ConflictList
+	var v1alpha1RequiredAPIList []RequiredAPI
+	if source.RequiredAPIs != nil {
+		v1alpha1RequiredAPIList = make([]RequiredAPI, len(source.RequiredAPIs))
+		for k := 0; k < len(source.RequiredAPIs); k++ {
+			v1alpha1RequiredAPIList[k] = c.v1RequiredAPIToV1alpha1RequiredAPI(source.RequiredAPIs[k])
+		}
+	}
+	v1alpha1MetaSpec.RequiredAPIs = v1alpha1RequiredAPIList
 	return v1alpha1MetaSpec
 }
 func (c *GeneratedFromHubConverter) v1PolicyRuleToV1PolicyRule(source v11.PolicyRule) v11.PolicyRule {
@@ -166,9 +249,28 @@ func (c *GeneratedFromHubConverter) v1PolicyRuleToV1PolicyRule(source v11.Policy
 func (c *GeneratedFromHubConverter) v1ProviderSpecToV1alpha1ProviderSpec(source v1.ProviderSpec) ProviderSpec {
 	var v1alpha1ProviderSpec ProviderSpec
 	v1alpha1ProviderSpec.Controller = c.v1ControllerSpecToV1alpha1ControllerSpec(source.Controller)
+	var stringList []string
+	if source.Capabilities != nil {
+		stringList = make([]string, len(source.Capabilities))
+		for i := 0; i < len(source.Capabilities); i++ {
+			stringList[i] = source.Capabilities[i]
+		}
+	}
+	v1alpha1ProviderSpec.Capabilities = stringList
 	v1alpha1ProviderSpec.MetaSpec = c.v1MetaSpecToV1alpha1MetaSpec(source.MetaSpec)
 	return v1alpha1ProviderSpec
 }
+func (c *GeneratedFromHubConverter) v1RequiredAPIToV1alpha1RequiredAPI(source v1.RequiredAPI) RequiredAPI {
+	var v1alpha1RequiredAPI RequiredAPI
+	v1alpha1RequiredAPI.Group = source.Group
+	var pString *string
+	if source.Version != nil {
+		xstring := *source.Version
+		pString = &xstring
+	}
+	v1alpha1RequiredAPI.Version = pString
+	return v1alpha1RequiredAPI
+}
 func (c *GeneratedFromHubConverter) v1TypeMetaToV1TypeMeta(source v12.TypeMeta) v12.TypeMeta {
 	var v1TypeMeta v12.TypeMeta
 	v1TypeMeta.Kind = source.Kind
@@ -209,6 +311,42 @@ func (c *GeneratedToHubConverter) pV1alpha1CrossplaneConstraintsToPV1CrossplaneC
 	}
 	return pV1CrossplaneConstraints
 }
+func (c *GeneratedToHubConverter) pV1alpha1InstallHookToPV1InstallHook(source *InstallHook) *v1.InstallHook {
+	var pV1InstallHook *v1.InstallHook
+	if source != nil {
+		var v1InstallHook v1.InstallHook
+		var stringList []string
+		if (*source).Command != nil {
+			stringList = make([]string, len((*source).Command))
+			for i := 0; i < len((*source).Command); i++ {
+				stringList[i] = (*source).Command[i]
+			}
+		}
+		v1InstallHook.Command = stringList
+		var stringList2 []string
+		if (*source).Args != nil {
+			stringList2 = make([]string, len((*source).Args))
+			for j := 0; j < len((*source).Args); j++ {
+				stringList2[j] = (*source).Args[j]
+			}
+		}
+		v1InstallHook.Args = stringList2
+		var pInt64 *int64
+		if (*source).TimeoutSeconds != nil {
+			xint64 := *(*source).TimeoutSeconds
+			pInt64 = &xint64
+		}
+		v1InstallHook.TimeoutSeconds = pInt64
+		var pInt32 *int32
+		if (*source).BackoffLimit != nil {
+			xint32 := *(*source).BackoffLimit
+			pInt32 = &xint32
+		}
+		v1InstallHook.BackoffLimit = pInt32
+		pV1InstallHook = &v1InstallHook
+	}
+	return pV1InstallHook
+}
 func (c *GeneratedToHubConverter) v1PolicyRuleToV1PolicyRule(source v11.PolicyRule) v11.PolicyRule {
 	var v1PolicyRule v11.PolicyRule
 	var stringList []string
@@ -262,8 +400,21 @@ func (c *GeneratedToHubConverter) v1TypeMetaToV1TypeMeta(source v12.TypeMeta) v1
 func (c *GeneratedToHubConverter) v1alpha1ConfigurationSpecToV1ConfigurationSpec(source ConfigurationSpec) v1.ConfigurationSpec {
 	var v1ConfigurationSpec v1.ConfigurationSpec
 	v1ConfigurationSpec.MetaSpec = c.v1alpha1MetaSpecToV1MetaSpec(source.MetaSpec)
+	var v1LockedDependencyList []v1.LockedDependency
+	if source.DependencyLock != nil {
+		v1LockedDependencyList = make([]v1.LockedDependency, len(source.DependencyLock))
+		for i := 0; i < len(source.DependencyLock); i++ {
+			v1LockedDependencyList[i] = c.v1alpha1LockedDependencyToV1LockedDependency(source.DependencyLock[i])
+		}
+	}
+	v1ConfigurationSpec.DependencyLock = v1LockedDependencyList
 	return v1ConfigurationSpec
 }
+func (c *GeneratedToHubConverter) v1alpha1ConflictToV1Conflict(source Conflict) v1.Conflict {
+	var v1Conflict v1.Conflict
+	v1Conflict.Package = source.Package
+	return v1Conflict
+}
 func (c *GeneratedToHubConverter) v1alpha1ControllerSpecToV1ControllerSpec(source ControllerSpec) v1.ControllerSpec {
 	var v1ControllerSpec v1.ControllerSpec
 	var pString *string
@@ -280,6 +431,15 @@ func (c *GeneratedToHubConverter) v1alpha1ControllerSpecToV1ControllerSpec(sourc
 		}
 	}
 	v1ControllerSpec.PermissionRequests = v1PolicyRuleList
+	var stringList []string
+	if source.WritablePaths != nil {
+		stringList = make([]string, len(source.WritablePaths))
+		for j := 0; j < len(source.WritablePaths); j++ {
+			stringList[j] = source.WritablePaths[j]
+		}
+	}
+	v1ControllerSpec.WritablePaths = stringList
+	v1ControllerSpec.InstallHook = c.pV1alpha1InstallHookToPV1InstallHook(source.InstallHook)
 	return v1ControllerSpec
 }
 func (c *GeneratedToHubConverter) v1alpha1DependencyToV1Dependency(source Dependency) v1.Dependency {
@@ -323,6 +483,15 @@ func (c *GeneratedToHubConverter) v1alpha1DependencyToV1Dependency(source Depend
 	v1Dependency.Version = source.Version
 	return v1Dependency
 }
+func (c *GeneratedToHubConverter) v1alpha1LockedDependencyToV1LockedDependency(source LockedDependency) v1.LockedDependency {
+	var v1LockedDependency v1.LockedDependency
+	v1LockedDependency.APIVersion = source.APIVersion
+	v1LockedDependency.Kind = source.Kind
+	v1LockedDependency.Package = source.Package
+	v1LockedDependency.Version = source.Version
+	v1LockedDependency.Digest = source.Digest
+	return v1LockedDependency
+}
 func (c *GeneratedToHubConverter) v1alpha1MetaSpecToV1MetaSpec(source MetaSpec) v1.MetaSpec {
 	var v1MetaSpec v1.MetaSpec
 	v1MetaSpec.Crossplane = c.pV1alpha1CrossplaneConstraintsToPV1CrossplaneConstraints(source.Crossplane)
@@ -334,11 +503,46 @@ func (c *GeneratedToHubConverter) v1alpha1MetaSpecToV1MetaSpec(source MetaSpec)
 		}
 	}
 	v1MetaSpec.DependsOn = v1DependencyList
+	var v1ConflictList []v1.Conflict
+	if source.Conflicts != nil {
+		v1ConflictList = make([]v1.Conflict, len(source.Conflicts))
+		for j := 0; j < len(source.Conflicts); j++ {
+			v1ConflictList[j] = c.v1alpha1ConflictToV1Conflict(source.Conflicts[j])
+		}
+	}
+	v1MetaSpec.Conflicts = v1ConflictList
+	var v1RequiredAPIList []v1.RequiredAPI
+	if source.RequiredAPIs != nil {
+		v1RequiredAPIList = make([]v1.RequiredAPI, len(source.RequiredAPIs))
+		for k := 0; k < len(source.RequiredAPIs); k++ {
+			v1RequiredAPIList[k] = c.v1alpha1RequiredAPIToV1RequiredAPI(source.RequiredAPIs[k])
+		}
+	}
+	v1MetaSpec.RequiredAPIs = v1RequiredAPIList
 	return v1MetaSpec
 }
 func (c *GeneratedToHubConverter) v1alpha1ProviderSpecToV1ProviderSpec(source ProviderSpec) v1.ProviderSpec {
 	var v1ProviderSpec v1.ProviderSpec
 	v1ProviderSpec.Controller = c.v1alpha1ControllerSpecToV1ControllerSpec(source.Controller)
+	var stringList []string
+	if source.Capabilities != nil {
+		stringList = make([]string, len(source.Capabilities))
+		for i := 0; i < len(source.Capabilities); i++ {
+			stringList[i] = source.Capabilities[i]
+		}
+	}
+	v1ProviderSpec.Capabilities = stringList
 	v1ProviderSpec.MetaSpec = c.v1alpha1MetaSpecToV1MetaSpec(source.MetaSpec)
 	return v1ProviderSpec
 }
+func (c *GeneratedToHubConverter) v1alpha1RequiredAPIToV1RequiredAPI(source RequiredAPI) v1.RequiredAPI {
+	var v1RequiredAPI v1.RequiredAPI
+	v1RequiredAPI.Group = source.Group
+	var pString *string
+	if source.Version != nil {
+		xstring := *source.Version
+		pString = &xstring
+	}
+	v1RequiredAPI.Version = pString
+	return v1RequiredAPI
+}
@@ -55,6 +55,11 @@ func (in *Configuration) DeepCopyObject() runtime.Object {
 func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	*out = *in
 	in.MetaSpec.DeepCopyInto(&out.MetaSpec)
+	if in.DependencyLock != nil {
+		in, out := &in.DependencyLock, &out.DependencyLock
+		*out = make([]LockedDependency, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSpec.
@@ -67,6 +72,21 @@ func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Conflict) DeepCopyInto(out *Conflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conflict.
+func (in *Conflict) DeepCopy() *Conflict {
+	if in == nil {
+		return nil
+	}
+	out := new(Conflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerSpec) DeepCopyInto(out *ControllerSpec) {
 	*out = *in
@@ -82,6 +102,16 @@ func (in *ControllerSpec) DeepCopyInto(out *ControllerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.WritablePaths != nil {
+		in, out := &in.WritablePaths, &out.WritablePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstallHook != nil {
+		in, out := &in.InstallHook, &out.InstallHook
+		*out = new(InstallHook)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerSpec.
@@ -184,6 +214,56 @@ func (in *GeneratedToHubConverter) DeepCopy() *GeneratedToHubConverter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallHook) DeepCopyInto(out *InstallHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallHook.
+func (in *InstallHook) DeepCopy() *InstallHook {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LockedDependency) DeepCopyInto(out *LockedDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockedDependency.
+func (in *LockedDependency) DeepCopy() *LockedDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(LockedDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetaSpec) DeepCopyInto(out *MetaSpec) {
 	*out = *in
@@ -199,6 +279,18 @@ func (in *MetaSpec) DeepCopyInto(out *MetaSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]Conflict, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredAPIs != nil {
+		in, out := &in.RequiredAPIs, &out.RequiredAPIs
+		*out = make([]RequiredAPI, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetaSpec.
@@ -241,6 +333,11 @@ func (in *Provider) DeepCopyObject() runtime.Object {
 func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	*out = *in
 	in.Controller.DeepCopyInto(&out.Controller)
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.MetaSpec.DeepCopyInto(&out.MetaSpec)
 }
 
@@ -253,3 +350,23 @@ func (in *ProviderSpec) DeepCopy() *ProviderSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredAPI) DeepCopyInto(out *RequiredAPI) {
+	*out = *in
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredAPI.
+func (in *RequiredAPI) DeepCopy() *RequiredAPI {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredAPI)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -30,6 +30,11 @@ func (c *GeneratedFromHubConverter) pV1CrossplaneConstraintsToPV1beta1Crossplane
 	}
 	return pV1beta1CrossplaneConstraints
 }
+func (c *GeneratedFromHubConverter) v1ConflictToV1beta1Conflict(source v1.Conflict) Conflict {
+	var v1beta1Conflict Conflict
+	v1beta1Conflict.Package = source.Package
+	return v1beta1Conflict
+}
 func (c *GeneratedFromHubConverter) v1DependencyToV1beta1Dependency(source v1.Dependency) Dependency {
 	var v1beta1Dependency Dependency
 	var pString *string
@@ -80,6 +85,14 @@ func (c *GeneratedFromHubConverter) v1FunctionSpecToV1beta1FunctionSpec(source v
 		pString = &xstring
 	}
 	v1beta1FunctionSpec.Image = pString
+	var stringList []string
+	if source.WritablePaths != nil {
+		stringList = make([]string, len(source.WritablePaths))
+		for j := 0; j < len(source.WritablePaths); j++ {
+			stringList[j] = source.WritablePaths[j]
+		}
+	}
+	v1beta1FunctionSpec.WritablePaths = stringList
 	return v1beta1FunctionSpec
 }
 func (c *GeneratedFromHubConverter) v1MetaSpecToV1beta1MetaSpec(source v1.MetaSpec) MetaSpec {
@@ -93,8 +106,35 @@ func (c *GeneratedFromHubConverter) v1MetaSpecToV1beta1MetaSpec(source v1.MetaSp
 		}
 	}
 	v1beta1MetaSpec.DependsOn = v1beta1DependencyList
+	var v1beta1ConflictList []Conflict
+	if source.Conflicts != nil {
+		v1beta1ConflictList = make([]Conflict, len(source.Conflicts))
+		for j := 0; j < len(source.Conflicts); j++ {
+			v1beta1ConflictList[j] = c.v1ConflictToV1beta1Conflict(source.Conflicts[j])
+		}
+	}
+	v1beta1MetaSpec.Conflicts = v1beta1ConflictList
+	var v1beta1RequiredAPIList []RequiredAPI
+	if source.RequiredAPIs != nil {
+		v1beta1RequiredAPIList = make([]RequiredAPI, len(source.RequiredAPIs))
+		for k := 0; k < len(source.RequiredAPIs); k++ {
+			v1beta1RequiredAPIList[k] = c.v1RequiredAPIToV1beta1RequiredAPI(source.RequiredAPIs[k])
+		}
+	}
+	v1beta1MetaSpec.RequiredAPIs = v1beta1RequiredAPIList
 	return v1beta1MetaSpec
 }
+func (c *GeneratedFromHubConverter) v1RequiredAPIToV1beta1RequiredAPI(source v1.RequiredAPI) RequiredAPI {
+	var v1beta1RequiredAPI RequiredAPI
+	v1beta1RequiredAPI.Group = source.Group
+	var pString *string
+	if source.Version != nil {
+		xstring := *source.Version
+		pString = &xstring
+	}
+	v1beta1RequiredAPI.Version = pString
+	return v1beta1RequiredAPI
+}
 func (c *GeneratedFromHubConverter) v1TypeMetaToV1TypeMeta(source v11.TypeMeta) v11.TypeMeta {
 	var v1TypeMeta v11.TypeMeta
 	v1TypeMeta.Kind = source.Kind
@@ -130,6 +170,11 @@ func (c *GeneratedToHubConverter) v1TypeMetaToV1TypeMeta(source v11.TypeMeta) v1
 	v1TypeMeta.APIVersion = source.APIVersion
 	return v1TypeMeta
 }
+func (c *GeneratedToHubConverter) v1beta1ConflictToV1Conflict(source Conflict) v1.Conflict {
+	var v1Conflict v1.Conflict
+	v1Conflict.Package = source.Package
+	return v1Conflict
+}
 func (c *GeneratedToHubConverter) v1beta1DependencyToV1Dependency(source Dependency) v1.Dependency {
 	var v1Dependency v1.Dependency
 	var pString *string
@@ -180,6 +225,14 @@ func (c *GeneratedToHubConverter) v1beta1FunctionSpecToV1FunctionSpec(source Fun
 		pString = &xstring
 	}
 	v1FunctionSpec.Image = pString
+	var stringList []string
+	if source.WritablePaths != nil {
+		stringList = make([]string, len(source.WritablePaths))
+		for j := 0; j < len(source.WritablePaths); j++ {
+			stringList[j] = source.WritablePaths[j]
+		}
+	}
+	v1FunctionSpec.WritablePaths = stringList
 	return v1FunctionSpec
 }
 func (c *GeneratedToHubConverter) v1beta1MetaSpecToV1MetaSpec(source MetaSpec) v1.MetaSpec {
@@ -193,5 +246,32 @@ func (c *GeneratedToHubConverter) v1beta1MetaSpecToV1MetaSpec(source MetaSpec) v
 		}
 	}
 	v1MetaSpec.DependsOn = v1DependencyList
+	var v1ConflictList []v1.Conflict
+	if source.Conflicts != nil {
+		v1ConflictList = make([]v1.Conflict, len(source.Conflicts))
+		for j := 0; j < len(source.Conflicts); j++ {
+			v1ConflictList[j] = c.v1beta1ConflictToV1Conflict(source.Conflicts[j])
+		}
+	}
+	v1MetaSpec.Conflicts = v1ConflictList
+	var v1RequiredAPIList []v1.RequiredAPI
+	if source.RequiredAPIs != nil {
+		v1RequiredAPIList = make([]v1.RequiredAPI, len(source.RequiredAPIs))
+		for k := 0; k < len(source.RequiredAPIs); k++ {
+			v1RequiredAPIList[k] = c.v1beta1RequiredAPIToV1RequiredAPI(source.RequiredAPIs[k])
+		}
+	}
+	v1MetaSpec.RequiredAPIs = v1RequiredAPIList
 	return v1MetaSpec
 }
+func (c *GeneratedToHubConverter) v1beta1RequiredAPIToV1RequiredAPI(source RequiredAPI) v1.RequiredAPI {
+	var v1RequiredAPI v1.RequiredAPI
+	v1RequiredAPI.Group = source.Group
+	var pString *string
+	if source.Version != nil {
+		xstring := *source.Version
+		pString = &xstring
+	}
+	v1RequiredAPI.Version = pString
+	return v1RequiredAPI
+}
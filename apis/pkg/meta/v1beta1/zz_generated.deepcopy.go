@@ -24,6 +24,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Conflict) DeepCopyInto(out *Conflict) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Conflict.
+func (in *Conflict) DeepCopy() *Conflict {
+	if in == nil {
+		return nil
+	}
+	out := new(Conflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CrossplaneConstraints) DeepCopyInto(out *CrossplaneConstraints) {
 	*out = *in
@@ -119,6 +134,11 @@ func (in *FunctionSpec) DeepCopyInto(out *FunctionSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.WritablePaths != nil {
+		in, out := &in.WritablePaths, &out.WritablePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionSpec.
@@ -176,6 +196,18 @@ func (in *MetaSpec) DeepCopyInto(out *MetaSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]Conflict, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredAPIs != nil {
+		in, out := &in.RequiredAPIs, &out.RequiredAPIs
+		*out = make([]RequiredAPI, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetaSpec.
@@ -187,3 +219,23 @@ func (in *MetaSpec) DeepCopy() *MetaSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredAPI) DeepCopyInto(out *RequiredAPI) {
+	*out = *in
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredAPI.
+func (in *RequiredAPI) DeepCopy() *RequiredAPI {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredAPI)
+	in.DeepCopyInto(out)
+	return out
+}
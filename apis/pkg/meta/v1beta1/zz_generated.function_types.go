@@ -28,6 +28,12 @@ type FunctionSpec struct {
 
 	// Image is the packaged Function image.
 	Image *string `json:"image,omitempty"`
+
+	// WritablePaths are paths the packaged Function needs to write to. They
+	// remain writable when the Function runs with a read-only root
+	// filesystem.
+	// +optional
+	WritablePaths []string `json:"writablePaths,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -36,6 +36,14 @@ const (
 	// A TypeVerified indicates whether a package's signature is verified.
 	// It could be either successful or skipped to be marked as complete.
 	TypeVerified xpv1.ConditionType = "Verified"
+
+	// A TypeUpdateAvailable indicates whether a package's tracked tag
+	// resolves to a different digest than the one the package is pinned to.
+	TypeUpdateAvailable xpv1.ConditionType = "UpdateAvailable"
+
+	// A TypeDigestChanged indicates whether the tag a package's source
+	// references has resolved to a different digest than it used to.
+	TypeDigestChanged xpv1.ConditionType = "DigestChanged"
 )
 
 // Reasons a package is or is not installed.
@@ -47,6 +55,39 @@ const (
 	ReasonUnhealthy            xpv1.ConditionReason = "UnhealthyPackageRevision"
 	ReasonHealthy              xpv1.ConditionReason = "HealthyPackageRevision"
 	ReasonUnknownHealth        xpv1.ConditionReason = "UnknownPackageRevisionHealth"
+
+	// ReasonIncompatibleCrossplaneVersion indicates that a package revision
+	// requires a version of Crossplane other than the one it's installed on.
+	ReasonIncompatibleCrossplaneVersion xpv1.ConditionReason = "IncompatibleCrossplaneVersion"
+
+	// ReasonConflictingPackage indicates that a package revision conflicts
+	// with a package that's already installed.
+	ReasonConflictingPackage xpv1.ConditionReason = "ConflictingPackage"
+
+	// ReasonMissingRequiredAPIs indicates that a package revision requires
+	// Kubernetes APIs that the API server doesn't serve.
+	ReasonMissingRequiredAPIs xpv1.ConditionReason = "MissingRequiredAPIs"
+
+	// ReasonInstallHookRunning indicates that a package revision is waiting
+	// for its install hook to finish running.
+	ReasonInstallHookRunning xpv1.ConditionReason = "InstallHookRunning"
+
+	// ReasonRegistryProxyUnreachable indicates that the package manager
+	// couldn't connect to the HTTP proxy it uses to reach a package's
+	// registry.
+	ReasonRegistryProxyUnreachable xpv1.ConditionReason = "RegistryProxyUnreachable"
+
+	// ReasonRegistryNotAllowed indicates that the package manager won't
+	// install a package because its source isn't from an allowed registry.
+	ReasonRegistryNotAllowed xpv1.ConditionReason = "RegistryNotAllowed"
+
+	// ReasonRolledBack indicates that the package manager has rolled a
+	// package back to a previous revision.
+	ReasonRolledBack xpv1.ConditionReason = "RolledBackPackageRevision"
+
+	// ReasonRollbackRevisionNotFound indicates that the package manager can't
+	// roll a package back because the revision to roll back to doesn't exist.
+	ReasonRollbackRevisionNotFound xpv1.ConditionReason = "RollbackRevisionNotFound"
 )
 
 // Reasons a package's signature is or is not verified.
@@ -66,6 +107,29 @@ const (
 	ReasonVerificationFailed xpv1.ConditionReason = "SignatureVerificationFailed"
 )
 
+// Reasons an update is or is not available for a package.
+const (
+	// ReasonTrackedTagAdvanced indicates that a package's tracked tag
+	// resolves to a different digest than the one the package is pinned to.
+	ReasonTrackedTagAdvanced xpv1.ConditionReason = "TrackedTagAdvanced"
+	// ReasonTrackedTagCurrent indicates that a package's tracked tag resolves
+	// to the digest the package is pinned to.
+	ReasonTrackedTagCurrent xpv1.ConditionReason = "TrackedTagCurrent"
+	// ReasonTrackedTagUnresolved indicates that the package manager couldn't
+	// compare a package's tracked tag to the digest it is pinned to.
+	ReasonTrackedTagUnresolved xpv1.ConditionReason = "TrackedTagUnresolved"
+)
+
+// Reasons a package's resolved digest has or has not changed.
+const (
+	// ReasonResolvedDigestChanged indicates that the tag a package's source
+	// references resolved to a different digest than it previously did.
+	ReasonResolvedDigestChanged xpv1.ConditionReason = "ResolvedDigestChanged"
+	// ReasonSourceChanged indicates that a package's source was changed, so
+	// its previously resolved digest no longer applies.
+	ReasonSourceChanged xpv1.ConditionReason = "SourceChanged"
+)
+
 // AwaitingVerification indicates that the package manager is waiting for
 // a package's signature to be verified.
 func AwaitingVerification() xpv1.Condition {
@@ -88,6 +152,51 @@ func Unpacking() xpv1.Condition {
 	}
 }
 
+// UnpackingProxyUnreachable indicates that the package manager is waiting for
+// a package revision to be unpacked because it couldn't connect to the HTTP
+// proxy it uses to reach the package's registry.
+func UnpackingProxyUnreachable() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRegistryProxyUnreachable,
+	}
+}
+
+// RegistryNotAllowed indicates that the package manager won't install a
+// package because its source isn't from an allowed registry.
+func RegistryNotAllowed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRegistryNotAllowed,
+	}
+}
+
+// RolledBack indicates that the package manager has rolled a package back to,
+// and activated, a previous package revision.
+func RolledBack() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRolledBack,
+	}
+}
+
+// RollbackRevisionNotFound indicates that the package manager can't roll a
+// package back because the revision to roll back to doesn't exist.
+func RollbackRevisionNotFound() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRollbackRevisionNotFound,
+	}
+}
+
 // Inactive indicates that the package manager is waiting for a package
 // revision to be transitioned to an active state.
 func Inactive() xpv1.Condition {
@@ -120,6 +229,63 @@ func Unhealthy() xpv1.Condition {
 	}
 }
 
+// IncompatibleCrossplaneVersion indicates that the current revision is
+// unhealthy because it requires a version of Crossplane other than the one it's
+// installed on.
+func IncompatibleCrossplaneVersion() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonIncompatibleCrossplaneVersion,
+	}
+}
+
+// ConflictingPackage indicates that the current revision is unhealthy because
+// it conflicts with a package that's already installed.
+func ConflictingPackage() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConflictingPackage,
+	}
+}
+
+// MissingRequiredAPIs indicates that the current revision is unhealthy because
+// it requires Kubernetes APIs that the API server doesn't serve.
+func MissingRequiredAPIs() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonMissingRequiredAPIs,
+	}
+}
+
+// InstallHookRunning indicates that the health of the current revision is
+// unknown because it is waiting for its install hook to finish running.
+func InstallHookRunning() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInstallHookRunning,
+	}
+}
+
+// UnhealthyProxyUnreachable indicates that the current revision is unhealthy
+// because the package manager couldn't connect to the HTTP proxy it uses to
+// reach the package's registry.
+func UnhealthyProxyUnreachable() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRegistryProxyUnreachable,
+	}
+}
+
 // Healthy indicates that the current revision is healthy.
 func Healthy() xpv1.Condition {
 	return xpv1.Condition{
@@ -186,3 +352,61 @@ func VerificationIncomplete(err error) xpv1.Condition {
 		Message:            fmt.Sprintf("Error occurred during signature verification %s", err),
 	}
 }
+
+// UpdateAvailable returns a condition indicating that a package's tracked tag
+// resolves to a different digest than the one the package is pinned to.
+func UpdateAvailable(tag, digest string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpdateAvailable,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTrackedTagAdvanced,
+		Message:            fmt.Sprintf("Tracked tag %q resolves to digest %s", tag, digest),
+	}
+}
+
+// NoUpdateAvailable returns a condition indicating that a package's tracked
+// tag resolves to the digest the package is pinned to.
+func NoUpdateAvailable() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpdateAvailable,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTrackedTagCurrent,
+	}
+}
+
+// UnknownUpdateAvailability returns a condition indicating that the package
+// manager couldn't compare a package's tracked tag to the digest it is pinned
+// to.
+func UnknownUpdateAvailability() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpdateAvailable,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTrackedTagUnresolved,
+	}
+}
+
+// DigestChanged returns a condition indicating that the tag a package's
+// source references resolved to a different digest than it previously did.
+func DigestChanged(source, from, to string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDigestChanged,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResolvedDigestChanged,
+		Message:            fmt.Sprintf("Package source %q resolved to digest %s, previously %s", source, to, from),
+	}
+}
+
+// DigestUnchanged returns a condition indicating that a package's source was
+// changed, so any previous change to its resolved digest no longer applies.
+func DigestUnchanged() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDigestChanged,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonSourceChanged,
+	}
+}
@@ -18,6 +18,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -109,11 +110,23 @@ type Package interface { //nolint:interfacebloat // TODO(negz): Could we break t
 	GetCurrentIdentifier() string
 	SetCurrentIdentifier(r string)
 
+	GetResolvedDigest() string
+	SetResolvedDigest(d string)
+
+	GetLastSuccessfulReconcileTime() *metav1.Time
+	SetLastSuccessfulReconcileTime(t *metav1.Time)
+
 	GetSkipDependencyResolution() *bool
 	SetSkipDependencyResolution(skip *bool)
 
 	GetCommonLabels() map[string]string
 	SetCommonLabels(l map[string]string)
+
+	GetTrackTag() *string
+	SetTrackTag(t *string)
+
+	GetRollbackRevision() *string
+	SetRollbackRevision(r *string)
 }
 
 // GetCondition of this Provider.
@@ -231,6 +244,26 @@ func (p *Provider) SetSkipDependencyResolution(b *bool) {
 	p.Spec.SkipDependencyResolution = b
 }
 
+// GetTrackTag of this Provider.
+func (p *Provider) GetTrackTag() *string {
+	return p.Spec.TrackTag
+}
+
+// SetTrackTag of this Provider.
+func (p *Provider) SetTrackTag(t *string) {
+	p.Spec.TrackTag = t
+}
+
+// GetRollbackRevision of this Provider.
+func (p *Provider) GetRollbackRevision() *string {
+	return p.Spec.RollbackRevision
+}
+
+// SetRollbackRevision of this Provider.
+func (p *Provider) SetRollbackRevision(r *string) {
+	p.Spec.RollbackRevision = r
+}
+
 // GetCurrentIdentifier of this Provider.
 func (p *Provider) GetCurrentIdentifier() string {
 	return p.Status.CurrentIdentifier
@@ -241,6 +274,26 @@ func (p *Provider) SetCurrentIdentifier(s string) {
 	p.Status.CurrentIdentifier = s
 }
 
+// GetResolvedDigest of this Provider.
+func (p *Provider) GetResolvedDigest() string {
+	return p.Status.ResolvedDigest
+}
+
+// SetResolvedDigest of this Provider.
+func (p *Provider) SetResolvedDigest(d string) {
+	p.Status.ResolvedDigest = d
+}
+
+// GetLastSuccessfulReconcileTime of this Provider.
+func (p *Provider) GetLastSuccessfulReconcileTime() *metav1.Time {
+	return p.Status.LastSuccessfulReconcileTime
+}
+
+// SetLastSuccessfulReconcileTime of this Provider.
+func (p *Provider) SetLastSuccessfulReconcileTime(t *metav1.Time) {
+	p.Status.LastSuccessfulReconcileTime = t
+}
+
 // GetCommonLabels of this Provider.
 func (p *Provider) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -356,6 +409,26 @@ func (p *Configuration) SetSkipDependencyResolution(b *bool) {
 	p.Spec.SkipDependencyResolution = b
 }
 
+// GetTrackTag of this Configuration.
+func (p *Configuration) GetTrackTag() *string {
+	return p.Spec.TrackTag
+}
+
+// SetTrackTag of this Configuration.
+func (p *Configuration) SetTrackTag(t *string) {
+	p.Spec.TrackTag = t
+}
+
+// GetRollbackRevision of this Configuration.
+func (p *Configuration) GetRollbackRevision() *string {
+	return p.Spec.RollbackRevision
+}
+
+// SetRollbackRevision of this Configuration.
+func (p *Configuration) SetRollbackRevision(r *string) {
+	p.Spec.RollbackRevision = r
+}
+
 // GetCurrentIdentifier of this Configuration.
 func (p *Configuration) GetCurrentIdentifier() string {
 	return p.Status.CurrentIdentifier
@@ -366,6 +439,26 @@ func (p *Configuration) SetCurrentIdentifier(s string) {
 	p.Status.CurrentIdentifier = s
 }
 
+// GetResolvedDigest of this Configuration.
+func (p *Configuration) GetResolvedDigest() string {
+	return p.Status.ResolvedDigest
+}
+
+// SetResolvedDigest of this Configuration.
+func (p *Configuration) SetResolvedDigest(d string) {
+	p.Status.ResolvedDigest = d
+}
+
+// GetLastSuccessfulReconcileTime of this Configuration.
+func (p *Configuration) GetLastSuccessfulReconcileTime() *metav1.Time {
+	return p.Status.LastSuccessfulReconcileTime
+}
+
+// SetLastSuccessfulReconcileTime of this Configuration.
+func (p *Configuration) SetLastSuccessfulReconcileTime(t *metav1.Time) {
+	p.Status.LastSuccessfulReconcileTime = t
+}
+
 // GetCommonLabels of this Configuration.
 func (p *Configuration) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -430,6 +523,15 @@ type PackageRevision interface { //nolint:interfacebloat // TODO(negz): Could we
 	GetDependencyStatus() (found, installed, invalid int64)
 	SetDependencyStatus(found, installed, invalid int64)
 
+	GetLastSuccessfulReconcileTime() *metav1.Time
+	SetLastSuccessfulReconcileTime(t *metav1.Time)
+
+	GetVariant() string
+	SetVariant(v string)
+
+	GetLastInstall() *PackageInstall
+	SetLastInstall(i *PackageInstall)
+
 	GetCommonLabels() map[string]string
 	SetCommonLabels(l map[string]string)
 }
@@ -521,6 +623,36 @@ func (p *ProviderRevision) SetDependencyStatus(found, installed, invalid int64)
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetLastSuccessfulReconcileTime of this ProviderRevision.
+func (p *ProviderRevision) GetLastSuccessfulReconcileTime() *metav1.Time {
+	return p.Status.LastSuccessfulReconcileTime
+}
+
+// SetLastSuccessfulReconcileTime of this ProviderRevision.
+func (p *ProviderRevision) SetLastSuccessfulReconcileTime(t *metav1.Time) {
+	p.Status.LastSuccessfulReconcileTime = t
+}
+
+// GetVariant of this ProviderRevision.
+func (p *ProviderRevision) GetVariant() string {
+	return p.Status.Variant
+}
+
+// SetVariant of this ProviderRevision.
+func (p *ProviderRevision) SetVariant(v string) {
+	p.Status.Variant = v
+}
+
+// GetLastInstall of this ProviderRevision.
+func (p *ProviderRevision) GetLastInstall() *PackageInstall {
+	return p.Status.LastInstall
+}
+
+// SetLastInstall of this ProviderRevision.
+func (p *ProviderRevision) SetLastInstall(i *PackageInstall) {
+	p.Status.LastInstall = i
+}
+
 // GetIgnoreCrossplaneConstraints of this ProviderRevision.
 func (p *ProviderRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -678,6 +810,36 @@ func (p *ConfigurationRevision) SetDependencyStatus(found, installed, invalid in
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetLastSuccessfulReconcileTime of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetLastSuccessfulReconcileTime() *metav1.Time {
+	return p.Status.LastSuccessfulReconcileTime
+}
+
+// SetLastSuccessfulReconcileTime of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetLastSuccessfulReconcileTime(t *metav1.Time) {
+	p.Status.LastSuccessfulReconcileTime = t
+}
+
+// GetVariant of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetVariant() string {
+	return p.Status.Variant
+}
+
+// SetVariant of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetVariant(v string) {
+	p.Status.Variant = v
+}
+
+// GetLastInstall of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetLastInstall() *PackageInstall {
+	return p.Status.LastInstall
+}
+
+// SetLastInstall of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetLastInstall(i *PackageInstall) {
+	p.Status.LastInstall = i
+}
+
 // GetIgnoreCrossplaneConstraints of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -874,6 +1036,26 @@ func (f *Function) SetSkipDependencyResolution(b *bool) {
 	f.Spec.SkipDependencyResolution = b
 }
 
+// GetTrackTag of this Function.
+func (f *Function) GetTrackTag() *string {
+	return f.Spec.TrackTag
+}
+
+// SetTrackTag of this Function.
+func (f *Function) SetTrackTag(t *string) {
+	f.Spec.TrackTag = t
+}
+
+// GetRollbackRevision of this Function.
+func (f *Function) GetRollbackRevision() *string {
+	return f.Spec.RollbackRevision
+}
+
+// SetRollbackRevision of this Function.
+func (f *Function) SetRollbackRevision(r *string) {
+	f.Spec.RollbackRevision = r
+}
+
 // GetCurrentIdentifier of this Function.
 func (f *Function) GetCurrentIdentifier() string {
 	return f.Status.CurrentIdentifier
@@ -884,6 +1066,26 @@ func (f *Function) SetCurrentIdentifier(s string) {
 	f.Status.CurrentIdentifier = s
 }
 
+// GetResolvedDigest of this Function.
+func (f *Function) GetResolvedDigest() string {
+	return f.Status.ResolvedDigest
+}
+
+// SetResolvedDigest of this Function.
+func (f *Function) SetResolvedDigest(d string) {
+	f.Status.ResolvedDigest = d
+}
+
+// GetLastSuccessfulReconcileTime of this Function.
+func (f *Function) GetLastSuccessfulReconcileTime() *metav1.Time {
+	return f.Status.LastSuccessfulReconcileTime
+}
+
+// SetLastSuccessfulReconcileTime of this Function.
+func (f *Function) SetLastSuccessfulReconcileTime(t *metav1.Time) {
+	f.Status.LastSuccessfulReconcileTime = t
+}
+
 // GetCommonLabels of this Function.
 func (f *Function) GetCommonLabels() map[string]string {
 	return f.Spec.CommonLabels
@@ -991,6 +1193,36 @@ func (r *FunctionRevision) SetDependencyStatus(found, installed, invalid int64)
 	r.Status.InvalidDependencies = invalid
 }
 
+// GetLastSuccessfulReconcileTime of this FunctionRevision.
+func (r *FunctionRevision) GetLastSuccessfulReconcileTime() *metav1.Time {
+	return r.Status.LastSuccessfulReconcileTime
+}
+
+// SetLastSuccessfulReconcileTime of this FunctionRevision.
+func (r *FunctionRevision) SetLastSuccessfulReconcileTime(t *metav1.Time) {
+	r.Status.LastSuccessfulReconcileTime = t
+}
+
+// GetVariant of this FunctionRevision.
+func (r *FunctionRevision) GetVariant() string {
+	return r.Status.Variant
+}
+
+// SetVariant of this FunctionRevision.
+func (r *FunctionRevision) SetVariant(v string) {
+	r.Status.Variant = v
+}
+
+// GetLastInstall of this FunctionRevision.
+func (r *FunctionRevision) GetLastInstall() *PackageInstall {
+	return r.Status.LastInstall
+}
+
+// SetLastInstall of this FunctionRevision.
+func (r *FunctionRevision) SetLastInstall(i *PackageInstall) {
+	r.Status.LastInstall = i
+}
+
 // GetIgnoreCrossplaneConstraints of this FunctionRevision.
 func (r *FunctionRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return r.Spec.IgnoreCrossplaneConstraints
@@ -16,7 +16,10 @@ limitations under the License.
 
 package v1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // RevisionActivationPolicy indicates how a package should activate its
 // revisions.
@@ -27,6 +30,13 @@ type PackageSpec struct {
 	// Package is the name of the package that is being requested.
 	Package string `json:"package"`
 
+	// TrackTag is a tag of the package's repository to track for updates.
+	// When the package is pinned to a digest the package manager reports an
+	// UpdateAvailable condition if this tag resolves to a different digest.
+	// The package manager never upgrades a package to the tracked tag.
+	// +optional
+	TrackTag *string `json:"trackTag,omitempty"`
+
 	// RevisionActivationPolicy specifies how the package controller should
 	// update from one revision to the next. Options are Automatic or Manual.
 	// Default is Automatic.
@@ -41,6 +51,17 @@ type PackageSpec struct {
 	// +kubebuilder:default=1
 	RevisionHistoryLimit *int64 `json:"revisionHistoryLimit,omitempty"`
 
+	// RollbackRevision is the name of a previous revision of this package to
+	// roll back to. While it's set the package manager activates the named
+	// revision and deactivates all other revisions, regardless of the
+	// revision activation policy. It doesn't create a revision for the
+	// requested package or garbage collect old revisions while the package is
+	// rolled back, so the named revision can't be garbage collected due to
+	// the revision history limit. Unset it to resume installing the requested
+	// package.
+	// +optional
+	RollbackRevision *string `json:"rollbackRevision,omitempty"`
+
 	// PackagePullSecrets are named secrets in the same namespace that can be used
 	// to fetch packages from private registries.
 	// +optional
@@ -89,4 +110,15 @@ type PackageStatus struct {
 	// will cause the package manager to check that the current revision is
 	// correct for the given package source.
 	CurrentIdentifier string `json:"currentIdentifier,omitempty"`
+
+	// ResolvedDigest is the digest the package source most recently resolved
+	// to. It's only recorded for package sources that reference a tag. The
+	// package manager raises the DigestChanged condition if a tag resolves to
+	// a different digest than the one recorded here.
+	ResolvedDigest string `json:"resolvedDigest,omitempty"`
+
+	// LastSuccessfulReconcileTime is when the package manager last
+	// successfully reconciled this package. It's refreshed at most once per
+	// minute, so it may lag behind the most recent successful reconcile.
+	LastSuccessfulReconcileTime *metav1.Time `json:"lastSuccessfulReconcileTime,omitempty"`
 }
@@ -19,6 +19,7 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
@@ -99,6 +100,81 @@ type PackageRevisionStatus struct {
 	// controller needs these permissions to run. The RBAC manager is
 	// responsible for granting them.
 	PermissionRequests []rbacv1.PolicyRule `json:"permissionRequests,omitempty"`
+
+	// Capabilities the package declares in its metadata.
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// LastSuccessfulReconcileTime is when the package manager last
+	// successfully reconciled this package revision. It's refreshed at most
+	// once per minute, so it may lag behind the most recent successful
+	// reconcile.
+	LastSuccessfulReconcileTime *metav1.Time `json:"lastSuccessfulReconcileTime,omitempty"`
+
+	// Variant of the package's base layer that the package manager unpacked,
+	// if the package offers variants and one matched the package manager's
+	// preferred variant. Empty if the package manager unpacked the package's
+	// default base layer.
+	// +optional
+	Variant string `json:"variant,omitempty"`
+
+	// LastInstall records how long the package manager took to fetch and
+	// install this package revision the last time it did so, broken down by
+	// step. It's updated each time the package manager finishes installing
+	// the package revision after fetching it from its registry.
+	// +optional
+	LastInstall *PackageInstall `json:"lastInstall,omitempty"`
+}
+
+// A PackageInstall records how long the package manager took to install a
+// package revision.
+type PackageInstall struct {
+	// CompletionTime is when the install completed.
+	CompletionTime metav1.Time `json:"completionTime"`
+
+	// Duration of the entire install.
+	Duration metav1.Duration `json:"duration"`
+
+	// Steps of the install, in the order the package manager started them.
+	// +optional
+	Steps []PackageInstallStep `json:"steps,omitempty"`
+}
+
+// A PackageInstallStepName identifies a step of a package install.
+type PackageInstallStepName string
+
+// Package install steps.
+const (
+	// PackageInstallStepFetch is the step where the package manager fetches
+	// the package's image from its registry.
+	PackageInstallStepFetch PackageInstallStepName = "Fetch"
+
+	// PackageInstallStepUnpack is the step where the package manager unpacks
+	// and parses the package's contents.
+	PackageInstallStepUnpack PackageInstallStepName = "Unpack"
+
+	// PackageInstallStepResolveDependencies is the step where the package
+	// manager resolves the package's dependencies.
+	PackageInstallStepResolveDependencies PackageInstallStepName = "ResolveDependencies"
+
+	// PackageInstallStepEstablishObjects is the step where the package
+	// manager establishes the package's objects, including waiting for its
+	// CRDs to become established.
+	PackageInstallStepEstablishObjects PackageInstallStepName = "EstablishObjects"
+
+	// PackageInstallStepRuntime is the step where the package manager
+	// configures the package's runtime, for example its Deployment.
+	PackageInstallStepRuntime PackageInstallStepName = "Runtime"
+)
+
+// A PackageInstallStep records how long a step of a package install took.
+type PackageInstallStep struct {
+	// Name of the step.
+	// +kubebuilder:validation:Enum=Fetch;Unpack;ResolveDependencies;EstablishObjects;Runtime
+	Name PackageInstallStepName `json:"name"`
+
+	// Duration of the step.
+	Duration metav1.Duration `json:"duration"`
 }
 
 // A ControllerReference references the controller (e.g. Deployment), if any,
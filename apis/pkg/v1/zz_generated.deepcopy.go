@@ -165,7 +165,7 @@ func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
 func (in *ConfigurationStatus) DeepCopyInto(out *ConfigurationStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationStatus.
@@ -380,7 +380,7 @@ func (in *FunctionSpec) DeepCopy() *FunctionSpec {
 func (in *FunctionStatus) DeepCopyInto(out *FunctionStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStatus.
@@ -393,6 +393,44 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageInstall) DeepCopyInto(out *PackageInstall) {
+	*out = *in
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	out.Duration = in.Duration
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PackageInstallStep, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageInstall.
+func (in *PackageInstall) DeepCopy() *PackageInstall {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageInstall)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageInstallStep) DeepCopyInto(out *PackageInstallStep) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageInstallStep.
+func (in *PackageInstallStep) DeepCopy() *PackageInstallStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageInstallStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageRevisionRuntimeSpec) DeepCopyInto(out *PackageRevisionRuntimeSpec) {
 	*out = *in
@@ -477,6 +515,20 @@ func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSuccessfulReconcileTime != nil {
+		in, out := &in.LastSuccessfulReconcileTime, &out.LastSuccessfulReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastInstall != nil {
+		in, out := &in.LastInstall, &out.LastInstall
+		*out = new(PackageInstall)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionStatus.
@@ -517,6 +569,11 @@ func (in *PackageRuntimeSpec) DeepCopy() *PackageRuntimeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 	*out = *in
+	if in.TrackTag != nil {
+		in, out := &in.TrackTag, &out.TrackTag
+		*out = new(string)
+		**out = **in
+	}
 	if in.RevisionActivationPolicy != nil {
 		in, out := &in.RevisionActivationPolicy, &out.RevisionActivationPolicy
 		*out = new(RevisionActivationPolicy)
@@ -527,6 +584,11 @@ func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.RollbackRevision != nil {
+		in, out := &in.RollbackRevision, &out.RollbackRevision
+		*out = new(string)
+		**out = **in
+	}
 	if in.PackagePullSecrets != nil {
 		in, out := &in.PackagePullSecrets, &out.PackagePullSecrets
 		*out = make([]corev1.LocalObjectReference, len(*in))
@@ -569,6 +631,10 @@ func (in *PackageSpec) DeepCopy() *PackageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageStatus) DeepCopyInto(out *PackageStatus) {
 	*out = *in
+	if in.LastSuccessfulReconcileTime != nil {
+		in, out := &in.LastSuccessfulReconcileTime, &out.LastSuccessfulReconcileTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageStatus.
@@ -737,7 +803,7 @@ func (in *ProviderSpec) DeepCopy() *ProviderSpec {
 func (in *ProviderStatus) DeepCopyInto(out *ProviderStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderStatus.
@@ -12,6 +12,9 @@ import (
 const (
 	// TypeResolved is the type for the Resolved condition.
 	TypeResolved xpv1.ConditionType = "Resolved"
+
+	// TypeMirrored is the type for the Mirrored condition.
+	TypeMirrored xpv1.ConditionType = "Mirrored"
 )
 
 // Reasons dependency resolution can fail.
@@ -20,6 +23,12 @@ const (
 	ReasonSucceeded xpv1.ConditionReason = "DependencyResolutionSucceeded"
 )
 
+// Reasons dependency mirroring can fail.
+const (
+	ReasonMirrorFailed    xpv1.ConditionReason = "DependencyMirrorFailed"
+	ReasonMirrorSucceeded xpv1.ConditionReason = "DependencyMirrorSucceeded"
+)
+
 // ResolutionFailed indicates that the dependency resolution process failed.
 func ResolutionFailed(err error) xpv1.Condition {
 	return xpv1.Condition{
@@ -40,3 +49,27 @@ func ResolutionSucceeded() xpv1.Condition {
 		Reason:             ReasonSucceeded,
 	}
 }
+
+// MirrorFailed indicates that a dependency couldn't be copied to the mirror
+// registry.
+func MirrorFailed(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeMirrored,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonMirrorFailed,
+		Message:            fmt.Sprintf("Error occurred during dependency mirroring %s", err),
+	}
+}
+
+// MirrorSucceeded indicates that a dependency was copied to the mirror
+// registry.
+func MirrorSucceeded(pkg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeMirrored,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonMirrorSucceeded,
+		Message:            fmt.Sprintf("Mirrored dependency %s", pkg),
+	}
+}
@@ -79,8 +79,32 @@ type DeploymentRuntimeConfigSpec struct {
 	// +optional
 	ServiceTemplate *ServiceTemplate `json:"serviceTemplate,omitempty"`
 	// ServiceAccountTemplate is the template for the ServiceAccount object.
+	// Set metadata.name to give the ServiceAccount the package manager
+	// creates a fixed name. To use an existing ServiceAccount instead, set
+	// deploymentTemplate.spec.template.spec.serviceAccountName. The package
+	// manager won't create or modify an existing ServiceAccount, and won't
+	// deploy the package runtime until it exists.
 	// +optional
 	ServiceAccountTemplate *ServiceAccountTemplate `json:"serviceAccountTemplate,omitempty"`
+	// AutomountServiceAccountToken controls whether the package runtime pod
+	// automounts its ServiceAccount's API token. When set it overrides any
+	// value in the DeploymentTemplate. When unset it defaults to true, unless
+	// the DeploymentTemplate sets it, because provider and function controllers
+	// use the token to talk to the API server. Setting it to false will break
+	// any controller that needs API access, unless it mounts a token some other
+	// way.
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+	// ReadOnlyRootFilesystem runs the package runtime container with a
+	// read-only root filesystem. When the root filesystem is read-only an
+	// emptyDir volume is mounted at /tmp, at each of the WritablePaths, and at
+	// each writable path the package declares.
+	// +optional
+	ReadOnlyRootFilesystem *bool `json:"readOnlyRootFilesystem,omitempty"`
+	// WritablePaths are paths in the package runtime container that must
+	// remain writable when its root filesystem is read-only.
+	// +optional
+	WritablePaths []string `json:"writablePaths,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -96,11 +96,44 @@ type RegistryAuthentication struct {
 	PullSecretRef corev1.LocalObjectReference `json:"pullSecretRef"`
 }
 
+// RegistryProxyMode is how the package manager connects to a registry.
+type RegistryProxyMode string
+
+const (
+	// RegistryProxyModeDirect connects to the registry directly, ignoring
+	// any proxy configured using environment variables.
+	RegistryProxyModeDirect RegistryProxyMode = "Direct"
+
+	// RegistryProxyModeHTTP connects to the registry via an HTTP proxy.
+	RegistryProxyModeHTTP RegistryProxyMode = "HTTP"
+)
+
+// RegistryProxy configures how the package manager connects to a registry.
+// +kubebuilder:validation:XValidation:rule="self.mode != 'HTTP' || has(self.url)",message="url is required when mode is HTTP"
+type RegistryProxy struct {
+	// Mode is how the package manager connects to the registry. Direct
+	// connects to the registry without a proxy. HTTP connects via the proxy
+	// at the supplied URL.
+	// +kubebuilder:validation:Enum=Direct;HTTP
+	Mode RegistryProxyMode `json:"mode"`
+
+	// URL of the HTTP proxy, for example http://proxy.example.org:3128. Only
+	// used when mode is HTTP.
+	// +optional
+	URL *string `json:"url,omitempty"`
+}
+
 // RegistryConfig contains the configuration for the registry.
 type RegistryConfig struct {
 	// Authentication is the authentication information for the registry.
 	// +optional
 	Authentication *RegistryAuthentication `json:"authentication,omitempty"`
+
+	// Proxy configures how the package manager connects to the registry. If
+	// omitted the package manager uses the proxy configured using the
+	// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
+	// +optional
+	Proxy *RegistryProxy `json:"proxy,omitempty"`
 }
 
 // ImageVerification contains the configuration for verifying the image.
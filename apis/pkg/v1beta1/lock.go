@@ -68,6 +68,10 @@ type LockPackage struct {
 	// the dependencies will dictate the order in which they are resolved.
 	Dependencies []Dependency `json:"dependencies"`
 
+	// Conflicts are the sources of the packages this package conflicts with.
+	// +optional
+	Conflicts []string `json:"conflicts,omitempty"`
+
 	// ParentConstraints is a list of constraints that are passed down from the parent package to the dependency.
 	ParentConstraints []string `json:"-"` // NOTE(ezgidemirel): We don't want to expose this field in the API.
 }
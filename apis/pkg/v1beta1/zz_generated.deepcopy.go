@@ -236,6 +236,21 @@ func (in *DeploymentRuntimeConfigSpec) DeepCopyInto(out *DeploymentRuntimeConfig
 		*out = new(ServiceAccountTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AutomountServiceAccountToken != nil {
+		in, out := &in.AutomountServiceAccountToken, &out.AutomountServiceAccountToken
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WritablePaths != nil {
+		in, out := &in.WritablePaths, &out.WritablePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentRuntimeConfigSpec.
@@ -445,7 +460,7 @@ func (in *FunctionSpec) DeepCopy() *FunctionSpec {
 func (in *FunctionStatus) DeepCopyInto(out *FunctionStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
-	out.PackageStatus = in.PackageStatus
+	in.PackageStatus.DeepCopyInto(&out.PackageStatus)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionStatus.
@@ -743,6 +758,11 @@ func (in *LockPackage) DeepCopyInto(out *LockPackage) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ParentConstraints != nil {
 		in, out := &in.ParentConstraints, &out.ParentConstraints
 		*out = make([]string, len(*in))
@@ -810,6 +830,44 @@ func (in *ObjectMeta) DeepCopy() *ObjectMeta {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageInstall) DeepCopyInto(out *PackageInstall) {
+	*out = *in
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+	out.Duration = in.Duration
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PackageInstallStep, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageInstall.
+func (in *PackageInstall) DeepCopy() *PackageInstall {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageInstall)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageInstallStep) DeepCopyInto(out *PackageInstallStep) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageInstallStep.
+func (in *PackageInstallStep) DeepCopy() *PackageInstallStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageInstallStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageRevisionRuntimeSpec) DeepCopyInto(out *PackageRevisionRuntimeSpec) {
 	*out = *in
@@ -894,6 +952,20 @@ func (in *PackageRevisionStatus) DeepCopyInto(out *PackageRevisionStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSuccessfulReconcileTime != nil {
+		in, out := &in.LastSuccessfulReconcileTime, &out.LastSuccessfulReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastInstall != nil {
+		in, out := &in.LastInstall, &out.LastInstall
+		*out = new(PackageInstall)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRevisionStatus.
@@ -934,6 +1006,11 @@ func (in *PackageRuntimeSpec) DeepCopy() *PackageRuntimeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 	*out = *in
+	if in.TrackTag != nil {
+		in, out := &in.TrackTag, &out.TrackTag
+		*out = new(string)
+		**out = **in
+	}
 	if in.RevisionActivationPolicy != nil {
 		in, out := &in.RevisionActivationPolicy, &out.RevisionActivationPolicy
 		*out = new(RevisionActivationPolicy)
@@ -944,6 +1021,11 @@ func (in *PackageSpec) DeepCopyInto(out *PackageSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.RollbackRevision != nil {
+		in, out := &in.RollbackRevision, &out.RollbackRevision
+		*out = new(string)
+		**out = **in
+	}
 	if in.PackagePullSecrets != nil {
 		in, out := &in.PackagePullSecrets, &out.PackagePullSecrets
 		*out = make([]corev1.LocalObjectReference, len(*in))
@@ -986,6 +1068,10 @@ func (in *PackageSpec) DeepCopy() *PackageSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageStatus) DeepCopyInto(out *PackageStatus) {
 	*out = *in
+	if in.LastSuccessfulReconcileTime != nil {
+		in, out := &in.LastSuccessfulReconcileTime, &out.LastSuccessfulReconcileTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageStatus.
@@ -1022,6 +1108,11 @@ func (in *RegistryConfig) DeepCopyInto(out *RegistryConfig) {
 		*out = new(RegistryAuthentication)
 		**out = **in
 	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(RegistryProxy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryConfig.
@@ -1034,6 +1125,26 @@ func (in *RegistryConfig) DeepCopy() *RegistryConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryProxy) DeepCopyInto(out *RegistryProxy) {
+	*out = *in
+	if in.URL != nil {
+		in, out := &in.URL, &out.URL
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryProxy.
+func (in *RegistryProxy) DeepCopy() *RegistryProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuntimeConfigReference) DeepCopyInto(out *RuntimeConfigReference) {
 	*out = *in
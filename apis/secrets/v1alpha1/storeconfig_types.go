@@ -25,6 +25,83 @@ import (
 // A StoreConfigSpec defines the desired state of a StoreConfig.
 type StoreConfigSpec struct {
 	xpv1.SecretStoreConfig `json:",inline"`
+
+	// KeyTransforms are applied, in order, to the key of each connection
+	// detail Crossplane writes to this store. They're reversed, in reverse
+	// order, when Crossplane reads connection details from this store. Only
+	// connection details written by Crossplane are transformed, not those
+	// written by providers.
+	// +optional
+	KeyTransforms []KeyTransform `json:"keyTransforms,omitempty"`
+}
+
+// A KeyTransformType is a type of connection detail key transform.
+type KeyTransformType string
+
+// Supported key transform types.
+const (
+	// KeyTransformTypePrefix prepends a prefix to each key. Reading strips the
+	// prefix from keys that have it.
+	KeyTransformTypePrefix KeyTransformType = "Prefix"
+
+	// KeyTransformTypeSuffix appends a suffix to each key. Reading strips the
+	// suffix from keys that have it.
+	KeyTransformTypeSuffix KeyTransformType = "Suffix"
+
+	// KeyTransformTypeCase changes the case of each key. It can't be
+	// reversed, so reading returns keys in the transformed case.
+	KeyTransformTypeCase KeyTransformType = "Case"
+
+	// KeyTransformTypeReplace replaces each occurrence of a string in each
+	// key. Reading replaces each occurrence of the replacement with the
+	// original string. This is only an exact round trip if the original keys
+	// don't contain the replacement.
+	KeyTransformTypeReplace KeyTransformType = "Replace"
+)
+
+// A KeyCase is the case a Case key transform converts keys to.
+type KeyCase string
+
+// Supported key cases.
+const (
+	KeyCaseLower KeyCase = "Lower"
+	KeyCaseUpper KeyCase = "Upper"
+)
+
+// A KeyTransform transforms the key of a connection detail.
+type KeyTransform struct {
+	// Type of the key transform.
+	// +kubebuilder:validation:Enum=Prefix;Suffix;Case;Replace
+	Type KeyTransformType `json:"type"`
+
+	// Prefix to prepend to each key. Required if type is Prefix.
+	// +optional
+	Prefix *string `json:"prefix,omitempty"`
+
+	// Suffix to append to each key. Required if type is Suffix.
+	// +optional
+	Suffix *string `json:"suffix,omitempty"`
+
+	// Case to convert each key to. Required if type is Case.
+	// +optional
+	// +kubebuilder:validation:Enum=Lower;Upper
+	Case *KeyCase `json:"case,omitempty"`
+
+	// Replace configures a Replace key transform. Required if type is
+	// Replace.
+	// +optional
+	Replace *ReplaceKeyTransform `json:"replace,omitempty"`
+}
+
+// A ReplaceKeyTransform replaces each occurrence of a string in a key.
+type ReplaceKeyTransform struct {
+	// Search is the string to replace.
+	// +kubebuilder:validation:MinLength=1
+	Search string `json:"search"`
+
+	// Replace is the string to replace it with. Keys transformed with an
+	// empty replacement can't be reversed when read.
+	Replace string `json:"replace"`
 }
 
 // +kubebuilder:object:root=true
@@ -55,3 +132,8 @@ type StoreConfigList struct {
 func (in *StoreConfig) GetStoreConfig() xpv1.SecretStoreConfig {
 	return in.Spec.SecretStoreConfig
 }
+
+// GetKeyTransforms returns the store's key transforms.
+func (in *StoreConfig) GetKeyTransforms() []KeyTransform {
+	return in.Spec.KeyTransforms
+}
@@ -24,6 +24,56 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyTransform) DeepCopyInto(out *KeyTransform) {
+	*out = *in
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(string)
+		**out = **in
+	}
+	if in.Suffix != nil {
+		in, out := &in.Suffix, &out.Suffix
+		*out = new(string)
+		**out = **in
+	}
+	if in.Case != nil {
+		in, out := &in.Case, &out.Case
+		*out = new(KeyCase)
+		**out = **in
+	}
+	if in.Replace != nil {
+		in, out := &in.Replace, &out.Replace
+		*out = new(ReplaceKeyTransform)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyTransform.
+func (in *KeyTransform) DeepCopy() *KeyTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplaceKeyTransform) DeepCopyInto(out *ReplaceKeyTransform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplaceKeyTransform.
+func (in *ReplaceKeyTransform) DeepCopy() *ReplaceKeyTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplaceKeyTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreConfig) DeepCopyInto(out *StoreConfig) {
 	*out = *in
@@ -86,6 +136,13 @@ func (in *StoreConfigList) DeepCopyObject() runtime.Object {
 func (in *StoreConfigSpec) DeepCopyInto(out *StoreConfigSpec) {
 	*out = *in
 	in.SecretStoreConfig.DeepCopyInto(&out.SecretStoreConfig)
+	if in.KeyTransforms != nil {
+		in, out := &in.KeyTransforms, &out.KeyTransforms
+		*out = make([]KeyTransform, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoreConfigSpec.
@@ -0,0 +1,268 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff contains the diff command.
+package diff
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/pkg"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/cmd/crank/render"
+)
+
+const (
+	errKubeConfig     = "failed to get kubeconfig"
+	errInitKubeClient = "cannot init kubeclient"
+)
+
+// Cmd previews how a Composition would change existing composite resources.
+type Cmd struct {
+	// Arguments.
+	Composition string `arg:"" help:"A YAML file specifying the updated Composition. Must be mode: Pipeline." type:"existingfile"`
+
+	// Flags. Keep them in alphabetical order.
+	Context             string        `default:""   help:"Kubernetes context."                                                                                             name:"context" short:"c"`
+	FunctionCredentials string        `help:"A YAML file or directory of YAML files specifying credentials to use for Functions. Defaults to the Secrets referenced by the Composition." placeholder:"PATH" type:"path"`
+	Functions           string        `help:"A YAML file or directory of YAML files specifying the Composition Functions to use. Defaults to the Functions installed in the cluster."     placeholder:"PATH" type:"path"`
+	Timeout             time.Duration `default:"5m" help:"How long to run before timing out."`
+
+	fs afero.Fs
+}
+
+// Help returns help message for the diff command.
+func (c *Cmd) Help() string {
+	return `
+This command previews how an updated Composition would change the composite
+resources (XRs) that use it. It finds every XR in the cluster that uses a
+Composition with the same name, renders it using the updated Composition, and
+shows how each of its composed resources would change. It prints a summary of
+how many XRs would be affected.
+
+This command never changes the cluster. It renders XRs locally, the same way
+'crossplane render' does, so Composition Functions are pulled and run using
+Docker by default.
+
+Only the fields the updated Composition specifies are compared to each existing
+composed resource. Of their metadata, only labels and annotations are compared.
+
+Examples:
+
+  # Preview how an updated Composition would change existing XRs.
+  crossplane beta diff composition.yaml
+
+  # Preview using Functions from a file, rather than those installed in the
+  # cluster.
+  crossplane beta diff composition.yaml --functions=functions.yaml
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *Cmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run runs the diff command.
+func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	comp, err := render.LoadComposition(c.fs, c.Composition)
+	if err != nil {
+		return errors.Wrapf(err, "cannot load Composition from %q", c.Composition)
+	}
+	if m := comp.Spec.Mode; m == nil || *m != v1.CompositionModePipeline {
+		return errors.Errorf("diff only supports Composition Function pipelines: Composition %q must use spec.mode: Pipeline", comp.GetName())
+	}
+
+	kube, err := c.newClient()
+	if err != nil {
+		return err
+	}
+
+	fns, err := c.loadFunctions(ctx, kube)
+	if err != nil {
+		return err
+	}
+
+	creds, err := c.loadCredentials(ctx, kube, comp)
+	if err != nil {
+		return err
+	}
+
+	xrs, err := listCompositeResources(ctx, kube, comp)
+	if err != nil {
+		return err
+	}
+
+	affected := 0
+	for i := range xrs {
+		xr := &xrs[i]
+
+		observed, err := getComposedResources(ctx, kube, xr)
+		if err != nil {
+			return errors.Wrapf(err, "cannot get composed resources of %q", xr.GetName())
+		}
+
+		out, err := render.Render(ctx, logger, render.Inputs{
+			CompositeResource:   xr.DeepCopy(),
+			Composition:         comp,
+			Functions:           fns,
+			FunctionCredentials: creds,
+			ObservedResources:   observed,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "cannot render %q", xr.GetName())
+		}
+
+		diffs, err := DiffComposedResources(out.ComposedResources, observed)
+		if err != nil {
+			return errors.Wrapf(err, "cannot diff %q", xr.GetName())
+		}
+		if len(diffs) == 0 {
+			continue
+		}
+
+		affected++
+		if err := PrintDiffs(k.Stdout, fmt.Sprintf("%s/%s", xr.GetKind(), xr.GetName()), diffs); err != nil {
+			return errors.Wrap(err, "cannot print diff")
+		}
+	}
+
+	_, err = fmt.Fprintf(k.Stdout, "\n%d of %d composite resources would change.\n", affected, len(xrs))
+	return errors.Wrap(err, "cannot print summary")
+}
+
+func (c *Cmd) newClient() (client.Client, error) {
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: c.Context},
+	).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, errKubeConfig)
+	}
+
+	s := scheme.Scheme
+	_ = pkg.AddToScheme(s)
+
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	return kube, errors.Wrap(err, errInitKubeClient)
+}
+
+// loadFunctions loads Functions from a file if one was supplied, or from the
+// cluster if not.
+func (c *Cmd) loadFunctions(ctx context.Context, kube client.Reader) ([]pkgv1.Function, error) {
+	if c.Functions != "" {
+		fns, err := render.LoadFunctions(c.fs, c.Functions)
+		return fns, errors.Wrapf(err, "cannot load functions from %q", c.Functions)
+	}
+
+	l := &pkgv1.FunctionList{}
+	if err := kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, "cannot list functions")
+	}
+	return l.Items, nil
+}
+
+// loadCredentials loads Function credentials from a file if one was supplied,
+// or gets the Secrets the Composition references from the cluster if not.
+func (c *Cmd) loadCredentials(ctx context.Context, kube client.Reader, comp *v1.Composition) ([]corev1.Secret, error) {
+	if c.FunctionCredentials != "" {
+		creds, err := render.LoadCredentials(c.fs, c.FunctionCredentials)
+		return creds, errors.Wrapf(err, "cannot load secrets from %q", c.FunctionCredentials)
+	}
+
+	creds := []corev1.Secret{}
+	for _, s := range comp.Spec.Pipeline {
+		for _, cs := range s.Credentials {
+			if cs.Source != v1.FunctionCredentialsSourceSecret || cs.SecretRef == nil {
+				continue
+			}
+			sec := &corev1.Secret{}
+			if err := kube.Get(ctx, client.ObjectKey{Namespace: cs.SecretRef.Namespace, Name: cs.SecretRef.Name}, sec); err != nil {
+				return nil, errors.Wrapf(err, "cannot get credentials %q for pipeline step %q", cs.Name, s.Step)
+			}
+			creds = append(creds, *sec)
+		}
+	}
+	return creds, nil
+}
+
+// listCompositeResources lists the XRs that use the supplied Composition.
+func listCompositeResources(ctx context.Context, kube client.Reader, comp *v1.Composition) ([]composite.Unstructured, error) {
+	gv, err := schema.ParseGroupVersion(comp.Spec.CompositeTypeRef.APIVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse Composition's compositeTypeRef.apiVersion")
+	}
+
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gv.WithKind(comp.Spec.CompositeTypeRef.Kind + "List"))
+	if err := kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, "cannot list composite resources")
+	}
+
+	xrs := make([]composite.Unstructured, 0, len(l.Items))
+	for _, u := range l.Items {
+		xr := composite.Unstructured{Unstructured: u}
+		if ref := xr.GetCompositionReference(); ref == nil || ref.Name != comp.GetName() {
+			continue
+		}
+		xrs = append(xrs, xr)
+	}
+	return xrs, nil
+}
+
+// getComposedResources gets the composed resources referenced by the supplied
+// XR. Composed resources that don't exist are ignored.
+func getComposedResources(ctx context.Context, kube client.Reader, xr *composite.Unstructured) ([]composed.Unstructured, error) {
+	cds := make([]composed.Unstructured, 0)
+	for _, ref := range xr.GetResourceReferences() {
+		// Anonymous resource templates may have empty references.
+		if ref.Name == "" {
+			continue
+		}
+		cd := composed.New(composed.FromReference(ref))
+		err := kube.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, cd)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get composed resource %q", ref.Name)
+		}
+		cds = append(cds, *cd)
+	}
+	return cds, nil
+}
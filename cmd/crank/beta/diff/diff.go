@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	"github.com/crossplane/crossplane/cmd/crank/render"
+)
+
+// A ChangeType describes how a composed resource would change.
+type ChangeType string
+
+// Change types.
+const (
+	ChangeTypeAdded    ChangeType = "+"
+	ChangeTypeRemoved  ChangeType = "-"
+	ChangeTypeModified ChangeType = "~"
+)
+
+// A ResourceDiff describes how a composed resource would change.
+type ResourceDiff struct {
+	// ResourceName is the name of the composed resource within the
+	// Composition, i.e. its composition-resource-name annotation.
+	ResourceName string
+
+	// Kind of the composed resource.
+	Kind string
+
+	// Name of the composed resource, if it exists.
+	Name string
+
+	// Type of change.
+	Type ChangeType
+
+	// Diff is a line diff between the current and desired state of a
+	// modified composed resource.
+	Diff string
+}
+
+// DiffComposedResources returns the difference between the desired composed
+// resources rendered by a Composition and the observed composed resources that
+// exist today. Only the fields of a modified resource that the Composition
+// specifies are compared. Of its metadata, only labels and annotations are
+// compared. Diffs are sorted by resource name.
+func DiffComposedResources(desired, observed []composed.Unstructured) ([]ResourceDiff, error) {
+	obs := make(map[string]composed.Unstructured, len(observed))
+	for _, cd := range observed {
+		obs[cd.GetAnnotations()[render.AnnotationKeyCompositionResourceName]] = cd
+	}
+
+	diffs := make([]ResourceDiff, 0)
+	seen := make(map[string]bool, len(desired))
+	for _, cd := range desired {
+		name := cd.GetAnnotations()[render.AnnotationKeyCompositionResourceName]
+		seen[name] = true
+
+		o, ok := obs[name]
+		if !ok {
+			diffs = append(diffs, ResourceDiff{ResourceName: name, Kind: cd.GetKind(), Type: ChangeTypeAdded})
+			continue
+		}
+
+		want := comparableFields(cd.UnstructuredContent())
+		got := prune(o.UnstructuredContent(), want)
+
+		d, err := lineDiff(got, want)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot diff composed resource %q", name)
+		}
+		if d == "" {
+			continue
+		}
+		diffs = append(diffs, ResourceDiff{ResourceName: name, Kind: cd.GetKind(), Name: o.GetName(), Type: ChangeTypeModified, Diff: d})
+	}
+
+	for name, cd := range obs {
+		if seen[name] {
+			continue
+		}
+		diffs = append(diffs, ResourceDiff{ResourceName: name, Kind: cd.GetKind(), Name: cd.GetName(), Type: ChangeTypeRemoved})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].ResourceName < diffs[j].ResourceName })
+	return diffs, nil
+}
+
+// comparableFields returns the parts of a desired composed resource that are
+// worth comparing to an observed composed resource. Other metadata, like owner
+// references, is derived from the XR rather than the Composition. Status is
+// never applied.
+func comparableFields(desired map[string]any) map[string]any {
+	out := make(map[string]any, len(desired))
+	for k, v := range desired {
+		switch k {
+		case "status":
+			continue
+		case "metadata":
+			m, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			md := map[string]any{}
+			for _, f := range []string{"labels", "annotations"} {
+				if fv, ok := m[f]; ok {
+					md[f] = fv
+				}
+			}
+			out[k] = md
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// prune returns the fields of the observed object that are also present in the
+// desired object. Objects are pruned recursively. Arrays and scalar values are
+// returned as is.
+func prune(observed, desired map[string]any) map[string]any {
+	out := make(map[string]any, len(desired))
+	for k, dv := range desired {
+		ov, ok := observed[k]
+		if !ok {
+			continue
+		}
+		dm, dok := dv.(map[string]any)
+		om, ook := ov.(map[string]any)
+		if dok && ook {
+			out[k] = prune(om, dm)
+			continue
+		}
+		out[k] = ov
+	}
+	return out
+}
+
+// lineDiff returns a line diff between the YAML representations of the
+// supplied objects, or an empty string if they're equal.
+func lineDiff(from, to map[string]any) (string, error) {
+	a, err := yaml.Marshal(from)
+	if err != nil {
+		return "", err
+	}
+	b, err := yaml.Marshal(to)
+	if err != nil {
+		return "", err
+	}
+	if string(a) == string(b) {
+		return "", nil
+	}
+
+	dmp := diffmatchpatch.New()
+	ca, cb, lines := dmp.DiffLinesToChars(string(a), string(b))
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(ca, cb, false), lines)
+
+	sb := &strings.Builder{}
+	for _, d := range diffs {
+		prefix := "  "
+		switch d.Type { //nolint:exhaustive // Equal lines keep the default prefix.
+		case diffmatchpatch.DiffInsert:
+			prefix = "+ "
+		case diffmatchpatch.DiffDelete:
+			prefix = "- "
+		}
+		for _, l := range strings.SplitAfter(d.Text, "\n") {
+			if l == "" {
+				continue
+			}
+			sb.WriteString(prefix + l)
+		}
+	}
+	return sb.String(), nil
+}
+
+// PrintDiffs prints the supplied diffs for the named XR.
+func PrintDiffs(w io.Writer, xr string, diffs []ResourceDiff) error {
+	if _, err := fmt.Fprintf(w, "%s:\n", xr); err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		id := d.Kind
+		if d.Name != "" {
+			id = fmt.Sprintf("%s/%s", d.Kind, d.Name)
+		}
+		if _, err := fmt.Fprintf(w, "  %s %s (%s)\n", d.Type, d.ResourceName, id); err != nil {
+			return err
+		}
+		for _, l := range strings.SplitAfter(d.Diff, "\n") {
+			if l == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "      %s", l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
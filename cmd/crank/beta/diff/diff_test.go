@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func cd(name string, obj map[string]any) composed.Unstructured {
+	u := composed.Unstructured{Unstructured: unstructured.Unstructured{Object: obj}}
+	u.SetAPIVersion("example.org/v1")
+	u.SetKind("Bucket")
+	u.SetAnnotations(map[string]string{"crossplane.io/composition-resource-name": name})
+	return u
+}
+
+func withName(u composed.Unstructured, name string) composed.Unstructured {
+	u.SetName(name)
+	return u
+}
+
+func TestDiffComposedResources(t *testing.T) {
+	type args struct {
+		desired  []composed.Unstructured
+		observed []composed.Unstructured
+	}
+	type want struct {
+		diffs []ResourceDiff
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoChanges": {
+			reason: "Fields that exist only on the observed resource should be ignored.",
+			args: args{
+				desired: []composed.Unstructured{
+					cd("bucket", map[string]any{"spec": map[string]any{"region": "us-east-1"}}),
+				},
+				observed: []composed.Unstructured{
+					withName(cd("bucket", map[string]any{
+						"spec":   map[string]any{"region": "us-east-1", "acl": "private"},
+						"status": map[string]any{"ready": true},
+					}), "bucket-abc"),
+				},
+			},
+			want: want{
+				diffs: []ResourceDiff{},
+			},
+		},
+		"Changes": {
+			reason: "Added, removed, and modified resources should be returned sorted by name.",
+			args: args{
+				desired: []composed.Unstructured{
+					cd("c-new", map[string]any{"spec": map[string]any{"region": "us-east-1"}}),
+					cd("a-bucket", map[string]any{"spec": map[string]any{"region": "us-west-2"}}),
+				},
+				observed: []composed.Unstructured{
+					withName(cd("a-bucket", map[string]any{"spec": map[string]any{"region": "us-east-1"}}), "bucket-abc"),
+					withName(cd("b-old", map[string]any{"spec": map[string]any{"region": "us-east-1"}}), "bucket-def"),
+				},
+			},
+			want: want{
+				diffs: []ResourceDiff{
+					{
+						ResourceName: "a-bucket",
+						Kind:         "Bucket",
+						Name:         "bucket-abc",
+						Type:         ChangeTypeModified,
+						Diff: `  apiVersion: example.org/v1
+  kind: Bucket
+  metadata:
+    annotations:
+      crossplane.io/composition-resource-name: a-bucket
+  spec:
+-   region: us-east-1
++   region: us-west-2
+`,
+					},
+					{
+						ResourceName: "b-old",
+						Kind:         "Bucket",
+						Name:         "bucket-def",
+						Type:         ChangeTypeRemoved,
+					},
+					{
+						ResourceName: "c-new",
+						Kind:         "Bucket",
+						Type:         ChangeTypeAdded,
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			diffs, err := DiffComposedResources(tc.args.desired, tc.args.observed)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDiffComposedResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.diffs, diffs); diff != "" {
+				t.Errorf("\n%s\nDiffComposedResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -49,10 +49,20 @@ const (
 	errWriteRow               = "cannot write row"
 )
 
+// Sort orders.
+const (
+	sortByName   = "name"
+	sortByCPU    = "cpu"
+	sortByMemory = "memory"
+)
+
 // Cmd represents the top command.
 type Cmd struct {
-	Summary   bool   `help:"Adds summary header for all Crossplane pods." name:"summary"                                                             short:"s"`
-	Namespace string `default:"crossplane-system"                         help:"Show pods from a specific namespace, defaults to crossplane-system." name:"namespace" short:"n"`
+	Summary   bool   `help:"Adds summary header for all Crossplane pods."      name:"summary"                                                             short:"s"`
+	Namespace string `default:"crossplane-system"                              help:"Show pods from a specific namespace, defaults to crossplane-system." name:"namespace"                                                                           short:"n"`
+	SortBy    string `default:"name"                                           enum:"name,cpu,memory"                                                     help:"Sort pods by name (grouped by type), or by CPU or memory usage in descending order." name:"sort-by"`
+	Package   string `help:"Only show pods of the named provider or function." name:"package"`
+	Selector  string `help:"Only show pods matching this label selector."      name:"selector"                                                            short:"l"`
 }
 
 // Help returns help instructions for the top command.
@@ -71,11 +81,21 @@ Examples:
 
   # Add summary of resources utilization for all Crossplane pods in the default 'crossplane-system' on top of the results.
   crossplane beta top -s
+
+  # Show the Crossplane pods using the most memory first.
+  crossplane beta top --sort-by=memory
+
+  # Show resources utilization for the pods of a specific provider.
+  crossplane beta top --package=provider-aws-s3
+
+  # Show resources utilization for Crossplane pods matching a label selector.
+  crossplane beta top -l app.kubernetes.io/part-of=crossplane
 `
 }
 
 type topMetrics struct {
 	PodType      string
+	PackageName  string
 	PodName      string
 	PodNamespace string
 	CPUUsage     resource.Quantity
@@ -129,12 +149,12 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
 
 	ctx := context.Background()
 
-	pods, err := k8sClientset.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{})
+	pods, err := k8sClientset.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: c.Selector})
 	if err != nil {
 		return errors.Wrap(err, errFetchAllPods)
 	}
 
-	crossplanePods := getCrossplanePods(pods.Items)
+	crossplanePods := filterPodsByPackage(getCrossplanePods(pods.Items), c.Package)
 	logger.Debug("Fetched all Crossplane pods", "pods", crossplanePods, "namespace", c.Namespace)
 
 	if len(crossplanePods) == 0 {
@@ -159,12 +179,7 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
 
 	logger.Debug("Added metrics to Crossplane pods")
 
-	sort.Slice(crossplanePods, func(i, j int) bool {
-		if crossplanePods[i].PodType == crossplanePods[j].PodType {
-			return crossplanePods[i].PodName < crossplanePods[j].PodName
-		}
-		return crossplanePods[i].PodType < crossplanePods[j].PodType
-	})
+	sortPods(crossplanePods, c.SortBy)
 
 	if c.Summary {
 		printPodsSummary(k.Stdout, crossplanePods)
@@ -179,6 +194,45 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
 	return nil
 }
 
+// sortPods sorts the supplied pods. Pods are sorted by type then name, unless
+// they're sorted by CPU or memory usage, in which case the heaviest pods are
+// first. Pods with the same usage are sorted by name.
+func sortPods(pods []topMetrics, by string) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		switch by {
+		case sortByCPU:
+			if c := pods[i].CPUUsage.Cmp(pods[j].CPUUsage); c != 0 {
+				return c > 0
+			}
+			return pods[i].PodName < pods[j].PodName
+		case sortByMemory:
+			if c := pods[i].MemoryUsage.Cmp(pods[j].MemoryUsage); c != 0 {
+				return c > 0
+			}
+			return pods[i].PodName < pods[j].PodName
+		}
+		if pods[i].PodType == pods[j].PodType {
+			return pods[i].PodName < pods[j].PodName
+		}
+		return pods[i].PodType < pods[j].PodType
+	})
+}
+
+// filterPodsByPackage returns the supplied pods that belong to the named
+// package. All pods are returned if the name is empty.
+func filterPodsByPackage(pods []topMetrics, name string) []topMetrics {
+	if name == "" {
+		return pods
+	}
+	filtered := make([]topMetrics, 0, len(pods))
+	for _, pod := range pods {
+		if pod.PackageName == name {
+			filtered = append(filtered, pod)
+		}
+	}
+	return filtered
+}
+
 func printPodsTable(w io.Writer, crossplanePods []topMetrics) error {
 	tw := printers.GetNewTabWriter(w)
 	// Building header
@@ -246,13 +300,14 @@ func getCrossplanePods(pods []v1.Pod) []topMetrics {
 	for _, pod := range pods {
 		labels := pod.GetLabels()
 
-		var podType string
+		var podType, packageName string
 		isCrossplanePod := false
 		for labelKey, labelValue := range labels {
 			switch {
 			case strings.HasPrefix(labelKey, "pkg.crossplane.io/"):
 				podType = strings.SplitN(labelKey, "/", 2)[1]
 				if podType != "revision" {
+					packageName = labelValue
 					isCrossplanePod = true
 				}
 			case labelKey == "app.kubernetes.io/part-of" && labelValue == "crossplane":
@@ -267,6 +322,7 @@ func getCrossplanePods(pods []v1.Pod) []topMetrics {
 		if isCrossplanePod {
 			metricsList = append(metricsList, topMetrics{
 				PodType:      podType,
+				PackageName:  packageName,
 				PodName:      pod.Name,
 				PodNamespace: pod.Namespace,
 			})
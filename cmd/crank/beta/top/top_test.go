@@ -49,6 +49,7 @@ func TestGetCrossplanePods(t *testing.T) {
 				topMetrics: []topMetrics{
 					{
 						PodType:      "function",
+						PackageName:  "function-go-templating",
 						PodName:      "function-12345abcd-xyzwv",
 						PodNamespace: "crossplane-system",
 					},
@@ -106,11 +107,13 @@ func TestGetCrossplanePods(t *testing.T) {
 				topMetrics: []topMetrics{
 					{
 						PodType:      "function",
+						PackageName:  "function-go-templating",
 						PodName:      "function-go-templating-213wer",
 						PodNamespace: "crossplane-system",
 					},
 					{
 						PodType:      "provider",
+						PackageName:  "provider-azure-storage",
 						PodName:      "provider-azure-storage",
 						PodNamespace: "crossplane-system",
 					},
@@ -134,6 +137,7 @@ func TestGetCrossplanePods(t *testing.T) {
 				topMetrics: []topMetrics{
 					{
 						PodType:      "extension",
+						PackageName:  "new-crossplane-extension",
 						PodName:      "extension-some-feature-12345",
 						PodNamespace: "crossplane-system",
 					},
@@ -336,3 +340,89 @@ func TestCapitalizeFirst(t *testing.T) {
 		})
 	}
 }
+
+func TestSortPods(t *testing.T) {
+	pods := func() []topMetrics {
+		return []topMetrics{
+			{PodType: "provider", PodName: "provider-b", CPUUsage: resource.MustParse("10m"), MemoryUsage: resource.MustParse("300Mi")},
+			{PodType: "crossplane", PodName: "crossplane-1", CPUUsage: resource.MustParse("200m"), MemoryUsage: resource.MustParse("100Mi")},
+			{PodType: "function", PodName: "function-a", CPUUsage: resource.MustParse("50m"), MemoryUsage: resource.MustParse("200Mi")},
+			{PodType: "provider", PodName: "provider-a", CPUUsage: resource.MustParse("10m"), MemoryUsage: resource.MustParse("50Mi")},
+		}
+	}
+
+	tests := map[string]struct {
+		reason string
+		by     string
+		want   []string
+	}{
+		"ByName": {
+			reason: "Should sort pods by type, then name",
+			by:     sortByName,
+			want:   []string{"crossplane-1", "function-a", "provider-a", "provider-b"},
+		},
+		"ByCPU": {
+			reason: "Should sort pods by descending CPU usage, then name",
+			by:     sortByCPU,
+			want:   []string{"crossplane-1", "function-a", "provider-a", "provider-b"},
+		},
+		"ByMemory": {
+			reason: "Should sort pods by descending memory usage",
+			by:     sortByMemory,
+			want:   []string{"provider-b", "function-a", "crossplane-1", "provider-a"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := pods()
+			sortPods(got, tt.by)
+
+			names := make([]string, 0, len(got))
+			for _, p := range got {
+				names = append(names, p.PodName)
+			}
+			if diff := cmp.Diff(tt.want, names); diff != "" {
+				t.Errorf("%s\nsortPods(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFilterPodsByPackage(t *testing.T) {
+	pods := []topMetrics{
+		{PodType: "crossplane", PodName: "crossplane-1"},
+		{PodType: "provider", PackageName: "provider-aws-s3", PodName: "provider-aws-s3-1"},
+		{PodType: "function", PackageName: "function-auto-ready", PodName: "function-auto-ready-1"},
+	}
+
+	tests := map[string]struct {
+		reason string
+		name   string
+		want   []topMetrics
+	}{
+		"NoFilter": {
+			reason: "Should return all pods if no package name is supplied",
+			want:   pods,
+		},
+		"FilterByPackage": {
+			reason: "Should only return the pods of the named package",
+			name:   "provider-aws-s3",
+			want:   []topMetrics{pods[1]},
+		},
+		"NoMatches": {
+			reason: "Should return no pods if none belong to the named package",
+			name:   "provider-gcp",
+			want:   []topMetrics{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := filterPodsByPackage(pods, tt.name)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("%s\nfilterPodsByPackage(): -want, +got:\n%s", tt.reason, diff)
+			}
+		})
+	}
+}
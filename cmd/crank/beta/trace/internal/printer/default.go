@@ -223,6 +223,10 @@ func getResourceStatus(r *resource.Resource, name string, wide bool) fmt.Stringe
 		// if there is an error we want to show it
 		status = "Error"
 		m = r.Error.Error()
+	case r.IsPaused():
+		// A paused resource isn't synced on purpose, so we don't want to
+		// imply it's an error
+		status = "Paused"
 	case readyCond.Status == corev1.ConditionTrue && syncedCond.Status == corev1.ConditionTrue:
 		// if both are true we want to show the ready reason only
 		status = string(readyCond.Reason)
@@ -238,6 +242,11 @@ func getResourceStatus(r *resource.Resource, name string, wide bool) fmt.Stringe
 		status = string(readyCond.Reason)
 		m = readyCond.Message
 
+	case r.ConnectionSecretKeys != nil:
+		// This is a connection secret, show its keys but never their values
+		status = "Keys"
+		m = connectionSecretKeysString(r.ConnectionSecretKeys)
+
 	default:
 		// both are unknown or unset, let's try showing the ready reason, probably empty
 		status = string(readyCond.Reason)
@@ -279,6 +288,10 @@ func getPkgResourceStatus(r *resource.Resource, name string, wide bool) fmt.Stri
 		// resource is and what conditions it has.
 		status = "Error"
 		m = r.Error.Error()
+	case r.IsPaused():
+		// A paused package isn't reconciled on purpose, so we don't want to
+		// imply it's an error.
+		status = "Paused"
 	case xpkg.IsPackageType(gk):
 		switch {
 		case healthyCond.Status == corev1.ConditionTrue && installedCond.Status == corev1.ConditionTrue:
@@ -363,6 +376,22 @@ func getPkgResourceStatus(r *resource.Resource, name string, wide bool) fmt.Stri
 	}
 }
 
+// connectionSecretKeysString returns a string listing the supplied connection
+// secret keys, marking those that are empty.
+func connectionSecretKeysString(keys []resource.ConnectionSecretKey) string {
+	if len(keys) == 0 {
+		return "<none>"
+	}
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.Name
+		if k.Empty {
+			names[i] += " (empty)"
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
 func mapEmptyStatusToDash(s corev1.ConditionStatus) string {
 	if s == "" {
 		return "-"
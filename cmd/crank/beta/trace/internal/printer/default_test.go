@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/crossplane/cmd/crank/beta/trace/internal/resource"
@@ -87,6 +88,56 @@ ObjectStorage/test-resource (default)                             True      True
    │  └─ User/test-resource-child-2-bucket-hash        four       True      False   SomethingWrongHappened: Error with bucket child 2
    │     └─ User/test-resource-child-2-1-bucket-hash              True      -       
    └─ User/test-resource-user-hash                                Unknown   True    
+`,
+				err: nil,
+			},
+		},
+		"ResourceWithConnectionSecretKeys": {
+			reason: "Should print the keys of a connection secret, but not their values.",
+			args: args{
+				resource: &resource.Resource{
+					Unstructured: DummyNamespacedResource("ObjectStorage", "test-resource", "default", xpv1.Available(), xpv1.ReconcileSuccess()),
+					Children: []*resource.Resource{
+						{
+							Unstructured: DummyManifest("Secret", "test-resource-secret", WithAPIVersion("v1"), WithNamespace("default")),
+							ConnectionSecretKeys: []resource.ConnectionSecretKey{
+								{Name: "endpoint"},
+								{Name: "password", Empty: true},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				// Note: Use spaces instead of tabs for indentation
+				//nolint:dupword // False positive for 'True True'
+				output: `
+NAME                                       SYNCED   READY   STATUS
+ObjectStorage/test-resource (default)      True     True    Available
+└─ Secret/test-resource-secret (default)   -        -       Keys: endpoint, password (empty)
+`,
+				err: nil,
+			},
+		},
+		"PausedResource": {
+			reason: "Should show that a paused resource is paused, rather than implying it's an error.",
+			args: args{
+				resource: &resource.Resource{
+					Unstructured: DummyNamespacedResource("ObjectStorage", "test-resource", "default", xpv1.Available(), xpv1.ReconcileSuccess()),
+					Children: []*resource.Resource{
+						{
+							Unstructured: DummyManifest("Bucket", "test-resource-bucket", WithConditions(xpv1.Available(), xpv1.ReconcilePaused()), WithPaused()),
+						},
+					},
+				},
+			},
+			want: want{
+				// Note: Use spaces instead of tabs for indentation
+				//nolint:dupword // False positive for 'True True'
+				output: `
+NAME                                    SYNCED   READY   STATUS
+ObjectStorage/test-resource (default)   True     True    Available
+└─ Bucket/test-resource-bucket          False    True    Paused
 `,
 				err: nil,
 			},
@@ -27,6 +27,8 @@ type dotLabel struct {
 	name       string
 	ready      string
 	synced     string
+	keys       string
+	paused     bool
 	error      string
 }
 
@@ -44,6 +46,16 @@ func (r *dotLabel) String() string {
 		"Ready: "+r.ready,
 		"Synced: "+r.synced,
 	)
+	if r.paused {
+		out = append(out,
+			"Paused: true",
+		)
+	}
+	if r.keys != "" {
+		out = append(out,
+			"Keys: "+r.keys,
+		)
+	}
 	if r.error != "" {
 		out = append(out,
 			"Error: "+r.error,
@@ -142,13 +154,18 @@ func (p *DotPrinter) Print(w io.Writer, root *resource.Resource) error {
 			}
 			label = l
 		default:
-			label = &dotLabel{
+			l := &dotLabel{
 				namespace:  item.resource.Unstructured.GetNamespace(),
 				apiVersion: item.resource.Unstructured.GetObjectKind().GroupVersionKind().GroupVersion().String(),
 				name:       fmt.Sprintf("%s/%s", item.resource.Unstructured.GetKind(), item.resource.Unstructured.GetName()),
 				ready:      string(item.resource.GetCondition(xpv1.TypeReady).Status),
 				synced:     string(item.resource.GetCondition(xpv1.TypeSynced).Status),
+				paused:     item.resource.IsPaused(),
 			}
+			if item.resource.ConnectionSecretKeys != nil {
+				l.keys = connectionSecretKeysString(item.resource.ConnectionSecretKeys)
+			}
+			label = l
 		}
 		node.Label(label.String())
 		node.Attr("penwidth", "2")
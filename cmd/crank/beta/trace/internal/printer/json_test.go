@@ -273,6 +273,32 @@ func TestJSONPrinter(t *testing.T) {
     }
   ]
 }
+`,
+				err: nil,
+			},
+		},
+		"PausedResource": {
+			reason: "Should mark a paused resource as paused.",
+			args: args{
+				resource: &resource.Resource{
+					Unstructured: DummyManifest("Bucket", "test-resource-bucket", WithPaused()),
+				},
+			},
+			want: want{
+				output: `
+{
+  "object": {
+    "apiVersion": "test.cloud/v1alpha1",
+    "kind": "Bucket",
+    "metadata": {
+      "name": "test-resource-bucket",
+      "annotations": {
+        "crossplane.io/paused": "true"
+      }
+    }
+  },
+  "paused": true
+}
 `,
 				err: nil,
 			},
@@ -39,6 +39,7 @@ const (
 	TypeWide    Type = "wide"
 	TypeJSON    Type = "json"
 	TypeDot     Type = "dot"
+	TypeYAML    Type = "yaml"
 )
 
 // Printer implements the interface which is used by all printers in this package.
@@ -46,8 +47,28 @@ type Printer interface {
 	Print(w io.Writer, r *resource.Resource) error
 }
 
+// An Option configures a printer.
+type Option func(o *options)
+
+type options struct {
+	showSecretData bool
+}
+
+// WithSecretData configures printers that print complete manifests to include
+// the data of Secrets. It's removed by default.
+func WithSecretData(show bool) Option {
+	return func(o *options) {
+		o.showSecretData = show
+	}
+}
+
 // New creates a new printer based on the specified type.
-func New(typeStr string) (Printer, error) {
+func New(typeStr string, opts ...Option) (Printer, error) {
+	o := &options{}
+	for _, fn := range opts {
+		fn(o)
+	}
+
 	var p Printer
 
 	switch Type(typeStr) {
@@ -61,6 +82,10 @@ func New(typeStr string) (Printer, error) {
 		p = &JSONPrinter{}
 	case TypeDot:
 		p = &DotPrinter{}
+	case TypeYAML:
+		p = &YAMLPrinter{
+			showSecretData: o.showSecretData,
+		}
 	default:
 		return nil, errors.Errorf(errFmtUnknownPrinterType, typeStr)
 	}
@@ -78,6 +78,13 @@ func WithCompositionResourceName(n string) DummyManifestOpt {
 	}
 }
 
+// WithPaused pauses reconciliation of the manifest.
+func WithPaused() DummyManifestOpt {
+	return func(m *unstructured.Unstructured) {
+		meta.AddAnnotations(m, map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
+	}
+}
+
 // WithImage sets the image of the manifest.
 func WithImage(image string) DummyManifestOpt {
 	return func(m *unstructured.Unstructured) {
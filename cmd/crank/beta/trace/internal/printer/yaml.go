@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane/cmd/crank/beta/trace/internal/resource"
+)
+
+const (
+	errFmtCannotMarshalYAML = "cannot marshal resource %s/%s as YAML"
+)
+
+// YAMLPrinter is a printer that prints the complete manifest of every resource
+// in the resource graph as a multi-document YAML stream, in tree order.
+type YAMLPrinter struct {
+	// showSecretData includes the data of Secrets in the output. By default
+	// it's removed.
+	showSecretData bool
+}
+
+var _ Printer = &YAMLPrinter{}
+
+// Print implements the Printer interface.
+func (p *YAMLPrinter) Print(w io.Writer, root *resource.Resource) error {
+	first := true
+	var walk func(r *resource.Resource) error
+	walk = func(r *resource.Resource) error {
+		// Resources we couldn't get have nothing worth printing, but their
+		// children still might.
+		if r.Error == nil {
+			u := r.Unstructured.DeepCopy()
+			if !p.showSecretData && u.GroupVersionKind().GroupKind() == (schema.GroupKind{Group: "", Kind: "Secret"}) {
+				delete(u.Object, "data")
+				delete(u.Object, "stringData")
+			}
+			out, err := yaml.Marshal(u.Object)
+			if err != nil {
+				return errors.Wrapf(err, errFmtCannotMarshalYAML, u.GetKind(), u.GetName())
+			}
+			if !first {
+				if _, err := fmt.Fprintln(w, "---"); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := w.Write(out); err != nil {
+				return err
+			}
+		}
+		for _, c := range r.Children {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(root)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/cmd/crank/beta/trace/internal/resource"
+)
+
+func TestYAMLPrinter(t *testing.T) {
+	tree := func() *resource.Resource {
+		return &resource.Resource{
+			Unstructured: DummyManifest("ObjectStorage", "test-resource", WithNamespace("default")),
+			Children: []*resource.Resource{
+				{
+					Unstructured: DummyManifest("XObjectStorage", "test-resource-hash"),
+					Children: []*resource.Resource{
+						{
+							Unstructured: DummyManifest("Bucket", "test-resource-bucket-hash"),
+						},
+						{
+							Unstructured: DummyManifest("Bucket", "test-resource-missing"),
+							Error:        errors.New("not found"),
+						},
+					},
+				},
+				{
+					Unstructured: unstructured.Unstructured{Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "Secret",
+						"metadata": map[string]any{
+							"name":      "test-resource-connection",
+							"namespace": "default",
+						},
+						"data": map[string]any{
+							"password": "c2VjcmV0",
+						},
+					}},
+				},
+			},
+		}
+	}
+
+	type args struct {
+		p        *YAMLPrinter
+		resource *resource.Resource
+	}
+
+	type want struct {
+		output string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"RedactSecretData": {
+			reason: "Should print every resource in tree order, skipping those we couldn't get and removing Secret data.",
+			args: args{
+				p:        &YAMLPrinter{},
+				resource: tree(),
+			},
+			want: want{
+				output: `
+apiVersion: test.cloud/v1alpha1
+kind: ObjectStorage
+metadata:
+  name: test-resource
+  namespace: default
+---
+apiVersion: test.cloud/v1alpha1
+kind: XObjectStorage
+metadata:
+  name: test-resource-hash
+---
+apiVersion: test.cloud/v1alpha1
+kind: Bucket
+metadata:
+  name: test-resource-bucket-hash
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test-resource-connection
+  namespace: default
+`,
+			},
+		},
+		"ShowSecretData": {
+			reason: "Should include Secret data if asked to.",
+			args: args{
+				p: &YAMLPrinter{showSecretData: true},
+				resource: &resource.Resource{
+					Unstructured: tree().Children[1].Unstructured,
+				},
+			},
+			want: want{
+				output: `
+apiVersion: v1
+data:
+  password: c2VjcmV0
+kind: Secret
+metadata:
+  name: test-resource-connection
+  namespace: default
+`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := tc.args.p.Print(&buf, tc.args.resource)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nYAMLPrinter.Print(): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(strings.TrimPrefix(tc.want.output, "\n"), buf.String()); diff != "" {
+				t.Errorf("%s\nYAMLPrinter.Print(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,10 +19,13 @@ limitations under the License.
 package resource
 
 import (
+	"encoding/json"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 )
 
 // Resource struct represents a kubernetes resource.
@@ -30,6 +33,33 @@ type Resource struct {
 	Unstructured unstructured.Unstructured `json:"object"`
 	Error        error                     `json:"error,omitempty"`
 	Children     []*Resource               `json:"children,omitempty"`
+
+	// ConnectionSecretKeys are the keys of a connection secret. They're only
+	// set for connection secrets, and only if requested.
+	ConnectionSecretKeys []ConnectionSecretKey `json:"connectionSecretKeys,omitempty"`
+}
+
+// ConnectionSecretKey is a key of a connection secret. It never contains the
+// value of the key, only whether it's empty or not.
+type ConnectionSecretKey struct {
+	Name  string `json:"name"`
+	Empty bool   `json:"empty"`
+}
+
+// MarshalJSON marshals this resource, including whether it's paused.
+func (r *Resource) MarshalJSON() ([]byte, error) {
+	// The alias type doesn't have this method, which would otherwise recurse.
+	type alias Resource
+	return json.Marshal(struct {
+		*alias
+		Paused bool `json:"paused,omitempty"`
+	}{alias: (*alias)(r), Paused: r.IsPaused()})
+}
+
+// IsPaused returns true if reconciliation of this resource is paused using the
+// crossplane.io/paused annotation.
+func (r *Resource) IsPaused() bool {
+	return meta.IsPaused(&r.Unstructured)
 }
 
 // GetCondition of this resource.
@@ -20,6 +20,7 @@ package xrm
 
 import (
 	"context"
+	"sort"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -39,7 +40,8 @@ const defaultConcurrency = 5
 
 // Client to get a Resource with all its children.
 type Client struct {
-	getConnectionSecrets bool
+	getConnectionSecrets    bool
+	getConnectionSecretKeys bool
 
 	client      client.Client
 	concurrency int
@@ -55,6 +57,14 @@ func WithConnectionSecrets(v bool) ResourceClientOption {
 	}
 }
 
+// WithConnectionSecretKeys is a functional option that sets the client to
+// get secrets, reporting only the names of their keys and never their values.
+func WithConnectionSecretKeys(v bool) ResourceClientOption {
+	return func(c *Client) {
+		c.getConnectionSecretKeys = v
+	}
+}
+
 // WithConcurrency is a functional option that sets the concurrency for the resource load.
 func WithConcurrency(n int) ResourceClientOption {
 	return func(c *Client) {
@@ -87,13 +97,44 @@ func (kc *Client) GetResourceTree(ctx context.Context, root *resource.Resource)
 
 // loadResource returns the resource for the specified object reference.
 func (kc *Client) loadResource(ctx context.Context, ref *v1.ObjectReference) *resource.Resource {
-	return resource.GetResource(ctx, kc.client, ref)
+	r := resource.GetResource(ctx, kc.client, ref)
+	if kc.getConnectionSecretKeys {
+		redactConnectionSecret(r)
+	}
+	return r
 }
 
 // getResourceChildrenRefs returns the references to the children for the given
 // Resource, assuming it's a Crossplane resource, XR or XRC.
 func (kc *Client) getResourceChildrenRefs(r *resource.Resource) []v1.ObjectReference {
-	return getResourceChildrenRefs(r, kc.getConnectionSecrets)
+	return getResourceChildrenRefs(r, kc.getConnectionSecrets || kc.getConnectionSecretKeys)
+}
+
+// redactConnectionSecret records the keys of the supplied Resource, if it's a
+// Secret, and removes their values. It also removes the last applied
+// configuration annotation, which may contain them.
+func redactConnectionSecret(r *resource.Resource) {
+	if r.Unstructured.GroupVersionKind().GroupKind() != (schema.GroupKind{Group: "", Kind: "Secret"}) || r.Error != nil {
+		return
+	}
+
+	data := r.Unstructured.Object["data"]
+	keys := make([]resource.ConnectionSecretKey, 0)
+	if m, ok := data.(map[string]any); ok {
+		for k, v := range m {
+			s, _ := v.(string)
+			keys = append(keys, resource.ConnectionSecretKey{Name: k, Empty: s == ""})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+
+	delete(r.Unstructured.Object, "data")
+	delete(r.Unstructured.Object, "stringData")
+	if a := r.Unstructured.GetAnnotations(); a != nil {
+		delete(a, v1.LastAppliedConfigAnnotation)
+		r.Unstructured.SetAnnotations(a)
+	}
+	r.ConnectionSecretKeys = keys
 }
 
 // getResourceChildrenRefs returns the references to the children for the given
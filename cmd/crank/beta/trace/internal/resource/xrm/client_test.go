@@ -293,3 +293,122 @@ func TestGetResourceChildrenRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestRedactConnectionSecret(t *testing.T) {
+	type args struct {
+		resource *resource2.Resource
+	}
+	type want struct {
+		resource *resource2.Resource
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Secret": {
+			reason: "Should record the keys of a Secret, and remove their values.",
+			args: args{
+				resource: &resource2.Resource{
+					Unstructured: unstructured.Unstructured{Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "Secret",
+						"data": map[string]any{
+							"username": "YWRtaW4=",
+							"password": "",
+							"endpoint": "ZXhhbXBsZS5vcmc=",
+						},
+					}},
+				},
+			},
+			want: want{
+				resource: &resource2.Resource{
+					Unstructured: unstructured.Unstructured{Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "Secret",
+					}},
+					ConnectionSecretKeys: []resource2.ConnectionSecretKey{
+						{Name: "endpoint"},
+						{Name: "password", Empty: true},
+						{Name: "username"},
+					},
+				},
+			},
+		},
+		"SecretLastAppliedConfiguration": {
+			reason: "Should remove the last applied configuration annotation of a Secret, which may contain its values, but keep other annotations.",
+			args: args{
+				resource: &resource2.Resource{
+					Unstructured: unstructured.Unstructured{Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "Secret",
+						"metadata": map[string]any{
+							"annotations": map[string]any{
+								"kubectl.kubernetes.io/last-applied-configuration": `{"apiVersion":"v1","kind":"Secret","stringData":{"password":"hunter2"}}`,
+								"cool": "annotation",
+							},
+						},
+						"stringData": map[string]any{
+							"password": "hunter2",
+						},
+					}},
+				},
+			},
+			want: want{
+				resource: &resource2.Resource{
+					Unstructured: unstructured.Unstructured{Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "Secret",
+						"metadata": map[string]any{
+							"annotations": map[string]any{
+								"cool": "annotation",
+							},
+						},
+					}},
+					ConnectionSecretKeys: []resource2.ConnectionSecretKey{},
+				},
+			},
+		},
+		"EmptySecret": {
+			reason: "Should record that a Secret without data has no keys.",
+			args: args{
+				resource: &resource2.Resource{
+					Unstructured: unstructured.Unstructured{Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "Secret",
+					}},
+				},
+			},
+			want: want{
+				resource: &resource2.Resource{
+					Unstructured: unstructured.Unstructured{Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "Secret",
+					}},
+					ConnectionSecretKeys: []resource2.ConnectionSecretKey{},
+				},
+			},
+		},
+		"NotASecret": {
+			reason: "Should not modify a resource that isn't a Secret.",
+			args: args{
+				resource: &resource2.Resource{
+					Unstructured: *buildXR("root-xr"),
+				},
+			},
+			want: want{
+				resource: &resource2.Resource{
+					Unstructured: *buildXR("root-xr"),
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			redactConnectionSecret(tc.args.resource)
+			if diff := cmp.Diff(tc.want.resource, tc.args.resource); diff != "" {
+				t.Errorf("\n%s\nredactConnectionSecret(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,8 +18,14 @@ limitations under the License.
 package trace
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/alecthomas/kong"
 	v1 "k8s.io/api/core/v1"
@@ -56,20 +62,27 @@ const (
 	errInvalidResourceAndName = "invalid resource and name"
 )
 
+// clearScreen is the ANSI escape sequence to move the cursor to the top left
+// of the terminal and clear it.
+const clearScreen = "\033[H\033[2J"
+
 // Cmd builds the trace tree for a Crossplane resource.
 type Cmd struct {
 	Resource string `arg:"" help:"Kind of the Crossplane resource, accepts the 'TYPE[.VERSION][.GROUP][/NAME]' format."`
 	Name     string `arg:"" help:"Name of the Crossplane resource, can be passed as part of the resource too."          optional:""`
 
 	// TODO(phisco): add support for all the usual kubectl flags; configFlags := genericclioptions.NewConfigFlags(true).AddFlags(...)
-	Context                   string `default:""                                    help:"Kubernetes context."                         name:"context"                                                             short:"c"`
-	Namespace                 string `default:""                                    help:"Namespace of the resource."                  name:"namespace"                                                           short:"n"`
-	Output                    string `default:"default"                             enum:"default,wide,json,dot"                       help:"Output format. One of: default, wide, json, dot."                    name:"output"                    short:"o"`
-	ShowConnectionSecrets     bool   `help:"Show connection secrets in the output." name:"show-connection-secrets"                     short:"s"`
-	ShowPackageDependencies   string `default:"unique"                              enum:"unique,all,none"                             help:"Show package dependencies in the output. One of: unique, all, none." name:"show-package-dependencies"`
-	ShowPackageRevisions      string `default:"active"                              enum:"active,all,none"                             help:"Show package revisions in the output. One of: active, all, none."    name:"show-package-revisions"`
-	ShowPackageRuntimeConfigs bool   `default:"false"                               help:"Show package runtime configs in the output." name:"show-package-runtime-configs"`
-	Concurrency               int    `default:"5"                                   help:"load concurrency"                            name:"concurrency"`
+	Context                   string `default:""                                                                                                                 help:"Kubernetes context."                         name:"context"                                                             short:"c"`
+	Namespace                 string `default:""                                                                                                                 help:"Namespace of the resource."                  name:"namespace"                                                           short:"n"`
+	Output                    string `default:"default"                                                                                                          enum:"default,wide,json,dot,yaml"                  help:"Output format. One of: default, wide, json, dot, yaml."              name:"output"                    short:"o"`
+	ShowConnectionSecrets     bool   `help:"Show connection secrets in the output."                                                                              name:"show-connection-secrets"                     short:"s"`
+	ShowConnectionSecretKeys  bool   `help:"Show connection secrets in the output, listing the names of their keys but never their values."                      name:"show-connection-secret"`
+	ShowSecretData            bool   `help:"Include the data of Secrets when printing complete manifests with --output=yaml. Secret data is removed by default." name:"show-secret-data"`
+	ShowPackageDependencies   string `default:"unique"                                                                                                           enum:"unique,all,none"                             help:"Show package dependencies in the output. One of: unique, all, none." name:"show-package-dependencies"`
+	ShowPackageRevisions      string `default:"active"                                                                                                           enum:"active,all,none"                             help:"Show package revisions in the output. One of: active, all, none."    name:"show-package-revisions"`
+	ShowPackageRuntimeConfigs bool   `default:"false"                                                                                                            help:"Show package runtime configs in the output." name:"show-package-runtime-configs"`
+	Concurrency               int    `default:"5"                                                                                                                help:"load concurrency"                            name:"concurrency"`
+	Watch                     bool   `help:"Watch the resource, printing its tree again each time it changes."                                                   name:"watch"                                       short:"w"`
 }
 
 // Help returns help message for the trace command.
@@ -93,14 +106,27 @@ Examples:
   # Show connection secrets in the output
   crossplane beta trace mykind my-res -n my-ns --show-connection-secrets
 
+  # Show connection secrets in the output, listing which keys they contain and
+  # whether they're empty, without showing their values
+  crossplane beta trace mykind my-res -n my-ns --show-connection-secret
+
   # Output a graph in dot format and pipe to dot to generate a png
   crossplane beta trace mykind my-res -n my-ns -o dot | dot -Tpng -o output.png
 
   # Output all retrieved resources to json and pipe to jq to have it coloured
   crossplane beta trace mykind my-res -n my-ns -o json | jq
 
+  # Output the complete manifest of every resource in the tree as a
+  # multi-document YAML stream, e.g. for a support bundle. The data of any
+  # Secrets is removed unless --show-secret-data is set.
+  crossplane beta trace mykind my-res -n my-ns -o yaml --show-connection-secrets
+
   # Output debug logs to stderr while redirecting a dot formatted graph to dot
   crossplane beta trace mykind my-res -n my-ns -o dot --verbose | dot -Tpng -o output.png
+
+  # Watch a resource, printing its tree again each time it or any resource in
+  # its tree changes. Press Ctrl-C to stop watching.
+  crossplane beta trace mykind my-res -n my-ns --watch
 `
 }
 
@@ -110,7 +136,7 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
 	logger = logger.WithValues("Resource", c.Resource, "Name", c.Name)
 
 	// Init new printer
-	p, err := printer.New(c.Output)
+	p, err := printer.New(c.Output, printer.WithSecretData(c.ShowSecretData))
 	if err != nil {
 		return errors.Wrap(err, errInitPrinter)
 	}
@@ -140,7 +166,7 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
 
 	logger.Debug("Found kubeconfig")
 
-	client, err := client.New(kubeconfig, client.Options{
+	client, err := client.NewWithWatch(kubeconfig, client.Options{
 		Scheme: scheme.Scheme,
 	})
 	if err != nil {
@@ -211,6 +237,7 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
 		logger.Debug("Requested resource is not a package, assumed to be an XR, XRC or MR")
 		treeClient, err = xrm.NewClient(client,
 			xrm.WithConnectionSecrets(c.ShowConnectionSecrets),
+			xrm.WithConnectionSecretKeys(c.ShowConnectionSecretKeys),
 			xrm.WithConcurrency(c.Concurrency),
 		)
 		if err != nil {
@@ -219,6 +246,10 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
 	}
 	logger.Debug("Built client")
 
+	if c.Watch {
+		return c.watch(ctx, k.Stdout, p, client, treeClient, rootRef, logger)
+	}
+
 	root, err = treeClient.GetResourceTree(ctx, root)
 	if err != nil {
 		logger.Debug(errGetResource, "error", err)
@@ -235,6 +266,58 @@ func (c *Cmd) Run(k *kong.Context, logger logging.Logger) error {
 	return nil
 }
 
+// watch prints the resource tree each time it changes, until interrupted.
+func (c *Cmd) watch(ctx context.Context, out io.Writer, p printer.Printer, kube client.WithWatch, treeClient resource.TreeClient, rootRef *v1.ObjectReference, logger logging.Logger) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	w := newTreeWatcher(kube, logger)
+	defer w.Stop()
+
+	var last []byte
+	for {
+		root := resource.GetResource(ctx, kube, rootRef)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := root.Error; err != nil {
+			return errors.Wrap(err, errGetResource)
+		}
+		root, err := treeClient.GetResourceTree(ctx, root)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, errGetResource)
+		}
+
+		buf := &bytes.Buffer{}
+		if err := p.Print(buf, root); err != nil {
+			return errors.Wrap(err, errCliOutput)
+		}
+
+		// Watch events don't necessarily change what we print, e.g. when
+		// only a resource's resource version changes.
+		if !bytes.Equal(buf.Bytes(), last) {
+			if c.Output == string(printer.TypeDefault) || c.Output == string(printer.TypeWide) {
+				_, _ = fmt.Fprint(out, clearScreen)
+			}
+			if _, err := out.Write(buf.Bytes()); err != nil {
+				return errors.Wrap(err, errCliOutput)
+			}
+			last = buf.Bytes()
+		}
+
+		targets, keys := watchTargets(root)
+		logger.Debug("Watching resource tree", "kinds", len(targets), "resources", len(keys))
+		w.Sync(ctx, targets)
+
+		if !w.Wait(ctx, keys, watchDebounce) {
+			return nil
+		}
+	}
+}
+
 func (c *Cmd) getResourceAndName() (string, string, error) {
 	// If no resource was provided, error out (should never happen as it's
 	// required by Kong)
@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kwatch "k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/cmd/crank/beta/trace/internal/resource"
+)
+
+const (
+	// watchDebounce is how long to wait for further changes after a change to
+	// the tree, before rendering it again.
+	watchDebounce = 250 * time.Millisecond
+
+	// watchRetryInterval is how long to wait before trying to watch a kind of
+	// resource again, if it couldn't be watched. The tree is fetched again
+	// each time a watch fails, so that it's polled for changes when it can't
+	// be watched.
+	watchRetryInterval = 5 * time.Second
+
+	errWatchResources = "cannot watch resources"
+)
+
+// A watchTarget is a kind of resource to watch in a namespace. Cluster scoped
+// kinds of resource are watched in all namespaces.
+type watchTarget struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+}
+
+// An objectKey identifies a resource in a trace tree. The zero objectKey
+// indicates that the tree should be fetched again regardless of what it
+// contains.
+type objectKey struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// watchTargets returns the kinds of resource to watch for changes to the
+// supplied trace tree, and the resources it contains.
+func watchTargets(root *resource.Resource) (map[watchTarget]bool, map[objectKey]bool) {
+	targets := map[watchTarget]bool{}
+	keys := map[objectKey]bool{}
+
+	var walk func(r *resource.Resource)
+	walk = func(r *resource.Resource) {
+		u := r.Unstructured
+		if gvk := u.GroupVersionKind(); !gvk.Empty() && u.GetName() != "" {
+			targets[watchTarget{gvk: gvk, namespace: u.GetNamespace()}] = true
+			keys[objectKey{gvk: gvk, namespace: u.GetNamespace(), name: u.GetName()}] = true
+		}
+		for _, c := range r.Children {
+			walk(c)
+		}
+	}
+	walk(root)
+
+	return targets, keys
+}
+
+// A treeWatcher watches the kinds of resource in a trace tree, and reports
+// changes to the resources in the tree.
+type treeWatcher struct {
+	client  client.WithWatch
+	log     logging.Logger
+	events  chan objectKey
+	watches map[watchTarget]context.CancelFunc
+}
+
+func newTreeWatcher(c client.WithWatch, l logging.Logger) *treeWatcher {
+	return &treeWatcher{
+		client:  c,
+		log:     l,
+		events:  make(chan objectKey),
+		watches: map[watchTarget]context.CancelFunc{},
+	}
+}
+
+// Sync starts watching the supplied targets, and stops watching any targets
+// that aren't supplied. Resources may be added to or removed from a tree over
+// time, so Sync should be called each time the tree is fetched.
+func (w *treeWatcher) Sync(ctx context.Context, targets map[watchTarget]bool) {
+	for t, cancel := range w.watches {
+		if !targets[t] {
+			cancel()
+			delete(w.watches, t)
+		}
+	}
+	for t := range targets {
+		if _, ok := w.watches[t]; ok {
+			continue
+		}
+		wctx, cancel := context.WithCancel(ctx)
+		w.watches[t] = cancel
+		go w.watch(wctx, t)
+	}
+}
+
+// Stop watching all targets.
+func (w *treeWatcher) Stop() {
+	for t, cancel := range w.watches {
+		cancel()
+		delete(w.watches, t)
+	}
+}
+
+// Wait until one of the supplied resources changes, then wait for the
+// supplied debounce period to coalesce any further changes. It returns false
+// if the supplied context is done before a supplied resource changes.
+func (w *treeWatcher) Wait(ctx context.Context, keys map[objectKey]bool, debounce time.Duration) bool {
+	for changed := false; !changed; {
+		select {
+		case <-ctx.Done():
+			return false
+		case k := <-w.events:
+			changed = k == (objectKey{}) || keys[k]
+		}
+	}
+
+	t := time.NewTimer(debounce)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-w.events:
+		case <-t.C:
+			return true
+		}
+	}
+}
+
+func (w *treeWatcher) watch(ctx context.Context, t watchTarget) {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(t.gvk.GroupVersion().WithKind(t.gvk.Kind + "List"))
+
+	for {
+		wi, err := w.client.Watch(ctx, l, client.InNamespace(t.namespace))
+		if err == nil {
+			w.forward(ctx, t, wi)
+			wi.Stop()
+		} else {
+			w.log.Debug(errWatchResources, "gvk", t.gvk.String(), "namespace", t.namespace, "error", err)
+		}
+
+		// The watch either failed, or was closed by the API server. Fetch
+		// the tree again in case we missed any changes, then try again.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+		if !w.send(ctx, objectKey{}) {
+			return
+		}
+	}
+}
+
+func (w *treeWatcher) forward(ctx context.Context, t watchTarget, wi kwatch.Interface) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-wi.ResultChan():
+			if !ok {
+				return
+			}
+			o, ok := e.Object.(client.Object)
+			if !ok || e.Type == kwatch.Error || e.Type == kwatch.Bookmark {
+				continue
+			}
+			if !w.send(ctx, objectKey{gvk: t.gvk, namespace: o.GetNamespace(), name: o.GetName()}) {
+				return
+			}
+		}
+	}
+}
+
+func (w *treeWatcher) send(ctx context.Context, k objectKey) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case w.events <- k:
+		return true
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/cmd/crank/beta/trace/internal/resource"
+)
+
+func newResource(gvk schema.GroupVersionKind, namespace, name string, children ...*resource.Resource) *resource.Resource {
+	u := unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return &resource.Resource{Unstructured: u, Children: children}
+}
+
+func TestWatchTargets(t *testing.T) {
+	claim := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Claim"}
+	xr := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XR"}
+	mr := schema.GroupVersionKind{Group: "nop.crossplane.io", Version: "v1alpha1", Kind: "NopResource"}
+
+	type want struct {
+		targets map[watchTarget]bool
+		keys    map[objectKey]bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		root   *resource.Resource
+		want   want
+	}{
+		"Tree": {
+			reason: "We should watch each kind of resource in the tree, in the namespace of the resources.",
+			root: newResource(claim, "default", "cool-claim",
+				newResource(xr, "", "cool-xr",
+					newResource(mr, "", "cool-mr-1"),
+					newResource(mr, "", "cool-mr-2"),
+				),
+			),
+			want: want{
+				targets: map[watchTarget]bool{
+					{gvk: claim, namespace: "default"}: true,
+					{gvk: xr}:                          true,
+					{gvk: mr}:                          true,
+				},
+				keys: map[objectKey]bool{
+					{gvk: claim, namespace: "default", name: "cool-claim"}: true,
+					{gvk: xr, name: "cool-xr"}:                             true,
+					{gvk: mr, name: "cool-mr-1"}:                           true,
+					{gvk: mr, name: "cool-mr-2"}:                           true,
+				},
+			},
+		},
+		"UnnamedResource": {
+			reason: "We shouldn't watch resources we can't identify.",
+			root: newResource(xr, "", "cool-xr",
+				newResource(schema.GroupVersionKind{}, "", ""),
+			),
+			want: want{
+				targets: map[watchTarget]bool{
+					{gvk: xr}: true,
+				},
+				keys: map[objectKey]bool{
+					{gvk: xr, name: "cool-xr"}: true,
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			targets, keys := watchTargets(tc.root)
+			got := want{targets: targets, keys: keys}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}, watchTarget{}, objectKey{})); diff != "" {
+				t.Errorf("\n%s\nwatchTargets(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTreeWatcherWait(t *testing.T) {
+	xr := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XR"}
+	keys := map[objectKey]bool{{gvk: xr, name: "cool-xr"}: true}
+
+	cases := map[string]struct {
+		reason string
+		events []objectKey
+		want   bool
+	}{
+		"ResourceInTreeChanged": {
+			reason: "We should return true when a resource in the tree changes.",
+			events: []objectKey{{gvk: xr, name: "cool-xr"}},
+			want:   true,
+		},
+		"ResourceNotInTreeChanged": {
+			reason: "We should ignore changes to resources that aren't in the tree.",
+			events: []objectKey{{gvk: xr, name: "other-xr"}},
+			want:   false,
+		},
+		"Resync": {
+			reason: "We should return true when asked to fetch the tree again.",
+			events: []objectKey{{}},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			w := newTreeWatcher(nil, logging.NewNopLogger())
+			go func() {
+				for _, e := range tc.events {
+					_ = w.send(ctx, e)
+				}
+			}()
+
+			got := w.Wait(ctx, keys, 10*time.Millisecond)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWait(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -32,8 +32,9 @@ type Cache interface {
 	Store(schemas [][]byte, path string) error
 	Flush() error
 	Init() error
-	Load() ([]*unstructured.Unstructured, error)
-	Exists(image string) (string, error)
+	Load(paths ...string) ([]*unstructured.Unstructured, error)
+	Exists(key string) (string, error)
+	Path(key string) string
 }
 
 // LocalCache implements the Cache interface.
@@ -86,25 +87,34 @@ func (c *LocalCache) Flush() error {
 	return c.fs.RemoveAll(c.cacheDir)
 }
 
-// Load loads the schemas from the cache directory.
-func (c *LocalCache) Load() ([]*unstructured.Unstructured, error) {
-	loader, err := NewLoader(c.cacheDir)
-	if err != nil {
-		return nil, errors.Wrapf(err, "cannot create loader from the path %s", c.cacheDir)
-	}
+// Load loads the schemas stored at the supplied paths in the cache directory.
+func (c *LocalCache) Load(paths ...string) ([]*unstructured.Unstructured, error) {
+	var schemas []*unstructured.Unstructured
+	for _, path := range paths {
+		loader, err := NewLoader(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot create loader from the path %s", path)
+		}
 
-	schemas, err := loader.Load()
-	if err != nil {
-		return nil, errors.Wrapf(err, "cannot load schemas from the path %s", c.cacheDir)
+		s, err := loader.Load()
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load schemas from the path %s", path)
+		}
+
+		schemas = append(schemas, s...)
 	}
 
 	return schemas, nil
 }
 
-// Exists checks if the cache contains the image and returns the path if it doesn't exist.
-func (c *LocalCache) Exists(image string) (string, error) {
-	fName := strings.ReplaceAll(image, ":", "@")
-	path := filepath.Join(c.cacheDir, fName)
+// Path returns the path at which the schemas with the supplied key are cached.
+func (c *LocalCache) Path(key string) string {
+	return filepath.Join(c.cacheDir, strings.ReplaceAll(key, ":", "@"))
+}
+
+// Exists checks if the cache contains the key and returns the path if it doesn't exist.
+func (c *LocalCache) Exists(key string) (string, error) {
+	path := c.Path(key)
 
 	_, err := os.Stat(path)
 	if err != nil && os.IsNotExist(err) {
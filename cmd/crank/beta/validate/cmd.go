@@ -42,7 +42,9 @@ type Cmd struct {
 	// Flags. Keep them in alphabetical order.
 	CacheDir           string `default:"~/.crossplane/cache"                                                       help:"Absolute path to the cache directory where downloaded schemas are stored."`
 	CleanCache         bool   `help:"Clean the cache directory before downloading package schemas."`
+	NoCache            bool   `help:"Don't cache package schemas. Package schemas are downloaded every time."`
 	SkipSuccessResults bool   `help:"Skip printing success results."`
+	Strict             bool   `help:"Treat warnings, like resources without a schema, as errors when determining the exit code."`
 	CrossplaneImage    string `help:"Specify the Crossplane image to be used for validating the built-in schemas."`
 
 	fs afero.Fs
@@ -58,6 +60,13 @@ piped to this validate command in order to rapidly validate on the outputs of th
 If providers or configurations are provided as extensions, they will be downloaded and loaded as CRDs before performing
 validation. If the cache directory is not provided, it will default to "~/.crossplane/cache".
 Cache directory can be cleaned before downloading schemas by setting the "clean-cache" flag.
+Schemas are cached by package digest, so a package is downloaded again if its tag is moved
+to a different package. Caching can be disabled by setting the "no-cache" flag.
+
+The input of each step of a Composition's function pipeline is validated against the schema of its kind. Functions
+deliver these schemas as CRDs in their packages, so provide the Functions a Composition uses as extensions, or provide
+the input CRDs directly. Compositions may be provided as extensions or as resources. Inputs without a schema are
+reported as warnings.
 
 All validation is performed offline locally using the Kubernetes API server's validation library, so it does not require
 any Crossplane instance or control plane to be running or configured.
@@ -80,6 +89,10 @@ Examples:
   # Validate all resources in the resourceDir folder against the extensions in the extensionsDir folder using provided
   # cache directory and clean the cache directory before downloading schemas
   crossplane beta validate extensionsDir/ resourceDir/ --cache-dir .cache --clean-cache
+
+  # Validate all resources in the resourceDir folder against the extensions in the extensionsDir folder and fail if
+  # any resource has warnings, such as a missing schema
+  crossplane beta validate extensionsDir/ resourceDir/ --strict
 `
 }
 
@@ -126,22 +139,7 @@ func (c *Cmd) Run(k *kong.Context, _ logging.Logger) error {
 		c.CacheDir = filepath.Join(homeDir, c.CacheDir[2:])
 	}
 
-	m := NewManager(c.CacheDir, c.fs, k.Stdout, WithCrossplaneImage(c.CrossplaneImage))
+	m := NewManager(c.CacheDir, c.fs, k.Stdout, WithCrossplaneImage(c.CrossplaneImage), WithCacheDisabled(c.NoCache))
 
-	// Convert XRDs/CRDs to CRDs and add package dependencies
-	if err := m.PrepExtensions(extensions); err != nil {
-		return errors.Wrapf(err, "cannot prepare extensions")
-	}
-
-	// Download package base layers to cache and load them as CRDs
-	if err := m.CacheAndLoad(c.CleanCache); err != nil {
-		return errors.Wrapf(err, "cannot download and load cache")
-	}
-
-	// Validate resources against schemas
-	if err := SchemaValidation(resources, m.crds, c.SkipSuccessResults, k.Stdout); err != nil {
-		return errors.Wrapf(err, "cannot validate resources")
-	}
-
-	return nil
+	return m.Validate(extensions, resources, c.CleanCache, c.SkipSuccessResults, c.Strict)
 }
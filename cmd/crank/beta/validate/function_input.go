@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// A functionInput is the input of a Composition pipeline step.
+type functionInput struct {
+	composition string
+	step        string
+	path        *field.Path
+	input       *unstructured.Unstructured
+}
+
+// functionInputs returns the inputs of the pipeline steps of the supplied
+// Compositions. Other resources, and Compositions that appear more than once,
+// are ignored.
+func functionInputs(resources []*unstructured.Unstructured) []functionInput {
+	inputs := make([]functionInput, 0)
+	seen := make(map[string]bool)
+	for _, r := range resources {
+		if r.GroupVersionKind().GroupKind() != (schema.GroupKind{Group: "apiextensions.crossplane.io", Kind: "Composition"}) {
+			continue
+		}
+		if seen[r.GetName()] {
+			continue
+		}
+		seen[r.GetName()] = true
+
+		steps, _, _ := unstructured.NestedSlice(r.Object, "spec", "pipeline")
+		for i := range steps {
+			s, ok := steps[i].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			in, ok := s["input"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := s["step"].(string)
+			inputs = append(inputs, functionInput{
+				composition: r.GetName(),
+				step:        name,
+				path:        field.NewPath("spec", "pipeline").Index(i).Child("input"),
+				input:       &unstructured.Unstructured{Object: in},
+			})
+		}
+	}
+	return inputs
+}
+
+// FunctionInputValidation validates the input of each pipeline step of the
+// supplied Compositions against the schema of its kind. Functions deliver
+// these schemas as CRDs in their packages. Errors are reported with the path
+// of the invalid field within the Composition. Inputs without a schema are
+// reported as warnings. Warnings only cause validation to fail if strict is
+// true.
+func FunctionInputValidation(compositions []*unstructured.Unstructured, crds []*extv1.CustomResourceDefinition, skipSuccessLogs, strict bool, w io.Writer) error { //nolint:gocognit // printing the output increases the cyclomatic complexity a little bit
+	inputs := functionInputs(compositions)
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	schemaValidators, structurals, err := newValidatorsAndStructurals(crds)
+	if err != nil {
+		return errors.Wrap(err, "cannot create schema validators")
+	}
+
+	failure, missingSchemas := 0, 0
+
+	for _, in := range inputs {
+		gvk := in.input.GroupVersionKind()
+		sv, ok := schemaValidators[gvk]
+		s := structurals[gvk] // if we have a schema validator, we should also have a structural
+		if !ok {
+			missingSchemas++
+			if _, err := fmt.Fprintf(w, "[!] could not find CRD for the input of step %q of Composition %q: %s\n", in.step, in.composition, gvk.String()); err != nil {
+				return errors.Wrap(err, errWriteOutput)
+			}
+
+			continue
+		}
+
+		re := field.ErrorList{}
+		for _, v := range sv {
+			re = append(re, validation.ValidateCustomResource(in.path, in.input.UnstructuredContent(), *v)...)
+		}
+		for _, e := range validateUnknownFields(in.input.UnstructuredContent(), s) {
+			e.Field = in.path.String() + "." + e.Field
+			re = append(re, e)
+		}
+
+		celValidator := cel.NewValidator(s, true, celconfig.PerCallLimit)
+		ce, _ := celValidator.Validate(context.TODO(), in.path, s, in.input.Object, nil, celconfig.PerCallLimit)
+		re = append(re, ce...)
+
+		for _, e := range re {
+			if _, err := fmt.Fprintf(w, "[x] schema validation error in the input of step %q of Composition %q, %s : %s\n", in.step, in.composition, gvk.String(), e.Error()); err != nil {
+				return errors.Wrap(err, errWriteOutput)
+			}
+		}
+
+		if len(re) > 0 {
+			failure++
+			continue
+		}
+
+		if !skipSuccessLogs {
+			if _, err := fmt.Fprintf(w, "[✓] the input of step %q of Composition %q validated successfully\n", in.step, in.composition); err != nil {
+				return errors.Wrap(err, errWriteOutput)
+			}
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "Total %d function inputs: %d missing schemas, %d success cases, %d failure cases\n", len(inputs), missingSchemas, len(inputs)-failure-missingSchemas, failure); err != nil {
+		return errors.Wrap(err, errWriteOutput)
+	}
+
+	if failure > 0 {
+		return errors.New("could not validate all function inputs")
+	}
+
+	if strict && missingSchemas > 0 {
+		return errors.New("could not validate all function inputs: missing schemas are errors in strict mode")
+	}
+
+	return nil
+}
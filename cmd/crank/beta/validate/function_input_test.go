@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestFunctionInputValidation(t *testing.T) {
+	composition := func(input map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "apiextensions.crossplane.io/v1",
+				"kind":       "Composition",
+				"metadata": map[string]interface{}{
+					"name": "cool-composition",
+				},
+				"spec": map[string]interface{}{
+					"mode": "Pipeline",
+					"pipeline": []interface{}{
+						map[string]interface{}{
+							"step":        "cool-step",
+							"functionRef": map[string]interface{}{"name": "cool-function"},
+							"input":       input,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	type args struct {
+		compositions []*unstructured.Unstructured
+		crds         []*extv1.CustomResourceDefinition
+		strict       bool
+	}
+	type want struct {
+		output string
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoCompositions": {
+			reason: "Should not return an error or write anything if there are no Compositions",
+			args: args{
+				compositions: []*unstructured.Unstructured{},
+				crds:         []*extv1.CustomResourceDefinition{testCRD},
+			},
+		},
+		"Valid": {
+			reason: "Should not return an error if the function inputs are valid",
+			args: args{
+				compositions: []*unstructured.Unstructured{
+					composition(map[string]interface{}{
+						"apiVersion": "test.org/v1alpha1",
+						"kind":       "Test",
+						"spec": map[string]interface{}{
+							"replicas": int64(1),
+						},
+					}),
+				},
+				crds: []*extv1.CustomResourceDefinition{testCRD},
+			},
+			want: want{
+				output: `[✓] the input of step "cool-step" of Composition "cool-composition" validated successfully`,
+			},
+		},
+		"Invalid": {
+			reason: "Should return an error, and report the path of the invalid field, if a function input is invalid",
+			args: args{
+				compositions: []*unstructured.Unstructured{
+					composition(map[string]interface{}{
+						"apiVersion": "test.org/v1alpha1",
+						"kind":       "Test",
+						"spec": map[string]interface{}{
+							"replicas": "non-integer",
+						},
+					}),
+				},
+				crds: []*extv1.CustomResourceDefinition{testCRD},
+			},
+			want: want{
+				output: "spec.pipeline[0].input.spec.replicas",
+				err:    errors.New("could not validate all function inputs"),
+			},
+		},
+		"MissingCRD": {
+			reason: "Should not return an error if a function input's CRD is missing",
+			args: args{
+				compositions: []*unstructured.Unstructured{
+					composition(map[string]interface{}{
+						"apiVersion": "test.org/v1alpha1",
+						"kind":       "Test",
+					}),
+				},
+				crds: []*extv1.CustomResourceDefinition{},
+			},
+			want: want{
+				output: `[!] could not find CRD for the input of step "cool-step" of Composition "cool-composition"`,
+			},
+		},
+		"MissingCRDStrict": {
+			reason: "Should return an error if a function input's CRD is missing in strict mode",
+			args: args{
+				compositions: []*unstructured.Unstructured{
+					composition(map[string]interface{}{
+						"apiVersion": "test.org/v1alpha1",
+						"kind":       "Test",
+					}),
+				},
+				crds:   []*extv1.CustomResourceDefinition{},
+				strict: true,
+			},
+			want: want{
+				output: `[!] could not find CRD for the input of step "cool-step" of Composition "cool-composition"`,
+				err:    errors.New("could not validate all function inputs: missing schemas are errors in strict mode"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			got := FunctionInputValidation(tc.args.compositions, tc.args.crds, false, tc.args.strict, w)
+
+			if diff := cmp.Diff(tc.want.err, got, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nFunctionInputValidation(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if !strings.Contains(w.String(), tc.want.output) {
+				t.Errorf("%s\nFunctionInputValidation(...): want output to contain %q, got:\n%s", tc.reason, tc.want.output, w.String())
+			}
+		})
+	}
+}
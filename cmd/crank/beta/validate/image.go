@@ -40,6 +40,7 @@ const maxDecompressedSize = 200 * 1024 * 1024 // 200 MB
 type ImageFetcher interface {
 	FetchBaseLayer(image string) (*conregv1.Layer, error)
 	FetchImage(image string) ([]conregv1.Layer, error)
+	FetchDigest(image string) (string, error)
 }
 
 // Fetcher implements the ImageFetcher interface.
@@ -67,6 +68,22 @@ func (f *Fetcher) FetchImage(image string) ([]conregv1.Layer, error) {
 	return layers, nil
 }
 
+// FetchDigest returns the digest of the image, which uniquely identifies the
+// package's contents.
+func (f *Fetcher) FetchDigest(image string) (string, error) {
+	if _, d, ok := strings.Cut(image, "@"); ok {
+		return d, nil
+	}
+
+	image, err := prepareImageReference(image)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to prepare image reference")
+	}
+
+	d, err := crane.Digest(image)
+	return d, errors.Wrapf(err, "cannot get digest")
+}
+
 // BaseLayerNotFoundError is returned when the base layer of the image could not be found.
 type BaseLayerNotFoundError struct {
 	image string
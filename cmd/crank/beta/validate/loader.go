@@ -18,6 +18,7 @@ package validate
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"io"
 	"os"
@@ -68,6 +69,35 @@ func (s *StdinLoader) Load() ([]*unstructured.Unstructured, error) {
 	return streamToUnstructured(stream)
 }
 
+// ReaderLoader implements the Loader interface for reading from an io.Reader.
+type ReaderLoader struct {
+	r io.Reader
+}
+
+// NewReaderLoader returns a Loader that reads a YAML stream from the supplied
+// io.Reader.
+func NewReaderLoader(r io.Reader) *ReaderLoader {
+	return &ReaderLoader{r: r}
+}
+
+// Load reads the contents from the io.Reader.
+func (l *ReaderLoader) Load() ([]*unstructured.Unstructured, error) {
+	stream, err := load(l.r)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load YAML stream")
+	}
+
+	// Skip empty documents, like those between consecutive separators.
+	docs := make([][]byte, 0, len(stream))
+	for _, d := range stream {
+		if len(bytes.TrimSpace(d)) > 0 {
+			docs = append(docs, d)
+		}
+	}
+
+	return streamToUnstructured(docs)
+}
+
 // FileLoader implements the Loader interface for reading from a file and converting input to unstructured objects.
 type FileLoader struct {
 	path string
@@ -17,6 +17,7 @@ limitations under the License.
 package validate
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -110,6 +111,79 @@ func TestFileLoaderLoad(t *testing.T) {
 	}
 }
 
+func TestReaderLoaderLoad(t *testing.T) {
+	type args struct {
+		stream string
+	}
+	type want struct {
+		resources []*unstructured.Unstructured
+		err       error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "Successfully load resources from a reader",
+			args: args{
+				stream: `
+---
+apiVersion: example.org/v1alpha1
+kind: ComposedResource
+metadata:
+  name: test-validate-a
+  annotations:
+    crossplane.io/composition-resource-name: resource-a
+spec:
+  coolField: "I'm cool!"
+---
+apiVersion: example.org/v1alpha1
+kind: ComposedResource
+metadata:
+  name: test-validate-b
+  annotations:
+    crossplane.io/composition-resource-name: resource-b
+spec:
+  coolerField: "I'm cooler!"
+`,
+			},
+			want: want{
+				resources: []*unstructured.Unstructured{
+					{
+						Object: coolResource,
+					},
+					{
+						Object: coolerResource,
+					},
+				},
+			},
+		},
+		"Error": {
+			reason: "Error loading an invalid manifest from a reader",
+			args: args{
+				stream: "apiVersion: [",
+			},
+			want: want{
+				resources: nil,
+				err:       cmpopts.AnyError,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewReaderLoader(strings.NewReader(tc.args.stream)).Load()
+			if diff := cmp.Diff(tc.want.resources, got); diff != "" {
+				t.Errorf("%s\nLoad(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s\nLoad(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestFolderLoaderLoad(t *testing.T) {
 	type args struct {
 		Path string
@@ -17,8 +17,10 @@ limitations under the License.
 package validate
 
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/afero"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -48,9 +50,13 @@ type Manager struct {
 	cache   Cache
 	writer  io.Writer
 
-	crds  []*extv1.CustomResourceDefinition
-	deps  map[string]bool                  // Dependency images
-	confs map[string]*metav1.Configuration // Configuration images
+	noCache bool
+
+	crds    []*extv1.CustomResourceDefinition
+	deps    map[string]bool                  // Dependency images
+	confs   map[string]*metav1.Configuration // Configuration images
+	cached  []string                         // Cache paths of dependency schemas
+	schemas []*unstructured.Unstructured     // Dependency schemas that weren't cached
 }
 
 // Option defines an option for the Manager.
@@ -66,6 +72,14 @@ func WithCrossplaneImage(image string) Option {
 	}
 }
 
+// WithCacheDisabled disables caching of package schemas. Package schemas are
+// downloaded every time they're needed when caching is disabled.
+func WithCacheDisabled(disabled bool) Option {
+	return func(m *Manager) {
+		m.noCache = disabled
+	}
+}
+
 // NewManager returns a new Manager.
 func NewManager(cacheDir string, fs afero.Fs, w io.Writer, opts ...Option) *Manager {
 	m := &Manager{}
@@ -179,6 +193,36 @@ func (m *Manager) PrepExtensions(extensions []*unstructured.Unstructured) error
 	return nil
 }
 
+// Validate validates the supplied resources against the schemas of the
+// supplied extensions, and of any packages they depend on. It writes the
+// result of validating each resource, and returns an error if any resource
+// is invalid.
+func (m *Manager) Validate(extensions, resources []*unstructured.Unstructured, cleanCache, skipSuccessResults, strict bool) error {
+	// Convert XRDs/CRDs to CRDs and add package dependencies
+	if err := m.PrepExtensions(extensions); err != nil {
+		return errors.Wrapf(err, "cannot prepare extensions")
+	}
+
+	// Download package base layers to cache and load them as CRDs
+	if err := m.CacheAndLoad(cleanCache); err != nil {
+		return errors.Wrapf(err, "cannot download and load cache")
+	}
+
+	// Validate resources against schemas
+	rerr := SchemaValidation(resources, m.crds, skipSuccessResults, strict, m.writer)
+
+	// Validate the inputs of Composition pipeline steps against the schemas
+	// their Functions deliver. Compositions may be supplied as extensions or
+	// as resources.
+	ierr := FunctionInputValidation(append(extensions, resources...), m.crds, skipSuccessResults, strict, m.writer)
+
+	if rerr != nil {
+		return errors.Wrapf(rerr, "cannot validate resources")
+	}
+
+	return errors.Wrapf(ierr, "cannot validate function inputs")
+}
+
 // CacheAndLoad finds and caches dependencies and loads them as CRDs.
 func (m *Manager) CacheAndLoad(cleanCache bool) error {
 	if cleanCache {
@@ -187,8 +231,10 @@ func (m *Manager) CacheAndLoad(cleanCache bool) error {
 		}
 	}
 
-	if err := m.cache.Init(); err != nil {
-		return errors.Wrapf(err, "cannot initialize cache directory")
+	if !m.noCache {
+		if err := m.cache.Init(); err != nil {
+			return errors.Wrapf(err, "cannot initialize cache directory")
+		}
 	}
 
 	if err := m.addDependencies(m.confs); err != nil {
@@ -199,12 +245,12 @@ func (m *Manager) CacheAndLoad(cleanCache bool) error {
 		return errors.Wrapf(err, "cannot cache package dependencies")
 	}
 
-	schemas, err := m.cache.Load()
+	schemas, err := m.cache.Load(m.cached...)
 	if err != nil {
 		return errors.Wrapf(err, "cannot load cache")
 	}
 
-	return m.PrepExtensions(schemas)
+	return m.PrepExtensions(append(schemas, m.schemas...))
 }
 
 func (m *Manager) addDependencies(confs map[string]*metav1.Configuration) error {
@@ -264,12 +310,35 @@ func (m *Manager) addDependencies(confs map[string]*metav1.Configuration) error
 }
 
 func (m *Manager) cacheDependencies() error {
-	if err := m.cache.Init(); err != nil {
-		return errors.Wrapf(err, "cannot initialize  cache directory")
-	}
-
 	for image := range m.deps {
-		path, err := m.cache.Exists(image) // returns the path if the image is not cached
+		if m.noCache {
+			if _, err := fmt.Fprintln(m.writer, "cache is disabled, downloading: ", image); err != nil {
+				return errors.Wrapf(err, errWriteOutput)
+			}
+
+			schemas, err := m.fetchSchemas(image)
+			if err != nil {
+				return err
+			}
+
+			u, err := NewReaderLoader(bytes.NewReader(bytes.Join(schemas, []byte("\n---\n")))).Load()
+			if err != nil {
+				return errors.Wrapf(err, "cannot load schemas of %s", image)
+			}
+			m.schemas = append(m.schemas, u...)
+			continue
+		}
+
+		// Schemas are cached by the package's digest, not its tag. A tag may
+		// be moved to a different package, but a digest may not.
+		digest, err := m.fetcher.FetchDigest(image)
+		if err != nil {
+			return errors.Wrapf(err, "cannot get digest of %s", image)
+		}
+		key := fmt.Sprintf(refFmt, packageRepository(image), digest)
+		m.cached = append(m.cached, m.cache.Path(key))
+
+		path, err := m.cache.Exists(key) // returns the path if the image is not cached
 		if err != nil {
 			return errors.Wrapf(err, "cannot check if cache exists for %s", image)
 		}
@@ -282,27 +351,9 @@ func (m *Manager) cacheDependencies() error {
 			return errors.Wrapf(err, errWriteOutput)
 		}
 
-		var schemas [][]byte
-		// handling for packages
-		layer, err := m.fetcher.FetchBaseLayer(image)
-		switch {
-		case IsErrBaseLayerNotFound(err):
-			// We fall back to fetching the image if the base layer is not found
-			layers, err := m.fetcher.FetchImage(image)
-			if err != nil {
-				return errors.Wrapf(err, "cannot extract crds")
-			}
-			schemas, err = extractPackageCRDs(layers)
-			if err != nil {
-				return errors.Wrapf(err, "cannot find crds")
-			}
-		case err != nil:
-			return errors.Wrapf(err, "cannot download package %s", image)
-		default:
-			schemas, _, err = extractPackageContent(*layer)
-			if err != nil {
-				return errors.Wrapf(err, "cannot extract package file and meta")
-			}
+		schemas, err := m.fetchSchemas(image)
+		if err != nil {
+			return err
 		}
 
 		if err := m.cache.Store(schemas, path); err != nil {
@@ -312,3 +363,36 @@ func (m *Manager) cacheDependencies() error {
 
 	return nil
 }
+
+// fetchSchemas fetches the schemas of the supplied package image.
+func (m *Manager) fetchSchemas(image string) ([][]byte, error) {
+	// handling for packages
+	layer, err := m.fetcher.FetchBaseLayer(image)
+	switch {
+	case IsErrBaseLayerNotFound(err):
+		// We fall back to fetching the image if the base layer is not found
+		layers, err := m.fetcher.FetchImage(image)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot extract crds")
+		}
+		schemas, err := extractPackageCRDs(layers)
+		return schemas, errors.Wrapf(err, "cannot find crds")
+	case err != nil:
+		return nil, errors.Wrapf(err, "cannot download package %s", image)
+	default:
+		schemas, _, err := extractPackageContent(*layer)
+		return schemas, errors.Wrapf(err, "cannot extract package file and meta")
+	}
+}
+
+// packageRepository returns the supplied package image without its tag or
+// digest.
+func packageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+	return image
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/afero"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
@@ -333,7 +334,7 @@ func TestConfigurationTypeSupport(t *testing.T) {
 
 		m := NewManager("", fs, w)
 		t.Run(name, func(t *testing.T) {
-			m.fetcher = &MockFetcher{tc.args.fetchMock, nil}
+			m.fetcher = &MockFetcher{fetchBaseLayer: tc.args.fetchMock}
 			err := m.PrepExtensions(tc.args.extensions)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -444,7 +445,7 @@ func TestAddDependencies(t *testing.T) {
 			//   └─►function-dep-1
 			reason: "All dependencies should be successfully fetched and added without specifying a crossplane image",
 			args: args{
-				fetcher: &MockFetcher{fetchMockFunc, nil},
+				fetcher: &MockFetcher{fetchBaseLayer: fetchMockFunc},
 				extensions: []*unstructured.Unstructured{
 					{
 						Object: map[string]interface{}{
@@ -492,9 +493,125 @@ func TestAddDependencies(t *testing.T) {
 	}
 }
 
+func TestCacheDependencies(t *testing.T) {
+	errBoom := errors.New("boom")
+	pkg := static.NewLayer([]byte(`apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-test
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: test
+---
+signature
+`), types.OCILayer)
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata": map[string]interface{}{
+			"name": "test",
+		},
+	}}
+
+	type args struct {
+		noCache bool
+		fetcher ImageFetcher
+	}
+	type want struct {
+		cached  []string
+		schemas []*unstructured.Unstructured
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"CacheByDigest": {
+			reason: "Schemas should be cached by the package's digest, not its tag",
+			args: args{
+				fetcher: &MockFetcher{
+					fetchBaseLayer: func(_ string) (*conregv1.Layer, error) { return &pkg, nil },
+					fetchDigest:    func(_ string) (string, error) { return "sha256:cool", nil },
+				},
+			},
+			want: want{
+				cached: []string{"testdata/cache/xpkg.upbound.io/crossplane-contrib/provider-test@sha256@cool"},
+			},
+		},
+		"CacheDisabled": {
+			reason: "Schemas should be downloaded, and not cached, when the cache is disabled",
+			args: args{
+				noCache: true,
+				fetcher: &MockFetcher{
+					fetchBaseLayer: func(_ string) (*conregv1.Layer, error) { return &pkg, nil },
+				},
+			},
+			want: want{
+				schemas: []*unstructured.Unstructured{crd},
+			},
+		},
+		"DigestError": {
+			reason: "We should return an error if we can't get the package's digest",
+			args: args{
+				fetcher: &MockFetcher{
+					fetchDigest: func(_ string) (string, error) { return "", errBoom },
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, "cannot get digest of %s", "xpkg.upbound.io/crossplane-contrib/provider-test:v1.3.0"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			w := &bytes.Buffer{}
+
+			m := NewManager("testdata/cache", fs, w, WithCacheDisabled(tc.args.noCache))
+			m.deps["xpkg.upbound.io/crossplane-contrib/provider-test:v1.3.0"] = true
+			m.fetcher = tc.args.fetcher
+
+			err := m.cacheDependencies()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncacheDependencies(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cached, m.cached); diff != "" {
+				t.Errorf("\n%s\ncacheDependencies(): -want cached, +got cached:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.schemas, m.schemas); diff != "" {
+				t.Errorf("\n%s\ncacheDependencies(): -want schemas, +got schemas:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPackageRepository(t *testing.T) {
+	cases := map[string]struct {
+		image string
+		want  string
+	}{
+		"Tag":            {image: "xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.0", want: "xpkg.upbound.io/crossplane-contrib/provider-nop"},
+		"Digest":         {image: "xpkg.upbound.io/crossplane-contrib/provider-nop@sha256:cool", want: "xpkg.upbound.io/crossplane-contrib/provider-nop"},
+		"RegistryPort":   {image: "localhost:5000/provider-nop", want: "localhost:5000/provider-nop"},
+		"NoTagOrDigest":  {image: "xpkg.upbound.io/crossplane-contrib/provider-nop", want: "xpkg.upbound.io/crossplane-contrib/provider-nop"},
+		"PortAndVersion": {image: "localhost:5000/provider-nop:>=v0.2.0", want: "localhost:5000/provider-nop"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, packageRepository(tc.image)); diff != "" {
+				t.Errorf("packageRepository(%q): -want, +got:\n%s", tc.image, diff)
+			}
+		})
+	}
+}
+
 type MockFetcher struct {
 	fetchBaseLayer func(image string) (*conregv1.Layer, error)
 	fetchImage     func(image string) ([]conregv1.Layer, error)
+	fetchDigest    func(image string) (string, error)
 }
 
 func (m *MockFetcher) FetchBaseLayer(image string) (*conregv1.Layer, error) {
@@ -507,3 +624,7 @@ func (m *MockFetcher) FetchImage(image string) ([]conregv1.Layer, error) {
 	}
 	return nil, nil // or a sensible default/mock behavior
 }
+
+func (m *MockFetcher) FetchDigest(image string) (string, error) {
+	return m.fetchDigest(image)
+}
@@ -91,8 +91,10 @@ func newValidatorsAndStructurals(crds []*extv1.CustomResourceDefinition) (map[ru
 	return validators, structurals, nil
 }
 
-// SchemaValidation validates the resources against the given CRDs.
-func SchemaValidation(resources []*unstructured.Unstructured, crds []*extv1.CustomResourceDefinition, skipSuccessLogs bool, w io.Writer) error { //nolint:gocognit // printing the output increases the cyclomatic complexity a little bit
+// SchemaValidation validates the resources against the given CRDs. Resources
+// without a schema are reported as warnings. Warnings only cause validation to
+// fail if strict is true.
+func SchemaValidation(resources []*unstructured.Unstructured, crds []*extv1.CustomResourceDefinition, skipSuccessLogs, strict bool, w io.Writer) error { //nolint:gocognit // printing the output increases the cyclomatic complexity a little bit
 	schemaValidators, structurals, err := newValidatorsAndStructurals(crds)
 	if err != nil {
 		return errors.Wrap(err, "cannot create schema validators")
@@ -134,12 +136,14 @@ func SchemaValidation(resources []*unstructured.Unstructured, crds []*extv1.Cust
 				}
 			}
 
-			if rf == 0 && !skipSuccessLogs {
+			if rf > 0 {
+				failure++
+				continue
+			}
+			if !skipSuccessLogs {
 				if _, err := fmt.Fprintf(w, "[✓] %s, %s validated successfully\n", r.GroupVersionKind().String(), getResourceName(r)); err != nil {
 					return errors.Wrap(err, errWriteOutput)
 				}
-			} else {
-				failure++
 			}
 		}
 	}
@@ -152,6 +156,10 @@ func SchemaValidation(resources []*unstructured.Unstructured, crds []*extv1.Cust
 		return errors.New("could not validate all resources")
 	}
 
+	if strict && missingSchemas > 0 {
+		return errors.New("could not validate all resources: missing schemas are errors in strict mode")
+	}
+
 	return nil
 }
 
@@ -419,6 +419,10 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"compositionRevisionHash": {
+															Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+															Type:        "string",
+														},
 														"compositionUpdatePolicy": {
 															Type: "string",
 															Enum: []extv1.JSON{
@@ -523,12 +527,75 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"lastHandledReconcileAt": {
+															Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+															Type:        "string",
+														},
+														"functionState": {
+															Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+															Type:                   "object",
+															XPreserveUnknownFields: ptr.To(true),
+														},
 														"connectionDetails": {
 															Type: "object",
 															Properties: map[string]extv1.JSONSchemaProps{
 																"lastPublishedTime": {Type: "string", Format: "date-time"},
 															},
 														},
+														"resourceReadiness": {
+															Description: "Readiness of the composed resources as of the most recent reconcile.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"ready": {Type: "integer"},
+																"total": {Type: "integer"},
+																"unready": {
+																	Type: "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type: "string",
+																		},
+																	},
+																},
+															},
+														},
+														"pipelineDebug": {
+															Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+															Type:        "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"step"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"request": {
+																			Type:                   "object",
+																			XPreserveUnknownFields: ptr.To(true),
+																		},
+																		"response": {
+																			Type:                   "object",
+																			XPreserveUnknownFields: ptr.To(true),
+																		},
+																		"step":      {Type: "string"},
+																		"truncated": {Type: "boolean"},
+																	},
+																},
+															},
+														},
+														"pipelineResults": {
+															Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+															Type:        "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"message", "severity", "step"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"message":  {Type: "string"},
+																		"reason":   {Type: "string"},
+																		"severity": {Type: "string"},
+																		"step":     {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -757,6 +824,10 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"compositionRevisionHash": {
+															Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+															Type:        "string",
+														},
 														"compositionUpdatePolicy": {
 															Type: "string",
 															Enum: []extv1.JSON{
@@ -861,12 +932,75 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"lastHandledReconcileAt": {
+															Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+															Type:        "string",
+														},
+														"functionState": {
+															Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+															Type:                   "object",
+															XPreserveUnknownFields: ptr.To(true),
+														},
 														"connectionDetails": {
 															Type: "object",
 															Properties: map[string]extv1.JSONSchemaProps{
 																"lastPublishedTime": {Type: "string", Format: "date-time"},
 															},
 														},
+														"resourceReadiness": {
+															Description: "Readiness of the composed resources as of the most recent reconcile.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"ready": {Type: "integer"},
+																"total": {Type: "integer"},
+																"unready": {
+																	Type: "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type: "string",
+																		},
+																	},
+																},
+															},
+														},
+														"pipelineDebug": {
+															Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+															Type:        "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"step"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"request": {
+																			Type:                   "object",
+																			XPreserveUnknownFields: ptr.To(true),
+																		},
+																		"response": {
+																			Type:                   "object",
+																			XPreserveUnknownFields: ptr.To(true),
+																		},
+																		"step":      {Type: "string"},
+																		"truncated": {Type: "boolean"},
+																	},
+																},
+															},
+														},
+														"pipelineResults": {
+															Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+															Type:        "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"message", "severity", "step"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"message":  {Type: "string"},
+																		"reason":   {Type: "string"},
+																		"severity": {Type: "string"},
+																		"step":     {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -1017,6 +1151,10 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"compositionRevisionHash": {
+															Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+															Type:        "string",
+														},
 														"compositionUpdatePolicy": {
 															Type: "string",
 															Enum: []extv1.JSON{
@@ -1114,12 +1252,75 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"lastHandledReconcileAt": {
+															Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+															Type:        "string",
+														},
+														"functionState": {
+															Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+															Type:                   "object",
+															XPreserveUnknownFields: ptr.To(true),
+														},
 														"connectionDetails": {
 															Type: "object",
 															Properties: map[string]extv1.JSONSchemaProps{
 																"lastPublishedTime": {Type: "string", Format: "date-time"},
 															},
 														},
+														"resourceReadiness": {
+															Description: "Readiness of the composed resources as of the most recent reconcile.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"ready": {Type: "integer"},
+																"total": {Type: "integer"},
+																"unready": {
+																	Type: "array",
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type: "string",
+																		},
+																	},
+																},
+															},
+														},
+														"pipelineDebug": {
+															Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+															Type:        "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"step"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"request": {
+																			Type:                   "object",
+																			XPreserveUnknownFields: ptr.To(true),
+																		},
+																		"response": {
+																			Type:                   "object",
+																			XPreserveUnknownFields: ptr.To(true),
+																		},
+																		"step":      {Type: "string"},
+																		"truncated": {Type: "boolean"},
+																	},
+																},
+															},
+														},
+														"pipelineResults": {
+															Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+															Type:        "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"message", "severity", "step"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"message":  {Type: "string"},
+																		"reason":   {Type: "string"},
+																		"severity": {Type: "string"},
+																		"step":     {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -1173,6 +1374,7 @@ func TestValidateResources(t *testing.T) {
 	type args struct {
 		resources []*unstructured.Unstructured
 		crds      []*extv1.CustomResourceDefinition
+		strict    bool
 	}
 	type want struct {
 		err error
@@ -1300,11 +1502,35 @@ func TestValidateResources(t *testing.T) {
 				crds: []*extv1.CustomResourceDefinition{},
 			},
 		},
+		"MissingCRDStrict": {
+			reason: "Should return an error if the CRD/XRD is missing in strict mode",
+			args: args{
+				resources: []*unstructured.Unstructured{
+					{
+						Object: map[string]interface{}{
+							"apiVersion": "test.org/v1alpha1",
+							"kind":       "Test",
+							"metadata": map[string]interface{}{
+								"name": "test",
+							},
+							"spec": map[string]interface{}{
+								"replicas": 1,
+							},
+						},
+					},
+				},
+				crds:   []*extv1.CustomResourceDefinition{},
+				strict: true,
+			},
+			want: want{
+				err: errors.New("could not validate all resources: missing schemas are errors in strict mode"),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			w := &bytes.Buffer{}
-			got := SchemaValidation(tc.args.resources, tc.args.crds, false, w)
+			got := SchemaValidation(tc.args.resources, tc.args.crds, false, tc.args.strict, w)
 
 			if diff := cmp.Diff(tc.want.err, got, test.EquateErrors()); diff != "" {
 				t.Errorf("%s\nvalidateResources(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -20,38 +20,50 @@ package render
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/spf13/afero"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 )
 
 // Cmd arguments and flags for render subcommand.
 type Cmd struct {
 	// Arguments.
 	CompositeResource string `arg:"" help:"A YAML file specifying the composite resource (XR) to render."                                        type:"existingfile"`
-	Composition       string `arg:"" help:"A YAML file specifying the Composition to use to render the XR. Must be mode: Pipeline."              type:"existingfile"`
-	Functions         string `arg:"" help:"A YAML file or directory of YAML files specifying the Composition Functions to use to render the XR." type:"path"`
+	Composition       string `arg:"" help:"A YAML file specifying the Composition to use to render the XR. Must be mode: Pipeline. Omit when using --package."                          optional:"" type:"existingfile"`
+	Functions         string `arg:"" help:"A YAML file or directory of YAML files specifying the Composition Functions to use to render the XR. Omit when using --package." optional:"" type:"path"`
 
 	// Flags. Keep them in alphabetical order.
-	ContextFiles           map[string]string `help:"Comma-separated context key-value pairs to pass to the Function pipeline. Values must be files containing JSON."                           mapsep:""`
+	ContextFiles           map[string]string `help:"Comma-separated context key-value pairs to pass to the Function pipeline. Values must be files containing JSON or YAML."                   mapsep:""`
 	ContextValues          map[string]string `help:"Comma-separated context key-value pairs to pass to the Function pipeline. Values must be JSON. Keys take precedence over --context-files." mapsep:""`
+	EmitEvents             bool              `help:"Print informational and warning messages from Functions to stderr, keeping the rendered output on stdout clean."`
 	IncludeFunctionResults bool              `help:"Include informational and warning messages from Functions in the rendered output as resources of kind: Result."                            short:"r"`
 	IncludeFullXR          bool              `help:"Include a direct copy of the input XR's spec and metadata fields in the rendered output."                                                  short:"x"`
+	IncludeUsageOrder      bool              `help:"Include the deletion ordering implied by any composed Usages in the rendered output as a resource of kind: UsageOrder."`
 	ObservedResources      string            `help:"A YAML file or directory of YAML files specifying the observed state of composed resources."                                               placeholder:"PATH" short:"o"   type:"path"`
 	ExtraResources         string            `help:"A YAML file or directory of YAML files specifying extra resources to pass to the Function pipeline."                                       placeholder:"PATH" short:"e"   type:"path"`
 	IncludeContext         bool              `help:"Include the context in the rendered output as a resource of kind: Context."                                                                short:"c"`
 	FunctionCredentials    string            `help:"A YAML file or directory of YAML files specifying credentials to use for Functions to render the XR."                                      placeholder:"PATH" type:"path"`
+	MergeObserved          bool              `help:"Render repeatedly, merging each iteration's desired state into the observed state of the next, until the output stops changing."`
+	Iterations             int               `default:"10"                                                                                                                                     help:"Maximum number of iterations to render with --merge-observed."`
+	Package                string            `help:"A Configuration package file (.xpkg) to load the Composition and Functions from, instead of YAML files."                                   placeholder:"PATH" type:"existingfile"`
+	PackageComposition     string            `help:"The name of the Composition to use when --package contains more than one Composition for the XR's type."                                 placeholder:"NAME"`
+	ValidateSchemas        string            `help:"A YAML file or directory of YAML files specifying CRDs or XRDs to validate the rendered composed resources against."                       placeholder:"PATH" type:"path"`
 
 	Timeout time.Duration `default:"1m" help:"How long to run before timing out."`
 
@@ -94,6 +106,17 @@ the following annotations to each Function to change how they're run:
     Always pull the Function's package, even if it already exists locally.
 	Other supported values are Never, or IfNotPresent.
 
+Use --context-files and --context-values to seed the Function pipeline's
+context before the first Function runs, for example to test a Function that
+reads context written by an earlier Function in isolation. Each flag takes
+comma-separated key=value pairs, where each key is a context key. The values of
+--context-files are paths to files that each contain a single JSON or YAML
+value, like an object, a string, or a number. The values of --context-values are
+JSON. A key passed to --context-values takes precedence over the same key passed
+to --context-files. Each Function receives the context returned by the previous
+Function, so seeded context keys are passed along the pipeline until a Function
+overwrites or removes them.
+
 Use the standard DOCKER_HOST, DOCKER_API_VERSION, DOCKER_CERT_PATH, and
 DOCKER_TLS_VERIFY environment variables to configure how this command connects
 to the Docker daemon.
@@ -111,13 +134,41 @@ Examples:
   crossplane render xr.yaml composition.yaml functions.yaml \
     --context-values=apiextensions.crossplane.io/environment='{"key": "value"}'
 
+  # Pass context values loaded from files to the Function pipeline.
+  crossplane render xr.yaml composition.yaml functions.yaml \
+    --context-files=apiextensions.crossplane.io/environment=environment.yaml
+
   # Pass extra resources Functions in the pipeline can request.
   crossplane render xr.yaml composition.yaml functions.yaml \
 	--extra-resources=extra-resources.yaml
 
+  # Print warnings and other messages from Functions to stderr.
+  crossplane render xr.yaml composition.yaml functions.yaml --emit-events
+
   # Pass credentials to Functions in the pipeline that need them.
   crossplane render xr.yaml composition.yaml functions.yaml \
 	--function-credentials=credentials.yaml
+
+  # Render repeatedly, feeding each iteration's desired state back in as
+  # observed state, to check that the Composition converges.
+  crossplane render xr.yaml composition.yaml functions.yaml \
+	--merge-observed --iterations=5
+
+  # Render an XR using the Composition and Functions in a built Configuration
+  # package. The package's Function dependencies must use exact versions.
+  crossplane render xr.yaml --package=configuration.xpkg
+
+  # Pick a Composition when the package has several for the XR's type.
+  crossplane render xr.yaml --package=configuration.xpkg \
+	--package-composition=xnopresources-large
+
+  # Show the order in which composed Usages would cause resources to be deleted.
+  crossplane render xr.yaml composition.yaml functions.yaml \
+	--include-usage-order
+
+  # Validate the rendered composed resources against their CRDs.
+  crossplane render xr.yaml composition.yaml functions.yaml \
+	--validate-schemas=crds/
 `
 }
 
@@ -134,9 +185,9 @@ func (c *Cmd) Run(k *kong.Context, log logging.Logger) error { //nolint:gocognit
 		return errors.Wrapf(err, "cannot load composite resource from %q", c.CompositeResource)
 	}
 
-	comp, err := LoadComposition(c.fs, c.Composition)
+	comp, fns, err := c.loadCompositionAndFunctions(xr)
 	if err != nil {
-		return errors.Wrapf(err, "cannot load Composition from %q", c.Composition)
+		return err
 	}
 
 	// Validate that Composition's compositeTypeRef matches the XR's GroupVersionKind.
@@ -178,11 +229,6 @@ func (c *Cmd) Run(k *kong.Context, log logging.Logger) error { //nolint:gocognit
 		return errors.Errorf("render only supports Composition Function pipelines: Composition %q must use spec.mode: Pipeline", comp.GetName())
 	}
 
-	fns, err := LoadFunctions(c.fs, c.Functions)
-	if err != nil {
-		return errors.Wrapf(err, "cannot load functions from %q", c.Functions)
-	}
-
 	fcreds := []corev1.Secret{}
 	if c.FunctionCredentials != "" {
 		fcreds, err = LoadCredentials(c.fs, c.FunctionCredentials)
@@ -207,22 +253,26 @@ func (c *Cmd) Run(k *kong.Context, log logging.Logger) error { //nolint:gocognit
 		}
 	}
 
-	fctx := map[string][]byte{}
-	for k, filename := range c.ContextFiles {
-		v, err := afero.ReadFile(c.fs, filename)
-		if err != nil {
-			return errors.Wrapf(err, "cannot read context value for key %q", k)
-		}
-		fctx[k] = v
+	fctx, err := LoadContextFiles(c.fs, c.ContextFiles)
+	if err != nil {
+		return errors.Wrap(err, "cannot load context files")
 	}
 	for k, v := range c.ContextValues {
 		fctx[k] = []byte(v)
 	}
 
+	var crds []*extv1.CustomResourceDefinition
+	if c.ValidateSchemas != "" {
+		crds, err = LoadSchemas(c.fs, c.ValidateSchemas)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load schemas from %q", c.ValidateSchemas)
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
 	defer cancel()
 
-	out, err := Render(ctx, log, Inputs{
+	in := Inputs{
 		CompositeResource:   xr,
 		Composition:         comp,
 		Functions:           fns,
@@ -230,10 +280,108 @@ func (c *Cmd) Run(k *kong.Context, log logging.Logger) error { //nolint:gocognit
 		ObservedResources:   ors,
 		ExtraResources:      ers,
 		Context:             fctx,
-	})
+	}
+
+	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{Yaml: true})
+
+	if !c.MergeObserved {
+		out, err := Render(ctx, log, in)
+		if err != nil {
+			return errors.Wrap(err, "cannot render composite resource")
+		}
+		if err := c.print(k.Stdout, s, xr, out); err != nil {
+			return err
+		}
+		c.emitEvents(k.Stderr, out)
+		return c.validate(k.Stderr, out, crds)
+	}
+
+	if c.Iterations < 1 {
+		return errors.New("--iterations must be at least 1")
+	}
+
+	outs, converged, err := Converge(ctx, log, in, c.Iterations)
+	for i := range outs {
+		// Mark where each iteration's output starts.
+		_, _ = fmt.Fprintln(k.Stdout, "---")
+		iteration := &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "render.crossplane.io/v1beta1",
+			"kind":       "Iteration",
+			"iteration":  int64(i + 1),
+		}}
+		if err := s.Encode(iteration, k.Stdout); err != nil {
+			return errors.Wrap(err, "cannot marshal iteration to YAML")
+		}
+		if err := c.print(k.Stdout, s, xr, outs[i]); err != nil {
+			return err
+		}
+	}
 	if err != nil {
 		return errors.Wrap(err, "cannot render composite resource")
 	}
+	if !converged {
+		return errors.Errorf("composite resource did not converge after %d iterations", c.Iterations)
+	}
+
+	// Only the final iteration's desired state matters.
+	c.emitEvents(k.Stderr, outs[len(outs)-1])
+	return c.validate(k.Stderr, outs[len(outs)-1], crds)
+}
+
+// emitEvents prints the Function results in the supplied outputs, if asked to.
+func (c *Cmd) emitEvents(w io.Writer, out Outputs) {
+	if !c.EmitEvents {
+		return
+	}
+	PrintResults(w, out.Results)
+}
+
+// validate the composed resources in the supplied outputs, if asked to.
+func (c *Cmd) validate(w io.Writer, out Outputs, crds []*extv1.CustomResourceDefinition) error {
+	if c.ValidateSchemas == "" {
+		return nil
+	}
+	return ValidateComposedResources(w, out, crds)
+}
+
+// loadCompositionAndFunctions loads the Composition and Functions to render
+// the supplied XR with, either from YAML files or from a package file.
+func (c *Cmd) loadCompositionAndFunctions(xr *ucomposite.Unstructured) (*v1.Composition, []pkgv1.Function, error) {
+	if c.Package == "" {
+		if c.Composition == "" || c.Functions == "" {
+			return nil, nil, errors.New("a Composition and Functions must be specified, unless --package is used")
+		}
+		comp, err := LoadComposition(c.fs, c.Composition)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot load Composition from %q", c.Composition)
+		}
+		fns, err := LoadFunctions(c.fs, c.Functions)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "cannot load functions from %q", c.Functions)
+		}
+		return comp, fns, nil
+	}
+
+	if c.Composition != "" || c.Functions != "" {
+		return nil, nil, errors.New("a Composition and Functions cannot be specified when --package is used")
+	}
+	pkg, err := LoadPackage(c.fs, c.Package)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot load package from %q", c.Package)
+	}
+	comp, err := CompositionFromPackage(pkg, xr.GetObjectKind().GroupVersionKind(), c.PackageComposition)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot load Composition from package %q", c.Package)
+	}
+	fns, err := FunctionsFromPackage(pkg)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "cannot load functions from package %q", c.Package)
+	}
+	return comp, fns, nil
+}
+
+// print the supplied outputs of rendering the supplied XR.
+func (c *Cmd) print(w io.Writer, s runtime.Encoder, xr *ucomposite.Unstructured, out Outputs) error {
 
 	// TODO(negz): Right now we're just emitting the desired state, which is an
 	// overlay on the observed state. Would it be more useful to apply the
@@ -241,9 +389,6 @@ func (c *Cmd) Run(k *kong.Context, log logging.Logger) error { //nolint:gocognit
 	// challenge with that would be that we'd have to try emulate what
 	// server-side apply would do (e.g. merging vs atomically replacing arrays)
 	// and we don't have enough context (i.e. OpenAPI schemas) to do that.
-
-	s := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{Yaml: true})
-
 	if c.IncludeFullXR {
 		xrSpec, err := fieldpath.Pave(xr.Object).GetValue("spec")
 		if err != nil {
@@ -264,33 +409,44 @@ func (c *Cmd) Run(k *kong.Context, log logging.Logger) error { //nolint:gocognit
 		}
 	}
 
-	_, _ = fmt.Fprintln(k.Stdout, "---")
-	if err := s.Encode(out.CompositeResource, k.Stdout); err != nil {
+	_, _ = fmt.Fprintln(w, "---")
+	if err := s.Encode(out.CompositeResource, w); err != nil {
 		return errors.Wrapf(err, "cannot marshal composite resource %q to YAML", xr.GetName())
 	}
 
 	for i := range out.ComposedResources {
-		_, _ = fmt.Fprintln(k.Stdout, "---")
-		if err := s.Encode(&out.ComposedResources[i], k.Stdout); err != nil {
+		_, _ = fmt.Fprintln(w, "---")
+		if err := s.Encode(&out.ComposedResources[i], w); err != nil {
 			return errors.Wrapf(err, "cannot marshal composed resource %q to YAML", out.ComposedResources[i].GetAnnotations()[AnnotationKeyCompositionResourceName])
 		}
 	}
 
 	if c.IncludeFunctionResults {
 		for i := range out.Results {
-			_, _ = fmt.Fprintln(k.Stdout, "---")
-			if err := s.Encode(&out.Results[i], k.Stdout); err != nil {
+			_, _ = fmt.Fprintln(w, "---")
+			if err := s.Encode(&out.Results[i], w); err != nil {
 				return errors.Wrap(err, "cannot marshal result to YAML")
 			}
 		}
 	}
 
 	if c.IncludeContext {
-		_, _ = fmt.Fprintln(k.Stdout, "---")
-		if err := s.Encode(out.Context, k.Stdout); err != nil {
+		_, _ = fmt.Fprintln(w, "---")
+		if err := s.Encode(out.Context, w); err != nil {
 			return errors.Wrap(err, "cannot marshal context to YAML")
 		}
 	}
 
+	if c.IncludeUsageOrder {
+		order, err := UsageOrder(out.ComposedResources)
+		if err != nil {
+			return errors.Wrap(err, "cannot determine usage order")
+		}
+		_, _ = fmt.Fprintln(w, "---")
+		if err := s.Encode(order, w); err != nil {
+			return errors.Wrap(err, "cannot marshal usage order to YAML")
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+// Converge renders the supplied inputs repeatedly. Each iteration's desired
+// state is merged into the observed state used to render the next iteration,
+// roughly emulating how Crossplane would apply it. Converge returns the outputs
+// of every iteration. It stops after the supplied number of iterations, or when
+// an iteration's outputs are the same as the previous iteration's, in which
+// case it reports that the pipeline converged.
+func Converge(ctx context.Context, log logging.Logger, in Inputs, iterations int) ([]Outputs, bool, error) {
+	runtimes, err := NewRuntimeFunctionRunner(ctx, log, in.Functions)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "cannot start function runtimes")
+	}
+
+	defer func() {
+		if err := runtimes.Stop(ctx); err != nil {
+			log.Info("Error stopping function runtimes", "error", err)
+		}
+	}()
+
+	return converge(ctx, composite.NewFetchingFunctionRunner(runtimes, &FilteringFetcher{extra: in.ExtraResources}), in, iterations)
+}
+
+func converge(ctx context.Context, runner composite.FunctionRunner, in Inputs, iterations int) ([]Outputs, bool, error) {
+	outs := make([]Outputs, 0, iterations)
+	for i := range iterations {
+		out, err := render(ctx, runner, in)
+		if err != nil {
+			return outs, false, errors.Wrapf(err, "cannot render iteration %d", i+1)
+		}
+		outs = append(outs, out)
+
+		if i > 0 && sameOutputs(outs[i-1], out) {
+			return outs, true, nil
+		}
+
+		in, err = MergeObserved(in, out)
+		if err != nil {
+			return outs, false, errors.Wrapf(err, "cannot merge iteration %d into observed state", i+1)
+		}
+	}
+	return outs, false, nil
+}
+
+// MergeObserved returns a copy of the supplied inputs, with the supplied
+// outputs merged into their observed state.
+//
+// The desired status of the XR is merged into the XR's status. Each desired
+// composed resource is merged into the observed composed resource with the
+// same composition resource name. A desired composed resource without a name
+// is named using its generate name and composition resource name. Observed
+// composed resources that are no longer desired are removed, as Crossplane
+// would garbage collect them.
+//
+// Desired fields replace observed fields, except for objects, which are
+// merged. This approximates, but doesn't exactly match, server-side apply.
+func MergeObserved(in Inputs, out Outputs) (Inputs, error) {
+	next := in
+
+	next.CompositeResource = in.CompositeResource.DeepCopy()
+	if status, err := fieldpath.Pave(out.CompositeResource.Object).GetValue("status"); err == nil {
+		observed, _ := next.CompositeResource.Object["status"].(map[string]any)
+		next.CompositeResource.Object["status"] = mergeValue(observed, status)
+	}
+
+	observed := make(map[string]*composed.Unstructured, len(in.ObservedResources))
+	for i := range in.ObservedResources {
+		observed[in.ObservedResources[i].GetAnnotations()[AnnotationKeyCompositionResourceName]] = &in.ObservedResources[i]
+	}
+
+	next.ObservedResources = make([]composed.Unstructured, 0, len(out.ComposedResources))
+	for _, dr := range out.ComposedResources {
+		name := dr.GetAnnotations()[AnnotationKeyCompositionResourceName]
+		cd := composed.New()
+		if or, ok := observed[name]; ok {
+			cd = or.DeepCopy()
+		}
+		merged, ok := mergeValue(cd.Object, dr.DeepCopy().Object).(map[string]any)
+		if !ok {
+			return Inputs{}, errors.Errorf("cannot merge composed resource %q", name)
+		}
+		cd.Object = merged
+		if cd.GetName() == "" {
+			cd.SetName(cd.GetGenerateName() + name)
+		}
+		next.ObservedResources = append(next.ObservedResources, *cd)
+	}
+
+	return next, nil
+}
+
+// mergeValue merges the desired value into the observed value. Objects are
+// merged recursively. Any other desired value replaces the observed value.
+func mergeValue(observed, desired any) any {
+	o, ook := observed.(map[string]any)
+	d, dok := desired.(map[string]any)
+	if !ook || !dok {
+		return desired
+	}
+	merged := make(map[string]any, len(o)+len(d))
+	for k, v := range o {
+		merged[k] = v
+	}
+	for k, v := range d {
+		merged[k] = mergeValue(o[k], v)
+	}
+	return merged
+}
+
+func sameOutputs(a, b Outputs) bool {
+	return equality.Semantic.DeepEqual(a.CompositeResource, b.CompositeResource) &&
+		equality.Semantic.DeepEqual(a.ComposedResources, b.ComposedResources)
+}
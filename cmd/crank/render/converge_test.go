@@ -0,0 +1,268 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	ucomposite "github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+func unstructuredWith(o map[string]any) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: o}
+}
+
+func TestMergeObserved(t *testing.T) {
+	type args struct {
+		in  Inputs
+		out Outputs
+	}
+	type want struct {
+		in  Inputs
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"MergeDesiredState": {
+			reason: "Desired XR status and composed resources should be merged into observed state, and undesired composed resources dropped.",
+			args: args{
+				in: Inputs{
+					CompositeResource: &ucomposite.Unstructured{Unstructured: unstructuredWith(map[string]any{
+						"apiVersion": "example.org/v1",
+						"kind":       "XR",
+						"metadata":   map[string]any{"name": "test-xr"},
+						"spec":       map[string]any{"coolField": "cool"},
+						"status":     map[string]any{"observed": "yes"},
+					})},
+					ObservedResources: []composed.Unstructured{
+						{Unstructured: unstructuredWith(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"name":        "test-xr-abcde",
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "bucket"},
+							},
+							"spec":   map[string]any{"region": "us-west-2", "size": int64(1)},
+							"status": map[string]any{"arn": "cool-arn"},
+						})},
+						{Unstructured: unstructuredWith(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"name":        "test-xr-fghij",
+								"annotations": map[string]any{AnnotationKeyCompositionResourceName: "removed"},
+							},
+						})},
+					},
+				},
+				out: Outputs{
+					CompositeResource: &ucomposite.Unstructured{Unstructured: unstructuredWith(map[string]any{
+						"apiVersion": "example.org/v1",
+						"kind":       "XR",
+						"metadata":   map[string]any{"name": "test-xr"},
+						"status":     map[string]any{"desired": "yes"},
+					})},
+					ComposedResources: []composed.Unstructured{
+						{Unstructured: unstructuredWith(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"generateName": "test-xr-",
+								"annotations":  map[string]any{AnnotationKeyCompositionResourceName: "bucket"},
+							},
+							"spec": map[string]any{"size": int64(2)},
+						})},
+						{Unstructured: unstructuredWith(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"generateName": "test-xr-",
+								"annotations":  map[string]any{AnnotationKeyCompositionResourceName: "new"},
+							},
+						})},
+					},
+				},
+			},
+			want: want{
+				in: Inputs{
+					CompositeResource: &ucomposite.Unstructured{Unstructured: unstructuredWith(map[string]any{
+						"apiVersion": "example.org/v1",
+						"kind":       "XR",
+						"metadata":   map[string]any{"name": "test-xr"},
+						"spec":       map[string]any{"coolField": "cool"},
+						"status":     map[string]any{"observed": "yes", "desired": "yes"},
+					})},
+					ObservedResources: []composed.Unstructured{
+						{Unstructured: unstructuredWith(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"name":         "test-xr-abcde",
+								"generateName": "test-xr-",
+								"annotations":  map[string]any{AnnotationKeyCompositionResourceName: "bucket"},
+							},
+							"spec":   map[string]any{"region": "us-west-2", "size": int64(2)},
+							"status": map[string]any{"arn": "cool-arn"},
+						})},
+						{Unstructured: unstructuredWith(map[string]any{
+							"apiVersion": "example.org/v1",
+							"kind":       "Bucket",
+							"metadata": map[string]any{
+								"name":         "test-xr-new",
+								"generateName": "test-xr-",
+								"annotations":  map[string]any{AnnotationKeyCompositionResourceName: "new"},
+							},
+						})},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := MergeObserved(tc.args.in, tc.args.out)
+			if diff := cmp.Diff(tc.want.in, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("%s\nMergeObserved(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s\nMergeObserved(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConverge(t *testing.T) {
+	pipeline := apiextensionsv1.CompositionModePipeline
+
+	// count returns a function that desires a bucket whose spec.count is one
+	// more than its observed spec.count, up to the supplied limit.
+	count := func(limit float64) composite.FunctionRunnerFn {
+		return func(_ context.Context, _ string, req *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+			n := req.GetObserved().GetResources()["bucket"].GetResource().GetFields()["spec"].GetStructValue().GetFields()["count"].GetNumberValue()
+			if n < limit {
+				n++
+			}
+			return &fnv1.RunFunctionResponse{
+				Desired: &fnv1.State{
+					Composite: &fnv1.Resource{Resource: MustStructJSON(`{"status":{}}`)},
+					Resources: map[string]*fnv1.Resource{
+						"bucket": {Resource: &structpb.Struct{Fields: map[string]*structpb.Value{
+							"apiVersion": structpb.NewStringValue("example.org/v1"),
+							"kind":       structpb.NewStringValue("Bucket"),
+							"spec": structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+								"count": structpb.NewNumberValue(n),
+							}}),
+						}}},
+					},
+				},
+			}, nil
+		}
+	}
+
+	in := func() Inputs {
+		return Inputs{
+			CompositeResource: &ucomposite.Unstructured{Unstructured: unstructuredWith(map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "XR",
+				"metadata":   map[string]any{"name": "test-xr"},
+			})},
+			Composition: &apiextensionsv1.Composition{
+				Spec: apiextensionsv1.CompositionSpec{
+					Mode:     &pipeline,
+					Pipeline: []apiextensionsv1.PipelineStep{{Step: "count", FunctionRef: apiextensionsv1.FunctionReference{Name: "function-count"}}},
+				},
+			},
+		}
+	}
+
+	type args struct {
+		runner     composite.FunctionRunner
+		iterations int
+	}
+	type want struct {
+		iterations int
+		converged  bool
+		count      float64
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Converges": {
+			reason: "We should stop once an iteration's output is the same as the previous iteration's.",
+			args: args{
+				runner:     count(2),
+				iterations: 10,
+			},
+			want: want{
+				// Counts 1, 2, then 2 again.
+				iterations: 3,
+				converged:  true,
+				count:      2,
+			},
+		},
+		"DoesNotConverge": {
+			reason: "We should stop after the maximum number of iterations if the output keeps changing.",
+			args: args{
+				runner:     count(100),
+				iterations: 4,
+			},
+			want: want{
+				iterations: 4,
+				converged:  false,
+				count:      4,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			outs, converged, err := converge(context.Background(), tc.args.runner, in(), tc.args.iterations)
+			if err != nil {
+				t.Fatalf("%s\nconverge(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.iterations, len(outs)); diff != "" {
+				t.Errorf("%s\nconverge(...): -want iterations, +got iterations:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.converged, converged); diff != "" {
+				t.Errorf("%s\nconverge(...): -want converged, +got converged:\n%s", tc.reason, diff)
+			}
+			last := outs[len(outs)-1].ComposedResources[0]
+			if diff := cmp.Diff(tc.want.count, last.Object["spec"].(map[string]any)["count"]); diff != "" {
+				t.Errorf("%s\nconverge(...): -want count, +got count:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
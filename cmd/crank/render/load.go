@@ -23,6 +23,7 @@ import (
 
 	"github.com/spf13/afero"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
@@ -33,6 +34,7 @@ import (
 	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 // LoadCompositeResource from a YAML manifest.
@@ -114,6 +116,25 @@ func LoadCredentials(fs afero.Fs, file string) ([]corev1.Secret, error) {
 	return secrets, nil
 }
 
+// LoadContextFiles loads Function pipeline context values from files, keyed by
+// context key. Each file must contain a single JSON or YAML value, which is
+// converted to JSON.
+func LoadContextFiles(fs afero.Fs, files map[string]string) (map[string][]byte, error) {
+	fctx := make(map[string][]byte, len(files))
+	for k, file := range files {
+		y, err := afero.ReadFile(fs, file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read context value for key %q", k)
+		}
+		j, err := yaml.ToJSON(y)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot convert context value for key %q to JSON", k)
+		}
+		fctx[k] = j
+	}
+	return fctx, nil
+}
+
 // LoadExtraResources from a stream of YAML manifests.
 func LoadExtraResources(fs afero.Fs, file string) ([]unstructured.Unstructured, error) {
 	stream, err := LoadYAMLStream(fs, file)
@@ -152,6 +173,46 @@ func LoadObservedResources(fs afero.Fs, file string) ([]composed.Unstructured, e
 	return observed, nil
 }
 
+// LoadSchemas loads the CRDs to validate rendered resources against from a
+// stream of YAML manifests. The stream may also contain XRDs, in which case
+// the CRD of the composite resource they define is derived from them.
+func LoadSchemas(fs afero.Fs, file string) ([]*extv1.CustomResourceDefinition, error) {
+	stream, err := LoadYAMLStream(fs, file)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot load YAML stream from file")
+	}
+
+	crds := make([]*extv1.CustomResourceDefinition, 0, len(stream))
+	for _, y := range stream {
+		u := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(y, u); err != nil {
+			return nil, errors.Wrap(err, "cannot parse YAML manifest")
+		}
+		switch gvk := u.GroupVersionKind(); gvk.GroupKind() {
+		case extv1.SchemeGroupVersion.WithKind("CustomResourceDefinition").GroupKind():
+			crd := &extv1.CustomResourceDefinition{}
+			if err := yaml.Unmarshal(y, crd); err != nil {
+				return nil, errors.Wrap(err, "cannot parse YAML CustomResourceDefinition manifest")
+			}
+			crds = append(crds, crd)
+		case apiextensionsv1.CompositeResourceDefinitionGroupVersionKind.GroupKind():
+			xrd := &apiextensionsv1.CompositeResourceDefinition{}
+			if err := yaml.Unmarshal(y, xrd); err != nil {
+				return nil, errors.Wrap(err, "cannot parse YAML CompositeResourceDefinition manifest")
+			}
+			crd, err := xcrd.ForCompositeResource(xrd)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot derive composite resource CRD from XRD %q", xrd.GetName())
+			}
+			crds = append(crds, crd)
+		default:
+			return nil, errors.Errorf("not a CustomResourceDefinition or CompositeResourceDefinition: %s/%s", gvk.Kind, u.GetName())
+		}
+	}
+
+	return crds, nil
+}
+
 // LoadYAMLStream from the supplied file or directory. Returns an array of byte
 // arrays, where each byte array is expected to be a YAML manifest.
 func LoadYAMLStream(filesys afero.Fs, fileOrDir string) ([][]byte, error) {
@@ -411,6 +411,72 @@ func TestLoadExtraResources(t *testing.T) {
 	}
 }
 
+func TestLoadContextFiles(t *testing.T) {
+	fs := afero.FromIOFS{FS: fstest.MapFS{
+		"environment.json": &fstest.MapFile{Data: []byte(`{"region": "us-west-2"}`)},
+		"environment.yaml": &fstest.MapFile{Data: []byte("region: us-east-1\nzones:\n- a\n- b\n")},
+		"invalid.yaml":     &fstest.MapFile{Data: []byte("region: [")},
+	}}
+
+	type args struct {
+		files map[string]string
+	}
+	type want struct {
+		out map[string][]byte
+		err error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Success": {
+			args: args{
+				files: map[string]string{
+					"example.org/json": "environment.json",
+					"example.org/yaml": "environment.yaml",
+				},
+			},
+			want: want{
+				out: map[string][]byte{
+					"example.org/json": []byte(`{"region": "us-west-2"}`),
+					"example.org/yaml": []byte(`{"region":"us-east-1","zones":["a","b"]}`),
+				},
+			},
+		},
+		"NoSuchFile": {
+			args: args{
+				files: map[string]string{"example.org/missing": "nonexist.yaml"},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+		"InvalidYAML": {
+			args: args{
+				files: map[string]string{"example.org/invalid": "invalid.yaml"},
+			},
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			out, err := LoadContextFiles(fs, tc.args.files)
+
+			if diff := cmp.Diff(tc.want.out, out, cmpopts.AcyclicTransformer("string", func(in []byte) string {
+				return string(in)
+			})); diff != "" {
+				t.Errorf("LoadContextFiles(..), -want, +got:\n%s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("LoadContextFiles(..), -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestLoadYAMLStream(t *testing.T) {
 	type args struct {
 		file string
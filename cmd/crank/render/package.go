@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/spf13/afero"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+// LoadPackage from a package file, i.e. one built by crossplane xpkg build.
+func LoadPackage(fs afero.Fs, file string) (*parser.Package, error) {
+	img, err := tarball.Image(func() (io.ReadCloser, error) { return fs.Open(file) }, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot read package file")
+	}
+	pkg, err := xpkg.ReadPackage(img)
+	return pkg, errors.Wrap(err, "cannot read package contents")
+}
+
+// CompositionFromPackage returns the Composition in the supplied package that
+// composes the supplied type of XR. If the package contains more than one such
+// Composition the selector must be used to pick one by name.
+func CompositionFromPackage(pkg *parser.Package, xr schema.GroupVersionKind, selector string) (*apiextensionsv1.Composition, error) {
+	candidates := make([]*apiextensionsv1.Composition, 0)
+	for _, o := range pkg.GetObjects() {
+		comp, ok := o.(*apiextensionsv1.Composition)
+		if !ok {
+			continue
+		}
+		if selector != "" && comp.GetName() != selector {
+			continue
+		}
+		ref := comp.Spec.CompositeTypeRef
+		if ref.Kind != xr.Kind || ref.APIVersion != xr.GroupVersion().String() {
+			continue
+		}
+		candidates = append(candidates, comp)
+	}
+
+	switch len(candidates) {
+	case 0:
+		if selector != "" {
+			return nil, errors.Errorf("package contains no Composition named %q for %s", selector, xr)
+		}
+		return nil, errors.Errorf("package contains no Composition for %s", xr)
+	case 1:
+		// The rest of render expects the Composition's type to be set.
+		candidates[0].SetGroupVersionKind(apiextensionsv1.CompositionGroupVersionKind)
+		return candidates[0], nil
+	default:
+		names := make([]string, len(candidates))
+		for i := range candidates {
+			names[i] = candidates[i].GetName()
+		}
+		return nil, errors.Errorf("package contains %d Compositions for %s (%s) - use --package-composition to select one", len(candidates), xr, strings.Join(names, ", "))
+	}
+}
+
+// FunctionsFromPackage returns the Functions the supplied package depends on.
+// Each Function is named the way the package manager would name it when
+// resolving the package's dependencies. Dependencies must specify an exact
+// version or digest, not a version constraint.
+func FunctionsFromPackage(pkg *parser.Package) ([]pkgv1.Function, error) {
+	if len(pkg.GetMeta()) != 1 {
+		return nil, errors.New("package contents don't include exactly one package metadata object")
+	}
+	meta, ok := xpkg.TryConvertToPkg(pkg.GetMeta()[0], &pkgmetav1.Configuration{})
+	if !ok {
+		return nil, errors.New("package is not a Configuration")
+	}
+
+	fns := make([]pkgv1.Function, 0)
+	for _, d := range meta.GetDependencies() {
+		var source string
+		switch {
+		case d.Kind != nil && d.Package != nil && *d.Kind == pkgmetav1.FunctionKind:
+			source = *d.Package
+		case d.Function != nil:
+			source = *d.Function
+		default:
+			continue
+		}
+
+		ref, err := name.ParseReference(source, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse Function dependency %q", source)
+		}
+
+		format := "%s:%s"
+		switch {
+		case isDigest(d.Version):
+			format = "%s@%s"
+		case isExactVersion(d.Version):
+		default:
+			return nil, errors.Errorf("Function dependency %q has version constraint %q: render needs an exact version or digest", source, d.Version)
+		}
+
+		fns = append(fns, pkgv1.Function{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: pkgv1.FunctionGroupVersionKind.GroupVersion().String(),
+				Kind:       pkgv1.FunctionKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: xpkg.ToDNSLabel(ref.Context().RepositoryStr())},
+			Spec: pkgv1.FunctionSpec{
+				PackageSpec: pkgv1.PackageSpec{
+					Package: fmt.Sprintf(format, ref.Context().String(), d.Version),
+				},
+			},
+		})
+	}
+
+	return fns, nil
+}
+
+func isDigest(v string) bool {
+	_, err := conregv1.NewHash(v)
+	return err == nil
+}
+
+func isExactVersion(v string) bool {
+	_, err := semver.NewVersion(v)
+	return err == nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const configurationMeta = `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: configuration-nop
+spec:
+  dependsOn:
+  - apiVersion: pkg.crossplane.io/v1
+    kind: Provider
+    package: xpkg.upbound.io/crossplane-contrib/provider-nop
+    version: ">=v0.2.0"
+  - apiVersion: pkg.crossplane.io/v1
+    kind: Function
+    package: xpkg.upbound.io/crossplane-contrib/function-patch-and-transform
+    version: v0.7.0
+  - function: xpkg.upbound.io/crossplane-contrib/function-auto-ready
+    version: sha256:9a4a4d2b3f1d9b2a3c8c1e4f4a2b1d9c3e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b
+`
+
+const compositionFmt = `
+apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: %s
+spec:
+  compositeTypeRef:
+    apiVersion: nop.example.org/v1alpha1
+    kind: XNopResource
+  mode: Pipeline
+  pipeline:
+  - step: be-a-dummy
+    functionRef:
+      name: crossplane-contrib-function-patch-and-transform
+`
+
+func parsePackage(t *testing.T, docs ...string) *parser.Package {
+	t.Helper()
+	ms, err := xpkg.BuildMetaScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	obs, err := xpkg.BuildObjectScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := parser.New(ms, obs).Parse(context.Background(), io.NopCloser(strings.NewReader(strings.Join(docs, "\n---\n"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg
+}
+
+func composition(name string) string {
+	return fmt.Sprintf(compositionFmt, name)
+}
+
+func TestCompositionFromPackage(t *testing.T) {
+	xr := schema.GroupVersionKind{Group: "nop.example.org", Version: "v1alpha1", Kind: "XNopResource"}
+
+	type args struct {
+		docs     []string
+		xr       schema.GroupVersionKind
+		selector string
+	}
+	type want struct {
+		name string
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SingleComposition": {
+			reason: "We should return the only Composition for the XR's type.",
+			args: args{
+				docs: []string{configurationMeta, composition("xnopresources")},
+				xr:   xr,
+			},
+			want: want{
+				name: "xnopresources",
+			},
+		},
+		"NoCompositionForType": {
+			reason: "We should return an error if no Composition composes the XR's type.",
+			args: args{
+				docs: []string{configurationMeta, composition("xnopresources")},
+				xr:   schema.GroupVersionKind{Group: "nop.example.org", Version: "v1alpha1", Kind: "XOtherResource"},
+			},
+			want: want{
+				err: errors.Errorf("package contains no Composition for %s", schema.GroupVersionKind{Group: "nop.example.org", Version: "v1alpha1", Kind: "XOtherResource"}),
+			},
+		},
+		"AmbiguousComposition": {
+			reason: "We should return an error if several Compositions compose the XR's type and none is selected.",
+			args: args{
+				docs: []string{configurationMeta, composition("small"), composition("large")},
+				xr:   xr,
+			},
+			want: want{
+				err: errors.Errorf("package contains 2 Compositions for %s (small, large) - use --package-composition to select one", xr),
+			},
+		},
+		"SelectedComposition": {
+			reason: "We should return the selected Composition if several compose the XR's type.",
+			args: args{
+				docs:     []string{configurationMeta, composition("small"), composition("large")},
+				xr:       xr,
+				selector: "large",
+			},
+			want: want{
+				name: "large",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			comp, err := CompositionFromPackage(parsePackage(t, tc.args.docs...), tc.args.xr, tc.args.selector)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCompositionFromPackage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.name, comp.GetName()); diff != "" {
+				t.Errorf("\n%s\nCompositionFromPackage(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFunctionsFromPackage(t *testing.T) {
+	type want struct {
+		fns []pkgv1.Function
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		docs   []string
+		want   want
+	}{
+		"FunctionDependencies": {
+			reason: "We should return a Function for each Function dependency, named like the package manager would name it.",
+			docs:   []string{configurationMeta},
+			want: want{
+				fns: []pkgv1.Function{
+					{
+						TypeMeta:   metav1.TypeMeta{APIVersion: "pkg.crossplane.io/v1", Kind: "Function"},
+						ObjectMeta: metav1.ObjectMeta{Name: "crossplane-contrib-function-patch-and-transform"},
+						Spec: pkgv1.FunctionSpec{
+							PackageSpec: pkgv1.PackageSpec{
+								Package: "xpkg.upbound.io/crossplane-contrib/function-patch-and-transform:v0.7.0",
+							},
+						},
+					},
+					{
+						TypeMeta:   metav1.TypeMeta{APIVersion: "pkg.crossplane.io/v1", Kind: "Function"},
+						ObjectMeta: metav1.ObjectMeta{Name: "crossplane-contrib-function-auto-ready"},
+						Spec: pkgv1.FunctionSpec{
+							PackageSpec: pkgv1.PackageSpec{
+								Package: "xpkg.upbound.io/crossplane-contrib/function-auto-ready@sha256:9a4a4d2b3f1d9b2a3c8c1e4f4a2b1d9c3e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b",
+							},
+						},
+					},
+				},
+			},
+		},
+		"VersionConstraint": {
+			reason: "We should return an error if a Function dependency uses a version constraint.",
+			docs:   []string{strings.ReplaceAll(configurationMeta, "version: v0.7.0", "version: \">=v0.7.0\"")},
+			want: want{
+				err: errors.Errorf("Function dependency %q has version constraint %q: render needs an exact version or digest", "xpkg.upbound.io/crossplane-contrib/function-patch-and-transform", ">=v0.7.0"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fns, err := FunctionsFromPackage(parsePackage(t, tc.docs...))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFunctionsFromPackage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.fns, fns, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nFunctionsFromPackage(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
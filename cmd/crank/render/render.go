@@ -31,9 +31,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -172,7 +174,7 @@ func getSecret(name string, nameSpace string, secrets []corev1.Secret) (*corev1.
 }
 
 // Render the desired XR and composed resources, sorted by resource name, given the supplied inputs.
-func Render(ctx context.Context, log logging.Logger, in Inputs) (Outputs, error) { //nolint:gocognit // TODO(negz): Should we refactor to break this up a bit?
+func Render(ctx context.Context, log logging.Logger, in Inputs) (Outputs, error) {
 	runtimes, err := NewRuntimeFunctionRunner(ctx, log, in.Functions)
 	if err != nil {
 		return Outputs{}, errors.Wrap(err, "cannot start function runtimes")
@@ -184,8 +186,11 @@ func Render(ctx context.Context, log logging.Logger, in Inputs) (Outputs, error)
 		}
 	}()
 
-	runner := composite.NewFetchingFunctionRunner(runtimes, &FilteringFetcher{extra: in.ExtraResources})
+	return render(ctx, composite.NewFetchingFunctionRunner(runtimes, &FilteringFetcher{extra: in.ExtraResources}), in)
+}
 
+// render the supplied inputs using the supplied function runner.
+func render(ctx context.Context, runner composite.FunctionRunner, in Inputs) (Outputs, error) { //nolint:gocognit // TODO(negz): Should we refactor to break this up a bit?
 	observed := composite.ComposedResourceStates{}
 	for i, cd := range in.ObservedResources {
 		name := cd.GetAnnotations()[AnnotationKeyCompositionResourceName]
@@ -225,18 +230,34 @@ func Render(ctx context.Context, log logging.Logger, in Inputs) (Outputs, error)
 		fctx.Fields[k] = v
 	}
 
+	// Function inputs may reference fields of the XR.
+	xrp := fieldpath.Pave(in.CompositeResource.Object)
+
 	// Run any Composition Functions in the pipeline. Each Function may mutate
 	// the desired state returned by the last, and each Function may produce
 	// results.
 	for _, fn := range in.Composition.Spec.Pipeline {
+		enabled, err := composite.StepEnabled(fn, in.CompositeResource)
+		if err != nil {
+			return Outputs{}, errors.Wrapf(err, "cannot determine whether Composition pipeline step %q is enabled", fn.Step)
+		}
+		if !enabled {
+			continue
+		}
+
 		// The request to send to the function, will be updated at each iteration if needed.
-		req := &fnv1.RunFunctionRequest{Observed: o, Desired: d, Context: fctx}
+		req := &fnv1.RunFunctionRequest{Observed: composite.SelectObserved(o, observed, fn.ObservedResources), Desired: d, Context: fctx}
 
 		if fn.Input != nil {
 			in := &structpb.Struct{}
 			if err := in.UnmarshalJSON(fn.Input.Raw); err != nil {
 				return Outputs{}, errors.Wrapf(err, "cannot unmarshal input for Composition pipeline step %q", fn.Step)
 			}
+			if ptr.Deref(fn.InterpolateInput, false) {
+				if err := composite.InterpolateInput(in, xrp); err != nil {
+					return Outputs{}, errors.Wrapf(err, "cannot interpolate input for Composition pipeline step %q", fn.Step)
+				}
+			}
 			req.Input = in
 		}
 
@@ -307,6 +328,11 @@ func Render(ctx context.Context, log logging.Logger, in Inputs) (Outputs, error)
 				}})
 			}
 		}
+
+		// A Function may stop the pipeline. Subsequent Functions don't run.
+		if rsp.GetStopPipeline() {
+			break
+		}
 	}
 
 	desired := make([]composed.Unstructured, 0, len(d.GetResources()))
@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+)
+
+// PrintResults writes the supplied Function results to the supplied writer,
+// one per line, in the order the Functions returned them. Each line starts
+// with the result's severity and the pipeline step that returned it, e.g.
+// WARN(validate): replicas should be at least 3.
+func PrintResults(w io.Writer, results []unstructured.Unstructured) {
+	for _, r := range results {
+		step, _, _ := unstructured.NestedString(r.Object, "step")
+		severity, _, _ := unstructured.NestedString(r.Object, "severity")
+		message, _, _ := unstructured.NestedString(r.Object, "message")
+		_, _ = fmt.Fprintf(w, "%s(%s): %s\n", severityLabel(severity), step, message)
+	}
+}
+
+func severityLabel(severity string) string {
+	switch severity {
+	case fnv1.Severity_SEVERITY_WARNING.String():
+		return "WARN"
+	case fnv1.Severity_SEVERITY_NORMAL.String():
+		return "INFO"
+	default:
+		return severity
+	}
+}
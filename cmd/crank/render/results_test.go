@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPrintResults(t *testing.T) {
+	result := func(step, severity, message string) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "render.crossplane.io/v1beta1",
+			"kind":       "Result",
+			"step":       step,
+			"severity":   severity,
+			"message":    message,
+		}}
+	}
+
+	cases := map[string]struct {
+		reason  string
+		results []unstructured.Unstructured
+		want    string
+	}{
+		"NoResults": {
+			reason: "We should print nothing if Functions returned no results.",
+			want:   "",
+		},
+		"Results": {
+			reason: "We should print one line per result, in order, with its severity and step.",
+			results: []unstructured.Unstructured{
+				result("validate", "SEVERITY_WARNING", "replicas should be at least 3"),
+				result("compose", "SEVERITY_NORMAL", "composed 2 resources"),
+				result("other", "SEVERITY_UNSPECIFIED", "huh"),
+			},
+			want: `WARN(validate): replicas should be at least 3
+INFO(compose): composed 2 resources
+SEVERITY_UNSPECIFIED(other): huh
+`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			PrintResults(b, tc.results)
+			if diff := cmp.Diff(tc.want, b.String()); diff != "" {
+				t.Errorf("\n%s\nPrintResults(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1beta1"
+)
+
+// IsUsage returns true if the supplied composed resource is a Usage.
+func IsUsage(cd *composed.Unstructured) bool {
+	gvk := cd.GroupVersionKind()
+	return gvk.Group == v1beta1.Group && gvk.Kind == v1beta1.UsageKind
+}
+
+// UsageOrder returns a resource of kind UsageOrder that describes the deletion
+// ordering implied by any Usages among the supplied composed resources.
+//
+// A Usage's 'of' and 'by' resources are resolved to composed resources by API
+// version, kind, and either name or labels. Every composed resource is
+// controlled by the XR, so selectors that match the controller reference match
+// any composed resource. A Usage blocks deletion of the resources it's 'of'
+// until it's deleted, and is deleted once the resources it's 'by' are deleted.
+// The composed resources are therefore deleted in phases. Each phase contains
+// the composed resources that may be deleted once all resources in previous
+// phases are gone.
+func UsageOrder(cds []composed.Unstructured) (*unstructured.Unstructured, error) {
+	// Each composed resource's name, and the names of the composed resources
+	// that must be deleted before it.
+	after := make(map[string]map[string]bool, len(cds))
+	for i := range cds {
+		after[compositionResourceName(&cds[i])] = map[string]bool{}
+	}
+
+	usages := make([]any, 0)
+	for i := range cds {
+		if !IsUsage(&cds[i]) {
+			continue
+		}
+		name := compositionResourceName(&cds[i])
+
+		u := &v1beta1.Usage{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(cds[i].UnstructuredContent(), u); err != nil {
+			return nil, errors.Wrapf(err, "cannot convert composed resource %q to a Usage", name)
+		}
+
+		of := resolve(cds, u.Spec.Of)
+		var by []string
+		if u.Spec.By != nil {
+			by = resolve(cds, *u.Spec.By)
+		}
+
+		// The Usage is deleted after the resources that use it, and the
+		// resources being used are deleted after the Usage.
+		for _, b := range by {
+			after[name][b] = true
+		}
+		for _, o := range of {
+			after[o][name] = true
+		}
+
+		usage := map[string]any{"name": name, "of": asAnySlice(of)}
+		if u.Spec.By != nil {
+			usage["by"] = asAnySlice(by)
+		}
+		usages = append(usages, usage)
+	}
+
+	phases, err := deletionPhases(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion":    "render.crossplane.io/v1beta1",
+		"kind":          "UsageOrder",
+		"usages":        usages,
+		"deletionOrder": phases,
+	}}, nil
+}
+
+// resolve returns the names of the composed resources that match the supplied
+// Usage resource.
+func resolve(cds []composed.Unstructured, r v1beta1.Resource) []string {
+	var sel labels.Selector
+	if r.ResourceRef == nil && r.ResourceSelector != nil {
+		sel = labels.SelectorFromSet(r.ResourceSelector.MatchLabels)
+	}
+
+	names := make([]string, 0)
+	for i := range cds {
+		cd := &cds[i]
+		if cd.GetAPIVersion() != r.APIVersion || cd.GetKind() != r.Kind {
+			continue
+		}
+		switch {
+		case r.ResourceRef != nil:
+			if cd.GetName() != r.ResourceRef.Name {
+				continue
+			}
+		case sel != nil:
+			if !sel.Matches(labels.Set(cd.GetLabels())) {
+				continue
+			}
+		default:
+			continue
+		}
+		names = append(names, compositionResourceName(cd))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// deletionPhases sorts the supplied composed resources into phases, such that
+// each resource is in a later phase than every resource it must be deleted
+// after.
+func deletionPhases(after map[string]map[string]bool) ([]any, error) {
+	phases := make([]any, 0)
+	deleted := make(map[string]bool, len(after))
+	for len(deleted) < len(after) {
+		phase := make([]string, 0)
+		for name, deps := range after {
+			if deleted[name] {
+				continue
+			}
+			ready := true
+			for d := range deps {
+				if !deleted[d] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				phase = append(phase, name)
+			}
+		}
+
+		if len(phase) == 0 {
+			remaining := make([]string, 0)
+			for name := range after {
+				if !deleted[name] {
+					remaining = append(remaining, name)
+				}
+			}
+			sort.Strings(remaining)
+			return nil, errors.Errorf("usages form a cycle between composed resources %s", strings.Join(remaining, ", "))
+		}
+
+		sort.Strings(phase)
+		for _, name := range phase {
+			deleted[name] = true
+		}
+		phases = append(phases, asAnySlice(phase))
+	}
+	return phases, nil
+}
+
+func compositionResourceName(cd *composed.Unstructured) string {
+	return cd.GetAnnotations()[AnnotationKeyCompositionResourceName]
+}
+
+func asAnySlice(s []string) []any {
+	out := make([]any, len(s))
+	for i := range s {
+		out[i] = s[i]
+	}
+	return out
+}
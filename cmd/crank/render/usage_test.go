@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestUsageOrder(t *testing.T) {
+	cd := func(name, kind, objName string, labels map[string]any) composed.Unstructured {
+		meta := map[string]any{
+			"name":        objName,
+			"annotations": map[string]any{AnnotationKeyCompositionResourceName: name},
+		}
+		if labels != nil {
+			meta["labels"] = labels
+		}
+		return composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       kind,
+			"metadata":   meta,
+		}}}
+	}
+	usage := func(name string, spec map[string]any) composed.Unstructured {
+		return composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "apiextensions.crossplane.io/v1beta1",
+			"kind":       "Usage",
+			"metadata": map[string]any{
+				"annotations": map[string]any{AnnotationKeyCompositionResourceName: name},
+			},
+			"spec": spec,
+		}}}
+	}
+	ref := func(kind, name string) map[string]any {
+		return map[string]any{"apiVersion": "example.org/v1", "kind": kind, "resourceRef": map[string]any{"name": name}}
+	}
+	order := func(usages []any, phases ...[]any) *unstructured.Unstructured {
+		p := make([]any, len(phases))
+		for i := range phases {
+			p[i] = phases[i]
+		}
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion":    "render.crossplane.io/v1beta1",
+			"kind":          "UsageOrder",
+			"usages":        usages,
+			"deletionOrder": p,
+		}}
+	}
+
+	type want struct {
+		order *unstructured.Unstructured
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		cds    []composed.Unstructured
+		want   want
+	}{
+		"NoUsages": {
+			reason: "All composed resources should be deleted in a single phase if there are no Usages.",
+			cds: []composed.Unstructured{
+				cd("db", "Database", "cool-db", nil),
+				cd("app", "App", "cool-app", nil),
+			},
+			want: want{
+				order: order([]any{}, []any{"app", "db"}),
+			},
+		},
+		"UsageByRef": {
+			reason: "A resource used by another should be deleted after the Usage, which should be deleted after the user.",
+			cds: []composed.Unstructured{
+				cd("db", "Database", "cool-db", nil),
+				cd("app", "App", "cool-app", nil),
+				usage("app-uses-db", map[string]any{"of": ref("Database", "cool-db"), "by": ref("App", "cool-app")}),
+			},
+			want: want{
+				order: order(
+					[]any{map[string]any{"name": "app-uses-db", "of": []any{"db"}, "by": []any{"app"}}},
+					[]any{"app"},
+					[]any{"app-uses-db"},
+					[]any{"db"},
+				),
+			},
+		},
+		"UsageBySelector": {
+			reason: "A Usage should resolve resources by label selector.",
+			cds: []composed.Unstructured{
+				cd("db", "Database", "cool-db", map[string]any{"tier": "data"}),
+				cd("other-db", "Database", "other-db", nil),
+				usage("protect-db", map[string]any{"of": map[string]any{
+					"apiVersion":       "example.org/v1",
+					"kind":             "Database",
+					"resourceSelector": map[string]any{"matchLabels": map[string]any{"tier": "data"}},
+				}}),
+			},
+			want: want{
+				order: order(
+					[]any{map[string]any{"name": "protect-db", "of": []any{"db"}}},
+					[]any{"other-db", "protect-db"},
+					[]any{"db"},
+				),
+			},
+		},
+		"Cycle": {
+			reason: "We should return an error if Usages form a cycle.",
+			cds: []composed.Unstructured{
+				cd("a", "App", "a", nil),
+				cd("b", "App", "b", nil),
+				usage("a-uses-b", map[string]any{"of": ref("App", "b"), "by": ref("App", "a")}),
+				usage("b-uses-a", map[string]any{"of": ref("App", "a"), "by": ref("App", "b")}),
+			},
+			want: want{
+				err: errors.New("usages form a cycle between composed resources a, a-uses-b, b, b-uses-a"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := UsageOrder(tc.cds)
+			if diff := cmp.Diff(tc.want.order, got); diff != "" {
+				t.Errorf("\n%s\nUsageOrder(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUsageOrder(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
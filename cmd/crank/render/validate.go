@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"io"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/cmd/crank/beta/validate"
+)
+
+// ValidateComposedResources validates the desired composed resources in the
+// supplied outputs against the supplied CRDs, writing any field-level
+// violations to the supplied writer. It returns an error if any composed
+// resource is invalid. Composed resources without a CRD are reported, but
+// don't cause validation to fail.
+func ValidateComposedResources(w io.Writer, out Outputs, crds []*extv1.CustomResourceDefinition) error {
+	resources := make([]*unstructured.Unstructured, len(out.ComposedResources))
+	for i := range out.ComposedResources {
+		resources[i] = &out.ComposedResources[i].Unstructured
+	}
+	return errors.Wrap(validate.SchemaValidation(resources, crds, true, false, w), "invalid composed resources")
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const testSchemas = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: buckets.example.org
+spec:
+  group: example.org
+  names:
+    kind: Bucket
+    plural: buckets
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              size:
+                type: integer
+                maximum: 10
+---
+apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xdatabases.example.org
+spec:
+  group: example.org
+  names:
+    kind: XDatabase
+    plural: xdatabases
+  versions:
+  - name: v1
+    served: true
+    referenceable: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        properties:
+          spec:
+            type: object
+            properties:
+              engine:
+                type: string
+                enum: [postgres, mysql]
+`
+
+func TestValidateComposedResources(t *testing.T) {
+	fs := afero.FromIOFS{FS: fstest.MapFS{
+		"schemas.yaml": &fstest.MapFile{Data: []byte(testSchemas)},
+	}}
+	crds, err := LoadSchemas(fs, "schemas.yaml")
+	if err != nil {
+		t.Fatalf("LoadSchemas(...): %s", err)
+	}
+
+	cd := func(kind, name string, spec map[string]any) composed.Unstructured {
+		cd := composed.New()
+		cd.SetAPIVersion("example.org/v1")
+		cd.SetKind(kind)
+		cd.SetName(name)
+		cd.Object["spec"] = spec
+		return *cd
+	}
+
+	type want struct {
+		output []string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		out    Outputs
+		want   want
+	}{
+		"Valid": {
+			reason: "We shouldn't return an error if all composed resources are valid.",
+			out: Outputs{ComposedResources: []composed.Unstructured{
+				cd("Bucket", "cool-bucket", map[string]any{"size": int64(5)}),
+				cd("XDatabase", "cool-db", map[string]any{"engine": "postgres"}),
+			}},
+		},
+		"MissingSchema": {
+			reason: "We should report, but not fail on, composed resources without a schema.",
+			out: Outputs{ComposedResources: []composed.Unstructured{
+				cd("Queue", "cool-queue", map[string]any{}),
+			}},
+			want: want{
+				output: []string{"could not find CRD/XRD for: example.org/v1, Kind=Queue"},
+			},
+		},
+		"Invalid": {
+			reason: "We should report each field that violates a composed resource's schema.",
+			out: Outputs{ComposedResources: []composed.Unstructured{
+				cd("Bucket", "cool-bucket", map[string]any{"size": int64(11)}),
+				cd("XDatabase", "cool-db", map[string]any{"engine": "oracle"}),
+			}},
+			want: want{
+				output: []string{"cool-bucket : spec.size", "cool-db : spec.engine"},
+				err:    errors.Wrap(errors.New("could not validate all resources"), "invalid composed resources"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := ValidateComposedResources(w, tc.out, crds)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateComposedResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			for _, o := range tc.want.output {
+				if !strings.Contains(w.String(), o) {
+					t.Errorf("\n%s\nValidateComposedResources(...): want output to contain %q, got:\n%s", tc.reason, o, w.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/alecthomas/kong"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/spf13/afero"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errFindPackages      = "failed to find package directories"
+	errNoPackages        = "no package directories found"
+	errCreateOutputDir   = "failed to create output directory"
+	errWriteManifest     = "failed to write manifest"
+	errMarshalManifest   = "failed to marshal manifest"
+	errWriteOutput       = "failed to write output"
+	errFmtBuildPackages  = "failed to build %d of %d packages"
+	errFmtBuildPackageAt = "failed to build package in %q"
+)
+
+// batchBuildCmd builds all of the Crossplane packages found under a directory.
+type batchBuildCmd struct {
+	// Arguments.
+	Root string `arg:"" default:"." help:"The directory to search for packages. Any directory that contains a crossplane.yaml file is built as a package." type:"existingdir"`
+
+	// Flags. Keep sorted alphabetically.
+	Concurrency int      `default:"4"                                                                                                                                      help:"The maximum number of packages to build at once."`
+	ExamplesDir string   `default:"examples"                                                                                                                               help:"The directory, relative to each package's directory, of example YAML files to include in the package."`
+	Ignore      []string `help:"Comma-separated file paths, specified relative to each package's directory, to exclude from the package. Wildcards are supported." placeholder:"PATH"`
+	Manifest    string   `help:"A file to write a JSON manifest of the built packages to."                                                                         placeholder:"PATH" type:"path"`
+	OutputDir   string   `help:"The directory to write packages to. Defaults to each package's directory."                                                         placeholder:"PATH" short:"o" type:"path"`
+
+	// Internal state. These aren't part of the user-exposed CLI structure.
+	fs afero.Fs
+}
+
+func (c *batchBuildCmd) Help() string {
+	return `
+This command builds every package found under a directory. A package is any
+directory that contains a crossplane.yaml file. Directories nested inside a
+package directory are considered part of that package, and are not searched
+for further packages.
+
+Runtime images can't be embedded in packages built by this command. Use
+'crossplane xpkg build' to build Provider and Function packages that embed a
+runtime image.
+
+Examples:
+
+  # Build all packages under the 'packages' directory.
+  crossplane xpkg batch-build packages/
+
+  # Build all packages under the current directory, writing them to the
+  # 'dist' directory along with a manifest of the built packages.
+  crossplane xpkg batch-build --output-dir=dist --manifest=dist/manifest.json
+`
+}
+
+// AfterApply constructs and binds context to any subcommands
+// that have Run() methods that receive it.
+func (c *batchBuildCmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// A batchBuildResult is the result of building one package.
+type batchBuildResult struct {
+	// Directory is the directory the package was built from.
+	Directory string `json:"directory"`
+
+	// Package is the file the package was written to.
+	Package string `json:"package,omitempty"`
+
+	// Digest is the digest of the package image.
+	Digest string `json:"digest,omitempty"`
+
+	// Error is why the package couldn't be built.
+	Error string `json:"error,omitempty"`
+}
+
+// Run executes the batch build command.
+func (c *batchBuildCmd) Run(k *kong.Context, logger logging.Logger) error {
+	root, err := filepath.Abs(c.Root)
+	if err != nil {
+		return err
+	}
+
+	dirs, err := findPackageDirs(c.fs, root)
+	if err != nil {
+		return errors.Wrap(err, errFindPackages)
+	}
+	if len(dirs) == 0 {
+		return errors.New(errNoPackages)
+	}
+
+	if c.OutputDir != "" {
+		if err := c.fs.MkdirAll(c.OutputDir, 0o755); err != nil {
+			return errors.Wrap(err, errCreateOutputDir)
+		}
+	}
+
+	results := make([]batchBuildResult, len(dirs))
+	sem := make(chan struct{}, max(c.Concurrency, 1))
+	wg := sync.WaitGroup{}
+	for i, dir := range dirs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			results[i] = c.build(dir)
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			logger.Debug("Failed to build package", "directory", r.Directory, "error", r.Error)
+			if _, err := fmt.Fprintf(k.Stdout, "FAILED  %s: %s\n", r.Directory, r.Error); err != nil {
+				return errors.Wrap(err, errWriteOutput)
+			}
+			continue
+		}
+		logger.Debug("xpkg saved", "directory", r.Directory, "output", r.Package)
+		if _, err := fmt.Fprintf(k.Stdout, "BUILT   %s: %s\n", r.Directory, r.Package); err != nil {
+			return errors.Wrap(err, errWriteOutput)
+		}
+	}
+
+	if c.Manifest != "" {
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, errMarshalManifest)
+		}
+		if err := afero.WriteFile(c.fs, c.Manifest, b, 0o644); err != nil {
+			return errors.Wrap(err, errWriteManifest)
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf(errFmtBuildPackages, failed, len(results))
+	}
+	return nil
+}
+
+// build builds the package in the supplied directory.
+func (c *batchBuildCmd) build(dir string) batchBuildResult {
+	res := batchBuildResult{Directory: dir}
+
+	output, digest, err := c.buildPackage(dir)
+	if err != nil {
+		res.Error = errors.Wrapf(err, errFmtBuildPackageAt, dir).Error()
+		return res
+	}
+
+	res.Package = output
+	res.Digest = digest
+	return res
+}
+
+func (c *batchBuildCmd) buildPackage(dir string) (output, digest string, err error) {
+	b, err := newBuilder(c.fs, dir, filepath.Join(dir, c.ExamplesDir), c.Ignore)
+	if err != nil {
+		return "", "", err
+	}
+
+	img, meta, err := b.Build(context.Background())
+	if err != nil {
+		return "", "", errors.Wrap(err, errBuildPackage)
+	}
+
+	hash, err := img.Digest()
+	if err != nil {
+		return "", "", errors.Wrap(err, errImageDigest)
+	}
+
+	pkgMeta, ok := meta.(metav1.Object)
+	if !ok {
+		return "", "", errors.New(errGetNameFromMeta)
+	}
+	outDir := dir
+	if c.OutputDir != "" {
+		outDir = c.OutputDir
+	}
+	output = xpkg.BuildPath(outDir, xpkg.FriendlyID(pkgMeta.GetName(), hash.Hex), xpkg.XpkgExtension)
+
+	f, err := c.fs.Create(output)
+	if err != nil {
+		return "", "", errors.Wrap(err, errCreatePackage)
+	}
+	defer func() { _ = f.Close() }()
+	if err := tarball.Write(nil, img, f); err != nil {
+		return "", "", err
+	}
+
+	return output, hash.String(), nil
+}
+
+// findPackageDirs returns all directories under root that contain a package
+// metadata file, sorted by path. It doesn't search for packages within a
+// package directory.
+func findPackageDirs(fsys afero.Fs, root string) ([]string, error) {
+	dirs := []string{}
+	err := afero.Walk(fsys, root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		ok, err := afero.Exists(fsys, filepath.Join(path, xpkg.MetaFile))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		dirs = append(dirs, path)
+		return filepath.SkipDir
+	})
+	sort.Strings(dirs)
+	return dirs, err
+}
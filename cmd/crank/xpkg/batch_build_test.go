@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestFindPackageDirs(t *testing.T) {
+	type args struct {
+		files []string
+	}
+	type want struct {
+		dirs []string
+		err  error
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoPackages": {
+			reason: "Should return no directories when no crossplane.yaml files exist",
+			args: args{
+				files: []string{"/root/README.md", "/root/a/other.yaml"},
+			},
+			want: want{
+				dirs: []string{},
+			},
+		},
+		"MultiplePackages": {
+			reason: "Should return every directory that contains a crossplane.yaml, sorted by path",
+			args: args{
+				files: []string{
+					"/root/b/crossplane.yaml",
+					"/root/a/crossplane.yaml",
+					"/root/c/d/crossplane.yaml",
+					"/root/c/README.md",
+				},
+			},
+			want: want{
+				dirs: []string{"/root/a", "/root/b", "/root/c/d"},
+			},
+		},
+		"NestedPackages": {
+			reason: "Should not search for packages within a package directory",
+			args: args{
+				files: []string{
+					"/root/a/crossplane.yaml",
+					"/root/a/nested/crossplane.yaml",
+				},
+			},
+			want: want{
+				dirs: []string{"/root/a"},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for _, f := range tc.args.files {
+				if err := afero.WriteFile(fs, f, []byte{}, 0o644); err != nil {
+					t.Fatalf("WriteFile(...): %v", err)
+				}
+			}
+
+			dirs, err := findPackageDirs(fs, "/root")
+			if diff := cmp.Diff(tc.want.dirs, dirs); diff != "" {
+				t.Errorf("\n%s\nfindPackageDirs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nfindPackageDirs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,7 +18,13 @@ package xpkg
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kong"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
@@ -26,12 +32,15 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/spf13/afero"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/parser"
 
+	"github.com/crossplane/crossplane/cmd/crank/beta/validate"
+	"github.com/crossplane/crossplane/internal/version"
 	"github.com/crossplane/crossplane/internal/xpkg"
 	"github.com/crossplane/crossplane/internal/xpkg/parser/examples"
 	"github.com/crossplane/crossplane/internal/xpkg/parser/yaml"
@@ -46,6 +55,10 @@ const (
 	errPullRuntimeImage        = "failed to pull runtime image"
 	errLoadRuntimeTarball      = "failed to load runtime tarball"
 	errGetRuntimeBaseImageOpts = "failed to get runtime base image options"
+	errValidatePackage         = "failed to validate package"
+	errLoadPackage             = "failed to load package files"
+	errLoadExamples            = "failed to load example files"
+	errMkCacheDir              = "failed to create cache directory"
 )
 
 // AfterApply constructs and binds context to any subcommands
@@ -59,47 +72,84 @@ func (c *buildCmd) AfterApply() error {
 	}
 	c.root = root
 
-	ex, err := filepath.Abs(c.ExamplesRoot)
+	b, err := newBuilder(c.fs, root, c.ExamplesRoot, c.Ignore)
 	if err != nil {
 		return err
 	}
+	c.builder = b
+
+	if strings.HasPrefix(c.ValidateCacheDir, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		c.ValidateCacheDir = filepath.Join(homeDir, c.ValidateCacheDir[2:])
+	}
+
+	if strings.HasPrefix(c.CacheDir, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		c.CacheDir = filepath.Join(homeDir, c.CacheDir[2:])
+	}
+
+	return nil
+}
+
+// newBuilder returns a package builder that reads package files from root and
+// example files from examplesRoot, excluding any ignored paths.
+func newBuilder(fs afero.Fs, root, examplesRoot string, ignore []string) (*xpkg.Builder, error) {
+	ex, err := filepath.Abs(examplesRoot)
+	if err != nil {
+		return nil, err
+	}
 
 	pp, err := yaml.New()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	c.builder = xpkg.New(
-		parser.NewFsBackend(
-			c.fs,
-			parser.FsDir(root),
-			parser.FsFilters(
-				append(
-					buildFilters(root, c.Ignore),
-					xpkg.SkipContains(c.ExamplesRoot))...),
-		),
-		parser.NewFsBackend(
-			c.fs,
-			parser.FsDir(ex),
-			parser.FsFilters(
-				buildFilters(ex, c.Ignore)...),
-		),
+	return xpkg.New(
+		packageBackend(fs, root, examplesRoot, ignore),
+		examplesBackend(fs, ex, ignore),
 		pp,
 		examples.New(),
+	), nil
+}
+
+// packageBackend returns a parser backend that reads package files from root,
+// excluding examples and any ignored paths.
+func packageBackend(fs afero.Fs, root, examplesRoot string, ignore []string) parser.Backend {
+	return parser.NewFsBackend(
+		fs,
+		parser.FsDir(root),
+		parser.FsFilters(
+			append(
+				buildFilters(root, ignore),
+				xpkg.SkipContains(examplesRoot))...),
 	)
+}
 
-	return nil
+// examplesBackend returns a parser backend that reads example files from
+// examplesRoot, excluding any ignored paths.
+func examplesBackend(fs afero.Fs, examplesRoot string, ignore []string) parser.Backend {
+	return parser.NewFsBackend(
+		fs,
+		parser.FsDir(examplesRoot),
+		parser.FsFilters(
+			buildFilters(examplesRoot, ignore)...),
+	)
 }
 
 // buildCmd builds a crossplane package.
 type buildCmd struct {
 	// Flags. Keep sorted alphabetically.
-	EmbedRuntimeImage        string   `help:"An OCI image to embed in the package as its runtime."                                                                                                    placeholder:"NAME"                                                     xor:"runtime-image"`
-	EmbedRuntimeImageTarball string   `help:"An OCI image tarball to embed in the package as its runtime."                                                                                            placeholder:"PATH"                                                     type:"existingfile" xor:"runtime-image"`
-	ExamplesRoot             string   `default:"./examples"                                                                                                                                           help:"A directory of example YAML files to include in the package."    short:"e"           type:"path"`
-	Ignore                   []string `help:"Comma-separated file paths, specified relative to --package-root, to exclude from the package. Wildcards are supported. Directories cannot be excluded." placeholder:"PATH"`
-	PackageFile              string   `help:"The file to write the package to. Defaults to a generated filename in --package-root."                                                                   placeholder:"PATH"                                                     short:"o"           type:"path"`
-	PackageRoot              string   `default:"."                                                                                                                                                    help:"The directory that contains the package's crossplane.yaml file." short:"f"           type:"existingdir"`
+	Annotations              map[string]string `help:"An OCI annotation to add to the package's image manifest, as KEY=VALUE. May be repeated."                                                                mapsep:""                                                                                      name:"annotation"   placeholder:"KEY=VALUE"`
+	CacheDir                 string            `default:"~/.crossplane/cache/xpkg"                                                                                                                             help:"Absolute path to the cache directory for built package contents."`
+	EmbedRuntimeImage        string            `help:"An OCI image to embed in the package as its runtime."                                                                                                    placeholder:"NAME"                                                                             xor:"runtime-image"`
+	EmbedRuntimeImageTarball string            `help:"An OCI image tarball to embed in the package as its runtime."                                                                                            placeholder:"PATH"                                                                             type:"existingfile" xor:"runtime-image"`
+	ExamplesRoot             string            `default:"./examples"                                                                                                                                           help:"A directory of example YAML files to include in the package."                            short:"e"           type:"path"`
+	Ignore                   []string          `help:"Comma-separated file paths, specified relative to --package-root, to exclude from the package. Wildcards are supported. Directories cannot be excluded." placeholder:"PATH"`
+	NoCache                  bool              `help:"Don't build packages from cached contents, or cache their contents."`
+	PackageFile              string            `help:"The file to write the package to. Defaults to a generated filename in --package-root."                                                                   placeholder:"PATH"                                                                             short:"o"           type:"path"`
+	PackageRoot              string            `default:"."                                                                                                                                                    help:"The directory that contains the package's crossplane.yaml file."                         short:"f"           type:"existingdir"`
+	Validate                 bool              `help:"Validate the package's resources and examples against their schemas before building the package. The package isn't built if any resource is invalid."`
+	ValidateCacheDir         string            `default:"~/.crossplane/cache"                                                                                                                                  help:"Absolute path to the cache directory where schemas downloaded by --validate are stored."`
 
 	// Internal state. These aren't part of the user-exposed CLI structure.
 	fs      afero.Fs
@@ -120,6 +170,23 @@ Examples:
   # 'docker build' so that the package can also be used to run the provider.
   # Provider and Function packages support embedding runtime images.
   crossplane xpkg build --embed-runtime-image=cc873e13cdc1
+
+  # Build a package without reading or writing the cache. By default the
+  # package's contents are cached, and a package whose files haven't changed
+  # is built from the cache.
+  crossplane xpkg build --package-root=package/ --no-cache
+
+  # Validate the package's resources and examples against their schemas, the
+  # same way 'crossplane beta validate' does, before building the package.
+  crossplane xpkg build --package-root=package/ --validate
+
+  # Add OCI annotations to the package's image manifest. Crossplane doesn't
+  # add any annotations itself, so building the same files twice produces the
+  # same digest. Avoid annotations like a build date that change every build,
+  # or derive them from something stable like the commit date.
+  crossplane xpkg build --package-root=package/ \
+    --annotation=org.opencontainers.image.source=https://github.com/example/package \
+    --annotation=org.opencontainers.image.revision=$(git rev-parse HEAD)
 `
 }
 
@@ -165,13 +232,28 @@ func (c *buildCmd) GetOutputFileName(meta runtime.Object, hash v1.Hash) (string,
 }
 
 // Run executes the build command.
-func (c *buildCmd) Run(logger logging.Logger) error {
+func (c *buildCmd) Run(k *kong.Context, logger logging.Logger) error {
+	if c.Validate {
+		if err := c.validate(k.Stdout); err != nil {
+			return errors.Wrap(err, errValidatePackage)
+		}
+	}
+
 	var buildOpts []xpkg.BuildOpt
 	rtBuildOpts, err := c.GetRuntimeBaseImageOpts()
 	if err != nil {
 		return errors.Wrap(err, errGetRuntimeBaseImageOpts)
 	}
 	buildOpts = append(buildOpts, rtBuildOpts...)
+	if len(c.Annotations) > 0 {
+		buildOpts = append(buildOpts, xpkg.WithAnnotations(c.Annotations))
+	}
+	if !c.NoCache {
+		if err := c.fs.MkdirAll(c.CacheDir, 0o755); err != nil {
+			return errors.Wrap(err, errMkCacheDir)
+		}
+		buildOpts = append(buildOpts, xpkg.WithCache(xpkg.NewFsPackageCache(c.CacheDir, c.fs)))
+	}
 
 	img, meta, err := c.builder.Build(context.Background(), buildOpts...)
 	if err != nil {
@@ -201,6 +283,49 @@ func (c *buildCmd) Run(logger logging.Logger) error {
 	return nil
 }
 
+// validate validates the package's resources and examples against the schemas
+// of the package's XRDs and CRDs, of the packages it depends on, and of
+// Crossplane's own types. It uses the same validation as 'crossplane beta
+// validate', treating the package as extensions, and the package and its
+// examples as resources.
+func (c *buildCmd) validate(w io.Writer) error {
+	ex, err := filepath.Abs(c.ExamplesRoot)
+	if err != nil {
+		return err
+	}
+
+	extensions, err := loadBackend(packageBackend(c.fs, c.root, c.ExamplesRoot, c.Ignore))
+	if err != nil {
+		return errors.Wrap(err, errLoadPackage)
+	}
+
+	examples, err := loadBackend(examplesBackend(c.fs, ex, c.Ignore))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, errLoadExamples)
+	}
+
+	resources := make([]*unstructured.Unstructured, 0, len(extensions)+len(examples))
+	for _, e := range extensions {
+		resources = append(resources, e.DeepCopy())
+	}
+	resources = append(resources, examples...)
+
+	image := fmt.Sprintf("%s/crossplane/crossplane:%s", xpkg.DefaultRegistry, version.New().GetVersionString())
+	m := validate.NewManager(c.ValidateCacheDir, c.fs, w, validate.WithCrossplaneImage(image))
+	return m.Validate(extensions, resources, false, false, false)
+}
+
+// loadBackend loads the YAML stream read from the supplied backend as
+// unstructured objects.
+func loadBackend(b parser.Backend) ([]*unstructured.Unstructured, error) {
+	r, err := b.Init(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return validate.NewReaderLoader(r).Load()
+}
+
 // default build filters skip directories, empty files, and files without YAML
 // extension in addition to any paths specified.
 func buildFilters(root string, skips []string) []parser.FilterFn {
@@ -0,0 +1,328 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/spf13/afero"
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/upbound"
+	"github.com/crossplane/crossplane/internal/xpkg/upbound/credhelper"
+)
+
+const (
+	errFmtParseReference = "failed to parse package reference %q"
+	errFmtFetchPackage   = "failed to fetch package %s"
+	errNoPackageMeta     = "package contents don't include exactly one package metadata object"
+	errUnknownMeta       = "package metadata isn't a known Provider, Configuration, or Function"
+	errMarshalSummary    = "failed to marshal package summary"
+	errWriteSummary      = "failed to write package summary"
+)
+
+// inspectOutputJSON is the JSON output format of the inspect command.
+const inspectOutputJSON = "json"
+
+// inspectCmd prints a summary of a package.
+type inspectCmd struct {
+	// Arguments.
+	Package string `arg:"" help:"The package to inspect. Either an OCI reference or the path to an xpkg file."`
+
+	// Flags. Keep sorted alphabetically.
+	Output string `default:"default" enum:"default,json" help:"Output format. One of: default, json." short:"o"`
+
+	// Common Upbound API configuration.
+	upbound.Flags `embed:""`
+
+	// Internal state. These aren't part of the user-exposed CLI structure.
+	fs afero.Fs
+}
+
+func (c *inspectCmd) Help() string {
+	return `
+This command prints a summary of a package, including its type, version,
+dependencies, the permissions it requests, and the APIs it defines. A
+Function's APIs are the types of input it accepts.
+
+The package may be an OCI reference or a local xpkg file. When inspecting an
+OCI reference only the package's metadata layer is pulled, not its runtime
+image. Credentials for the registry are automatically retrieved from xpkg login
+and dockers configuration as fallback.
+
+Examples:
+
+  # Inspect a package in a registry.
+  crossplane xpkg inspect xpkg.upbound.io/crossplane-contrib/function-patch-and-transform:v0.7.0
+
+  # Inspect a local package file, printing JSON.
+  crossplane xpkg inspect function-example.xpkg -o json
+`
+}
+
+// AfterApply sets up the inspect command.
+func (c *inspectCmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// A packageSummary is a human-friendly summary of a package.
+type packageSummary struct {
+	// Type of the package - Provider, Configuration, or Function.
+	Type string `json:"type"`
+
+	// Name of the package, per its metadata.
+	Name string `json:"name"`
+
+	// Source of the package, if it was fetched from a registry.
+	Source string `json:"source,omitempty"`
+
+	// Version of the package, if it was fetched by tag or digest.
+	Version string `json:"version,omitempty"`
+
+	// Crossplane version constraints of the package.
+	Crossplane string `json:"crossplane,omitempty"`
+
+	// Dependencies of the package.
+	Dependencies []dependencySummary `json:"dependencies,omitempty"`
+
+	// PermissionRequests the package makes, in addition to the permissions
+	// needed for the APIs it defines.
+	PermissionRequests []rbacv1.PolicyRule `json:"permissionRequests,omitempty"`
+
+	// APIs defined by the package, as kind.group. A Function's APIs are the
+	// types of input it accepts.
+	APIs []string `json:"apis,omitempty"`
+}
+
+// A dependencySummary is a human-friendly summary of a package dependency.
+type dependencySummary struct {
+	// Type of the dependency - e.g. Provider.
+	Type string `json:"type"`
+
+	// Package the dependency depends on.
+	Package string `json:"package"`
+
+	// Version constraints of the dependency.
+	Version string `json:"version"`
+}
+
+// Run runs the inspect cmd.
+func (c *inspectCmd) Run(k *kong.Context, logger logging.Logger) error {
+	img, ref, err := c.image(context.Background(), logger)
+	if err != nil {
+		return err
+	}
+
+	pkg, err := xpkg.ReadPackage(img)
+	if err != nil {
+		return err
+	}
+
+	s, err := summarize(pkg)
+	if err != nil {
+		return err
+	}
+	if ref != nil {
+		s.Source = xpkg.ParsePackageSourceFromReference(ref)
+		s.Version = ref.Identifier()
+	}
+
+	if c.Output == inspectOutputJSON {
+		b, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, errMarshalSummary)
+		}
+		_, err = fmt.Fprintln(k.Stdout, string(b))
+		return errors.Wrap(err, errWriteSummary)
+	}
+	return errors.Wrap(printSummary(k.Stdout, s), errWriteSummary)
+}
+
+// image returns the package image to inspect. It returns a reference if the
+// image is in a registry, or nil if it's a local file. Images in a registry
+// are fetched lazily, so only the layers we read are pulled.
+func (c *inspectCmd) image(ctx context.Context, logger logging.Logger) (v1.Image, name.Reference, error) {
+	if _, err := c.fs.Stat(c.Package); err == nil {
+		img, err := tarball.ImageFromPath(c.Package, nil)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, errFmtReadPackage, c.Package)
+		}
+		logger.Debug("Inspecting package file", "path", c.Package)
+		return img, nil, nil
+	}
+
+	ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, errFmtParseReference, c.Package)
+	}
+
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.AllowMissingProfile())
+	if err != nil {
+		return nil, nil, err
+	}
+	kc := authn.NewMultiKeychain(
+		authn.NewKeychainFromHelper(credhelper.New(
+			credhelper.WithLogger(logger),
+			credhelper.WithProfile(upCtx.ProfileName),
+			credhelper.WithDomain(upCtx.Domain.Hostname()),
+		)),
+		authn.DefaultKeychain,
+	)
+
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(kc), remote.WithContext(ctx))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, errFmtFetchPackage, ref)
+	}
+	logger.Debug("Inspecting package", "ref", ref.String())
+	return img, ref, nil
+}
+
+// summarize the supplied package.
+func summarize(pkg *parser.Package) (*packageSummary, error) {
+	if len(pkg.GetMeta()) != 1 {
+		return nil, errors.New(errNoPackageMeta)
+	}
+	meta, ok := xpkg.TryConvertToPkg(pkg.GetMeta()[0], &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+	if !ok {
+		return nil, errors.New(errUnknownMeta)
+	}
+
+	s := &packageSummary{Name: meta.GetName()}
+	switch m := meta.(type) {
+	case *pkgmetav1.Provider:
+		s.Type = pkgmetav1.ProviderKind
+		s.PermissionRequests = m.Spec.Controller.PermissionRequests
+	case *pkgmetav1.Configuration:
+		s.Type = pkgmetav1.ConfigurationKind
+	case *pkgmetav1.Function:
+		s.Type = pkgmetav1.FunctionKind
+	}
+
+	if c := meta.GetCrossplaneConstraints(); c != nil {
+		s.Crossplane = c.Version
+	}
+
+	for _, d := range meta.GetDependencies() {
+		s.Dependencies = append(s.Dependencies, summarizeDependency(d))
+	}
+
+	for _, o := range pkg.GetObjects() {
+		switch t := o.(type) {
+		case *extv1.CustomResourceDefinition:
+			s.APIs = append(s.APIs, t.Spec.Names.Kind+"."+t.Spec.Group)
+		case *apiextensionsv1.CompositeResourceDefinition:
+			s.APIs = append(s.APIs, t.Spec.Names.Kind+"."+t.Spec.Group)
+		}
+	}
+
+	return s, nil
+}
+
+func summarizeDependency(d pkgmetav1.Dependency) dependencySummary {
+	s := dependencySummary{Version: d.Version}
+	switch {
+	case d.Kind != nil && d.Package != nil:
+		s.Type, s.Package = *d.Kind, *d.Package
+	case d.Provider != nil:
+		s.Type, s.Package = pkgmetav1.ProviderKind, *d.Provider
+	case d.Configuration != nil:
+		s.Type, s.Package = pkgmetav1.ConfigurationKind, *d.Configuration
+	case d.Function != nil:
+		s.Type, s.Package = pkgmetav1.FunctionKind, *d.Function
+	}
+	return s
+}
+
+// printSummary prints a human-readable summary of a package.
+func printSummary(w io.Writer, s *packageSummary) error {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "Type:       %s\n", s.Type)
+	fmt.Fprintf(b, "Name:       %s\n", s.Name)
+	if s.Source != "" {
+		fmt.Fprintf(b, "Source:     %s\n", s.Source)
+	}
+	if s.Version != "" {
+		fmt.Fprintf(b, "Version:    %s\n", s.Version)
+	}
+	if s.Crossplane != "" {
+		fmt.Fprintf(b, "Crossplane: %s\n", s.Crossplane)
+	}
+
+	if len(s.Dependencies) > 0 {
+		fmt.Fprintln(b, "Dependencies:")
+		for _, d := range s.Dependencies {
+			fmt.Fprintf(b, "  - %s %s (%s)\n", d.Type, d.Package, d.Version)
+		}
+	}
+
+	if len(s.PermissionRequests) > 0 {
+		fmt.Fprintln(b, "Permission Requests:")
+		for _, r := range s.PermissionRequests {
+			fmt.Fprintf(b, "  - %s\n", formatPolicyRule(r))
+		}
+	}
+
+	if len(s.APIs) > 0 {
+		heading := "APIs:"
+		if s.Type == pkgmetav1.FunctionKind {
+			heading = "Inputs:"
+		}
+		fmt.Fprintln(b, heading)
+		for _, a := range s.APIs {
+			fmt.Fprintf(b, "  - %s\n", a)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatPolicyRule formats an RBAC policy rule as a single line, e.g.
+// "get, list secrets" or "create events.events.k8s.io".
+func formatPolicyRule(r rbacv1.PolicyRule) string {
+	targets := make([]string, 0, len(r.Resources)+len(r.NonResourceURLs))
+	for _, g := range r.APIGroups {
+		for _, res := range r.Resources {
+			if g == "" {
+				targets = append(targets, res)
+				continue
+			}
+			targets = append(targets, res+"."+g)
+		}
+	}
+	targets = append(targets, r.NonResourceURLs...)
+	return strings.Join(r.Verbs, ", ") + " " + strings.Join(targets, ", ")
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	providerPackage = `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-nop
+spec:
+  crossplane:
+    version: ">=v1.14.0"
+  controller:
+    permissionRequests:
+    - apiGroups: [""]
+      resources: [secrets]
+      verbs: [get, list]
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: nopresources.nop.crossplane.io
+spec:
+  group: nop.crossplane.io
+  names:
+    kind: NopResource
+    plural: nopresources
+  scope: Cluster
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+`
+	functionPackage = `
+apiVersion: meta.pkg.crossplane.io/v1beta1
+kind: Function
+metadata:
+  name: function-cool
+spec:
+  dependsOn:
+  - provider: xpkg.upbound.io/crossplane-contrib/provider-nop
+    version: ">=v0.2.0"
+  - apiVersion: pkg.crossplane.io/v1
+    kind: Function
+    package: xpkg.upbound.io/crossplane-contrib/function-auto-ready
+    version: ">=v0.1.0"
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: inputs.cool.fn.crossplane.io
+spec:
+  group: cool.fn.crossplane.io
+  names:
+    kind: Input
+    plural: inputs
+  scope: Namespaced
+  versions:
+  - name: v1beta1
+    served: true
+    storage: false
+`
+)
+
+// packageImage returns an image with a base layer containing the supplied
+// package.yaml stream. The base layer is identified by a label in the image's
+// config file, as in a package file, and optionally by a layer annotation, as
+// in a package pushed to a registry.
+func packageImage(t *testing.T, stream string, annotate bool) v1.Image {
+	t.Helper()
+
+	cfg := &v1.Config{Labels: map[string]string{}}
+	base, err := xpkg.Layer(strings.NewReader(stream), xpkg.StreamFile, xpkg.PackageAnnotation, int64(len(stream)), xpkg.StreamFileMode, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := mutate.AppendLayers(empty.Image, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err = mutate.Config(img, *cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !annotate {
+		return img
+	}
+	img, err = xpkg.AnnotateLayers(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestInspectPackage(t *testing.T) {
+	type want struct {
+		s   *packageSummary
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		img    func(t *testing.T) v1.Image
+		want   want
+	}{
+		"NoBaseLayer": {
+			reason: "We should return an error if the package has no base layer.",
+			img: func(t *testing.T) v1.Image {
+				t.Helper()
+				return empty.Image
+			},
+			want: want{
+				err: errors.New("package has no layer annotated as the package base layer"),
+			},
+		},
+		"Provider": {
+			reason: "We should summarize a Provider's permission requests and APIs.",
+			img: func(t *testing.T) v1.Image {
+				t.Helper()
+				return packageImage(t, providerPackage, false)
+			},
+			want: want{
+				s: &packageSummary{
+					Type:       "Provider",
+					Name:       "provider-nop",
+					Crossplane: ">=v1.14.0",
+					PermissionRequests: []rbacv1.PolicyRule{{
+						APIGroups: []string{""},
+						Resources: []string{"secrets"},
+						Verbs:     []string{"get", "list"},
+					}},
+					APIs: []string{"NopResource.nop.crossplane.io"},
+				},
+			},
+		},
+		"Function": {
+			reason: "We should summarize a Function's dependencies and inputs, finding its base layer by annotation.",
+			img: func(t *testing.T) v1.Image {
+				t.Helper()
+				return packageImage(t, functionPackage, true)
+			},
+			want: want{
+				s: &packageSummary{
+					Type: "Function",
+					Name: "function-cool",
+					Dependencies: []dependencySummary{
+						{Type: "Provider", Package: "xpkg.upbound.io/crossplane-contrib/provider-nop", Version: ">=v0.2.0"},
+						{Type: "Function", Package: "xpkg.upbound.io/crossplane-contrib/function-auto-ready", Version: ">=v0.1.0"},
+					},
+					APIs: []string{"Input.cool.fn.crossplane.io"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var s *packageSummary
+			pkg, err := xpkg.ReadPackage(tc.img(t))
+			if err == nil {
+				s, err = summarize(pkg)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ninspect(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.s, s); diff != "" {
+				t.Errorf("\n%s\ninspect(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFormatPolicyRule(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		r      rbacv1.PolicyRule
+		want   string
+	}{
+		"CoreGroup": {
+			reason: "Resources in the core API group shouldn't include a group.",
+			r:      rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets", "configmaps"}, Verbs: []string{"get", "list"}},
+			want:   "get, list secrets, configmaps",
+		},
+		"NamedGroup": {
+			reason: "Resources in a named API group should include the group.",
+			r:      rbacv1.PolicyRule{APIGroups: []string{"events.k8s.io"}, Resources: []string{"events"}, Verbs: []string{"create"}},
+			want:   "create events.events.k8s.io",
+		},
+		"NonResourceURL": {
+			reason: "Non-resource URLs should be included as is.",
+			r:      rbacv1.PolicyRule{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+			want:   "get /healthz",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := formatPolicyRule(tc.r)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nformatPolicyRule(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
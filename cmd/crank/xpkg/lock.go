@@ -0,0 +1,321 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/Masterminds/semver"
+	"github.com/alecthomas/kong"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	xpkgyaml "github.com/crossplane/crossplane/internal/xpkg/parser/yaml"
+	"github.com/crossplane/crossplane/internal/xpkg/upbound"
+	"github.com/crossplane/crossplane/internal/xpkg/upbound/credhelper"
+)
+
+const (
+	errReadMeta                  = "failed to read crossplane.yaml"
+	errNotConfiguration          = "only Configurations can lock their dependencies"
+	errFmtParseRepository        = "failed to parse package repository %q"
+	errFmtListTags               = "failed to list tags of package %s"
+	errFmtHeadPackage            = "failed to get digest of package %s"
+	errFmtReadDependency         = "failed to read package %s"
+	errFmtInvalidConstraint      = "package %q has invalid version constraint %q"
+	errFmtNoSatisfyingVersion    = "no version of package %s satisfies constraint %q"
+	errFmtNoVersionForDigest     = "no semantic version tag of package %s refers to digest %s"
+	errFmtConflictingConstraints = "cannot satisfy every constraint on package %q"
+	errMarshalLock               = "failed to marshal dependency lock"
+	errWriteLock                 = "failed to write dependency lock"
+)
+
+// lockCmd generates a Configuration's dependency lock.
+type lockCmd struct {
+	// Flags. Keep sorted alphabetically.
+	PackageRoot string `default:"." help:"The directory that contains the Configuration's crossplane.yaml file." short:"f" type:"existingdir"`
+
+	// Common Upbound API configuration.
+	upbound.Flags `embed:""`
+
+	// Internal state. These aren't part of the user-exposed CLI structure.
+	fs afero.Fs
+}
+
+func (c *lockCmd) Help() string {
+	return `
+This command resolves a Configuration's dependencies, and their transitive
+dependencies, to exact package versions and digests. It prints a dependency
+lock that can be added to the spec of the Configuration's crossplane.yaml.
+
+When a Configuration has a dependency lock the package manager installs the
+locked packages instead of resolving dependency version constraints, so the
+Configuration always installs the same dependencies. Building or installing
+a Configuration fails if its lock doesn't satisfy its declared dependencies.
+Run this command again to regenerate the lock whenever the dependencies
+change, or to pick up new versions that satisfy their constraints.
+
+Credentials for the registry are automatically retrieved from xpkg login and
+dockers configuration as fallback.
+
+Examples:
+
+  # Print the dependency lock of the Configuration in the current directory.
+  crossplane xpkg lock
+
+  # Print the dependency lock of the Configuration in a different directory.
+  crossplane xpkg lock -f ./configuration
+`
+}
+
+// AfterApply sets up the lock command.
+func (c *lockCmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run runs the lock cmd.
+func (c *lockCmd) Run(k *kong.Context, logger logging.Logger) error {
+	ctx := context.Background()
+
+	b, err := afero.ReadFile(c.fs, filepath.Join(c.PackageRoot, xpkg.MetaFile))
+	if err != nil {
+		return errors.Wrap(err, errReadMeta)
+	}
+	pp, err := xpkgyaml.New()
+	if err != nil {
+		return err
+	}
+	pkg, err := pp.Parse(ctx, io.NopCloser(bytes.NewReader(b)))
+	if err != nil {
+		return errors.Wrap(err, errReadMeta)
+	}
+	if len(pkg.GetMeta()) != 1 {
+		return errors.New(errNoPackageMeta)
+	}
+	meta, ok := xpkg.TryConvertToPkg(pkg.GetMeta()[0], &pkgmetav1.Configuration{})
+	if !ok {
+		return errors.New(errNotConfiguration)
+	}
+
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.AllowMissingProfile())
+	if err != nil {
+		return err
+	}
+	kc := authn.NewMultiKeychain(
+		authn.NewKeychainFromHelper(credhelper.New(
+			credhelper.WithLogger(logger),
+			credhelper.WithProfile(upCtx.ProfileName),
+			credhelper.WithDomain(upCtx.Domain.Hostname()),
+		)),
+		authn.DefaultKeychain,
+	)
+
+	l := &dependencyLocker{fetcher: &remoteFetcher{keychain: kc}, log: logger}
+	lock, err := l.Lock(ctx, meta.GetDependencies())
+	if err != nil {
+		return err
+	}
+
+	out, err := yaml.Marshal(struct {
+		DependencyLock []pkgmetav1.LockedDependency `json:"dependencyLock"`
+	}{DependencyLock: lock})
+	if err != nil {
+		return errors.Wrap(err, errMarshalLock)
+	}
+	_, err = fmt.Fprint(k.Stdout, string(out))
+	return errors.Wrap(err, errWriteLock)
+}
+
+// A dependencyLocker resolves dependencies to exact packages.
+type dependencyLocker struct {
+	fetcher xpkg.Fetcher
+	log     logging.Logger
+}
+
+// Lock the supplied dependencies, and their transitive dependencies, to the
+// exact packages they resolve to. A package that more than one package
+// depends on is locked to a single version that must satisfy every
+// constraint on it. The lock is sorted by package.
+func (l *dependencyLocker) Lock(ctx context.Context, deps []pkgmetav1.Dependency) ([]pkgmetav1.LockedDependency, error) {
+	locked := make(map[string]pkgmetav1.LockedDependency)
+
+	queue := append([]pkgmetav1.Dependency{}, deps...)
+	for len(queue) > 0 {
+		d := queue[0]
+		queue = queue[1:]
+
+		pkg := xpkg.DependencyPackage(d)
+		if ld, ok := locked[pkg]; ok {
+			// We've already locked this package. The version we locked
+			// must satisfy this dependency's constraint too.
+			if err := xpkg.ValidateDependencyLock([]pkgmetav1.Dependency{d}, []pkgmetav1.LockedDependency{ld}); err != nil {
+				return nil, errors.Wrapf(err, errFmtConflictingConstraints, pkg)
+			}
+			continue
+		}
+
+		ld, transitive, err := l.lock(ctx, pkg, d.Version)
+		if err != nil {
+			return nil, err
+		}
+		l.log.Debug("Locked dependency", "package", pkg, "version", ld.Version, "digest", ld.Digest)
+		locked[pkg] = ld
+		queue = append(queue, transitive...)
+	}
+
+	lock := make([]pkgmetav1.LockedDependency, 0, len(locked))
+	for _, ld := range locked {
+		lock = append(lock, ld)
+	}
+	sort.Slice(lock, func(i, j int) bool { return lock[i].Package < lock[j].Package })
+	return lock, nil
+}
+
+// lock the supplied package to the newest version that satisfies the supplied
+// constraint, which may be a digest. It returns the package's dependencies.
+func (l *dependencyLocker) lock(ctx context.Context, pkg, constraint string) (pkgmetav1.LockedDependency, []pkgmetav1.Dependency, error) {
+	repo, err := name.NewRepository(pkg, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+	if err != nil {
+		return pkgmetav1.LockedDependency{}, nil, errors.Wrapf(err, errFmtParseRepository, pkg)
+	}
+
+	versions, err := l.versions(ctx, repo)
+	if err != nil {
+		return pkgmetav1.LockedDependency{}, nil, err
+	}
+
+	ld := pkgmetav1.LockedDependency{Package: pkg}
+	if h, err := v1.NewHash(constraint); err == nil {
+		// The dependency is pinned to a digest. We still need a version
+		// for the lock, so we find a tag that refers to the digest.
+		ld.Digest = h.String()
+		for _, v := range versions {
+			d, err := l.fetcher.Head(ctx, repo.Tag(v.Original()))
+			if err != nil {
+				return pkgmetav1.LockedDependency{}, nil, errors.Wrapf(err, errFmtHeadPackage, repo.Tag(v.Original()))
+			}
+			if d.Digest == h {
+				ld.Version = v.Original()
+				break
+			}
+		}
+		if ld.Version == "" {
+			return pkgmetav1.LockedDependency{}, nil, errors.Errorf(errFmtNoVersionForDigest, repo, ld.Digest)
+		}
+	} else {
+		c, err := semver.NewConstraint(constraint)
+		if err != nil {
+			return pkgmetav1.LockedDependency{}, nil, errors.Wrapf(err, errFmtInvalidConstraint, pkg, constraint)
+		}
+		for _, v := range versions {
+			if c.Check(v) {
+				ld.Version = v.Original()
+				break
+			}
+		}
+		if ld.Version == "" {
+			return pkgmetav1.LockedDependency{}, nil, errors.Errorf(errFmtNoSatisfyingVersion, repo, constraint)
+		}
+		d, err := l.fetcher.Head(ctx, repo.Tag(ld.Version))
+		if err != nil {
+			return pkgmetav1.LockedDependency{}, nil, errors.Wrapf(err, errFmtHeadPackage, repo.Tag(ld.Version))
+		}
+		ld.Digest = d.Digest.String()
+	}
+
+	// Read the locked package to find its type and its dependencies.
+	ref := repo.Digest(ld.Digest)
+	img, err := l.fetcher.Fetch(ctx, ref)
+	if err != nil {
+		return pkgmetav1.LockedDependency{}, nil, errors.Wrapf(err, errFmtReadDependency, ref)
+	}
+	p, err := xpkg.ReadPackage(img)
+	if err != nil {
+		return pkgmetav1.LockedDependency{}, nil, errors.Wrapf(err, errFmtReadDependency, ref)
+	}
+	if len(p.GetMeta()) != 1 {
+		return pkgmetav1.LockedDependency{}, nil, errors.Wrapf(errors.New(errNoPackageMeta), errFmtReadDependency, ref)
+	}
+	meta, ok := xpkg.TryConvertToPkg(p.GetMeta()[0], &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+	if !ok {
+		return pkgmetav1.LockedDependency{}, nil, errors.Wrapf(errors.New(errUnknownMeta), errFmtReadDependency, ref)
+	}
+
+	switch meta.(type) {
+	case *pkgmetav1.Provider:
+		ld.APIVersion, ld.Kind = pkgv1.ProviderGroupVersionKind.ToAPIVersionAndKind()
+	case *pkgmetav1.Configuration:
+		ld.APIVersion, ld.Kind = pkgv1.ConfigurationGroupVersionKind.ToAPIVersionAndKind()
+	case *pkgmetav1.Function:
+		ld.APIVersion, ld.Kind = pkgv1.FunctionGroupVersionKind.ToAPIVersionAndKind()
+	}
+
+	return ld, meta.GetDependencies(), nil
+}
+
+// versions returns the semantic versions the supplied repository is tagged
+// with, newest first. Tags that aren't semantic versions are ignored.
+func (l *dependencyLocker) versions(ctx context.Context, repo name.Repository) ([]*semver.Version, error) {
+	tags, err := l.fetcher.Tags(ctx, repo.Tag(name.DefaultTag))
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtListTags, repo)
+	}
+	vs := make([]*semver.Version, 0, len(tags))
+	for _, t := range tags {
+		v, err := semver.NewVersion(t)
+		if err != nil {
+			continue
+		}
+		vs = append(vs, v)
+	}
+	sort.Sort(sort.Reverse(semver.Collection(vs)))
+	return vs, nil
+}
+
+// A remoteFetcher fetches packages directly from their registry, using the
+// supplied keychain. It ignores pull secrets.
+type remoteFetcher struct {
+	keychain authn.Keychain
+}
+
+func (f *remoteFetcher) Fetch(ctx context.Context, ref name.Reference, _ ...string) (v1.Image, error) {
+	return remote.Image(ref, remote.WithAuthFromKeychain(f.keychain), remote.WithContext(ctx))
+}
+
+func (f *remoteFetcher) Head(ctx context.Context, ref name.Reference, _ ...string) (*v1.Descriptor, error) {
+	return remote.Head(ref, remote.WithAuthFromKeychain(f.keychain), remote.WithContext(ctx))
+}
+
+func (f *remoteFetcher) Tags(ctx context.Context, ref name.Reference, _ ...string) ([]string, error) {
+	return remote.List(ref.Context(), remote.WithAuthFromKeychain(f.keychain), remote.WithContext(ctx))
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errBuildMetaScheme  = "failed to build package metadata scheme"
+	errFmtReadMetaFile  = "failed to read %s"
+	errFmtWriteMetaFile = "failed to write %s"
+	errFmtMigrate       = "failed to migrate %s"
+	errDecodeMeta       = "failed to decode package metadata"
+	errUnknownMetaKind  = "package metadata is not a Configuration, Provider, or Function"
+	errMarshalMeta      = "failed to marshal package metadata"
+	errFmtNotMigrated   = "%d package(s) have fields that couldn't be migrated - use --force to migrate them anyway"
+)
+
+// migrateCmd migrates package metadata to the latest apiVersion.
+type migrateCmd struct {
+	// Arguments.
+	PackageRoots []string `arg:"" default:"." help:"The directories that contain the packages' crossplane.yaml files." optional:"" type:"existingdir"`
+
+	// Flags. Keep sorted alphabetically.
+	DryRun bool `help:"Print the migrated crossplane.yaml files instead of writing them."`
+	Force  bool `help:"Migrate packages even if some of their fields can't be migrated. Those fields are removed."`
+
+	// Internal state. These aren't part of the user-exposed CLI structure.
+	fs afero.Fs
+}
+
+func (c *migrateCmd) Help() string {
+	return `
+This command migrates packages' crossplane.yaml files to the latest package
+metadata apiVersion, meta.pkg.crossplane.io/v1. It also translates deprecated
+fields. For example it translates dependencies that use the deprecated
+provider, configuration, and function fields to specify an apiVersion, kind,
+and package instead.
+
+A field that can't be migrated, for example because it isn't part of any
+version of the package metadata schema, is reported. By default packages with
+fields that can't be migrated aren't changed. Comments and formatting in
+crossplane.yaml aren't preserved.
+
+Examples:
+
+  # Migrate the package in the current directory.
+  crossplane xpkg migrate
+
+  # Migrate several packages at once.
+  crossplane xpkg migrate ./configuration ./provider ./function
+
+  # Print the migrated crossplane.yaml instead of writing it.
+  crossplane xpkg migrate --dry-run
+`
+}
+
+// AfterApply sets up the migrate command.
+func (c *migrateCmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run runs the migrate cmd.
+func (c *migrateCmd) Run(k *kong.Context) error {
+	s, err := xpkg.BuildMetaScheme()
+	if err != nil {
+		return errors.Wrap(err, errBuildMetaScheme)
+	}
+
+	notMigrated := 0
+	for _, root := range c.PackageRoots {
+		path := filepath.Join(root, xpkg.MetaFile)
+		b, err := afero.ReadFile(c.fs, path)
+		if err != nil {
+			return errors.Wrapf(err, errFmtReadMetaFile, path)
+		}
+
+		m, err := migrateMeta(s, b)
+		if err != nil {
+			return errors.Wrapf(err, errFmtMigrate, path)
+		}
+
+		for _, f := range m.translated {
+			_, _ = fmt.Fprintf(k.Stderr, "%s: translated deprecated field %s\n", path, f)
+		}
+		for _, f := range m.dropped {
+			_, _ = fmt.Fprintf(k.Stderr, "%s: couldn't migrate field %s\n", path, f)
+		}
+		if len(m.dropped) > 0 && !c.Force {
+			notMigrated++
+			continue
+		}
+
+		if c.DryRun {
+			_, _ = fmt.Fprintf(k.Stdout, "---\n# %s\n%s", path, m.out)
+			continue
+		}
+		if err := afero.WriteFile(c.fs, path, m.out, 0o644); err != nil {
+			return errors.Wrapf(err, errFmtWriteMetaFile, path)
+		}
+		_, _ = fmt.Fprintf(k.Stdout, "%s: migrated from %s to %s\n", path, m.from, pkgmetav1.SchemeGroupVersion)
+	}
+
+	if notMigrated > 0 {
+		return errors.Errorf(errFmtNotMigrated, notMigrated)
+	}
+	return nil
+}
+
+// A metaMigration is the result of migrating package metadata.
+type metaMigration struct {
+	// from is the apiVersion the metadata was migrated from.
+	from string
+
+	// out is the migrated metadata, as YAML.
+	out []byte
+
+	// translated fields were deprecated. They were migrated to the fields
+	// that replace them.
+	translated []string
+
+	// dropped fields couldn't be migrated.
+	dropped []string
+}
+
+// migrateMeta migrates the supplied package metadata to the latest apiVersion
+// using the package metadata conversions in the supplied scheme.
+func migrateMeta(s *runtime.Scheme, b []byte) (*metaMigration, error) {
+	in := map[string]any{}
+	if err := yaml.Unmarshal(b, &in); err != nil {
+		return nil, errors.Wrap(err, errDecodeMeta)
+	}
+
+	obj, gvk, err := serializer.NewCodecFactory(s).UniversalDeserializer().Decode(b, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errDecodeMeta)
+	}
+
+	// Any field that doesn't survive decoding isn't part of the schema of
+	// the metadata's current apiVersion, so can't be migrated.
+	decoded, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, errDecodeMeta)
+	}
+	m := &metaMigration{from: gvk.GroupVersion().String()}
+	m.dropped = missingFields("", in, decoded)
+
+	hub, ok := xpkg.TryConvertToPkg(obj, &pkgmetav1.Configuration{}, &pkgmetav1.Provider{}, &pkgmetav1.Function{})
+	if !ok {
+		return nil, errors.New(errUnknownMetaKind)
+	}
+	switch hub.(type) {
+	case *pkgmetav1.Configuration:
+		hub.GetObjectKind().SetGroupVersionKind(pkgmetav1.ConfigurationGroupVersionKind)
+	case *pkgmetav1.Provider:
+		hub.GetObjectKind().SetGroupVersionKind(pkgmetav1.ProviderGroupVersionKind)
+	case *pkgmetav1.Function:
+		hub.GetObjectKind().SetGroupVersionKind(pkgmetav1.FunctionGroupVersionKind)
+	}
+
+	deps := hub.GetDependencies()
+	for i := range deps {
+		if f := translateDependency(&deps[i]); f != "" {
+			m.translated = append(m.translated, fmt.Sprintf("spec.dependsOn[%d].%s", i, f))
+		}
+	}
+
+	out, err := runtime.DefaultUnstructuredConverter.ToUnstructured(hub)
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalMeta)
+	}
+	unstructured.RemoveNestedField(out, "metadata", "creationTimestamp")
+
+	m.out, err = yaml.Marshal(out)
+	return m, errors.Wrap(err, errMarshalMeta)
+}
+
+// translateDependency translates a dependency that uses a deprecated field to
+// specify its package into one that specifies an apiVersion, kind, and
+// package. It returns the deprecated field, if any.
+func translateDependency(d *pkgmetav1.Dependency) string {
+	if d.APIVersion != nil && d.Kind != nil && d.Package != nil {
+		return ""
+	}
+
+	var field string
+	switch {
+	case d.Configuration != nil:
+		field = "configuration"
+		d.Kind = ptr.To(pkgv1.ConfigurationKind)
+		d.Package = d.Configuration
+	case d.Provider != nil:
+		field = "provider"
+		d.Kind = ptr.To(pkgv1.ProviderKind)
+		d.Package = d.Provider
+	case d.Function != nil:
+		field = "function"
+		d.Kind = ptr.To(pkgv1.FunctionKind)
+		d.Package = d.Function
+	default:
+		return ""
+	}
+
+	d.APIVersion = ptr.To(pkgv1.SchemeGroupVersion.String())
+	d.Configuration = nil
+	d.Provider = nil
+	d.Function = nil
+	return field
+}
+
+// missingFields returns the paths of the fields in the supplied input that
+// are missing from the supplied output, sorted.
+func missingFields(prefix string, in, out any) []string {
+	var missing []string
+	switch iv := in.(type) {
+	case map[string]any:
+		ov, _ := out.(map[string]any)
+		for k, v := range iv {
+			p := k
+			if prefix != "" {
+				p = prefix + "." + k
+			}
+			o, ok := ov[k]
+			if !ok {
+				// A null, zero, or empty field has no value to migrate.
+				if !isEmpty(v) {
+					missing = append(missing, p)
+				}
+				continue
+			}
+			missing = append(missing, missingFields(p, v, o)...)
+		}
+	case []any:
+		ov, _ := out.([]any)
+		for i, v := range iv {
+			if i >= len(ov) {
+				break
+			}
+			missing = append(missing, missingFields(prefix+"["+strconv.Itoa(i)+"]", v, ov[i])...)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func isEmpty(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case bool:
+		return !t
+	case float64:
+		return t == 0
+	case map[string]any:
+		return len(t) == 0
+	case []any:
+		return len(t) == 0
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+func TestMigrateMeta(t *testing.T) {
+	type want struct {
+		out        string
+		from       string
+		translated []string
+		dropped    []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		in     string
+		want   want
+	}{
+		"AlreadyLatest": {
+			reason: "Metadata that's already the latest apiVersion should be unchanged.",
+			in: `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: cool
+spec:
+  dependsOn:
+  - apiVersion: pkg.crossplane.io/v1
+    kind: Provider
+    package: xpkg.crossplane.io/crossplane-contrib/provider-nop
+    version: ">=v0.1.0"
+`,
+			want: want{
+				from: "meta.pkg.crossplane.io/v1",
+				out: `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: cool
+spec:
+  dependsOn:
+  - apiVersion: pkg.crossplane.io/v1
+    kind: Provider
+    package: xpkg.crossplane.io/crossplane-contrib/provider-nop
+    version: ">=v0.1.0"
+`,
+			},
+		},
+		"DeprecatedDependency": {
+			reason: "Older metadata should be migrated, translating deprecated dependency fields.",
+			in: `
+apiVersion: meta.pkg.crossplane.io/v1alpha1
+kind: Configuration
+metadata:
+  name: cool
+spec:
+  dependsOn:
+  - provider: xpkg.crossplane.io/crossplane-contrib/provider-nop
+    version: ">=v0.1.0"
+`,
+			want: want{
+				from: "meta.pkg.crossplane.io/v1alpha1",
+				out: `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: cool
+spec:
+  dependsOn:
+  - apiVersion: pkg.crossplane.io/v1
+    kind: Provider
+    package: xpkg.crossplane.io/crossplane-contrib/provider-nop
+    version: ">=v0.1.0"
+`,
+				translated: []string{"spec.dependsOn[0].provider"},
+			},
+		},
+		"UnknownField": {
+			reason: "Fields that aren't part of the metadata's schema should be reported as dropped.",
+			in: `
+apiVersion: meta.pkg.crossplane.io/v1alpha1
+kind: Configuration
+metadata:
+  name: cool
+spec:
+  coolness: 11
+`,
+			want: want{
+				from: "meta.pkg.crossplane.io/v1alpha1",
+				out: `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: cool
+spec: {}
+`,
+				dropped: []string{"spec.coolness"},
+			},
+		},
+	}
+
+	s, err := xpkg.BuildMetaScheme()
+	if err != nil {
+		t.Fatalf("BuildMetaScheme(): %s", err)
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, err := migrateMeta(s, []byte(tc.in))
+			if err != nil {
+				t.Fatalf("\n%s\nmigrateMeta(...): unexpected error: %s", tc.reason, err)
+			}
+
+			want, got := map[string]any{}, map[string]any{}
+			if err := yaml.Unmarshal([]byte(tc.want.out), &want); err != nil {
+				t.Fatalf("cannot unmarshal wanted output: %s", err)
+			}
+			if err := yaml.Unmarshal(m.out, &got); err != nil {
+				t.Fatalf("\n%s\nmigrateMeta(...): cannot unmarshal output: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nmigrateMeta(...): -want output, +got output:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.from, m.from); diff != "" {
+				t.Errorf("\n%s\nmigrateMeta(...): -want from, +got from:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.translated, m.translated); diff != "" {
+				t.Errorf("\n%s\nmigrateMeta(...): -want translated, +got translated:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.dropped, m.dropped); diff != "" {
+				t.Errorf("\n%s\nmigrateMeta(...): -want dropped, +got dropped:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
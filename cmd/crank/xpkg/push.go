@@ -44,6 +44,9 @@ const (
 	errGetwd           = "failed to get working directory while searching for package"
 	errFindPackageinWd = "failed to find a package in current working directory"
 	errAnnotateLayers  = "failed to propagate xpkg annotations from OCI image config file to image layers"
+	errAnnotateImage   = "failed to propagate package annotations from OCI image config file to image manifest"
+	errNoSigningKey    = "--signing-key is required to sign a package"
+	errGetPushDigest   = "failed to get digest of package to sign"
 
 	errFmtNewTag        = "failed to parse package tag %q"
 	errFmtReadPackage   = "failed to read package file %s"
@@ -53,8 +56,21 @@ const (
 	errFmtGetMediaType  = "failed to get media type of package file %s"
 	errFmtGetConfigFile = "failed to get OCI config file of package file %s"
 	errFmtWriteIndex    = "failed to push an OCI image index of %d packages"
+	errFmtSignPackage   = "failed to sign package %s; it was pushed but not tagged"
+	errFmtTagPackage    = "failed to tag signed package %s"
 )
 
+// A packageSigner signs a package that was pushed to an OCI registry.
+type packageSigner interface {
+	Sign(ctx context.Context, d name.Digest, opts ...remote.Option) error
+}
+
+// A signable package can be pushed by digest and tagged once signed.
+type signable interface {
+	remote.Taggable
+	Digest() (v1.Hash, error)
+}
+
 // pushCmd pushes a package.
 type pushCmd struct {
 	// Arguments.
@@ -62,12 +78,15 @@ type pushCmd struct {
 
 	// Flags. Keep sorted alphabetically.
 	PackageFiles []string `help:"A comma-separated list of xpkg files to push." placeholder:"PATH" short:"f" type:"existingfile"`
+	Sign         bool     `help:"Sign the package with cosign after pushing it. The package is only tagged once it's signed."`
+	SigningKey   string   `help:"Path to the cosign private key used to sign the package. Its password is read from the COSIGN_PASSWORD environment variable." placeholder:"PATH" type:"existingfile"`
 
 	// Common Upbound API configuration.
 	upbound.Flags `embed:""`
 
 	// Internal state. These aren't part of the user-exposed CLI structure.
-	fs afero.Fs
+	fs     afero.Fs
+	signer packageSigner
 }
 
 func (c *pushCmd) Help() string {
@@ -84,12 +103,26 @@ Examples:
 
   # Push the xpkg file in the current directory to a different registry.
   crossplane xpkg push index.docker.io/crossplane/function-example:v1.0.0
+
+  # Push a package and sign it using a cosign private key.
+  COSIGN_PASSWORD=secret crossplane xpkg push --sign --signing-key=cosign.key crossplane/function-example:v1.0.0
 `
 }
 
 // AfterApply sets the tag for the parent push command.
 func (c *pushCmd) AfterApply() error {
 	c.fs = afero.NewOsFs()
+	if !c.Sign {
+		return nil
+	}
+	if c.SigningKey == "" {
+		return errors.New(errNoSigningKey)
+	}
+	s, err := newCosignSigner(c.fs, c.SigningKey, []byte(os.Getenv("COSIGN_PASSWORD")))
+	if err != nil {
+		return err
+	}
+	c.signer = s
 	return nil
 }
 
@@ -139,11 +172,18 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocognit // This f
 		if err != nil {
 			return errors.Wrapf(err, errAnnotateLayers)
 		}
-		if err := remote.Write(tag, img, remote.WithAuthFromKeychain(kc)); err != nil {
-			return errors.Wrapf(err, errFmtPushPackage, c.PackageFiles[0])
+		img, err = xpkg.AnnotateManifest(img)
+		if err != nil {
+			return errors.Wrap(err, errAnnotateImage)
+		}
+		write := func(ref name.Reference) error {
+			if err := remote.Write(ref, img, remote.WithAuthFromKeychain(kc)); err != nil {
+				return errors.Wrapf(err, errFmtPushPackage, c.PackageFiles[0])
+			}
+			logger.Debug("Pushed package", "path", c.PackageFiles[0], "ref", ref.String())
+			return nil
 		}
-		logger.Debug("Pushed package", "path", c.PackageFiles[0], "ref", tag.String())
-		return nil
+		return c.push(context.Background(), tag, img, write, remote.WithAuthFromKeychain(kc))
 	}
 
 	// If there's more than one package file we'll write (push) them all by
@@ -163,6 +203,11 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocognit // This f
 				return errors.Wrapf(err, errAnnotateLayers)
 			}
 
+			img, err = xpkg.AnnotateManifest(img)
+			if err != nil {
+				return errors.Wrap(err, errAnnotateImage)
+			}
+
 			d, err := img.Digest()
 			if err != nil {
 				return errors.Wrapf(err, errFmtGetDigest, file)
@@ -206,9 +251,35 @@ func (c *pushCmd) Run(logger logging.Logger) error { //nolint:gocognit // This f
 		return err
 	}
 
-	if err := remote.WriteIndex(tag, mutate.AppendManifests(empty.Index, adds...), remote.WithAuthFromKeychain(kc)); err != nil {
-		return errors.Wrapf(err, errFmtWriteIndex, len(adds))
+	idx := mutate.AppendManifests(empty.Index, adds...)
+	write := func(ref name.Reference) error {
+		if err := remote.WriteIndex(ref, idx, remote.WithAuthFromKeychain(kc)); err != nil {
+			return errors.Wrapf(err, errFmtWriteIndex, len(adds))
+		}
+		logger.Debug("Wrote OCI index", "ref", ref.String(), "manifests", len(adds))
+		return nil
 	}
-	logger.Debug("Wrote OCI index", "ref", tag.String(), "manifests", len(adds))
-	return nil
+	return c.push(context.Background(), tag, idx, write, remote.WithAuthFromKeychain(kc))
+}
+
+// push writes the supplied package to the supplied tag. If the package should
+// be signed it's written by digest, signed, and only tagged once it's signed.
+// This ensures a signing failure never leaves an unsigned package at the tag.
+func (c *pushCmd) push(ctx context.Context, tag name.Tag, pkg signable, write func(ref name.Reference) error, opts ...remote.Option) error {
+	if c.signer == nil {
+		return write(tag)
+	}
+
+	h, err := pkg.Digest()
+	if err != nil {
+		return errors.Wrap(err, errGetPushDigest)
+	}
+	d := tag.Context().Digest(h.String())
+	if err := write(d); err != nil {
+		return err
+	}
+	if err := c.signer.Sign(ctx, d, opts...); err != nil {
+		return errors.Wrapf(err, errFmtSignPackage, d)
+	}
+	return errors.Wrapf(remote.Tag(tag, pkg, append(opts, remote.WithContext(ctx))...), errFmtTagPackage, tag)
 }
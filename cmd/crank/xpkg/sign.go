@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errReadSigningKey  = "failed to read signing key"
+	errLoadSigningKey  = "failed to load signing key"
+	errMarshalPayload  = "failed to marshal signature payload"
+	errSignPayload     = "failed to sign signature payload"
+	errNewSignature    = "failed to create signature"
+	errGetSignedEntity = "failed to get package to sign"
+	errAttachSignature = "failed to attach signature to package"
+	errWriteSignature  = "failed to push signature"
+)
+
+// A cosignSigner signs packages in an OCI registry using a cosign private key.
+// Signatures are pushed to the registry alongside the package, where they can
+// be verified using the matching public key.
+type cosignSigner struct {
+	sv signature.SignerVerifier
+}
+
+// newCosignSigner returns a signer that signs packages using the cosign
+// private key at the supplied path, decrypted using the supplied password.
+func newCosignSigner(fs afero.Fs, path string, password []byte) (*cosignSigner, error) {
+	key, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadSigningKey)
+	}
+	sv, err := cosign.LoadPrivateKey(key, password)
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadSigningKey)
+	}
+	return &cosignSigner{sv: sv}, nil
+}
+
+// Sign the package at the supplied digest, and push the signature to the
+// package's repository.
+func (s *cosignSigner) Sign(ctx context.Context, d name.Digest, opts ...remote.Option) error {
+	p, err := payload.Cosign{Image: d}.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, errMarshalPayload)
+	}
+	sig, err := s.sv.SignMessage(bytes.NewReader(p), options.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, errSignPayload)
+	}
+	ociSig, err := static.NewSignature(p, base64.StdEncoding.EncodeToString(sig))
+	if err != nil {
+		return errors.Wrap(err, errNewSignature)
+	}
+
+	ro := ociremote.WithRemoteOptions(append(opts, remote.WithContext(ctx))...)
+	se, err := ociremote.SignedEntity(d, ro)
+	if err != nil {
+		return errors.Wrap(err, errGetSignedEntity)
+	}
+	se, err = mutate.AttachSignatureToEntity(se, ociSig)
+	if err != nil {
+		return errors.Wrap(err, errAttachSignature)
+	}
+	return errors.Wrap(ociremote.WriteSignatures(d.Repository, se, ro), errWriteSignature)
+}
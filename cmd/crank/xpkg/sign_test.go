@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/base64"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type packageSignerFn func(ctx context.Context, d name.Digest, opts ...remote.Option) error
+
+func (fn packageSignerFn) Sign(ctx context.Context, d name.Digest, opts ...remote.Option) error {
+	return fn(ctx, d, opts...)
+}
+
+func TestCosignSigner(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	reg := strings.TrimPrefix(s.URL, "http://")
+
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return []byte("secret"), nil })
+	if err != nil {
+		t.Fatalf("GenerateKeyPair(...): %v", err)
+	}
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "cosign.key", keys.PrivateBytes, 0o600)
+
+	img, _ := random.Image(1024, 1)
+	h, _ := img.Digest()
+	d, _ := name.NewDigest(reg + "/crossplane/function-example@" + h.String())
+	if err := remote.Write(d, img); err != nil {
+		t.Fatalf("remote.Write(...): %v", err)
+	}
+
+	type args struct {
+		password string
+	}
+	type want struct {
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Signed": {
+			reason: "We should push a signature that verifies using the key's public key.",
+			args: args{
+				password: "secret",
+			},
+		},
+		"WrongPassword": {
+			reason: "We should return an error if we can't decrypt the signing key.",
+			args: args{
+				password: "wrong",
+			},
+			want: want{
+				err: errors.Wrap(errors.New("decrypt: encrypted: decryption failed"), errLoadSigningKey),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			signer, err := newCosignSigner(fs, "cosign.key", []byte(tc.args.password))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nnewCosignSigner(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			if err := signer.Sign(context.Background(), d); err != nil {
+				t.Fatalf("\n%s\nSign(...): %v", tc.reason, err)
+			}
+
+			se, err := ociremote.SignedEntity(d)
+			if err != nil {
+				t.Fatalf("\n%s\nociremote.SignedEntity(...): %v", tc.reason, err)
+			}
+			sigs, _ := se.Signatures()
+			got, _ := sigs.Get()
+			if len(got) != 1 {
+				t.Fatalf("\n%s\nSign(...): want 1 signature, got %d", tc.reason, len(got))
+			}
+
+			pub, _ := cosign.PemToECDSAKey(keys.PublicBytes)
+			v, _ := signature.LoadECDSAVerifier(pub, crypto.SHA256)
+			p, _ := got[0].Payload()
+			b64, _ := got[0].Base64Signature()
+			raw, _ := base64.StdEncoding.DecodeString(b64)
+			if err := v.VerifySignature(bytes.NewReader(raw), bytes.NewReader(p)); err != nil {
+				t.Errorf("\n%s\nVerifySignature(...): %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestPush(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	s := httptest.NewServer(registry.New())
+	defer s.Close()
+	reg := strings.TrimPrefix(s.URL, "http://")
+
+	img, _ := random.Image(1024, 1)
+	h, _ := img.Digest()
+
+	type want struct {
+		// Whether the package should be tagged.
+		tagged bool
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		repo   string
+		signer packageSigner
+		want   want
+	}{
+		"Unsigned": {
+			reason: "We should push an unsigned package directly to its tag.",
+			repo:   "unsigned",
+			want: want{
+				tagged: true,
+			},
+		},
+		"Signed": {
+			reason: "We should tag a signed package once it's signed.",
+			repo:   "signed",
+			signer: packageSignerFn(func(_ context.Context, d name.Digest, _ ...remote.Option) error {
+				if d.DigestStr() != h.String() {
+					t.Errorf("Sign(...): want digest %s, got %s", h, d.DigestStr())
+				}
+				return nil
+			}),
+			want: want{
+				tagged: true,
+			},
+		},
+		"SignError": {
+			reason: "We shouldn't tag a package that we fail to sign.",
+			repo:   "sign-error",
+			signer: packageSignerFn(func(_ context.Context, _ name.Digest, _ ...remote.Option) error {
+				return errBoom
+			}),
+			want: want{
+				tagged: false,
+				err:    errors.Wrapf(errBoom, errFmtSignPackage, reg+"/crossplane/sign-error@"+h.String()),
+			},
+		},
+	}
+
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			tag, _ := name.NewTag(reg + "/crossplane/" + tc.repo + ":v1.0.0")
+			c := &pushCmd{signer: tc.signer}
+			write := func(ref name.Reference) error { return remote.Write(ref, img) }
+
+			err := c.push(context.Background(), tag, img, write)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\npush(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			_, err = remote.Head(tag)
+			if diff := cmp.Diff(tc.want.tagged, err == nil); diff != "" {
+				t.Errorf("\n%s\npush(...): -want tagged, +got tagged:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
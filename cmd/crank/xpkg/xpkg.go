@@ -22,13 +22,17 @@ package xpkg
 // Cmd contains commands for interacting with xpkgs.
 type Cmd struct {
 	// Keep subcommands sorted alphabetically.
-	Build   buildCmd   `cmd:"" help:"Build a new package."`
-	Init    initCmd    `cmd:"" help:"Initialize a new package from a template."`
-	Install installCmd `cmd:"" help:"Install a package in a control plane."`
-	Login   loginCmd   `cmd:"" help:"Login to the default package registry."`
-	Logout  logoutCmd  `cmd:"" help:"Logout of the default package registry."`
-	Push    pushCmd    `cmd:"" help:"Push a package to a registry."`
-	Update  updateCmd  `cmd:"" help:"Update a package in a control plane."`
+	BatchBuild batchBuildCmd `cmd:"" help:"Build multiple packages found in a directory tree."`
+	Build      buildCmd      `cmd:"" help:"Build a new package."`
+	Init       initCmd       `cmd:"" help:"Initialize a new package from a template."`
+	Inspect    inspectCmd    `cmd:"" help:"Print a summary of a package."`
+	Install    installCmd    `cmd:"" help:"Install a package in a control plane."`
+	Lock       lockCmd       `cmd:"" help:"Lock a Configuration's dependencies to exact packages."`
+	Login      loginCmd      `cmd:"" help:"Login to the default package registry."`
+	Logout     logoutCmd     `cmd:"" help:"Logout of the default package registry."`
+	Migrate    migrateCmd    `cmd:"" help:"Migrate package metadata to the latest apiVersion."`
+	Push       pushCmd       `cmd:"" help:"Push a package to a registry."`
+	Update     updateCmd     `cmd:"" help:"Update a package in a control plane."`
 }
 
 // Help prints out the help for the xpkg command.
@@ -28,6 +28,7 @@ import (
 
 	"github.com/alecthomas/kong"
 	"github.com/spf13/afero"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
@@ -95,12 +96,29 @@ type startCommand struct {
 	CABundlePath   string `env:"CA_BUNDLE_PATH"            help:"Additional CA bundle to use when fetching packages from registry."`
 	UserAgent      string `default:"${default_user_agent}" env:"USER_AGENT"                                                         help:"The User-Agent header that will be set on all package requests."`
 
-	PackageRuntime string `default:"Deployment" env:"PACKAGE_RUNTIME" help:"The package runtime to use for packages with a runtime (e.g. Providers and Functions)"`
+	PackageRuntime string `default:"Deployment"  env:"PACKAGE_RUNTIME"                                                                                                                                                                                                                                              help:"The package runtime to use for packages with a runtime (e.g. Providers and Functions)"`
+	PackageVariant string `env:"PACKAGE_VARIANT" help:"The variant of a package's base layer to unpack, for packages that offer variants (e.g. a slim variant for constrained clusters). Packages that don't offer it are unpacked from their default base layer. Only applies to packages that aren't yet cached." placeholder:"VARIANT"`
+
+	AllowedRegistries []string `env:"ALLOWED_REGISTRIES" help:"Only install packages from these registries. Each is a registry host, which must match exactly, optionally followed by a repository path prefix that must match whole path segments. For example xpkg.upbound.io or xpkg.upbound.io/crossplane-contrib. Tags and digests aren't evaluated. Packages from any registry may be installed if none are specified." placeholder:"REGISTRY" sep:","`
+
+	DependencyMirror string `env:"DEPENDENCY_MIRROR" help:"Registry to copy dependency packages to, and install them from, when dependency mirroring is enabled. For example registry.example.org/mirror." placeholder:"REGISTRY"`
+
+	PreserveCRDConversion bool `env:"PRESERVE_CRD_CONVERSION" help:"Leave the conversion config (spec.conversion) of existing package CRDs untouched, for example so cert-manager can manage their conversion webhooks. The rest of each CRD is still kept in sync with its package. New CRDs are created with the conversion config their package specifies, without Crossplane's webhook service or CA bundle."`
 
 	SyncInterval                     time.Duration `default:"1h"  help:"How often all resources will be double-checked for drift from the desired state."                      short:"s"`
 	PollInterval                     time.Duration `default:"1m"  help:"How often individual resources will be checked for drift from the desired state."`
+	PollJitter                       float64       `default:"0.1" help:"Randomly vary each composite resource's poll interval by up to plus or minus this fraction of --poll-interval, uniformly distributed. For example 0.1 varies a 1m poll interval between 54s and 66s. Reconciles triggered by changes aren't delayed. Zero disables it."`
+	MinReconcileInterval             time.Duration `default:"0s"  help:"The minimum interval between compositions of a composite resource that hasn't changed. Changes to its spec or Composition, and the crossplane.io/reconcile-requested-at annotation, bypass this minimum. Zero disables it."`
+	ReadinessStableFor               time.Duration `default:"0s"  help:"How long each composed resource must be continuously ready before its composite resource is considered ready. A composed resource that becomes unready must be ready for this long again. Compositions may override it. Zero disables it."`
 	MaxReconcileRate                 int           `default:"100" help:"The global maximum rate per second at which resources may checked for drift from the desired state."`
+	MaxConsecutiveFailures           int           `default:"0"   help:"How many consecutive times a composite resource may fail to reconcile before it's marked Stalled and retried only every --stalled-backoff. A successful reconcile or a spec change clears it. Zero disables it."`
+	StalledBackoff                   time.Duration `default:"10m" help:"How long to wait before retrying a composite resource that's Stalled. See --max-consecutive-failures."`
+	MaxCompositionDepth              int           `default:"0"   help:"How deeply composite resources may be nested under a top-level composite resource, which is at depth zero. A composite resource nested more deeply isn't composed. Depth is tracked using the crossplane.io/composition-depth label, which each composite resource sets on the resources it composes to its own depth plus one. Zero disables it."`
+	ObservationCacheMaxAge           time.Duration `default:"0s"  help:"How long a composite resource may reuse the connection details of a composed resource whose resourceVersion hasn't changed, instead of reading its connection secret again. Bounds how stale connection details may be. Zero disables it."`
 	MaxConcurrentPackageEstablishers int           `default:"10"  help:"The the maximum number of goroutines to use for establishing Providers, Configurations and Functions."`
+	GracefulShutdownTimeout          time.Duration `default:"30s" help:"How long to wait for in-flight reconciles to finish or cleanly abort when Crossplane stops, for example during an upgrade. Crossplane keeps its leader election lease until they do."`
+
+	CompositeEventsOnClaim bool `help:"Also record events that target only a composite resource (XR) on its claim."`
 
 	WebhookEnabled bool `default:"true" env:"WEBHOOK_ENABLED" help:"Enable webhook configuration."`
 
@@ -109,11 +127,16 @@ type startCommand struct {
 	TLSClientSecretName string `env:"TLS_CLIENT_SECRET_NAME" help:"The name of the TLS Secret that will be store Crossplane's client certificate."`
 	TLSClientCertsDir   string `env:"TLS_CLIENT_CERTS_DIR"   help:"The path of the folder which will store TLS client certificate of Crossplane."`
 
-	EnableExternalSecretStores      bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
-	EnableRealtimeCompositions      bool `group:"Alpha Features:" help:"Enable support for realtime compositions, i.e. watching composed resources and reconciling compositions immediately when any of the composed resources is updated."`
-	EnableSSAClaims                 bool `group:"Alpha Features:" help:"Enable support for using Kubernetes server-side apply to sync claims with composite resources (XRs)."`
-	EnableDependencyVersionUpgrades bool `group:"Alpha Features:" help:"Enable support for upgrading dependency versions when the parent package is updated."`
-	EnableSignatureVerification     bool `group:"Alpha Features:" help:"Enable support for package signature verification via ImageConfig API."`
+	EnableExternalSecretStores       bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
+	EnableRealtimeCompositions       bool `group:"Alpha Features:" help:"Enable support for realtime compositions, i.e. watching composed resources and reconciling compositions immediately when any of the composed resources is updated."`
+	EnableSSAClaims                  bool `group:"Alpha Features:" help:"Enable support for using Kubernetes server-side apply to sync claims with composite resources (XRs)."`
+	EnableDependencyVersionUpgrades  bool `group:"Alpha Features:" help:"Enable support for upgrading dependency versions when the parent package is updated."`
+	EnableSignatureVerification      bool `group:"Alpha Features:" help:"Enable support for package signature verification via ImageConfig API."`
+	EnableCompositionStepAnnotations bool `group:"Alpha Features:" help:"Enable annotating composed resources with the Composition pipeline step that last modified them."`
+	EnableDependencyMirroring        bool `group:"Alpha Features:" help:"Enable copying dependency packages to the registry specified by --dependency-mirror, and installing them from there."`
+	EnableTracing                    bool `group:"Alpha Features:" help:"Enable emitting OpenTelemetry traces of composite resource reconciles, including each Composition Function call and composed resource apply. Traces are exported using OTLP over gRPC, configured by the standard OTEL_EXPORTER_OTLP_* environment variables."`
+	EnableComposedDryRunApply        bool `group:"Alpha Features:" help:"Enable dry-run applying all of a composite resource's composed resources before applying any of them. Only applies to Compositions in Pipeline mode. If any dry-run fails none of the composite resource's composed resources are changed. Doubles the composed resource apply requests Crossplane makes to the API server."`
+	EnableComposedClaimNamespace     bool `group:"Alpha Features:" help:"Enable creating namespaced composed resources that don't specify a namespace in the namespace of the claim their composite resource is bound to. Any namespace specified for a cluster scoped composed resource is ignored."`
 
 	EnableCompositionWebhookSchemaValidation bool `default:"true" group:"Beta Features:" help:"Enable support for Composition validation using schemas."`
 	EnableDeploymentRuntimeConfigs           bool `default:"true" group:"Beta Features:" help:"Enable support for Deployment Runtime Configs."`
@@ -187,6 +210,11 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		LeaseDuration:                 func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:                 func() *time.Duration { d := 50 * time.Second; return &d }(),
 
+		// Give in-flight reconciles time to finish or cleanly abort before
+		// we release our leader election lease, so the next leader doesn't
+		// start reconciling while we're still writing.
+		GracefulShutdownTimeout: &c.GracefulShutdownTimeout,
+
 		PprofBindAddress:       c.Profile,
 		HealthProbeBindAddress: ":8081",
 	})
@@ -199,6 +227,10 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 	})
 	defer eb.Shutdown()
 
+	if c.PollJitter < 0 || c.PollJitter >= 1 {
+		return errors.New("--poll-jitter must be at least 0 and less than 1")
+	}
+
 	o := controller.Options{
 		Logger:                  log,
 		MaxConcurrentReconciles: c.MaxReconcileRate,
@@ -223,11 +255,13 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		return errors.New("Crossplane no longer supports loading and patching EnvironmentConfigs natively. Please use function-environment-configs instead. The --enable-environment-configs flag will be removed in a future release.")
 	}
 
-	clienttls, err := certificates.LoadMTLSConfig(
+	// Functions require mutual TLS. We reload the client certificates when
+	// they're rotated, so new connections to functions use them without us
+	// needing to restart.
+	clienttls, err := xfn.NewReloadingClientTLS(
 		filepath.Join(c.TLSClientCertsDir, initializer.SecretKeyCACert),
 		filepath.Join(c.TLSClientCertsDir, corev1.TLSCertKey),
-		filepath.Join(c.TLSClientCertsDir, corev1.TLSPrivateKeyKey),
-		false)
+		filepath.Join(c.TLSClientCertsDir, corev1.TLSPrivateKeyKey))
 	if err != nil {
 		return errors.Wrap(err, "cannot load client TLS certificates")
 	}
@@ -235,12 +269,35 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 	m := xfn.NewMetrics()
 	metrics.Registry.MustRegister(m)
 
-	// We want all XR controllers to share the same gRPC clients.
-	functionRunner := xfn.NewPackagedFunctionRunner(mgr.GetClient(),
+	ro := []xfn.PackagedFunctionRunnerOption{
 		xfn.WithLogger(log),
-		xfn.WithTLSConfig(clienttls),
+		xfn.WithTLSConfig(clienttls.Config()),
 		xfn.WithInterceptorCreators(m),
-	)
+	}
+
+	var tp trace.TracerProvider
+	if c.EnableTracing {
+		o.Features.Enable(features.EnableAlphaTracing)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaTracing)
+
+		stp, err := newTracerProvider(ctx)
+		if err != nil {
+			return errors.Wrap(err, "cannot setup tracing")
+		}
+		// Flush any buffered spans when we stop.
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = stp.Shutdown(ctx)
+		}()
+		tp = stp
+
+		// Trace Function calls, and propagate our span context to Functions.
+		ro = append(ro, xfn.WithTracerProvider(tp))
+	}
+
+	// We want all XR controllers to share the same gRPC clients.
+	functionRunner := xfn.NewPackagedFunctionRunner(mgr.GetClient(), ro...)
 
 	// Periodically remove clients for Functions that no longer exist.
 	go functionRunner.GarbageCollectConnections(ctx, 10*time.Minute)
@@ -290,6 +347,25 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		o.Features.Enable(features.EnableAlphaSignatureVerification)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaSignatureVerification)
 	}
+	if c.EnableCompositionStepAnnotations {
+		o.Features.Enable(features.EnableAlphaCompositionStepAnnotations)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaCompositionStepAnnotations)
+	}
+	if c.EnableComposedDryRunApply {
+		o.Features.Enable(features.EnableAlphaComposedDryRunApply)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaComposedDryRunApply)
+	}
+	if c.EnableComposedClaimNamespace {
+		o.Features.Enable(features.EnableAlphaComposedClaimNamespace)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaComposedClaimNamespace)
+	}
+	if c.EnableDependencyMirroring {
+		if c.DependencyMirror == "" {
+			return errors.New("--dependency-mirror is required when dependency mirroring is enabled")
+		}
+		o.Features.Enable(features.EnableAlphaDependencyMirroring)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaDependencyMirroring)
+	}
 
 	// Claim and XR controllers are started and stopped dynamically by the
 	// ControllerEngine below. When realtime compositions are enabled, they also
@@ -371,9 +447,18 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 	}
 
 	ao := apiextensionscontroller.Options{
-		Options:          o,
-		ControllerEngine: ce,
-		FunctionRunner:   functionRunner,
+		Options:                o,
+		ControllerEngine:       ce,
+		FunctionRunner:         functionRunner,
+		CompositeEventsOnClaim: c.CompositeEventsOnClaim,
+		PollJitter:             c.PollJitter,
+		MinReconcileInterval:   c.MinReconcileInterval,
+		ReadinessStableFor:     c.ReadinessStableFor,
+		MaxConsecutiveFailures: c.MaxConsecutiveFailures,
+		StalledBackoff:         c.StalledBackoff,
+		MaxCompositionDepth:    c.MaxCompositionDepth,
+		ObservationCacheMaxAge: c.ObservationCacheMaxAge,
+		TracerProvider:         tp,
 	}
 
 	if err := apiextensions.Setup(mgr, ao); err != nil {
@@ -391,15 +476,24 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 			c.PackageRuntime, pkgcontroller.PackageRuntimeDeployment, pkgcontroller.PackageRuntimeExternal)
 	}
 
+	allowed, err := xpkg.NewRegistryAllowList(c.AllowedRegistries, c.Registry)
+	if err != nil {
+		return errors.Wrap(err, "cannot parse allowed registries")
+	}
+
 	po := pkgcontroller.Options{
 		Options:                          o,
 		Cache:                            xpkg.NewFsPackageCache(c.CacheDir, afero.NewOsFs()),
 		Namespace:                        c.Namespace,
 		ServiceAccount:                   c.ServiceAccount,
 		DefaultRegistry:                  c.Registry,
-		FetcherOptions:                   []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent)},
+		DependencyMirror:                 c.DependencyMirror,
+		AllowedRegistries:                allowed,
+		FetcherOptions:                   []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent), xpkg.WithRegistryProxies(xpkg.NewImageConfigStore(mgr.GetClient(), c.Namespace))},
 		PackageRuntime:                   pr,
+		PackageVariant:                   c.PackageVariant,
 		MaxConcurrentPackageEstablishers: c.MaxConcurrentPackageEstablishers,
+		PreserveCRDConversion:            c.PreserveCRDConversion,
 	}
 
 	// We need to set the TUF_ROOT environment variable so that the TUF client
@@ -0,0 +1,236 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package connection manages connection details stored in external secret
+// stores configured by a secrets.crossplane.io StoreConfig.
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+)
+
+// Error strings.
+const (
+	errConnectStore    = "cannot connect to secret store"
+	errWriteStore      = "cannot write to secret store"
+	errReadStore       = "cannot read from secret store"
+	errDeleteFromStore = "cannot delete from secret store"
+	errGetStoreConfig  = "cannot get store config"
+	errSecretConflict  = "cannot establish control of existing connection secret"
+)
+
+// A DetailsManagerOption configures a DetailsManager.
+type DetailsManagerOption func(*DetailsManager)
+
+// WithStoreBuilder configures the StoreBuilder to use.
+func WithStoreBuilder(sb connection.StoreBuilderFn) DetailsManagerOption {
+	return func(m *DetailsManager) {
+		m.storeBuilder = sb
+	}
+}
+
+// WithTLSConfig configures the TLS config to use.
+func WithTLSConfig(tcfg *tls.Config) DetailsManagerOption {
+	return func(m *DetailsManager) {
+		m.tcfg = tcfg
+	}
+}
+
+// WithPropagatedKeyRenames specifies connection secret keys that should be
+// renamed when they're propagated. Each key of the supplied map is a key of the
+// source's connection secret, and each value is the key it should be written
+// to in the destination's connection secret.
+func WithPropagatedKeyRenames(renames map[string]string) DetailsManagerOption {
+	return func(m *DetailsManager) {
+		m.renames = renames
+	}
+}
+
+// A DetailsManager publishes, unpublishes, fetches, and propagates connection
+// details using the secret store configured by a StoreConfig. It's like
+// crossplane-runtime's DetailsManager, but also applies the StoreConfig's key
+// transforms.
+type DetailsManager struct {
+	client       client.Client
+	storeBuilder connection.StoreBuilderFn
+	tcfg         *tls.Config
+	renames      map[string]string
+}
+
+// NewDetailsManager returns a new DetailsManager.
+func NewDetailsManager(c client.Client, o ...DetailsManagerOption) *DetailsManager {
+	m := &DetailsManager{
+		client:       c,
+		storeBuilder: connection.RuntimeStoreBuilder,
+	}
+
+	for _, mo := range o {
+		mo(m)
+	}
+
+	return m
+}
+
+// PublishConnection publishes the supplied ConnectionDetails to a secret on
+// the configured connection Store.
+func (m *DetailsManager) PublishConnection(ctx context.Context, so resource.ConnectionSecretOwner, conn managed.ConnectionDetails) (bool, error) {
+	// This resource does not want to expose a connection secret.
+	p := so.GetPublishConnectionDetailsTo()
+	if p == nil {
+		return false, nil
+	}
+
+	ss, err := m.connectStore(ctx, p)
+	if err != nil {
+		return false, errors.Wrap(err, errConnectStore)
+	}
+
+	changed, err := ss.WriteKeyValues(ctx, store.NewSecret(so, store.KeyValues(conn)), connection.SecretToWriteMustBeOwnedBy(so))
+	return changed, errors.Wrap(err, errWriteStore)
+}
+
+// UnpublishConnection deletes connection details secret to the configured
+// connection Store.
+func (m *DetailsManager) UnpublishConnection(ctx context.Context, so resource.ConnectionSecretOwner, conn managed.ConnectionDetails) error {
+	// This resource didn't expose a connection secret.
+	p := so.GetPublishConnectionDetailsTo()
+	if p == nil {
+		return nil
+	}
+
+	ss, err := m.connectStore(ctx, p)
+	if err != nil {
+		return errors.Wrap(err, errConnectStore)
+	}
+
+	return errors.Wrap(ss.DeleteKeyValues(ctx, store.NewSecret(so, store.KeyValues(conn)), connection.SecretToDeleteMustBeOwnedBy(so)), errDeleteFromStore)
+}
+
+// FetchConnection fetches connection details of a given ConnectionSecretOwner.
+func (m *DetailsManager) FetchConnection(ctx context.Context, so resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+	// This resource does not want to expose a connection secret.
+	p := so.GetPublishConnectionDetailsTo()
+	if p == nil {
+		return nil, nil
+	}
+
+	ss, err := m.connectStore(ctx, p)
+	if err != nil {
+		return nil, errors.Wrap(err, errConnectStore)
+	}
+
+	s := &store.Secret{}
+	if err := ss.ReadKeyValues(ctx, store.ScopedName{Name: p.Name, Scope: so.GetNamespace()}, s); err != nil {
+		return nil, errors.Wrap(err, errReadStore)
+	}
+	return managed.ConnectionDetails(s.Data), nil
+}
+
+// PropagateConnection propagates connection details from one resource to
+// another. The details are read using the source's StoreConfig and written
+// using the destination's, so each store's key transforms are respected. Keys
+// are renamed before they're transformed by the destination's store.
+func (m *DetailsManager) PropagateConnection(ctx context.Context, to resource.LocalConnectionSecretOwner, from resource.ConnectionSecretOwner) (propagated bool, err error) {
+	// Either from does not expose a connection secret, or to does not want one.
+	if from.GetPublishConnectionDetailsTo() == nil || to.GetPublishConnectionDetailsTo() == nil {
+		return false, nil
+	}
+
+	ssFrom, err := m.connectStore(ctx, from.GetPublishConnectionDetailsTo())
+	if err != nil {
+		return false, errors.Wrap(err, errConnectStore)
+	}
+
+	sFrom := &store.Secret{}
+	if err = ssFrom.ReadKeyValues(ctx, store.ScopedName{
+		Name:  from.GetPublishConnectionDetailsTo().Name,
+		Scope: from.GetNamespace(),
+	}, sFrom); err != nil {
+		return false, errors.Wrap(err, errReadStore)
+	}
+
+	// Make sure 'from' is the controller of the connection secret it references
+	// before we propagate it. This ensures a resource cannot use Crossplane to
+	// circumvent RBAC by propagating a secret it does not own.
+	if sFrom.GetOwner() != string(from.GetUID()) {
+		return false, errors.New(errSecretConflict)
+	}
+
+	ssTo, err := m.connectStore(ctx, to.GetPublishConnectionDetailsTo())
+	if err != nil {
+		return false, errors.Wrap(err, errConnectStore)
+	}
+
+	changed, err := ssTo.WriteKeyValues(ctx, store.NewSecret(to, RenameKeys(sFrom.Data, m.renames)), connection.SecretToWriteMustBeOwnedBy(to))
+	return changed, errors.Wrap(err, errWriteStore)
+}
+
+func (m *DetailsManager) connectStore(ctx context.Context, p *xpv1.PublishConnectionDetailsTo) (connection.Store, error) {
+	sc := &v1alpha1.StoreConfig{}
+	if err := m.client.Get(ctx, types.NamespacedName{Name: p.SecretStoreConfigRef.Name}, sc); err != nil {
+		return nil, errors.Wrap(err, errGetStoreConfig)
+	}
+
+	ss, err := m.storeBuilder(ctx, m.client, m.tcfg, sc.GetStoreConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sc.GetKeyTransforms()) == 0 {
+		return ss, nil
+	}
+	return NewKeyTransformingStore(ss, sc.GetKeyTransforms()), nil
+}
+
+// RenameKeys returns a copy of the supplied connection secret data with its
+// keys renamed. Keys that aren't renamed are unchanged. A renamed key takes
+// precedence over a key of the same name that isn't renamed. If two keys are
+// renamed to the same key the one that sorts last wins, though XRD validation
+// should prevent this.
+func RenameKeys(data map[string][]byte, renames map[string]string) map[string][]byte {
+	if len(renames) == 0 {
+		return data
+	}
+
+	out := make(map[string][]byte, len(data))
+	renamed := make([]string, 0, len(renames))
+	for k, v := range data {
+		if _, ok := renames[k]; ok {
+			renamed = append(renamed, k)
+			continue
+		}
+		out[k] = v
+	}
+	sort.Strings(renamed)
+	for _, k := range renamed {
+		out[renames[k]] = data[k]
+	}
+	return out
+}
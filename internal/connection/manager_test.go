@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	resourcefake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+)
+
+func TestPublishConnection(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	owner := func() *resourcefake.MockConnectionSecretOwner {
+		return &resourcefake.MockConnectionSecretOwner{
+			To: &xpv1.PublishConnectionDetailsTo{
+				Name:                 "cool-secret",
+				SecretStoreConfigRef: &xpv1.Reference{Name: "vault"},
+			},
+		}
+	}
+
+	type args struct {
+		client client.Client
+		so     *resourcefake.MockConnectionSecretOwner
+		conn   managed.ConnectionDetails
+	}
+	type want struct {
+		written store.KeyValues
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotPublishing": {
+			reason: "We should not write anything if the resource doesn't want to publish connection details.",
+			args: args{
+				so:   &resourcefake.MockConnectionSecretOwner{},
+				conn: managed.ConnectionDetails{"key": []byte("val")},
+			},
+		},
+		"GetStoreConfigError": {
+			reason: "We should return any error encountered getting the StoreConfig.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				so:     owner(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errGetStoreConfig), errConnectStore),
+			},
+		},
+		"WithoutKeyTransforms": {
+			reason: "We should write keys as they are if the StoreConfig has no key transforms.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				so:     owner(),
+				conn:   managed.ConnectionDetails{"tls.crt": []byte("val")},
+			},
+			want: want{
+				written: store.KeyValues{"tls.crt": []byte("val")},
+			},
+		},
+		"WithKeyTransforms": {
+			reason: "We should apply the StoreConfig's key transforms when writing.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					sc := obj.(*v1alpha1.StoreConfig)
+					sc.Spec.KeyTransforms = []v1alpha1.KeyTransform{
+						{Type: v1alpha1.KeyTransformTypeReplace, Replace: &v1alpha1.ReplaceKeyTransform{Search: ".", Replace: "_"}},
+						{Type: v1alpha1.KeyTransformTypePrefix, Prefix: ptr.To("app_")},
+					}
+					return nil
+				})},
+				so:   owner(),
+				conn: managed.ConnectionDetails{"tls.crt": []byte("val")},
+			},
+			want: want{
+				written: store.KeyValues{"app_tls_crt": []byte("val")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written store.KeyValues
+			sb := connection.StoreBuilderFn(func(_ context.Context, _ client.Client, _ *tls.Config, _ xpv1.SecretStoreConfig) (connection.Store, error) {
+				return &fake.SecretStore{
+					WriteKeyValuesFn: func(_ context.Context, s *store.Secret, _ ...store.WriteOption) (bool, error) {
+						written = s.Data
+						return true, nil
+					},
+				}, nil
+			})
+
+			m := NewDetailsManager(tc.args.client, WithStoreBuilder(sb))
+			_, err := m.PublishConnection(context.Background(), tc.args.so, tc.args.conn)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nm.PublishConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.written, written); diff != "" {
+				t.Errorf("%s\nm.PublishConnection(...): -want written, +got written:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPropagateConnection(t *testing.T) {
+	to := &xpv1.PublishConnectionDetailsTo{
+		Name:                 "cool-secret",
+		SecretStoreConfigRef: &xpv1.Reference{Name: "vault"},
+	}
+
+	type args struct {
+		renames map[string]string
+		read    store.KeyValues
+	}
+	type want struct {
+		written store.KeyValues
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"WithoutRenames": {
+			reason: "We should propagate keys as they are if no keys are renamed.",
+			args: args{
+				read: store.KeyValues{"endpoint": []byte("val")},
+			},
+			want: want{
+				written: store.KeyValues{"endpoint": []byte("val")},
+			},
+		},
+		"WithRenames": {
+			reason: "We should propagate renamed keys under their new names, taking precedence over keys of the same name.",
+			args: args{
+				renames: map[string]string{"endpoint": "DB_HOST"},
+				read:    store.KeyValues{"endpoint": []byte("host"), "DB_HOST": []byte("stale"), "cool": []byte("val")},
+			},
+			want: want{
+				written: store.KeyValues{"DB_HOST": []byte("host"), "cool": []byte("val")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written store.KeyValues
+			sb := connection.StoreBuilderFn(func(_ context.Context, _ client.Client, _ *tls.Config, _ xpv1.SecretStoreConfig) (connection.Store, error) {
+				return &fake.SecretStore{
+					ReadKeyValuesFn: func(_ context.Context, _ store.ScopedName, s *store.Secret) error {
+						s.Metadata = &xpv1.ConnectionSecretMetadata{Labels: map[string]string{xpv1.LabelKeyOwnerUID: "from-uid"}}
+						s.Data = tc.args.read
+						return nil
+					},
+					WriteKeyValuesFn: func(_ context.Context, s *store.Secret, _ ...store.WriteOption) (bool, error) {
+						written = s.Data
+						return true, nil
+					},
+				}, nil
+			})
+
+			m := NewDetailsManager(&test.MockClient{MockGet: test.NewMockGetFn(nil)}, WithStoreBuilder(sb), WithPropagatedKeyRenames(tc.args.renames))
+			_, err := m.PropagateConnection(context.Background(),
+				&resourcefake.MockLocalConnectionSecretOwner{ObjectMeta: metav1.ObjectMeta{UID: "to-uid"}, To: to},
+				&resourcefake.MockConnectionSecretOwner{ObjectMeta: metav1.ObjectMeta{UID: "from-uid"}, To: to},
+			)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nm.PropagateConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.written, written); diff != "" {
+				t.Errorf("%s\nm.PropagateConnection(...): -want written, +got written:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+)
+
+// Error strings.
+const (
+	errFmtKeyTransform     = "cannot apply key transform at index %d"
+	errFmtKeyTransformType = "unknown key transform type %q"
+	errFmtKeyCase          = "unknown key case %q"
+	errFmtKeyConflict      = "more than one connection detail key transforms to %q"
+
+	errPrefixNotSet  = "prefix is required by key transform type Prefix"
+	errSuffixNotSet  = "suffix is required by key transform type Suffix"
+	errCaseNotSet    = "case is required by key transform type Case"
+	errReplaceNotSet = "replace is required by key transform type Replace"
+	errSearchNotSet  = "replace.search is required by key transform type Replace"
+)
+
+// TransformKey applies the supplied transforms, in order, to the supplied key.
+func TransformKey(key string, kt []v1alpha1.KeyTransform) (string, error) {
+	for i, t := range kt {
+		out, err := transformKey(key, t)
+		if err != nil {
+			return "", errors.Wrapf(err, errFmtKeyTransform, i)
+		}
+		key = out
+	}
+	return key, nil
+}
+
+// ReverseKey reverses the supplied transforms, in reverse order, for the
+// supplied key. Case transforms can't be reversed, so the key is returned in
+// the transformed case. Prefix and suffix transforms only strip the prefix or
+// suffix from keys that have it.
+func ReverseKey(key string, kt []v1alpha1.KeyTransform) (string, error) {
+	for i := len(kt) - 1; i >= 0; i-- {
+		out, err := reverseKey(key, kt[i])
+		if err != nil {
+			return "", errors.Wrapf(err, errFmtKeyTransform, i)
+		}
+		key = out
+	}
+	return key, nil
+}
+
+func transformKey(key string, t v1alpha1.KeyTransform) (string, error) {
+	if err := validate(t); err != nil {
+		return "", err
+	}
+	switch t.Type {
+	case v1alpha1.KeyTransformTypePrefix:
+		return *t.Prefix + key, nil
+	case v1alpha1.KeyTransformTypeSuffix:
+		return key + *t.Suffix, nil
+	case v1alpha1.KeyTransformTypeCase:
+		if *t.Case == v1alpha1.KeyCaseUpper {
+			return strings.ToUpper(key), nil
+		}
+		return strings.ToLower(key), nil
+	case v1alpha1.KeyTransformTypeReplace:
+		return strings.ReplaceAll(key, t.Replace.Search, t.Replace.Replace), nil
+	}
+	return "", errors.Errorf(errFmtKeyTransformType, t.Type)
+}
+
+func reverseKey(key string, t v1alpha1.KeyTransform) (string, error) {
+	if err := validate(t); err != nil {
+		return "", err
+	}
+	switch t.Type {
+	case v1alpha1.KeyTransformTypePrefix:
+		return strings.TrimPrefix(key, *t.Prefix), nil
+	case v1alpha1.KeyTransformTypeSuffix:
+		return strings.TrimSuffix(key, *t.Suffix), nil
+	case v1alpha1.KeyTransformTypeCase:
+		return key, nil
+	case v1alpha1.KeyTransformTypeReplace:
+		// We can't tell where an empty replacement was made.
+		if t.Replace.Replace == "" {
+			return key, nil
+		}
+		return strings.ReplaceAll(key, t.Replace.Replace, t.Replace.Search), nil
+	}
+	return "", errors.Errorf(errFmtKeyTransformType, t.Type)
+}
+
+func validate(t v1alpha1.KeyTransform) error {
+	switch t.Type {
+	case v1alpha1.KeyTransformTypePrefix:
+		if t.Prefix == nil {
+			return errors.New(errPrefixNotSet)
+		}
+	case v1alpha1.KeyTransformTypeSuffix:
+		if t.Suffix == nil {
+			return errors.New(errSuffixNotSet)
+		}
+	case v1alpha1.KeyTransformTypeCase:
+		if t.Case == nil {
+			return errors.New(errCaseNotSet)
+		}
+		if *t.Case != v1alpha1.KeyCaseLower && *t.Case != v1alpha1.KeyCaseUpper {
+			return errors.Errorf(errFmtKeyCase, *t.Case)
+		}
+	case v1alpha1.KeyTransformTypeReplace:
+		if t.Replace == nil {
+			return errors.New(errReplaceNotSet)
+		}
+		if t.Replace.Search == "" {
+			return errors.New(errSearchNotSet)
+		}
+	default:
+		return errors.Errorf(errFmtKeyTransformType, t.Type)
+	}
+	return nil
+}
+
+// A KeyTransformingStore is a Store that transforms the keys of the secrets
+// it writes and deletes, and reverses the transforms for the secrets it reads.
+type KeyTransformingStore struct {
+	wrapped    connection.Store
+	transforms []v1alpha1.KeyTransform
+}
+
+// NewKeyTransformingStore returns a Store that applies the supplied key
+// transforms to the secrets read from and written to the supplied Store.
+func NewKeyTransformingStore(s connection.Store, kt []v1alpha1.KeyTransform) *KeyTransformingStore {
+	return &KeyTransformingStore{wrapped: s, transforms: kt}
+}
+
+// ReadKeyValues reads the named secret, and reverses the transforms of its
+// keys.
+func (s *KeyTransformingStore) ReadKeyValues(ctx context.Context, n store.ScopedName, sec *store.Secret) error {
+	if err := s.wrapped.ReadKeyValues(ctx, n, sec); err != nil {
+		return err
+	}
+	data, err := transformKeys(sec.Data, s.transforms, ReverseKey)
+	if err != nil {
+		return err
+	}
+	sec.Data = data
+	return nil
+}
+
+// WriteKeyValues transforms the keys of the supplied secret, and writes it.
+func (s *KeyTransformingStore) WriteKeyValues(ctx context.Context, sec *store.Secret, wo ...store.WriteOption) (bool, error) {
+	data, err := transformKeys(sec.Data, s.transforms, TransformKey)
+	if err != nil {
+		return false, err
+	}
+	return s.wrapped.WriteKeyValues(ctx, &store.Secret{ScopedName: sec.ScopedName, Metadata: sec.Metadata, Data: data}, wo...)
+}
+
+// DeleteKeyValues transforms the keys of the supplied secret, and deletes
+// them.
+func (s *KeyTransformingStore) DeleteKeyValues(ctx context.Context, sec *store.Secret, do ...store.DeleteOption) error {
+	data, err := transformKeys(sec.Data, s.transforms, TransformKey)
+	if err != nil {
+		return err
+	}
+	return s.wrapped.DeleteKeyValues(ctx, &store.Secret{ScopedName: sec.ScopedName, Metadata: sec.Metadata, Data: data}, do...)
+}
+
+func transformKeys(in store.KeyValues, kt []v1alpha1.KeyTransform, fn func(string, []v1alpha1.KeyTransform) (string, error)) (store.KeyValues, error) {
+	if in == nil || len(kt) == 0 {
+		return in, nil
+	}
+	out := make(store.KeyValues, len(in))
+	for k, v := range in {
+		tk, err := fn(k, kt)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := out[tk]; ok {
+			return nil, errors.Errorf(errFmtKeyConflict, tk)
+		}
+		out[tk] = v
+	}
+	return out, nil
+}
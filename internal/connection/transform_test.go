@@ -0,0 +1,240 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/connection/store"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+)
+
+func TestTransformKey(t *testing.T) {
+	upper := v1alpha1.KeyCaseUpper
+	lower := v1alpha1.KeyCaseLower
+
+	type args struct {
+		key string
+		kt  []v1alpha1.KeyTransform
+	}
+	type want struct {
+		key     string
+		reverse string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoTransforms": {
+			reason: "A key should be unchanged if there are no transforms.",
+			args: args{
+				key: "endpoint",
+			},
+			want: want{
+				key:     "endpoint",
+				reverse: "endpoint",
+			},
+		},
+		"PrefixAndSuffix": {
+			reason: "A prefix and suffix should be added when writing, and stripped when reading.",
+			args: args{
+				key: "endpoint",
+				kt: []v1alpha1.KeyTransform{
+					{Type: v1alpha1.KeyTransformTypePrefix, Prefix: ptr.To("db-")},
+					{Type: v1alpha1.KeyTransformTypeSuffix, Suffix: ptr.To("-v1")},
+				},
+			},
+			want: want{
+				key:     "db-endpoint-v1",
+				reverse: "endpoint",
+			},
+		},
+		"Replace": {
+			reason: "Occurrences of the search string should be replaced when writing, and restored when reading.",
+			args: args{
+				key: "tls.crt",
+				kt: []v1alpha1.KeyTransform{
+					{Type: v1alpha1.KeyTransformTypeReplace, Replace: &v1alpha1.ReplaceKeyTransform{Search: ".", Replace: "_"}},
+				},
+			},
+			want: want{
+				key:     "tls_crt",
+				reverse: "tls.crt",
+			},
+		},
+		"ReplaceWithEmptyString": {
+			reason: "An empty replacement can't be reversed, so the key should be read as it was written.",
+			args: args{
+				key: "tls.crt",
+				kt: []v1alpha1.KeyTransform{
+					{Type: v1alpha1.KeyTransformTypeReplace, Replace: &v1alpha1.ReplaceKeyTransform{Search: "."}},
+				},
+			},
+			want: want{
+				key:     "tlscrt",
+				reverse: "tlscrt",
+			},
+		},
+		"Case": {
+			reason: "A key should be converted to the configured case when writing. Case can't be reversed, so the key should be read in that case.",
+			args: args{
+				key: "caData",
+				kt: []v1alpha1.KeyTransform{
+					{Type: v1alpha1.KeyTransformTypeCase, Case: &upper},
+				},
+			},
+			want: want{
+				key:     "CADATA",
+				reverse: "CADATA",
+			},
+		},
+		"TransformsAreReversedInReverseOrder": {
+			reason: "Transforms should be reversed in the reverse of the order they were applied.",
+			args: args{
+				key: "Tls.Crt",
+				kt: []v1alpha1.KeyTransform{
+					{Type: v1alpha1.KeyTransformTypeCase, Case: &lower},
+					{Type: v1alpha1.KeyTransformTypeReplace, Replace: &v1alpha1.ReplaceKeyTransform{Search: ".", Replace: "-"}},
+					{Type: v1alpha1.KeyTransformTypePrefix, Prefix: ptr.To("app.")},
+				},
+			},
+			want: want{
+				key:     "app.tls-crt",
+				reverse: "tls.crt",
+			},
+		},
+		"MissingConfig": {
+			reason: "A transform missing its configuration should return an error.",
+			args: args{
+				key: "endpoint",
+				kt: []v1alpha1.KeyTransform{
+					{Type: v1alpha1.KeyTransformTypeSuffix},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New(errSuffixNotSet), errFmtKeyTransform, 0),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := TransformKey(tc.args.key, tc.args.kt)
+			if diff := cmp.Diff(tc.want.key, got); diff != "" {
+				t.Errorf("%s\nTransformKey(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nTransformKey(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			rev, err := ReverseKey(got, tc.args.kt)
+			if diff := cmp.Diff(tc.want.reverse, rev); diff != "" {
+				t.Errorf("%s\nReverseKey(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\nReverseKey(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKeyTransformingStore(t *testing.T) {
+	kt := []v1alpha1.KeyTransform{
+		{Type: v1alpha1.KeyTransformTypePrefix, Prefix: ptr.To("xp_")},
+	}
+
+	type want struct {
+		written store.KeyValues
+		read    store.KeyValues
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		data   store.KeyValues
+		stored store.KeyValues
+		want   want
+	}{
+		"RoundTrip": {
+			reason: "Keys should be transformed when written, and reversed when read.",
+			data:   store.KeyValues{"username": []byte("admin")},
+			stored: store.KeyValues{"xp_username": []byte("admin")},
+			want: want{
+				written: store.KeyValues{"xp_username": []byte("admin")},
+				read:    store.KeyValues{"username": []byte("admin")},
+			},
+		},
+		"Conflict": {
+			reason: "We should return an error if two stored keys read as the same key.",
+			stored: store.KeyValues{"xp_username": []byte("a"), "username": []byte("b")},
+			want: want{
+				err: errors.Errorf(errFmtKeyConflict, "username"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var written store.KeyValues
+			s := NewKeyTransformingStore(&fake.SecretStore{
+				WriteKeyValuesFn: func(_ context.Context, s *store.Secret, _ ...store.WriteOption) (bool, error) {
+					written = s.Data
+					return true, nil
+				},
+				ReadKeyValuesFn: func(_ context.Context, _ store.ScopedName, s *store.Secret) error {
+					s.Data = tc.stored
+					return nil
+				},
+			}, kt)
+
+			if tc.data != nil {
+				if _, err := s.WriteKeyValues(context.Background(), &store.Secret{Data: tc.data}); err != nil {
+					t.Fatalf("%s\ns.WriteKeyValues(...): %v", tc.reason, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want.written, written, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("%s\ns.WriteKeyValues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			read := &store.Secret{}
+			err := s.ReadKeyValues(context.Background(), store.ScopedName{}, read)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("%s\ns.ReadKeyValues(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.read, read.Data, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("%s\ns.ReadKeyValues(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -31,11 +31,15 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/connection"
 )
 
 // Error strings.
 const (
 	errGetSecret            = "cannot get composite resource's connection secret"
+	errGetRevision          = "cannot get composite resource's composition revision"
 	errSecretConflict       = "cannot establish control of existing connection secret"
 	errCreateOrUpdateSecret = "cannot create or update connection secret"
 )
@@ -105,14 +109,32 @@ func (s soClaim) GetWriteConnectionSecretToReference() *xpv1.SecretReference {
 // An APIConnectionPropagator propagates connection details by reading
 // them from and writing them to a Kubernetes API server.
 type APIConnectionPropagator struct {
-	client resource.ClientApplicator
+	client  resource.ClientApplicator
+	renames map[string]string
+}
+
+// An APIConnectionPropagatorOption configures an APIConnectionPropagator.
+type APIConnectionPropagatorOption func(*APIConnectionPropagator)
+
+// WithConnectionSecretKeyRenames specifies connection secret keys that should
+// be renamed when they're propagated. Each key of the supplied map is a key of
+// the composite resource's connection secret, and each value is the key it
+// should be written to in the claim's connection secret.
+func WithConnectionSecretKeyRenames(renames map[string]string) APIConnectionPropagatorOption {
+	return func(a *APIConnectionPropagator) {
+		a.renames = renames
+	}
 }
 
 // NewAPIConnectionPropagator returns a new APIConnectionPropagator.
-func NewAPIConnectionPropagator(c client.Client) *APIConnectionPropagator {
-	return &APIConnectionPropagator{
+func NewAPIConnectionPropagator(c client.Client, opts ...APIConnectionPropagatorOption) *APIConnectionPropagator {
+	a := &APIConnectionPropagator{
 		client: resource.ClientApplicator{Client: c, Applicator: resource.NewAPIUpdatingApplicator(c)},
 	}
+	for _, fn := range opts {
+		fn(a)
+	}
+	return a
 }
 
 // PropagateConnection details from the supplied resource.
@@ -122,6 +144,12 @@ func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to re
 		return false, nil
 	}
 
+	// The composite resource's composition disables connection secrets, so
+	// any connection secret it references is stale. Don't propagate it.
+	if disabled, err := a.connectionSecretsDisabled(ctx, from); err != nil || disabled {
+		return false, err
+	}
+
 	n := types.NamespacedName{
 		Namespace: from.GetWriteConnectionSecretToReference().Namespace,
 		Name:      from.GetWriteConnectionSecretToReference().Name,
@@ -139,7 +167,7 @@ func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to re
 	}
 
 	ts := resource.LocalConnectionSecretFor(to, to.GetObjectKind().GroupVersionKind())
-	ts.Data = fs.Data
+	ts.Data = connection.RenameKeys(fs.Data, a.renames)
 
 	err := a.client.Apply(ctx, ts,
 		resource.ConnectionSecretMustBeControllableBy(to.GetUID()),
@@ -161,3 +189,17 @@ func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to re
 
 	return true, nil
 }
+
+// connectionSecretsDisabled returns true if the composition revision the
+// supplied composite resource uses disables connection secrets.
+func (a *APIConnectionPropagator) connectionSecretsDisabled(ctx context.Context, from resource.ConnectionSecretOwner) (bool, error) {
+	rr, ok := from.(resource.CompositionRevisionReferencer)
+	if !ok || rr.GetCompositionRevisionReference() == nil {
+		return false, nil
+	}
+	rev := &v1.CompositionRevision{}
+	if err := a.client.Get(ctx, types.NamespacedName{Name: rr.GetCompositionRevisionReference().Name}, rev); err != nil {
+		return false, errors.Wrap(err, errGetRevision)
+	}
+	return rev.Spec.DisableConnectionSecrets, nil
+}
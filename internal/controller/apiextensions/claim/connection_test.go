@@ -32,6 +32,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 )
 
 var _ ConnectionPropagator = &APIConnectionPropagator{}
@@ -52,6 +54,15 @@ func TestPropagateConnection(t *testing.T) {
 		},
 	}
 
+	cprev := &fake.Composite{
+		CompositionRevisionReferencer: fake.CompositionRevisionReferencer{
+			Ref: &corev1.LocalObjectReference{Name: "cool-rev"},
+		},
+		ConnectionSecretWriterTo: fake.ConnectionSecretWriterTo{
+			Ref: &xpv1.SecretReference{Namespace: mgcsns, Name: mgcsname},
+		},
+	}
+
 	cm := &fake.CompositeClaim{
 		ObjectMeta: metav1.ObjectMeta{Namespace: cmcsns},
 		LocalConnectionSecretWriterTo: fake.LocalConnectionSecretWriterTo{
@@ -60,7 +71,8 @@ func TestPropagateConnection(t *testing.T) {
 	}
 
 	type fields struct {
-		client resource.ClientApplicator
+		client  resource.ClientApplicator
+		renames map[string]string
 	}
 
 	type args struct {
@@ -99,6 +111,48 @@ func TestPropagateConnection(t *testing.T) {
 				err: nil,
 			},
 		},
+		"GetCompositionRevisionError": {
+			reason: "Errors getting the composite resource's composition revision should be returned",
+			fields: fields{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				},
+			},
+			args: args{
+				to:   cm,
+				from: cprev,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetRevision),
+			},
+		},
+		"ConnectionSecretsDisabled": {
+			reason: "The composite resource's secret should not be propagated if its composition revision disables connection secrets",
+			fields: fields{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						rev, ok := o.(*v1.CompositionRevision)
+						if !ok {
+							t.Errorf("unexpected Get of %T", o)
+							return nil
+						}
+						rev.Spec.DisableConnectionSecrets = true
+						return nil
+					})},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						t.Errorf("unexpected Apply")
+						return nil
+					}),
+				},
+			},
+			args: args{
+				to:   cm,
+				from: cprev,
+			},
+			want: want{
+				propagated: false,
+			},
+		},
 		"GetManagedSecretError": {
 			reason: "Errors getting the composite resource's connection secret should be returned",
 			fields: fields{
@@ -219,11 +273,44 @@ func TestPropagateConnection(t *testing.T) {
 				propagated: true,
 			},
 		},
+		"SuccessfulPublishRenamedKeys": {
+			reason: "Renamed keys should be propagated under their new names, taking precedence over keys of the same name, while other keys are unchanged",
+			fields: fields{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							s := resource.ConnectionSecretFor(cp, schema.GroupVersionKind{})
+							s.Data = map[string][]byte{"endpoint": {1}, "DB_HOST": {2}, "cool": {3}}
+
+							*o.(*corev1.Secret) = *s
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+						want := resource.LocalConnectionSecretFor(cm, schema.GroupVersionKind{})
+						want.Data = map[string][]byte{"DB_HOST": {1}, "cool": {3}}
+						if diff := cmp.Diff(want, o); diff != "" {
+							t.Errorf("-want, +got:\n %s", diff)
+						}
+
+						return nil
+					}),
+				},
+				renames: map[string]string{"endpoint": "DB_HOST", "port": "DB_PORT"},
+			},
+			args: args{
+				to:   cm,
+				from: cp,
+			},
+			want: want{
+				propagated: true,
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			api := &APIConnectionPropagator{client: tc.fields.client}
+			api := &APIConnectionPropagator{client: tc.fields.client, renames: tc.fields.renames}
 			got, err := api.PropagateConnection(tc.args.ctx, tc.args.to, tc.args.from)
 			if diff := cmp.Diff(tc.want.propagated, got); diff != "" {
 				t.Errorf("\n%s\napi.PropagateConnection(...): -want, +got:\n%s", tc.reason, diff)
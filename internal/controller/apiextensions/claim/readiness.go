@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// fieldResourceReadiness is the status field in which the composite resource
+// reconciler records the readiness of an XR's composed resources. The same
+// field is used to expose readiness to the XR's claim.
+const fieldResourceReadiness = "status.resourceReadiness"
+
+// A ResourceReadiness summarizes the readiness of an XR's composed resources.
+type ResourceReadiness struct {
+	// Ready is the number of composed resources that are ready.
+	Ready int64
+
+	// Total is the number of composed resources.
+	Total int64
+
+	// Unready are the names of the composed resources that aren't ready, as
+	// they appear in the XR's Composition.
+	Unready []string
+}
+
+// getResourceReadiness returns the readiness of the supplied XR's composed
+// resources. It returns false if the XR doesn't record its readiness.
+func getResourceReadiness(xr *composite.Unstructured) (ResourceReadiness, bool) {
+	p := fieldpath.Pave(xr.Object)
+	rr := ResourceReadiness{}
+	total, err := p.GetInteger(fieldResourceReadiness + ".total")
+	if err != nil {
+		return rr, false
+	}
+	rr.Total = total
+	rr.Ready, _ = p.GetInteger(fieldResourceReadiness + ".ready")
+	rr.Unready, _ = p.GetStringArray(fieldResourceReadiness + ".unready")
+	return rr, true
+}
+
+// propagateReadiness exposes the readiness of the supplied XR's composed
+// resources to the supplied claim, with the supplied level of detail. It
+// returns a message describing the readiness, or an empty string if there's
+// nothing to describe.
+func propagateReadiness(cm *claim.Unstructured, xr *composite.Unstructured, d v1.ClaimReadinessDetail) (string, error) {
+	p := fieldpath.Pave(cm.Object)
+
+	rr, ok := getResourceReadiness(xr)
+	if !ok || d == v1.ClaimReadinessDetailNone {
+		return "", p.DeleteField(fieldResourceReadiness)
+	}
+
+	out := map[string]any{
+		"ready": rr.Ready,
+		"total": rr.Total,
+	}
+	msg := fmt.Sprintf("%d of %d composed resources are ready", rr.Ready, rr.Total)
+
+	if d == v1.ClaimReadinessDetailResources && len(rr.Unready) > 0 {
+		unready := make([]any, len(rr.Unready))
+		for i, n := range rr.Unready {
+			unready[i] = n
+		}
+		out["unready"] = unready
+		msg += fmt.Sprintf(". Unready resources: %s", resource.StableNAndSomeMore(resource.DefaultFirstN, rr.Unready))
+	}
+
+	return msg, p.SetValue(fieldResourceReadiness, out)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestPropagateReadiness(t *testing.T) {
+	xr := func(readiness map[string]any) *composite.Unstructured {
+		obj := map[string]any{}
+		if readiness != nil {
+			obj["status"] = map[string]any{"resourceReadiness": readiness}
+		}
+		return &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: obj}}
+	}
+	cm := func(readiness map[string]any) *claim.Unstructured {
+		obj := map[string]any{"status": map[string]any{}}
+		if readiness != nil {
+			obj["status"] = map[string]any{"resourceReadiness": readiness}
+		}
+		return &claim.Unstructured{Unstructured: unstructured.Unstructured{Object: obj}}
+	}
+	unready := map[string]any{
+		"ready":   int64(2),
+		"total":   int64(4),
+		"unready": []any{"bucket", "database"},
+	}
+
+	type args struct {
+		cm     *claim.Unstructured
+		xr     *composite.Unstructured
+		detail v1.ClaimReadinessDetail
+	}
+	type want struct {
+		cm  *claim.Unstructured
+		msg string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"XRDoesNotRecordReadiness": {
+			reason: "We should remove any readiness from the claim if the XR doesn't record its readiness.",
+			args: args{
+				cm:     cm(map[string]any{"ready": int64(1), "total": int64(1)}),
+				xr:     xr(nil),
+				detail: v1.ClaimReadinessDetailResources,
+			},
+			want: want{
+				cm: cm(nil),
+			},
+		},
+		"None": {
+			reason: "We shouldn't expose any readiness detail to the claim if the detail is None.",
+			args: args{
+				cm:     cm(nil),
+				xr:     xr(unready),
+				detail: v1.ClaimReadinessDetailNone,
+			},
+			want: want{
+				cm: cm(nil),
+			},
+		},
+		"Counts": {
+			reason: "We should only expose how many composed resources are ready if the detail is Counts.",
+			args: args{
+				cm:     cm(nil),
+				xr:     xr(unready),
+				detail: v1.ClaimReadinessDetailCounts,
+			},
+			want: want{
+				cm:  cm(map[string]any{"ready": int64(2), "total": int64(4)}),
+				msg: "2 of 4 composed resources are ready",
+			},
+		},
+		"Resources": {
+			reason: "We should also expose which composed resources aren't ready if the detail is Resources.",
+			args: args{
+				cm:     cm(nil),
+				xr:     xr(unready),
+				detail: v1.ClaimReadinessDetailResources,
+			},
+			want: want{
+				cm:  cm(unready),
+				msg: "2 of 4 composed resources are ready. Unready resources: bucket, database",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			msg, err := propagateReadiness(tc.args.cm, tc.args.xr, tc.args.detail)
+			if err != nil {
+				t.Fatalf("\n%s\npropagateReadiness(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.msg, msg); diff != "" {
+				t.Errorf("\n%s\npropagateReadiness(...): -want message, +got message:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cm, tc.args.cm); diff != "" {
+				t.Errorf("\n%s\npropagateReadiness(...): -want claim, +got claim:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
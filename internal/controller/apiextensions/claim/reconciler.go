@@ -19,6 +19,7 @@ package claim
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -40,6 +41,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/names"
 )
 
@@ -60,6 +62,7 @@ const (
 	errSync                 = "cannot bind and sync claim with composite resource"
 	errPropagateCDs         = "cannot propagate connection details from composite resource"
 	errUpdateClaimStatus    = "cannot update claim status"
+	errPropagateReadiness   = "cannot propagate readiness from composite resource"
 
 	errFmtUnbound = "refusing to operate on composite resource %q that is not bound to this claim: bound to claim %q"
 )
@@ -188,9 +191,10 @@ type Reconciler struct {
 	composite crComposite
 	claim     crClaim
 
-	log          logging.Logger
-	record       event.Recorder
-	pollInterval time.Duration
+	log             logging.Logger
+	record          event.Recorder
+	pollInterval    time.Duration
+	readinessDetail v1.ClaimReadinessDetail
 }
 
 type crComposite struct {
@@ -275,6 +279,14 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithReadinessDetail specifies how much detail about a composite resource's
+// readiness the Reconciler should expose to its claim.
+func WithReadinessDetail(d v1.ClaimReadinessDetail) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.readinessDetail = d
+	}
+}
+
 // WithPollInterval specifies how long the Reconciler should wait before queueing
 // a new reconciliation after a successful reconcile. The Reconciler requeues
 // after a specified duration when it is not actively waiting for an external
@@ -293,14 +305,15 @@ func WithPollInterval(after time.Duration) ReconcilerOption {
 // configure their composite resources.
 func NewReconciler(c client.Client, of resource.CompositeClaimKind, with resource.CompositeKind, o ...ReconcilerOption) *Reconciler {
 	r := &Reconciler{
-		client:        c,
-		gvkClaim:      schema.GroupVersionKind(of),
-		gvkXR:         schema.GroupVersionKind(with),
-		managedFields: &NopManagedFieldsUpgrader{},
-		composite:     defaultCRComposite(c),
-		claim:         defaultCRClaim(c),
-		log:           logging.NewNopLogger(),
-		record:        event.NewNopRecorder(),
+		client:          c,
+		gvkClaim:        schema.GroupVersionKind(of),
+		gvkXR:           schema.GroupVersionKind(with),
+		managedFields:   &NopManagedFieldsUpgrader{},
+		composite:       defaultCRComposite(c),
+		claim:           defaultCRClaim(c),
+		log:             logging.NewNopLogger(),
+		record:          event.NewNopRecorder(),
+		readinessDetail: v1.ClaimReadinessDetailCounts,
 	}
 
 	for _, ro := range o {
@@ -480,12 +493,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		cm.SetConditions(c)
 	}
 
+	// Expose the readiness of the XR's composed resources to the claim, in
+	// as much detail as its XRD allows.
+	readiness, err := propagateReadiness(cm, xr, r.readinessDetail)
+	if err != nil {
+		log.Debug(errPropagateReadiness, "error", err)
+	}
+
 	if !resource.IsConditionTrue(xr.GetCondition(xpv1.TypeReady)) {
 		record.Event(cm, event.Normal(reasonBind, "Composite resource is not yet ready"))
 
 		// We should be watching the composite resource and will have a
 		// request queued if it changes, so no need to requeue.
-		cm.SetConditions(Waiting())
+		w := Waiting()
+		if readiness != "" {
+			w = w.WithMessage(fmt.Sprintf("%s. %s.", w.Message, readiness))
+		}
+		cm.SetConditions(w)
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
@@ -18,12 +18,16 @@ package composite
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,6 +40,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -53,6 +58,13 @@ const (
 	errCompositionNotCompatible        = "referenced composition is not compatible with this composite resource"
 	errGetXRD                          = "cannot get composite resource definition"
 	errFetchCompositionRevision        = "cannot fetch composition revision"
+	errGetClaimNamespace               = "cannot get claim namespace"
+
+	errFmtNoAvailableComposition      = "no compatible Compositions are available to claims in namespace %q"
+	errFmtCompositionNotAvailable     = "Composition %q is not available to claims in namespace %q"
+	errFmtParseClaimNamespaceSelector = "cannot parse claimNamespaceSelector of Composition %q"
+	errListDefaultCompositions        = "cannot list DefaultCompositions"
+	errFmtMultipleDefaultCompositions = "namespace %q has more than one DefaultComposition for composite resource kind %q: %s"
 )
 
 // Event reasons.
@@ -248,18 +260,47 @@ func (r *APILabelSelectorResolver) SelectComposition(ctx context.Context, cp res
 		return errors.Wrap(err, errListCompositions)
 	}
 
-	candidates := make([]string, 0, len(list.Items))
+	compatible := make([]*v1.Composition, 0, len(list.Items))
 	v, k := cp.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
 
-	for _, comp := range list.Items {
+	for i := range list.Items {
+		comp := &list.Items[i]
 		if comp.Spec.CompositeTypeRef.APIVersion == v && comp.Spec.CompositeTypeRef.Kind == k {
 			// This composition is compatible with our composite resource.
+			compatible = append(compatible, comp)
+		}
+	}
+
+	if len(compatible) == 0 {
+		return errors.New(errNoCompatibleComposition)
+	}
+
+	// Only get the claim's namespace if we need its labels.
+	var ns *corev1.Namespace
+	for _, comp := range compatible {
+		if comp.Spec.ClaimNamespaceSelector == nil {
+			continue
+		}
+		var err error
+		if ns, err = getClaimNamespace(ctx, r.client, cp); err != nil {
+			return err
+		}
+		break
+	}
+
+	candidates := make([]string, 0, len(compatible))
+	for _, comp := range compatible {
+		ok, err := availableToClaimNamespace(comp.GetName(), comp.Spec.ClaimNamespaceSelector, ns)
+		if err != nil {
+			return err
+		}
+		if ok {
 			candidates = append(candidates, comp.Name)
 		}
 	}
 
 	if len(candidates) == 0 {
-		return errors.New(errNoCompatibleComposition)
+		return errors.Errorf(errFmtNoAvailableComposition, cp.GetClaimReference().Namespace)
 	}
 
 	random := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec // We don't need this to be cryptographically random.
@@ -300,6 +341,65 @@ func (s *APIDefaultCompositionSelector) SelectComposition(ctx context.Context, c
 	return nil
 }
 
+// NewAPINamespaceDefaultCompositionSelector returns an
+// APINamespaceDefaultCompositionSelector.
+func NewAPINamespaceDefaultCompositionSelector(c client.Client, r event.Recorder) *APINamespaceDefaultCompositionSelector {
+	return &APINamespaceDefaultCompositionSelector{client: c, recorder: r}
+}
+
+// An APINamespaceDefaultCompositionSelector selects the composition referenced
+// by the DefaultComposition in the claim's namespace if neither a reference
+// nor selector is given in the composite resource. It should precede the
+// APIDefaultCompositionSelector in a chain, so that a namespace's default
+// takes precedence over the definition's default.
+type APINamespaceDefaultCompositionSelector struct {
+	client   client.Client
+	recorder event.Recorder
+}
+
+// SelectComposition selects the claim namespace's default composition if
+// neither a reference nor selector is given in composite resource.
+func (s *APINamespaceDefaultCompositionSelector) SelectComposition(ctx context.Context, cp resource.Composite) error {
+	if cp.GetCompositionReference() != nil || cp.GetCompositionSelector() != nil {
+		return nil
+	}
+
+	// Only composite resources bound to a claim have a namespace.
+	ref := cp.GetClaimReference()
+	if ref == nil {
+		return nil
+	}
+
+	l := &v1alpha1.DefaultCompositionList{}
+	if err := s.client.List(ctx, l, client.InNamespace(ref.Namespace)); err != nil {
+		return errors.Wrap(err, errListDefaultCompositions)
+	}
+
+	v, k := cp.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+	matches := make([]string, 0, 1)
+	name := ""
+	for _, dc := range l.Items {
+		if dc.Spec.CompositeTypeRef.APIVersion != v || dc.Spec.CompositeTypeRef.Kind != k {
+			continue
+		}
+		matches = append(matches, dc.GetName())
+		name = dc.Spec.CompositionRef.Name
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil
+	case 1:
+		cp.SetCompositionReference(&corev1.ObjectReference{Name: name})
+		s.recorder.Event(cp, event.Normal(reasonCompositionSelection, fmt.Sprintf("Default composition of namespace %q has been selected", ref.Namespace)))
+		return nil
+	default:
+		// Picking one would make the selected composition depend on the
+		// order the API server lists DefaultCompositions in.
+		return errors.Errorf(errFmtMultipleDefaultCompositions, ref.Namespace, k, strings.Join(matches, ", "))
+	}
+}
+
 // NewEnforcedCompositionSelector returns a EnforcedCompositionSelector.
 func NewEnforcedCompositionSelector(def v1.CompositeResourceDefinition, r event.Recorder) *EnforcedCompositionSelector {
 	return &EnforcedCompositionSelector{def: def, recorder: r}
@@ -330,6 +430,80 @@ func (s *EnforcedCompositionSelector) SelectComposition(_ context.Context, cp re
 	return nil
 }
 
+// NewAPIClaimNamespaceRevisionFetcher returns an
+// APIClaimNamespaceRevisionFetcher that wraps the supplied
+// CompositionRevisionFetcher.
+func NewAPIClaimNamespaceRevisionFetcher(c client.Reader, f CompositionRevisionFetcher) *APIClaimNamespaceRevisionFetcher {
+	return &APIClaimNamespaceRevisionFetcher{client: c, wrapped: f}
+}
+
+// An APIClaimNamespaceRevisionFetcher ensures a composite resource that is
+// bound to a claim only uses a composition revision that is available to
+// claims in the claim's namespace, per the revision's claimNamespaceSelector.
+// It validates the revision the wrapped CompositionRevisionFetcher fetches, so
+// that it validates the composition however it was selected.
+type APIClaimNamespaceRevisionFetcher struct {
+	client  client.Reader
+	wrapped CompositionRevisionFetcher
+}
+
+// Fetch the appropriate CompositionRevision for the supplied composite
+// resource. Returns an error if the revision isn't available to claims in the
+// composite resource's claim's namespace.
+func (f *APIClaimNamespaceRevisionFetcher) Fetch(ctx context.Context, cp resource.Composite) (*v1.CompositionRevision, error) {
+	rev, err := f.wrapped.Fetch(ctx, cp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Avoid getting the claim's namespace if the revision is available to
+	// all claims.
+	if cp.GetClaimReference() == nil || rev.Spec.ClaimNamespaceSelector == nil {
+		return rev, nil
+	}
+
+	ns, err := getClaimNamespace(ctx, f.client, cp)
+	if err != nil {
+		return nil, err
+	}
+
+	name := cp.GetCompositionReference().Name
+	ok, err := availableToClaimNamespace(name, rev.Spec.ClaimNamespaceSelector, ns)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Errorf(errFmtCompositionNotAvailable, name, cp.GetClaimReference().Namespace)
+	}
+	return rev, nil
+}
+
+// getClaimNamespace returns the namespace of the claim the supplied composite
+// resource is bound to, or nil if it isn't bound to a claim.
+func getClaimNamespace(ctx context.Context, c client.Reader, cp resource.Composite) (*corev1.Namespace, error) {
+	ref := cp.GetClaimReference()
+	if ref == nil {
+		return nil, nil
+	}
+	ns := &corev1.Namespace{}
+	return ns, errors.Wrap(c.Get(ctx, types.NamespacedName{Name: ref.Namespace}, ns), errGetClaimNamespace)
+}
+
+// availableToClaimNamespace returns true if the named composition, which has
+// the supplied claimNamespaceSelector, may be used by a composite resource
+// bound to a claim in the supplied namespace. A nil namespace means the
+// composite resource isn't bound to a claim.
+func availableToClaimNamespace(name string, ls *metav1.LabelSelector, ns *corev1.Namespace) (bool, error) {
+	if ns == nil || ls == nil {
+		return true, nil
+	}
+	sel, err := metav1.LabelSelectorAsSelector(ls)
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtParseClaimNamespaceSelector, name)
+	}
+	return sel.Matches(labels.Set(ns.GetLabels())), nil
+}
+
 // NewConfiguratorChain returns a new *ConfiguratorChain.
 func NewConfiguratorChain(l ...Configurator) *ConfiguratorChain {
 	return &ConfiguratorChain{list: l}
@@ -370,6 +544,10 @@ func (c *APIConfigurator) Configure(ctx context.Context, cp resource.Composite,
 		return errors.New(errCompositionNotCompatible)
 	}
 
+	if rev.Spec.DisableConnectionSecrets {
+		return nil
+	}
+
 	if cp.GetWriteConnectionSecretToReference() != nil || rev.Spec.WriteConnectionSecretsToNamespace == nil {
 		return nil
 	}
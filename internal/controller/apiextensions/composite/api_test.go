@@ -33,9 +33,11 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/reference"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -575,6 +577,23 @@ func TestConfigure(t *testing.T) {
 				ObjectMeta: metav1.ObjectMeta{UID: types.UID(cs.Ref.Name)},
 			}},
 		},
+		"ConnectionSecretsDisabled": {
+			reason: "Should not fill connection secret ref if composition disables connection secrets",
+			args: args{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{UID: types.UID(cs.Ref.Name)},
+				},
+				rev: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						WriteConnectionSecretsToNamespace: &cs.Ref.Namespace,
+						DisableConnectionSecrets:          true,
+					},
+				},
+			},
+			want: want{cp: &fake.Composite{
+				ObjectMeta: metav1.ObjectMeta{UID: types.UID(cs.Ref.Name)},
+			}},
+		},
 		"UpdateFailed": {
 			reason: "Should fail if kube update failed",
 			args: args{
@@ -712,6 +731,85 @@ func TestSelectorResolver(t *testing.T) {
 				},
 			},
 		},
+		"SelectedTheAvailableOne": {
+			reason: "Should select the compatible composition that is available to the claim's namespace",
+			args: args{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetLabels(map[string]string{"tenant": "a"})
+						return nil
+					}),
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						compList := &v1.CompositionList{
+							Items: []v1.Composition{
+								{
+									ObjectMeta: metav1.ObjectMeta{Name: "tenant-b"},
+									Spec: v1.CompositionSpec{
+										CompositeTypeRef:       tref,
+										ClaimNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "b"}},
+									},
+								},
+								{
+									ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"},
+									Spec: v1.CompositionSpec{
+										CompositeTypeRef:       tref,
+										ClaimNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}},
+									},
+								},
+							},
+						}
+						compList.DeepCopyInto(obj.(*v1.CompositionList))
+						return nil
+					}),
+				},
+				cp: &fake.Composite{
+					CompositionSelector: fake.CompositionSelector{Sel: sel},
+					ClaimReferencer:     fake.ClaimReferencer{Ref: &reference.Claim{Namespace: "a"}},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: "tenant-a"}},
+					CompositionSelector:   fake.CompositionSelector{Sel: sel},
+					ClaimReferencer:       fake.ClaimReferencer{Ref: &reference.Claim{Namespace: "a"}},
+				},
+			},
+		},
+		"NoneAvailable": {
+			reason: "Should fail if no compatible composition is available to the claim's namespace",
+			args: args{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						compList := &v1.CompositionList{
+							Items: []v1.Composition{
+								{
+									ObjectMeta: metav1.ObjectMeta{Name: "tenant-b"},
+									Spec: v1.CompositionSpec{
+										CompositeTypeRef:       tref,
+										ClaimNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "b"}},
+									},
+								},
+							},
+						}
+						compList.DeepCopyInto(obj.(*v1.CompositionList))
+						return nil
+					}),
+				},
+				cp: &fake.Composite{
+					CompositionSelector: fake.CompositionSelector{Sel: sel},
+					ClaimReferencer:     fake.ClaimReferencer{Ref: &reference.Claim{Namespace: "a"}},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					CompositionSelector: fake.CompositionSelector{Sel: sel},
+					ClaimReferencer:     fake.ClaimReferencer{Ref: &reference.Claim{Namespace: "a"}},
+				},
+				err: errors.Errorf(errFmtNoAvailableComposition, "a"),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -727,6 +825,143 @@ func TestSelectorResolver(t *testing.T) {
 	}
 }
 
+func TestAPIClaimNamespaceRevisionFetcher(t *testing.T) {
+	errBoom := errors.New("boom")
+	claim := &reference.Claim{Namespace: "a"}
+	ref := &corev1.ObjectReference{Name: "cool-composition"}
+
+	// withSelector returns a CompositionRevisionFetcher that fetches a
+	// revision with the supplied claimNamespaceSelector.
+	withSelector := func(sel *metav1.LabelSelector) CompositionRevisionFetcher {
+		return CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+			return &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{ClaimNamespaceSelector: sel}}, nil
+		})
+	}
+
+	// withNamespaceLabels returns a MockGetFn that returns a Namespace with
+	// the supplied labels.
+	withNamespaceLabels := func(l map[string]string) test.MockGetFn {
+		return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			if o, ok := obj.(*corev1.Namespace); ok {
+				o.SetName(claim.Namespace)
+				o.SetLabels(l)
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		kube    client.Reader
+		wrapped CompositionRevisionFetcher
+		cp      resource.Composite
+	}
+	type want struct {
+		rev *v1.CompositionRevision
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FetchError": {
+			reason: "Should return an error if the wrapped fetcher can't fetch the revision",
+			args: args{
+				wrapped: CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+					return nil, errBoom
+				}),
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+					ClaimReferencer:       fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"NoClaim": {
+			reason: "Should return the revision if the composite resource isn't bound to a claim",
+			args: args{
+				wrapped: withSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "b"}}),
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+				},
+			},
+			want: want{
+				rev: &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{ClaimNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "b"}}}},
+			},
+		},
+		"NoSelector": {
+			reason: "Should return a revision without a claimNamespaceSelector without getting the claim's namespace",
+			args: args{
+				wrapped: withSelector(nil),
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+					ClaimReferencer:       fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				rev: &v1.CompositionRevision{},
+			},
+		},
+		"GetNamespaceError": {
+			reason: "Should return an error if the claim's namespace can't be fetched",
+			args: args{
+				kube:    &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				wrapped: withSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}}),
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+					ClaimReferencer:       fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetClaimNamespace),
+			},
+		},
+		"Available": {
+			reason: "Should return a revision whose claimNamespaceSelector matches the claim's namespace",
+			args: args{
+				kube:    &test.MockClient{MockGet: withNamespaceLabels(map[string]string{"tenant": "a"})},
+				wrapped: withSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}}),
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+					ClaimReferencer:       fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				rev: &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{ClaimNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "a"}}}},
+			},
+		},
+		"NotAvailable": {
+			reason: "Should return an error if the revision's claimNamespaceSelector doesn't match the claim's namespace",
+			args: args{
+				kube:    &test.MockClient{MockGet: withNamespaceLabels(map[string]string{"tenant": "a"})},
+				wrapped: withSelector(&metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "b"}}),
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: ref},
+					ClaimReferencer:       fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtCompositionNotAvailable, ref.Name, claim.Namespace),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := NewAPIClaimNamespaceRevisionFetcher(tc.args.kube, tc.args.wrapped)
+			rev, err := f.Fetch(context.Background(), tc.args.cp)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rev, rev); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestAPIDefaultCompositionSelector(t *testing.T) {
 	errBoom := errors.New("boom")
 	a, k := schema.EmptyObjectKind.GroupVersionKind().ToAPIVersionAndKind()
@@ -847,6 +1082,139 @@ func TestAPIDefaultCompositionSelector(t *testing.T) {
 	}
 }
 
+func TestAPINamespaceDefaultCompositionSelector(t *testing.T) {
+	errBoom := errors.New("boom")
+	a, k := schema.EmptyObjectKind.GroupVersionKind().ToAPIVersionAndKind()
+	claim := &reference.Claim{Namespace: "tenant-a"}
+
+	dc := func(name, kind, comp string) v1alpha1.DefaultComposition {
+		return v1alpha1.DefaultComposition{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: claim.Namespace},
+			Spec: v1alpha1.DefaultCompositionSpec{
+				CompositeTypeRef: v1alpha1.CompositeTypeReference{APIVersion: a, Kind: kind},
+				CompositionRef:   v1alpha1.CompositionReference{Name: comp},
+			},
+		}
+	}
+	list := func(dcs ...v1alpha1.DefaultComposition) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			obj.(*v1alpha1.DefaultCompositionList).Items = dcs
+			return nil
+		})
+	}
+
+	type args struct {
+		kube client.Client
+		cp   resource.Composite
+	}
+	type want struct {
+		cp  resource.Composite
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AlreadyResolved": {
+			reason: "Should be a no-op if a composition is already selected",
+			args: args{
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: "cool"}},
+					ClaimReferencer:       fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: "cool"}},
+					ClaimReferencer:       fake.ClaimReferencer{Ref: claim},
+				},
+			},
+		},
+		"NoClaim": {
+			reason: "Should be a no-op if the composite resource isn't bound to a claim",
+			args: args{
+				cp: &fake.Composite{},
+			},
+			want: want{
+				cp: &fake.Composite{},
+			},
+		},
+		"ListError": {
+			reason: "Should return an error if DefaultCompositions can't be listed",
+			args: args{
+				kube: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				cp: &fake.Composite{
+					ClaimReferencer: fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ClaimReferencer: fake.ClaimReferencer{Ref: claim},
+				},
+				err: errors.Wrap(errBoom, errListDefaultCompositions),
+			},
+		},
+		"NoDefault": {
+			reason: "Should be a no-op if the namespace has no DefaultComposition for the composite resource's kind",
+			args: args{
+				kube: &test.MockClient{MockList: list(dc("other", "OtherKind", "other"))},
+				cp: &fake.Composite{
+					ClaimReferencer: fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ClaimReferencer: fake.ClaimReferencer{Ref: claim},
+				},
+			},
+		},
+		"MultipleDefaults": {
+			reason: "Should return an error if the namespace has more than one DefaultComposition for the composite resource's kind",
+			args: args{
+				kube: &test.MockClient{MockList: list(dc("a", k, "cool"), dc("b", k, "lame"))},
+				cp: &fake.Composite{
+					ClaimReferencer: fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ClaimReferencer: fake.ClaimReferencer{Ref: claim},
+				},
+				err: errors.Errorf(errFmtMultipleDefaultCompositions, claim.Namespace, k, "a, b"),
+			},
+		},
+		"Success": {
+			reason: "Should select the composition referenced by the namespace's DefaultComposition",
+			args: args{
+				kube: &test.MockClient{MockList: list(dc("other", "OtherKind", "other"), dc("default", k, "cool"))},
+				cp: &fake.Composite{
+					ClaimReferencer: fake.ClaimReferencer{Ref: claim},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: "cool"}},
+					ClaimReferencer:       fake.ClaimReferencer{Ref: claim},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewAPINamespaceDefaultCompositionSelector(tc.args.kube, event.NewNopRecorder())
+			err := c.SelectComposition(context.Background(), tc.args.cp)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSelectComposition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cp, tc.args.cp); diff != "" {
+				t.Errorf("\n%s\nSelectComposition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestAPIEnforcedCompositionSelector(t *testing.T) {
 	a, k := schema.EmptyObjectKind.GroupVersionKind().ToAPIVersionAndKind()
 	tref := v1.TypeReference{APIVersion: a, Kind: k}
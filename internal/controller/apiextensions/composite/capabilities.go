@@ -0,0 +1,269 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// Error strings.
+const (
+	errMapComposedKind  = "cannot map composed resource kind to a resource"
+	errGetComposedCRD   = "cannot get the CustomResourceDefinition of composed resource"
+	errGetProviderRev   = "cannot get the ProviderRevision that owns the composed resource's CustomResourceDefinition"
+	errFmtMissingCapabs = "composed resource %q uses features that require provider capabilities %v, which the installed provider revision %q doesn't declare"
+	errFmtCheckCapabs   = "cannot check the provider capabilities required by composed resource %q"
+)
+
+// managementPoliciesAll is the default management policy, which every
+// provider supports.
+const managementPoliciesAll = "*"
+
+// DefaultProviderRevisionCacheTTL is how long composers cache the provider
+// revision that owns each kind of composed resource by default.
+const DefaultProviderRevisionCacheTTL = 1 * time.Minute
+
+// A CapabilityChecker checks whether the provider that reconciles a composed
+// resource declares the capabilities the composed resource requires.
+type CapabilityChecker interface {
+	// MissingCapabilities returns the capabilities the supplied composed
+	// resource requires but its provider doesn't declare, and the name of
+	// the provider revision. It returns no capabilities if they can't be
+	// determined.
+	MissingCapabilities(ctx context.Context, cd resource.Composed) (missing []string, revision string, err error)
+}
+
+// A CapabilityCheckerFn checks whether the provider that reconciles a
+// composed resource declares the capabilities the composed resource requires.
+type CapabilityCheckerFn func(ctx context.Context, cd resource.Composed) ([]string, string, error)
+
+// MissingCapabilities returns the capabilities the supplied composed resource
+// requires but its provider doesn't declare.
+func (fn CapabilityCheckerFn) MissingCapabilities(ctx context.Context, cd resource.Composed) ([]string, string, error) {
+	return fn(ctx, cd)
+}
+
+// RequiredCapabilities returns the provider capabilities the supplied
+// composed resource requires, based on the features it uses.
+func RequiredCapabilities(cd resource.Composed) []string {
+	p, err := fieldpath.PaveObject(cd)
+	if err != nil {
+		return nil
+	}
+
+	var required []string
+
+	// The default management policies work with any provider, so only
+	// other policies require support for management policies.
+	if mp, err := p.GetStringArray("spec.managementPolicies"); err == nil {
+		if !(len(mp) == 1 && mp[0] == managementPoliciesAll) {
+			required = append(required, pkgmetav1.ProviderCapabilityManagementPolicies)
+		}
+	}
+	if _, err := p.GetValue("spec.initProvider"); err == nil {
+		required = append(required, pkgmetav1.ProviderCapabilityInitProvider)
+	}
+
+	return required
+}
+
+// A ProviderRevisionFetcher fetches the provider revision that owns a
+// composed resource's CustomResourceDefinition.
+type ProviderRevisionFetcher interface {
+	// FetchProviderRevision returns the provider revision that owns the
+	// supplied composed resource's CustomResourceDefinition. It returns nil
+	// if the composed resource isn't defined by a provider, or if its
+	// provider revision can't be found.
+	FetchProviderRevision(ctx context.Context, cd resource.Composed) (*pkgv1.ProviderRevision, error)
+}
+
+// An APIProviderRevisionFetcher fetches provider revisions from the API
+// server.
+type APIProviderRevisionFetcher struct {
+	client client.Client
+}
+
+// NewAPIProviderRevisionFetcher returns a ProviderRevisionFetcher that
+// fetches provider revisions from the API server.
+func NewAPIProviderRevisionFetcher(c client.Client) *APIProviderRevisionFetcher {
+	return &APIProviderRevisionFetcher{client: c}
+}
+
+// FetchProviderRevision returns the provider revision that owns the supplied
+// composed resource's CustomResourceDefinition.
+func (f *APIProviderRevisionFetcher) FetchProviderRevision(ctx context.Context, cd resource.Composed) (*pkgv1.ProviderRevision, error) {
+	// We can't map kinds to resources without a REST mapper.
+	m := f.client.RESTMapper()
+	if m == nil {
+		return nil, nil
+	}
+
+	gvk := cd.GetObjectKind().GroupVersionKind()
+	mapping, err := m.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, errMapComposedKind)
+	}
+
+	crd := &extv1.CustomResourceDefinition{}
+	if err := f.client.Get(ctx, types.NamespacedName{Name: mapping.Resource.GroupResource().String()}, crd); err != nil {
+		return nil, errors.Wrap(resource.IgnoreNotFound(err), errGetComposedCRD)
+	}
+
+	ref := metav1.GetControllerOf(crd)
+	if ref == nil || ref.Kind != pkgv1.ProviderRevisionKind {
+		return nil, nil
+	}
+
+	rev := &pkgv1.ProviderRevision{}
+	if err := f.client.Get(ctx, types.NamespacedName{Name: ref.Name}, rev); err != nil {
+		return nil, errors.Wrap(resource.IgnoreNotFound(err), errGetProviderRev)
+	}
+	return rev, nil
+}
+
+// A CachingProviderRevisionFetcher caches the provider revisions fetched by
+// another ProviderRevisionFetcher per composed resource kind, so that each
+// kind's provider revision is fetched at most once per TTL no matter how many
+// resources of that kind are composed, or how often. Errors aren't cached.
+type CachingProviderRevisionFetcher struct {
+	wrapped ProviderRevisionFetcher
+	ttl     time.Duration
+	now     func() time.Time
+
+	mx   sync.Mutex
+	revs map[schema.GroupVersionKind]fetchedProviderRevision
+}
+
+type fetchedProviderRevision struct {
+	rev     *pkgv1.ProviderRevision
+	fetched time.Time
+}
+
+// NewCachingProviderRevisionFetcher returns a ProviderRevisionFetcher that
+// caches the provider revisions fetched by the supplied ProviderRevisionFetcher
+// for the supplied TTL.
+func NewCachingProviderRevisionFetcher(f ProviderRevisionFetcher, ttl time.Duration) *CachingProviderRevisionFetcher {
+	return &CachingProviderRevisionFetcher{wrapped: f, ttl: ttl, now: time.Now, revs: make(map[schema.GroupVersionKind]fetchedProviderRevision)}
+}
+
+// FetchProviderRevision returns the cached provider revision that owns the
+// supplied composed resource's CustomResourceDefinition, if it was fetched
+// within the TTL. Otherwise it fetches and caches the provider revision.
+func (c *CachingProviderRevisionFetcher) FetchProviderRevision(ctx context.Context, cd resource.Composed) (*pkgv1.ProviderRevision, error) {
+	gvk := cd.GetObjectKind().GroupVersionKind()
+
+	c.mx.Lock()
+	cached, ok := c.revs[gvk]
+	c.mx.Unlock()
+	if ok && c.now().Sub(cached.fetched) < c.ttl {
+		return cached.rev.DeepCopy(), nil
+	}
+
+	rev, err := c.wrapped.FetchProviderRevision(ctx, cd)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mx.Lock()
+	c.revs[gvk] = fetchedProviderRevision{rev: rev, fetched: c.now()}
+	c.mx.Unlock()
+	return rev.DeepCopy(), nil
+}
+
+// A ProviderCapabilityChecker checks the capabilities declared by the provider
+// revision that owns a composed resource's CustomResourceDefinition.
+type ProviderCapabilityChecker struct {
+	revision ProviderRevisionFetcher
+}
+
+// NewProviderCapabilityChecker returns a CapabilityChecker that reads provider
+// capabilities from the provider revisions the supplied
+// ProviderRevisionFetcher fetches.
+func NewProviderCapabilityChecker(f ProviderRevisionFetcher) *ProviderCapabilityChecker {
+	return &ProviderCapabilityChecker{revision: f}
+}
+
+// MissingCapabilities returns the capabilities the supplied composed resource
+// requires but the provider revision that owns its CustomResourceDefinition
+// doesn't declare. Composed resources that require no capabilities, that
+// aren't defined by a provider, or whose provider declares no capabilities at
+// all are assumed to be supported. Providers built before capabilities were
+// introduced don't declare any.
+func (c *ProviderCapabilityChecker) MissingCapabilities(ctx context.Context, cd resource.Composed) ([]string, string, error) {
+	required := RequiredCapabilities(cd)
+	if len(required) == 0 {
+		return nil, "", nil
+	}
+
+	rev, err := c.revision.FetchProviderRevision(ctx, cd)
+	if err != nil || rev == nil {
+		return nil, "", err
+	}
+
+	declared := rev.Status.Capabilities
+	if len(declared) == 0 {
+		return nil, "", nil
+	}
+
+	has := make(map[string]bool, len(declared))
+	for _, capab := range declared {
+		has[capab] = true
+	}
+	var missing []string
+	for _, capab := range required {
+		if !has[capab] {
+			missing = append(missing, capab)
+		}
+	}
+	return missing, rev.GetName(), nil
+}
+
+// checkCapabilities returns a warning event if the supplied composed resource
+// requires provider capabilities that its provider doesn't declare, or if its
+// capabilities can't be checked.
+func checkCapabilities(ctx context.Context, cc CapabilityChecker, cd resource.Composed, name string) []TargetedEvent {
+	missing, rev, err := cc.MissingCapabilities(ctx, cd)
+	if err != nil {
+		return []TargetedEvent{{
+			Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtCheckCapabs, name)),
+			Target: CompositionTargetComposite,
+		}}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return []TargetedEvent{{
+		Event:  event.Warning(reasonCompose, errors.Errorf(errFmtMissingCapabs, name, missing, rev)),
+		Target: CompositionTargetComposite,
+	}}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// A mappingClient is a MockClient with a REST mapper.
+type mappingClient struct {
+	*test.MockClient
+	mapper kmeta.RESTMapper
+}
+
+func (c *mappingClient) RESTMapper() kmeta.RESTMapper {
+	return c.mapper
+}
+
+func TestMissingCapabilities(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"}
+	mapper := kmeta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, kmeta.RESTScopeRoot)
+
+	cd := func(spec map[string]any) *composed.Unstructured {
+		cd := composed.New()
+		cd.SetGroupVersionKind(gvk)
+		cd.Object["spec"] = spec
+		return cd
+	}
+
+	get := func(owner string, capabilities ...string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *extv1.CustomResourceDefinition:
+				if key.Name != "buckets.example.org" {
+					return errBoom
+				}
+				if owner != "" {
+					o.SetOwnerReferences([]metav1.OwnerReference{{Kind: owner, Name: "provider-example-abc", Controller: ptr.To(true)}})
+				}
+			case *pkgv1.ProviderRevision:
+				o.SetName(key.Name)
+				o.Status.Capabilities = capabilities
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Client
+		cd     *composed.Unstructured
+	}
+	type want struct {
+		missing []string
+		rev     string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoCapabilitiesRequired": {
+			reason: "We shouldn't check a composed resource that doesn't require any capabilities.",
+			args: args{
+				client: &mappingClient{MockClient: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}, mapper: mapper},
+				cd:     cd(map[string]any{"managementPolicies": []any{"*"}}),
+			},
+		},
+		"NotDefinedByProvider": {
+			reason: "We shouldn't report missing capabilities for a composed resource that isn't defined by a provider.",
+			args: args{
+				client: &mappingClient{MockClient: &test.MockClient{MockGet: get("")}, mapper: mapper},
+				cd:     cd(map[string]any{"initProvider": map[string]any{}}),
+			},
+		},
+		"NoCapabilitiesDeclared": {
+			reason: "We shouldn't report missing capabilities if the provider doesn't declare any.",
+			args: args{
+				client: &mappingClient{MockClient: &test.MockClient{MockGet: get(pkgv1.ProviderRevisionKind)}, mapper: mapper},
+				cd:     cd(map[string]any{"initProvider": map[string]any{}}),
+			},
+		},
+		"CapabilitiesDeclared": {
+			reason: "We shouldn't report missing capabilities if the provider declares all the required ones.",
+			args: args{
+				client: &mappingClient{
+					MockClient: &test.MockClient{MockGet: get(pkgv1.ProviderRevisionKind, pkgmetav1.ProviderCapabilityManagementPolicies, pkgmetav1.ProviderCapabilityInitProvider)},
+					mapper:     mapper,
+				},
+				cd: cd(map[string]any{"managementPolicies": []any{"Observe"}, "initProvider": map[string]any{}}),
+			},
+			want: want{
+				rev: "provider-example-abc",
+			},
+		},
+		"CapabilitiesMissing": {
+			reason: "We should report required capabilities the provider doesn't declare.",
+			args: args{
+				client: &mappingClient{
+					MockClient: &test.MockClient{MockGet: get(pkgv1.ProviderRevisionKind, pkgmetav1.ProviderCapabilityManagementPolicies)},
+					mapper:     mapper,
+				},
+				cd: cd(map[string]any{"managementPolicies": []any{"Observe"}, "initProvider": map[string]any{}}),
+			},
+			want: want{
+				missing: []string{pkgmetav1.ProviderCapabilityInitProvider},
+				rev:     "provider-example-abc",
+			},
+		},
+		"GetCRDError": {
+			reason: "We should return any error encountered getting the composed resource's CRD.",
+			args: args{
+				client: &mappingClient{MockClient: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}, mapper: mapper},
+				cd:     cd(map[string]any{"initProvider": map[string]any{}}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetComposedCRD),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewProviderCapabilityChecker(NewAPIProviderRevisionFetcher(tc.args.client))
+			missing, rev, err := c.MissingCapabilities(context.Background(), tc.args.cd)
+
+			if diff := cmp.Diff(tc.want.missing, missing); diff != "" {
+				t.Errorf("\n%s\nMissingCapabilities(...): -want missing, +got missing:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rev, rev); diff != "" {
+				t.Errorf("\n%s\nMissingCapabilities(...): -want revision, +got revision:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMissingCapabilities(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type countingProviderRevisionFetcher struct {
+	rev   *pkgv1.ProviderRevision
+	err   error
+	calls int
+}
+
+func (f *countingProviderRevisionFetcher) FetchProviderRevision(_ context.Context, _ resource.Composed) (*pkgv1.ProviderRevision, error) {
+	f.calls++
+	return f.rev, f.err
+}
+
+func TestCachingProviderRevisionFetcher(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+	rev := &pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "provider-example-abc"}}
+
+	cd := func(kind string) *composed.Unstructured {
+		cd := composed.New()
+		cd.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: kind})
+		return cd
+	}
+
+	type call struct {
+		cd  *composed.Unstructured
+		at  time.Time
+		rev *pkgv1.ProviderRevision
+		err error
+	}
+
+	type want struct {
+		calls int
+	}
+
+	cases := map[string]struct {
+		reason  string
+		wrapped *countingProviderRevisionFetcher
+		calls   []call
+		want    want
+	}{
+		"WithinTTL": {
+			reason:  "We should only fetch a kind's provider revision once within the TTL.",
+			wrapped: &countingProviderRevisionFetcher{rev: rev},
+			calls: []call{
+				{cd: cd("Bucket"), at: now, rev: rev},
+				{cd: cd("Bucket"), at: now.Add(30 * time.Second), rev: rev},
+			},
+			want: want{calls: 1},
+		},
+		"Expired": {
+			reason:  "We should fetch a kind's provider revision again once the TTL has passed.",
+			wrapped: &countingProviderRevisionFetcher{rev: rev},
+			calls: []call{
+				{cd: cd("Bucket"), at: now, rev: rev},
+				{cd: cd("Bucket"), at: now.Add(2 * time.Minute), rev: rev},
+			},
+			want: want{calls: 2},
+		},
+		"DifferentKind": {
+			reason:  "We should fetch the provider revision of each kind separately.",
+			wrapped: &countingProviderRevisionFetcher{rev: rev},
+			calls: []call{
+				{cd: cd("Bucket"), at: now, rev: rev},
+				{cd: cd("Table"), at: now, rev: rev},
+			},
+			want: want{calls: 2},
+		},
+		"NotOwnedByProvider": {
+			reason:  "We should cache that a kind isn't owned by a provider revision.",
+			wrapped: &countingProviderRevisionFetcher{},
+			calls: []call{
+				{cd: cd("Bucket"), at: now},
+				{cd: cd("Bucket"), at: now},
+			},
+			want: want{calls: 1},
+		},
+		"ErrorsNotCached": {
+			reason:  "We shouldn't cache errors fetching the provider revision.",
+			wrapped: &countingProviderRevisionFetcher{err: errBoom},
+			calls: []call{
+				{cd: cd("Bucket"), at: now, err: errBoom},
+				{cd: cd("Bucket"), at: now, err: errBoom},
+			},
+			want: want{calls: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewCachingProviderRevisionFetcher(tc.wrapped, time.Minute)
+			for i, call := range tc.calls {
+				c.now = func() time.Time { return call.at }
+				got, err := c.FetchProviderRevision(context.TODO(), call.cd)
+				if diff := cmp.Diff(call.err, err, test.EquateErrors()); diff != "" {
+					t.Errorf("\n%s\nc.FetchProviderRevision(...) call %d: -want error, +got error:\n%s", tc.reason, i, diff)
+				}
+				if diff := cmp.Diff(call.rev, got); diff != "" {
+					t.Errorf("\n%s\nc.FetchProviderRevision(...) call %d: -want, +got:\n%s", tc.reason, i, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.calls, tc.wrapped.calls); diff != "" {
+				t.Errorf("\n%s\nc.FetchProviderRevision(...): -want wrapped calls, +got wrapped calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"text/template"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errParseNameTemplate   = "cannot parse name template"
+	errExecuteNameTemplate = "cannot execute name template"
+	errGetNameCandidate    = "cannot determine whether composed resource name is in use"
+
+	errFmtInvalidName   = "rendered name %q is invalid: %s"
+	errFmtNameCollision = "rendered name %q and its hashed alternative %q are both in use"
+	errFmtRenderName    = "cannot render the name template of composed resource %q"
+)
+
+// nameHashLength is the number of hex characters of the hash that is appended
+// to a templated name that is already in use.
+const nameHashLength = 8
+
+// A ComposedNameRenderer renders the name of a composed resource from a name
+// template.
+type ComposedNameRenderer interface {
+	// RenderName renders the name of the supplied composed resource from
+	// the supplied template. The template's name is used to disambiguate
+	// the rendered name if it's already in use. Names in the supplied set
+	// are considered in use.
+	RenderName(ctx context.Context, cd resource.Composed, xr resource.Composite, name, tmpl string, used ComposedNames) error
+}
+
+// A ComposedNameRendererFn renders the name of a composed resource from a
+// name template.
+type ComposedNameRendererFn func(ctx context.Context, cd resource.Composed, xr resource.Composite, name, tmpl string, used ComposedNames) error
+
+// RenderName renders the name of the supplied composed resource.
+func (fn ComposedNameRendererFn) RenderName(ctx context.Context, cd resource.Composed, xr resource.Composite, name, tmpl string, used ComposedNames) error {
+	return fn(ctx, cd, xr, name, tmpl, used)
+}
+
+// ComposedNames is a set of composed resource names, keyed by kind and
+// namespace.
+type ComposedNames map[string]bool
+
+// Add the name of the supplied resource to the set.
+func (n ComposedNames) Add(o resource.Object) {
+	if o.GetName() == "" {
+		return
+	}
+	n[composedNameKey(o, o.GetName())] = true
+}
+
+// Has returns true if the supplied name is in the set for the kind and
+// namespace of the supplied resource.
+func (n ComposedNames) Has(o resource.Object, name string) bool {
+	return n[composedNameKey(o, name)]
+}
+
+func composedNameKey(o resource.Object, name string) string {
+	gk := o.GetObjectKind().GroupVersionKind().GroupKind()
+	return strings.Join([]string{gk.String(), o.GetNamespace(), name}, "/")
+}
+
+// An APIComposedNameRenderer renders the names of composed resources from
+// name templates, checking the API server for names that are in use.
+type APIComposedNameRenderer struct {
+	client client.Reader
+}
+
+// NewAPIComposedNameRenderer returns a ComposedNameRenderer that checks the
+// API server for names that are in use.
+func NewAPIComposedNameRenderer(c client.Reader) *APIComposedNameRenderer {
+	return &APIComposedNameRenderer{client: c}
+}
+
+// RenderName renders the name of the supplied composed resource from the
+// supplied template, using the composite resource as the template's data. If
+// the rendered name is in use, either by another composed resource of the
+// same composite resource or by a resource that the composite resource didn't
+// compose, a hyphen and a short hash of the composite resource's UID and the
+// template's name are appended to it. It returns an error if the hashed name
+// is in use too.
+func (r *APIComposedNameRenderer) RenderName(ctx context.Context, cd resource.Composed, xr resource.Composite, name, tmpl string, used ComposedNames) error {
+	rendered, err := RenderNameTemplate(tmpl, xr)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := r.inUse(ctx, cd, xr, rendered, used)
+	if err != nil {
+		return err
+	}
+	if !inUse {
+		cd.SetName(rendered)
+		return nil
+	}
+
+	hashed := HashedName(rendered, xr, name)
+	if errs := validation.IsDNS1123Subdomain(hashed); len(errs) > 0 {
+		return errors.Errorf(errFmtInvalidName, hashed, strings.Join(errs, ", "))
+	}
+	inUse, err = r.inUse(ctx, cd, xr, hashed, used)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.Errorf(errFmtNameCollision, rendered, hashed)
+	}
+	cd.SetName(hashed)
+	return nil
+}
+
+// inUse returns true if the supplied name is already in use by a resource of
+// the same kind and namespace as the supplied composed resource. A resource
+// that was composed by the supplied composite resource is only considered to
+// be in use if it's in the supplied set of names, because it may be a composed
+// resource the composite resource has lost its reference to.
+func (r *APIComposedNameRenderer) inUse(ctx context.Context, cd resource.Composed, xr resource.Composite, name string, used ComposedNames) (bool, error) {
+	if used.Has(cd, name) {
+		return true, nil
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(cd.GetObjectKind().GroupVersionKind())
+	err := r.client.Get(ctx, types.NamespacedName{Namespace: cd.GetNamespace(), Name: name}, existing)
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errGetNameCandidate)
+	}
+	return ComposerOf(existing) != xr.GetUID(), nil
+}
+
+// RenderNameTemplate renders the supplied name template, using the supplied
+// composite resource as the template's data. It returns an error if the
+// template references a field the composite resource doesn't have, or if the
+// rendered name isn't a valid resource name.
+func RenderNameTemplate(tmpl string, xr resource.Composite) (string, error) {
+	t, err := template.New("").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", errors.Wrap(err, errParseNameTemplate)
+	}
+
+	paved, err := fieldpath.PaveObject(xr)
+	if err != nil {
+		return "", err
+	}
+
+	b := &strings.Builder{}
+	if err := t.Execute(b, paved.UnstructuredContent()); err != nil {
+		return "", errors.Wrap(err, errExecuteNameTemplate)
+	}
+
+	name := b.String()
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", errors.Errorf(errFmtInvalidName, name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
+
+// HashedName returns the supplied name suffixed with a hyphen and a short hash
+// of the supplied composite resource's UID and template name. The hash is
+// deterministic, so the same composite resource and template always produce
+// the same name.
+func HashedName(name string, xr resource.Composite, templateName string) string {
+	h := sha256.Sum256([]byte(string(xr.GetUID()) + "/" + templateName))
+	return name + "-" + hex.EncodeToString(h[:])[:nameHashLength]
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+func TestRenderName(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := NewComposite(func(cr resource.Composite) {
+		cr.SetName("cool-xr")
+		cr.SetUID(types.UID("cool-uid"))
+	})
+	cd := func() *composed.Unstructured {
+		cd := composed.New()
+		cd.SetAPIVersion("example.org/v1")
+		cd.SetKind("Bucket")
+		return cd
+	}
+	used := func(names ...string) ComposedNames {
+		n := ComposedNames{}
+		for _, name := range names {
+			o := cd()
+			o.SetName(name)
+			n.Add(o)
+		}
+		return n
+	}
+
+	notFound := kerrors.NewNotFound(schema.GroupResource{}, "")
+	existing := func(controlled bool, names ...string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			for _, n := range names {
+				if key.Name != n {
+					continue
+				}
+				if controlled {
+					obj.SetOwnerReferences([]metav1.OwnerReference{{UID: xr.GetUID(), Controller: ptr.To(true)}})
+				}
+				return nil
+			}
+			return notFound
+		}
+	}
+
+	composedBy := func(uid types.UID, names ...string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			for _, n := range names {
+				if key.Name != n {
+					continue
+				}
+				obj.SetLabels(map[string]string{xcrd.LabelKeyCompositeUID: string(uid)})
+				return nil
+			}
+			return notFound
+		}
+	}
+
+	hashed := HashedName("cool-xr-bucket", xr, "bucket")
+
+	type args struct {
+		client client.Reader
+		tmpl   string
+		used   ComposedNames
+	}
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Available": {
+			reason: "We should use the rendered name if it isn't in use.",
+			args: args{
+				client: &test.MockClient{MockGet: existing(false)},
+				tmpl:   "{{ .metadata.name }}-bucket",
+				used:   used(),
+			},
+			want: want{
+				name: "cool-xr-bucket",
+			},
+		},
+		"ControlledByXR": {
+			reason: "We should use the rendered name if it's in use by a resource the XR controls.",
+			args: args{
+				client: &test.MockClient{MockGet: existing(true, "cool-xr-bucket")},
+				tmpl:   "{{ .metadata.name }}-bucket",
+				used:   used(),
+			},
+			want: want{
+				name: "cool-xr-bucket",
+			},
+		},
+		"ComposedByXRWithoutOwnerReference": {
+			reason: "We should use the rendered name if it's in use by a resource the XR composed without an owner reference.",
+			args: args{
+				client: &test.MockClient{MockGet: composedBy(xr.GetUID(), "cool-xr-bucket")},
+				tmpl:   "{{ .metadata.name }}-bucket",
+				used:   used(),
+			},
+			want: want{
+				name: "cool-xr-bucket",
+			},
+		},
+		"ComposedByOtherXRWithoutOwnerReference": {
+			reason: "We should use the hashed name if the rendered name is used by a resource another XR composed without an owner reference.",
+			args: args{
+				client: &test.MockClient{MockGet: composedBy("other-uid", "cool-xr-bucket")},
+				tmpl:   "{{ .metadata.name }}-bucket",
+				used:   used(),
+			},
+			want: want{
+				name: hashed,
+			},
+		},
+		"UsedByOtherComposedResource": {
+			reason: "We should use the hashed name if the rendered name is used by another of the XR's composed resources.",
+			args: args{
+				client: &test.MockClient{MockGet: existing(false)},
+				tmpl:   "{{ .metadata.name }}-bucket",
+				used:   used("cool-xr-bucket"),
+			},
+			want: want{
+				name: hashed,
+			},
+		},
+		"UsedByOtherResource": {
+			reason: "We should use the hashed name if the rendered name is used by a resource the XR doesn't control.",
+			args: args{
+				client: &test.MockClient{MockGet: existing(false, "cool-xr-bucket")},
+				tmpl:   "{{ .metadata.name }}-bucket",
+				used:   used(),
+			},
+			want: want{
+				name: hashed,
+			},
+		},
+		"HashedNameInUse": {
+			reason: "We should return an error if both the rendered and hashed names are in use.",
+			args: args{
+				client: &test.MockClient{MockGet: existing(false, "cool-xr-bucket", hashed)},
+				tmpl:   "{{ .metadata.name }}-bucket",
+				used:   used(),
+			},
+			want: want{
+				err: errors.Errorf(errFmtNameCollision, "cool-xr-bucket", hashed),
+			},
+		},
+		"GetError": {
+			reason: "We should return any error encountered determining whether a name is in use.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				tmpl:   "{{ .metadata.name }}-bucket",
+				used:   used(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetNameCandidate),
+			},
+		},
+		"InvalidName": {
+			reason: "We should return an error if the rendered name isn't a valid resource name.",
+			args: args{
+				client: &test.MockClient{MockGet: existing(false)},
+				tmpl:   "{{ .metadata.name }}_Bucket",
+				used:   used(),
+			},
+			want: want{
+				err: errors.Errorf(errFmtInvalidName, "cool-xr_Bucket", strings.Join(validation.IsDNS1123Subdomain("cool-xr_Bucket"), ", ")),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewAPIComposedNameRenderer(tc.args.client)
+			got := cd()
+			err := r.RenderName(context.Background(), got, xr, "bucket", tc.args.tmpl, tc.args.used)
+
+			if diff := cmp.Diff(tc.want.name, got.GetName()); diff != "" {
+				t.Errorf("\n%s\nRenderName(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRenderName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,30 +17,131 @@ limitations under the License.
 package composite
 
 import (
+	"strconv"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 // Annotation keys.
 const (
 	AnnotationKeyCompositionResourceName = "crossplane.io/composition-resource-name"
+	AnnotationKeyCompositionPipelineStep = "crossplane.io/composition-pipeline-step"
+
+	// AnnotationKeyReconcileRequestedAt requests that an XR be composed
+	// immediately, even if it was composed less than the minimum reconcile
+	// interval ago. Its value is arbitrary, but is typically a timestamp. Each
+	// distinct value is handled once; the XR's status.lastHandledReconcileAt
+	// field records the value that was last handled.
+	AnnotationKeyReconcileRequestedAt = "crossplane.io/reconcile-requested-at"
+
+	// AnnotationKeyDeletionProtection protects an XR from deletion when its
+	// value is "true". Crossplane adds a finalizer to a protected XR. If the
+	// XR is deleted while protected it's left in place along with its
+	// composed resources, until the annotation is removed or set to any other
+	// value. Crossplane can't protect composed resources from foreground
+	// cascading deletion, which deletes them as soon as the XR is deleted.
+	AnnotationKeyDeletionProtection = "crossplane.io/deletion-protection"
+
+	// AnnotationKeyComposedDeletionPolicy specifies what happens to an XR's
+	// composed resources when the XR is deleted. When its value is "Orphan"
+	// Crossplane removes the XR's owner references from its composed
+	// resources instead of deleting them, and ignores the deletion order of
+	// the XR's Composition. Any other value, or no value, deletes composed
+	// resources. An orphaned managed resource isn't deleted, so its own
+	// deletion policy only applies when it's deleted later. Crossplane can't
+	// orphan composed resources that are deleted by foreground cascading
+	// deletion of the XR.
+	AnnotationKeyComposedDeletionPolicy = "crossplane.io/composed-deletion-policy"
 )
 
+// fieldLastHandledReconcileAt is the XR status field that records the last
+// handled value of the reconcile request annotation.
+const fieldLastHandledReconcileAt = "status.lastHandledReconcileAt"
+
+// fieldCompositionRevisionHash is the XR spec field that pins the hash of the
+// composition revision the XR may be composed using.
+const fieldCompositionRevisionHash = "spec.compositionRevisionHash"
+
 // SetCompositionResourceName sets the name of the composition template used to
 // reconcile a composed resource as an annotation.
 func SetCompositionResourceName(o metav1.Object, n ResourceName) {
 	meta.AddAnnotations(o, map[string]string{AnnotationKeyCompositionResourceName: string(n)})
 }
 
+// SetCompositionPipelineStep sets the name of the Composition pipeline step
+// that last modified a composed resource as an annotation.
+func SetCompositionPipelineStep(o metav1.Object, step string) {
+	meta.AddAnnotations(o, map[string]string{AnnotationKeyCompositionPipelineStep: step})
+}
+
 // GetCompositionResourceName gets the name of the composition template used to
 // reconcile a composed resource from its annotations.
 func GetCompositionResourceName(o metav1.Object) ResourceName {
 	return ResourceName(o.GetAnnotations()[AnnotationKeyCompositionResourceName])
 }
 
+// GetReconcileRequest returns the value of the supplied XR's reconcile request
+// annotation, and whether that value hasn't yet been handled.
+func GetReconcileRequest(xr *composite.Unstructured) (string, bool) {
+	req := xr.GetAnnotations()[AnnotationKeyReconcileRequestedAt]
+	if req == "" {
+		return "", false
+	}
+	handled, _ := fieldpath.Pave(xr.Object).GetString(fieldLastHandledReconcileAt)
+	return req, req != handled
+}
+
+// SetLastHandledReconcileAt records that the supplied reconcile request
+// annotation value was handled in the supplied XR's status.
+func SetLastHandledReconcileAt(xr *composite.Unstructured, req string) error {
+	return fieldpath.Pave(xr.Object).SetValue(fieldLastHandledReconcileAt, req)
+}
+
+// GetCompositionRevisionHash returns the composition revision hash the supplied
+// XR is pinned to, or an empty string if it isn't pinned.
+func GetCompositionRevisionHash(xr *composite.Unstructured) string {
+	h, _ := fieldpath.Pave(xr.Object).GetString(fieldCompositionRevisionHash)
+	return h
+}
+
+// GetCompositionDepth returns how deeply the supplied resource is nested under
+// a top-level composite resource. A resource without a valid composition depth
+// label is at depth zero.
+func GetCompositionDepth(o metav1.Object) int {
+	d, err := strconv.Atoi(o.GetLabels()[xcrd.LabelKeyCompositionDepth])
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// IsCompositionDepthTracked returns true if the supplied resource's
+// composition depth is tracked, i.e. if it has a composition depth label.
+func IsCompositionDepthTracked(o metav1.Object) bool {
+	_, ok := o.GetLabels()[xcrd.LabelKeyCompositionDepth]
+	return ok
+}
+
+// IsDeletionProtected returns true if the supplied XR is protected from
+// deletion.
+func IsDeletionProtected(xr metav1.Object) bool {
+	return xr.GetAnnotations()[AnnotationKeyDeletionProtection] == "true"
+}
+
+// IsOrphaningComposedResources returns true if the supplied XR's composed
+// resources should be orphaned when it's deleted.
+func IsOrphaningComposedResources(xr metav1.Object) bool {
+	return xr.GetAnnotations()[AnnotationKeyComposedDeletionPolicy] == string(xpv1.DeletionOrphan)
+}
+
 // Returns types of patches that are from a composed resource _to_ a composite resource.
 func patchTypesToXR() []v1.PatchType {
 	return []v1.PatchType{v1.PatchTypeToCompositeFieldPath, v1.PatchTypeCombineToComposite}
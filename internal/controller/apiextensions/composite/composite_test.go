@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+func TestGetReconcileRequest(t *testing.T) {
+	requested := func(at string) CompositeModifier {
+		return func(cr resource.Composite) {
+			cr.SetAnnotations(map[string]string{AnnotationKeyReconcileRequestedAt: at})
+		}
+	}
+	handled := func(at string) CompositeModifier {
+		return func(cr resource.Composite) {
+			_ = SetLastHandledReconcileAt(cr.(*composite.Unstructured), at)
+		}
+	}
+
+	type want struct {
+		requestedAt string
+		requested   bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		xr     *composite.Unstructured
+		want   want
+	}{
+		"NotRequested": {
+			reason: "An XR without the annotation hasn't requested a reconcile.",
+			xr:     NewComposite(handled("then")),
+			want:   want{},
+		},
+		"Requested": {
+			reason: "An XR with an unhandled annotation value has requested a reconcile.",
+			xr:     NewComposite(requested("now")),
+			want:   want{requestedAt: "now", requested: true},
+		},
+		"RequestedAgain": {
+			reason: "An XR whose annotation value differs from the last handled value has requested a reconcile.",
+			xr:     NewComposite(requested("now"), handled("then")),
+			want:   want{requestedAt: "now", requested: true},
+		},
+		"AlreadyHandled": {
+			reason: "An XR whose annotation value was already handled hasn't requested another reconcile.",
+			xr:     NewComposite(requested("now"), handled("now")),
+			want:   want{requestedAt: "now", requested: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			at, ok := GetReconcileRequest(tc.xr)
+			got := want{requestedAt: at, requested: ok}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nGetReconcileRequest(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
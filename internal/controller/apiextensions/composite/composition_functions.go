@@ -21,13 +21,17 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
@@ -37,6 +41,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -55,6 +60,7 @@ const (
 	errFetchXRConnectionDetails = "cannot fetch composite resource connection details"
 	errGetExistingCDs           = "cannot get existing composed resources"
 	errBuildObserved            = "cannot build observed state for RunFunctionRequest"
+	errDefaultCDPolicies        = "cannot set default management policies of composed resources"
 	errGarbageCollectCDs        = "cannot garbage collect composed resources that are no longer desired"
 	errApplyXRRefs              = "cannot update composite resource spec.resourceRefs"
 	errApplyXRStatus            = "cannot apply composite resource status"
@@ -67,18 +73,25 @@ const (
 	errExtraResourceAsStruct    = "cannot encode extra resource to protocol buffer Struct well-known type"
 	errUnknownResourceSelector  = "cannot get extra resource by name: unknown resource selector type"
 	errListExtraResources       = "cannot list extra resources"
-
-	errFmtApplyCD                    = "cannot apply composed resource %q"
-	errFmtFetchCDConnectionDetails   = "cannot fetch connection details for composed resource %q (a %s named %s)"
-	errFmtUnmarshalPipelineStepInput = "cannot unmarshal input for Composition pipeline step %q"
-	errFmtGetCredentialsFromSecret   = "cannot get Composition pipeline step %q credential %q from Secret"
-	errFmtRunPipelineStep            = "cannot run Composition pipeline step %q"
-	errFmtControllerMismatch         = "refusing to delete composed resource %q that is controlled by %s %q"
-	errFmtCleanupLabelsCD            = "cannot cleanup composed resource labels of resource %q (a %s named %s)"
-	errFmtDeleteCD                   = "cannot delete composed resource %q (a %s named %s)"
-	errFmtUnmarshalDesiredCD         = "cannot unmarshal desired composed resource %q from RunFunctionResponse"
-	errFmtCDAsStruct                 = "cannot encode composed resource %q to protocol buffer Struct well-known type"
-	errFmtFatalResult                = "pipeline step %q returned a fatal result: %s"
+	errSetFunctionState         = "cannot set composite resource function state"
+	errSeedFunctionContext      = "cannot seed Function context"
+	errUnmarshalFunctionContext = "cannot unmarshal composite resource definition function context"
+
+	errFmtApplyCD                      = "cannot apply composed resource %q"
+	errFmtDryRunApplyCD                = "cannot dry-run apply composed resource %q"
+	errFmtFetchCDConnectionDetails     = "cannot fetch connection details for composed resource %q (a %s named %s)"
+	errFmtUnmarshalPipelineStepInput   = "cannot unmarshal input for Composition pipeline step %q"
+	errFmtInterpolatePipelineStepInput = "cannot interpolate input for Composition pipeline step %q"
+	errFmtGetCredentialsFromSecret     = "cannot get Composition pipeline step %q credential %q from Secret"
+	errFmtRunPipelineStep              = "cannot run Composition pipeline step %q"
+	errFmtPipelineStepEnabled          = "cannot determine whether Composition pipeline step %q is enabled"
+	errFmtControllerMismatch           = "refusing to delete composed resource %q that is controlled by %s %q"
+	errFmtCleanupLabelsCD              = "cannot cleanup composed resource labels of resource %q (a %s named %s)"
+	errFmtDeleteCD                     = "cannot delete composed resource %q (a %s named %s)"
+	errFmtUnmarshalDesiredCD           = "cannot unmarshal desired composed resource %q from RunFunctionResponse"
+	errFmtCDAsStruct                   = "cannot encode composed resource %q to protocol buffer Struct well-known type"
+	errFmtFatalResult                  = "pipeline step %q returned a fatal result: %s"
+	errFmtFunctionStateTooLarge        = "pipeline step %q returned %d bytes of function state, which exceeds the %d byte limit; not persisting it"
 )
 
 // Server-side-apply field owners. We need two of these because it's possible
@@ -98,21 +111,65 @@ const (
 	FieldOwnerComposedPrefix = "apiextensions.crossplane.io/composed"
 )
 
+// Function state is persisted between reconciles for each pipeline step.
+//
+// Function state is a non-authoritative cache. A Function may use it to avoid
+// repeating work, like regenerating a value, but must tolerate it being lost.
+// Each step's state is persisted in the XR's status. It's sent to the step's
+// Function in the RunFunctionRequest context, and read back from the
+// RunFunctionResponse context. It's never sent to any other step.
+const (
+	// ContextKeyFunctionState is the Function context key a pipeline step's
+	// persisted state is sent and returned under.
+	ContextKeyFunctionState = "apiextensions.crossplane.io/function-state"
+
+	// MaxFunctionStateSize is the maximum size, in bytes of JSON, of the
+	// state a pipeline step may persist.
+	MaxFunctionStateSize = 4 * 1024
+
+	// fieldFunctionState is the XR status field that persists function
+	// state, keyed by pipeline step.
+	fieldFunctionState = "functionState"
+
+	// MaxPipelineResults is the maximum number of pipeline results recorded
+	// in an XR's status.
+	MaxPipelineResults = 20
+
+	// fieldPipelineResults is the XR status field that records the results
+	// pipeline steps returned during the most recent reconcile.
+	fieldPipelineResults = "pipelineResults"
+)
+
 // A FunctionComposer supports composing resources using a pipeline of
 // Composition Functions. It ignores the P&T resources array.
 type FunctionComposer struct {
 	client    client.Client
 	composite xr
 	pipeline  FunctionRunner
+
+	// Whether to annotate composed resources with the pipeline step that
+	// last modified them.
+	annotateSteps bool
+
+	// Whether to dry-run apply all composed resources before mutating any
+	// of them.
+	dryRunApply bool
+
+	// Traces Function calls and composed resource applies.
+	tracer trace.Tracer
 }
 
 type xr struct {
 	names.NameGenerator
+	ComposedNamespacer
 	managed.ConnectionDetailsFetcher
 	ComposedResourceObserver
 	ComposedResourceGarbageCollector
 	ExtraResourcesFetcher
 	ManagedFieldsUpgrader
+	FunctionContextSeeder
+	CapabilityChecker
+	ManagementPolicyDefaulter
 }
 
 // A FunctionRunner runs a single Composition Function.
@@ -206,6 +263,14 @@ func WithComposedResourceGarbageCollector(d ComposedResourceGarbageCollector) Fu
 	}
 }
 
+// WithComposedResourceNamespacer configures how the FunctionComposer should
+// render the namespace of composed resources.
+func WithComposedResourceNamespacer(n ComposedNamespacer) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.composite.ComposedNamespacer = n
+	}
+}
+
 // WithManagedFieldsUpgrader configures how the FunctionComposer should upgrade
 // composed resources managed fields from client-side apply to
 // server-side apply.
@@ -215,6 +280,81 @@ func WithManagedFieldsUpgrader(u ManagedFieldsUpgrader) FunctionComposerOption {
 	}
 }
 
+// WithComposedResourceCapabilityChecker configures how the FunctionComposer
+// should check whether providers declare the capabilities composed resources
+// require.
+func WithComposedResourceCapabilityChecker(cc CapabilityChecker) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.composite.CapabilityChecker = cc
+	}
+}
+
+// WithComposedResourceManagementPolicyDefaulter configures how the
+// FunctionComposer should set default management policies on composed managed
+// resources.
+func WithComposedResourceManagementPolicyDefaulter(d ManagementPolicyDefaulter) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.composite.ManagementPolicyDefaulter = d
+	}
+}
+
+// WithPipelineStepAnnotations configures the FunctionComposer to annotate each
+// composed resource with the name of the pipeline step that last modified its
+// desired state.
+func WithPipelineStepAnnotations() FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.annotateSteps = true
+	}
+}
+
+// WithDryRunApply configures the FunctionComposer to dry-run apply all desired
+// composed resources before it garbage collects or applies any of them. If any
+// dry-run fails the FunctionComposer returns an error without mutating any
+// composed resources. This doubles the number of composed resource apply
+// requests the FunctionComposer makes to the API server.
+func WithDryRunApply() FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.dryRunApply = true
+	}
+}
+
+// WithFunctionTracer configures the OpenTelemetry tracer the FunctionComposer
+// should use to trace Function calls and composed resource applies.
+func WithFunctionTracer(t trace.Tracer) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.tracer = t
+	}
+}
+
+// WithFunctionContextSeeder configures how the FunctionComposer should seed
+// the Function pipeline context.
+func WithFunctionContextSeeder(s FunctionContextSeeder) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.composite.FunctionContextSeeder = s
+	}
+}
+
+// A FunctionContextSeeder returns the context the Function pipeline of a
+// composite resource starts with.
+type FunctionContextSeeder interface {
+	SeedFunctionContext(ctx context.Context, xr resource.Composite) (*structpb.Struct, error)
+}
+
+// A FunctionContextSeederFn returns the context the Function pipeline of a
+// composite resource starts with.
+type FunctionContextSeederFn func(ctx context.Context, xr resource.Composite) (*structpb.Struct, error)
+
+// SeedFunctionContext returns the context the Function pipeline of the
+// supplied composite resource starts with.
+func (fn FunctionContextSeederFn) SeedFunctionContext(ctx context.Context, xr resource.Composite) (*structpb.Struct, error) {
+	return fn(ctx, xr)
+}
+
+// emptyFunctionContext seeds an empty Function pipeline context.
+func emptyFunctionContext(_ context.Context, _ resource.Composite) (*structpb.Struct, error) {
+	return &structpb.Struct{Fields: map[string]*structpb.Value{}}, nil
+}
+
 // NewFunctionComposer returns a new Composer that supports composing resources using
 // both Patch and Transform (P&T) logic and a pipeline of Composition Functions.
 func NewFunctionComposer(kube client.Client, r FunctionRunner, o ...FunctionComposerOption) *FunctionComposer {
@@ -228,10 +368,15 @@ func NewFunctionComposer(kube client.Client, r FunctionRunner, o ...FunctionComp
 			ComposedResourceObserver:         NewExistingComposedResourceObserver(kube, f),
 			ComposedResourceGarbageCollector: NewDeletingComposedResourceGarbageCollector(kube),
 			NameGenerator:                    names.NewNameGenerator(kube),
+			ComposedNamespacer:               ComposedNamespacerFn(noRenderNamespace),
 			ManagedFieldsUpgrader:            NewPatchingManagedFieldsUpgrader(kube),
+			FunctionContextSeeder:            FunctionContextSeederFn(emptyFunctionContext),
+			CapabilityChecker:                NewProviderCapabilityChecker(NewCachingProviderRevisionFetcher(NewAPIProviderRevisionFetcher(kube), DefaultProviderRevisionCacheTTL)),
+			ManagementPolicyDefaulter:        ManagementPolicyDefaulterFn(noDefaultManagementPolicies),
 		},
 
 		pipeline: r,
+		tracer:   nopTracer(),
 	}
 
 	for _, fn := range o {
@@ -269,26 +414,92 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		return CompositionResult{}, errors.Wrap(err, errBuildObserved)
 	}
 
+	// Function state is scoped to the step that persisted it, so it's hidden
+	// from the observed XR. Pipeline results and debug records are the output
+	// of the previous reconcile, not part of the XR's observed state.
+	delete(o.GetComposite().GetResource().GetFields()["status"].GetStructValue().GetFields(), fieldFunctionState)
+	delete(o.GetComposite().GetResource().GetFields()["status"].GetStructValue().GetFields(), fieldPipelineResults)
+	delete(o.GetComposite().GetResource().GetFields()["status"].GetStructValue().GetFields(), fieldPipelineDebug)
+
 	// The Function pipeline starts with empty desired state.
 	d := &fnv1.State{}
 
 	events := []TargetedEvent{}
 	conditions := []TargetedCondition{}
+	results := []PipelineResult{}
 
-	// The Function context always starts empty.
-	fctx := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	// Record each step's request and response if debugging is enabled for
+	// this XR. The records are nil otherwise, which removes any records from
+	// a previous reconcile.
+	debug := PipelineDebugEnabled(xr, time.Now())
+	var records []PipelineStepDebug
+
+	// The Function context starts with any seeded entries. Steps may
+	// overwrite them - each step sees the context returned by the last.
+	fctx, err := c.composite.SeedFunctionContext(ctx, xr)
+	if err != nil {
+		return CompositionResult{}, errors.Wrap(err, errSeedFunctionContext)
+	}
+	if fctx.GetFields() == nil {
+		fctx = &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	}
+
+	// Function state is sent only to the step that persisted it.
+	delete(fctx.Fields, ContextKeyFunctionState)
+
+	// The pipeline step that last modified each desired composed resource.
+	steps := map[string]string{}
+
+	// The state each pipeline step persisted. Steps that don't run keep the
+	// state they persisted previously.
+	state := map[string]*structpb.Value{}
+	prevState := GetFunctionState(xr)
+	for _, fn := range req.Revision.Spec.Pipeline {
+		if v, ok := prevState[fn.Step]; ok {
+			state[fn.Step] = v
+		}
+	}
+
+	// The pipeline step that stopped the pipeline, if any.
+	var stoppedBy string
 
 	// Run any Composition Functions in the pipeline. Each Function may mutate
 	// the desired state returned by the last, and each Function may produce
 	// results that will be emitted as events.
 	for _, fn := range req.Revision.Spec.Pipeline {
-		req := &fnv1.RunFunctionRequest{Observed: o, Desired: d, Context: fctx}
+		// A step that isn't enabled is skipped as if it weren't part of the
+		// pipeline. It keeps any state it persisted previously.
+		enabled, err := StepEnabled(fn, xr)
+		if err != nil {
+			return CompositionResult{}, errors.Wrapf(err, errFmtPipelineStepEnabled, fn.Step)
+		}
+		if !enabled {
+			continue
+		}
+
+		// Send this step the state it persisted, if any.
+		if v, ok := state[fn.Step]; ok {
+			if fctx == nil {
+				fctx = &structpb.Struct{}
+			}
+			if fctx.Fields == nil {
+				fctx.Fields = map[string]*structpb.Value{}
+			}
+			fctx.Fields[ContextKeyFunctionState] = v
+		}
+
+		req := &fnv1.RunFunctionRequest{Observed: SelectObserved(o, observed, fn.ObservedResources), Desired: d, Context: fctx}
 
 		if fn.Input != nil {
 			in := &structpb.Struct{}
 			if err := in.UnmarshalJSON(fn.Input.Raw); err != nil {
 				return CompositionResult{}, errors.Wrapf(err, errFmtUnmarshalPipelineStepInput, fn.Step)
 			}
+			if ptr.Deref(fn.InterpolateInput, false) {
+				if err := InterpolateInput(in, fieldpath.Pave(xr.Object)); err != nil {
+					return CompositionResult{}, errors.Wrapf(err, errFmtInterpolatePipelineStepInput, fn.Step)
+				}
+			}
 			req.Input = in
 		}
 
@@ -312,11 +523,35 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 			}
 		}
 
+		// Snapshot the desired state passed to this Function, in case it
+		// mutates the request.
+		var prev *fnv1.State
+		if c.annotateSteps {
+			prev = proto.Clone(d).(*fnv1.State) //nolint:forcetypeassert // Clone always returns the type it was passed.
+		}
+
 		// TODO(negz): Generate a content-addressable tag for this request.
 		// Perhaps using https://github.com/cerbos/protoc-gen-go-hashpb ?
-		rsp, err := c.pipeline.RunFunction(ctx, fn.FunctionRef.Name, req)
+		// The Function call's span context is propagated to the Function,
+		// so any spans the Function emits are its children.
+		sctx, span := c.tracer.Start(ctx, spanRunFunction, trace.WithAttributes(attrStep.String(fn.Step), attrFunction.String(fn.FunctionRef.Name)))
+		rsp, err := c.pipeline.RunFunction(sctx, fn.FunctionRef.Name, req)
+		endSpan(span, err)
+		if debug {
+			records = append(records, NewPipelineStepDebug(fn.Step, req, rsp))
+		}
 		if err != nil {
-			return CompositionResult{}, errors.Wrapf(err, errFmtRunPipelineStep, fn.Step)
+			results = append(results, PipelineResult{Step: fn.Step, Severity: PipelineResultSeverityFatal, Message: err.Error()})
+			return CompositionResult{PipelineResults: results, PipelineDebug: records}, errors.Wrapf(err, errFmtRunPipelineStep, fn.Step)
+		}
+
+		// Record which desired composed resources this Function produced or
+		// modified. A resource's annotation only changes when the step that
+		// last modified it changes, so it's stable across reconciles.
+		for name, dr := range rsp.GetDesired().GetResources() {
+			if prev != nil && !proto.Equal(dr, prev.GetResources()[name]) {
+				steps[name] = fn.Step
+			}
 		}
 
 		// Pass the desired state returned by this Function to the next one.
@@ -326,6 +561,21 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		// We intentionally discard/ignore this after the last Function runs.
 		fctx = rsp.GetContext()
 
+		// Persist the state this step returned, and don't send it to any other
+		// step. A step that doesn't return its state no longer has any.
+		delete(state, fn.Step)
+		if v, ok := fctx.GetFields()[ContextKeyFunctionState]; ok {
+			delete(fctx.Fields, ContextKeyFunctionState)
+			if size := len(protojson.Format(v)); size > MaxFunctionStateSize {
+				events = append(events, TargetedEvent{
+					Event:  event.Warning(reasonCompose, errors.Errorf(errFmtFunctionStateTooLarge, fn.Step, size, MaxFunctionStateSize)),
+					Target: CompositionTargetComposite,
+				})
+			} else {
+				state[fn.Step] = v
+			}
+		}
+
 		for _, c := range rsp.GetConditions() {
 			var status corev1.ConditionStatus
 			switch c.GetStatus() {
@@ -358,16 +608,21 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 			}
 
 			e := TargetedEvent{Target: convertTarget(rs.GetTarget())}
+			pr := PipelineResult{Step: fn.Step, Reason: string(reason), Message: rs.GetMessage()}
 
 			switch rs.GetSeverity() {
 			case fnv1.Severity_SEVERITY_FATAL:
-				return CompositionResult{Events: events, Conditions: conditions}, errors.Errorf(errFmtFatalResult, fn.Step, rs.GetMessage())
+				pr.Severity = PipelineResultSeverityFatal
+				results = append(results, pr)
+				return CompositionResult{Events: events, Conditions: conditions, PipelineResults: results, PipelineDebug: records}, errors.Errorf(errFmtFatalResult, fn.Step, rs.GetMessage())
 			case fnv1.Severity_SEVERITY_WARNING:
 				e.Event = event.Warning(reason, errors.New(rs.GetMessage()))
 				e.Detail = fmt.Sprintf("Pipeline step %q", fn.Step)
+				pr.Severity = PipelineResultSeverityWarning
 			case fnv1.Severity_SEVERITY_NORMAL:
 				e.Event = event.Normal(reason, rs.GetMessage())
 				e.Detail = fmt.Sprintf("Pipeline step %q", fn.Step)
+				pr.Severity = PipelineResultSeverityNormal
 			case fnv1.Severity_SEVERITY_UNSPECIFIED:
 				// We could hit this case if a Function was built against a newer
 				// protobuf than this build of Crossplane, and the new protobuf
@@ -376,8 +631,19 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 				// Explicitly target only the XR, since we're including information
 				// about an exceptional, unexpected state.
 				e.Target = CompositionTargetComposite
+				pr.Severity = PipelineResultSeverityWarning
 			}
 			events = append(events, e)
+			results = append(results, pr)
+		}
+
+		// A Function may stop the pipeline without returning an error, for
+		// example to implement a conditional pipeline. The desired state it
+		// returned is the desired state of the pipeline. Subsequent Functions
+		// don't run.
+		if rsp.GetStopPipeline() {
+			stoppedBy = fn.Step
+			break
 		}
 	}
 
@@ -386,7 +652,7 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	for name, dr := range d.GetResources() {
 		cd := composed.New()
 		if err := FromStruct(cd, dr.GetResource()); err != nil {
-			return CompositionResult{}, errors.Wrapf(err, errFmtUnmarshalDesiredCD, name)
+			return CompositionResult{PipelineDebug: records}, errors.Wrapf(err, errFmtUnmarshalDesiredCD, name)
 		}
 
 		// If this desired resource state pertains to an existing composed
@@ -397,9 +663,17 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 			cd.SetName(or.Resource.GetName())
 		}
 
+		if err := c.composite.RenderNamespace(cd, xr); err != nil {
+			return CompositionResult{PipelineDebug: records}, errors.Wrapf(err, errFmtRenderNamespace, name)
+		}
+
 		// Set standard composed resource metadata that is derived from the XR.
-		if err := RenderComposedResourceMetadata(cd, xr, ResourceName(name)); err != nil {
-			return CompositionResult{}, errors.Wrapf(err, errFmtRenderMetadata, name)
+		if err := RenderComposedResourceMetadata(cd, xr, ResourceName(name), ptr.Deref(req.Revision.Spec.ComposedOwnerReference, v1.ComposedOwnerReferenceController)); err != nil {
+			return CompositionResult{PipelineDebug: records}, errors.Wrapf(err, errFmtRenderMetadata, name)
+		}
+
+		if c.annotateSteps {
+			SetCompositionPipelineStep(cd, steps[name])
 		}
 
 		// Generate a name. We want to allocate this name before we actually
@@ -411,7 +685,7 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		// million names).
 		if cd.GetName() == "" {
 			if err := c.composite.GenerateName(ctx, cd); err != nil {
-				return CompositionResult{}, errors.Wrapf(err, errFmtGenerateName, name)
+				return CompositionResult{PipelineDebug: records}, errors.Wrapf(err, errFmtGenerateName, name)
 			}
 		}
 
@@ -436,12 +710,29 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		compositeRes.Ready = ptr.To(false)
 	}
 
+	// Set default management policies on any desired composed managed
+	// resources that don't specify their own.
+	mpe, err := c.composite.DefaultManagementPolicies(ctx, xr, desired)
+	if err != nil {
+		return CompositionResult{PipelineDebug: records}, errors.Wrap(err, errDefaultCDPolicies)
+	}
+	events = append(events, mpe...)
+
+	// Dry-run apply our desired composed resources before we mutate any of
+	// them, so that a composed resource the API server would reject doesn't
+	// leave us with only some of our desired state applied.
+	if c.dryRunApply {
+		if err := c.dryRunApplyComposed(ctx, xr, desired); err != nil {
+			return CompositionResult{PipelineDebug: records}, err
+		}
+	}
+
 	// Garbage collect any observed resources that aren't part of our final
 	// desired state. We must do this before we update the XR's resource
 	// references to ensure that we don't forget and leak them if a delete
 	// fails.
 	if err := c.composite.GarbageCollectComposedResources(ctx, xr, observed, desired); err != nil {
-		return CompositionResult{}, errors.Wrap(err, errGarbageCollectCDs)
+		return CompositionResult{PipelineDebug: records}, errors.Wrap(err, errGarbageCollectCDs)
 	}
 
 	// Record references to all desired composed resources. We need to do this
@@ -464,7 +755,7 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		// It's important we don't proceed if this fails, because we need to be
 		// sure we've persisted our resource references before we create any new
 		// composed resources below.
-		return CompositionResult{}, errors.Wrap(err, errApplyXRRefs)
+		return CompositionResult{PipelineDebug: records}, errors.Wrap(err, errApplyXRRefs)
 	}
 
 	// TODO: Remove this call to Upgrade once no supported version of
@@ -476,7 +767,7 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	// of fields and field removals won't sync properly.
 	for _, cd := range observed {
 		if err := c.composite.ManagedFieldsUpgrader.Upgrade(ctx, cd.Resource); err != nil {
-			return CompositionResult{}, errors.Wrap(err, "cannot upgrade composed resource's managed fields from client-side to server-side apply")
+			return CompositionResult{PipelineDebug: records}, errors.Wrap(err, "cannot upgrade composed resource's managed fields from client-side to server-side apply")
 		}
 	}
 
@@ -484,10 +775,43 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	// Reconciler uses this array to determine whether the XR is ready.
 	resources := make([]ComposedResource, 0, len(desired))
 
+	// Warnings about composed resources that require capabilities their
+	// provider doesn't declare. The Reconciler only emits these when they
+	// first appear, since they're likely to persist.
+	var warnings []TargetedEvent
+
+	policy := ptr.Deref(req.Revision.Spec.ComposedOwnerReference, v1.ComposedOwnerReferenceController)
+
 	// We apply all of our desired resources before we observe them in the loop
 	// below. This ensures that issues observing and processing one composed
 	// resource won't block the application of another.
 	for name, cd := range desired {
+		// We warn about, but still apply, composed resources that require
+		// provider capabilities their provider doesn't declare.
+		warnings = append(warnings, checkCapabilities(ctx, c.composite, cd.Resource, string(name))...)
+
+		// Server-side apply won't stop us taking over a resource another XR
+		// composed if neither has a controller reference, so we check for
+		// ourselves before we apply a resource we didn't observe.
+		if _, ok := observed[name]; !ok && policy == v1.ComposedOwnerReferenceNone {
+			existing := composed.New()
+			existing.SetGroupVersionKind(cd.Resource.GetObjectKind().GroupVersionKind())
+			err := c.client.Get(ctx, types.NamespacedName{Namespace: cd.Resource.GetNamespace(), Name: cd.Resource.GetName()}, existing)
+			if resource.IgnoreNotFound(err) != nil {
+				return CompositionResult{PipelineDebug: records}, errors.Wrapf(err, errFmtApplyCD, name)
+			}
+			if err == nil {
+				if err := MustBeComposableBy(xr.GetUID())(ctx, existing, cd.Resource); err != nil {
+					events = append(events, TargetedEvent{
+						Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtApplyCD, name)),
+						Target: CompositionTargetComposite,
+					})
+					resources = append(resources, ComposedResource{ResourceName: name, Ready: cd.Ready, Synced: false})
+					continue
+				}
+			}
+		}
+
 		// We don't need any crossplane-runtime resource.Applicator style apply
 		// options here because server-side apply takes care of everything.
 		// Specifically it will merge rather than replace owner references (e.g.
@@ -496,7 +820,10 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		// NOTE(phisco): We need to set a field owner unique for each XR here,
 		// this prevents multiple XRs composing the same resource to be
 		// continuously alternated as controllers.
-		if err := c.client.Patch(ctx, cd.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr))); err != nil {
+		actx, span := c.tracer.Start(ctx, spanApplyComposed, trace.WithAttributes(attrComposedName.String(string(name)), attrComposedKind.String(cd.Resource.GetObjectKind().GroupVersionKind().Kind)))
+		err := c.client.Patch(actx, cd.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr)))
+		endSpan(span, err)
+		if err != nil {
 			if kerrors.IsInvalid(err) {
 				// We tried applying an invalid resource, we can't tell whether
 				// this means the resource will never be valid or it will if we
@@ -517,7 +844,7 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 				resources = append(resources, ComposedResource{ResourceName: name, Ready: cd.Ready, Synced: false})
 				continue
 			}
-			return CompositionResult{}, errors.Wrapf(err, errFmtApplyCD, name)
+			return CompositionResult{PipelineDebug: records}, errors.Wrapf(err, errFmtApplyCD, name)
 		}
 
 		resources = append(resources, ComposedResource{ResourceName: name, Ready: cd.Ready, Synced: true})
@@ -534,23 +861,54 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	n := xr.GetName()
 	u := xr.GetUID()
 	if err := FromStruct(xr, d.GetComposite().GetResource()); err != nil {
-		return CompositionResult{}, errors.Wrap(err, errUnmarshalDesiredXRStatus)
+		return CompositionResult{PipelineDebug: records}, errors.Wrap(err, errUnmarshalDesiredXRStatus)
 	}
 	xr.SetAPIVersion(v)
 	xr.SetKind(k)
 	xr.SetName(n)
 	xr.SetUID(u)
 
+	// Function state is part of our fully specified intent, so any state that
+	// isn't persisted here is removed.
+	if len(state) > 0 {
+		if err := SetFunctionState(xr, state); err != nil {
+			return CompositionResult{PipelineDebug: records}, errors.Wrap(err, errSetFunctionState)
+		}
+	}
+
 	// NOTE(phisco): Here we are fine using a hardcoded field owner as there is
 	// no risk of conflict between different XRs.
 	if err := c.client.Status().Patch(ctx, xr, client.Apply, client.ForceOwnership, client.FieldOwner(FieldOwnerXR)); err != nil {
 		// Note(phisco): here we are fine with this error being terminal, as
 		// there is no other resource to apply that might eventually resolve
 		// this issue.
-		return CompositionResult{}, errors.Wrap(err, errApplyXRStatus)
+		return CompositionResult{PipelineDebug: records}, errors.Wrap(err, errApplyXRStatus)
 	}
 
-	return CompositionResult{ConnectionDetails: d.GetComposite().GetConnectionDetails(), Composite: compositeRes, Composed: resources, Events: events, Conditions: conditions}, nil
+	return CompositionResult{ConnectionDetails: d.GetComposite().GetConnectionDetails(), Composite: compositeRes, Composed: resources, Events: events, CapabilityWarnings: warnings, Conditions: conditions, PipelineResults: results, PipelineDebug: records, PipelineStoppedBy: stoppedBy}, nil
+}
+
+// dryRunApplyComposed dry-run applies the supplied desired composed resources,
+// using the same field owner as a real apply. It returns an error that
+// describes every composed resource the API server wouldn't apply, including
+// invalid composed resources that a real apply would only warn about.
+func (c *FunctionComposer) dryRunApplyComposed(ctx context.Context, xr *composite.Unstructured, desired map[ResourceName]ComposedResourceState) error {
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	errs := make([]error, 0)
+	for _, name := range names {
+		// Dry-run a copy of the composed resource, because a patch overwrites
+		// the object it's passed with the API server's response.
+		cd := desired[ResourceName(name)].Resource.DeepCopyObject().(resource.Composed) //nolint:forcetypeassert // A deep copy is always the same type.
+		if err := c.client.Patch(ctx, cd, client.Apply, client.DryRunAll, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr))); err != nil {
+			errs = append(errs, errors.Wrapf(err, errFmtDryRunApplyCD, name))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // ComposedFieldOwnerName generates a unique field owner name
@@ -622,10 +980,10 @@ func (g *ExistingComposedResourceObserver) ObserveComposedResources(ctx context.
 			return nil, errors.Wrap(err, errGetComposed)
 		}
 
-		if c := metav1.GetControllerOf(r); c != nil && c.UID != xr.GetUID() {
-			// If we don't control this resource we just pretend it doesn't
+		if c := ComposerOf(r); c != "" && c != xr.GetUID() {
+			// If we didn't compose this resource we just pretend it doesn't
 			// exist. We might try to render and re-create it later, but that
-			// should fail because we check the controller ref there too.
+			// should fail because we check who composed it there too.
 			continue
 		}
 
@@ -668,6 +1026,118 @@ func AsState(xr resource.Composite, xc managed.ConnectionDetails, rs ComposedRes
 	return &fnv1.State{Composite: oxr, Resources: ocds}, nil
 }
 
+// GetFunctionState returns the state each pipeline step persisted in the
+// supplied XR's status, keyed by step. Invalid state is ignored; function state
+// is only a cache.
+func GetFunctionState(xr *composite.Unstructured) map[string]*structpb.Value {
+	fs, err := fieldpath.Pave(xr.Object).GetValue("status." + fieldFunctionState)
+	if err != nil {
+		return nil
+	}
+	steps, ok := fs.(map[string]any)
+	if !ok {
+		return nil
+	}
+	out := make(map[string]*structpb.Value, len(steps))
+	for step, s := range steps {
+		v, err := structpb.NewValue(s)
+		if err != nil {
+			continue
+		}
+		out[step] = v
+	}
+	return out
+}
+
+// SetFunctionState persists the supplied state of each pipeline step in the
+// supplied XR's status.
+func SetFunctionState(xr *composite.Unstructured, state map[string]*structpb.Value) error {
+	steps := make(map[string]any, len(state))
+	for step, v := range state {
+		steps[step] = v.AsInterface()
+	}
+	return fieldpath.Pave(xr.Object).SetValue("status."+fieldFunctionState, steps)
+}
+
+// SetPipelineResults records the supplied pipeline results in the supplied
+// XR's status, replacing any results recorded by a previous reconcile. At most
+// MaxPipelineResults are recorded. Normal results are dropped before fatal
+// and warning results, and older results are dropped before newer ones.
+func SetPipelineResults(xr *composite.Unstructured, results []PipelineResult) error {
+	p := fieldpath.Pave(xr.Object)
+	if len(results) == 0 {
+		return p.DeleteField("status." + fieldPipelineResults)
+	}
+
+	keep := make([]bool, len(results))
+	budget := MaxPipelineResults
+	for i := len(results) - 1; i >= 0 && budget > 0; i-- {
+		if results[i].Severity != PipelineResultSeverityNormal {
+			keep[i] = true
+			budget--
+		}
+	}
+	for i := len(results) - 1; i >= 0 && budget > 0; i-- {
+		if !keep[i] {
+			keep[i] = true
+			budget--
+		}
+	}
+
+	out := make([]any, 0, MaxPipelineResults)
+	for i, r := range results {
+		if !keep[i] {
+			continue
+		}
+		pr := map[string]any{
+			"step":     r.Step,
+			"severity": string(r.Severity),
+			"message":  r.Message,
+		}
+		if r.Reason != "" {
+			pr["reason"] = r.Reason
+		}
+		out = append(out, pr)
+	}
+	return p.SetValue("status."+fieldPipelineResults, out)
+}
+
+// SelectObserved returns the observed state to send to a Composition Function
+// that needs only the composed resources selected by the supplied selector.
+// The supplied observed state must have been built from the supplied composed
+// resource states. The returned state shares the selected resources with the
+// supplied state. All observed state is returned if the selector is nil.
+func SelectObserved(o *fnv1.State, rs ComposedResourceStates, s *v1.ObservedResourceSelector) *fnv1.State {
+	if s == nil {
+		return o
+	}
+
+	names := make(map[string]bool, len(s.Names))
+	for _, n := range s.Names {
+		names[n] = true
+	}
+	// An empty label selector matches everything, so only use it if it
+	// specifies some labels.
+	var sel labels.Selector
+	if len(s.MatchLabels) > 0 {
+		sel = labels.SelectorFromSet(s.MatchLabels)
+	}
+
+	ocds := make(map[string]*fnv1.Resource)
+	for name, r := range o.GetResources() {
+		if names[name] {
+			ocds[name] = r
+			continue
+		}
+		or, ok := rs[ResourceName(name)]
+		if sel != nil && ok && or.Resource != nil && sel.Matches(labels.Set(or.Resource.GetLabels())) {
+			ocds[name] = r
+		}
+	}
+
+	return &fnv1.State{Composite: o.GetComposite(), Resources: ocds}
+}
+
 // AsStruct converts the supplied object to a protocol buffer Struct well-known
 // type.
 func AsStruct(o runtime.Object) (*structpb.Struct, error) {
@@ -787,6 +1257,38 @@ func UpdateResourceRefs(xr resource.ComposedResourcesReferencer, desired Compose
 	xr.SetResourceReferences(refs)
 }
 
+// An APIFunctionContextSeeder seeds the Function pipeline context with the
+// function context of a composite resource's definition.
+type APIFunctionContextSeeder struct {
+	client client.Reader
+	defRef corev1.ObjectReference
+}
+
+// NewAPIFunctionContextSeeder returns a FunctionContextSeeder that seeds the
+// Function pipeline context with the function context of the referenced
+// composite resource definition.
+func NewAPIFunctionContextSeeder(c client.Reader, ref corev1.ObjectReference) *APIFunctionContextSeeder {
+	return &APIFunctionContextSeeder{client: c, defRef: ref}
+}
+
+// SeedFunctionContext returns the function context of the composite resource
+// definition. The definition is read each time so changes to its function
+// context apply without restarting the composite resource controller.
+func (s *APIFunctionContextSeeder) SeedFunctionContext(ctx context.Context, _ resource.Composite) (*structpb.Struct, error) {
+	def := &v1.CompositeResourceDefinition{}
+	if err := s.client.Get(ctx, meta.NamespacedNameOf(&s.defRef), def); err != nil {
+		return nil, errors.Wrap(err, errGetXRD)
+	}
+	fctx := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	if def.Spec.FunctionContext == nil || len(def.Spec.FunctionContext.Raw) == 0 {
+		return fctx, nil
+	}
+	if err := fctx.UnmarshalJSON(def.Spec.FunctionContext.Raw); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalFunctionContext)
+	}
+	return fctx, nil
+}
+
 // A PatchingManagedFieldsUpgrader uses a JSON patch to upgrade an object's
 // managed fields from client-side to server-side apply. The upgrade is a no-op
 // if the object does not need upgrading.
@@ -870,8 +1372,13 @@ func (u *PatchingManagedFieldsUpgrader) Upgrade(ctx context.Context, obj client.
 }
 
 func convertTarget(t fnv1.Target) CompositionTarget {
-	if t == fnv1.Target_TARGET_COMPOSITE_AND_CLAIM {
+	switch t {
+	case fnv1.Target_TARGET_COMPOSITE_AND_CLAIM:
 		return CompositionTargetCompositeAndClaim
+	case fnv1.Target_TARGET_CLAIM:
+		return CompositionTargetClaim
+	case fnv1.Target_TARGET_UNSPECIFIED, fnv1.Target_TARGET_COMPOSITE:
+		return CompositionTargetComposite
 	}
 	return CompositionTargetComposite
 }
@@ -18,6 +18,7 @@ package composite
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -28,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,6 +39,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -61,7 +64,6 @@ func TestFunctionCompose(t *testing.T) {
 		o    []FunctionComposerOption
 	}
 	type args struct {
-		ctx context.Context
 		xr  *composite.Unstructured
 		req CompositionRequest
 	}
@@ -225,6 +227,11 @@ func TestFunctionCompose(t *testing.T) {
 			},
 			want: want{
 				err: errors.Wrapf(errBoom, errFmtRunPipelineStep, "run-cool-function"),
+				res: CompositionResult{
+					PipelineResults: []PipelineResult{
+						{Step: "run-cool-function", Severity: PipelineResultSeverityFatal, Message: "boom"},
+					},
+				},
 			},
 		},
 		"FatalFunctionResultError": {
@@ -350,6 +357,11 @@ func TestFunctionCompose(t *testing.T) {
 							Target: CompositionTargetCompositeAndClaim,
 						},
 					},
+					PipelineResults: []PipelineResult{
+						{Step: "run-cool-function", Severity: PipelineResultSeverityNormal, Reason: "ComposeResources", Message: "A result before the fatal result with the default Reason."},
+						{Step: "run-cool-function", Severity: PipelineResultSeverityNormal, Reason: "SomeReason", Message: "A result before the fatal result with a specific Reason."},
+						{Step: "run-cool-function", Severity: PipelineResultSeverityFatal, Reason: "ComposeResources", Message: "oh no"},
+					},
 				},
 			},
 		},
@@ -395,7 +407,7 @@ func TestFunctionCompose(t *testing.T) {
 				},
 			},
 			want: want{
-				err: errors.Wrapf(RenderComposedResourceMetadata(nil, composite.New(), ""), errFmtRenderMetadata, "cool-resource"),
+				err: errors.Wrapf(RenderComposedResourceMetadata(nil, composite.New(), "", v1.ComposedOwnerReferenceController), errFmtRenderMetadata, "cool-resource"),
 			},
 		},
 		"GenerateNameCreateComposedResourceError": {
@@ -646,6 +658,391 @@ func TestFunctionCompose(t *testing.T) {
 				err: errors.Wrapf(errBoom, errFmtApplyCD, "uncool-resource"),
 			},
 		},
+		"DryRunApplyComposedResourceError": {
+			reason: "We should return any error we encounter when dry-run applying a composed resource, without garbage collecting or applying any composed resources",
+			params: params{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "UncoolComposed"}, "")), // all names are available
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, opts ...client.PatchOption) error {
+						if _, ok := obj.(*composed.Unstructured); !ok {
+							return nil
+						}
+						for _, o := range opts {
+							if o == client.DryRunAll {
+								return errBoom
+							}
+						}
+						t.Errorf("Patch(...): unexpected apply of composed resource %q", obj.GetName())
+						return nil
+					},
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+				},
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					d := &fnv1.State{
+						Resources: map[string]*fnv1.Resource{
+							"uncool-resource": {
+								Resource: MustStruct(map[string]any{
+									"apiVersion": "test.crossplane.io/v1",
+									"kind":       "UncoolComposed",
+								}),
+							},
+						},
+					}
+					return &fnv1.RunFunctionResponse{Desired: d}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+						t.Errorf("GarbageCollectComposedResources(...): unexpected garbage collection")
+						return nil
+					})),
+					WithDryRunApply(),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Join(errors.Wrapf(errBoom, errFmtDryRunApplyCD, "uncool-resource")),
+			},
+		},
+		"PipelineStepAnnotations": {
+			reason: "We should annotate each composed resource with the pipeline step that last modified it",
+			params: params{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "CoolComposed"}, "")), // all names are available
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						cd, ok := obj.(*composed.Unstructured)
+						if !ok {
+							return nil
+						}
+						want := map[string]string{
+							"resource-a": "create-resources",
+							"resource-b": "update-resource-b",
+						}[string(GetCompositionResourceName(cd))]
+						if got := cd.GetAnnotations()[AnnotationKeyCompositionPipelineStep]; got != want {
+							t.Errorf("Patch(...): want %q annotation %q, got %q", AnnotationKeyCompositionPipelineStep, want, got)
+						}
+						return nil
+					}),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+				},
+				r: FunctionRunnerFn(func(_ context.Context, name string, req *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					if name == "create-function" {
+						d := &fnv1.State{
+							Resources: map[string]*fnv1.Resource{
+								"resource-a": {Resource: MustStruct(map[string]any{"apiVersion": "test.crossplane.io/v1", "kind": "CoolComposed"})},
+								"resource-b": {Resource: MustStruct(map[string]any{"apiVersion": "test.crossplane.io/v1", "kind": "CoolComposed"})},
+							},
+						}
+						return &fnv1.RunFunctionResponse{Desired: d}, nil
+					}
+					d := req.GetDesired()
+					d.Resources["resource-b"] = &fnv1.Resource{Resource: MustStruct(map[string]any{
+						"apiVersion": "test.crossplane.io/v1",
+						"kind":       "CoolComposed",
+						"spec":       map[string]any{"cool": true},
+					})}
+					return &fnv1.RunFunctionResponse{Desired: d}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+						return nil
+					})),
+					WithPipelineStepAnnotations(),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "create-resources",
+									FunctionRef: v1.FunctionReference{Name: "create-function"},
+								},
+								{
+									Step:        "update-resource-b",
+									FunctionRef: v1.FunctionReference{Name: "update-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{
+						{ResourceName: "resource-a", Synced: true},
+						{ResourceName: "resource-b", Synced: true},
+					},
+				},
+			},
+		},
+		"FunctionState": {
+			reason: "We should send each pipeline step only the state it persisted, persist the state it returns unless it's too large, and drop state for steps no longer in the pipeline.",
+			params: params{
+				kube: &test.MockClient{
+					MockPatch: test.NewMockPatchFn(nil),
+					MockStatusPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+						xr, ok := obj.(*composite.Unstructured)
+						if !ok {
+							return nil
+						}
+						want := map[string]any{"remember": map[string]any{"generated": "abc"}}
+						got, _ := fieldpath.Pave(xr.Object).GetValue("status.functionState")
+						if diff := cmp.Diff(want, got); diff != "" {
+							t.Errorf("Status().Patch(...): -want function state, +got function state:\n%s", diff)
+						}
+						return nil
+					},
+				},
+				r: FunctionRunnerFn(func(_ context.Context, name string, req *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					if _, ok := req.GetObserved().GetComposite().GetResource().GetFields()["status"].GetStructValue().GetFields()["functionState"]; ok {
+						t.Errorf("RunFunction(...): Function %q unexpectedly observed function state in the XR", name)
+					}
+					got := req.GetContext().GetFields()[ContextKeyFunctionState]
+					switch name {
+					case "remember-function":
+						want := structpb.NewStructValue(MustStruct(map[string]any{"generated": "abc"}))
+						if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+							t.Errorf("RunFunction(...): -want function state, +got function state:\n%s", diff)
+						}
+						return &fnv1.RunFunctionResponse{Context: req.GetContext()}, nil
+					case "forget-function":
+						if got != nil {
+							t.Errorf("RunFunction(...): Function %q unexpectedly received function state %v", name, got)
+						}
+						big := structpb.NewStringValue(strings.Repeat("x", MaxFunctionStateSize))
+						return &fnv1.RunFunctionResponse{Context: &structpb.Struct{Fields: map[string]*structpb.Value{ContextKeyFunctionState: big}}}, nil
+					}
+					return nil, errBoom
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+						return nil
+					})),
+				},
+			},
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := WithParentLabel()
+					_ = fieldpath.Pave(xr.Object).SetValue("status.functionState", map[string]any{
+						"remember": map[string]any{"generated": "abc"},
+						"removed":  map[string]any{"generated": "def"},
+					})
+					return xr
+				}(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "remember",
+									FunctionRef: v1.FunctionReference{Name: "remember-function"},
+								},
+								{
+									Step:        "forget",
+									FunctionRef: v1.FunctionReference{Name: "forget-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Events: []TargetedEvent{
+						{
+							Event:  event.Warning(reasonCompose, errors.Errorf(errFmtFunctionStateTooLarge, "forget", MaxFunctionStateSize+2, MaxFunctionStateSize)),
+							Target: CompositionTargetComposite,
+						},
+					},
+				},
+			},
+		},
+		"SeedFunctionContextError": {
+			reason: "We should return any error encountered while seeding the Function context.",
+			params: params{
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithFunctionContextSeeder(FunctionContextSeederFn(func(_ context.Context, _ resource.Composite) (*structpb.Struct, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			args: args{
+				xr:  composite.New(),
+				req: CompositionRequest{Revision: &v1.CompositionRevision{}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errSeedFunctionContext),
+			},
+		},
+		"SeedFunctionContext": {
+			reason: "We should send seeded context entries to the first pipeline step, except function state, and let pipeline steps overwrite them.",
+			params: params{
+				kube: &test.MockClient{
+					MockPatch:       test.NewMockPatchFn(nil),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+				},
+				r: FunctionRunnerFn(func(_ context.Context, name string, req *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					if got := req.GetContext().GetFields()[ContextKeyFunctionState]; got != nil {
+						t.Errorf("RunFunction(...): Function %q unexpectedly received seeded function state %v", name, got)
+					}
+					got := req.GetContext().GetFields()["accounts"]
+					switch name {
+					case "first-function":
+						want := structpb.NewStructValue(MustStruct(map[string]any{"prod": "123"}))
+						if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+							t.Errorf("RunFunction(...): -want seeded context, +got context:\n%s", diff)
+						}
+						return &fnv1.RunFunctionResponse{Context: MustStruct(map[string]any{"accounts": map[string]any{"prod": "456"}})}, nil
+					case "second-function":
+						want := structpb.NewStructValue(MustStruct(map[string]any{"prod": "456"}))
+						if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+							t.Errorf("RunFunction(...): -want overwritten context, +got context:\n%s", diff)
+						}
+						return &fnv1.RunFunctionResponse{}, nil
+					}
+					return nil, errBoom
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+						return nil
+					})),
+					WithFunctionContextSeeder(FunctionContextSeederFn(func(_ context.Context, _ resource.Composite) (*structpb.Struct, error) {
+						return MustStruct(map[string]any{
+							"accounts":              map[string]any{"prod": "123"},
+							ContextKeyFunctionState: "seeded",
+						}), nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "first",
+									FunctionRef: v1.FunctionReference{Name: "first-function"},
+								},
+								{
+									Step:        "second",
+									FunctionRef: v1.FunctionReference{Name: "second-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{},
+			},
+		},
+		"StopPipeline": {
+			reason: "We should skip subsequent pipeline steps when a Function stops the pipeline, using the desired state it returned",
+			params: params{
+				kube: &test.MockClient{
+					MockGet:         test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "CoolComposed"}, "")), // all names are available
+					MockPatch:       test.NewMockPatchFn(nil),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+				},
+				r: FunctionRunnerFn(func(_ context.Context, name string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					if name != "stop-function" {
+						t.Errorf("RunFunction(...): unexpectedly ran Function %q after the pipeline was stopped", name)
+						return nil, errBoom
+					}
+					d := &fnv1.State{
+						Resources: map[string]*fnv1.Resource{
+							"resource-a": {Resource: MustStruct(map[string]any{"apiVersion": "test.crossplane.io/v1", "kind": "CoolComposed"})},
+						},
+					}
+					return &fnv1.RunFunctionResponse{Desired: d, StopPipeline: true}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+						return nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "stop",
+									FunctionRef: v1.FunctionReference{Name: "stop-function"},
+								},
+								{
+									Step:        "never-run",
+									FunctionRef: v1.FunctionReference{Name: "never-run-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{
+						{ResourceName: "resource-a", Synced: true},
+					},
+					PipelineStoppedBy: "stop",
+				},
+			},
+		},
 		"Successful": {
 			reason: "We should return a valid CompositionResult when a 'pure Function' (i.e. patch-and-transform-less) reconcile succeeds",
 			params: params{
@@ -861,6 +1258,12 @@ func TestFunctionCompose(t *testing.T) {
 							Target: CompositionTargetCompositeAndClaim,
 						},
 					},
+					PipelineResults: []PipelineResult{
+						{Step: "run-cool-function", Severity: PipelineResultSeverityNormal, Reason: "ComposeResources", Message: "A normal result"},
+						{Step: "run-cool-function", Severity: PipelineResultSeverityWarning, Reason: "ComposeResources", Message: "A warning result"},
+						{Step: "run-cool-function", Severity: PipelineResultSeverityWarning, Reason: "ComposeResources", Message: "A result of unspecified severity"},
+						{Step: "run-cool-function", Severity: PipelineResultSeverityNormal, Reason: "SomeReason", Message: "A result with all values explicitly set."},
+					},
 				},
 				err: nil,
 			},
@@ -870,7 +1273,7 @@ func TestFunctionCompose(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			c := NewFunctionComposer(tc.params.kube, tc.params.r, tc.params.o...)
-			res, err := c.Compose(tc.args.ctx, tc.args.xr, tc.args.req)
+			res, err := c.Compose(context.Background(), tc.args.xr, tc.args.req)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nCompose(...): -want, +got:\n%s", tc.reason, diff)
@@ -1197,6 +1600,107 @@ func TestAsState(t *testing.T) {
 	}
 }
 
+func TestSelectObserved(t *testing.T) {
+	cd := func(l map[string]string) *composed.Unstructured {
+		cd := composed.New()
+		cd.SetLabels(l)
+		return cd
+	}
+	res := func(name string) *fnv1.Resource {
+		return &fnv1.Resource{Resource: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"name": structpb.NewStringValue(name),
+		}}}
+	}
+
+	xr := res("cool-xr")
+	o := &fnv1.State{
+		Composite: xr,
+		Resources: map[string]*fnv1.Resource{
+			"cool-resource":   res("cool-resource"),
+			"cooler-resource": res("cooler-resource"),
+			"uncool-resource": res("uncool-resource"),
+		},
+	}
+	rs := ComposedResourceStates{
+		"cool-resource":   ComposedResourceState{Resource: cd(map[string]string{"cool": "true"})},
+		"cooler-resource": ComposedResourceState{Resource: cd(map[string]string{"cool": "true", "cooler": "true"})},
+		"uncool-resource": ComposedResourceState{Resource: cd(nil)},
+	}
+
+	type args struct {
+		o  *fnv1.State
+		rs ComposedResourceStates
+		s  *v1.ObservedResourceSelector
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *fnv1.State
+	}{
+		"NoSelector": {
+			reason: "We should return all observed state if there's no selector.",
+			args: args{
+				o:  o,
+				rs: rs,
+			},
+			want: o,
+		},
+		"EmptySelector": {
+			reason: "An empty selector should select no composed resources, but we should still return the XR.",
+			args: args{
+				o:  o,
+				rs: rs,
+				s:  &v1.ObservedResourceSelector{},
+			},
+			want: &fnv1.State{Composite: xr, Resources: map[string]*fnv1.Resource{}},
+		},
+		"SelectByName": {
+			reason: "We should return composed resources with the selected names.",
+			args: args{
+				o:  o,
+				rs: rs,
+				s:  &v1.ObservedResourceSelector{Names: []string{"uncool-resource", "nonexistent-resource"}},
+			},
+			want: &fnv1.State{Composite: xr, Resources: map[string]*fnv1.Resource{
+				"uncool-resource": res("uncool-resource"),
+			}},
+		},
+		"SelectByLabels": {
+			reason: "We should return composed resources that have all of the selected labels.",
+			args: args{
+				o:  o,
+				rs: rs,
+				s:  &v1.ObservedResourceSelector{MatchLabels: map[string]string{"cool": "true", "cooler": "true"}},
+			},
+			want: &fnv1.State{Composite: xr, Resources: map[string]*fnv1.Resource{
+				"cooler-resource": res("cooler-resource"),
+			}},
+		},
+		"SelectByNameOrLabels": {
+			reason: "We should return composed resources that are selected by name or by labels.",
+			args: args{
+				o:  o,
+				rs: rs,
+				s: &v1.ObservedResourceSelector{
+					Names:       []string{"uncool-resource"},
+					MatchLabels: map[string]string{"cool": "true"},
+				},
+			},
+			want: o,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SelectObserved(tc.args.o, tc.args.rs, tc.args.s)
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nSelectObserved(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestGarbageCollectComposedResources(t *testing.T) {
 	errBoom := errors.New("boom")
 
@@ -1410,6 +1914,71 @@ func TestGarbageCollectComposedResources(t *testing.T) {
 	}
 }
 
+func TestSeedFunctionContext(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type params struct {
+		client client.Reader
+	}
+
+	type want struct {
+		fctx *structpb.Struct
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params params
+		want   want
+	}{
+		"GetDefinitionError": {
+			reason: "We should return any error encountered getting the definition.",
+			params: params{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetXRD),
+			},
+		},
+		"NoFunctionContext": {
+			reason: "We should return an empty context if the definition doesn't seed one.",
+			params: params{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			},
+			want: want{
+				fctx: &structpb.Struct{Fields: map[string]*structpb.Value{}},
+			},
+		},
+		"FunctionContext": {
+			reason: "We should return the definition's function context.",
+			params: params{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					def := obj.(*v1.CompositeResourceDefinition)
+					def.Spec.FunctionContext = &runtime.RawExtension{Raw: []byte(`{"accounts":{"prod":"123"}}`)}
+					return nil
+				})},
+			},
+			want: want{
+				fctx: MustStruct(map[string]any{"accounts": map[string]any{"prod": "123"}}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := NewAPIFunctionContextSeeder(tc.params.client, corev1.ObjectReference{Name: "cool-xrd"})
+			fctx, err := s.SeedFunctionContext(context.Background(), composite.New())
+
+			if diff := cmp.Diff(tc.want.fctx, fctx, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nSeedFunctionContext(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSeedFunctionContext(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestUpdateResourceRefs(t *testing.T) {
 	type args struct {
 		xr  resource.ComposedResourcesReferencer
@@ -1477,3 +2046,70 @@ func TestUpdateResourceRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestSetPipelineResults(t *testing.T) {
+	result := func(step string, s PipelineResultSeverity) PipelineResult {
+		return PipelineResult{Step: step, Severity: s, Message: "a result"}
+	}
+	results := func(rs []PipelineResult) any {
+		out := make([]any, len(rs))
+		for i, r := range rs {
+			out[i] = map[string]any{"step": r.Step, "severity": string(r.Severity), "message": r.Message}
+		}
+		return out
+	}
+
+	manyNormal := make([]PipelineResult, MaxPipelineResults)
+	for i := range manyNormal {
+		manyNormal[i] = result("normal", PipelineResultSeverityNormal)
+	}
+
+	cases := map[string]struct {
+		reason  string
+		xr      *composite.Unstructured
+		results []PipelineResult
+		want    any
+	}{
+		"NoResults": {
+			reason: "Results recorded by a previous reconcile should be removed if there are no results.",
+			xr: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+				"status": map[string]any{
+					"pipelineResults": results([]PipelineResult{result("old", PipelineResultSeverityWarning)}),
+				},
+			}}},
+			want: nil,
+		},
+		"SomeResults": {
+			reason: "Results should replace those recorded by a previous reconcile.",
+			xr: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+				"status": map[string]any{
+					"pipelineResults": results([]PipelineResult{result("old", PipelineResultSeverityWarning)}),
+				},
+			}}},
+			results: []PipelineResult{result("a", PipelineResultSeverityNormal), result("b", PipelineResultSeverityFatal)},
+			want:    results([]PipelineResult{result("a", PipelineResultSeverityNormal), result("b", PipelineResultSeverityFatal)}),
+		},
+		"TooManyResults": {
+			reason: "Normal results should be dropped before others when there are too many results.",
+			xr:     composite.New(),
+			results: append(
+				[]PipelineResult{result("first", PipelineResultSeverityWarning)},
+				append(manyNormal, result("last", PipelineResultSeverityFatal))...),
+			want: results(append(
+				[]PipelineResult{result("first", PipelineResultSeverityWarning)},
+				append(manyNormal[2:], result("last", PipelineResultSeverityFatal))...)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := SetPipelineResults(tc.xr, tc.results); err != nil {
+				t.Fatalf("\n%s\nSetPipelineResults(...): unexpected error: %s", tc.reason, err)
+			}
+			got, _ := fieldpath.Pave(tc.xr.Object).GetValue("status.pipelineResults")
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSetPipelineResults(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
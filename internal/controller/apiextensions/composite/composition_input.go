@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+const (
+	errFmtInputReference   = "cannot resolve input reference %q"
+	errFmtInputNotScalar   = "input reference %q must resolve to a string, number, or boolean when it's part of a larger string"
+	errFmtInputStructValue = "cannot convert the value of input reference %q to a protocol buffer Value"
+)
+
+// An input reference looks like ${xr.spec.region}. A reference escaped with an
+// extra dollar sign, like $${xr.spec.region}, is left as a literal.
+var inputReference = regexp.MustCompile(`\$?\$\{xr\.([^}]*)\}`)
+
+// InterpolateInput replaces references to composite resource fields within the
+// string values of the supplied Function input. A reference has the form
+// ${xr.<fieldpath>}, for example ${xr.spec.region}.
+//
+// A string that consists solely of one reference is replaced by the referenced
+// value, which may be of any type. A reference that's part of a larger string
+// must resolve to a string, number, or boolean. A reference to a field that
+// doesn't exist is an error. A reference escaped as $${xr.<fieldpath>} is
+// replaced by the literal ${xr.<fieldpath>}. Other text, including ${...}
+// sequences that don't start with xr., is left as is.
+func InterpolateInput(in *structpb.Struct, xr *fieldpath.Paved) error {
+	for k, v := range in.GetFields() {
+		nv, err := interpolateValue(v, xr)
+		if err != nil {
+			return err
+		}
+		in.Fields[k] = nv
+	}
+	return nil
+}
+
+func interpolateValue(v *structpb.Value, xr *fieldpath.Paved) (*structpb.Value, error) {
+	switch t := v.GetKind().(type) {
+	case *structpb.Value_StructValue:
+		return v, InterpolateInput(t.StructValue, xr)
+	case *structpb.Value_ListValue:
+		for i, e := range t.ListValue.GetValues() {
+			nv, err := interpolateValue(e, xr)
+			if err != nil {
+				return nil, err
+			}
+			t.ListValue.Values[i] = nv
+		}
+		return v, nil
+	case *structpb.Value_StringValue:
+		return interpolateString(t.StringValue, xr)
+	default:
+		return v, nil
+	}
+}
+
+func interpolateString(s string, xr *fieldpath.Paved) (*structpb.Value, error) {
+	// The string consists solely of one reference, so replace it with the
+	// referenced value, whatever its type.
+	if m := inputReference.FindStringSubmatch(s); m != nil && m[0] == s && !strings.HasPrefix(s, "$$") {
+		val, err := resolveReference(m[1], xr)
+		if err != nil {
+			return nil, err
+		}
+		sv, err := structpb.NewValue(val)
+		return sv, errors.Wrapf(err, errFmtInputStructValue, "xr."+m[1])
+	}
+
+	var rerr error
+	out := inputReference.ReplaceAllStringFunc(s, func(match string) string {
+		if rerr != nil {
+			return match
+		}
+		if strings.HasPrefix(match, "$$") {
+			return match[1:]
+		}
+		ref := inputReference.FindStringSubmatch(match)[1]
+		val, err := resolveReference(ref, xr)
+		if err != nil {
+			rerr = err
+			return match
+		}
+		switch v := val.(type) {
+		case string:
+			return v
+		case bool, int64, float64:
+			b, _ := json.Marshal(v)
+			return string(b)
+		default:
+			rerr = errors.Errorf(errFmtInputNotScalar, "xr."+ref)
+			return match
+		}
+	})
+	if rerr != nil {
+		return nil, rerr
+	}
+	return structpb.NewStringValue(out), nil
+}
+
+// resolveReference returns the value of the supplied composite resource field
+// path.
+func resolveReference(path string, xr *fieldpath.Paved) (any, error) {
+	val, err := xr.GetValue(path)
+	return val, errors.Wrapf(err, errFmtInputReference, "xr."+path)
+}
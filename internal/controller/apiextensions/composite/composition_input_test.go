@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestInterpolateInput(t *testing.T) {
+	xr := map[string]any{
+		"spec": map[string]any{
+			"region":   "us-east-1",
+			"replicas": int64(3),
+			"enabled":  true,
+			"tags": map[string]any{
+				"team": "platform",
+			},
+		},
+	}
+
+	type args struct {
+		in map[string]any
+	}
+	type want struct {
+		in  map[string]any
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"WholeStringReference": {
+			reason: "A string that is only a reference should be replaced by the referenced value, preserving its type.",
+			args: args{
+				in: map[string]any{
+					"region":   "${xr.spec.region}",
+					"replicas": "${xr.spec.replicas}",
+					"tags":     "${xr.spec.tags}",
+				},
+			},
+			want: want{
+				in: map[string]any{
+					"region":   "us-east-1",
+					"replicas": float64(3),
+					"tags":     map[string]any{"team": "platform"},
+				},
+			},
+		},
+		"EmbeddedReferences": {
+			reason: "References within a larger string should be replaced by their formatted values.",
+			args: args{
+				in: map[string]any{
+					"nested": map[string]any{
+						"list": []any{"env-${xr.spec.region}", "${xr.spec.replicas} replicas, enabled: ${xr.spec.enabled}"},
+					},
+				},
+			},
+			want: want{
+				in: map[string]any{
+					"nested": map[string]any{
+						"list": []any{"env-us-east-1", "3 replicas, enabled: true"},
+					},
+				},
+			},
+		},
+		"EscapedAndUnrelatedReferences": {
+			reason: "Escaped references and ${...} sequences that don't reference the XR should be left as literals.",
+			args: args{
+				in: map[string]any{
+					"escaped":   "$${xr.spec.region}",
+					"unrelated": "echo ${HOME}",
+				},
+			},
+			want: want{
+				in: map[string]any{
+					"escaped":   "${xr.spec.region}",
+					"unrelated": "echo ${HOME}",
+				},
+			},
+		},
+		"MissingField": {
+			reason: "A reference to a field that doesn't exist should return an error.",
+			args: args{
+				in: map[string]any{
+					"zone": "${xr.spec.zone}",
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New("spec.zone: no such field"), errFmtInputReference, "xr.spec.zone"),
+			},
+		},
+		"EmbeddedObject": {
+			reason: "A reference to an object within a larger string should return an error.",
+			args: args{
+				in: map[string]any{
+					"tags": "tags: ${xr.spec.tags}",
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtInputNotScalar, "xr.spec.tags"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in, _ := structpb.NewStruct(tc.args.in)
+			err := InterpolateInput(in, fieldpath.Pave(xr))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInterpolateInput(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			want, _ := structpb.NewStruct(tc.want.in)
+			if diff := cmp.Diff(want, in, protocmp.Transform()); diff != "" {
+				t.Errorf("\n%s\nInterpolateInput(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -40,6 +40,7 @@ const (
 	errFmtCombineConfigMissing        = "given combine strategy %s requires configuration"
 	errFmtCombineStrategyFailed       = "%s strategy could not combine"
 	errFmtExpandingArrayFieldPaths    = "cannot expand ToFieldPath %s"
+	errFmtSecretTarget                = "cannot patch a value read from a Secret into %s of a %s; it may only be patched into the data of a Secret"
 )
 
 // Apply executes a patching operation between the from and to resources.
@@ -56,6 +57,10 @@ func ApplyToObjects(p v1.Patch, cp, cd runtime.Object, only ...v1.PatchType) err
 		return nil
 	}
 
+	if ok, err := MatchesCondition(p, cp); err != nil || !ok {
+		return err
+	}
+
 	switch p.GetType() {
 	case v1.PatchTypeFromCompositeFieldPath:
 		return ApplyFromFieldPathPatch(p, cp, cd)
@@ -170,6 +175,38 @@ func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
 	return patchFieldValueToObject(*p.ToFieldPath, out, to, mo)
 }
 
+// ApplyFromReferencedKeyPatch patches the "to" resource, using the supplied
+// value of the key referenced by the patch. The value may be transformed if any
+// transforms are defined on the patch. A value read from a Secret may only be
+// patched into the data of a Secret.
+func ApplyFromReferencedKeyPatch(p v1.Patch, value any, to runtime.Object) error {
+	if p.ReferencedKey == nil {
+		return errors.Errorf(errFmtRequiredField, "ReferencedKey", p.Type)
+	}
+	if p.ToFieldPath == nil {
+		return errors.Errorf(errFmtRequiredField, "ToFieldPath", p.Type)
+	}
+
+	if p.ReferencedKey.Kind == v1.ReferencedKeyKindSecret {
+		gvk := to.GetObjectKind().GroupVersionKind()
+		if !v1.IsSecretDataFieldPath(gvk.GroupVersion().String(), gvk.Kind, *p.ToFieldPath) {
+			return errors.Errorf(errFmtSecretTarget, *p.ToFieldPath, gvk.Kind)
+		}
+	}
+
+	var mo *xpv1.MergeOptions
+	if p.Policy != nil {
+		mo = p.Policy.MergeOptions
+	}
+
+	out, err := ResolveTransforms(p, value)
+	if err != nil {
+		return err
+	}
+
+	return patchFieldValueToObject(*p.ToFieldPath, out, to, mo)
+}
+
 // ApplyCombineFromVariablesPatch patches the "to" resource, taking a list of
 // input variables and combining them into a single output value.
 // The single output value may then be further transformed if they are defined
@@ -305,10 +342,26 @@ func ComposedTemplates(pss []v1.PatchSet, cts []v1.ComposedTemplate) ([]v1.Compo
 			if !ok {
 				return nil, errors.Errorf(errFmtUndefinedPatchSet, *p.PatchSetName)
 			}
-			po = append(po, ps...)
+			for _, sp := range ps {
+				sp.MatchCondition = combineMatchConditions(p.MatchCondition, sp.MatchCondition)
+				po = append(po, sp)
+			}
 		}
 		ct[i] = r
 		ct[i].Patches = po
 	}
 	return ct, nil
 }
+
+// combineMatchConditions returns a match condition that is true only when both
+// supplied match conditions are. A nil match condition is always true.
+func combineMatchConditions(a, b *string) *string {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	c := fmt.Sprintf("(%s) && (%s)", *a, *b)
+	return &c
+}
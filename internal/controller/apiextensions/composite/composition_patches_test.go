@@ -95,6 +95,53 @@ func TestPatchApply(t *testing.T) {
 				err: errors.Errorf(errFmtInvalidPatchType, "invalid-patchtype"),
 			},
 		},
+		"MatchConditionFalse": {
+			reason: "Should skip a patch whose match condition is false, even if its fromFieldPath is required and missing",
+			args: args{
+				patch: v1.Patch{
+					Type:           v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath:  ptr.To("objectMeta.labels.missing"),
+					ToFieldPath:    ptr.To("objectMeta.labels.missing"),
+					Policy:         &v1.PatchPolicy{FromFieldPath: ptr.To(v1.FromFieldPathPolicyRequired)},
+					MatchCondition: ptr.To(`xr.objectMeta.labels.env == "production"`),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cp",
+						Labels: map[string]string{"env": "dev"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+		},
+		"MatchConditionTrue": {
+			reason: "Should apply a patch whose match condition is true",
+			args: args{
+				patch: v1.Patch{
+					Type:           v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath:  ptr.To("objectMeta.labels.env"),
+					MatchCondition: ptr.To(`xr.objectMeta.labels.env == "production"`),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cp",
+						Labels: map[string]string{"env": "production"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{
+					Name:   "cd",
+					Labels: map[string]string{"env": "production"},
+				}},
+			},
+		},
 		"ValidCompositeFieldPathPatch": {
 			reason: "Should correctly apply a CompositeFieldPathPatch with valid settings",
 			args: args{
@@ -1167,6 +1214,56 @@ func TestComposedTemplates(t *testing.T) {
 				},
 			},
 		},
+		"PatchSetMatchCondition": {
+			reason: "A match condition on a PatchSet patch should apply to every patch in the set, combined with the patch's own condition",
+			args: args{
+				pss: []v1.PatchSet{
+					{
+						Name: "patch-set-1",
+						Patches: []v1.Patch{
+							{
+								Type:          v1.PatchTypeFromCompositeFieldPath,
+								FromFieldPath: ptr.To("metadata.namespace"),
+							},
+							{
+								Type:           v1.PatchTypeFromCompositeFieldPath,
+								FromFieldPath:  ptr.To("spec.parameters.test"),
+								MatchCondition: ptr.To("has(xr.spec.parameters.test)"),
+							},
+						},
+					},
+				},
+				cts: []v1.ComposedTemplate{
+					{
+						Patches: []v1.Patch{
+							{
+								Type:           v1.PatchTypePatchSet,
+								PatchSetName:   ptr.To("patch-set-1"),
+								MatchCondition: ptr.To(`xr.spec.env == "production"`),
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				ct: []v1.ComposedTemplate{
+					{
+						Patches: []v1.Patch{
+							{
+								Type:           v1.PatchTypeFromCompositeFieldPath,
+								FromFieldPath:  ptr.To("metadata.namespace"),
+								MatchCondition: ptr.To(`xr.spec.env == "production"`),
+							},
+							{
+								Type:           v1.PatchTypeFromCompositeFieldPath,
+								FromFieldPath:  ptr.To("spec.parameters.test"),
+								MatchCondition: ptr.To(`(xr.spec.env == "production") && (has(xr.spec.parameters.test))`),
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
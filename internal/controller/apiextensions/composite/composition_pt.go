@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,10 +51,13 @@ const (
 	errInline          = "cannot inline Composition patch sets"
 
 	errFmtApplyComposed              = "cannot apply composed resource %q"
+	errFmtGetExistingComposed        = "cannot determine whether composed resource %q exists"
+	errFmtOmitInitFields             = "cannot omit initialization-only fields of composed resource %q"
 	errFmtParseBase                  = "cannot parse base template of composed resource %q"
 	errFmtRenderFromCompositePatches = "cannot render FromComposite patches for composed resource %q"
 	errFmtRenderToCompositePatches   = "cannot render ToComposite patches for composed resource %q"
 	errFmtRenderMetadata             = "cannot render metadata for composed resource %q"
+	errFmtRenderNamespace            = "cannot render namespace for composed resource %q"
 	errFmtGenerateName               = "cannot generate a name for composed resource %q"
 	errFmtExtractDetails             = "cannot extract composite resource connection details from composed resource %q"
 	errFmtCheckReadiness             = "cannot check whether composed resource %q is ready"
@@ -80,6 +84,22 @@ func WithComposedNameGenerator(r names.NameGenerator) PTComposerOption {
 	}
 }
 
+// WithComposedNameRenderer configures how the PTComposer should render the
+// names of composed resources from name templates.
+func WithComposedNameRenderer(r ComposedNameRenderer) PTComposerOption {
+	return func(c *PTComposer) {
+		c.composed.ComposedNameRenderer = r
+	}
+}
+
+// WithComposedNamespacer configures how the PTComposer should render the
+// namespace of composed resources.
+func WithComposedNamespacer(n ComposedNamespacer) PTComposerOption {
+	return func(c *PTComposer) {
+		c.composed.ComposedNamespacer = n
+	}
+}
+
 // WithComposedReadinessChecker configures how a PatchAndTransformComposer
 // checks composed resource readiness.
 func WithComposedReadinessChecker(r ReadinessChecker) PTComposerOption {
@@ -88,6 +108,23 @@ func WithComposedReadinessChecker(r ReadinessChecker) PTComposerOption {
 	}
 }
 
+// WithComposedCapabilityChecker configures how a PatchAndTransformComposer
+// checks whether providers declare the capabilities composed resources require.
+func WithComposedCapabilityChecker(cc CapabilityChecker) PTComposerOption {
+	return func(c *PTComposer) {
+		c.composed.CapabilityChecker = cc
+	}
+}
+
+// WithComposedManagementPolicyDefaulter configures how a
+// PatchAndTransformComposer sets default management policies on composed
+// managed resources.
+func WithComposedManagementPolicyDefaulter(d ManagementPolicyDefaulter) PTComposerOption {
+	return func(c *PTComposer) {
+		c.composed.ManagementPolicyDefaulter = d
+	}
+}
+
 // WithComposedConnectionDetailsFetcher configures how a
 // PatchAndTransformComposer fetches composed resource connection details.
 func WithComposedConnectionDetailsFetcher(f managed.ConnectionDetailsFetcher) PTComposerOption {
@@ -105,11 +142,41 @@ func WithComposedConnectionDetailsExtractor(e ConnectionDetailsExtractor) PTComp
 	}
 }
 
+// WithReferencedKeyFetcher configures how a PatchAndTransformComposer fetches
+// the Secret and ConfigMap keys read by FromReferencedKey patches.
+func WithReferencedKeyFetcher(f ReferencedKeyFetcher) PTComposerOption {
+	return func(c *PTComposer) {
+		c.composed.ReferencedKeyFetcher = f
+	}
+}
+
+// WithComposedTracer configures the OpenTelemetry tracer the PTComposer should
+// use to trace composed resource applies.
+func WithComposedTracer(t trace.Tracer) PTComposerOption {
+	return func(c *PTComposer) {
+		c.tracer = t
+	}
+}
+
+// WithEnvironmentConfigWriter configures how a PatchAndTransformComposer
+// writes the EnvironmentConfig keys written by ToEnvironmentConfig patches.
+func WithEnvironmentConfigWriter(w EnvironmentConfigWriter) PTComposerOption {
+	return func(c *PTComposer) {
+		c.composed.EnvironmentConfigWriter = w
+	}
+}
+
 type composedResource struct {
 	names.NameGenerator
+	ComposedNameRenderer
+	ComposedNamespacer
 	managed.ConnectionDetailsFetcher
 	ConnectionDetailsExtractor
 	ReadinessChecker
+	ReferencedKeyFetcher
+	CapabilityChecker
+	ManagementPolicyDefaulter
+	EnvironmentConfigWriter
 }
 
 // A PTComposer composes resources using Patch and Transform (P&T) Composition.
@@ -121,6 +188,9 @@ type PTComposer struct {
 
 	composition CompositionTemplateAssociator
 	composed    composedResource
+
+	// Traces composed resource applies.
+	tracer trace.Tracer
 }
 
 // NewPTComposer returns a Composer that composes resources using Patch and
@@ -132,10 +202,17 @@ func NewPTComposer(kube client.Client, o ...PTComposerOption) *PTComposer {
 		composition: NewGarbageCollectingAssociator(kube),
 		composed: composedResource{
 			NameGenerator:              names.NewNameGenerator(kube),
+			ComposedNameRenderer:       NewAPIComposedNameRenderer(kube),
+			ComposedNamespacer:         ComposedNamespacerFn(noRenderNamespace),
 			ReadinessChecker:           ReadinessCheckerFn(IsReady),
 			ConnectionDetailsFetcher:   NewSecretConnectionDetailsFetcher(kube),
 			ConnectionDetailsExtractor: ConnectionDetailsExtractorFn(ExtractConnectionDetails),
+			ReferencedKeyFetcher:       NewAPIReferencedKeyFetcher(kube),
+			EnvironmentConfigWriter:    NewAPIEnvironmentConfigWriter(kube),
+			CapabilityChecker:          NewProviderCapabilityChecker(NewCachingProviderRevisionFetcher(NewAPIProviderRevisionFetcher(kube), DefaultProviderRevisionCacheTTL)),
+			ManagementPolicyDefaulter:  ManagementPolicyDefaulterFn(noDefaultManagementPolicies),
 		},
+		tracer: nopTracer(),
 	}
 
 	for _, fn := range o {
@@ -176,6 +253,11 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 
 	events := make([]TargetedEvent, 0)
 
+	// Warnings about composed resources that require capabilities their
+	// provider doesn't declare. The Reconciler only emits these when they
+	// first appear, since they're likely to persist.
+	var warnings []TargetedEvent
+
 	// We optimistically render all composed resources that we are able to with
 	// the expectation that any that we fail to render will subsequently have
 	// their error corrected by manual intervention or propagation of a required
@@ -183,6 +265,15 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 	// process.
 	refs := make([]corev1.ObjectReference, len(tas))
 	cds := make([]resource.Composed, len(tas))
+
+	// Names rendered from name templates mustn't collide with the names of
+	// the XR's other composed resources, including those that don't exist
+	// yet.
+	used := ComposedNames{}
+	for i := range tas {
+		used.Add(composed.New(composed.FromReference(tas[i].Reference)))
+	}
+
 	for i := range tas {
 		ta := tas[i]
 
@@ -211,7 +302,23 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			rendered = false
 		}
 
-		if err := RenderComposedResourceMetadata(r, xr, ResourceName(ptr.Deref(ta.Template.Name, ""))); err != nil {
+		if err := RenderFromReferencedKeyPatches(ctx, c.composed, r, xr, ta.Template.Patches); err != nil {
+			events = append(events, TargetedEvent{
+				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderReferencedKeyPatches, name)),
+				Target: CompositionTargetComposite,
+			})
+			rendered = false
+		}
+
+		if err := c.composed.RenderNamespace(r, xr); err != nil {
+			events = append(events, TargetedEvent{
+				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderNamespace, name)),
+				Target: CompositionTargetComposite,
+			})
+			rendered = false
+		}
+
+		if err := RenderComposedResourceMetadata(r, xr, ResourceName(ptr.Deref(ta.Template.Name, "")), ptr.Deref(req.Revision.Spec.ComposedOwnerReference, v1.ComposedOwnerReferenceController)); err != nil {
 			events = append(events, TargetedEvent{
 				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderMetadata, name)),
 				Target: CompositionTargetComposite,
@@ -219,6 +326,17 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			rendered = false
 		}
 
+		if r.GetName() == "" && ta.Template.NameTemplate != nil {
+			if err := c.composed.RenderName(ctx, r, xr, name, *ta.Template.NameTemplate, used); err != nil {
+				events = append(events, TargetedEvent{
+					Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderName, name)),
+					Target: CompositionTargetComposite,
+				})
+				rendered = false
+			}
+			used.Add(r)
+		}
+
 		if err := c.composed.GenerateName(ctx, r); err != nil {
 			events = append(events, TargetedEvent{
 				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtGenerateName, name)),
@@ -227,6 +345,14 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			rendered = false
 		}
 
+		// Providers that don't declare a capability may silently ignore the
+		// features that require it, so we warn about it. We still apply the
+		// composed resource, since the provider may support the features
+		// without declaring them.
+		if rendered {
+			warnings = append(warnings, checkCapabilities(ctx, c.composed, r, name)...)
+		}
+
 		// We record a reference even if we didn't render the resource because
 		// if it already exists we don't want to drop our reference to it (and
 		// thus not know about it next reconcile). If we're using anonymous
@@ -241,6 +367,22 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		}
 	}
 
+	// Set default management policies on any rendered composed managed
+	// resources that don't specify their own.
+	desired := make(ComposedResourceStates, len(tas))
+	for i := range tas {
+		if cds[i] == nil {
+			continue
+		}
+		name := ResourceName(ptr.Deref(tas[i].Template.Name, fmt.Sprintf("resource %d", i+1)))
+		desired[name] = ComposedResourceState{Resource: cds[i]}
+	}
+	mpe, err := c.composed.DefaultManagementPolicies(ctx, xr, desired)
+	if err != nil {
+		return CompositionResult{}, errors.Wrap(err, errDefaultCDPolicies)
+	}
+	events = append(events, mpe...)
+
 	// We persist references to our composed resources before we create
 	// them. This way we can render composed resources with
 	// non-deterministic names, and also potentially recover from any errors
@@ -265,9 +407,48 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			continue
 		}
 
-		o := []resource.ApplyOption{resource.MustBeControllableBy(xr.GetUID()), usage.RespectOwnerRefs()}
-		o = append(o, mergeOptions(filterPatches(t.Patches, patchTypesFromXR()...))...)
-		if err := c.client.Apply(ctx, cd, o...); err != nil {
+		// Create-only composed resources and initialization-only fields both
+		// depend on whether the composed resource already exists, so we get
+		// it at most once.
+		createOnly := ptr.Deref(t.UpdatePolicy, v1.ComposedUpdatePolicyAlways) == v1.ComposedUpdatePolicyCreateOnly
+		exists := false
+		if (createOnly || len(t.InitFieldPaths) > 0) && cd.GetName() != "" {
+			existing := composed.New(composed.FromReference(refs[i]))
+			err := c.client.Get(ctx, types.NamespacedName{Namespace: cd.GetNamespace(), Name: cd.GetName()}, existing)
+			if resource.IgnoreNotFound(err) != nil {
+				return CompositionResult{}, errors.Wrapf(err, errFmtGetExistingComposed, ptr.Deref(t.Name, fmt.Sprintf("%d", i+1)))
+			}
+			exists = err == nil
+
+			// We never update a create-only composed resource once it
+			// exists. Instead we observe it as it is. If it doesn't exist
+			// (anymore) we create it.
+			if exists && createOnly {
+				if err := MustBeComposableBy(xr.GetUID())(ctx, existing, cd); err != nil {
+					return CompositionResult{}, errors.Wrapf(err, errFmtApplyComposed, ptr.Deref(t.Name, fmt.Sprintf("%d", i+1)))
+				}
+				cds[i] = existing
+				continue
+			}
+		}
+
+		// Initialization-only fields are only set when we create the composed
+		// resource. If it already exists we omit them when we apply it, so
+		// that we don't overwrite their current values. This is true even if
+		// a patch targets the same field.
+		if exists && len(t.InitFieldPaths) > 0 {
+			if err := OmitInitFields(cd, t.InitFieldPaths); err != nil {
+				return CompositionResult{}, errors.Wrapf(err, errFmtOmitInitFields, ptr.Deref(t.Name, fmt.Sprintf("%d", i+1)))
+			}
+		}
+
+		o := []resource.ApplyOption{MustBeComposableBy(xr.GetUID()), usage.RespectOwnerRefs()}
+		o = append(o, mergeOptions(filterPatches(t.Patches, append(patchTypesFromXR(), v1.PatchTypeFromReferencedKey)...))...)
+		o = append(o, mergePolicies(t.MergePolicies)...)
+		actx, span := c.tracer.Start(ctx, spanApplyComposed, trace.WithAttributes(attrComposedName.String(ptr.Deref(t.Name, fmt.Sprintf("%d", i+1))), attrComposedKind.String(cd.GetObjectKind().GroupVersionKind().Kind)))
+		err := c.client.Apply(actx, cd, o...)
+		endSpan(span, err)
+		if err != nil {
 			if kerrors.IsInvalid(err) {
 				// We tried applying an invalid resource, we can't tell whether
 				// this means the resource will never be valid or it will if we
@@ -322,18 +503,32 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			return CompositionResult{}, errors.Wrapf(err, errFmtRenderToCompositePatches, name)
 		}
 
-		cdConnDetails, err := c.composed.FetchConnection(ctx, cd)
-		if err != nil {
-			return CompositionResult{}, errors.Wrap(err, errFetchDetails)
+		// Failures to write EnvironmentConfigs aren't terminal. They're
+		// usually conflicts with a value another composite resource wrote,
+		// which only a human can resolve.
+		if err := RenderToEnvironmentConfigPatches(ctx, c.composed, cd, xr, t.Patches); err != nil {
+			events = append(events, TargetedEvent{
+				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderToEnvConfigPatches, name)),
+				Target: CompositionTargetComposite,
+			})
 		}
 
-		extracted, err := c.composed.ExtractConnection(cd, cdConnDetails, ExtractConfigsFromComposedTemplate(&t)...)
-		if err != nil {
-			return CompositionResult{}, errors.Wrapf(err, errFmtExtractDetails, name)
-		}
+		// There's no need to fetch the connection details of a resource
+		// that doesn't publish them to the XR.
+		if ptr.Deref(t.PublishConnectionDetails, true) {
+			cdConnDetails, err := c.composed.FetchConnection(ctx, cd)
+			if err != nil {
+				return CompositionResult{}, errors.Wrap(err, errFetchDetails)
+			}
 
-		for key, val := range extracted {
-			xrConnDetails[key] = val
+			extracted, err := c.composed.ExtractConnection(cd, cdConnDetails, ExtractConfigsFromComposedTemplate(&t)...)
+			if err != nil {
+				return CompositionResult{}, errors.Wrapf(err, errFmtExtractDetails, name)
+			}
+
+			for key, val := range extracted {
+				xrConnDetails[key] = val
+			}
 		}
 
 		ready, err := c.composed.IsReady(ctx, cd, ReadinessChecksFromComposedTemplate(&t)...)
@@ -362,7 +557,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		return CompositionResult{}, errors.Wrap(err, errUpdate)
 	}
 
-	return CompositionResult{ConnectionDetails: xrConnDetails, Composed: resources, Events: events}, nil
+	return CompositionResult{ConnectionDetails: xrConnDetails, Composed: resources, Events: events, CapabilityWarnings: warnings}, nil
 }
 
 // toXRPatchesFromTAs selects patches defined in composed templates,
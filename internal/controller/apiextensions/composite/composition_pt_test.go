@@ -33,6 +33,7 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
@@ -49,12 +50,38 @@ func TestPTCompose(t *testing.T) {
 	details := managed.ConnectionDetails{"a": []byte("b")}
 	base := runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"ComposedResource"}`)}
 
+	// An XR whose composed resources don't have owner references.
+	noOwnerRef := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{ComposedOwnerReference: ptr.To(v1.ComposedOwnerReferenceNone)}}
+	coolXR := func() *composite.Unstructured {
+		xr := WithParentLabel()
+		xr.SetName("cool-xr")
+		xr.SetUID("cool-uid")
+		return xr
+	}
+	composedBy := func(uid string) func(obj client.Object) error {
+		return func(obj client.Object) error {
+			obj.SetLabels(map[string]string{xcrd.LabelKeyCompositeUID: uid})
+			return nil
+		}
+	}
+
+	// Composed resources should be applied with these management policies.
+	observeOnly := func(obj client.Object) error {
+		if obj.GetObjectKind().GroupVersionKind().Kind != "ComposedResource" {
+			return nil
+		}
+		got, _ := fieldpath.Pave(obj.(runtime.Unstructured).UnstructuredContent()).GetStringArray("spec.managementPolicies")
+		if diff := cmp.Diff([]string{"Observe"}, got); diff != "" {
+			t.Errorf("spec.managementPolicies: -want, +got:\n%s", diff)
+		}
+		return nil
+	}
+
 	type params struct {
 		kube client.Client
 		o    []PTComposerOption
 	}
 	type args struct {
-		ctx context.Context
 		xr  *composite.Unstructured
 		req CompositionRequest
 	}
@@ -399,6 +426,401 @@ func TestPTCompose(t *testing.T) {
 				},
 			},
 		},
+		"DefaultManagementPoliciesError": {
+			reason: "We should return any error encountered setting default management policies.",
+			params: params{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name: ptr.To("cool-resource"),
+								Base: base,
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedManagementPolicyDefaulter(ManagementPolicyDefaulterFn(func(_ context.Context, _ resource.Composite, _ ComposedResourceStates) ([]TargetedEvent, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDefaultCDPolicies),
+			},
+		},
+		"DefaultManagementPolicies": {
+			reason: "We should set default management policies on rendered composed resources before we apply them, and return any events about them.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+
+					// Apply uses Get, Create, and Patch.
+					MockGet:    test.NewMockGetFn(nil),
+					MockCreate: test.NewMockCreateFn(nil, observeOnly),
+					MockPatch:  test.NewMockPatchFn(nil, observeOnly),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name: ptr.To("cool-resource"),
+								Base: base,
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedManagementPolicyDefaulter(ManagementPolicyDefaulterFn(func(_ context.Context, _ resource.Composite, desired ComposedResourceStates) ([]TargetedEvent, error) {
+						cd := desired["cool-resource"].Resource.(runtime.Unstructured)
+						_ = fieldpath.Pave(cd.UnstructuredContent()).SetValue("spec.managementPolicies", []any{"Observe"})
+						return []TargetedEvent{{Event: event.Normal(reasonCompose, "defaulted"), Target: CompositionTargetComposite}}, nil
+					})),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(_ resource.Composed, _ managed.ConnectionDetails, _ ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						Ready:        true,
+						Synced:       true,
+					}},
+					ConnectionDetails: managed.ConnectionDetails{},
+					Events:            []TargetedEvent{{Event: event.Normal(reasonCompose, "defaulted"), Target: CompositionTargetComposite}},
+				},
+			},
+		},
+		"UnpublishedConnectionDetails": {
+			reason: "We shouldn't publish the connection details of resources that don't publish them to the XR.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+
+					// Apply uses Get, Create, and Patch.
+					MockGet:    test.NewMockGetFn(nil),
+					MockCreate: test.NewMockCreateFn(nil),
+					MockPatch:  test.NewMockPatchFn(nil),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name:                     ptr.To("cool-resource"),
+								Base:                     base,
+								PublishConnectionDetails: ptr.To(false),
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, errBoom
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(_ resource.Composed, _ managed.ConnectionDetails, _ ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						Ready:        true,
+						Synced:       true,
+					}},
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
+		"CreateOnlyExists": {
+			reason: "We should observe, but not update, a create-only composed resource that already exists.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetLabels(map[string]string{"observed": "true"})
+						return nil
+					}),
+
+					// Applying the composed resource would call Patch,
+					// because it exists. Only the XR should be patched.
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						if _, ok := obj.(*composite.Unstructured); !ok {
+							return errBoom
+						}
+						return nil
+					}),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name:         ptr.To("cool-resource"),
+								Base:         base,
+								UpdatePolicy: ptr.To(v1.ComposedUpdatePolicyCreateOnly),
+							},
+							Reference: corev1.ObjectReference{
+								APIVersion: "test.crossplane.io/v1",
+								Kind:       "ComposedResource",
+								Name:       "cool-resource-42",
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(_ resource.Composed, _ managed.ConnectionDetails, _ ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, o ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+						// We should check the readiness of the existing
+						// resource, not the one we rendered.
+						return o.GetLabels()["observed"] == "true", nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						Ready:        true,
+						Synced:       true,
+					}},
+					ConnectionDetails: details,
+				},
+			},
+		},
+		"CreateOnlyNotFound": {
+			reason: "We should create a create-only composed resource that doesn't exist.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name:         ptr.To("cool-resource"),
+								Base:         base,
+								UpdatePolicy: ptr.To(v1.ComposedUpdatePolicyCreateOnly),
+							},
+							Reference: corev1.ObjectReference{
+								APIVersion: "test.crossplane.io/v1",
+								Kind:       "ComposedResource",
+								Name:       "cool-resource-42",
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errBoom, "cannot create object"), errFmtApplyComposed, "cool-resource"),
+			},
+		},
+		"CreateOnlyGetError": {
+			reason: "We should return any error encountered while getting a create-only composed resource.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(errBoom),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name:         ptr.To("cool-resource"),
+								Base:         base,
+								UpdatePolicy: ptr.To(v1.ComposedUpdatePolicyCreateOnly),
+							},
+							Reference: corev1.ObjectReference{
+								APIVersion: "test.crossplane.io/v1",
+								Kind:       "ComposedResource",
+								Name:       "cool-resource-42",
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetExistingComposed, "cool-resource"),
+			},
+		},
+		"InitFieldPathsGetError": {
+			reason: "We should return any error encountered while determining whether a composed resource with initialization-only fields exists.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(errBoom),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name:           ptr.To("cool-resource"),
+								Base:           base,
+								InitFieldPaths: []string{"spec.size"},
+							},
+							Reference: corev1.ObjectReference{
+								APIVersion: "test.crossplane.io/v1",
+								Kind:       "ComposedResource",
+								Name:       "cool-resource-42",
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetExistingComposed, "cool-resource"),
+			},
+		},
+		"NoOwnerReferenceNameTemplate": {
+			reason: "We should use the rendered name of a composed resource that is in use by a resource the XR composed without an owner reference.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(nil, composedBy("cool-uid")),
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						// We patch the rendered composed resource, then the XR.
+						if obj.GetName() != "cool-xr-bucket" && obj.GetName() != "cool-xr" {
+							return errBoom
+						}
+						return nil
+					},
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name:         ptr.To("cool-resource"),
+								Base:         base,
+								NameTemplate: ptr.To("{{ .metadata.name }}-bucket"),
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr:  coolXR(),
+				req: CompositionRequest{Revision: noOwnerRef},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						Ready:        true,
+						Synced:       true,
+					}},
+				},
+			},
+		},
+		"NoOwnerReferenceComposedByOtherXR": {
+			reason: "We should not adopt a resource that another XR composed without an owner reference.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(nil, composedBy("other-uid")),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name: ptr.To("cool-resource"),
+								Base: base,
+							},
+							Reference: corev1.ObjectReference{
+								APIVersion: "test.crossplane.io/v1",
+								Kind:       "ComposedResource",
+								Name:       "cool-resource-42",
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedNameGenerator(names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil })),
+				},
+			},
+			args: args{
+				xr:  coolXR(),
+				req: CompositionRequest{Revision: noOwnerRef},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtComposedByOther, "cool-uid"), errFmtApplyComposed, "cool-resource"),
+			},
+		},
 		"PartialSuccess": {
 			reason: "We should return the resources we composed, and our derived connection details. We should return events for any resources we couldn't compose",
 			params: params{
@@ -482,7 +904,7 @@ func TestPTCompose(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			c := NewPTComposer(tc.params.kube, tc.params.o...)
-			res, err := c.Compose(tc.args.ctx, tc.args.xr, tc.args.req)
+			res, err := c.Compose(context.Background(), tc.args.xr, tc.args.req)
 
 			if diff := cmp.Diff(tc.want.res, res, cmpopts.EquateEmpty()); diff != "" {
 				t.Errorf("\n%s\nCompose(...): -want, +got:\n%s", tc.reason, diff)
@@ -16,9 +16,18 @@ specific language governing permissions and limitations under the License.
 package composite
 
 import (
+	"context"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
@@ -31,9 +40,14 @@ const (
 	errUnmarshalJSON      = "cannot unmarshal JSON data"
 	errMarshalProtoStruct = "cannot marshal protobuf Struct to JSON"
 	errSetControllerRef   = "cannot set controller reference"
+	errDetermineScope     = "cannot determine whether composed resource is namespaced"
+	errMissingObjectMeta  = "existing object is missing object metadata"
+	errSecretDataToXR     = "cannot patch the data of a composed resource that receives values read from a Secret to the composite resource"
 
 	errFmtKindChanged     = "cannot change the kind of a composed resource from %s to %s (possible composed resource template mismatch)"
 	errFmtNamePrefixLabel = "cannot find top-level composite resource name label %q in composite resource metadata"
+	errFmtOmitInitField   = "cannot omit initialization-only field path %q"
+	errFmtComposedByOther = "existing object is not composed by UID %q"
 
 	// TODO(negz): Include more detail such as field paths if they exist.
 	// Perhaps require each patch type to have a String() method to help
@@ -91,9 +105,14 @@ func RenderFromCompositePatches(cd resource.Composed, xr resource.Composite, p [
 
 // RenderToCompositePatches renders the supplied composite resource by applying
 // all patches that are _from_ the supplied composed resource. composed resource
-// and template.
+// and template. Patches that would copy the data of a composed resource that
+// receives values read from a Secret to the composite resource are rejected.
 func RenderToCompositePatches(xr resource.Composite, cd resource.Composed, p []v1.Patch) error {
+	secret := v1.ReceivesReferencedSecret(p)
 	for i := range p {
+		if secret && v1.ReadsSecretData(p[i]) {
+			return errors.Wrapf(errors.New(errSecretDataToXR), errFmtPatch, p[i].Type, i)
+		}
 		if err := Apply(p[i], xr, cd, patchTypesToXR()...); err != nil {
 			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
 		}
@@ -101,11 +120,31 @@ func RenderToCompositePatches(xr resource.Composite, cd resource.Composed, p []v
 	return nil
 }
 
+// OmitInitFields removes the supplied initialization-only field paths from the
+// supplied composed resource, so that applying it doesn't overwrite the fields
+// of a composed resource that already exists. Field paths that don't exist are
+// ignored.
+func OmitInitFields(cd resource.Composed, paths []string) error {
+	paved, err := fieldpath.PaveObject(cd)
+	if err != nil {
+		return err
+	}
+	for _, fp := range paths {
+		if err := paved.DeleteField(fp); err != nil {
+			return errors.Wrapf(err, errFmtOmitInitField, fp)
+		}
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(paved.UnstructuredContent(), cd)
+}
+
 // RenderComposedResourceMetadata derives composed resource metadata from the
-// supplied composite resource. It makes the composite resource the controller
-// of the composed resource. It should run toward the end of a render pipeline
-// to ensure that a Composition cannot influence the controller reference.
-func RenderComposedResourceMetadata(cd, xr resource.Object, n ResourceName) error {
+// supplied composite resource. Unless the supplied owner reference policy is
+// None it makes the composite resource the controller of the composed
+// resource. If it's None it labels the composed resource with the composite
+// resource's UID instead, so that the composite resource can still tell which
+// resources it composed. It should run toward the end of a render pipeline to
+// ensure that a Composition cannot influence the controller reference.
+func RenderComposedResourceMetadata(cd, xr resource.Object, n ResourceName, p v1.ComposedOwnerReferencePolicy) error {
 	// Fail early if the supplied composite resource is missing the name prefix
 	// label.
 	if xr.GetLabels()[xcrd.LabelKeyNamePrefixForComposed] == "" {
@@ -126,9 +165,108 @@ func RenderComposedResourceMetadata(cd, xr resource.Object, n ResourceName) erro
 		xcrd.LabelKeyClaimNamespace:        xr.GetLabels()[xcrd.LabelKeyClaimNamespace],
 	})
 
+	// A composed resource is nested one level deeper than the XR that
+	// composes it. We only propagate the depth if the XR's is tracked.
+	if IsCompositionDepthTracked(xr) {
+		meta.AddLabels(cd, map[string]string{xcrd.LabelKeyCompositionDepth: strconv.Itoa(GetCompositionDepth(xr) + 1)})
+	}
+
 	or := meta.AsController(meta.TypedReferenceTo(xr, xr.GetObjectKind().GroupVersionKind()))
+	switch p {
+	case v1.ComposedOwnerReferenceNone:
+		meta.AddLabels(cd, map[string]string{xcrd.LabelKeyCompositeUID: string(xr.GetUID())})
+		return nil
+	case v1.ComposedOwnerReferenceNonBlockingController:
+		or.BlockOwnerDeletion = ptr.To(false)
+	}
 	return errors.Wrap(meta.AddControllerReference(cd, or), errSetControllerRef)
 }
 
+// ComposerOf returns the UID of the composite resource that composed the
+// supplied resource. That's the UID of the resource's controller, or if it has
+// no controller the UID it's labelled with. It returns an empty UID if the
+// resource has neither.
+func ComposerOf(o metav1.Object) types.UID {
+	if c := metav1.GetControllerOf(o); c != nil {
+		return c.UID
+	}
+	return types.UID(o.GetLabels()[xcrd.LabelKeyCompositeUID])
+}
+
+// MustBeComposableBy requires that the current object either wasn't composed
+// by a composite resource, or was composed by the composite resource with the
+// supplied UID. Unlike resource.MustBeControllableBy it also protects composed
+// resources that don't have a controller reference.
+func MustBeComposableBy(u types.UID) resource.ApplyOption {
+	return func(_ context.Context, current, _ runtime.Object) error {
+		mo, ok := current.(metav1.Object)
+		if !ok {
+			return errors.New(errMissingObjectMeta)
+		}
+		if c := ComposerOf(mo); c != "" && c != u {
+			return errors.Errorf(errFmtComposedByOther, u)
+		}
+		return nil
+	}
+}
+
+// A ComposedNamespacer renders the namespace of a composed resource.
+type ComposedNamespacer interface {
+	// RenderNamespace renders the namespace of the supplied composed
+	// resource, which is composed by the supplied composite resource.
+	RenderNamespace(cd, xr resource.Object) error
+}
+
+// A ComposedNamespacerFn renders the namespace of a composed resource.
+type ComposedNamespacerFn func(cd, xr resource.Object) error
+
+// RenderNamespace renders the namespace of the supplied composed resource.
+func (fn ComposedNamespacerFn) RenderNamespace(cd, xr resource.Object) error {
+	return fn(cd, xr)
+}
+
+// noRenderNamespace doesn't render the namespace of a composed resource.
+func noRenderNamespace(_, _ resource.Object) error { return nil }
+
+// A ClaimNamespacer renders the namespace of a composed resource using the
+// namespace of the claim its composite resource is bound to.
+type ClaimNamespacer struct {
+	client client.Client
+}
+
+// NewClaimNamespacer returns a ComposedNamespacer that defaults the namespace
+// of namespaced composed resources to the namespace of the claim.
+func NewClaimNamespacer(c client.Client) *ClaimNamespacer {
+	return &ClaimNamespacer{client: c}
+}
+
+// RenderNamespace renders the namespace of the supplied composed resource. A
+// namespaced composed resource that doesn't specify a namespace is created in
+// the namespace of the claim, if any. A cluster scoped composed resource never
+// has a namespace, so any namespace it specifies is removed.
+func (n *ClaimNamespacer) RenderNamespace(cd, xr resource.Object) error {
+	claimNamespace := xr.GetLabels()[xcrd.LabelKeyClaimNamespace]
+
+	// There's no namespace to default, or to remove.
+	if cd.GetNamespace() == "" && claimNamespace == "" {
+		return nil
+	}
+
+	namespaced, err := n.client.IsObjectNamespaced(cd)
+	if err != nil {
+		return errors.Wrap(err, errDetermineScope)
+	}
+
+	if !namespaced {
+		cd.SetNamespace("")
+		return nil
+	}
+
+	if cd.GetNamespace() == "" {
+		cd.SetNamespace(claimNamespace)
+	}
+	return nil
+}
+
 // TODO(negz): It's simple enough that we should just inline it into the
 // PTComposer, which is now the only consumer.
@@ -23,15 +23,21 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -154,6 +160,46 @@ func TestRenderFromJSON(t *testing.T) {
 				}},
 			},
 		},
+		"TemplateNamespace": {
+			reason: "The namespace specified by the base template should not be used if the composed resource doesn't have one. Composed resource namespaces are rendered separately.",
+			args: args{
+				o:    composed.New(),
+				data: []byte(`{"apiVersion": "example.org/v1", "kind": "Potato", "metadata": {"namespace": "template"}}`),
+			},
+			want: want{
+				o: &composed.Unstructured{Unstructured: unstructured.Unstructured{
+					Object: map[string]any{
+						"apiVersion": "example.org/v1",
+						"kind":       "Potato",
+						"metadata":   map[string]any{},
+					},
+				}},
+			},
+		},
+		"ExistingNamespace": {
+			reason: "The namespace of an existing composed resource should not be changed by the base template",
+			args: args{
+				o: composed.New(composed.FromReference(corev1.ObjectReference{
+					APIVersion: "example.org/v1",
+					Kind:       "Potato",
+					Name:       "ola-superrandom",
+					Namespace:  "existing",
+				})),
+				data: []byte(`{"apiVersion": "example.org/v1", "kind": "Potato", "metadata": {"namespace": "template"}}`),
+			},
+			want: want{
+				o: &composed.Unstructured{Unstructured: unstructured.Unstructured{
+					Object: map[string]any{
+						"apiVersion": "example.org/v1",
+						"kind":       "Potato",
+						"metadata": map[string]any{
+							"name":      "ola-superrandom",
+							"namespace": "existing",
+						},
+					},
+				}},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -168,6 +214,151 @@ func TestRenderFromJSON(t *testing.T) {
 	}
 }
 
+func TestOmitInitFields(t *testing.T) {
+	cd := func(spec map[string]any) *composed.Unstructured {
+		return &composed.Unstructured{Unstructured: unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "Potato",
+				"spec":       spec,
+			},
+		}}
+	}
+
+	type args struct {
+		cd    *composed.Unstructured
+		paths []string
+	}
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"OmitFields": {
+			reason: "We should remove the supplied field paths, ignoring any that don't exist.",
+			args: args{
+				cd:    cd(map[string]any{"size": "large", "color": "purple"}),
+				paths: []string{"spec.size", "spec.weight"},
+			},
+			want: want{
+				cd: cd(map[string]any{"color": "purple"}),
+			},
+		},
+		"InvalidFieldPath": {
+			reason: "We should return an error if a field path is invalid.",
+			args: args{
+				cd:    cd(map[string]any{"size": "large"}),
+				paths: []string{"spec[size"},
+			},
+			want: want{
+				cd:  cd(map[string]any{"size": "large"}),
+				err: errors.Wrapf(errors.Wrap(errors.New("unterminated '[' at position 4"), "cannot parse path \"spec[size\""), errFmtOmitInitField, "spec[size"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := OmitInitFields(tc.args.cd, tc.args.paths)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nOmitInitFields(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+				t.Errorf("\n%s\nOmitInitFields(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderToCompositePatches(t *testing.T) {
+	cd := func() *composed.Unstructured {
+		return &composed.Unstructured{Unstructured: unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata":   map[string]any{"name": "cool-secret"},
+				"data":       map[string]any{"password": "aHVudGVyMg=="},
+			},
+		}}
+	}
+	secretPatch := v1.Patch{
+		Type:          v1.PatchTypeFromReferencedKey,
+		ReferencedKey: &v1.ReferencedKey{Kind: v1.ReferencedKeyKindSecret, NameFieldPath: "spec.ref.name", Key: "password"},
+		ToFieldPath:   ptr.To("data.password"),
+	}
+	toXR := v1.Patch{
+		Type:          v1.PatchTypeToCompositeFieldPath,
+		FromFieldPath: ptr.To("data.password"),
+		ToFieldPath:   ptr.To("status.password"),
+	}
+	combineToXR := v1.Patch{
+		Type: v1.PatchTypeCombineToComposite,
+		Combine: &v1.Combine{
+			Variables: []v1.CombineVariable{{FromFieldPath: "metadata.name"}, {FromFieldPath: "data.password"}},
+			Strategy:  v1.CombineStrategyString,
+			String:    &v1.StringCombine{Format: "%s-%s"},
+		},
+		ToFieldPath: ptr.To("status.password"),
+	}
+
+	type args struct {
+		p []v1.Patch
+	}
+	type want struct {
+		password any
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoReferencedSecret": {
+			reason: "We should patch the data of a composed resource that doesn't receive values read from a Secret to the composite resource.",
+			args: args{
+				p: []v1.Patch{toXR},
+			},
+			want: want{
+				password: "aHVudGVyMg==",
+			},
+		},
+		"ToCompositeFieldPathFromSecretData": {
+			reason: "We should refuse to patch the data of a composed resource that receives values read from a Secret to the composite resource.",
+			args: args{
+				p: []v1.Patch{secretPatch, toXR},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New(errSecretDataToXR), errFmtPatch, v1.PatchTypeToCompositeFieldPath, 1),
+			},
+		},
+		"CombineToCompositeFromSecretData": {
+			reason: "We should refuse to combine the data of a composed resource that receives values read from a Secret into the composite resource.",
+			args: args{
+				p: []v1.Patch{secretPatch, combineToXR},
+			},
+			want: want{
+				err: errors.Wrapf(errors.New(errSecretDataToXR), errFmtPatch, v1.PatchTypeCombineToComposite, 1),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			xr := composite.New(composite.WithGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XSecret"}))
+			err := RenderToCompositePatches(xr, cd(), tc.args.p)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRenderToCompositePatches(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			got, _ := fieldpath.Pave(xr.Object).GetValue("status.password")
+			if diff := cmp.Diff(tc.want.password, got); diff != "" {
+				t.Errorf("\n%s\nRenderToCompositePatches(...): -want password, +got password:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestRenderComposedResourceMetadata(t *testing.T) {
 	controlled := &fake.Composed{
 		ObjectMeta: metav1.ObjectMeta{
@@ -183,6 +374,7 @@ func TestRenderComposedResourceMetadata(t *testing.T) {
 		xr resource.Composite
 		cd resource.Composed
 		rn ResourceName
+		p  v1.ComposedOwnerReferencePolicy
 	}
 	type want struct {
 		cd  resource.Composed
@@ -308,10 +500,114 @@ func TestRenderComposedResourceMetadata(t *testing.T) {
 				},
 			},
 		},
+		"CompositionDepth": {
+			reason: "We should label the composed resource one level deeper than the XR if the XR's composition depth is tracked",
+			args: args{
+				xr: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cool-xr",
+						UID:  "somewhat-random",
+						Labels: map[string]string{
+							xcrd.LabelKeyNamePrefixForComposed: "prefix",
+							xcrd.LabelKeyClaimName:             "name",
+							xcrd.LabelKeyClaimNamespace:        "namespace",
+							xcrd.LabelKeyCompositionDepth:      "1",
+						},
+					},
+				},
+				cd: &fake.Composed{},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						GenerateName: "prefix-",
+						OwnerReferences: []metav1.OwnerReference{{
+							Controller:         ptr.To(true),
+							BlockOwnerDeletion: ptr.To(true),
+							UID:                "somewhat-random",
+							Name:               "cool-xr",
+						}},
+						Labels: map[string]string{
+							xcrd.LabelKeyNamePrefixForComposed: "prefix",
+							xcrd.LabelKeyClaimName:             "name",
+							xcrd.LabelKeyClaimNamespace:        "namespace",
+							xcrd.LabelKeyCompositionDepth:      "2",
+						},
+					},
+				},
+			},
+		},
+		"NonBlockingControllerReference": {
+			reason: "We should add a controller reference that doesn't block owner deletion if the policy is NonBlockingController",
+			args: args{
+				xr: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cool-xr",
+						UID:  "somewhat-random",
+						Labels: map[string]string{
+							xcrd.LabelKeyNamePrefixForComposed: "prefix",
+							xcrd.LabelKeyClaimName:             "name",
+							xcrd.LabelKeyClaimNamespace:        "namespace",
+						},
+					},
+				},
+				cd: &fake.Composed{},
+				p:  v1.ComposedOwnerReferenceNonBlockingController,
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						GenerateName: "prefix-",
+						OwnerReferences: []metav1.OwnerReference{{
+							Controller:         ptr.To(true),
+							BlockOwnerDeletion: ptr.To(false),
+							UID:                "somewhat-random",
+							Name:               "cool-xr",
+						}},
+						Labels: map[string]string{
+							xcrd.LabelKeyNamePrefixForComposed: "prefix",
+							xcrd.LabelKeyClaimName:             "name",
+							xcrd.LabelKeyClaimNamespace:        "namespace",
+						},
+					},
+				},
+			},
+		},
+		"NoOwnerReference": {
+			reason: "We should label the composed resource with the XR's UID instead of adding an owner reference if the policy is None",
+			args: args{
+				xr: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cool-xr",
+						UID:  "somewhat-random",
+						Labels: map[string]string{
+							xcrd.LabelKeyNamePrefixForComposed: "prefix",
+							xcrd.LabelKeyClaimName:             "name",
+							xcrd.LabelKeyClaimNamespace:        "namespace",
+						},
+					},
+				},
+				cd: &fake.Composed{},
+				p:  v1.ComposedOwnerReferenceNone,
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						GenerateName: "prefix-",
+						Labels: map[string]string{
+							xcrd.LabelKeyNamePrefixForComposed: "prefix",
+							xcrd.LabelKeyClaimName:             "name",
+							xcrd.LabelKeyClaimNamespace:        "namespace",
+							xcrd.LabelKeyCompositeUID:          "somewhat-random",
+						},
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			err := RenderComposedResourceMetadata(tc.args.cd, tc.args.xr, tc.args.rn)
+			err := RenderComposedResourceMetadata(tc.args.cd, tc.args.xr, tc.args.rn, tc.args.p)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nRenderComposedResourceMetadata(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
@@ -321,3 +617,120 @@ func TestRenderComposedResourceMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestClaimNamespacer(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	claimed := &fake.Composite{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				xcrd.LabelKeyClaimNamespace: "claim",
+			},
+		},
+	}
+
+	type params struct {
+		kube client.Client
+	}
+	type args struct {
+		cd resource.Object
+		xr resource.Object
+	}
+	type want struct {
+		cd  resource.Object
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		params params
+		args   args
+		want   want
+	}{
+		"NothingToDo": {
+			reason: "We shouldn't need to determine the scope of a composed resource with no namespace when there's no claim.",
+			params: params{
+				kube: &test.MockClient{},
+			},
+			args: args{
+				cd: &fake.Composed{},
+				xr: &fake.Composite{},
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+		"DetermineScopeError": {
+			reason: "We should return any error encountered determining the scope of the composed resource.",
+			params: params{
+				kube: &test.MockClient{
+					MockIsObjectNamespaced: func(_ runtime.Object) (bool, error) { return false, errBoom },
+				},
+			},
+			args: args{
+				cd: &fake.Composed{},
+				xr: claimed,
+			},
+			want: want{
+				cd:  &fake.Composed{},
+				err: errors.Wrap(errBoom, errDetermineScope),
+			},
+		},
+		"DefaultToClaimNamespace": {
+			reason: "A namespaced composed resource with no namespace should use the claim's namespace.",
+			params: params{
+				kube: &test.MockClient{
+					MockIsObjectNamespaced: func(_ runtime.Object) (bool, error) { return true, nil },
+				},
+			},
+			args: args{
+				cd: &fake.Composed{},
+				xr: claimed,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Namespace: "claim"}},
+			},
+		},
+		"KeepExplicitNamespace": {
+			reason: "A namespaced composed resource that specifies a namespace should keep it.",
+			params: params{
+				kube: &test.MockClient{
+					MockIsObjectNamespaced: func(_ runtime.Object) (bool, error) { return true, nil },
+				},
+			},
+			args: args{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Namespace: "explicit"}},
+				xr: claimed,
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Namespace: "explicit"}},
+			},
+		},
+		"ClusterScoped": {
+			reason: "A cluster scoped composed resource should never have a namespace.",
+			params: params{
+				kube: &test.MockClient{
+					MockIsObjectNamespaced: func(_ runtime.Object) (bool, error) { return false, nil },
+				},
+			},
+			args: args{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Namespace: "explicit"}},
+				xr: claimed,
+			},
+			want: want{
+				cd: &fake.Composed{},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			n := NewClaimNamespacer(tc.params.kube)
+			err := n.RenderNamespace(tc.args.cd, tc.args.xr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRenderNamespace(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+				t.Errorf("\n%s\nRenderNamespace(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -445,7 +446,14 @@ func GetConversionFunc(t *v1.ConvertTransform, from v1.TransformIOType) (func(an
 			return input, nil
 		}, nil
 	}
-	f, ok := conversions[conversionPair{from: from, to: to, format: t.GetFormat()}]
+	pair := conversionPair{from: from, to: to, format: t.GetFormat()}
+	if f, ok := unitConversions[pair]; ok {
+		unit := t.GetUnit()
+		return func(input any) (any, error) {
+			return f(input, unit)
+		}, nil
+	}
+	f, ok := conversions[pair]
 	if !ok {
 		return nil, errors.Errorf(v1.ErrFmtConvertFormatPairNotSupported, originalFrom, to, t.GetFormat())
 	}
@@ -477,17 +485,6 @@ var conversions = map[conversionPair]func(any) (any, error){ //nolint:gochecknog
 		}
 		return strconv.ParseFloat(s, 64)
 	},
-	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeFloat64, format: v1.ConvertTransformFormatQuantity}: func(i any) (any, error) {
-		s, ok := i.(string)
-		if !ok {
-			return nil, errors.New("not a string")
-		}
-		q, err := resource.ParseQuantity(s)
-		if err != nil {
-			return nil, err
-		}
-		return q.AsApproximateFloat64(), nil
-	},
 
 	{from: v1.TransformIOTypeInt64, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatNone}: func(i any) (any, error) {
 		i64, ok := i.(int64)
@@ -577,3 +574,111 @@ var conversions = map[conversionPair]func(any) (any, error){ //nolint:gochecknog
 		return o, json.Unmarshal([]byte(s), &o)
 	},
 }
+
+// unitConversions are conversions that convert a number to or from a unit.
+// They're supplied the transform's unit.
+var unitConversions = map[conversionPair]func(i any, unit string) (any, error){ //nolint:gochecknoglobals // We treat this map as a constant.
+	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeFloat64, format: v1.ConvertTransformFormatQuantity}: func(i any, unit string) (any, error) {
+		s, ok := i.(string)
+		if !ok {
+			return nil, errors.New("not a string")
+		}
+		q, err := resource.ParseQuantity(s)
+		if err != nil {
+			return nil, err
+		}
+		u, err := resource.ParseQuantity("1" + unit)
+		if err != nil {
+			return nil, err
+		}
+		return q.AsApproximateFloat64() / u.AsApproximateFloat64(), nil
+	},
+	{from: v1.TransformIOTypeInt64, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatQuantity}: func(i any, unit string) (any, error) {
+		i64, ok := i.(int64)
+		if !ok {
+			return nil, errors.New("not an int64")
+		}
+		return formatQuantity(strconv.FormatInt(i64, 10), unit)
+	},
+	{from: v1.TransformIOTypeFloat64, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatQuantity}: func(i any, unit string) (any, error) {
+		f64, ok := i.(float64)
+		if !ok {
+			return nil, errors.New("not a float64")
+		}
+		return formatQuantity(strconv.FormatFloat(f64, 'f', -1, 64), unit)
+	},
+
+	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeFloat64, format: v1.ConvertTransformFormatDuration}: func(i any, unit string) (any, error) {
+		s, ok := i.(string)
+		if !ok {
+			return nil, errors.New("not a string")
+		}
+		d, u, err := parseDuration(s, unit)
+		if err != nil {
+			return nil, err
+		}
+		return float64(d) / float64(u), nil
+	},
+	{from: v1.TransformIOTypeString, to: v1.TransformIOTypeInt64, format: v1.ConvertTransformFormatDuration}: func(i any, unit string) (any, error) {
+		s, ok := i.(string)
+		if !ok {
+			return nil, errors.New("not a string")
+		}
+		d, u, err := parseDuration(s, unit)
+		if err != nil {
+			return nil, err
+		}
+		if d%u != 0 {
+			return nil, errors.Errorf("duration %s is not a whole number of %s", d, unit)
+		}
+		return int64(d / u), nil
+	},
+	{from: v1.TransformIOTypeInt64, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatDuration}: func(i any, unit string) (any, error) {
+		i64, ok := i.(int64)
+		if !ok {
+			return nil, errors.New("not an int64")
+		}
+		return formatDuration(strconv.FormatInt(i64, 10), unit)
+	},
+	{from: v1.TransformIOTypeFloat64, to: v1.TransformIOTypeString, format: v1.ConvertTransformFormatDuration}: func(i any, unit string) (any, error) {
+		f64, ok := i.(float64)
+		if !ok {
+			return nil, errors.New("not a float64")
+		}
+		return formatDuration(strconv.FormatFloat(f64, 'f', -1, 64), unit)
+	},
+}
+
+// formatQuantity formats the supplied number of the supplied unit as a
+// canonical K8s quantity, e.g. 1.5 of unit Gi is formatted as 1536Mi.
+func formatQuantity(number, unit string) (string, error) {
+	q, err := resource.ParseQuantity(number + unit)
+	if err != nil {
+		return "", err
+	}
+	return q.String(), nil
+}
+
+// formatDuration formats the supplied number of the supplied unit as a
+// duration, e.g. 90 of unit s is formatted as 1m30s.
+func formatDuration(number, unit string) (string, error) {
+	d, err := time.ParseDuration(number + unit)
+	if err != nil {
+		return "", err
+	}
+	return d.String(), nil
+}
+
+// parseDuration parses the supplied duration, and the duration of one of the
+// supplied unit.
+func parseDuration(s, unit string) (time.Duration, time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	u, err := time.ParseDuration("1" + unit)
+	if err != nil {
+		return 0, 0, err
+	}
+	return d, u, nil
+}
@@ -1081,6 +1081,7 @@ func TestConvertResolve(t *testing.T) {
 	type args struct {
 		to     v1.TransformIOType
 		format *v1.ConvertTransformFormat
+		unit   *string
 		i      any
 	}
 	type want struct {
@@ -1183,12 +1184,149 @@ func TestConvertResolve(t *testing.T) {
 		},
 		"ConversionPairFormatNotSupported": {
 			args: args{
-				i:      100,
+				i:      true,
 				to:     v1.TransformIOTypeString,
 				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatQuantity))),
 			},
 			want: want{
-				err: errors.Errorf(errFmtConvertFormatPairNotSupported, "int", "string", string(v1.ConvertTransformFormatQuantity)),
+				err: errors.Errorf(errFmtConvertFormatPairNotSupported, "bool", "string", string(v1.ConvertTransformFormatQuantity)),
+			},
+		},
+		"StringToQuantityFloat64WithUnit": {
+			args: args{
+				i:      "1536Mi",
+				to:     v1.TransformIOTypeFloat64,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatQuantity))),
+				unit:   ptr.To("Gi"),
+			},
+			want: want{
+				o: 1.5,
+			},
+		},
+		"Int64ToQuantityString": {
+			args: args{
+				i:      int64(10),
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatQuantity))),
+				unit:   ptr.To("Gi"),
+			},
+			want: want{
+				o: "10Gi",
+			},
+		},
+		"Float64ToQuantityString": {
+			args: args{
+				i:      1.5,
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatQuantity))),
+				unit:   ptr.To("Gi"),
+			},
+			want: want{
+				o: "1536Mi",
+			},
+		},
+		"Float64ToQuantityStringNoUnit": {
+			args: args{
+				i:      0.5,
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatQuantity))),
+			},
+			want: want{
+				o: "500m",
+			},
+		},
+		"InvalidQuantityUnit": {
+			args: args{
+				i:      int64(10),
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatQuantity))),
+				unit:   ptr.To("Gigs"),
+			},
+			want: want{
+				err: &field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "unit",
+					BadValue: "Gigs",
+					Detail:   "invalid quantity unit",
+				},
+			},
+		},
+		"Int64ToDurationString": {
+			args: args{
+				i:      int64(90),
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatDuration))),
+			},
+			want: want{
+				o: "1m30s",
+			},
+		},
+		"Float64ToDurationStringWithUnit": {
+			args: args{
+				i:      1.5,
+				to:     v1.TransformIOTypeString,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatDuration))),
+				unit:   ptr.To("h"),
+			},
+			want: want{
+				o: "1h30m0s",
+			},
+		},
+		"DurationStringToInt64": {
+			args: args{
+				i:      "1m30s",
+				to:     v1.TransformIOTypeInt64,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatDuration))),
+			},
+			want: want{
+				o: int64(90),
+			},
+		},
+		"DurationStringToInt64NotWhole": {
+			args: args{
+				i:      "1m30s",
+				to:     v1.TransformIOTypeInt64,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatDuration))),
+				unit:   ptr.To("m"),
+			},
+			want: want{
+				err: errors.Wrap(errors.New("duration 1m30s is not a whole number of m"), "cannot convert value 1m30s"),
+			},
+		},
+		"DurationStringToFloat64": {
+			args: args{
+				i:      "1m30s",
+				to:     v1.TransformIOTypeFloat64,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatDuration))),
+				unit:   ptr.To("m"),
+			},
+			want: want{
+				o: 1.5,
+			},
+		},
+		"InvalidDurationString": {
+			args: args{
+				i:      "soon",
+				to:     v1.TransformIOTypeFloat64,
+				format: (*v1.ConvertTransformFormat)(ptr.To(string(v1.ConvertTransformFormatDuration))),
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`time: invalid duration "soon"`), "cannot convert value soon"),
+			},
+		},
+		"UnitWithoutFormat": {
+			args: args{
+				i:    int64(10),
+				to:   v1.TransformIOTypeString,
+				unit: ptr.To("Gi"),
+			},
+			want: want{
+				err: &field.Error{
+					Type:     field.ErrorTypeInvalid,
+					Field:    "unit",
+					BadValue: "Gi",
+					Detail:   "unit is only supported with the quantity and duration formats",
+				},
 			},
 		},
 		"ConversionPairNotSupported": {
@@ -1217,7 +1355,7 @@ func TestConvertResolve(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			tr := v1.ConvertTransform{ToType: tc.args.to, Format: tc.format}
+			tr := v1.ConvertTransform{ToType: tc.args.to, Format: tc.format, Unit: tc.unit}
 			got, err := ResolveConvert(tr, tc.i)
 
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
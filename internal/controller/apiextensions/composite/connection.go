@@ -42,6 +42,10 @@ const (
 	errFmtConnDetailKey  = "connection detail of type %q key is not set"
 	errFmtConnDetailVal  = "connection detail of type %q value is not set"
 	errFmtConnDetailPath = "connection detail of type %q fromFieldPath is not set"
+
+	errFmtConnDetailTransform = "cannot apply transform at index %d to connection detail %q"
+
+	errFmtCompositeConnDetail = "cannot read composite connection detail %q from field path %q"
 )
 
 // A ConnectionDetailsFetcherFn fetches the connection details of the supplied
@@ -202,12 +206,13 @@ func ExtractConnectionDetails(cd resource.Composed, data managed.ConnectionDetai
 		if cfg.Name == "" {
 			return nil, errors.Errorf(errConnDetailName)
 		}
+		var v []byte
 		switch tp := cfg.Type; tp {
 		case ConnectionDetailTypeFromValue:
 			if cfg.Value == nil {
 				return nil, errors.Errorf(errFmtConnDetailVal, tp)
 			}
-			out[cfg.Name] = []byte(*cfg.Value)
+			v = []byte(*cfg.Value)
 		case ConnectionDetailTypeFromConnectionSecretKey:
 			if cfg.FromConnectionSecretKey == nil {
 				return nil, errors.Errorf(errFmtConnDetailKey, tp)
@@ -217,22 +222,76 @@ func ExtractConnectionDetails(cd resource.Composed, data managed.ConnectionDetai
 				// key will still be written at some point in the future.
 				continue
 			}
-			out[cfg.Name] = data[*cfg.FromConnectionSecretKey]
+			v = data[*cfg.FromConnectionSecretKey]
 		case ConnectionDetailTypeFromFieldPath:
 			if cfg.FromFieldPath == nil {
 				return nil, errors.Errorf(errFmtConnDetailPath, tp)
 			}
-			// Note we're checking that the error _is_ nil. If we hit an error
-			// we silently avoid including this connection secret. It's possible
-			// the path will start existing with a valid value in future.
-			if b, err := fromFieldPath(cd, *cfg.FromFieldPath); err == nil {
-				out[cfg.Name] = b
+			// If we hit an error we silently avoid including this connection
+			// secret. It's possible the path will start existing with a valid
+			// value in future.
+			b, err := fromFieldPath(cd, *cfg.FromFieldPath)
+			if err != nil {
+				continue
 			}
+			v = b
+		default:
+			continue
+		}
+		v, err := transformConnectionDetail(cfg.Name, v, cfg.Transforms)
+		if err != nil {
+			return nil, err
+		}
+		out[cfg.Name] = v
+	}
+	return out, nil
+}
+
+// ExtractCompositeConnectionDetails extracts connection details from the
+// supplied composite resource. A connection detail whose field path doesn't
+// exist is omitted, unless its policy requires the field path to exist.
+func ExtractCompositeConnectionDetails(xr resource.Composite, cds []v1.CompositeConnectionDetail) (managed.ConnectionDetails, error) {
+	out := map[string][]byte{}
+	for _, cd := range cds {
+		v, err := fromFieldPath(xr, cd.FromFieldPath)
+		if fieldpath.IsNotFound(err) && cd.GetFromFieldPathPolicy() != v1.FromFieldPathPolicyRequired {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtCompositeConnDetail, cd.Name, cd.FromFieldPath)
 		}
+		out[cd.Name] = v
 	}
 	return out, nil
 }
 
+// transformConnectionDetail applies the supplied transforms to the value of a
+// connection detail. The value is passed to the first transform as a string.
+// If the last transform doesn't output a string its output is returned as JSON.
+// Transform errors often include their input, so they're redacted to avoid
+// leaking the secret value of the connection detail.
+func transformConnectionDetail(name string, v []byte, ts []v1.Transform) ([]byte, error) {
+	if len(ts) == 0 {
+		return v, nil
+	}
+	var in any = string(v)
+	for i, t := range ts {
+		out, err := Resolve(t, in)
+		if err != nil {
+			return nil, errors.Errorf(errFmtConnDetailTransform, i, name)
+		}
+		in = out
+	}
+	if s, ok := in.(string); ok {
+		return []byte(s), nil
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		return nil, errors.Errorf(errFmtConnDetailTransform, len(ts)-1, name)
+	}
+	return b, nil
+}
+
 // A ConnectionDetailType is a type of connection detail.
 type ConnectionDetailType string
 
@@ -269,6 +328,10 @@ type ConnectionDetailExtractConfig struct {
 	// an explicit value may be set to inject a fixed, non-sensitive connection
 	// secret values, for example a well-known port.
 	Value *string
+
+	// Transforms are applied to the connection detail's value, in order,
+	// before it's propagated to the connection secret.
+	Transforms []v1.Transform
 }
 
 // ExtractConfigsFromComposedTemplate builds extract configs for the supplied
@@ -284,6 +347,7 @@ func ExtractConfigsFromComposedTemplate(t *v1.ComposedTemplate) []ConnectionDeta
 			Value:                   t.ConnectionDetails[i].Value,
 			FromConnectionSecretKey: t.ConnectionDetails[i].FromConnectionSecretKey,
 			FromFieldPath:           t.ConnectionDetails[i].FromFieldPath,
+			Transforms:              t.ConnectionDetails[i].Transforms,
 		}
 
 		if t.ConnectionDetails[i].Name != nil {
@@ -18,11 +18,13 @@ package composite
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -31,9 +33,11 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -245,6 +249,13 @@ func TestConnectionDetailsFetcherChain(t *testing.T) {
 
 func TestExtractConnectionDetails(t *testing.T) {
 	// errBoom := errors.New("boom")
+	fromBase64 := v1.Transform{
+		Type: v1.TransformTypeString,
+		String: &v1.StringTransform{
+			Type:    v1.StringTransformTypeConvert,
+			Convert: ptr.To(v1.StringConversionTypeFromBase64),
+		},
+	}
 
 	type args struct {
 		cd   resource.Composed
@@ -316,6 +327,65 @@ func TestExtractConnectionDetails(t *testing.T) {
 				err: errors.Errorf(errFmtConnDetailPath, v1.ConnectionDetailTypeFromFieldPath),
 			},
 		},
+		"TransformedValues": {
+			reason: "We should apply transforms to connection detail values before returning them.",
+			args: args{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				data: managed.ConnectionDetails{
+					"encoded": []byte("aHVudGVyMg=="),
+				},
+				cfg: []ConnectionDetailExtractConfig{
+					{
+						Type:                    ConnectionDetailTypeFromConnectionSecretKey,
+						Name:                    "password",
+						FromConnectionSecretKey: ptr.To("encoded"),
+						Transforms:              []v1.Transform{fromBase64},
+					},
+					{
+						Type:       ConnectionDetailTypeFromValue,
+						Name:       "endpoint",
+						Value:      ptr.To("example.org"),
+						Transforms: []v1.Transform{{Type: v1.TransformTypeString, String: &v1.StringTransform{Type: v1.StringTransformTypeFormat, Format: ptr.To("https://%s")}}},
+					},
+					{
+						Type:          ConnectionDetailTypeFromFieldPath,
+						Name:          "name",
+						FromFieldPath: ptr.To("objectMeta.name"),
+						Transforms:    []v1.Transform{{Type: v1.TransformTypeMap, Map: &v1.MapTransform{Pairs: map[string]extv1.JSON{"test": {Raw: []byte(`["a","b"]`)}}}}},
+					},
+				},
+			},
+			want: want{
+				conn: managed.ConnectionDetails{
+					"password": []byte("hunter2"),
+					"endpoint": []byte("https://example.org"),
+					"name":     []byte(`["a","b"]`),
+				},
+			},
+		},
+		"TransformError": {
+			reason: "We should return an error if a connection detail's transform fails.",
+			args: args{
+				data: managed.ConnectionDetails{
+					"encoded": []byte("not base64!"),
+				},
+				cfg: []ConnectionDetailExtractConfig{
+					{
+						Type:                    ConnectionDetailTypeFromConnectionSecretKey,
+						Name:                    "password",
+						FromConnectionSecretKey: ptr.To("encoded"),
+						Transforms:              []v1.Transform{fromBase64},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtConnDetailTransform, 0, "password"),
+			},
+		},
 		"FetchConfigSuccess": {
 			reason: "Should extract only the selected set of secret keys",
 			args: args{
@@ -386,6 +456,178 @@ func TestExtractConnectionDetails(t *testing.T) {
 	}
 }
 
+func TestTransformConnectionDetail(t *testing.T) {
+	secret := "hunter2"
+
+	type args struct {
+		name string
+		v    []byte
+		ts   []v1.Transform
+	}
+	type want struct {
+		v   []byte
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoTransforms": {
+			reason: "We should return the value unchanged if there are no transforms.",
+			args: args{
+				name: "password",
+				v:    []byte(secret),
+			},
+			want: want{
+				v: []byte(secret),
+			},
+		},
+		"MapKeyNotFound": {
+			reason: "We should not include the secret value in the error if a map transform doesn't contain it.",
+			args: args{
+				name: "password",
+				v:    []byte(secret),
+				ts: []v1.Transform{{
+					Type: v1.TransformTypeMap,
+					Map:  &v1.MapTransform{Pairs: map[string]extv1.JSON{"other": {Raw: []byte(`"value"`)}}},
+				}},
+			},
+			want: want{
+				err: errors.Errorf(errFmtConnDetailTransform, 0, "password"),
+			},
+		},
+		"ConvertError": {
+			reason: "We should not include the secret value in the error if a convert transform fails.",
+			args: args{
+				name: "password",
+				v:    []byte(secret),
+				ts: []v1.Transform{
+					{
+						Type:   v1.TransformTypeString,
+						String: &v1.StringTransform{Type: v1.StringTransformTypeFormat, Format: ptr.To("%s")},
+					},
+					{
+						Type:    v1.TransformTypeConvert,
+						Convert: &v1.ConvertTransform{ToType: v1.TransformIOTypeInt64},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtConnDetailTransform, 1, "password"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := transformConnectionDetail(tc.args.name, tc.args.v, tc.args.ts)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ntransformConnectionDetail(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil && strings.Contains(err.Error(), secret) {
+				t.Errorf("\n%s\ntransformConnectionDetail(...): error %q contains the secret value", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.v, v); diff != "" {
+				t.Errorf("\n%s\ntransformConnectionDetail(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExtractCompositeConnectionDetails(t *testing.T) {
+	_, errNotFound := fieldpath.Pave(map[string]any{"spec": map[string]any{}}).GetValue("spec.missing")
+
+	xr := composite.New()
+	xr.Object["spec"] = map[string]any{
+		"endpoint": "example.org",
+		"ports":    []any{int64(80), int64(443)},
+	}
+
+	type args struct {
+		xr  resource.Composite
+		cds []v1.CompositeConnectionDetail
+	}
+	type want struct {
+		conn managed.ConnectionDetails
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoConnectionDetails": {
+			reason: "We should return no connection details if none are configured.",
+			args: args{
+				xr: xr,
+			},
+			want: want{
+				conn: managed.ConnectionDetails{},
+			},
+		},
+		"Success": {
+			reason: "We should read strings as is, and other values as JSON.",
+			args: args{
+				xr: xr,
+				cds: []v1.CompositeConnectionDetail{
+					{Name: "endpoint", FromFieldPath: "spec.endpoint"},
+					{Name: "ports", FromFieldPath: "spec.ports"},
+				},
+			},
+			want: want{
+				conn: managed.ConnectionDetails{
+					"endpoint": []byte("example.org"),
+					"ports":    []byte("[80,443]"),
+				},
+			},
+		},
+		"OptionalMissing": {
+			reason: "We should omit an optional connection detail whose field path doesn't exist.",
+			args: args{
+				xr: xr,
+				cds: []v1.CompositeConnectionDetail{
+					{Name: "endpoint", FromFieldPath: "spec.endpoint"},
+					{Name: "missing", FromFieldPath: "spec.missing"},
+				},
+			},
+			want: want{
+				conn: managed.ConnectionDetails{
+					"endpoint": []byte("example.org"),
+				},
+			},
+		},
+		"RequiredMissing": {
+			reason: "We should return an error if a required connection detail's field path doesn't exist.",
+			args: args{
+				xr: xr,
+				cds: []v1.CompositeConnectionDetail{
+					{
+						Name:          "missing",
+						FromFieldPath: "spec.missing",
+						Policy:        &v1.CompositeConnectionDetailPolicy{FromFieldPath: ptr.To(v1.FromFieldPathPolicyRequired)},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errNotFound, errFmtCompositeConnDetail, "missing", "spec.missing"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			conn, err := ExtractCompositeConnectionDetails(tc.args.xr, tc.args.cds)
+			if diff := cmp.Diff(tc.want.conn, conn); diff != "" {
+				t.Errorf("\n%s\nExtractCompositeConnectionDetails(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExtractCompositeConnectionDetails(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestExtractConfigsFromTemplate(t *testing.T) {
 	tfk := v1.ConnectionDetailTypeFromConnectionSecretKey
 
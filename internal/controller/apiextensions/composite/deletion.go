@@ -0,0 +1,274 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Error strings.
+const (
+	errGetDeletionRevision = "cannot get composition revision to determine deletion order"
+	errGetComposedForDel   = "cannot get composed resource"
+	errDeleteComposed      = "cannot delete composed resource"
+	errOrphanComposed      = "cannot remove owner reference from composed resource"
+
+	errFmtDeletionPhaseTimeout = "deletion phase %d timed out after %s waiting for composed resources %s to be deleted; starting the next phase"
+
+	fmtOrphanedComposed = "Orphaned %d composed resources because the composite resource's %s annotation is %q"
+)
+
+// A DeletionResult is the result of deleting an XR's composed resources.
+type DeletionResult struct {
+	// Complete is true if all ordered deletion phases have completed, and the
+	// XR can be deleted.
+	Complete bool
+
+	// Phase is the deletion phase that's in progress, starting from 1. It's
+	// only set if Complete is false.
+	Phase int
+
+	// Waiting is the names of the composed resources that must be deleted
+	// before the in-progress deletion phase completes.
+	Waiting []ResourceName
+
+	// TimedOut is the deletion phases that timed out, and were skipped.
+	TimedOut []DeletionPhaseTimeout
+
+	// Events that should be recorded on the XR.
+	Events []event.Event
+}
+
+// A DeletionPhaseTimeout is a deletion phase that timed out.
+type DeletionPhaseTimeout struct {
+	// Phase that timed out, starting from 1.
+	Phase int
+
+	// Event that describes the timeout.
+	Event event.Event
+}
+
+// A ComposedResourceDeleter deletes the resources composed by an XR that is
+// being deleted.
+type ComposedResourceDeleter interface {
+	DeleteComposedResources(ctx context.Context, xr *composite.Unstructured) (DeletionResult, error)
+}
+
+// A ComposedResourceDeleterFn deletes the resources composed by an XR that is
+// being deleted.
+type ComposedResourceDeleterFn func(ctx context.Context, xr *composite.Unstructured) (DeletionResult, error)
+
+// DeleteComposedResources deletes the resources composed by an XR.
+func (fn ComposedResourceDeleterFn) DeleteComposedResources(ctx context.Context, xr *composite.Unstructured) (DeletionResult, error) {
+	return fn(ctx, xr)
+}
+
+// A PhasedDeleter deletes an XR's composed resources in the phases specified
+// by the deletion order of its CompositionRevision. Composed resources that
+// aren't in any phase are left to be garbage collected once the XR is
+// deleted.
+type PhasedDeleter struct {
+	client client.Client
+}
+
+// NewPhasedDeleter returns a ComposedResourceDeleter that deletes composed
+// resources in the order specified by their XR's CompositionRevision.
+func NewPhasedDeleter(c client.Client) *PhasedDeleter {
+	return &PhasedDeleter{client: c}
+}
+
+// DeleteComposedResources deletes the supplied XR's composed resources one
+// deletion phase at a time. It deletes each composed resource in the first
+// phase that has composed resources that still exist, and returns which of them
+// it's waiting for. A phase that has been in progress for longer than its
+// timeout is skipped, and returned as timed out.
+func (d *PhasedDeleter) DeleteComposedResources(ctx context.Context, xr *composite.Unstructured) (DeletionResult, error) { //nolint:gocognit // Only slightly over.
+	ref := xr.GetCompositionRevisionReference()
+	if ref == nil {
+		return DeletionResult{Complete: true}, nil
+	}
+
+	rev := &v1.CompositionRevision{}
+	if err := d.client.Get(ctx, types.NamespacedName{Name: ref.Name}, rev); err != nil {
+		// Without a revision there's no deletion order to respect.
+		if kerrors.IsNotFound(err) {
+			return DeletionResult{Complete: true}, nil
+		}
+		return DeletionResult{}, errors.Wrap(err, errGetDeletionRevision)
+	}
+	if len(rev.Spec.DeletionOrder) == 0 {
+		return DeletionResult{Complete: true}, nil
+	}
+
+	// Find the composed resources that still exist, by their name within
+	// the composition.
+	existing := map[ResourceName][]*composed.Unstructured{}
+	for _, ref := range xr.GetResourceReferences() {
+		cd := composed.New(composed.FromReference(ref))
+		if err := d.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cd); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return DeletionResult{}, errors.Wrap(err, errGetComposedForDel)
+		}
+		// We only delete resources this XR controls.
+		if c := metav1.GetControllerOf(cd); c == nil || c.UID != xr.GetUID() {
+			continue
+		}
+		n := GetCompositionResourceName(cd)
+		existing[n] = append(existing[n], cd)
+	}
+
+	now := time.Now()
+	res := DeletionResult{}
+	for i, p := range rev.Spec.DeletionOrder {
+		var waiting []ResourceName
+		var started time.Time
+		for _, name := range p.Resources {
+			cds := existing[ResourceName(name)]
+			if len(cds) == 0 {
+				continue
+			}
+			waiting = append(waiting, ResourceName(name))
+			for _, cd := range cds {
+				// This phase started when we first deleted one of its
+				// composed resources.
+				if ts := cd.GetDeletionTimestamp(); ts != nil {
+					if started.IsZero() || ts.Time.Before(started) {
+						started = ts.Time
+					}
+					continue
+				}
+				if err := d.client.Delete(ctx, cd); resource.IgnoreNotFound(err) != nil {
+					return DeletionResult{}, errors.Wrap(err, errDeleteComposed)
+				}
+				if started.IsZero() {
+					started = now
+				}
+			}
+		}
+
+		if len(waiting) == 0 {
+			continue
+		}
+
+		if p.Timeout != nil && now.Sub(started) >= p.Timeout.Duration {
+			res.TimedOut = append(res.TimedOut, DeletionPhaseTimeout{
+				Phase: i + 1,
+				Event: event.Warning(reasonDelete, errors.Errorf(errFmtDeletionPhaseTimeout, i+1, p.Timeout.Duration, joinResourceNames(waiting))),
+			})
+			continue
+		}
+
+		res.Phase = i + 1
+		res.Waiting = waiting
+		return res, nil
+	}
+
+	res.Complete = true
+	return res, nil
+}
+
+// An OrphaningDeleter orphans the composed resources of an XR whose composed
+// deletion policy is Orphan, and otherwise deletes them using the wrapped
+// ComposedResourceDeleter.
+type OrphaningDeleter struct {
+	client  client.Client
+	wrapped ComposedResourceDeleter
+}
+
+// NewOrphaningDeleter returns a ComposedResourceDeleter that orphans composed
+// resources when their XR's composed deletion policy is Orphan.
+func NewOrphaningDeleter(c client.Client, d ComposedResourceDeleter) *OrphaningDeleter {
+	return &OrphaningDeleter{client: c, wrapped: d}
+}
+
+// DeleteComposedResources orphans the supplied XR's composed resources if its
+// composed deletion policy is Orphan, by removing the XR's owner reference from
+// each composed resource the XR controls. Orphaned composed resources aren't
+// garbage collected when the XR is deleted. Composed resources are otherwise
+// deleted by the wrapped ComposedResourceDeleter.
+func (d *OrphaningDeleter) DeleteComposedResources(ctx context.Context, xr *composite.Unstructured) (DeletionResult, error) {
+	if !IsOrphaningComposedResources(xr) {
+		return d.wrapped.DeleteComposedResources(ctx, xr)
+	}
+
+	orphaned := 0
+	for _, ref := range xr.GetResourceReferences() {
+		cd := composed.New(composed.FromReference(ref))
+		if err := d.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cd); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return DeletionResult{}, errors.Wrap(err, errGetComposedForDel)
+		}
+		// We only orphan resources this XR controls.
+		if c := metav1.GetControllerOf(cd); c == nil || c.UID != xr.GetUID() {
+			continue
+		}
+
+		refs := cd.GetOwnerReferences()
+		kept := make([]metav1.OwnerReference, 0, len(refs))
+		for _, r := range refs {
+			if r.UID != xr.GetUID() {
+				kept = append(kept, r)
+			}
+		}
+		cd.SetOwnerReferences(kept)
+		if err := d.client.Update(ctx, cd); resource.IgnoreNotFound(err) != nil {
+			return DeletionResult{}, errors.Wrap(err, errOrphanComposed)
+		}
+		orphaned++
+	}
+
+	res := DeletionResult{Complete: true}
+	if orphaned > 0 {
+		res.Events = append(res.Events, event.Normal(reasonDelete, fmt.Sprintf(fmtOrphanedComposed, orphaned, AnnotationKeyComposedDeletionPolicy, xpv1.DeletionOrphan)))
+	}
+	return res, nil
+}
+
+// WaitingMessage returns a message describing which composed resources the
+// in-progress deletion phase is waiting for.
+func (r DeletionResult) WaitingMessage() string {
+	return fmt.Sprintf("Waiting for composed resources to be deleted in deletion phase %d: %s", r.Phase, joinResourceNames(r.Waiting))
+}
+
+func joinResourceNames(names []ResourceName) string {
+	s := make([]string, len(names))
+	for i, n := range names {
+		s[i] = string(n)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,461 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestPhasedDeleterDeleteComposedResources(t *testing.T) {
+	errBoom := errors.New("boom")
+	hourAgo := metav1.NewTime(time.Now().Add(-1 * time.Hour))
+
+	xr := func() *composite.Unstructured {
+		return NewComposite(func(cr resource.Composite) {
+			cr.SetUID("cool-uid")
+			cr.SetCompositionRevisionReference(&corev1.LocalObjectReference{Name: "cool-rev"})
+			cr.SetResourceReferences([]corev1.ObjectReference{
+				{APIVersion: "example.org/v1", Kind: "App", Name: "cool-app"},
+				{APIVersion: "example.org/v1", Kind: "Database", Name: "cool-db"},
+				{APIVersion: "example.org/v1", Kind: "Network", Name: "cool-net"},
+			})
+		})
+	}
+
+	// cd returns a composed resource controlled by the XR.
+	cd := func(kind, name string, rn ResourceName, mods ...func(cd *composed.Unstructured)) *composed.Unstructured {
+		cd := composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: "example.org/v1", Kind: kind, Name: name}))
+		SetCompositionResourceName(cd, rn)
+		cd.SetOwnerReferences([]metav1.OwnerReference{{UID: "cool-uid", Controller: ptr.To(true)}})
+		for _, fn := range mods {
+			fn(cd)
+		}
+		return cd
+	}
+
+	order := []v1.DeletionPhase{
+		{Resources: []string{"app"}, Timeout: &metav1.Duration{Duration: time.Minute}},
+		{Resources: []string{"database"}},
+	}
+
+	// get returns a MockGetFn that supplies a revision with the supplied
+	// deletion order, and the supplied composed resources. Composed resources
+	// that aren't supplied are not found.
+	get := func(order []v1.DeletionPhase, cds ...*composed.Unstructured) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1.CompositionRevision:
+				o.Spec.DeletionOrder = order
+				return nil
+			case *composed.Unstructured:
+				for _, cd := range cds {
+					if cd.GetName() == key.Name {
+						*o = *cd.DeepCopy()
+						return nil
+					}
+				}
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+	}
+
+	type args struct {
+		client client.Client
+		xr     *composite.Unstructured
+	}
+	type want struct {
+		res     DeletionResult
+		err     error
+		deleted []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoCompositionRevision": {
+			reason: "We should be complete if the XR doesn't reference a composition revision.",
+			args: args{
+				client: &test.MockClient{},
+				xr:     NewComposite(),
+			},
+			want: want{
+				res: DeletionResult{Complete: true},
+			},
+		},
+		"CompositionRevisionNotFound": {
+			reason: "We should be complete if the XR's composition revision doesn't exist.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool-rev")),
+				},
+				xr: xr(),
+			},
+			want: want{
+				res: DeletionResult{Complete: true},
+			},
+		},
+		"GetCompositionRevisionError": {
+			reason: "We should return any error encountered getting the XR's composition revision.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				xr: xr(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetDeletionRevision),
+			},
+		},
+		"NoDeletionOrder": {
+			reason: "We should be complete if the composition revision doesn't specify a deletion order.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: get(nil, cd("App", "cool-app", "app")),
+				},
+				xr: xr(),
+			},
+			want: want{
+				res: DeletionResult{Complete: true},
+			},
+		},
+		"DeleteFirstPhase": {
+			reason: "We should delete the composed resources in the first phase, and wait for them to be deleted.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: get(order,
+						cd("App", "cool-app", "app"),
+						cd("Database", "cool-db", "database"),
+						cd("Network", "cool-net", "network"),
+					),
+				},
+				xr: xr(),
+			},
+			want: want{
+				res:     DeletionResult{Phase: 1, Waiting: []ResourceName{"app"}},
+				deleted: []string{"cool-app"},
+			},
+		},
+		"IgnoreUncontrolled": {
+			reason: "We shouldn't delete or wait for composed resources the XR doesn't control.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: get(order,
+						cd("App", "cool-app", "app", func(cd *composed.Unstructured) {
+							cd.SetOwnerReferences(nil)
+						}),
+						cd("Database", "cool-db", "database"),
+					),
+				},
+				xr: xr(),
+			},
+			want: want{
+				res:     DeletionResult{Phase: 2, Waiting: []ResourceName{"database"}},
+				deleted: []string{"cool-db"},
+			},
+		},
+		"WaitForFirstPhase": {
+			reason: "We shouldn't delete composed resources again while waiting for them to be deleted.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: get(order,
+						cd("App", "cool-app", "app", func(cd *composed.Unstructured) {
+							now := metav1.Now()
+							cd.SetDeletionTimestamp(&now)
+						}),
+						cd("Database", "cool-db", "database"),
+					),
+				},
+				xr: xr(),
+			},
+			want: want{
+				res: DeletionResult{Phase: 1, Waiting: []ResourceName{"app"}},
+			},
+		},
+		"FirstPhaseComplete": {
+			reason: "We should delete the composed resources in the second phase once the first phase's are gone.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: get(order,
+						cd("Database", "cool-db", "database"),
+						cd("Network", "cool-net", "network"),
+					),
+				},
+				xr: xr(),
+			},
+			want: want{
+				res:     DeletionResult{Phase: 2, Waiting: []ResourceName{"database"}},
+				deleted: []string{"cool-db"},
+			},
+		},
+		"FirstPhaseTimedOut": {
+			reason: "We should return a timed out phase and start the next phase if a phase times out.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: get(order,
+						cd("App", "cool-app", "app", func(cd *composed.Unstructured) {
+							cd.SetDeletionTimestamp(&hourAgo)
+						}),
+						cd("Database", "cool-db", "database"),
+					),
+				},
+				xr: xr(),
+			},
+			want: want{
+				res: DeletionResult{
+					Phase:   2,
+					Waiting: []ResourceName{"database"},
+					TimedOut: []DeletionPhaseTimeout{{
+						Phase: 1,
+						Event: event.Warning(reasonDelete, errors.Errorf(errFmtDeletionPhaseTimeout, 1, time.Minute, "app")),
+					}},
+				},
+				deleted: []string{"cool-db"},
+			},
+		},
+		"AllPhasesComplete": {
+			reason: "We should be complete once the composed resources in every phase are gone.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: get(order, cd("Network", "cool-net", "network")),
+				},
+				xr: xr(),
+			},
+			want: want{
+				res: DeletionResult{Complete: true},
+			},
+		},
+		"GetComposedResourceError": {
+			reason: "We should return any error encountered getting a composed resource.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if o, ok := obj.(*v1.CompositionRevision); ok {
+							o.Spec.DeletionOrder = order
+							return nil
+						}
+						return errBoom
+					},
+				},
+				xr: xr(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetComposedForDel),
+			},
+		},
+		"DeleteComposedResourceError": {
+			reason: "We should return any error encountered deleting a composed resource.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    get(order, cd("App", "cool-app", "app")),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+				xr: xr(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteComposed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			if mc, ok := tc.args.client.(*test.MockClient); ok && mc.MockDelete == nil {
+				mc.MockDelete = func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+					deleted = append(deleted, obj.GetName())
+					return nil
+				}
+			}
+
+			d := NewPhasedDeleter(tc.args.client)
+			res, err := d.DeleteComposedResources(context.Background(), tc.args.xr)
+
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\nDeleteComposedResources(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDeleteComposedResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted); diff != "" {
+				t.Errorf("\n%s\nDeleteComposedResources(...): -want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOrphaningDeleterDeleteComposedResources(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := func(policy string) *composite.Unstructured {
+		return NewComposite(func(cr resource.Composite) {
+			cr.SetUID("cool-uid")
+			if policy != "" {
+				cr.SetAnnotations(map[string]string{AnnotationKeyComposedDeletionPolicy: policy})
+			}
+			cr.SetResourceReferences([]corev1.ObjectReference{
+				{APIVersion: "example.org/v1", Kind: "App", Name: "cool-app"},
+				{APIVersion: "example.org/v1", Kind: "Database", Name: "cool-db"},
+				{APIVersion: "example.org/v1", Kind: "Network", Name: "cool-net"},
+			})
+		})
+	}
+
+	// get supplies cool-app controlled by the XR, and cool-db controlled by
+	// another XR. Any other composed resource isn't found.
+	get := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		owner := metav1.OwnerReference{UID: "other-uid", Controller: ptr.To(true)}
+		switch key.Name {
+		case "cool-app":
+			owner.UID = "cool-uid"
+		case "cool-db":
+		default:
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		}
+		obj.SetOwnerReferences([]metav1.OwnerReference{owner, {UID: "unrelated-uid"}})
+		return nil
+	}
+
+	wrapped := ComposedResourceDeleterFn(func(_ context.Context, _ *composite.Unstructured) (DeletionResult, error) {
+		return DeletionResult{Phase: 1, Waiting: []ResourceName{"app"}}, nil
+	})
+
+	type args struct {
+		client client.Client
+		xr     *composite.Unstructured
+	}
+	type want struct {
+		res     DeletionResult
+		err     error
+		updated map[string][]metav1.OwnerReference
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Delete": {
+			reason: "We should use the wrapped deleter if the XR's composed deletion policy isn't Orphan.",
+			args: args{
+				client: &test.MockClient{},
+				xr:     xr(string(xpv1.DeletionDelete)),
+			},
+			want: want{
+				res: DeletionResult{Phase: 1, Waiting: []ResourceName{"app"}},
+			},
+		},
+		"NoPolicy": {
+			reason: "We should use the wrapped deleter if the XR doesn't specify a composed deletion policy.",
+			args: args{
+				client: &test.MockClient{},
+				xr:     xr(""),
+			},
+			want: want{
+				res: DeletionResult{Phase: 1, Waiting: []ResourceName{"app"}},
+			},
+		},
+		"Orphan": {
+			reason: "We should remove the XR's owner reference from the composed resources it controls.",
+			args: args{
+				client: &test.MockClient{MockGet: get},
+				xr:     xr(string(xpv1.DeletionOrphan)),
+			},
+			want: want{
+				res: DeletionResult{
+					Complete: true,
+					Events:   []event.Event{event.Normal(reasonDelete, "Orphaned 1 composed resources because the composite resource's crossplane.io/composed-deletion-policy annotation is \"Orphan\"")},
+				},
+				updated: map[string][]metav1.OwnerReference{
+					"cool-app": {{UID: "unrelated-uid"}},
+				},
+			},
+		},
+		"GetComposedError": {
+			reason: "We should return any error encountered getting a composed resource.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				xr:     xr(string(xpv1.DeletionOrphan)),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetComposedForDel),
+			},
+		},
+		"UpdateComposedError": {
+			reason: "We should return any error encountered removing the XR's owner reference from a composed resource.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    get,
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				xr: xr(string(xpv1.DeletionOrphan)),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errOrphanComposed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updated map[string][]metav1.OwnerReference
+			if mc, ok := tc.args.client.(*test.MockClient); ok && mc.MockUpdate == nil {
+				mc.MockUpdate = func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					if updated == nil {
+						updated = map[string][]metav1.OwnerReference{}
+					}
+					updated[obj.GetName()] = obj.GetOwnerReferences()
+					return nil
+				}
+			}
+
+			d := NewOrphaningDeleter(tc.args.client, wrapped)
+			res, err := d.DeleteComposedResources(context.Background(), tc.args.xr)
+
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
+				t.Errorf("\n%s\nDeleteComposedResources(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDeleteComposedResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("\n%s\nDeleteComposedResources(...): -want updated, +got updated:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
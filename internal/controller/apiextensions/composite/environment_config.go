@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1beta1"
+)
+
+// Error strings.
+const (
+	errGetEnvironmentConfig        = "cannot get EnvironmentConfig"
+	errUpdateEnvironmentConfig     = "cannot update EnvironmentConfig"
+	errMarshalEnvironmentValue     = "cannot marshal value to JSON"
+	errUnmarshalEnvironmentValue   = "cannot unmarshal existing value"
+	errFmtEnvironmentKeyReadOnly   = "EnvironmentConfig %q doesn't list key %q in its %s annotation"
+	errFmtEnvironmentKeyConflict   = "EnvironmentConfig %q key %q already has a different value"
+	errFmtRenderToEnvConfigPatches = "cannot render ToEnvironmentConfig patches for composed resource %q"
+)
+
+// An EnvironmentConfigWriter writes a value to a key of an EnvironmentConfig.
+type EnvironmentConfigWriter interface {
+	WriteEnvironmentConfigKey(ctx context.Context, k v1.EnvironmentConfigKey, value any) error
+}
+
+// An EnvironmentConfigWriterFn writes a value to a key of an
+// EnvironmentConfig.
+type EnvironmentConfigWriterFn func(ctx context.Context, k v1.EnvironmentConfigKey, value any) error
+
+// WriteEnvironmentConfigKey writes the value to the key.
+func (fn EnvironmentConfigWriterFn) WriteEnvironmentConfigKey(ctx context.Context, k v1.EnvironmentConfigKey, value any) error {
+	return fn(ctx, k, value)
+}
+
+// An APIEnvironmentConfigWriter writes EnvironmentConfig keys using the API
+// server.
+type APIEnvironmentConfigWriter struct {
+	client client.Client
+}
+
+// NewAPIEnvironmentConfigWriter returns an EnvironmentConfigWriter that writes
+// EnvironmentConfig keys using the API server.
+func NewAPIEnvironmentConfigWriter(c client.Client) *APIEnvironmentConfigWriter {
+	return &APIEnvironmentConfigWriter{client: c}
+}
+
+// WriteEnvironmentConfigKey writes the supplied value to the supplied key. The
+// EnvironmentConfig must list the key in its composition-writable-keys
+// annotation. A key that already has a different value is never overwritten.
+// The update is rejected if the EnvironmentConfig changed since we read it, so
+// when several composite resources write the same key only the first write
+// succeeds.
+func (w *APIEnvironmentConfigWriter) WriteEnvironmentConfigKey(ctx context.Context, k v1.EnvironmentConfigKey, value any) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, errMarshalEnvironmentValue)
+	}
+
+	ec := &v1beta1.EnvironmentConfig{}
+	if err := w.client.Get(ctx, types.NamespacedName{Name: k.Name}, ec); err != nil {
+		return errors.Wrap(err, errGetEnvironmentConfig)
+	}
+
+	if !IsCompositionWritableKey(ec, k.Key) {
+		return errors.Errorf(errFmtEnvironmentKeyReadOnly, k.Name, k.Key, v1beta1.AnnotationKeyCompositionWritableKeys)
+	}
+
+	if existing, ok := ec.Data[k.Key]; ok {
+		equal, err := equalJSON(existing.Raw, raw)
+		if err != nil {
+			return err
+		}
+		if !equal {
+			return errors.Errorf(errFmtEnvironmentKeyConflict, k.Name, k.Key)
+		}
+		return nil
+	}
+
+	if ec.Data == nil {
+		ec.Data = make(map[string]extv1.JSON)
+	}
+	ec.Data[k.Key] = extv1.JSON{Raw: raw}
+	return errors.Wrap(w.client.Update(ctx, ec), errUpdateEnvironmentConfig)
+}
+
+// IsCompositionWritableKey returns true if the supplied EnvironmentConfig lets
+// ToEnvironmentConfig patches write the supplied key.
+func IsCompositionWritableKey(ec *v1beta1.EnvironmentConfig, key string) bool {
+	for _, k := range strings.Split(ec.GetAnnotations()[v1beta1.AnnotationKeyCompositionWritableKeys], ",") {
+		if strings.TrimSpace(k) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func equalJSON(a, b []byte) (bool, error) {
+	var av, bv any
+	if err := json.Unmarshal(a, &av); err != nil {
+		return false, errors.Wrap(err, errUnmarshalEnvironmentValue)
+	}
+	if err := json.Unmarshal(b, &bv); err != nil {
+		return false, errors.Wrap(err, errUnmarshalEnvironmentValue)
+	}
+	return reflect.DeepEqual(av, bv), nil
+}
+
+// RenderToEnvironmentConfigPatches applies all ToEnvironmentConfig patches,
+// using the supplied writer to write values read from the supplied composed
+// resource. A patch whose from field path doesn't exist on the composed
+// resource is skipped, unless its policy requires the field path.
+func RenderToEnvironmentConfigPatches(ctx context.Context, w EnvironmentConfigWriter, cd resource.Composed, xr resource.Composite, p []v1.Patch) error {
+	for i := range p {
+		if p[i].GetType() != v1.PatchTypeToEnvironmentConfig || p[i].EnvironmentConfig == nil || p[i].FromFieldPath == nil {
+			continue
+		}
+		ok, err := MatchesCondition(p[i], xr)
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		if !ok {
+			continue
+		}
+		from, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cd)
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		in, err := fieldpath.Pave(from).GetValue(*p[i].FromFieldPath)
+		if IsOptionalFieldPathNotFound(err, p[i].Policy) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		out, err := ResolveTransforms(p[i], in)
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		if err := w.WriteEnvironmentConfigKey(ctx, *p[i].EnvironmentConfig, out); err != nil {
+			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1beta1"
+)
+
+func TestWriteEnvironmentConfigKey(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	get := func(writable string, data map[string]extv1.JSON) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			ec := obj.(*v1beta1.EnvironmentConfig)
+			ec.SetName("cool-env")
+			ec.SetAnnotations(map[string]string{v1beta1.AnnotationKeyCompositionWritableKeys: writable})
+			ec.Data = data
+			return nil
+		})
+	}
+
+	type args struct {
+		client client.Client
+		k      v1.EnvironmentConfigKey
+		value  any
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"WriteUnsetKey": {
+			reason: "We should write a writable key that isn't set.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: get("other, id", nil),
+					MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+						want := &v1beta1.EnvironmentConfig{Data: map[string]extv1.JSON{"id": {Raw: []byte(`"abc123"`)}}}
+						want.SetName("cool-env")
+						want.SetAnnotations(map[string]string{v1beta1.AnnotationKeyCompositionWritableKeys: "other, id"})
+						if diff := cmp.Diff(want, obj); diff != "" {
+							t.Errorf("-want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				k:     v1.EnvironmentConfigKey{Name: "cool-env", Key: "id"},
+				value: "abc123",
+			},
+		},
+		"SameValue": {
+			reason: "We shouldn't update the EnvironmentConfig if the key already has the value.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    get("id", map[string]extv1.JSON{"id": {Raw: []byte(`{"a": 1}`)}}),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				k:     v1.EnvironmentConfigKey{Name: "cool-env", Key: "id"},
+				value: map[string]any{"a": int64(1)},
+			},
+		},
+		"DifferentValue": {
+			reason: "We should refuse to overwrite a key that has a different value.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    get("id", map[string]extv1.JSON{"id": {Raw: []byte(`"xyz789"`)}}),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				k:     v1.EnvironmentConfigKey{Name: "cool-env", Key: "id"},
+				value: "abc123",
+			},
+			want: errors.Errorf(errFmtEnvironmentKeyConflict, "cool-env", "id"),
+		},
+		"ReadOnlyKey": {
+			reason: "We should refuse to write a key the EnvironmentConfig doesn't list as writable.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    get("other", nil),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				k:     v1.EnvironmentConfigKey{Name: "cool-env", Key: "id"},
+				value: "abc123",
+			},
+			want: errors.Errorf(errFmtEnvironmentKeyReadOnly, "cool-env", "id", v1beta1.AnnotationKeyCompositionWritableKeys),
+		},
+		"GetError": {
+			reason: "We should return any error encountered getting the EnvironmentConfig.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				k:      v1.EnvironmentConfigKey{Name: "cool-env", Key: "id"},
+				value:  "abc123",
+			},
+			want: errors.Wrap(errBoom, errGetEnvironmentConfig),
+		},
+		"UpdateError": {
+			reason: "We should return any error encountered updating the EnvironmentConfig, for example because another composite resource updated it first.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    get("id", nil),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+				},
+				k:     v1.EnvironmentConfigKey{Name: "cool-env", Key: "id"},
+				value: "abc123",
+			},
+			want: errors.Wrap(errBoom, errUpdateEnvironmentConfig),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := NewAPIEnvironmentConfigWriter(tc.args.client)
+			err := w.WriteEnvironmentConfigKey(context.Background(), tc.args.k, tc.args.value)
+
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWriteEnvironmentConfigKey(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderToEnvironmentConfigPatches(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cd := composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Database"}))
+	_ = fieldpath.Pave(cd.Object).SetValue("status.atProvider.id", "abc123")
+
+	envPatch := func(from string) v1.Patch {
+		return v1.Patch{
+			Type:              v1.PatchTypeToEnvironmentConfig,
+			FromFieldPath:     ptr.To(from),
+			EnvironmentConfig: &v1.EnvironmentConfigKey{Name: "cool-env", Key: "id"},
+		}
+	}
+
+	type args struct {
+		p []v1.Patch
+	}
+	type want struct {
+		written map[string]any
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		writer EnvironmentConfigWriter
+		want   want
+	}{
+		"WriteValue": {
+			reason: "We should write the value read from the composed resource.",
+			args: args{
+				p: []v1.Patch{envPatch("status.atProvider.id")},
+			},
+			want: want{
+				written: map[string]any{"cool-env/id": "abc123"},
+			},
+		},
+		"WriteTransformedValue": {
+			reason: "We should transform the value read from the composed resource before we write it.",
+			args: args{
+				p: []v1.Patch{func() v1.Patch {
+					p := envPatch("status.atProvider.id")
+					p.Transforms = []v1.Transform{{
+						Type:   v1.TransformTypeString,
+						String: &v1.StringTransform{Type: v1.StringTransformTypeFormat, Format: ptr.To("db-%s")},
+					}}
+					return p
+				}()},
+			},
+			want: want{
+				written: map[string]any{"cool-env/id": "db-abc123"},
+			},
+		},
+		"OptionalFieldPathNotFound": {
+			reason: "We should skip a patch whose optional from field path doesn't exist.",
+			args: args{
+				p: []v1.Patch{envPatch("status.atProvider.arn")},
+			},
+			want: want{
+				written: map[string]any{},
+			},
+		},
+		"OtherPatchTypesIgnored": {
+			reason: "We should ignore patches that aren't of type ToEnvironmentConfig.",
+			args: args{
+				p: []v1.Patch{{Type: v1.PatchTypeToCompositeFieldPath, FromFieldPath: ptr.To("status.atProvider.id")}},
+			},
+			want: want{
+				written: map[string]any{},
+			},
+		},
+		"WriteError": {
+			reason: "We should return any error encountered writing the EnvironmentConfig.",
+			args: args{
+				p: []v1.Patch{envPatch("status.atProvider.id")},
+			},
+			writer: EnvironmentConfigWriterFn(func(_ context.Context, _ v1.EnvironmentConfigKey, _ any) error { return errBoom }),
+			want: want{
+				written: map[string]any{},
+				err:     errors.Wrapf(errBoom, errFmtPatch, v1.PatchTypeToEnvironmentConfig, 0),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			written := map[string]any{}
+			var w EnvironmentConfigWriter = EnvironmentConfigWriterFn(func(_ context.Context, k v1.EnvironmentConfigKey, value any) error {
+				written[k.Name+"/"+k.Key] = value
+				return nil
+			})
+			if tc.writer != nil {
+				w = tc.writer
+			}
+
+			err := RenderToEnvironmentConfigPatches(context.Background(), w, cd, NewComposite(), tc.args.p)
+
+			if diff := cmp.Diff(tc.want.written, written); diff != "" {
+				t.Errorf("\n%s\nRenderToEnvironmentConfigPatches(...): -want written, +got written:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRenderToEnvironmentConfigPatches(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// A composeRecord records when an XR was last successfully composed, and what
+// it was composed from.
+type composeRecord struct {
+	uid        types.UID
+	generation int64
+	revision   string
+	time       time.Time
+}
+
+// A composeTracker tracks when XRs were last successfully composed, so that
+// the Reconciler can avoid composing an unchanged XR too frequently.
+type composeTracker struct {
+	mx      sync.Mutex
+	records map[types.NamespacedName]composeRecord
+}
+
+func newComposeTracker() *composeTracker {
+	return &composeTracker{records: make(map[types.NamespacedName]composeRecord)}
+}
+
+// Record that the supplied XR was successfully composed using the supplied
+// revision at the supplied time.
+func (t *composeTracker) Record(xr *composite.Unstructured, rev *v1.CompositionRevision, now time.Time) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	t.records[types.NamespacedName{Namespace: xr.GetNamespace(), Name: xr.GetName()}] = composeRecord{
+		uid:        xr.GetUID(),
+		generation: xr.GetGeneration(),
+		revision:   rev.GetName(),
+		time:       now,
+	}
+}
+
+// Forget any record of the named XR.
+func (t *composeTracker) Forget(nn types.NamespacedName) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	delete(t.records, nn)
+}
+
+// Remaining returns how long remains of the supplied minimum interval since
+// the supplied XR was last successfully composed. It returns zero if the XR
+// has changed meaningfully since it was last composed - i.e. if it's a new XR,
+// its spec has changed, or it now uses a different composition revision.
+func (t *composeTracker) Remaining(xr *composite.Unstructured, rev *v1.CompositionRevision, minInterval time.Duration, now time.Time) time.Duration {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	r, ok := t.records[types.NamespacedName{Namespace: xr.GetNamespace(), Name: xr.GetName()}]
+	if !ok {
+		return 0
+	}
+	if r.uid != xr.GetUID() || r.generation != xr.GetGeneration() || r.revision != rev.GetName() {
+		return 0
+	}
+	return max(minInterval-now.Sub(r.time), 0)
+}
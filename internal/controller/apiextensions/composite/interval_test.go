@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestComposeTrackerRemaining(t *testing.T) {
+	then := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newXR := func(uid types.UID, generation int64) *composite.Unstructured {
+		xr := composite.New()
+		xr.SetName("cool-xr")
+		xr.SetUID(uid)
+		xr.SetGeneration(generation)
+		return xr
+	}
+	newRev := func(name string) *v1.CompositionRevision {
+		return &v1.CompositionRevision{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	type args struct {
+		recorded bool
+		forget   bool
+		xr       *composite.Unstructured
+		rev      *v1.CompositionRevision
+		now      time.Time
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   time.Duration
+	}{
+		"NeverComposed": {
+			reason: "An XR that was never composed should be composed immediately.",
+			args: args{
+				xr:  newXR("uid", 1),
+				rev: newRev("rev-1"),
+				now: then,
+			},
+			want: 0,
+		},
+		"Unchanged": {
+			reason: "An unchanged XR should wait for the remainder of the minimum interval.",
+			args: args{
+				recorded: true,
+				xr:       newXR("uid", 1),
+				rev:      newRev("rev-1"),
+				now:      then.Add(20 * time.Second),
+			},
+			want: 40 * time.Second,
+		},
+		"IntervalElapsed": {
+			reason: "An unchanged XR should be composed once the minimum interval has elapsed.",
+			args: args{
+				recorded: true,
+				xr:       newXR("uid", 1),
+				rev:      newRev("rev-1"),
+				now:      then.Add(2 * time.Minute),
+			},
+			want: 0,
+		},
+		"SpecChanged": {
+			reason: "An XR whose generation changed should be composed immediately.",
+			args: args{
+				recorded: true,
+				xr:       newXR("uid", 2),
+				rev:      newRev("rev-1"),
+				now:      then.Add(20 * time.Second),
+			},
+			want: 0,
+		},
+		"RevisionChanged": {
+			reason: "An XR that uses a different composition revision should be composed immediately.",
+			args: args{
+				recorded: true,
+				xr:       newXR("uid", 1),
+				rev:      newRev("rev-2"),
+				now:      then.Add(20 * time.Second),
+			},
+			want: 0,
+		},
+		"Recreated": {
+			reason: "An XR that was deleted and recreated with the same name should be composed immediately.",
+			args: args{
+				recorded: true,
+				xr:       newXR("new-uid", 1),
+				rev:      newRev("rev-1"),
+				now:      then.Add(20 * time.Second),
+			},
+			want: 0,
+		},
+		"Forgotten": {
+			reason: "An XR that was forgotten should be composed immediately.",
+			args: args{
+				recorded: true,
+				forget:   true,
+				xr:       newXR("uid", 1),
+				rev:      newRev("rev-1"),
+				now:      then.Add(20 * time.Second),
+			},
+			want: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ct := newComposeTracker()
+			if tc.args.recorded {
+				ct.Record(newXR("uid", 1), newRev("rev-1"), then)
+			}
+			if tc.args.forget {
+				ct.Forget(types.NamespacedName{Name: "cool-xr"})
+			}
+
+			got := ct.Remaining(tc.args.xr, tc.args.rev, time.Minute, tc.args.now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRemaining(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
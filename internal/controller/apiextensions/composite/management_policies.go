@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+// Error strings.
+const (
+	errFmtCheckManagementPolicies       = "cannot check whether composed resource %q supports default management policies"
+	errFmtSetManagementPolicies         = "cannot set default management policies on composed resource %q"
+	errFmtUnsupportedManagementPolicies = "not setting default management policies on composed resource %q because its provider revision %q doesn't declare capability %q"
+)
+
+// A ManagementPolicyDefaulter sets default management policies on composed
+// managed resources.
+type ManagementPolicyDefaulter interface {
+	// DefaultManagementPolicies sets default management policies on the
+	// supplied desired composed resources of the supplied composite
+	// resource. It returns events describing any composed resources it
+	// couldn't set default management policies on.
+	DefaultManagementPolicies(ctx context.Context, xr resource.Composite, desired ComposedResourceStates) ([]TargetedEvent, error)
+}
+
+// A ManagementPolicyDefaulterFn sets default management policies on composed
+// managed resources.
+type ManagementPolicyDefaulterFn func(ctx context.Context, xr resource.Composite, desired ComposedResourceStates) ([]TargetedEvent, error)
+
+// DefaultManagementPolicies sets default management policies on the supplied
+// desired composed resources.
+func (fn ManagementPolicyDefaulterFn) DefaultManagementPolicies(ctx context.Context, xr resource.Composite, desired ComposedResourceStates) ([]TargetedEvent, error) {
+	return fn(ctx, xr, desired)
+}
+
+// noDefaultManagementPolicies doesn't set any default management policies.
+func noDefaultManagementPolicies(_ context.Context, _ resource.Composite, _ ComposedResourceStates) ([]TargetedEvent, error) {
+	return nil, nil
+}
+
+// A ProviderManagementPolicyDefaulter sets default management policies on
+// composed managed resources whose provider supports them.
+type ProviderManagementPolicyDefaulter struct {
+	revision ProviderRevisionFetcher
+	policies xpv1.ManagementPolicies
+}
+
+// NewProviderManagementPolicyDefaulter returns a ManagementPolicyDefaulter
+// that sets the supplied default management policies. It uses the supplied
+// ProviderRevisionFetcher to determine whether a composed resource is a
+// managed resource, and whether its provider supports management policies.
+func NewProviderManagementPolicyDefaulter(f ProviderRevisionFetcher, p xpv1.ManagementPolicies) *ProviderManagementPolicyDefaulter {
+	return &ProviderManagementPolicyDefaulter{revision: f, policies: p}
+}
+
+// DefaultManagementPolicies sets default management policies on desired
+// composed resources.
+//
+// Management policies set by a Composition take precedence over the defaults.
+// The defaults are only set on managed resources, i.e. composed resources
+// whose CustomResourceDefinition is owned by a provider revision. They're not
+// set on managed resources whose provider declares capabilities that don't
+// include ManagementPolicies. Providers that declare no capabilities at all
+// predate capabilities, and are assumed to support management policies.
+func (d *ProviderManagementPolicyDefaulter) DefaultManagementPolicies(ctx context.Context, _ resource.Composite, desired ComposedResourceStates) ([]TargetedEvent, error) {
+	if len(d.policies) == 0 {
+		return nil, nil
+	}
+	mp := make([]string, len(d.policies))
+	for i := range d.policies {
+		mp[i] = string(d.policies[i])
+	}
+
+	// We want any events we return to be in a stable order.
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	events := make([]TargetedEvent, 0)
+	for _, name := range names {
+		u, ok := desired[ResourceName(name)].Resource.(runtime.Unstructured)
+		if !ok {
+			continue
+		}
+		if _, found, _ := unstructured.NestedFieldNoCopy(u.UnstructuredContent(), "spec", "managementPolicies"); found {
+			continue
+		}
+
+		rev, err := d.revision.FetchProviderRevision(ctx, desired[ResourceName(name)].Resource)
+		if err != nil {
+			events = append(events, TargetedEvent{
+				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtCheckManagementPolicies, name)),
+				Target: CompositionTargetComposite,
+			})
+			continue
+		}
+		if rev == nil {
+			continue
+		}
+		if !supportsManagementPolicies(rev.Status.Capabilities) {
+			events = append(events, TargetedEvent{
+				Event:  event.Warning(reasonCompose, errors.Errorf(errFmtUnsupportedManagementPolicies, name, rev.GetName(), pkgmetav1.ProviderCapabilityManagementPolicies)),
+				Target: CompositionTargetComposite,
+			})
+			continue
+		}
+
+		if err := unstructured.SetNestedStringSlice(u.UnstructuredContent(), mp, "spec", "managementPolicies"); err != nil {
+			return nil, errors.Wrapf(err, errFmtSetManagementPolicies, name)
+		}
+	}
+	return events, nil
+}
+
+// supportsManagementPolicies returns true if the supplied provider
+// capabilities include management policies, or if no capabilities are
+// declared.
+func supportsManagementPolicies(capabilities []string) bool {
+	if len(capabilities) == 0 {
+		return true
+	}
+	for _, c := range capabilities {
+		if c == pkgmetav1.ProviderCapabilityManagementPolicies {
+			return true
+		}
+	}
+	return false
+}
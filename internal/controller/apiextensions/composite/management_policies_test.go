@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestDefaultManagementPolicies(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"}
+	mapper := kmeta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, kmeta.RESTScopeRoot)
+
+	cd := func(spec map[string]any) *composed.Unstructured {
+		cd := composed.New()
+		cd.SetGroupVersionKind(gvk)
+		cd.Object["spec"] = spec
+		return cd
+	}
+
+	get := func(owner string, capabilities ...string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *extv1.CustomResourceDefinition:
+				if key.Name != "buckets.example.org" {
+					return errBoom
+				}
+				if owner != "" {
+					o.SetOwnerReferences([]metav1.OwnerReference{{Kind: owner, Name: "provider-example-abc", Controller: ptr.To(true)}})
+				}
+			case *pkgv1.ProviderRevision:
+				o.SetName(key.Name)
+				o.Status.Capabilities = capabilities
+			}
+			return nil
+		}
+	}
+
+	observe := xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+
+	type args struct {
+		client   client.Client
+		policies xpv1.ManagementPolicies
+		desired  ComposedResourceStates
+	}
+	type want struct {
+		desired ComposedResourceStates
+		events  []TargetedEvent
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoDefaults": {
+			reason: "We shouldn't change composed resources if the definition doesn't specify default management policies.",
+			args: args{
+				client:  &mappingClient{MockClient: &test.MockClient{MockGet: get(pkgv1.ProviderRevisionKind)}, mapper: mapper},
+				desired: ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+			},
+			want: want{
+				desired: ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+			},
+		},
+		"PoliciesSpecified": {
+			reason: "We shouldn't override management policies a composed resource already specifies.",
+			args: args{
+				client:   &mappingClient{MockClient: &test.MockClient{MockGet: get(pkgv1.ProviderRevisionKind)}, mapper: mapper},
+				policies: observe,
+				desired:  ComposedResourceStates{"bucket": {Resource: cd(map[string]any{"managementPolicies": []any{"*"}})}},
+			},
+			want: want{
+				desired: ComposedResourceStates{"bucket": {Resource: cd(map[string]any{"managementPolicies": []any{"*"}})}},
+				events:  []TargetedEvent{},
+			},
+		},
+		"NotManagedResource": {
+			reason: "We shouldn't set default management policies on a composed resource that isn't defined by a provider.",
+			args: args{
+				client:   &mappingClient{MockClient: &test.MockClient{MockGet: get("")}, mapper: mapper},
+				policies: observe,
+				desired:  ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+			},
+			want: want{
+				desired: ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+				events:  []TargetedEvent{},
+			},
+		},
+		"ManagementPoliciesUnsupported": {
+			reason: "We should return a warning and not set default management policies if the provider declares capabilities that don't include management policies.",
+			args: args{
+				client:   &mappingClient{MockClient: &test.MockClient{MockGet: get(pkgv1.ProviderRevisionKind, pkgmetav1.ProviderCapabilityInitProvider)}, mapper: mapper},
+				policies: observe,
+				desired:  ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+			},
+			want: want{
+				desired: ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+				events: []TargetedEvent{{
+					Event:  event.Warning(reasonCompose, errors.Errorf(errFmtUnsupportedManagementPolicies, "bucket", "provider-example-abc", pkgmetav1.ProviderCapabilityManagementPolicies)),
+					Target: CompositionTargetComposite,
+				}},
+			},
+		},
+		"GetCRDError": {
+			reason: "We should return a warning and not set default management policies if we can't tell whether the provider supports them.",
+			args: args{
+				client: &mappingClient{MockClient: &test.MockClient{MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					if _, ok := obj.(*extv1.CustomResourceDefinition); ok {
+						return errBoom
+					}
+					return get(pkgv1.ProviderRevisionKind)(ctx, key, obj)
+				}}, mapper: mapper},
+				policies: observe,
+				desired:  ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+			},
+			want: want{
+				desired: ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+				events: []TargetedEvent{{
+					Event:  event.Warning(reasonCompose, errors.Wrapf(errors.Wrap(errBoom, errGetComposedCRD), errFmtCheckManagementPolicies, "bucket")),
+					Target: CompositionTargetComposite,
+				}},
+			},
+		},
+		"ManagementPoliciesSupported": {
+			reason: "We should set default management policies on managed resources whose provider declares support for them.",
+			args: args{
+				client:   &mappingClient{MockClient: &test.MockClient{MockGet: get(pkgv1.ProviderRevisionKind, pkgmetav1.ProviderCapabilityManagementPolicies)}, mapper: mapper},
+				policies: observe,
+				desired:  ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+			},
+			want: want{
+				desired: ComposedResourceStates{"bucket": {Resource: cd(map[string]any{"managementPolicies": []any{"Observe"}})}},
+				events:  []TargetedEvent{},
+			},
+		},
+		"NoCapabilitiesDeclared": {
+			reason: "We should set default management policies on managed resources whose provider doesn't declare any capabilities.",
+			args: args{
+				client:   &mappingClient{MockClient: &test.MockClient{MockGet: get(pkgv1.ProviderRevisionKind)}, mapper: mapper},
+				policies: observe,
+				desired:  ComposedResourceStates{"bucket": {Resource: cd(map[string]any{})}},
+			},
+			want: want{
+				desired: ComposedResourceStates{"bucket": {Resource: cd(map[string]any{"managementPolicies": []any{"Observe"}})}},
+				events:  []TargetedEvent{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewProviderManagementPolicyDefaulter(NewAPIProviderRevisionFetcher(tc.args.client), tc.args.policies)
+			events, err := d.DefaultManagementPolicies(context.Background(), nil, tc.args.desired)
+
+			if diff := cmp.Diff(tc.want.desired, tc.args.desired); diff != "" {
+				t.Errorf("\n%s\nDefaultManagementPolicies(...): -want desired, +got desired:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, events, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDefaultManagementPolicies(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDefaultManagementPolicies(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
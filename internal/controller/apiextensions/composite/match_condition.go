@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/runtime"
+	celconfig "k8s.io/apiserver/pkg/apis/cel"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	errCELEnvironment = "cannot create CEL environment"
+
+	errFmtCompileMatchCondition  = "cannot compile match condition %q"
+	errFmtMatchConditionType     = "match condition %q must evaluate to a bool, not %s"
+	errFmtEvaluateMatchCondition = "cannot evaluate match condition %q"
+)
+
+// MatchConditionVariable is the CEL variable a patch or pipeline step's match
+// condition uses to refer to the composite resource.
+const MatchConditionVariable = "xr"
+
+var matchConditionEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(cel.Variable(MatchConditionVariable, cel.MapType(cel.StringType, cel.DynType)))
+})
+
+// CompileMatchCondition compiles the supplied patch or pipeline step match
+// condition.
+func CompileMatchCondition(expr string) (cel.Program, error) {
+	env, err := matchConditionEnv()
+	if err != nil {
+		return nil, errors.Wrap(err, errCELEnvironment)
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, errors.Wrapf(iss.Err(), errFmtCompileMatchCondition, expr)
+	}
+	if t := ast.OutputType(); !t.IsExactType(cel.BoolType) && !t.IsExactType(cel.DynType) {
+		return nil, errors.Errorf(errFmtMatchConditionType, expr, t)
+	}
+	prg, err := env.Program(ast, cel.CostLimit(celconfig.PerCallLimit))
+	return prg, errors.Wrapf(err, errFmtCompileMatchCondition, expr)
+}
+
+// MatchesCondition returns true if the supplied patch should be applied to the
+// supplied composite resource, i.e. if the patch has no match condition or its
+// match condition evaluates to true.
+func MatchesCondition(p v1.Patch, xr runtime.Object) (bool, error) {
+	if p.MatchCondition == nil {
+		return true, nil
+	}
+	return evaluateMatchCondition(*p.MatchCondition, xr)
+}
+
+// StepEnabled returns true if the supplied pipeline step should run for the
+// supplied composite resource, i.e. if the step isn't disabled and it has no
+// match condition or its match condition evaluates to true.
+func StepEnabled(s v1.PipelineStep, xr runtime.Object) (bool, error) {
+	if !ptr.Deref(s.Enabled, true) {
+		return false, nil
+	}
+	if s.MatchCondition == nil {
+		return true, nil
+	}
+	return evaluateMatchCondition(*s.MatchCondition, xr)
+}
+
+func evaluateMatchCondition(expr string, xr runtime.Object) (bool, error) {
+	prg, err := CompileMatchCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	paved, err := fieldpath.PaveObject(xr)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := prg.Eval(map[string]any{MatchConditionVariable: paved.UnstructuredContent()})
+	if err != nil {
+		return false, errors.Wrapf(err, errFmtEvaluateMatchCondition, expr)
+	}
+	match, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.Errorf(errFmtMatchConditionType, expr, out.Type())
+	}
+	return match, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestMatchesCondition(t *testing.T) {
+	xr := composite.New()
+	xr.SetUnstructuredContent(map[string]any{
+		"spec": map[string]any{
+			"env":      "production",
+			"replicas": int64(3),
+		},
+	})
+
+	type want struct {
+		match bool
+		err   bool
+	}
+
+	cases := map[string]struct {
+		reason    string
+		condition *string
+		want      want
+	}{
+		"NoCondition": {
+			reason: "A patch without a match condition should always match.",
+			want: want{
+				match: true,
+			},
+		},
+		"True": {
+			reason:    "A patch should match if its condition evaluates to true.",
+			condition: ptr.To(`xr.spec.env == "production" && xr.spec.replicas > 1`),
+			want: want{
+				match: true,
+			},
+		},
+		"False": {
+			reason:    "A patch should not match if its condition evaluates to false.",
+			condition: ptr.To(`xr.spec.env == "dev"`),
+			want: want{
+				match: false,
+			},
+		},
+		"OptionalField": {
+			reason:    "A condition should be able to test for an optional field using has().",
+			condition: ptr.To(`has(xr.spec.region) && xr.spec.region == "us-east-1"`),
+			want: want{
+				match: false,
+			},
+		},
+		"InvalidExpression": {
+			reason:    "We should return an error if the condition doesn't compile.",
+			condition: ptr.To(`xr.spec.env ==`),
+			want: want{
+				err: true,
+			},
+		},
+		"NotABool": {
+			reason:    "We should return an error if the condition doesn't evaluate to a bool.",
+			condition: ptr.To(`xr.spec.env`),
+			want: want{
+				err: true,
+			},
+		},
+		"MissingField": {
+			reason:    "We should return an error if the condition refers to a field that doesn't exist.",
+			condition: ptr.To(`xr.spec.region == "us-east-1"`),
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			match, err := MatchesCondition(v1.Patch{MatchCondition: tc.condition}, xr)
+			if diff := cmp.Diff(tc.want.match, match); diff != "" {
+				t.Errorf("\n%s\nMatchesCondition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nMatchesCondition(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStepEnabled(t *testing.T) {
+	xr := composite.New()
+	xr.SetUnstructuredContent(map[string]any{
+		"spec": map[string]any{
+			"beta": true,
+		},
+	})
+
+	type want struct {
+		enabled bool
+		err     bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		step   v1.PipelineStep
+		want   want
+	}{
+		"Default": {
+			reason: "A step should be enabled by default.",
+			want: want{
+				enabled: true,
+			},
+		},
+		"Disabled": {
+			reason: "A disabled step should not be enabled, regardless of its match condition.",
+			step:   v1.PipelineStep{Enabled: ptr.To(false), MatchCondition: ptr.To(`xr.spec.beta`)},
+			want: want{
+				enabled: false,
+			},
+		},
+		"ConditionTrue": {
+			reason: "A step should be enabled if its match condition evaluates to true.",
+			step:   v1.PipelineStep{Enabled: ptr.To(true), MatchCondition: ptr.To(`xr.spec.beta == true`)},
+			want: want{
+				enabled: true,
+			},
+		},
+		"ConditionFalse": {
+			reason: "A step should not be enabled if its match condition evaluates to false.",
+			step:   v1.PipelineStep{MatchCondition: ptr.To(`xr.spec.beta == false`)},
+			want: want{
+				enabled: false,
+			},
+		},
+		"ConditionError": {
+			reason: "We should return an error if the match condition can't be evaluated.",
+			step:   v1.PipelineStep{MatchCondition: ptr.To(`xr.spec.gamma`)},
+			want: want{
+				err: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			enabled, err := StepEnabled(tc.step, xr)
+			if diff := cmp.Diff(tc.want.enabled, enabled); diff != "" {
+				t.Errorf("\n%s\nStepEnabled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nStepEnabled(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -83,6 +83,21 @@ func mergeOptions(pas []v1.Patch) []resource.ApplyOption {
 	return opts
 }
 
+// mergePolicies returns the merge policies of a composed resource template as
+// an array of apply options. A policy without merge options merges maps, and
+// replaces arrays.
+func mergePolicies(mps []v1.MergePolicy) []resource.ApplyOption {
+	opts := make([]resource.ApplyOption, 0, len(mps))
+	for _, mp := range mps {
+		mo := mp.MergeOptions
+		if mo == nil {
+			mo = &xpv1.MergeOptions{}
+		}
+		opts = append(opts, withMergeOptions(mp.FieldPath, mo))
+	}
+	return opts
+}
+
 // patchFieldValueToObject applies the value to the "to" object at the given
 // path with the given merge options, returning any errors as they occur.
 // If no merge options is supplied, then destination field is replaced
@@ -17,6 +17,7 @@ limitations under the License.
 package composite
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -423,3 +424,84 @@ func TestMergeOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestMergePolicies(t *testing.T) {
+	current := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			Data: map[string]string{
+				"from-provider": "value-from-current",
+				"shared":        "value-from-current",
+			},
+		}
+	}
+	desired := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			Data: map[string]string{
+				"from-composition": "value-from-desired",
+				"shared":           "value-from-desired",
+			},
+		}
+	}
+
+	type args struct {
+		policies []v1.MergePolicy
+	}
+	type want struct {
+		desired *corev1.ConfigMap
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoPolicies": {
+			reason: "The desired value should replace the current value if there are no merge policies.",
+			want: want{
+				desired: desired(),
+			},
+		},
+		"MergeMap": {
+			reason: "A policy without merge options should preserve current map entries, and replace entries that are desired.",
+			args: args{
+				policies: []v1.MergePolicy{{FieldPath: "data"}},
+			},
+			want: want{
+				desired: &corev1.ConfigMap{
+					Data: map[string]string{
+						"from-composition": "value-from-desired",
+						"from-provider":    "value-from-current",
+						"shared":           "value-from-desired",
+					},
+				},
+			},
+		},
+		"KeepMapValues": {
+			reason: "A policy that keeps map values should preserve current map entries, including entries that are desired.",
+			args: args{
+				policies: []v1.MergePolicy{{FieldPath: "data", MergeOptions: &xpv1.MergeOptions{KeepMapValues: &valTrue}}},
+			},
+			want: want{
+				desired: &corev1.ConfigMap{
+					Data: map[string]string{
+						"from-composition": "value-from-desired",
+						"from-provider":    "value-from-current",
+						"shared":           "value-from-current",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := desired()
+			for _, o := range mergePolicies(tc.args.policies) {
+				if err := o(context.Background(), current(), d); err != nil {
+					t.Fatalf("\n%s\nmergePolicies(...): unexpected error: %s", tc.reason, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want.desired, d); diff != "" {
+				t.Errorf("\n%s\nmergePolicies(...): -want desired, +got desired:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An observation is the connection details fetched for a composed resource at
+// a particular resourceVersion.
+type observation struct {
+	resourceVersion string
+	fetched         time.Time
+	conn            managed.ConnectionDetails
+}
+
+// The observations of an XR's composed resources, keyed by composed resource
+// UID.
+type xrObservations struct {
+	used      time.Time
+	resources map[types.UID]observation
+}
+
+// A CachingConnectionDetailsFetcher caches the connection details of composed
+// resources, keyed by the composed resource's UID and resourceVersion.
+//
+// Composed resources are read from an informer cache, but their connection
+// secrets aren't, so fetching connection details costs an API server request
+// per composed resource per reconcile. This fetcher skips that request when
+// the composed resource's resourceVersion hasn't changed since its connection
+// details were fetched.
+//
+// A connection secret may change without its composed resource changing, so
+// cached connection details are reused for at most maxAge. This bounds how
+// stale they can be. The composed resource itself is always observed fresh.
+//
+// The cache holds the observations of at most maxXRs XRs. Each XR's
+// observations include only composed resources fetched within maxAge. When
+// the cache is full the least recently used XR's observations are evicted.
+type CachingConnectionDetailsFetcher struct {
+	fetcher managed.ConnectionDetailsFetcher
+	maxAge  time.Duration
+	maxXRs  int
+	now     func() time.Time
+
+	mx  sync.Mutex
+	xrs map[types.UID]*xrObservations
+}
+
+// NewCachingConnectionDetailsFetcher returns a ConnectionDetailsFetcher that
+// caches the connection details the supplied fetcher fetches for composed
+// resources.
+func NewCachingConnectionDetailsFetcher(f managed.ConnectionDetailsFetcher, maxAge time.Duration, maxXRs int) *CachingConnectionDetailsFetcher {
+	return &CachingConnectionDetailsFetcher{
+		fetcher: f,
+		maxAge:  maxAge,
+		maxXRs:  maxXRs,
+		now:     time.Now,
+		xrs:     make(map[types.UID]*xrObservations),
+	}
+}
+
+// FetchConnection details of the supplied composed resource, from the cache if
+// possible.
+func (f *CachingConnectionDetailsFetcher) FetchConnection(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+	c := metav1.GetControllerOf(o)
+
+	// We can only cache the connection details of composed resources that
+	// exist, and that are controlled by an XR.
+	if c == nil || o.GetUID() == "" || o.GetResourceVersion() == "" {
+		return f.fetcher.FetchConnection(ctx, o)
+	}
+
+	if conn, ok := f.get(c.UID, o.GetUID(), o.GetResourceVersion()); ok {
+		return conn, nil
+	}
+
+	conn, err := f.fetcher.FetchConnection(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	f.set(c.UID, o.GetUID(), observation{resourceVersion: o.GetResourceVersion(), fetched: f.now(), conn: conn})
+	return conn, nil
+}
+
+func (f *CachingConnectionDetailsFetcher) get(xr, cd types.UID, rv string) (managed.ConnectionDetails, bool) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	x, ok := f.xrs[xr]
+	if !ok {
+		return nil, false
+	}
+	now := f.now()
+	x.used = now
+
+	obs, ok := x.resources[cd]
+	if !ok || obs.resourceVersion != rv || now.Sub(obs.fetched) >= f.maxAge {
+		return nil, false
+	}
+
+	// Callers may modify the connection details they're returned.
+	conn := make(managed.ConnectionDetails, len(obs.conn))
+	for k, v := range obs.conn {
+		conn[k] = v
+	}
+	return conn, true
+}
+
+func (f *CachingConnectionDetailsFetcher) set(xr, cd types.UID, obs observation) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	now := f.now()
+	x, ok := f.xrs[xr]
+	if !ok {
+		f.evict()
+		x = &xrObservations{resources: make(map[types.UID]observation)}
+		f.xrs[xr] = x
+	}
+	x.used = now
+
+	// Forget expired observations, including those of composed resources
+	// the XR no longer composes.
+	for uid, o := range x.resources {
+		if now.Sub(o.fetched) >= f.maxAge {
+			delete(x.resources, uid)
+		}
+	}
+
+	conn := make(managed.ConnectionDetails, len(obs.conn))
+	for k, v := range obs.conn {
+		conn[k] = v
+	}
+	obs.conn = conn
+	x.resources[cd] = obs
+}
+
+// evict the least recently used XR's observations if the cache is full.
+func (f *CachingConnectionDetailsFetcher) evict() {
+	if len(f.xrs) < f.maxXRs {
+		return
+	}
+	var lru types.UID
+	var used time.Time
+	for uid, x := range f.xrs {
+		if lru == "" || x.used.Before(used) {
+			lru, used = uid, x.used
+		}
+	}
+	delete(f.xrs, lru)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCachingConnectionDetailsFetcher(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := time.Now()
+
+	cd := func(xr types.UID, uid types.UID, rv string) *composed.Unstructured {
+		r := composed.New()
+		r.SetUID(uid)
+		r.SetResourceVersion(rv)
+		if xr != "" {
+			r.SetOwnerReferences([]metav1.OwnerReference{{UID: xr, Controller: ptr.To(true)}})
+		}
+		return r
+	}
+
+	// A fetch of a composed resource at an offset from now.
+	type fetch struct {
+		cd    *composed.Unstructured
+		after time.Duration
+	}
+
+	type args struct {
+		maxAge  time.Duration
+		maxXRs  int
+		err     error
+		fetches []fetch
+	}
+	type want struct {
+		calls int
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "We should only fetch the connection details of a composed resource whose resourceVersion hasn't changed once.",
+			args: args{
+				maxAge: time.Minute,
+				maxXRs: 10,
+				fetches: []fetch{
+					{cd: cd("xr", "cd", "1")},
+					{cd: cd("xr", "cd", "1"), after: 30 * time.Second},
+				},
+			},
+			want: want{calls: 1},
+		},
+		"Changed": {
+			reason: "We should fetch the connection details of a composed resource again if its resourceVersion changed.",
+			args: args{
+				maxAge: time.Minute,
+				maxXRs: 10,
+				fetches: []fetch{
+					{cd: cd("xr", "cd", "1")},
+					{cd: cd("xr", "cd", "2"), after: 30 * time.Second},
+				},
+			},
+			want: want{calls: 2},
+		},
+		"Expired": {
+			reason: "We should fetch the connection details of an unchanged composed resource again once they're older than the max age.",
+			args: args{
+				maxAge: time.Minute,
+				maxXRs: 10,
+				fetches: []fetch{
+					{cd: cd("xr", "cd", "1")},
+					{cd: cd("xr", "cd", "1"), after: time.Minute},
+				},
+			},
+			want: want{calls: 2},
+		},
+		"NotControlled": {
+			reason: "We shouldn't cache the connection details of a composed resource without a controller.",
+			args: args{
+				maxAge: time.Minute,
+				maxXRs: 10,
+				fetches: []fetch{
+					{cd: cd("", "cd", "1")},
+					{cd: cd("", "cd", "1")},
+				},
+			},
+			want: want{calls: 2},
+		},
+		"Evicted": {
+			reason: "We should evict the least recently used XR's observations when the cache is full.",
+			args: args{
+				maxAge: time.Minute,
+				maxXRs: 1,
+				fetches: []fetch{
+					{cd: cd("xr-a", "cd-a", "1")},
+					{cd: cd("xr-b", "cd-b", "1")},
+					{cd: cd("xr-a", "cd-a", "1")},
+				},
+			},
+			want: want{calls: 3},
+		},
+		"Error": {
+			reason: "We should return and not cache errors fetching connection details.",
+			args: args{
+				maxAge: time.Minute,
+				maxXRs: 10,
+				err:    errBoom,
+				fetches: []fetch{
+					{cd: cd("xr", "cd", "1")},
+					{cd: cd("xr", "cd", "1")},
+				},
+			},
+			want: want{calls: 2, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			f := NewCachingConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+				calls++
+				if tc.args.err != nil {
+					return nil, tc.args.err
+				}
+				return managed.ConnectionDetails{"key": []byte("secret")}, nil
+			}), tc.args.maxAge, tc.args.maxXRs)
+
+			var err error
+			for _, fe := range tc.args.fetches {
+				f.now = func() time.Time { return now.Add(fe.after) }
+				var conn managed.ConnectionDetails
+				conn, err = f.FetchConnection(context.Background(), fe.cd)
+				if err == nil {
+					if diff := cmp.Diff(managed.ConnectionDetails{"key": []byte("secret")}, conn); diff != "" {
+						t.Errorf("\n%s\nFetchConnection(...): -want, +got:\n%s", tc.reason, diff)
+					}
+				}
+			}
+
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("\n%s\nFetchConnection(...): -want fetches, +got fetches:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetchConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
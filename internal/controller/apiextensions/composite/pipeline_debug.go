@@ -0,0 +1,192 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+)
+
+// Pipeline debugging records the request and response of each Composition
+// Function pipeline step in the XR's status. It's opt-in per XR, using an
+// annotation that specifies when to stop recording. Recording stops, and the
+// recorded requests and responses are removed, once that time passes or the
+// annotation is removed.
+const (
+	// AnnotationKeyPipelineDebugUntil is the annotation that enables pipeline
+	// debugging for an XR, until the RFC 3339 time it specifies.
+	AnnotationKeyPipelineDebugUntil = "crossplane.io/composition-debug-until"
+
+	// MaxPipelineDebugSize is the maximum size, in bytes of JSON, of the
+	// requests and responses recorded in an XR's status.
+	MaxPipelineDebugSize = 256 * 1024
+
+	// fieldPipelineDebug is the XR status field that records the requests
+	// and responses of the pipeline steps that ran during the most recent
+	// reconcile.
+	fieldPipelineDebug = "pipelineDebug"
+)
+
+const (
+	errMarshalPipelineDebug = "cannot marshal pipeline debug record"
+)
+
+// A PipelineStepDebug records the request a Composition Function pipeline
+// step was sent, and the response it returned. Both are redacted.
+type PipelineStepDebug struct {
+	Step     string
+	Request  *fnv1.RunFunctionRequest
+	Response *fnv1.RunFunctionResponse
+}
+
+// PipelineDebugEnabled returns true if the supplied XR's pipeline should be
+// recorded at the supplied time.
+func PipelineDebugEnabled(xr metav1.Object, now time.Time) bool {
+	_, ok := PipelineDebugRemaining(xr, now)
+	return ok
+}
+
+// PipelineDebugRemaining returns how long after the supplied time the supplied
+// XR's pipeline should be recorded for. It returns false if the XR's pipeline
+// shouldn't be recorded at the supplied time.
+func PipelineDebugRemaining(xr metav1.Object, now time.Time) (time.Duration, bool) {
+	v, ok := xr.GetAnnotations()[AnnotationKeyPipelineDebugUntil]
+	if !ok {
+		return 0, false
+	}
+	until, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return 0, false
+	}
+	if !now.Before(until) {
+		return 0, false
+	}
+	return until.Sub(now), true
+}
+
+// NewPipelineStepDebug returns a record of the supplied request and
+// response, redacted so that it doesn't include any credentials, connection
+// details, or Secret data. The step's input and the pipeline context are
+// dropped, because they're arbitrary data that may include secrets.
+func NewPipelineStepDebug(step string, req *fnv1.RunFunctionRequest, rsp *fnv1.RunFunctionResponse) PipelineStepDebug {
+	d := PipelineStepDebug{Step: step}
+	if req != nil {
+		d.Request = proto.Clone(req).(*fnv1.RunFunctionRequest) //nolint:forcetypeassert // Clone always returns the type it was passed.
+		d.Request.Credentials = nil
+		d.Request.Input = nil
+		d.Request.Context = nil
+		redactState(d.Request.GetObserved())
+		redactState(d.Request.GetDesired())
+		for _, rs := range d.Request.GetExtraResources() {
+			for _, r := range rs.GetItems() {
+				redactResource(r)
+			}
+		}
+	}
+	if rsp != nil {
+		d.Response = proto.Clone(rsp).(*fnv1.RunFunctionResponse) //nolint:forcetypeassert // Clone always returns the type it was passed.
+		d.Response.Context = nil
+		redactState(d.Response.GetDesired())
+	}
+	return d
+}
+
+func redactState(s *fnv1.State) {
+	if s.GetComposite() != nil {
+		redactResource(s.GetComposite())
+	}
+	for _, r := range s.GetResources() {
+		redactResource(r)
+	}
+}
+
+// redactResource removes a resource's connection details, and its data if
+// it's a Secret.
+func redactResource(r *fnv1.Resource) {
+	r.ConnectionDetails = nil
+	f := r.GetResource().GetFields()
+	if f["apiVersion"].GetStringValue() != "v1" || f["kind"].GetStringValue() != "Secret" {
+		return
+	}
+	delete(f, "data")
+	delete(f, "stringData")
+}
+
+// SetPipelineDebug records the supplied pipeline debug records in the
+// supplied XR's status, replacing any records from a previous reconcile. The
+// records are removed if none are supplied. Records are kept in pipeline
+// order until MaxPipelineDebugSize is reached. Any step whose request and
+// response don't fit is recorded as truncated, without them.
+func SetPipelineDebug(xr *composite.Unstructured, records []PipelineStepDebug) error {
+	p := fieldpath.Pave(xr.Object)
+	if len(records) == 0 {
+		return p.DeleteField("status." + fieldPipelineDebug)
+	}
+
+	budget := MaxPipelineDebugSize
+	out := make([]any, 0, len(records))
+	for _, r := range records {
+		sd := map[string]any{"step": r.Step}
+
+		var req, rsp []byte
+		if r.Request != nil {
+			b, err := protojson.Marshal(r.Request)
+			if err != nil {
+				return errors.Wrap(err, errMarshalPipelineDebug)
+			}
+			req = b
+		}
+		if r.Response != nil {
+			b, err := protojson.Marshal(r.Response)
+			if err != nil {
+				return errors.Wrap(err, errMarshalPipelineDebug)
+			}
+			rsp = b
+		}
+
+		size := len(req) + len(rsp)
+		if size > budget {
+			sd["truncated"] = true
+			out = append(out, sd)
+			continue
+		}
+		budget -= size
+
+		for k, raw := range map[string][]byte{"request": req, "response": rsp} {
+			if raw == nil {
+				continue
+			}
+			var v any
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return errors.Wrap(err, errMarshalPipelineDebug)
+			}
+			sd[k] = v
+		}
+		out = append(out, sd)
+	}
+	return p.SetValue("status."+fieldPipelineDebug, out)
+}
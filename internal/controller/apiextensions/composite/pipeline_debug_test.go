@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+)
+
+func TestPipelineDebugRemaining(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	type want struct {
+		remaining time.Duration
+		ok        bool
+	}
+
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        want
+	}{
+		"NoAnnotation": {
+			reason: "Debugging should be disabled unless the XR is annotated.",
+		},
+		"InvalidTime": {
+			reason:      "Debugging should be disabled if the annotation isn't an RFC 3339 time.",
+			annotations: map[string]string{AnnotationKeyPipelineDebugUntil: "true"},
+		},
+		"Expired": {
+			reason:      "Debugging should be disabled once the annotation's time has passed.",
+			annotations: map[string]string{AnnotationKeyPipelineDebugUntil: "2026-10-16T11:00:00Z"},
+		},
+		"Enabled": {
+			reason:      "Debugging should be enabled until the annotation's time.",
+			annotations: map[string]string{AnnotationKeyPipelineDebugUntil: "2026-10-16T13:00:00Z"},
+			want: want{
+				remaining: time.Hour,
+				ok:        true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			xr := &metav1.ObjectMeta{Annotations: tc.annotations}
+			remaining, ok := PipelineDebugRemaining(xr, now)
+			if diff := cmp.Diff(tc.want, want{remaining: remaining, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nPipelineDebugRemaining(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, PipelineDebugEnabled(xr, now)); diff != "" {
+				t.Errorf("\n%s\nPipelineDebugEnabled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewPipelineStepDebug(t *testing.T) {
+	secret := func(extra map[string]any) *structpb.Struct {
+		m := map[string]any{"apiVersion": "v1", "kind": "Secret"}
+		for k, v := range extra {
+			m[k] = v
+		}
+		return MustStruct(m)
+	}
+	conn := map[string][]byte{"password": []byte("secret")}
+
+	req := &fnv1.RunFunctionRequest{
+		Observed: &fnv1.State{
+			Composite: &fnv1.Resource{Resource: MustStruct(map[string]any{"apiVersion": "example.org/v1", "kind": "XR"}), ConnectionDetails: conn},
+			Resources: map[string]*fnv1.Resource{
+				"secret": {Resource: secret(map[string]any{"data": map[string]any{"password": "c2VjcmV0"}}), ConnectionDetails: conn},
+			},
+		},
+		Desired: &fnv1.State{
+			Composite: &fnv1.Resource{ConnectionDetails: conn},
+		},
+		Credentials: map[string]*fnv1.Credentials{
+			"creds": {Source: &fnv1.Credentials_CredentialData{CredentialData: &fnv1.CredentialData{Data: conn}}},
+		},
+		ExtraResources: map[string]*fnv1.Resources{
+			"extra": {Items: []*fnv1.Resource{{Resource: secret(map[string]any{"stringData": map[string]any{"password": "secret"}})}}},
+		},
+		Input:   MustStruct(map[string]any{"password": "secret"}),
+		Context: MustStruct(map[string]any{"password": "secret"}),
+	}
+	rsp := &fnv1.RunFunctionResponse{
+		Context: MustStruct(map[string]any{"password": "secret"}),
+		Desired: &fnv1.State{
+			Composite: &fnv1.Resource{ConnectionDetails: conn},
+			Resources: map[string]*fnv1.Resource{
+				"secret": {Resource: secret(map[string]any{"data": map[string]any{"password": "c2VjcmV0"}}), ConnectionDetails: conn},
+			},
+		},
+	}
+
+	want := PipelineStepDebug{
+		Step: "a",
+		Request: &fnv1.RunFunctionRequest{
+			Observed: &fnv1.State{
+				Composite: &fnv1.Resource{Resource: MustStruct(map[string]any{"apiVersion": "example.org/v1", "kind": "XR"})},
+				Resources: map[string]*fnv1.Resource{
+					"secret": {Resource: secret(nil)},
+				},
+			},
+			Desired: &fnv1.State{
+				Composite: &fnv1.Resource{},
+			},
+			ExtraResources: map[string]*fnv1.Resources{
+				"extra": {Items: []*fnv1.Resource{{Resource: secret(nil)}}},
+			},
+		},
+		Response: &fnv1.RunFunctionResponse{
+			Desired: &fnv1.State{
+				Composite: &fnv1.Resource{},
+				Resources: map[string]*fnv1.Resource{
+					"secret": {Resource: secret(nil)},
+				},
+			},
+		},
+	}
+
+	got := NewPipelineStepDebug("a", req, rsp)
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("NewPipelineStepDebug(...): -want, +got:\n%s", diff)
+	}
+
+	// The supplied request and response must not be modified.
+	if len(req.GetCredentials()) == 0 || len(rsp.GetDesired().GetComposite().GetConnectionDetails()) == 0 {
+		t.Errorf("NewPipelineStepDebug(...): modified the supplied request or response")
+	}
+}
+
+func TestSetPipelineDebug(t *testing.T) {
+	small := &fnv1.RunFunctionRequest{Meta: &fnv1.RequestMeta{Tag: "small"}}
+	large := &fnv1.RunFunctionRequest{Meta: &fnv1.RequestMeta{Tag: strings.Repeat("l", MaxPipelineDebugSize)}}
+
+	cases := map[string]struct {
+		reason  string
+		xr      *composite.Unstructured
+		records []PipelineStepDebug
+		want    any
+	}{
+		"NoRecords": {
+			reason: "Records from a previous reconcile should be removed if there are no records.",
+			xr: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+				"status": map[string]any{
+					"pipelineDebug": []any{map[string]any{"step": "old"}},
+				},
+			}}},
+			want: nil,
+		},
+		"SomeRecords": {
+			reason: "Records should replace those from a previous reconcile.",
+			xr: &composite.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+				"status": map[string]any{
+					"pipelineDebug": []any{map[string]any{"step": "old"}},
+				},
+			}}},
+			records: []PipelineStepDebug{
+				{Step: "a", Request: small, Response: &fnv1.RunFunctionResponse{}},
+				{Step: "b", Request: small},
+			},
+			want: []any{
+				map[string]any{
+					"step":     "a",
+					"request":  map[string]any{"meta": map[string]any{"tag": "small"}},
+					"response": map[string]any{},
+				},
+				map[string]any{
+					"step":    "b",
+					"request": map[string]any{"meta": map[string]any{"tag": "small"}},
+				},
+			},
+		},
+		"TooLarge": {
+			reason: "Steps whose request and response don't fit should be recorded as truncated.",
+			xr:     composite.New(),
+			records: []PipelineStepDebug{
+				{Step: "a", Request: large},
+				{Step: "b", Request: small},
+			},
+			want: []any{
+				map[string]any{"step": "a", "truncated": true},
+				map[string]any{
+					"step":    "b",
+					"request": map[string]any{"meta": map[string]any{"tag": "small"}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := SetPipelineDebug(tc.xr, tc.records); err != nil {
+				t.Fatalf("\n%s\nSetPipelineDebug(...): unexpected error: %s", tc.reason, err)
+			}
+			got, _ := fieldpath.Pave(tc.xr.Object).GetValue("status.pipelineDebug")
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSetPipelineDebug(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
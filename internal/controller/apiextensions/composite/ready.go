@@ -56,7 +56,9 @@ const (
 	ReadinessCheckTypeMatchTrue      ReadinessCheckType = "MatchTrue"
 	ReadinessCheckTypeMatchFalse     ReadinessCheckType = "MatchFalse"
 	ReadinessCheckTypeMatchCondition ReadinessCheckType = "MatchCondition"
-	ReadinessCheckTypeNone           ReadinessCheckType = "None"
+	// A None check always passes. A composed resource whose only check is
+	// None is always ready, so it doesn't hold up its XR's readiness.
+	ReadinessCheckTypeNone ReadinessCheckType = "None"
 )
 
 // ReadinessCheck is used to indicate how to tell whether a resource is ready
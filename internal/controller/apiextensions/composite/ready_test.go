@@ -108,6 +108,22 @@ func TestIsReady(t *testing.T) {
 				ready: true,
 			},
 		},
+		"NoneWithOtherChecks": {
+			reason: "A 'None' readiness check shouldn't make a resource ready if its other checks fail.",
+			args: args{
+				o: composed.New(),
+				rc: []ReadinessCheck{
+					{
+						Type:           ReadinessCheckTypeMatchCondition,
+						MatchCondition: &MatchConditionReadinessCheck{Type: xpv1.TypeReady, Status: corev1.ConditionTrue},
+					},
+					{Type: ReadinessCheckTypeNone},
+				},
+			},
+			want: want{
+				ready: false,
+			},
+		},
 		"NonEmptyMissingFieldPath": {
 			reason: "If the value cannot be fetched due to fieldPath being missing, an error should be returned",
 			args: args{
@@ -21,14 +21,19 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strconv"
 	"time"
 
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -36,6 +41,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
@@ -46,37 +52,56 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/engine"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 const (
 	timeout             = 2 * time.Minute
 	defaultPollInterval = 1 * time.Minute
 	finalizer           = "composite.apiextensions.crossplane.io"
+	protectionFinalizer = "protection.composite.apiextensions.crossplane.io"
 )
 
+// DefaultPollJitter is the fraction of the poll interval by which each poll
+// interval is varied by default.
+const DefaultPollJitter = 0.1
+
 // Error strings.
 const (
-	errGet                    = "cannot get composite resource"
-	errUpdate                 = "cannot update composite resource"
-	errUpdateStatus           = "cannot update composite resource status"
-	errAddFinalizer           = "cannot add composite resource finalizer"
-	errRemoveFinalizer        = "cannot remove composite resource finalizer"
-	errSelectComp             = "cannot select Composition"
-	errSelectCompUpdatePolicy = "cannot select CompositionUpdatePolicy"
-	errFetchComp              = "cannot fetch Composition"
-	errConfigure              = "cannot configure composite resource"
-	errPublish                = "cannot publish connection details"
-	errUnpublish              = "cannot unpublish connection details"
-	errValidate               = "refusing to use invalid Composition"
-	errAssociate              = "cannot associate composed resources with Composition resource templates"
-	errCompose                = "cannot compose resources"
-	errInvalidResources       = "some resources were invalid, check events"
-	errRenderCD               = "cannot render composed resource"
-	errSyncResources          = "cannot sync composed resources"
-	errGetClaim               = "cannot get referenced claim"
-	errParseClaimRef          = "cannot parse claim reference"
-
-	reconcilePausedMsg = "Reconciliation (including deletion) is paused via the pause annotation"
+	errGet                     = "cannot get composite resource"
+	errUpdate                  = "cannot update composite resource"
+	errUpdateStatus            = "cannot update composite resource status"
+	errAddFinalizer            = "cannot add composite resource finalizer"
+	errRemoveFinalizer         = "cannot remove composite resource finalizer"
+	errAddProtection           = "cannot add composite resource deletion protection finalizer"
+	errRemoveProtection        = "cannot remove composite resource deletion protection finalizer"
+	errSelectComp              = "cannot select Composition"
+	errSelectCompUpdatePolicy  = "cannot select CompositionUpdatePolicy"
+	errFetchComp               = "cannot fetch Composition"
+	errConfigure               = "cannot configure composite resource"
+	errPublish                 = "cannot publish connection details"
+	errExtractCompositeConn    = "cannot extract composite connection details"
+	errUnpublish               = "cannot unpublish connection details"
+	errDeleteComposedResources = "cannot delete composed resources"
+	errHandleReconcileRequest  = "cannot acknowledge reconcile request"
+	errValidate                = "refusing to use invalid Composition"
+	errAssociate               = "cannot associate composed resources with Composition resource templates"
+	errCompose                 = "cannot compose resources"
+	errInvalidResources        = "some resources were invalid, check events"
+	errRenderCD                = "cannot render composed resource"
+	errSyncResources           = "cannot sync composed resources"
+	errGetClaim                = "cannot get referenced claim"
+	errParseClaimRef           = "cannot parse claim reference"
+	errSetPipelineResults      = "cannot set composite resource pipeline results"
+	errSetPipelineDebug        = "cannot set composite resource pipeline debug records"
+	errSetResourceReadiness    = "cannot set composite resource readiness"
+
+	errFmtCompositionRevisionHash = "refusing to use composition revision %q with hash %q because the composite resource is pinned to hash %q"
+	errFmtMaxCompositionDepth     = "refusing to compose resources because the composite resource is nested %d levels deep, which is more than the maximum composition depth of %d"
+
+	reconcilePausedMsg           = "Reconciliation (including deletion) is paused via the pause annotation"
+	deletionProtectedMsg         = "Deletion is blocked because the composite resource is protected by the " + AnnotationKeyDeletionProtection + " annotation. Remove the annotation to finish deleting it."
+	connectionSecretsDisabledMsg = "Stopped publishing connection details because the Composition disables connection secrets. Any existing connection secret isn't deleted."
 )
 
 // Event reasons.
@@ -87,11 +112,22 @@ const (
 	reasonInit    event.Reason = "InitializeCompositeResource"
 	reasonDelete  event.Reason = "DeleteCompositeResource"
 	reasonPaused  event.Reason = "ReconciliationPaused"
+	reasonProtect event.Reason = "DeletionProtection"
+	reasonStalled event.Reason = "Stalled"
 )
 
 // Condition reasons.
 const (
 	reasonFatalError xpv1.ConditionReason = "FatalError"
+
+	// ReasonCompositionRevisionHashMismatch indicates that an XR wasn't
+	// composed because the selected composition revision's hash doesn't match
+	// the hash the XR is pinned to.
+	ReasonCompositionRevisionHashMismatch xpv1.ConditionReason = "CompositionRevisionHashMismatch"
+
+	// ReasonMaxCompositionDepthExceeded indicates that an XR wasn't composed
+	// because it's nested more deeply than the maximum composition depth.
+	ReasonMaxCompositionDepthExceeded xpv1.ConditionReason = "MaxCompositionDepthExceeded"
 )
 
 // ControllerName returns the recommended name for controllers that use this
@@ -154,11 +190,35 @@ type CompositionRequest struct {
 
 // A CompositionResult is the result of the composition process.
 type CompositionResult struct {
-	Composite         CompositeResource
-	Composed          []ComposedResource
-	ConnectionDetails managed.ConnectionDetails
-	Events            []TargetedEvent
-	Conditions        []TargetedCondition
+	Composite          CompositeResource
+	Composed           []ComposedResource
+	ConnectionDetails  managed.ConnectionDetails
+	Events             []TargetedEvent
+	CapabilityWarnings []TargetedEvent
+	Conditions         []TargetedCondition
+	PipelineResults    []PipelineResult
+	PipelineDebug      []PipelineStepDebug
+	PipelineStoppedBy  string
+}
+
+// A PipelineResultSeverity is the severity of a pipeline result.
+type PipelineResultSeverity string
+
+// Pipeline result severities.
+const (
+	PipelineResultSeverityFatal   PipelineResultSeverity = "Fatal"
+	PipelineResultSeverityWarning PipelineResultSeverity = "Warning"
+	PipelineResultSeverityNormal  PipelineResultSeverity = "Normal"
+)
+
+// A PipelineResult is a result returned by a Composition Function pipeline
+// step. Pipeline results are recorded in the XR's status so users can see
+// which step returned which result.
+type PipelineResult struct {
+	Step     string
+	Severity PipelineResultSeverity
+	Reason   string
+	Message  string
 }
 
 // A CompositionTarget is the target of a composition event or condition.
@@ -168,10 +228,11 @@ type CompositionTarget string
 const (
 	CompositionTargetComposite         CompositionTarget = "Composite"
 	CompositionTargetCompositeAndClaim CompositionTarget = "CompositeAndClaim"
+	CompositionTargetClaim             CompositionTarget = "Claim"
 )
 
 // A TargetedEvent represents an event produced by the composition process. It
-// can target either the XR only, or both the XR and the claim.
+// can target the XR only, the claim only, or both the XR and the claim.
 type TargetedEvent struct {
 	event.Event
 	Target CompositionTarget
@@ -195,7 +256,9 @@ func (e *TargetedEvent) AsDetailedEvent() event.Event {
 }
 
 // A TargetedCondition represents a condition produced by the composition
-// process. It can target either the XR only, or both the XR and the claim.
+// process. It can target either the XR only, or both the XR and the claim. A
+// condition that targets only the claim is set on both, because the claim's
+// conditions are propagated from the XR.
 type TargetedCondition struct {
 	xpv1.Condition
 	Target CompositionTarget
@@ -259,12 +322,74 @@ func WithPollIntervalHook(h PollIntervalHook) ReconcilerOption {
 // polls twice as frequently (i.e. at half the supplied interval) +/- 10% when
 // waiting for composed resources to become ready.
 func WithPollInterval(interval time.Duration) ReconcilerOption {
+	return WithJitteredPollInterval(interval, DefaultPollJitter)
+}
+
+// WithJitteredPollInterval specifies how long the Reconciler should wait
+// before queueing a new reconciliation after a successful reconcile. Each
+// poll interval is varied by a random amount, uniformly distributed between
+// plus and minus the supplied fraction of the interval. This spreads out the
+// polls of XRs that were created at the same time. Jitter only applies to
+// periodic polls - it doesn't delay reconciles triggered by changes to an XR
+// or its composed resources. A jitter of zero disables jitter.
+func WithJitteredPollInterval(interval time.Duration, jitter float64) ReconcilerOption {
 	return WithPollIntervalHook(func(_ context.Context, _ *composite.Unstructured) time.Duration {
-		// Jitter the poll interval +/- 10%.
-		return interval + time.Duration((rand.Float64()-0.5)*2*(float64(interval)*0.1)) //nolint:gosec // No need for secure randomness
+		return interval + time.Duration((rand.Float64()-0.5)*2*(float64(interval)*jitter)) //nolint:gosec // No need for secure randomness
 	})
 }
 
+// WithMinReconcileInterval specifies the minimum interval between successful
+// compositions of an XR that hasn't changed. When an XR is reconciled less than
+// the supplied interval after it was last successfully composed the Reconciler
+// doesn't compose it again, unless its spec has changed, it now uses a
+// different composition revision, or a reconcile was requested using the
+// AnnotationKeyReconcileRequestedAt annotation. This reduces load on the API
+// server when an XR is frequently reconciled due to changes that don't affect
+// it, for example changes to its composed resources' status. A zero interval,
+// the default, means an XR is composed every time it's reconciled.
+func WithMinReconcileInterval(interval time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.minReconcileInterval = interval
+	}
+}
+
+// WithReadinessStableFor specifies how long each composed resource must have
+// been continuously ready before the Reconciler considers an XR ready. A
+// composed resource that's observed to be not ready must again be ready for
+// the supplied duration. A zero duration, the default, means an XR is ready as
+// soon as all of its composed resources are. A Composition may override the
+// supplied duration.
+func WithReadinessStableFor(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.readyStableFor = d
+	}
+}
+
+// WithMaxConsecutiveFailures specifies how many consecutive times an XR may
+// fail to reconcile before the Reconciler considers it stalled. The Reconciler
+// sets the Stalled condition on a stalled XR and waits for the supplied
+// backoff before retrying it, rather than requeueing it with the usual rate
+// limited backoff. A stalled XR is no longer stalled once it reconciles
+// successfully or its spec changes. Zero failures, the default, means an XR
+// never stalls.
+func WithMaxConsecutiveFailures(n int, backoff time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxFailures = n
+		r.stalledBackoff = backoff
+	}
+}
+
+// WithMaxCompositionDepth specifies how deeply XRs may be nested under a
+// top-level XR. A top-level XR is at depth zero, and each XR labels the
+// resources it composes with its own depth plus one. The Reconciler refuses to
+// compose an XR that's nested more deeply than the maximum. Zero, the default,
+// means XRs may be nested to any depth, and their depth isn't tracked.
+func WithMaxCompositionDepth(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.maxDepth = n
+	}
+}
+
 // WithCompositionRevisionFetcher specifies how the composition to be used should be
 // fetched.
 func WithCompositionRevisionFetcher(f CompositionRevisionFetcher) ReconcilerOption {
@@ -291,6 +416,14 @@ func WithCompositeFinalizer(f resource.Finalizer) ReconcilerOption {
 	}
 }
 
+// WithProtectionFinalizer specifies which Finalizer should be used to protect
+// composites from deletion while they have the deletion protection annotation.
+func WithProtectionFinalizer(f resource.Finalizer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.protection = f
+	}
+}
+
 // WithCompositionSelector specifies how the composition to be used should be
 // selected.
 func WithCompositionSelector(s CompositionSelector) ReconcilerOption {
@@ -315,6 +448,24 @@ func WithConnectionPublishers(p ...managed.ConnectionPublisher) ReconcilerOption
 	}
 }
 
+// WithComposedResourceDeleter specifies how the Reconciler should delete
+// composed resources when their composite resource is deleted.
+func WithComposedResourceDeleter(d ComposedResourceDeleter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.ComposedResourceDeleter = d
+	}
+}
+
+// WithCompositeEventsOnClaim specifies that the Reconciler should also record
+// events that target only the composite resource on its claim, if any. Only
+// the end-user friendly message is recorded on the claim; any detail is only
+// recorded on the composite resource.
+func WithCompositeEventsOnClaim() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.compositeEventsOnClaim = true
+	}
+}
+
 // WithComposer specifies how the Reconciler should compose resources.
 func WithComposer(c Composer) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -322,6 +473,15 @@ func WithComposer(c Composer) ReconcilerOption {
 	}
 }
 
+// WithTracer specifies the OpenTelemetry tracer the Reconciler should use to
+// trace reconciles of composite resources defined by the supplied XRD.
+func WithTracer(t trace.Tracer, xrd string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.tracer = t
+		r.xrd = xrd
+	}
+}
+
 // WithWatchStarter specifies how the Reconciler should start watches for any
 // resources it composes.
 func WithWatchStarter(controllerName string, h handler.EventHandler, w WatchStarter) ReconcilerOption {
@@ -377,6 +537,7 @@ type compositeResource struct {
 	CompositionSelector
 	Configurator
 	managed.ConnectionPublisher
+	ComposedResourceDeleter
 }
 
 // NewReconciler returns a new Reconciler of composite resources.
@@ -410,8 +571,12 @@ func NewReconciler(c client.Client, of resource.CompositeKind, opts ...Reconcile
 			// never filter any keys. Is there an unfiltered variant we could
 			// use by default instead?
 			ConnectionPublisher: NewAPIFilteredSecretPublisher(c, []string{}),
+
+			ComposedResourceDeleter: NewOrphaningDeleter(c, NewPhasedDeleter(c)),
 		},
 
+		protection: resource.NewAPIFinalizer(c, protectionFinalizer),
+
 		resource: NewPTComposer(c),
 
 		// Dynamic watches are disabled by default.
@@ -419,8 +584,16 @@ func NewReconciler(c client.Client, of resource.CompositeKind, opts ...Reconcile
 
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
+		tracer: nopTracer(),
 
 		pollInterval: func(_ context.Context, _ *composite.Unstructured) time.Duration { return defaultPollInterval },
+
+		composed: newComposeTracker(),
+		ready:    newReadyTracker(),
+		failures: newFailureTracker(),
+		stopped:  newTransitionTracker(),
+		timedOut: newTransitionTracker(),
+		warned:   newTransitionTracker(),
 	}
 
 	for _, f := range opts {
@@ -439,6 +612,9 @@ type Reconciler struct {
 	revision  revision
 	composite compositeResource
 
+	// Protects composites with the deletion protection annotation.
+	protection resource.Finalizer
+
 	resource Composer
 
 	// Used to dynamically start composed resource watches.
@@ -449,11 +625,58 @@ type Reconciler struct {
 	log    logging.Logger
 	record event.Recorder
 
+	// Traces reconciles of XRs defined by the XRD.
+	tracer trace.Tracer
+	xrd    string
+
 	pollInterval PollIntervalHook
+
+	// The minimum interval between successful compositions of an unchanged
+	// XR, and when each XR was last successfully composed.
+	minReconcileInterval time.Duration
+	composed             *composeTracker
+
+	// How long composed resources must be continuously ready before the XR
+	// is considered ready, and since when each has been ready.
+	readyStableFor time.Duration
+	ready          *readyTracker
+
+	// How many consecutive failures stall an XR, how long to wait before
+	// retrying a stalled XR, and how many times each XR has failed.
+	maxFailures    int
+	stalledBackoff time.Duration
+	failures       *failureTracker
+
+	// Which pipeline step, if any, stopped each XR's Composition Function
+	// pipeline.
+	stopped *transitionTracker
+
+	// Which deletion phases of each XR that's being deleted have timed out.
+	timedOut *transitionTracker
+
+	// Which capability warnings each XR's composed resources caused when it
+	// was last composed.
+	warned *transitionTracker
+
+	// How deeply XRs may be nested under a top-level XR.
+	maxDepth int
+
+	// Whether events that target only the XR should also be recorded on the
+	// claim.
+	compositeEventsOnClaim bool
 }
 
 // Reconcile a composite resource.
-func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocognit // Reconcile methods are often very complex. Be wary.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	// Each reconcile is a trace. Function calls and composed resource
+	// applies are its child spans.
+	ctx, span := r.tracer.Start(ctx, spanReconcile, trace.WithAttributes(attrXRD.String(r.xrd), attrCompositeName.String(req.Name)))
+	result, err := r.reconcile(ctx, req)
+	endSpan(span, err)
+	return result, err
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocognit // Reconcile methods are often very complex. Be wary.
 	log := r.log.WithValues("request", req)
 	log.Debug("Reconciling")
 
@@ -462,10 +685,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	xr := composite.New(composite.WithGroupVersionKind(r.gvk))
 	if err := r.client.Get(ctx, req.NamespacedName, xr); err != nil {
+		if kerrors.IsNotFound(err) {
+			r.composed.Forget(req.NamespacedName)
+			r.ready.Forget(req.NamespacedName)
+			r.stopped.Forget(req.NamespacedName)
+			r.timedOut.Forget(req.NamespacedName)
+			r.warned.Forget(req.NamespacedName)
+			r.failures.Forget(req.NamespacedName)
+		}
 		log.Debug(errGet, "error", err)
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGet)
 	}
 
+	// We only update the XR's status if it changed meaningfully since we
+	// read it, to avoid needlessly writing to the API server.
+	origXR := xr.Unstructured.DeepCopy()
+
 	log = log.WithValues(
 		"uid", xr.GetUID(),
 		"version", xr.GetResourceVersion(),
@@ -475,22 +710,84 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// Check the pause annotation and return if it has the value "true"
 	// after logging, publishing an event and updating the SYNC status condition
 	if meta.IsPaused(xr) {
+		r.composed.Forget(req.NamespacedName)
+		r.ready.Forget(req.NamespacedName)
+		r.stopped.Forget(req.NamespacedName)
+		r.warned.Forget(req.NamespacedName)
+		r.failures.Forget(req.NamespacedName)
 		r.record.Event(xr, event.Normal(reasonPaused, "Reconciliation is paused via the pause annotation"))
 		xr.SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
 		// If the pause annotation is removed, we will have a chance to reconcile again and resume
 		// and if status update fails, we will reconcile again to retry to update the status
-		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return reconcile.Result{}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
 	}
 
 	if meta.WasDeleted(xr) {
 		log = log.WithValues("deletion-timestamp", xr.GetDeletionTimestamp())
+		r.composed.Forget(req.NamespacedName)
+		r.ready.Forget(req.NamespacedName)
+		r.stopped.Forget(req.NamespacedName)
+		r.warned.Forget(req.NamespacedName)
+		r.failures.Forget(req.NamespacedName)
 
 		xr.SetConditions(xpv1.Deleting())
+
+		// A protected XR isn't deleted until it's unprotected. We don't
+		// requeue; removing the annotation will trigger a reconcile.
+		if IsDeletionProtected(xr) {
+			log.Debug("Composite resource is protected from deletion")
+			r.record.Event(xr, event.Warning(reasonProtect, errors.New(deletionProtectedMsg)))
+			xr.SetConditions(xpv1.Deleting().WithMessage(deletionProtectedMsg), xpv1.ReconcileSuccess())
+			return reconcile.Result{Requeue: false}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
+		}
+
+		if err := r.protection.RemoveFinalizer(ctx, xr); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errRemoveProtection)
+			r.record.Event(xr, event.Warning(reasonDelete, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
+		}
+
+		dr, err := r.composite.DeleteComposedResources(ctx, xr)
+		for _, e := range dr.Events {
+			r.record.Event(xr, e)
+		}
+		if err != nil {
+			err = errors.Wrap(err, errDeleteComposedResources)
+			r.record.Event(xr, event.Warning(reasonDelete, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
+		}
+
+		// A deletion phase that timed out stays timed out, so we only tell
+		// the user when it first times out.
+		phases := make([]string, len(dr.TimedOut))
+		for i, t := range dr.TimedOut {
+			phases[i] = strconv.Itoa(t.Phase)
+		}
+		timedOut := r.timedOut.Observe(xr, phases...)
+		for i, t := range dr.TimedOut {
+			if timedOut[phases[i]] {
+				r.record.Event(xr, t.Event)
+			}
+		}
+
+		if !dr.Complete {
+			// This requeue is subject to rate limiting. Requeues will
+			// exponentially backoff from 1 to 30 seconds.
+			log.Debug("Waiting for composed resources to be deleted", "phase", dr.Phase, "resources", dr.Waiting)
+			xr.SetConditions(xpv1.Deleting().WithMessage(dr.WaitingMessage()), xpv1.ReconcileSuccess())
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
+		}
+
 		if err := r.composite.UnpublishConnection(ctx, xr, nil); err != nil {
 			err = errors.Wrap(err, errUnpublish)
 			r.record.Event(xr, event.Warning(reasonDelete, err))
 			xr.SetConditions(xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
 		}
 
 		if err := r.composite.RemoveFinalizer(ctx, xr); err != nil {
@@ -500,12 +797,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			err = errors.Wrap(err, errRemoveFinalizer)
 			r.record.Event(xr, event.Warning(reasonDelete, err))
 			xr.SetConditions(xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
 		}
 
 		log.Debug("Successfully deleted composite resource")
 		xr.SetConditions(xpv1.ReconcileSuccess())
-		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
 	}
 
 	if err := r.composite.AddFinalizer(ctx, xr); err != nil {
@@ -515,7 +812,40 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		err = errors.Wrap(err, errAddFinalizer)
 		r.record.Event(xr, event.Warning(reasonInit, err))
 		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return r.failed(ctx, origXR, xr)
+	}
+
+	// Protect the XR from deletion while it has the protection annotation.
+	protect, errProtect := r.protection.RemoveFinalizer, errRemoveProtection
+	if IsDeletionProtected(xr) {
+		protect, errProtect = r.protection.AddFinalizer, errAddProtection
+	}
+	if err := protect(ctx, xr); err != nil {
+		if kerrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		err = errors.Wrap(err, errProtect)
+		r.record.Event(xr, event.Warning(reasonInit, err))
+		xr.SetConditions(xpv1.ReconcileError(err))
+		return r.failed(ctx, origXR, xr)
+	}
+
+	// Start tracking the depth of a top-level XR, so the resources it composes
+	// are labelled with theirs. There's no need to requeue an XR that's
+	// nested too deeply. Changing the XR or its parent will requeue it.
+	if r.maxDepth > 0 {
+		if !IsCompositionDepthTracked(xr) {
+			meta.AddLabels(xr, map[string]string{xcrd.LabelKeyCompositionDepth: "0"})
+		}
+		if d := GetCompositionDepth(xr); d > r.maxDepth {
+			err := errors.Errorf(errFmtMaxCompositionDepth, d, r.maxDepth)
+			log.Debug("Composite resource is nested too deeply", "error", err)
+			r.record.Event(xr, event.Warning(reasonCompose, err))
+			c := xpv1.ReconcileError(err)
+			c.Reason = ReasonMaxCompositionDepthExceeded
+			xr.SetConditions(c)
+			return reconcile.Result{Requeue: false}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
+		}
 	}
 
 	orig := xr.GetCompositionReference()
@@ -523,7 +853,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		err = errors.Wrap(err, errSelectComp)
 		r.record.Event(xr, event.Warning(reasonResolve, err))
 		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return r.failed(ctx, origXR, xr)
 	}
 	if compRef := xr.GetCompositionReference(); compRef != nil && (orig == nil || *compRef != *orig) {
 		r.record.Event(xr, event.Normal(reasonResolve, fmt.Sprintf("Successfully selected composition: %s", compRef.Name)))
@@ -537,12 +867,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		err = errors.Wrap(err, errFetchComp)
 		r.record.Event(xr, event.Warning(reasonCompose, err))
 		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return r.failed(ctx, origXR, xr)
 	}
+	trace.SpanFromContext(ctx).SetAttributes(attrComposition.String(rev.GetLabels()[v1.LabelCompositionName]), attrCompositionRevision.String(rev.GetName()))
 	if rev := xr.GetCompositionRevisionReference(); rev != nil && (origRev == nil || *rev != *origRev) {
 		r.record.Event(xr, event.Normal(reasonResolve, fmt.Sprintf("Selected composition revision: %s", rev.Name)))
 	}
 
+	// Don't compose an XR that's pinned to a composition revision hash using a
+	// revision with different content. This surfaces out-of-band changes to
+	// the Composition instead of silently applying them.
+	if pinned := GetCompositionRevisionHash(xr); pinned != "" {
+		if hash := rev.GetLabels()[v1.LabelCompositionHash]; hash != pinned {
+			err := errors.Errorf(errFmtCompositionRevisionHash, rev.GetName(), hash, pinned)
+			log.Debug("Composition revision hash mismatch", "error", err)
+			r.record.Event(xr, event.Warning(reasonCompose, err))
+			c := xpv1.ReconcileError(err)
+			c.Reason = ReasonCompositionRevisionHashMismatch
+			xr.SetConditions(c)
+			return r.failed(ctx, origXR, xr)
+		}
+	}
+
 	// TODO(negz): Update this to validate the revision? In practice that's what
 	// it's doing today when revis are enabled.
 	if err := r.revision.Validate(rev); err != nil {
@@ -550,7 +896,32 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		err = errors.Wrap(err, errValidate)
 		r.record.Event(xr, event.Warning(reasonCompose, err))
 		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return r.failed(ctx, origXR, xr)
+	}
+
+	// Don't compose an unchanged XR again if it was successfully composed
+	// less than the minimum reconcile interval ago, unless a reconcile was
+	// explicitly requested.
+	requestedAt, requested := GetReconcileRequest(xr)
+	if r.minReconcileInterval > 0 && !requested {
+		if remaining := r.composed.Remaining(xr, rev, r.minReconcileInterval, time.Now()); remaining > 0 {
+			log.Debug("Skipping composition of unchanged composite resource within minimum reconcile interval", "requeue-after", remaining)
+			return reconcile.Result{RequeueAfter: remaining}, nil
+		}
+	}
+
+	// Acknowledge the reconcile request. The acknowledgement is persisted by
+	// whichever status update ends this reconcile, so each distinct request
+	// is handled once.
+	if requested {
+		log.Debug("Handling requested reconcile", "requested-at", requestedAt)
+		if err := SetLastHandledReconcileAt(xr, requestedAt); err != nil {
+			log.Debug(errHandleReconcileRequest, "error", err)
+			err = errors.Wrap(err, errHandleReconcileRequest)
+			r.record.Event(xr, event.Warning(reasonCompose, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return r.failed(ctx, origXR, xr)
+		}
 	}
 
 	if err := r.composite.Configure(ctx, xr, rev); err != nil {
@@ -561,7 +932,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		err = errors.Wrap(err, errConfigure)
 		r.record.Event(xr, event.Warning(reasonCompose, err))
 		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return r.failed(ctx, origXR, xr)
 	}
 
 	res, err := r.resource.Compose(ctx, xr, CompositionRequest{Revision: rev})
@@ -600,7 +971,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			}
 		}
 
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return r.failed(ctx, origXR, xr)
 	}
 
 	ws := make([]engine.Watch, len(xr.GetResourceReferences()))
@@ -616,25 +987,84 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log.Debug("Cannot start watches for composed resources. Relying on polling to know when they change.", "controller-name", r.controllerName, "error", err)
 	}
 
-	published, err := r.composite.PublishConnection(ctx, xr, res.ConnectionDetails)
-	if err != nil {
-		log.Debug(errPublish, "error", err)
-		if kerrors.IsConflict(err) {
-			return reconcile.Result{Requeue: true}, nil
+	if rev.Spec.DisableConnectionSecrets {
+		// The composition doesn't want connection secrets. We ignore any
+		// secret reference the XR has, rather than rejecting it, but warn
+		// that it's ignored. We don't delete any connection secret the XR
+		// already published. It's owned by the XR, so it's garbage collected
+		// when the XR is deleted.
+		if xr.GetWriteConnectionSecretToReference() != nil {
+			log.Debug(connectionSecretsDisabledMsg)
+			r.record.Event(xr, event.Warning(reasonPublish, errors.New(connectionSecretsDisabledMsg)))
+		}
+		if xr.GetConnectionDetailsLastPublishedTime() != nil {
+			xr.SetConnectionDetailsLastPublishedTime(nil)
+		}
+	} else {
+		xcd, err := ExtractCompositeConnectionDetails(xr, rev.Spec.CompositeConnectionDetails)
+		if err != nil {
+			log.Debug(errExtractCompositeConn, "error", err)
+			err = errors.Wrap(err, errExtractCompositeConn)
+			r.record.Event(xr, event.Warning(reasonPublish, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return r.failed(ctx, origXR, xr)
+		}
+
+		// Connection details from composed resources take precedence over
+		// those read from the XR. Both are subject to the publisher's filter.
+		cd := managed.ConnectionDetails{}
+		for k, v := range xcd {
+			cd[k] = v
+		}
+		for k, v := range res.ConnectionDetails {
+			cd[k] = v
+		}
+
+		published, err := r.composite.PublishConnection(ctx, xr, cd)
+		if err != nil {
+			log.Debug(errPublish, "error", err)
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errPublish)
+			r.record.Event(xr, event.Warning(reasonPublish, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return r.failed(ctx, origXR, xr)
+		}
+		if published {
+			xr.SetConnectionDetailsLastPublishedTime(&metav1.Time{Time: time.Now()})
+			log.Debug("Successfully published connection details")
+			r.record.Event(xr, event.Normal(reasonPublish, "Successfully published connection details"))
 		}
-		err = errors.Wrap(err, errPublish)
-		r.record.Event(xr, event.Warning(reasonPublish, err))
-		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
 	}
-	if published {
-		xr.SetConnectionDetailsLastPublishedTime(&metav1.Time{Time: time.Now()})
-		log.Debug("Successfully published connection details")
-		r.record.Event(xr, event.Normal(reasonPublish, "Successfully published connection details"))
+
+	// Composed resources that require capabilities their provider doesn't
+	// declare will usually require them every time we compose, so we only
+	// warn when a warning first appears.
+	warnings := make([]string, len(res.CapabilityWarnings))
+	for i, e := range res.CapabilityWarnings {
+		warnings[i] = e.Event.Message
+	}
+	warned := r.warned.Observe(xr, warnings...)
+	for _, e := range res.CapabilityWarnings {
+		if warned[e.Event.Message] {
+			res.Events = append(res.Events, e)
+		}
 	}
 
 	meta := r.handleCommonCompositionResult(ctx, res, xr)
 
+	// A pipeline that's stopped early by a step will usually be stopped by
+	// the same step every time it runs, so we only tell the user when that
+	// changes.
+	var stopped []string
+	if res.PipelineStoppedBy != "" {
+		stopped = append(stopped, res.PipelineStoppedBy)
+	}
+	if r.stopped.Observe(xr, stopped...)[res.PipelineStoppedBy] {
+		r.record.Event(xr, event.Normal(reasonCompose, fmt.Sprintf("Pipeline step %q stopped the pipeline", res.PipelineStoppedBy)))
+	}
+
 	if meta.numWarningEvents == 0 {
 		// We don't consider warnings severe enough to prevent the XR from being
 		// considered synced (i.e. severe enough to return a ReconcileError) but
@@ -645,6 +1075,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	var unready []ComposedResource
 	var unsynced []ComposedResource
+	var notReadyIDs []string
 	for i, cd := range res.Composed {
 		// Specifying a name for P&T templates is optional but encouraged.
 		// If there was no name, fall back to using the index.
@@ -662,27 +1093,121 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		if !cd.Ready {
 			log.Debug("Composed resource is not yet ready", "id", id)
 			unready = append(unready, cd)
+			notReadyIDs = append(notReadyIDs, id)
 			r.record.Event(xr, event.Normal(reasonCompose, fmt.Sprintf("Composed resource %q is not yet ready", id)))
 		}
 	}
 
-	if updateXRConditions(xr, unsynced, unready, res) {
+	// The Composition may override how long composed resources must be
+	// ready. We don't emit an event for unstable composed resources - they're
+	// reported by the XR's Ready condition.
+	stableFor := r.readyStableFor
+	if rev.Spec.ReadinessStableFor != nil {
+		stableFor = rev.Spec.ReadinessStableFor.Duration
+	}
+	var unstable []ComposedResource
+	var stableIn time.Duration
+	if stableFor > 0 {
+		unstable, stableIn = r.ready.Unstable(xr, res.Composed, stableFor, time.Now())
+		for _, cd := range unstable {
+			log.Debug("Composed resource is ready, but not yet stable", "id", cd.ResourceName, "stable-for", stableFor)
+			notReadyIDs = append(notReadyIDs, string(cd.ResourceName))
+		}
+	} else {
+		r.ready.Forget(req.NamespacedName)
+	}
+
+	// Record readiness so the claim controller can tell the claim's users
+	// what's still in progress.
+	if err := setResourceReadiness(xr, len(res.Composed), notReadyIDs); err != nil {
+		log.Debug(errSetResourceReadiness, "error", err)
+	}
+
+	// The XR reconciled successfully, so it's no longer stalled.
+	r.failures.Forget(req.NamespacedName)
+	if IsStalled(xr) {
+		xr.SetConditions(NotStalled(ReasonRecovered))
+	}
+
+	if updateXRConditions(xr, unsynced, unready, unstable, res, ptr.Deref(rev.Spec.CompositeReadiness, v1.CompositeReadinessComposed)) {
 		// This requeue is subject to rate limiting. Requeues will exponentially
 		// backoff from 1 to 30 seconds. See the 'definition' (XRD) reconciler
 		// that sets up the ratelimiter.
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, origXR, xr), errUpdateStatus)
+	}
+
+	if err := r.updateStatus(ctx, origXR, xr); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+	if r.minReconcileInterval > 0 {
+		r.composed.Record(xr, rev, time.Now())
 	}
 
 	// We requeue after our poll interval because we can't watch composed
 	// resources - we can't know what type of resources we might compose
 	// when this controller is started.
-	return reconcile.Result{RequeueAfter: r.pollInterval(ctx, xr)}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+	requeueAfter := r.pollInterval(ctx, xr)
+
+	// Check again once the first of our unstable composed resources should
+	// have been ready for long enough, if that's sooner than our next poll.
+	if len(unstable) > 0 {
+		requeueAfter = min(stableIn, requeueAfter)
+	}
+
+	// Check again once pipeline debugging stops, so that we remove the debug
+	// records from the XR's status.
+	if remaining, ok := PipelineDebugRemaining(xr, time.Now()); ok {
+		requeueAfter = min(remaining, requeueAfter)
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// failed records that the supplied XR failed to reconcile, and updates its
+// status. The XR is requeued subject to rate limiting, unless it has failed too
+// many consecutive times. A stalled XR is requeued after the stalled backoff.
+func (r *Reconciler) failed(ctx context.Context, orig *unstructured.Unstructured, xr *composite.Unstructured) (reconcile.Result, error) {
+	// We report most errors using the Synced condition rather than by
+	// returning them, so that's where we find the reconcile's error.
+	trace.SpanFromContext(ctx).SetStatus(codes.Error, xr.GetCondition(xpv1.TypeSynced).Message)
+
+	if r.maxFailures < 1 {
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, orig, xr), errUpdateStatus)
+	}
+
+	n := r.failures.Fail(xr)
+	if n < r.maxFailures {
+		// A stalled XR that fails fewer times than the maximum must have
+		// started counting again because its spec changed.
+		if IsStalled(xr) {
+			xr.SetConditions(NotStalled(ReasonSpecChanged))
+		}
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, orig, xr), errUpdateStatus)
+	}
+
+	if !IsStalled(xr) {
+		r.log.Debug("Composite resource is stalled", "name", xr.GetName(), "failures", n, "requeue-after", r.stalledBackoff)
+		r.record.Event(xr, event.Warning(reasonStalled, errors.Errorf("failed to reconcile %d consecutive times", n)))
+	}
+	// Use the maximum rather than the actual number of failures so that the
+	// condition, and thus the XR's status, doesn't change on each failure.
+	xr.SetConditions(Stalled(r.maxFailures))
+	return reconcile.Result{RequeueAfter: r.stalledBackoff}, errors.Wrap(r.updateStatus(ctx, orig, xr), errUpdateStatus)
+}
+
+// updateStatus updates the supplied XR's status, unless it hasn't changed
+// meaningfully since the supplied original XR was read.
+func (r *Reconciler) updateStatus(ctx context.Context, orig *unstructured.Unstructured, xr *composite.Unstructured) error {
+	if !statusChanged(orig, xr) {
+		return nil
+	}
+	return r.client.Status().Update(ctx, xr)
 }
 
 // updateXRConditions updates the conditions of the supplied composite resource
-// based on the supplied composed resources. It returns true if the XR should be
-// requeued immediately.
-func updateXRConditions(xr *composite.Unstructured, unsynced, unready []ComposedResource, res CompositionResult) (requeueImmediately bool) {
+// based on the supplied composed resources and readiness policy. It returns
+// true if the XR should be requeued immediately.
+func updateXRConditions(xr *composite.Unstructured, unsynced, unready, unstable []ComposedResource, res CompositionResult, p v1.CompositeReadinessPolicy) (requeueImmediately bool) {
 	readyCond := xpv1.Available()
 	syncedCond := xpv1.ReconcileSuccess()
 	if len(unsynced) > 0 {
@@ -697,6 +1222,11 @@ func updateXRConditions(xr *composite.Unstructured, unsynced, unready []Composed
 		readyCond = xpv1.Creating().WithMessage(fmt.Sprintf("Unready resources: %s", resource.StableNAndSomeMore(resource.DefaultFirstN, getComposerResourcesNames(unready))))
 		requeueImmediately = true
 	}
+	if len(unready) == 0 && len(unstable) > 0 {
+		// We don't requeue immediately here. The caller knows when these
+		// composed resources will have been ready for long enough.
+		readyCond = xpv1.Creating().WithMessage(fmt.Sprintf("Resources not yet stable: %s", resource.StableNAndSomeMore(resource.DefaultFirstN, getComposerResourcesNames(unstable))))
+	}
 	if res.Composite.Ready != nil {
 		if *res.Composite.Ready {
 			readyCond = xpv1.Available()
@@ -706,10 +1236,41 @@ func updateXRConditions(xr *composite.Unstructured, unsynced, unready []Composed
 			readyCond = xpv1.Creating().WithMessage("Composite resource was explicitly marked as unready by the composer")
 		}
 	}
+	if res.Composite.Ready == nil && p == v1.CompositeReadinessExplicit && readyCond.Status != corev1.ConditionFalse {
+		// An XR that requires explicit readiness isn't ready just because
+		// its composed resources are.
+		readyCond = xpv1.Creating().WithMessage("Waiting for the composer to explicitly mark the composite resource as ready")
+	}
 	xr.SetConditions(syncedCond, readyCond)
 	return requeueImmediately
 }
 
+// setResourceReadiness records how many of the supplied XR's composed
+// resources are ready, and the names of those that aren't, in its status.
+// Composed resources that are ready but not yet stable count as not ready.
+// Readiness isn't recorded for an XR with no composed resources.
+func setResourceReadiness(xr *composite.Unstructured, total int, notReady []string) error {
+	p := fieldpath.Pave(xr.Object)
+	if total == 0 {
+		return p.DeleteField("status.resourceReadiness")
+	}
+	rr := map[string]any{
+		"ready": int64(total - len(notReady)),
+		"total": int64(total),
+	}
+	if len(notReady) > 0 {
+		sorted := make([]string, len(notReady))
+		copy(sorted, notReady)
+		sort.Strings(sorted)
+		names := make([]any, len(sorted))
+		for i, n := range sorted {
+			names[i] = n
+		}
+		rr["unready"] = names
+	}
+	return p.SetValue("status.resourceReadiness", rr)
+}
+
 func getComposerResourcesNames(cds []ComposedResource) []string {
 	names := make([]string, len(cds))
 	for i, cd := range cds {
@@ -743,13 +1304,38 @@ func (r *Reconciler) handleCommonCompositionResult(ctx context.Context, res Comp
 
 		detailedEvent := e.AsDetailedEvent()
 		log.Debug(detailedEvent.Message)
-		r.record.Event(xr, detailedEvent)
 
-		if e.Target == CompositionTargetCompositeAndClaim && cm != nil {
+		// Events that target only the claim are recorded on the XR when
+		// there is no claim, so that they're not lost.
+		if e.Target != CompositionTargetClaim || cm == nil {
+			r.record.Event(xr, detailedEvent)
+		}
+
+		if cm == nil {
+			continue
+		}
+
+		switch e.Target {
+		case CompositionTargetClaim, CompositionTargetCompositeAndClaim:
 			r.record.Event(cm, e.AsEvent())
+		case CompositionTargetComposite:
+			if r.compositeEventsOnClaim {
+				r.record.Event(cm, e.AsEvent())
+			}
 		}
 	}
 
+	// Pipeline results are only retained until the next reconcile.
+	if err := SetPipelineResults(xr, res.PipelineResults); err != nil {
+		log.Debug(errSetPipelineResults, "error", err)
+	}
+
+	// Pipeline debug records are only retained until the next reconcile, and
+	// only recorded while debugging is enabled.
+	if err := SetPipelineDebug(xr, res.PipelineDebug); err != nil {
+		log.Debug(errSetPipelineDebug, "error", err)
+	}
+
 	conditionTypesSeen := make(map[xpv1.ConditionType]bool)
 	for _, c := range res.Conditions {
 		if xpv1.IsSystemConditionType(c.Condition.Type) {
@@ -758,7 +1344,7 @@ func (r *Reconciler) handleCommonCompositionResult(ctx context.Context, res Comp
 		}
 		conditionTypesSeen[c.Condition.Type] = true
 		xr.SetConditions(c.Condition)
-		if c.Target == CompositionTargetCompositeAndClaim {
+		if c.Target == CompositionTargetCompositeAndClaim || c.Target == CompositionTargetClaim {
 			// We can ignore the error as it only occurs if given a system condition.
 			_ = xr.SetClaimConditionTypes(c.Condition.Type)
 		}
@@ -29,12 +29,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -46,6 +48,7 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/engine"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 var _ Composer = ComposerSelectorFn(func(_ *v1.CompositionMode) Composer { return nil })
@@ -93,6 +96,153 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errGet),
 			},
 		},
+		"DeletionProtected": {
+			reason: "We shouldn't delete composed resources while the composite resource is protected from deletion.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetDeletionTimestamp(&now)
+						meta.AddAnnotations(cr, map[string]string{AnnotationKeyDeletionProtection: "true"})
+					})),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+						want.SetDeletionTimestamp(&now)
+						meta.AddAnnotations(want, map[string]string{AnnotationKeyDeletionProtection: "true"})
+						want.SetConditions(xpv1.Deleting().WithMessage(deletionProtectedMsg), xpv1.ReconcileSuccess())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithProtectionFinalizer(resource.FinalizerFns{
+						RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							t.Errorf("We shouldn't remove the protection finalizer while the composite resource is protected")
+							return nil
+						},
+					}),
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(_ context.Context, _ *composite.Unstructured) (DeletionResult, error) {
+						t.Errorf("We shouldn't delete composed resources while the composite resource is protected")
+						return DeletionResult{}, nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"RemoveProtectionFinalizerOnDeleteError": {
+			reason: "We should return any error encountered while removing the protection finalizer from an unprotected composite resource that's being deleted.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetDeletionTimestamp(&now)
+					})),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+						want.SetDeletionTimestamp(&now)
+						want.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(errors.Wrap(errBoom, errRemoveProtection)))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithProtectionFinalizer(resource.FinalizerFns{
+						RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							return errBoom
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"DeleteComposedResourcesError": {
+			reason: "We should return any error encountered while deleting composed resources.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetDeletionTimestamp(&now)
+					})),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+						want.SetDeletionTimestamp(&now)
+						want.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(errors.Wrap(errBoom, errDeleteComposedResources)))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(_ context.Context, _ *composite.Unstructured) (DeletionResult, error) {
+						return DeletionResult{}, errBoom
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"WaitingForComposedResourceDeletion": {
+			reason: "We should requeue and report which composed resources we're waiting for if a deletion phase is in progress.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetDeletionTimestamp(&now)
+					})),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+						want.SetDeletionTimestamp(&now)
+						want.SetConditions(xpv1.Deleting().WithMessage("Waiting for composed resources to be deleted in deletion phase 1: app, cache"), xpv1.ReconcileSuccess())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.FinalizerFns{
+						RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							t.Errorf("We shouldn't remove the finalizer while composed resources are being deleted")
+							return nil
+						},
+					}),
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(_ context.Context, _ *composite.Unstructured) (DeletionResult, error) {
+						return DeletionResult{Phase: 1, Waiting: []ResourceName{"app", "cache"}}, nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"DeletionPhaseTimedOut": {
+			reason: "We should tell the user when a deletion phase times out.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetDeletionTimestamp(&now)
+					})),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+						want.SetDeletionTimestamp(&now)
+						want.SetConditions(xpv1.Deleting().WithMessage("Waiting for composed resources to be deleted in deletion phase 2: database"), xpv1.ReconcileSuccess())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder(
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeWarning,
+								Reason:      reasonDelete,
+								Message:     "phase 1 timed out",
+								Annotations: map[string]string{},
+							},
+						},
+					)),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(_ context.Context, _ *composite.Unstructured) (DeletionResult, error) {
+						return DeletionResult{
+							Phase:   2,
+							Waiting: []ResourceName{"database"},
+							TimedOut: []DeletionPhaseTimeout{{
+								Phase: 1,
+								Event: event.Warning(reasonDelete, errors.New("phase 1 timed out")),
+							}},
+						}, nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"UnpublishConnectionError": {
 			reason: "We should return any error encountered while unpublishing connection details.",
 			args: args{
@@ -197,6 +347,53 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"AddProtectionFinalizerError": {
+			reason: "We should return any error encountered while adding the protection finalizer to a protected composite resource.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+						meta.AddAnnotations(cr, map[string]string{AnnotationKeyDeletionProtection: "true"})
+					})),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+						meta.AddAnnotations(want, map[string]string{AnnotationKeyDeletionProtection: "true"})
+						want.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errAddProtection)))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithProtectionFinalizer(resource.FinalizerFns{
+						AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							return errBoom
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"RemoveProtectionFinalizerError": {
+			reason: "We should return any error encountered while removing the protection finalizer from an unprotected composite resource.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+						want.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errRemoveProtection)))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithProtectionFinalizer(resource.FinalizerFns{
+						RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error {
+							return errBoom
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"SelectCompositionError": {
 			reason: "We should return any error encountered while selecting a composition.",
 			args: args{
@@ -217,6 +414,56 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"SelectCompositionErrorStalled": {
+			reason: "We should mark the composite resource stalled and back off once it has failed the maximum number of consecutive times.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errSelectComp)))
+						cr.SetConditions(Stalled(1))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, _ resource.Composite) error {
+						return errBoom
+					})),
+					WithMaxConsecutiveFailures(1, 10*time.Minute),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 10 * time.Minute},
+			},
+		},
+		"MaxCompositionDepthExceeded": {
+			reason: "We should refuse to compose an XR that's nested more deeply than the maximum composition depth.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetLabels(map[string]string{xcrd.LabelKeyCompositionDepth: "3"})
+						return nil
+					}),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetLabels(map[string]string{xcrd.LabelKeyCompositionDepth: "3"})
+						c := xpv1.ReconcileError(errors.Errorf(errFmtMaxCompositionDepth, 3, 2))
+						c.Reason = ReasonMaxCompositionDepthExceeded
+						cr.SetConditions(c)
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, _ resource.Composite) error {
+						t.Errorf("We should not select a composition for an XR that's nested too deeply")
+						return nil
+					})),
+					WithMaxCompositionDepth(2),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"FetchCompositionError": {
 			reason: "We should return any error encountered while fetching a composition.",
 			args: args{
@@ -270,6 +517,44 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"CompositionRevisionHashMismatch": {
+			reason: "We should refuse to compose an XR pinned to a composition revision hash that doesn't match the selected revision's.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						_ = fieldpath.Pave(obj.(*composite.Unstructured).Object).SetValue("spec.compositionRevisionHash", "pinned")
+						return nil
+					}),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						_ = fieldpath.Pave(cr.(*composite.Unstructured).Object).SetValue("spec.compositionRevisionHash", "pinned")
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						c := xpv1.ReconcileError(errors.Errorf(errFmtCompositionRevisionHash, "cool-rev", "changed", "pinned"))
+						c.Reason = ReasonCompositionRevisionHashMismatch
+						cr.SetConditions(c)
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{ObjectMeta: metav1.ObjectMeta{
+							Name:   "cool-rev",
+							Labels: map[string]string{v1.LabelCompositionHash: "changed"},
+						}}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error {
+						t.Errorf("We should not validate a composition revision whose hash doesn't match the pinned hash")
+						return nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"ConfigureCompositeError": {
 			reason: "We should return any error encountered while configuring the composite resource.",
 			args: args{
@@ -331,51 +616,605 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
-		"PublishConnectionDetailsError": {
-			reason: "We should return any error encountered while publishing connection details.",
+		"PublishConnectionDetailsError": {
+			reason: "We should return any error encountered while publishing connection details.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errPublish)))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							return false, errBoom
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ReconcileRequested": {
+			reason: "We should acknowledge a requested reconcile in the XR's status.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetAnnotations(map[string]string{AnnotationKeyReconcileRequestedAt: "now"})
+					})),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetAnnotations(map[string]string{AnnotationKeyReconcileRequestedAt: "now"})
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						_ = SetLastHandledReconcileAt(cr.(*composite.Unstructured), "now")
+						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errPublish)))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							return false, errBoom
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ConnectionSecretsDisabled": {
+			reason: "We should not publish connection details if the Composition disables connection secrets.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(xr resource.Composite) {
+						xr.SetCompositionReference(&corev1.ObjectReference{})
+						xr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "cool-secret"})
+						xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "cool-secret"})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{DisableConnectionSecrets: true}}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{ConnectionDetails: managed.ConnectionDetails{"cool": []byte("data")}}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							t.Error("PublishConnection(...): unexpected call")
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"ConnectionSecretsDisabledAfterPublishing": {
+			reason: "We should warn, and stop recording when connection details were published, if the Composition disables connection secrets after the XR published them.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(xr resource.Composite) {
+						xr.SetCompositionReference(&corev1.ObjectReference{})
+						xr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "cool-secret"})
+						xr.SetConnectionDetailsLastPublishedTime(nil)
+						xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder(
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      reasonResolve,
+								Message:     "Successfully selected composition: ",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeWarning,
+								Reason:      reasonPublish,
+								Message:     connectionSecretsDisabledMsg,
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      reasonCompose,
+								Message:     "Successfully composed resources",
+								Annotations: map[string]string{},
+							},
+						},
+					)),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "cool-secret"})
+						cr.SetConnectionDetailsLastPublishedTime(&metav1.Time{Time: time.Now()})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{DisableConnectionSecrets: true}}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"ConnectionSecretsDisabledNeverPublished": {
+			reason: "We should warn that the XR's connection secret reference is ignored if the Composition disables connection secrets, even if the XR never published connection details.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(xr resource.Composite) {
+						xr.SetCompositionReference(&corev1.ObjectReference{})
+						xr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "cool-secret"})
+						xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder(
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      reasonResolve,
+								Message:     "Successfully selected composition: ",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeWarning,
+								Reason:      reasonPublish,
+								Message:     connectionSecretsDisabledMsg,
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      reasonCompose,
+								Message:     "Successfully composed resources",
+								Annotations: map[string]string{},
+							},
+						},
+					)),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.SetWriteConnectionSecretToReference(&xpv1.SecretReference{Name: "cool-secret"})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{DisableConnectionSecrets: true}}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"PipelineStopped": {
+			reason: "We should tell the user which pipeline step stopped the pipeline.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(xr resource.Composite) {
+						xr.SetCompositionReference(&corev1.ObjectReference{})
+						xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder(
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      reasonResolve,
+								Message:     "Successfully selected composition: ",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      reasonCompose,
+								Message:     `Pipeline step "stop" stopped the pipeline`,
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      reasonCompose,
+								Message:     "Successfully composed resources",
+								Annotations: map[string]string{},
+							},
+						},
+					)),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{PipelineStoppedBy: "stop"}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"CapabilityWarnings": {
+			reason: "We should emit capability warnings that an XR's composed resources caused for the first time.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(xr resource.Composite) {
+						xr.SetCompositionReference(&corev1.ObjectReference{})
+						xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder(
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      reasonResolve,
+								Message:     "Successfully selected composition: ",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeWarning,
+								Reason:      reasonCompose,
+								Message:     errBoom.Error(),
+								Annotations: map[string]string{},
+							},
+						},
+					)),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{
+							CapabilityWarnings: []TargetedEvent{
+								{
+									Event:  event.Warning(reasonCompose, errBoom),
+									Target: CompositionTargetComposite,
+								},
+							},
+						}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"ExplicitReadiness": {
+			reason: "An XR that requires explicit readiness shouldn't be ready unless the composer marks it ready.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(xr resource.Composite) {
+						xr.SetCompositionReference(&corev1.ObjectReference{})
+						xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Creating().WithMessage("Waiting for the composer to explicitly mark the composite resource as ready"))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						c := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
+							CompositeReadiness: ptr.To(v1.CompositeReadinessExplicit),
+						}}
+						return c, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"CompositionWarnings": {
+			reason: "We should not requeue if our Composer returned warning events.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(xr resource.Composite) {
+						xr.SetCompositionReference(&corev1.ObjectReference{})
+						xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						c := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{
+							Events: []TargetedEvent{
+								{
+									Event:  event.Warning("Warning", errBoom),
+									Target: CompositionTargetComposite,
+								},
+							},
+						}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"ComposedResourcesNotReady": {
+			reason: "We should requeue if any of our composed resources are not yet ready.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Creating().WithMessage("Unready resources: cat, cow, elephant, and 1 more"))
+						_ = fieldpath.Pave(cr.(*composite.Unstructured).Object).SetValue("status.resourceReadiness", map[string]any{
+							"ready":   int64(2),
+							"total":   int64(6),
+							"unready": []any{"cat", "cow", "elephant", "snake"},
+						})
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						c := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{
+							Composed: []ComposedResource{{
+								ResourceName: "elephant",
+								Ready:        false,
+								Synced:       true,
+							}, {
+								ResourceName: "cow",
+								Ready:        false,
+								Synced:       true,
+							}, {
+								ResourceName: "pig",
+								Ready:        true,
+								Synced:       true,
+							}, {
+								ResourceName: "cat",
+								Ready:        false,
+								Synced:       true,
+							}, {
+								ResourceName: "dog",
+								Ready:        true,
+								Synced:       true,
+							}, {
+								ResourceName: "snake",
+								Ready:        false,
+								Synced:       true,
+							}},
+						}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ComposedResourcesNotYetStable": {
+			reason: "We should requeue no later than our poll interval if our composed resources haven't been ready for long enough.",
 			args: args{
 				client: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
 					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
-						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errPublish)))
+						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Creating().WithMessage("Resources not yet stable: cat"))
+						_ = fieldpath.Pave(cr.(*composite.Unstructured).Object).SetValue("status.resourceReadiness", map[string]any{
+							"ready":   int64(0),
+							"total":   int64(1),
+							"unready": []any{"cat"},
+						})
 					})),
 				},
 				opts: []ReconcilerOption{
+					WithReadinessStableFor(time.Hour),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
 					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						return nil
 					})),
 					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
-						return &v1.CompositionRevision{}, nil
+						c := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
+							Resources: []v1.ComposedTemplate{{}},
+						}}
+						return c, nil
 					})),
 					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
 					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
 						return nil
 					})),
 					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
-						return CompositionResult{}, nil
+						return CompositionResult{
+							Composed: []ComposedResource{{
+								ResourceName: "cat",
+								Ready:        true,
+								Synced:       true,
+							}},
+						}, nil
 					})),
 					WithConnectionPublishers(managed.ConnectionPublisherFns{
 						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
-							return false, errBoom
+							return false, nil
 						},
 					}),
 				},
 			},
 			want: want{
-				r: reconcile.Result{Requeue: true},
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
-		"CompositionWarnings": {
-			reason: "We should not requeue if our Composer returned warning events.",
+		"CompositionReadinessStableFor": {
+			reason: "The Composition's readiness stable duration should be used if it specifies one.",
 			args: args{
 				client: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(xr resource.Composite) {
-						xr.SetCompositionReference(&corev1.ObjectReference{})
-						xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Creating().WithMessage("Resources not yet stable: cat"))
+						_ = fieldpath.Pave(cr.(*composite.Unstructured).Object).SetValue("status.resourceReadiness", map[string]any{
+							"ready":   int64(0),
+							"total":   int64(1),
+							"unready": []any{"cat"},
+						})
 					})),
 				},
 				opts: []ReconcilerOption{
@@ -386,7 +1225,8 @@ func TestReconcile(t *testing.T) {
 					})),
 					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
 						c := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
-							Resources: []v1.ComposedTemplate{{}},
+							Resources:          []v1.ComposedTemplate{{}},
+							ReadinessStableFor: &metav1.Duration{Duration: time.Hour},
 						}}
 						return c, nil
 					})),
@@ -396,12 +1236,11 @@ func TestReconcile(t *testing.T) {
 					})),
 					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
 						return CompositionResult{
-							Events: []TargetedEvent{
-								{
-									Event:  event.Warning("Warning", errBoom),
-									Target: CompositionTargetComposite,
-								},
-							},
+							Composed: []ComposedResource{{
+								ResourceName: "cat",
+								Ready:        true,
+								Synced:       true,
+							}},
 						}, nil
 					})),
 					WithConnectionPublishers(managed.ConnectionPublisherFns{
@@ -415,20 +1254,19 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
-		"ComposedResourcesNotReady": {
-			reason: "We should requeue if any of our composed resources are not yet ready.",
+		"StatusUnchanged": {
+			reason: "We shouldn't update the XR's status if it hasn't changed meaningfully, e.g. if only the order of its conditions changed.",
 			args: args{
 				client: &test.MockClient{
-					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
-						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Creating().WithMessage("Unready resources: cat, cow, elephant, and 1 more"))
+						cr.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
 					})),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
 				},
 				opts: []ReconcilerOption{
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
-					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
-						cr.SetCompositionReference(&corev1.ObjectReference{})
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, _ resource.Composite) error {
 						return nil
 					})),
 					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
@@ -442,33 +1280,7 @@ func TestReconcile(t *testing.T) {
 						return nil
 					})),
 					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
-						return CompositionResult{
-							Composed: []ComposedResource{{
-								ResourceName: "elephant",
-								Ready:        false,
-								Synced:       true,
-							}, {
-								ResourceName: "cow",
-								Ready:        false,
-								Synced:       true,
-							}, {
-								ResourceName: "pig",
-								Ready:        true,
-								Synced:       true,
-							}, {
-								ResourceName: "cat",
-								Ready:        false,
-								Synced:       true,
-							}, {
-								ResourceName: "dog",
-								Ready:        true,
-								Synced:       true,
-							}, {
-								ResourceName: "snake",
-								Ready:        false,
-								Synced:       true,
-							}},
-						}, nil
+						return CompositionResult{}, nil
 					})),
 					WithConnectionPublishers(managed.ConnectionPublisherFns{
 						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
@@ -478,7 +1290,7 @@ func TestReconcile(t *testing.T) {
 				},
 			},
 			want: want{
-				r: reconcile.Result{Requeue: true},
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
 		"ComposedResourcesReady": {
@@ -827,6 +1639,145 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
+		"ClaimTargetedEvents": {
+			reason: "We should emit events that target only the claim on the claim, and events that target only the composite on the claim when configured to.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if xr, ok := obj.(*composite.Unstructured); ok {
+							// non-nil claim ref to trigger claim Get()
+							xr.SetClaimReference(&reference.Claim{})
+							return nil
+						}
+						if cm, ok := obj.(*claim.Unstructured); ok {
+							claim.New(claim.WithGroupVersionKind(schema.GroupVersionKind{})).DeepCopyInto(cm)
+							return nil
+						}
+						return nil
+					}),
+					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.SetConditions(
+							xpv1.Condition{
+								Type:    "DatabaseReady",
+								Status:  corev1.ConditionTrue,
+								Reason:  "Available",
+								Message: "This is a condition for database availability.",
+							},
+							xpv1.ReconcileSuccess(),
+							xpv1.Available(),
+						)
+						cr.(*composite.Unstructured).SetClaimConditionTypes("DatabaseReady")
+						cr.SetClaimReference(&reference.Claim{})
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder(
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.Type(corev1.EventTypeNormal),
+								Reason:      "SelectComposition",
+								Message:     "Successfully selected composition: ",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: claimKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      "DatabaseAvailable",
+								Message:     "This is an event for database availability.",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      "SyncSuccess",
+								Message:     "Pipeline step \"some-function\": Internal sync was successful.",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: claimKind,
+							Event: event.Event{
+								Type:   event.TypeNormal,
+								Reason: "SyncSuccess",
+								// The claim should not have the "Pipeline step" prefix.
+								Message:     "Internal sync was successful.",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.Type(corev1.EventTypeNormal),
+								Reason:      "ComposeResources",
+								Message:     "Successfully composed resources",
+								Annotations: map[string]string{},
+							},
+						},
+					)),
+					WithCompositeEventsOnClaim(),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{
+							Composed:          []ComposedResource{},
+							ConnectionDetails: cd,
+							Events: []TargetedEvent{
+								{
+									Event: event.Event{
+										Type:        event.TypeNormal,
+										Reason:      "DatabaseAvailable",
+										Message:     "This is an event for database availability.",
+										Annotations: map[string]string{},
+									},
+									Detail: "Pipeline step \"some-function\"",
+									Target: CompositionTargetClaim,
+								},
+								{
+									Event: event.Event{
+										Type:        event.TypeNormal,
+										Reason:      "SyncSuccess",
+										Message:     "Internal sync was successful.",
+										Annotations: map[string]string{},
+									},
+									Detail: "Pipeline step \"some-function\"",
+									Target: CompositionTargetComposite,
+								},
+							},
+							Conditions: []TargetedCondition{
+								{
+									Condition: xpv1.Condition{
+										Type:    "DatabaseReady",
+										Status:  corev1.ConditionTrue,
+										Reason:  "Available",
+										Message: "This is a condition for database availability.",
+									},
+									Target: CompositionTargetClaim,
+								},
+							},
+						}, nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
 		"CustomEventsAndConditionFatal": {
 			reason: "In the case of a fatal result from the composer, we should set all custom conditions that were seen. If any custom conditions were not seen, they should be marked as Unknown. The error message should be emitted as an event to the composite but not the claim.",
 			args: args{
@@ -1486,3 +2437,41 @@ func newTestRecorder(expected ...eventArgs) *testRecorder {
 		Want: expected,
 	}
 }
+
+func TestWithJitteredPollInterval(t *testing.T) {
+	interval := time.Minute
+
+	cases := map[string]struct {
+		reason string
+		jitter float64
+		min    time.Duration
+		max    time.Duration
+	}{
+		"NoJitter": {
+			reason: "The poll interval shouldn't vary if jitter is zero.",
+			jitter: 0,
+			min:    interval,
+			max:    interval,
+		},
+		"Jitter": {
+			reason: "The poll interval should vary by up to plus or minus the jitter fraction of the interval.",
+			jitter: 0.1,
+			min:    54 * time.Second,
+			max:    66 * time.Second,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{}
+			WithJitteredPollInterval(interval, tc.jitter)(r)
+
+			for range 100 {
+				got := r.pollInterval(context.Background(), nil)
+				if got < tc.min || got > tc.max {
+					t.Errorf("\n%s\npollInterval(...): want between %s and %s, got %s", tc.reason, tc.min, tc.max, got)
+				}
+			}
+		})
+	}
+}
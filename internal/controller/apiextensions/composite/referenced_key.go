@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"encoding/base64"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// Error strings.
+const (
+	errGetReferencedName = "cannot get name of referenced resource"
+	errNoClaim           = "cannot reference a key unless the composite resource is bound to a claim"
+	errGetReferenced     = "cannot get referenced resource"
+
+	errFmtReferencedKind = "cannot reference a resource of kind %q"
+	errFmtReferencedKey  = "referenced %s %s/%s has no key %q"
+
+	errFmtRenderReferencedKeyPatches = "cannot render FromReferencedKey patches for composed resource %q"
+)
+
+// A ReferencedKeyFetcher fetches the value of a key of a Secret or ConfigMap
+// referenced by a composite resource.
+type ReferencedKeyFetcher interface {
+	FetchReferencedKey(ctx context.Context, xr resource.Composite, rk v1.ReferencedKey) (any, error)
+}
+
+// A ReferencedKeyFetcherFn fetches the value of a key of a Secret or ConfigMap
+// referenced by a composite resource.
+type ReferencedKeyFetcherFn func(ctx context.Context, xr resource.Composite, rk v1.ReferencedKey) (any, error)
+
+// FetchReferencedKey fetches the value of the referenced key.
+func (fn ReferencedKeyFetcherFn) FetchReferencedKey(ctx context.Context, xr resource.Composite, rk v1.ReferencedKey) (any, error) {
+	return fn(ctx, xr, rk)
+}
+
+// An APIReferencedKeyFetcher fetches referenced keys from the API server.
+type APIReferencedKeyFetcher struct {
+	client client.Reader
+}
+
+// NewAPIReferencedKeyFetcher returns a ReferencedKeyFetcher that fetches
+// referenced keys from the API server.
+func NewAPIReferencedKeyFetcher(c client.Reader) *APIReferencedKeyFetcher {
+	return &APIReferencedKeyFetcher{client: c}
+}
+
+// FetchReferencedKey fetches the value of the supplied key. The referenced
+// resource is read from the namespace of the composite resource's claim. A
+// composite resource that isn't bound to a claim can't reference keys, because
+// nothing would limit which namespaces it could read from. The value of a
+// Secret's key is returned base64 encoded, as it would appear in the data of a
+// Secret.
+func (f *APIReferencedKeyFetcher) FetchReferencedKey(ctx context.Context, xr resource.Composite, rk v1.ReferencedKey) (any, error) {
+	namespace := xr.GetLabels()[xcrd.LabelKeyClaimNamespace]
+	if namespace == "" {
+		return nil, errors.New(errNoClaim)
+	}
+
+	paved, err := fieldpath.PaveObject(xr)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := paved.GetString(rk.NameFieldPath)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetReferencedName)
+	}
+
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	switch rk.Kind {
+	case v1.ReferencedKeyKindSecret:
+		s := &corev1.Secret{}
+		if err := f.client.Get(ctx, nn, s); err != nil {
+			return nil, errors.Wrap(err, errGetReferenced)
+		}
+		v, ok := s.Data[rk.Key]
+		if !ok {
+			return nil, errors.Errorf(errFmtReferencedKey, rk.Kind, namespace, name, rk.Key)
+		}
+		return base64.StdEncoding.EncodeToString(v), nil
+	case v1.ReferencedKeyKindConfigMap:
+		cm := &corev1.ConfigMap{}
+		if err := f.client.Get(ctx, nn, cm); err != nil {
+			return nil, errors.Wrap(err, errGetReferenced)
+		}
+		v, ok := cm.Data[rk.Key]
+		if !ok {
+			return nil, errors.Errorf(errFmtReferencedKey, rk.Kind, namespace, name, rk.Key)
+		}
+		return v, nil
+	}
+	return nil, errors.Errorf(errFmtReferencedKind, rk.Kind)
+}
+
+// RenderFromReferencedKeyPatches renders the supplied composed resource by
+// applying all FromReferencedKey patches, using the supplied fetcher to read
+// the referenced keys. A patch whose referenced name field path doesn't exist
+// on the composite resource is skipped, unless its policy requires the field
+// path.
+func RenderFromReferencedKeyPatches(ctx context.Context, f ReferencedKeyFetcher, cd resource.Composed, xr resource.Composite, p []v1.Patch) error {
+	for i := range p {
+		if p[i].GetType() != v1.PatchTypeFromReferencedKey || p[i].ReferencedKey == nil {
+			continue
+		}
+		ok, err := MatchesCondition(p[i], xr)
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		if !ok {
+			continue
+		}
+		v, err := f.FetchReferencedKey(ctx, xr, *p[i].ReferencedKey)
+		if IsOptionalFieldPathNotFound(err, p[i].Policy) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+		if err := ApplyFromReferencedKeyPatch(p[i], v, cd); err != nil {
+			return errors.Wrapf(err, errFmtPatch, p[i].Type, i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+func TestFetchReferencedKey(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := func(mods ...CompositeModifier) resource.Composite {
+		return NewComposite(append([]CompositeModifier{func(cr resource.Composite) {
+			_ = fieldpath.Pave(cr.(*composite.Unstructured).Object).SetValue("spec.ref", map[string]any{"name": "cool-ref"})
+		}}, mods...)...)
+	}
+	claimed := func(cr resource.Composite) {
+		cr.SetLabels(map[string]string{xcrd.LabelKeyClaimNamespace: "claim-ns"})
+	}
+
+	get := test.NewMockGetFn(nil, func(obj client.Object) error {
+		switch o := obj.(type) {
+		case *corev1.Secret:
+			o.Data = map[string][]byte{"password": []byte("hunter2")}
+		case *corev1.ConfigMap:
+			o.Data = map[string]string{"region": "us-west-2"}
+		}
+		return nil
+	})
+
+	// Only allow reads from the claim's namespace.
+	claimGet := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		if key.Namespace != "claim-ns" {
+			return errBoom
+		}
+		return get(context.Background(), key, obj)
+	}
+
+	type args struct {
+		client client.Reader
+		xr     resource.Composite
+		rk     v1.ReferencedKey
+	}
+	type want struct {
+		value any
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ConfigMap": {
+			reason: "We should return the value of a ConfigMap's key, read from the claim's namespace.",
+			args: args{
+				client: &test.MockClient{MockGet: claimGet},
+				xr:     xr(claimed),
+				rk:     v1.ReferencedKey{Kind: v1.ReferencedKeyKindConfigMap, NameFieldPath: "spec.ref.name", Key: "region"},
+			},
+			want: want{
+				value: "us-west-2",
+			},
+		},
+		"Secret": {
+			reason: "We should return the base64 encoded value of a Secret's key, read from the claim's namespace.",
+			args: args{
+				client: &test.MockClient{MockGet: claimGet},
+				xr:     xr(claimed),
+				rk:     v1.ReferencedKey{Kind: v1.ReferencedKeyKindSecret, NameFieldPath: "spec.ref.name", Key: "password"},
+			},
+			want: want{
+				value: "aHVudGVyMg==",
+			},
+		},
+		"NoClaim": {
+			reason: "We should refuse to read a referenced key if the XR isn't bound to a claim.",
+			args: args{
+				client: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, _ client.Object) error {
+					t.Errorf("Get(...): unexpectedly read a referenced resource for an unclaimed XR")
+					return nil
+				}},
+				xr: xr(),
+				rk: v1.ReferencedKey{Kind: v1.ReferencedKeyKindSecret, NameFieldPath: "spec.ref.name", Key: "password"},
+			},
+			want: want{
+				err: errors.New(errNoClaim),
+			},
+		},
+		"GetError": {
+			reason: "We should return any error encountered getting the referenced resource.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				xr:     xr(claimed),
+				rk:     v1.ReferencedKey{Kind: v1.ReferencedKeyKindSecret, NameFieldPath: "spec.ref.name", Key: "password"},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetReferenced),
+			},
+		},
+		"MissingKey": {
+			reason: "We should return an error if the referenced resource doesn't have the key.",
+			args: args{
+				client: &test.MockClient{MockGet: get},
+				xr:     xr(claimed),
+				rk:     v1.ReferencedKey{Kind: v1.ReferencedKeyKindConfigMap, NameFieldPath: "spec.ref.name", Key: "zone"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtReferencedKey, v1.ReferencedKeyKindConfigMap, "claim-ns", "cool-ref", "zone"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := NewAPIReferencedKeyFetcher(tc.args.client)
+			v, err := f.FetchReferencedKey(context.Background(), tc.args.xr, tc.args.rk)
+
+			if diff := cmp.Diff(tc.want.value, v); diff != "" {
+				t.Errorf("\n%s\nFetchReferencedKey(...): -want value, +got value:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetchReferencedKey(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderFromReferencedKeyPatches(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cd := func(apiVersion, kind string) *composed.Unstructured {
+		return composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: apiVersion, Kind: kind}))
+	}
+	value := ReferencedKeyFetcherFn(func(_ context.Context, _ resource.Composite, _ v1.ReferencedKey) (any, error) {
+		return "aHVudGVyMg==", nil
+	})
+	secretPatch := func(to string) v1.Patch {
+		return v1.Patch{
+			Type:          v1.PatchTypeFromReferencedKey,
+			ReferencedKey: &v1.ReferencedKey{Kind: v1.ReferencedKeyKindSecret, NameFieldPath: "spec.ref.name", Key: "password"},
+			ToFieldPath:   ptr.To(to),
+		}
+	}
+
+	type args struct {
+		f  ReferencedKeyFetcher
+		cd *composed.Unstructured
+		p  []v1.Patch
+	}
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"SecretIntoSecretData": {
+			reason: "We should patch a value read from a Secret into the data of a composed Secret.",
+			args: args{
+				f:  value,
+				cd: cd("v1", "Secret"),
+				p:  []v1.Patch{secretPatch("data.password")},
+			},
+			want: want{
+				cd: func() *composed.Unstructured {
+					s := cd("v1", "Secret")
+					_ = fieldpath.Pave(s.Object).SetValue("data.password", "aHVudGVyMg==")
+					return s
+				}(),
+			},
+		},
+		"SecretIntoOtherResource": {
+			reason: "We should refuse to patch a value read from a Secret into a composed resource that isn't a Secret.",
+			args: args{
+				f:  value,
+				cd: cd("example.org/v1", "Database"),
+				p:  []v1.Patch{secretPatch("spec.password")},
+			},
+			want: want{
+				cd:  cd("example.org/v1", "Database"),
+				err: errors.Wrapf(errors.Errorf(errFmtSecretTarget, "spec.password", "Database"), errFmtPatch, v1.PatchTypeFromReferencedKey, 0),
+			},
+		},
+		"OtherPatchTypesIgnored": {
+			reason: "We should ignore patches that aren't of type FromReferencedKey.",
+			args: args{
+				f:  ReferencedKeyFetcherFn(func(_ context.Context, _ resource.Composite, _ v1.ReferencedKey) (any, error) { return nil, errBoom }),
+				cd: cd("v1", "Secret"),
+				p:  []v1.Patch{{Type: v1.PatchTypeFromCompositeFieldPath, FromFieldPath: ptr.To("spec.foo")}},
+			},
+			want: want{
+				cd: cd("v1", "Secret"),
+			},
+		},
+		"FetchError": {
+			reason: "We should return any error encountered fetching the referenced key.",
+			args: args{
+				f:  ReferencedKeyFetcherFn(func(_ context.Context, _ resource.Composite, _ v1.ReferencedKey) (any, error) { return nil, errBoom }),
+				cd: cd("v1", "Secret"),
+				p:  []v1.Patch{secretPatch("data.password")},
+			},
+			want: want{
+				cd:  cd("v1", "Secret"),
+				err: errors.Wrapf(errBoom, errFmtPatch, v1.PatchTypeFromReferencedKey, 0),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := RenderFromReferencedKeyPatches(context.Background(), tc.args.f, tc.args.cd, NewComposite(), tc.args.p)
+
+			if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+				t.Errorf("\n%s\nRenderFromReferencedKeyPatches(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRenderFromReferencedKeyPatches(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
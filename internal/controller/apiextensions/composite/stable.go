@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+// readyRecord records since when each of an XR's composed resources has been
+// continuously observed to be ready.
+type readyRecord struct {
+	uid   types.UID
+	since map[ResourceName]time.Time
+}
+
+// A readyTracker tracks how long each XR's composed resources have been
+// continuously ready, so that the Reconciler can require composed resources to
+// be stable before it considers an XR ready.
+type readyTracker struct {
+	mx      sync.Mutex
+	records map[types.NamespacedName]readyRecord
+}
+
+func newReadyTracker() *readyTracker {
+	return &readyTracker{records: make(map[types.NamespacedName]readyRecord)}
+}
+
+// Unstable observes the readiness of the supplied XR's composed resources at
+// the supplied time. It returns the composed resources that are ready, but
+// haven't been continuously ready for at least the supplied duration, and how
+// long remains until the first of them will have been.
+//
+// A composed resource's timer starts the first time it's observed to be
+// ready. Observing it to be not ready (i.e. a flap) resets its timer, which
+// starts again the next time it's observed to be ready. A flap that happens
+// entirely between two observations isn't detected.
+func (t *readyTracker) Unstable(xr *composite.Unstructured, cds []ComposedResource, stableFor time.Duration, now time.Time) ([]ComposedResource, time.Duration) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	nn := types.NamespacedName{Namespace: xr.GetNamespace(), Name: xr.GetName()}
+	r, ok := t.records[nn]
+	if !ok || r.uid != xr.GetUID() {
+		r = readyRecord{uid: xr.GetUID(), since: make(map[ResourceName]time.Time)}
+	}
+
+	// Start with an empty map so we forget resources the XR no longer
+	// composes, as well as resources that aren't ready.
+	since := make(map[ResourceName]time.Time, len(cds))
+
+	var unstable []ComposedResource
+	var remaining time.Duration
+	for _, cd := range cds {
+		if !cd.Ready {
+			continue
+		}
+		s, ok := r.since[cd.ResourceName]
+		if !ok {
+			s = now
+		}
+		since[cd.ResourceName] = s
+
+		if left := stableFor - now.Sub(s); left > 0 {
+			unstable = append(unstable, cd)
+			if remaining == 0 || left < remaining {
+				remaining = left
+			}
+		}
+	}
+
+	r.since = since
+	t.records[nn] = r
+
+	return unstable, remaining
+}
+
+// Forget any record of the named XR.
+func (t *readyTracker) Forget(nn types.NamespacedName) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	delete(t.records, nn)
+}
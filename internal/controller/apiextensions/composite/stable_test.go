@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+func TestReadyTrackerUnstable(t *testing.T) {
+	then := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newXR := func(uid types.UID) *composite.Unstructured {
+		xr := composite.New()
+		xr.SetName("cool-xr")
+		xr.SetUID(uid)
+		return xr
+	}
+
+	// An observation of the XR's composed resources at a point in time.
+	type observation struct {
+		xr  *composite.Unstructured
+		cds []ComposedResource
+		at  time.Time
+	}
+
+	type want struct {
+		unstable  []ComposedResource
+		remaining time.Duration
+	}
+
+	cases := map[string]struct {
+		reason string
+		// Earlier observations, made before the one we test.
+		earlier []observation
+		forget  bool
+		obs     observation
+		want    want
+	}{
+		"FirstObservedReady": {
+			reason: "A composed resource that's just become ready isn't stable yet.",
+			obs: observation{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+				at:  then,
+			},
+			want: want{
+				unstable:  []ComposedResource{{ResourceName: "cool", Ready: true}},
+				remaining: time.Minute,
+			},
+		},
+		"NotReady": {
+			reason: "A composed resource that isn't ready isn't considered unstable.",
+			obs: observation{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: false}},
+				at:  then,
+			},
+			want: want{},
+		},
+		"Stable": {
+			reason: "A composed resource that has been ready for long enough is stable.",
+			earlier: []observation{{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+				at:  then,
+			}},
+			obs: observation{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+				at:  then.Add(2 * time.Minute),
+			},
+			want: want{},
+		},
+		"StillStabilizing": {
+			reason: "A composed resource that hasn't been ready for long enough should report how long remains.",
+			earlier: []observation{{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}, {ResourceName: "cooler", Ready: true}},
+				at:  then,
+			}},
+			obs: observation{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}, {ResourceName: "cooler", Ready: true}, {ResourceName: "coolest", Ready: true}},
+				at:  then.Add(20 * time.Second),
+			},
+			want: want{
+				unstable:  []ComposedResource{{ResourceName: "cool", Ready: true}, {ResourceName: "cooler", Ready: true}, {ResourceName: "coolest", Ready: true}},
+				remaining: 40 * time.Second,
+			},
+		},
+		"Flapped": {
+			reason: "A composed resource that was observed to be not ready should start its timer again.",
+			earlier: []observation{
+				{
+					xr:  newXR("uid"),
+					cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+					at:  then,
+				},
+				{
+					xr:  newXR("uid"),
+					cds: []ComposedResource{{ResourceName: "cool", Ready: false}},
+					at:  then.Add(50 * time.Second),
+				},
+				{
+					xr:  newXR("uid"),
+					cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+					at:  then.Add(60 * time.Second),
+				},
+			},
+			obs: observation{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+				at:  then.Add(90 * time.Second),
+			},
+			want: want{
+				unstable:  []ComposedResource{{ResourceName: "cool", Ready: true}},
+				remaining: 30 * time.Second,
+			},
+		},
+		"Recreated": {
+			reason: "An XR that was deleted and recreated with the same name should start its timers again.",
+			earlier: []observation{{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+				at:  then,
+			}},
+			obs: observation{
+				xr:  newXR("new-uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+				at:  then.Add(2 * time.Minute),
+			},
+			want: want{
+				unstable:  []ComposedResource{{ResourceName: "cool", Ready: true}},
+				remaining: time.Minute,
+			},
+		},
+		"Forgotten": {
+			reason: "An XR that was forgotten should start its timers again.",
+			earlier: []observation{{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+				at:  then,
+			}},
+			forget: true,
+			obs: observation{
+				xr:  newXR("uid"),
+				cds: []ComposedResource{{ResourceName: "cool", Ready: true}},
+				at:  then.Add(2 * time.Minute),
+			},
+			want: want{
+				unstable:  []ComposedResource{{ResourceName: "cool", Ready: true}},
+				remaining: time.Minute,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rt := newReadyTracker()
+			for _, o := range tc.earlier {
+				rt.Unstable(o.xr, o.cds, time.Minute, o.at)
+			}
+			if tc.forget {
+				rt.Forget(types.NamespacedName{Name: "cool-xr"})
+			}
+
+			unstable, remaining := rt.Unstable(tc.obs.xr, tc.obs.cds, time.Minute, tc.obs.at)
+			if diff := cmp.Diff(tc.want.unstable, unstable); diff != "" {
+				t.Errorf("\n%s\nUnstable(...): -want unstable, +got unstable:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.remaining, remaining); diff != "" {
+				t.Errorf("\n%s\nUnstable(...): -want remaining, +got remaining:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
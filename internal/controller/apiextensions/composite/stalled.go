@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+// TypeStalled indicates whether a composite resource has failed to reconcile
+// too many consecutive times. The Reconciler retries a stalled composite
+// resource much less frequently.
+const TypeStalled xpv1.ConditionType = "Stalled"
+
+// Reasons a composite resource is or is not stalled.
+const (
+	ReasonConsecutiveFailures xpv1.ConditionReason = "ConsecutiveFailures"
+	ReasonRecovered           xpv1.ConditionReason = "Recovered"
+	ReasonSpecChanged         xpv1.ConditionReason = "SpecChanged"
+)
+
+// Stalled returns a condition that indicates a composite resource has failed
+// to reconcile at least the supplied number of consecutive times.
+func Stalled(failures int) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeStalled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConsecutiveFailures,
+		Message:            fmt.Sprintf("Failed to reconcile %d or more consecutive times. Retrying less frequently until the composite resource reconciles successfully or its spec changes.", failures),
+	}
+}
+
+// NotStalled returns a condition that indicates a composite resource is no
+// longer stalled, for the supplied reason.
+func NotStalled(r xpv1.ConditionReason) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeStalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+	}
+}
+
+// IsStalled returns true if the supplied composite resource is stalled.
+func IsStalled(xr *composite.Unstructured) bool {
+	return xr.GetCondition(TypeStalled).Status == corev1.ConditionTrue
+}
+
+// A failureRecord records how many consecutive times an XR has failed to
+// reconcile since its spec last changed.
+type failureRecord struct {
+	uid        types.UID
+	generation int64
+	failures   int
+}
+
+// A failureTracker tracks how many consecutive times each XR has failed to
+// reconcile, so that the Reconciler can stop churning on XRs that never
+// reconcile successfully.
+type failureTracker struct {
+	mx      sync.Mutex
+	records map[types.NamespacedName]failureRecord
+}
+
+func newFailureTracker() *failureTracker {
+	return &failureTracker{records: make(map[types.NamespacedName]failureRecord)}
+}
+
+// Fail records that the supplied XR failed to reconcile. It returns how many
+// consecutive times the XR has failed. The count starts again from one when
+// the XR's spec has changed since its previous failure.
+func (t *failureTracker) Fail(xr *composite.Unstructured) int {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	nn := types.NamespacedName{Namespace: xr.GetNamespace(), Name: xr.GetName()}
+	r, ok := t.records[nn]
+	if !ok || r.uid != xr.GetUID() || r.generation != xr.GetGeneration() {
+		r = failureRecord{uid: xr.GetUID(), generation: xr.GetGeneration()}
+	}
+	r.failures++
+	t.records[nn] = r
+
+	return r.failures
+}
+
+// Forget any record of the named XR, for example because it reconciled
+// successfully.
+func (t *failureTracker) Forget(nn types.NamespacedName) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	delete(t.records, nn)
+}
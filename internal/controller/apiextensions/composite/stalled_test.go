@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+func TestFailureTrackerFail(t *testing.T) {
+	newXR := func(uid types.UID, generation int64) *composite.Unstructured {
+		xr := composite.New()
+		xr.SetName("cool-xr")
+		xr.SetUID(uid)
+		xr.SetGeneration(generation)
+		return xr
+	}
+
+	cases := map[string]struct {
+		reason string
+		// Earlier failures, recorded before the one we test.
+		earlier []*composite.Unstructured
+		forget  bool
+		xr      *composite.Unstructured
+		want    int
+	}{
+		"FirstFailure": {
+			reason: "An XR that hasn't failed before has failed once.",
+			xr:     newXR("uid", 1),
+			want:   1,
+		},
+		"ConsecutiveFailures": {
+			reason: "Each failure of an unchanged XR should add to its count.",
+			earlier: []*composite.Unstructured{
+				newXR("uid", 1),
+				newXR("uid", 1),
+			},
+			xr:   newXR("uid", 1),
+			want: 3,
+		},
+		"SpecChanged": {
+			reason: "A failure after the XR's spec changed should start counting again.",
+			earlier: []*composite.Unstructured{
+				newXR("uid", 1),
+				newXR("uid", 1),
+			},
+			xr:   newXR("uid", 2),
+			want: 1,
+		},
+		"Recreated": {
+			reason: "A failure of a new XR with the same name should start counting again.",
+			earlier: []*composite.Unstructured{
+				newXR("old", 1),
+				newXR("old", 1),
+			},
+			xr:   newXR("new", 1),
+			want: 1,
+		},
+		"Forgotten": {
+			reason: "A failure after the XR was forgotten, e.g. because it succeeded, should start counting again.",
+			earlier: []*composite.Unstructured{
+				newXR("uid", 1),
+				newXR("uid", 1),
+			},
+			forget: true,
+			xr:     newXR("uid", 1),
+			want:   1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ft := newFailureTracker()
+			for _, xr := range tc.earlier {
+				ft.Fail(xr)
+			}
+			if tc.forget {
+				ft.Forget(types.NamespacedName{Name: "cool-xr"})
+			}
+
+			got := ft.Fail(tc.xr)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nFail(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+// statusChanged returns true if the supplied XR's status has changed
+// meaningfully since the supplied original XR was read from the API server.
+// An XR's status has changed meaningfully if:
+//
+//   - The XR's resource version differs from the original's. This means the
+//     XR was written since it was read, so the API server's copy of its status
+//     may no longer match the original.
+//   - The XR has a condition type that the original doesn't, or vice versa.
+//   - A condition's status, reason, message, or observed generation differs
+//     from the original condition of the same type. The order of conditions,
+//     and their last transition time, don't matter.
+//   - Any other status field's value differs from the original. The order of
+//     object keys, and how numbers are represented, don't matter.
+func statusChanged(orig *unstructured.Unstructured, xr *composite.Unstructured) bool {
+	if orig.GetResourceVersion() != xr.GetResourceVersion() {
+		return true
+	}
+
+	o := &composite.Unstructured{Unstructured: *orig}
+	if conditionsChanged(o.GetConditions(), xr.GetConditions()) {
+		return true
+	}
+
+	a, err := statusWithoutConditions(orig)
+	if err != nil {
+		return true
+	}
+	b, err := statusWithoutConditions(&xr.Unstructured)
+	if err != nil {
+		return true
+	}
+	return !bytes.Equal(a, b)
+}
+
+func conditionsChanged(orig, cs []xpv1.Condition) bool {
+	if len(orig) != len(cs) {
+		return true
+	}
+	byType := make(map[xpv1.ConditionType]xpv1.Condition, len(orig))
+	for _, c := range orig {
+		byType[c.Type] = c
+	}
+	for _, c := range cs {
+		o, ok := byType[c.Type]
+		if !ok || !o.Equal(c) || o.ObservedGeneration != c.ObservedGeneration {
+			return true
+		}
+		// Each type should appear only once. Delete it so that a duplicate
+		// type can't stand in for a missing one.
+		delete(byType, c.Type)
+	}
+	return false
+}
+
+// statusWithoutConditions returns the JSON encoding of the supplied object's
+// status, without its conditions. Maps are encoded with sorted keys, so the
+// encoding is stable.
+func statusWithoutConditions(u *unstructured.Unstructured) ([]byte, error) {
+	s := map[string]any{}
+	if err := fieldpath.Pave(u.Object).GetValueInto("status", &s); err != nil && !fieldpath.IsNotFound(err) {
+		return nil, err
+	}
+	delete(s, "conditions")
+	return json.Marshal(s)
+}
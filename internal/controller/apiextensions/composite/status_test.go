@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+func setStatusValue(cr resource.Composite, path string, v any) {
+	_ = fieldpath.Pave(cr.(*composite.Unstructured).Object).SetValue("status."+path, v)
+}
+
+func TestStatusChanged(t *testing.T) {
+	cool := func(cr resource.Composite) {
+		cr.SetResourceVersion("1")
+		cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+		setStatusValue(cr, "coolness", int64(42))
+	}
+
+	cases := map[string]struct {
+		reason string
+		orig   *composite.Unstructured
+		xr     *composite.Unstructured
+		want   bool
+	}{
+		"Unchanged": {
+			reason: "An identical status hasn't changed.",
+			orig:   NewComposite(cool),
+			xr:     NewComposite(cool),
+			want:   false,
+		},
+		"NoStatus": {
+			reason: "A missing status hasn't changed.",
+			orig:   NewComposite(),
+			xr:     NewComposite(),
+			want:   false,
+		},
+		"ConditionsReordered": {
+			reason: "Reordering conditions isn't a meaningful change.",
+			orig:   NewComposite(cool),
+			xr: NewComposite(cool, func(cr resource.Composite) {
+				setStatusValue(cr, "conditions", []any{})
+				cr.SetConditions(xpv1.Available(), xpv1.ReconcileSuccess())
+			}),
+			want: false,
+		},
+		"LastTransitionTimeChanged": {
+			reason: "Changing only a condition's last transition time isn't a meaningful change.",
+			orig:   NewComposite(cool),
+			xr: NewComposite(cool, func(cr resource.Composite) {
+				c := xpv1.Available()
+				c.LastTransitionTime = metav1.NewTime(time.Now().Add(time.Hour))
+				setStatusValue(cr, "conditions", []any{})
+				cr.SetConditions(xpv1.ReconcileSuccess(), c)
+			}),
+			want: false,
+		},
+		"NumberRepresentationChanged": {
+			reason: "Representing a number using a different type isn't a meaningful change.",
+			orig:   NewComposite(cool),
+			xr: NewComposite(cool, func(cr resource.Composite) {
+				setStatusValue(cr, "coolness", float64(42))
+			}),
+			want: false,
+		},
+		"ResourceVersionChanged": {
+			reason: "If the XR was written since it was read its status may have changed.",
+			orig:   NewComposite(cool),
+			xr: NewComposite(cool, func(cr resource.Composite) {
+				cr.SetResourceVersion("2")
+			}),
+			want: true,
+		},
+		"ConditionMessageChanged": {
+			reason: "Changing a condition's message is a meaningful change.",
+			orig:   NewComposite(cool),
+			xr: NewComposite(cool, func(cr resource.Composite) {
+				cr.SetConditions(xpv1.Available().WithMessage("cool"))
+			}),
+			want: true,
+		},
+		"ConditionObservedGenerationChanged": {
+			reason: "Changing a condition's observed generation is a meaningful change.",
+			orig:   NewComposite(cool),
+			xr: NewComposite(cool, func(cr resource.Composite) {
+				setStatusValue(cr, "conditions", []any{})
+				cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available().WithObservedGeneration(2))
+			}),
+			want: true,
+		},
+		"ConditionAdded": {
+			reason: "Adding a condition is a meaningful change.",
+			orig:   NewComposite(cool),
+			xr: NewComposite(cool, func(cr resource.Composite) {
+				cr.SetConditions(xpv1.Condition{Type: "Cool", Status: "True"})
+			}),
+			want: true,
+		},
+		"FieldChanged": {
+			reason: "Changing a status field's value is a meaningful change.",
+			orig:   NewComposite(cool),
+			xr: NewComposite(cool, func(cr resource.Composite) {
+				setStatusValue(cr, "coolness", int64(43))
+			}),
+			want: true,
+		},
+		"FieldAdded": {
+			reason: "Adding a status field is a meaningful change.",
+			orig:   NewComposite(cool),
+			xr: NewComposite(cool, func(cr resource.Composite) {
+				setStatusValue(cr, "newness", "new")
+			}),
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := statusChanged(&tc.orig.Unstructured, tc.xr)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nstatusChanged(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TracerName is the name of the OpenTelemetry tracer composite resource
+// reconcilers use.
+const TracerName = "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+
+// Span names.
+const (
+	spanReconcile     = "Reconcile"
+	spanRunFunction   = "RunFunction"
+	spanApplyComposed = "ApplyComposedResource"
+)
+
+// Span attribute keys.
+const (
+	attrXRD                 = attribute.Key("crossplane.xrd")
+	attrCompositeName       = attribute.Key("crossplane.composite.name")
+	attrComposition         = attribute.Key("crossplane.composition")
+	attrCompositionRevision = attribute.Key("crossplane.composition.revision")
+	attrStep                = attribute.Key("crossplane.step")
+	attrFunction            = attribute.Key("crossplane.function")
+	attrComposedName        = attribute.Key("crossplane.composed.name")
+	attrComposedKind        = attribute.Key("crossplane.composed.kind")
+)
+
+// nopTracer is used when tracing isn't enabled.
+func nopTracer() trace.Tracer {
+	return noop.NewTracerProvider().Tracer(TracerName)
+}
+
+// endSpan records the supplied error, if any, on the supplied span then ends
+// it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestReconcileTracing(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		name   string
+		attrs  map[attribute.Key]string
+		status codes.Code
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *test.MockClient
+		want   want
+	}{
+		"GetError": {
+			reason: "We should record an error on the reconcile's span if we can't get the XR.",
+			client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				name:   spanReconcile,
+				attrs:  map[attribute.Key]string{attrXRD: "xcools.example.org", attrCompositeName: "cool-xr"},
+				status: codes.Error,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+			r := NewReconciler(tc.client, resource.CompositeKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCool"}),
+				WithTracer(tp.Tracer(TracerName), "xcools.example.org"))
+			_, _ = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool-xr"}})
+
+			spans := sr.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("\n%s\nr.Reconcile(...): want 1 ended span, got %d", tc.reason, len(spans))
+			}
+			got := want{name: spans[0].Name(), attrs: map[attribute.Key]string{}, status: spans[0].Status().Code}
+			for _, kv := range spans[0].Attributes() {
+				got.attrs[kv.Key] = kv.Value.Emit()
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want span, +got span:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+// transitionRecord records the states an XR was observed to be in.
+type transitionRecord struct {
+	uid    types.UID
+	states map[string]bool
+}
+
+// A transitionTracker tracks which states each XR was in when the Reconciler
+// last observed it, so that the Reconciler can record an event when an XR
+// enters a state, rather than every time it observes the XR in that state.
+type transitionTracker struct {
+	mx      sync.Mutex
+	records map[types.NamespacedName]transitionRecord
+}
+
+func newTransitionTracker() *transitionTracker {
+	return &transitionTracker{records: make(map[types.NamespacedName]transitionRecord)}
+}
+
+// Observe that the supplied XR is in the supplied states, and no others. It
+// returns the states the XR wasn't in when it was last observed.
+func (t *transitionTracker) Observe(xr *composite.Unstructured, states ...string) map[string]bool {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	nn := types.NamespacedName{Namespace: xr.GetNamespace(), Name: xr.GetName()}
+	r, ok := t.records[nn]
+	if !ok || r.uid != xr.GetUID() {
+		r = transitionRecord{uid: xr.GetUID()}
+	}
+
+	entered := make(map[string]bool)
+	current := make(map[string]bool, len(states))
+	for _, s := range states {
+		current[s] = true
+		if !r.states[s] {
+			entered[s] = true
+		}
+	}
+
+	if len(current) == 0 {
+		delete(t.records, nn)
+		return entered
+	}
+
+	r.states = current
+	t.records[nn] = r
+	return entered
+}
+
+// Forget any record of the named XR.
+func (t *transitionTracker) Forget(nn types.NamespacedName) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	delete(t.records, nn)
+}
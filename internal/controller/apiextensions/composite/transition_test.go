@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+func TestTransitionTrackerObserve(t *testing.T) {
+	newXR := func(uid types.UID) *composite.Unstructured {
+		xr := composite.New()
+		xr.SetName("cool-xr")
+		xr.SetUID(uid)
+		return xr
+	}
+
+	// An observation of the XR's states.
+	type observation struct {
+		xr     *composite.Unstructured
+		states []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		// Earlier observations, made before the one we test.
+		earlier []observation
+		forget  bool
+		obs     observation
+		want    map[string]bool
+	}{
+		"FirstObserved": {
+			reason: "An XR that's observed in a state for the first time should enter it.",
+			obs:    observation{xr: newXR("uid"), states: []string{"cool"}},
+			want:   map[string]bool{"cool": true},
+		},
+		"StillInState": {
+			reason: "An XR that was already in a state shouldn't enter it again.",
+			earlier: []observation{
+				{xr: newXR("uid"), states: []string{"cool"}},
+			},
+			obs:  observation{xr: newXR("uid"), states: []string{"cool"}},
+			want: map[string]bool{},
+		},
+		"NewState": {
+			reason: "An XR should only enter the states it wasn't already in.",
+			earlier: []observation{
+				{xr: newXR("uid"), states: []string{"cool"}},
+			},
+			obs:  observation{xr: newXR("uid"), states: []string{"cool", "lame"}},
+			want: map[string]bool{"lame": true},
+		},
+		"LeftAndReentered": {
+			reason: "An XR that left a state should enter it again.",
+			earlier: []observation{
+				{xr: newXR("uid"), states: []string{"cool"}},
+				{xr: newXR("uid")},
+			},
+			obs:  observation{xr: newXR("uid"), states: []string{"cool"}},
+			want: map[string]bool{"cool": true},
+		},
+		"Recreated": {
+			reason: "A recreated XR should enter its states again.",
+			earlier: []observation{
+				{xr: newXR("old-uid"), states: []string{"cool"}},
+			},
+			obs:  observation{xr: newXR("new-uid"), states: []string{"cool"}},
+			want: map[string]bool{"cool": true},
+		},
+		"Forgotten": {
+			reason: "A forgotten XR should enter its states again.",
+			earlier: []observation{
+				{xr: newXR("uid"), states: []string{"cool"}},
+			},
+			forget: true,
+			obs:    observation{xr: newXR("uid"), states: []string{"cool"}},
+			want:   map[string]bool{"cool": true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tt := newTransitionTracker()
+			for _, o := range tc.earlier {
+				tt.Observe(o.xr, o.states...)
+			}
+			if tc.forget {
+				tt.Forget(types.NamespacedName{Name: "cool-xr"})
+			}
+
+			got := tt.Observe(tc.obs.xr, tc.obs.states...)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
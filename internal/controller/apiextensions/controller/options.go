@@ -18,6 +18,10 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
 	"github.com/crossplane/crossplane/internal/engine"
@@ -33,4 +37,47 @@ type Options struct {
 
 	// FunctionRunner used to run Composition Functions.
 	FunctionRunner *xfn.PackagedFunctionRunner
+
+	// CompositeEventsOnClaim specifies whether events that target only a
+	// composite resource should also be recorded on its claim.
+	CompositeEventsOnClaim bool
+
+	// PollJitter is the fraction of the poll interval by which each composite
+	// resource's poll interval is randomly varied, in either direction.
+	PollJitter float64
+
+	// MinReconcileInterval is the minimum interval between successful
+	// compositions of an unchanged composite resource. Zero means composite
+	// resources are composed every time they're reconciled.
+	MinReconcileInterval time.Duration
+
+	// ReadinessStableFor is how long each composed resource must have been
+	// continuously ready before its composite resource is considered ready.
+	// Zero means composite resources are ready as soon as all of their
+	// composed resources are.
+	ReadinessStableFor time.Duration
+
+	// MaxConsecutiveFailures is how many consecutive times a composite
+	// resource may fail to reconcile before it's considered stalled. Zero
+	// means composite resources never stall.
+	MaxConsecutiveFailures int
+
+	// StalledBackoff is how long to wait before retrying a stalled composite
+	// resource.
+	StalledBackoff time.Duration
+
+	// MaxCompositionDepth is how deeply composite resources may be nested
+	// under a top-level composite resource. Zero means composite resources
+	// may be nested to any depth.
+	MaxCompositionDepth int
+
+	// ObservationCacheMaxAge is how long the connection details of a composed
+	// resource whose resourceVersion hasn't changed may be reused instead of
+	// fetched again. Zero means connection details are fetched every time a
+	// composite resource is reconciled.
+	ObservationCacheMaxAge time.Duration
+
+	// TracerProvider used to trace composite resource reconciles. Nil means
+	// composite resource reconciles aren't traced.
+	TracerProvider trace.TracerProvider
 }
@@ -20,6 +20,7 @@ package definition
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -30,13 +31,13 @@ import (
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -47,7 +48,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
-	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+	"github.com/crossplane/crossplane/internal/connection"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite/watch"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
@@ -60,6 +61,10 @@ const (
 	timeout   = 2 * time.Minute
 	finalizer = "defined.apiextensions.crossplane.io"
 
+	// The maximum number of composite resources of each kind whose composed
+	// resources' connection details are cached.
+	maxObservationCacheXRs = 10000
+
 	errGetXRD                         = "cannot get CompositeResourceDefinition"
 	errRenderCRD                      = "cannot render composite resource CustomResourceDefinition"
 	errGetCRD                         = "cannot get composite resource CustomResourceDefinition"
@@ -460,6 +465,32 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			"desired-version", desired.APIVersion)
 	}
 
+	observedMCR := d.Status.Controllers.CompositeResourceMaxConcurrentReconciles
+	desiredMCR := ptr.Deref(d.Spec.MaxConcurrentReconciles, r.options.MaxConcurrentReconciles)
+	if observedMCR != 0 && observedMCR != desiredMCR {
+		if err := r.engine.Stop(ctx, composite.ControllerName(d.GetName())); err != nil {
+			err = errors.Wrap(err, errStopController)
+			r.record.Event(d, event.Warning(reasonEstablishXR, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Max concurrent reconciles changed; stopped composite resource controller",
+			"observed-max-concurrent-reconciles", observedMCR,
+			"desired-max-concurrent-reconciles", desiredMCR)
+	}
+
+	observedMP := d.Status.Controllers.CompositeResourceDefaultComposedManagementPolicies
+	desiredMP := d.GetDefaultComposedManagementPolicies()
+	if !slices.Equal(observedMP, desiredMP) {
+		if err := r.engine.Stop(ctx, composite.ControllerName(d.GetName())); err != nil {
+			err = errors.Wrap(err, errStopController)
+			r.record.Event(d, event.Warning(reasonEstablishXR, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Default composed management policies changed; stopped composite resource controller",
+			"observed-management-policies", observedMP,
+			"desired-management-policies", desiredMP)
+	}
+
 	if r.engine.IsRunning(composite.ControllerName(d.GetName())) {
 		log.Debug("Composite resource controller is running")
 		d.Status.SetConditions(v1.WatchingComposite())
@@ -471,6 +502,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	cr := composite.NewReconciler(r.engine.GetClient(), ck, ro...)
 	ko := r.options.ForControllerRuntime()
+	ko.MaxConcurrentReconciles = desiredMCR
 
 	// Most controllers use this type of rate limiter to backoff requeues from 1
 	// to 60 seconds. Despite the name, it doesn't only rate limit requeues due
@@ -523,6 +555,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	log.Debug("Started composite resource controller")
 
 	d.Status.Controllers.CompositeResourceTypeRef = v1.TypeReferenceTo(d.GetCompositeGroupVersionKind())
+	d.Status.Controllers.CompositeResourceMaxConcurrentReconciles = desiredMCR
+	d.Status.Controllers.CompositeResourceDefaultComposedManagementPolicies = desiredMP
 	d.Status.SetConditions(v1.WatchingComposite())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 }
@@ -535,12 +569,37 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 		composite.WithConnectionPublishers(composite.NewAPIFilteredSecretPublisher(r.engine.GetClient(), d.GetConnectionSecretKeys())),
 		composite.WithCompositionSelector(composite.NewCompositionSelectorChain(
 			composite.NewEnforcedCompositionSelector(*d, r.record),
+			composite.NewAPINamespaceDefaultCompositionSelector(r.engine.GetClient(), r.record),
 			composite.NewAPIDefaultCompositionSelector(r.engine.GetClient(), *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind), r.record),
 			composite.NewAPILabelSelectorResolver(r.engine.GetClient()),
 		)),
+		composite.WithCompositionRevisionFetcher(composite.NewAPIClaimNamespaceRevisionFetcher(
+			r.engine.GetClient(),
+			composite.NewAPIRevisionFetcher(resource.ClientApplicator{Client: r.engine.GetClient(), Applicator: resource.NewAPIPatchingApplicator(r.engine.GetClient())}),
+		)),
 		composite.WithLogger(r.log.WithValues("controller", composite.ControllerName(d.GetName()))),
 		composite.WithRecorder(r.record.WithAnnotations("controller", composite.ControllerName(d.GetName()))),
-		composite.WithPollInterval(r.options.PollInterval),
+		composite.WithJitteredPollInterval(r.options.PollInterval, r.options.PollJitter),
+	}
+
+	if r.options.CompositeEventsOnClaim {
+		o = append(o, composite.WithCompositeEventsOnClaim())
+	}
+
+	if r.options.MinReconcileInterval > 0 {
+		o = append(o, composite.WithMinReconcileInterval(r.options.MinReconcileInterval))
+	}
+
+	if r.options.ReadinessStableFor > 0 {
+		o = append(o, composite.WithReadinessStableFor(r.options.ReadinessStableFor))
+	}
+
+	if r.options.MaxConsecutiveFailures > 0 {
+		o = append(o, composite.WithMaxConsecutiveFailures(r.options.MaxConsecutiveFailures, r.options.StalledBackoff))
+	}
+
+	if r.options.MaxCompositionDepth > 0 {
+		o = append(o, composite.WithMaxCompositionDepth(r.options.MaxCompositionDepth))
 	}
 
 	// If external secret stores aren't enabled we just fetch connection details
@@ -556,15 +615,14 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 	if r.options.Features.Enabled(features.EnableAlphaExternalSecretStores) {
 		pc := []managed.ConnectionPublisher{
 			composite.NewAPIFilteredSecretPublisher(r.engine.GetClient(), d.GetConnectionSecretKeys()),
-			composite.NewSecretStoreConnectionPublisher(connection.NewDetailsManager(r.engine.GetClient(), v1alpha1.StoreConfigGroupVersionKind,
-				connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)), d.GetConnectionSecretKeys()),
+			composite.NewSecretStoreConnectionPublisher(connection.NewDetailsManager(r.engine.GetClient(), connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)), d.GetConnectionSecretKeys()),
 		}
 
 		// If external secret stores are enabled we need to support fetching
 		// connection details from both secrets and external stores.
 		fetcher = composite.ConnectionDetailsFetcherChain{
 			composite.NewSecretConnectionDetailsFetcher(r.engine.GetClient()),
-			connection.NewDetailsManager(r.engine.GetClient(), v1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)),
+			connection.NewDetailsManager(r.engine.GetClient(), connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)),
 		}
 
 		cc := composite.NewConfiguratorChain(
@@ -578,18 +636,59 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 			composite.WithConfigurator(cc))
 	}
 
-	// This composer is used for mode: Resources Compositions (the default).
-	ptc := composite.NewPTComposer(r.engine.GetClient(), composite.WithComposedConnectionDetailsFetcher(fetcher))
+	// Optionally reuse the connection details of composed resources that
+	// haven't changed since we last fetched them.
+	composedFetcher := fetcher
+	if r.options.ObservationCacheMaxAge > 0 {
+		composedFetcher = composite.NewCachingConnectionDetailsFetcher(fetcher, r.options.ObservationCacheMaxAge, maxObservationCacheXRs)
+	}
 
 	// Wrap the PackagedFunctionRunner setup in main with support for loading
 	// extra resources to satisfy function requirements.
 	runner := composite.NewFetchingFunctionRunner(r.options.FunctionRunner, composite.NewExistingExtraResourcesFetcher(r.engine.GetClient()))
 
-	// This composer is used for mode: Pipeline Compositions.
-	fc := composite.NewFunctionComposer(r.engine.GetClient(), runner,
-		composite.WithComposedResourceObserver(composite.NewExistingComposedResourceObserver(r.engine.GetClient(), fetcher)),
+	// Checking provider capabilities and setting default management policies
+	// both read the provider revision that defines each composed resource,
+	// so they share a cache of them.
+	revs := composite.NewCachingProviderRevisionFetcher(composite.NewAPIProviderRevisionFetcher(r.engine.GetClient()), composite.DefaultProviderRevisionCacheTTL)
+	caps := composite.NewProviderCapabilityChecker(revs)
+	mpd := composite.NewProviderManagementPolicyDefaulter(revs, d.GetDefaultComposedManagementPolicies())
+
+	po := []composite.PTComposerOption{
+		composite.WithComposedConnectionDetailsFetcher(composedFetcher),
+		composite.WithComposedCapabilityChecker(caps),
+		composite.WithComposedManagementPolicyDefaulter(mpd),
+	}
+	fo := []composite.FunctionComposerOption{
+		composite.WithComposedResourceObserver(composite.NewExistingComposedResourceObserver(r.engine.GetClient(), composedFetcher)),
 		composite.WithCompositeConnectionDetailsFetcher(fetcher),
-	)
+		composite.WithFunctionContextSeeder(composite.NewAPIFunctionContextSeeder(r.engine.GetClient(), *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind))),
+		composite.WithComposedResourceCapabilityChecker(caps),
+		composite.WithComposedResourceManagementPolicyDefaulter(mpd),
+	}
+	if r.options.Features.Enabled(features.EnableAlphaCompositionStepAnnotations) {
+		fo = append(fo, composite.WithPipelineStepAnnotations())
+	}
+	if r.options.Features.Enabled(features.EnableAlphaComposedDryRunApply) {
+		fo = append(fo, composite.WithDryRunApply())
+	}
+	if r.options.Features.Enabled(features.EnableAlphaComposedClaimNamespace) {
+		n := composite.NewClaimNamespacer(r.engine.GetClient())
+		po = append(po, composite.WithComposedNamespacer(n))
+		fo = append(fo, composite.WithComposedResourceNamespacer(n))
+	}
+	if r.options.TracerProvider != nil {
+		t := r.options.TracerProvider.Tracer(composite.TracerName)
+		o = append(o, composite.WithTracer(t, d.GetName()))
+		po = append(po, composite.WithComposedTracer(t))
+		fo = append(fo, composite.WithFunctionTracer(t))
+	}
+
+	// This composer is used for mode: Resources Compositions (the default).
+	ptc := composite.NewPTComposer(r.engine.GetClient(), po...)
+
+	// This composer is used for mode: Pipeline Compositions.
+	fc := composite.NewFunctionComposer(r.engine.GetClient(), runner, fo...)
 
 	// We use two different Composer implementations. One supports P&T (aka
 	// 'Resources mode') and the other Functions (aka 'Pipeline mode').
@@ -27,9 +27,11 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -746,6 +748,7 @@ func TestReconcile(t *testing.T) {
 						MockGet: test.NewMockGetFn(nil),
 						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 							want := &v1.CompositeResourceDefinition{}
+							want.Status.Controllers.CompositeResourceMaxConcurrentReconciles = 1
 							want.Status.SetConditions(v1.WatchingComposite())
 
 							if diff := cmp.Diff(want, o); diff != "" {
@@ -804,6 +807,7 @@ func TestReconcile(t *testing.T) {
 								{Name: "new", Referenceable: true},
 							}
 							want.Status.Controllers.CompositeResourceTypeRef = v1.TypeReference{APIVersion: "new"}
+							want.Status.Controllers.CompositeResourceMaxConcurrentReconciles = 1
 							want.Status.SetConditions(v1.WatchingComposite())
 
 							if diff := cmp.Diff(want, o); diff != "" {
@@ -842,6 +846,189 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"SuccessfulUpdateControllerMaxConcurrentReconciles": {
+			reason: "We should restart our controller if the XRD's max concurrent reconciles changed.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							d := obj.(*v1.CompositeResourceDefinition)
+							d.Spec.MaxConcurrentReconciles = ptr.To(10)
+							d.Status.Controllers.CompositeResourceMaxConcurrentReconciles = 1
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							want := &v1.CompositeResourceDefinition{}
+							want.Spec.MaxConcurrentReconciles = ptr.To(10)
+							want.Status.Controllers.CompositeResourceMaxConcurrentReconciles = 10
+							want.Status.SetConditions(v1.WatchingComposite())
+
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockStart:        func(_ string, _ ...engine.ControllerOption) error { return nil },
+						MockStop:         func(_ context.Context, _ string) error { return nil },
+						MockIsRunning:    func(_ string) bool { return false },
+						MockStartWatches: func(_ string, _ ...engine.Watch) error { return nil },
+						MockGetClient:    func() client.Client { return test.NewMockClient() },
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"MaxConcurrentReconcilesChangedStopControllerError": {
+			reason: "We should return any error we encounter while stopping our controller because the XRD's max concurrent reconciles changed.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							d := obj.(*v1.CompositeResourceDefinition)
+							d.Spec.MaxConcurrentReconciles = ptr.To(10)
+							d.Status.Controllers.CompositeResourceMaxConcurrentReconciles = 1
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockStop: func(_ context.Context, _ string) error { return errBoom },
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errStopController),
+			},
+		},
+		"SuccessfulUpdateControllerDefaultComposedManagementPolicies": {
+			reason: "We should restart our controller if the XRD's default composed management policies changed.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							d := obj.(*v1.CompositeResourceDefinition)
+							d.Spec.DefaultComposedManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+							d.Status.Controllers.CompositeResourceMaxConcurrentReconciles = 1
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							want := &v1.CompositeResourceDefinition{}
+							want.Spec.DefaultComposedManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+							want.Status.Controllers.CompositeResourceMaxConcurrentReconciles = 1
+							want.Status.Controllers.CompositeResourceDefaultComposedManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+							want.Status.SetConditions(v1.WatchingComposite())
+
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockStart:        func(_ string, _ ...engine.ControllerOption) error { return nil },
+						MockStop:         func(_ context.Context, _ string) error { return nil },
+						MockIsRunning:    func(_ string) bool { return false },
+						MockStartWatches: func(_ string, _ ...engine.Watch) error { return nil },
+						MockGetClient:    func() client.Client { return test.NewMockClient() },
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"DefaultComposedManagementPoliciesChangedStopControllerError": {
+			reason: "We should return any error we encounter while stopping our controller because the XRD's default composed management policies changed.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							d := obj.(*v1.CompositeResourceDefinition)
+							d.Spec.DefaultComposedManagementPolicies = xpv1.ManagementPolicies{xpv1.ManagementActionObserve}
+							d.Status.Controllers.CompositeResourceMaxConcurrentReconciles = 1
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockStop: func(_ context.Context, _ string) error { return errBoom },
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errStopController),
+			},
+		},
 		"NotRestartingWithoutVersionChange": {
 			reason: "We should return without requeueing if we successfully ensured our CRD exists and controller is started.",
 			args: args{
@@ -20,6 +20,7 @@ package offered
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
@@ -35,7 +36,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
@@ -45,7 +45,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
-	secretsv1alpha1 "github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+	"github.com/crossplane/crossplane/internal/connection"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/claim"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/engine"
@@ -427,10 +427,58 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	observed := d.Status.Controllers.CompositeResourceClaimTypeRef
+	desired := v1.TypeReferenceTo(d.GetClaimGroupVersionKind())
+	if observed.APIVersion != "" && observed != desired {
+		if err := r.engine.Stop(ctx, claim.ControllerName(d.GetName())); err != nil {
+			err = errors.Wrap(err, errStopController)
+			r.record.Event(d, event.Warning(reasonOfferXRC, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Referenceable version changed; stopped composite resource claim controller",
+			"observed-version", observed.APIVersion,
+			"desired-version", desired.APIVersion)
+	}
+
+	observedRD := d.Status.Controllers.CompositeResourceClaimReadinessDetail
+	desiredRD := d.GetClaimReadinessDetail()
+	if observedRD != "" && observedRD != desiredRD {
+		if err := r.engine.Stop(ctx, claim.ControllerName(d.GetName())); err != nil {
+			err = errors.Wrap(err, errStopController)
+			r.record.Event(d, event.Warning(reasonOfferXRC, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Claim readiness detail changed; stopped composite resource claim controller",
+			"observed-readiness-detail", observedRD,
+			"desired-readiness-detail", desiredRD)
+	}
+
+	observedRenames := d.Status.Controllers.CompositeResourceClaimConnectionSecretKeyRenames
+	desiredRenames := d.GetClaimConnectionSecretKeyRenames()
+	if !maps.Equal(observedRenames, desiredRenames) {
+		if err := r.engine.Stop(ctx, claim.ControllerName(d.GetName())); err != nil {
+			err = errors.Wrap(err, errStopController)
+			r.record.Event(d, event.Warning(reasonOfferXRC, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Claim connection secret key renames changed; stopped composite resource claim controller",
+			"observed-renames", observedRenames,
+			"desired-renames", desiredRenames)
+	}
+
+	if r.engine.IsRunning(claim.ControllerName(d.GetName())) {
+		log.Debug("Composite resource claim controller is running")
+		d.Status.SetConditions(v1.WatchingClaim())
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
+	}
+
+	propagator := claim.NewAPIConnectionPropagator(r.engine.GetClient(), claim.WithConnectionSecretKeyRenames(d.GetClaimConnectionSecretKeyRenames()))
 	o := []claim.ReconcilerOption{
 		claim.WithLogger(log.WithValues("controller", claim.ControllerName(d.GetName()))),
 		claim.WithRecorder(r.record.WithAnnotations("controller", claim.ControllerName(d.GetName()))),
 		claim.WithPollInterval(r.options.PollInterval),
+		claim.WithReadinessDetail(d.GetClaimReadinessDetail()),
+		claim.WithConnectionPropagator(propagator),
 	}
 
 	// We only want to use the server-side XR syncer if the relevant feature
@@ -449,32 +497,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// their default Connection Propagator.
 	if r.options.Features.Enabled(features.EnableAlphaExternalSecretStores) {
 		pc := claim.ConnectionPropagatorChain{
-			claim.NewAPIConnectionPropagator(r.engine.GetClient()),
-			connection.NewDetailsManager(r.engine.GetClient(), secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)),
+			propagator,
+			connection.NewDetailsManager(r.engine.GetClient(), connection.WithTLSConfig(r.options.ESSOptions.TLSConfig), connection.WithPropagatedKeyRenames(d.GetClaimConnectionSecretKeyRenames())),
 		}
 
 		o = append(o, claim.WithConnectionPropagator(pc), claim.WithConnectionUnpublisher(
-			claim.NewSecretStoreConnectionUnpublisher(connection.NewDetailsManager(r.engine.GetClient(),
-				secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)))))
-	}
-
-	observed := d.Status.Controllers.CompositeResourceClaimTypeRef
-	desired := v1.TypeReferenceTo(d.GetClaimGroupVersionKind())
-	if observed.APIVersion != "" && observed != desired {
-		if err := r.engine.Stop(ctx, claim.ControllerName(d.GetName())); err != nil {
-			err = errors.Wrap(err, errStopController)
-			r.record.Event(d, event.Warning(reasonOfferXRC, err))
-			return reconcile.Result{}, err
-		}
-		log.Debug("Referenceable version changed; stopped composite resource claim controller",
-			"observed-version", observed.APIVersion,
-			"desired-version", desired.APIVersion)
-	}
-
-	if r.engine.IsRunning(claim.ControllerName(d.GetName())) {
-		log.Debug("Composite resource claim controller is running")
-		d.Status.SetConditions(v1.WatchingClaim())
-		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
+			claim.NewSecretStoreConnectionUnpublisher(connection.NewDetailsManager(r.engine.GetClient(), connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)))))
 	}
 
 	cr := claim.NewReconciler(r.engine.GetClient(),
@@ -509,6 +537,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	d.Status.Controllers.CompositeResourceClaimTypeRef = v1.TypeReferenceTo(d.GetClaimGroupVersionKind())
+	d.Status.Controllers.CompositeResourceClaimReadinessDetail = desiredRD
+	d.Status.Controllers.CompositeResourceClaimConnectionSecretKeyRenames = desiredRenames
 	d.Status.SetConditions(v1.WatchingClaim())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 }
@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -750,6 +751,7 @@ func TestReconcile(t *testing.T) {
 						MockGet: test.NewMockGetFn(nil),
 						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 							want := &v1.CompositeResourceDefinition{}
+							want.Status.Controllers.CompositeResourceClaimReadinessDetail = v1.ClaimReadinessDetailCounts
 							want.Status.SetConditions(v1.WatchingClaim())
 
 							if diff := cmp.Diff(want, o); diff != "" {
@@ -811,6 +813,7 @@ func TestReconcile(t *testing.T) {
 								{Name: "new", Referenceable: true},
 							}
 							want.Status.Controllers.CompositeResourceClaimTypeRef = v1.TypeReference{APIVersion: "new"}
+							want.Status.Controllers.CompositeResourceClaimReadinessDetail = v1.ClaimReadinessDetailCounts
 							want.Status.SetConditions(v1.WatchingClaim())
 
 							if diff := cmp.Diff(want, o); diff != "" {
@@ -849,6 +852,120 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"SuccessfulUpdateControllerReadinessDetail": {
+			reason: "We should not requeue if we successfully ensured our CRD exists, the controller with the old readiness detail stopped, and the new one started.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							d := obj.(*v1.CompositeResourceDefinition)
+							d.Spec.ClaimReadinessDetail = ptr.To(v1.ClaimReadinessDetailResources)
+							d.Status.Controllers.CompositeResourceClaimReadinessDetail = v1.ClaimReadinessDetailCounts
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							want := &v1.CompositeResourceDefinition{}
+							want.Spec.ClaimReadinessDetail = ptr.To(v1.ClaimReadinessDetailResources)
+							want.Status.Controllers.CompositeResourceClaimReadinessDetail = v1.ClaimReadinessDetailResources
+							want.Status.SetConditions(v1.WatchingClaim())
+
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockStart:        func(_ string, _ ...engine.ControllerOption) error { return nil },
+						MockStop:         func(_ context.Context, _ string) error { return nil },
+						MockIsRunning:    func(_ string) bool { return false },
+						MockStartWatches: func(_ string, _ ...engine.Watch) error { return nil },
+						MockGetClient:    func() client.Client { return test.NewMockClient() },
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulUpdateControllerConnectionSecretKeyRenames": {
+			reason: "We should not requeue if we successfully ensured our CRD exists, the controller with the old connection secret key renames stopped, and the new one started.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							d := obj.(*v1.CompositeResourceDefinition)
+							d.Spec.ClaimConnectionSecretKeyRenames = map[string]string{"endpoint": "DB_HOST"}
+							d.Status.Controllers.CompositeResourceClaimConnectionSecretKeyRenames = map[string]string{"endpoint": "HOST"}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+							want := &v1.CompositeResourceDefinition{}
+							want.Spec.ClaimConnectionSecretKeyRenames = map[string]string{"endpoint": "DB_HOST"}
+							want.Status.Controllers.CompositeResourceClaimConnectionSecretKeyRenames = map[string]string{"endpoint": "DB_HOST"}
+							want.Status.Controllers.CompositeResourceClaimReadinessDetail = v1.ClaimReadinessDetailCounts
+							want.Status.SetConditions(v1.WatchingClaim())
+
+							if diff := cmp.Diff(want, o); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(func() *MockEngine {
+						// The controller is running until it's stopped.
+						running := true
+						return &MockEngine{
+							MockStart: func(_ string, _ ...engine.ControllerOption) error { return nil },
+							MockStop: func(_ context.Context, _ string) error {
+								running = false
+								return nil
+							},
+							MockIsRunning:    func(_ string) bool { return running },
+							MockStartWatches: func(_ string, _ ...engine.Watch) error { return nil },
+							MockGetClient:    func() client.Client { return test.NewMockClient() },
+						}
+					}()),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"NotRestartingWithoutVersionChange": {
 			reason: "We should return without requeueing if we successfully ensured our CRD exists and controller is started.",
 			args: args{
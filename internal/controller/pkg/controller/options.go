@@ -39,6 +39,14 @@ type Options struct {
 	// DefaultRegistry used to pull packages.
 	DefaultRegistry string
 
+	// DependencyMirror is the registry dependency packages are copied to,
+	// and installed from, when dependency mirroring is enabled.
+	DependencyMirror string
+
+	// AllowedRegistries restricts the registries packages may be installed
+	// from. A nil allow list allows all registries.
+	AllowedRegistries *xpkg.RegistryAllowList
+
 	// FetcherOptions can be used to add optional parameters to
 	// NewK8sFetcher.
 	FetcherOptions []xpkg.FetcherOpt
@@ -46,7 +54,15 @@ type Options struct {
 	// PackageRuntime specifies the runtime to use for package runtime.
 	PackageRuntime PackageRuntime
 
+	// PackageVariant is the variant of a package's base layer to unpack, if
+	// the package offers it.
+	PackageVariant string
+
 	// MaxConcurrentPackageEstablishers is the maximum number of goroutines to use
 	// for establishing Providers, Configurations and Functions.
 	MaxConcurrentPackageEstablishers int
+
+	// PreserveCRDConversion specifies that the conversion config of existing
+	// package CRDs should be left untouched, so other tooling can manage it.
+	PreserveCRDConversion bool
 }
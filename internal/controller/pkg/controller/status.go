@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SuccessfulReconcileRefreshInterval is how often the time a package or
+// package revision was last successfully reconciled is refreshed. Refreshing
+// it at most this often, rather than on every successful reconcile, avoids
+// writing status when a reconcile is otherwise a no-op.
+const SuccessfulReconcileRefreshInterval = 1 * time.Minute
+
+// A SuccessfulReconcileTimer records when it was last successfully
+// reconciled.
+type SuccessfulReconcileTimer interface {
+	GetLastSuccessfulReconcileTime() *metav1.Time
+	SetLastSuccessfulReconcileTime(t *metav1.Time)
+}
+
+// RecordSuccessfulReconcile records that the supplied object was successfully
+// reconciled at the supplied time, unless it was already recorded as
+// successfully reconciled less than SuccessfulReconcileRefreshInterval ago.
+func RecordSuccessfulReconcile(o SuccessfulReconcileTimer, now time.Time) {
+	if t := o.GetLastSuccessfulReconcileTime(); t != nil && now.Sub(t.Time) < SuccessfulReconcileRefreshInterval {
+		return
+	}
+	o.SetLastSuccessfulReconcileTime(&metav1.Time{Time: now})
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestRecordSuccessfulReconcile(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		last   *metav1.Time
+		want   *metav1.Time
+	}{
+		"NeverRecorded": {
+			reason: "We should record a successful reconcile if none was recorded before.",
+			want:   &metav1.Time{Time: now},
+		},
+		"RecentlyRecorded": {
+			reason: "We shouldn't refresh a successful reconcile that was recorded less than the refresh interval ago.",
+			last:   &metav1.Time{Time: now.Add(-30 * time.Second)},
+			want:   &metav1.Time{Time: now.Add(-30 * time.Second)},
+		},
+		"StaleRecord": {
+			reason: "We should refresh a successful reconcile that was recorded more than the refresh interval ago.",
+			last:   &metav1.Time{Time: now.Add(-2 * time.Minute)},
+			want:   &metav1.Time{Time: now},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &v1.Provider{}
+			p.SetLastSuccessfulReconcileTime(tc.last)
+
+			RecordSuccessfulReconcile(p, now)
+
+			if diff := cmp.Diff(tc.want, p.GetLastSuccessfulReconcileTime()); diff != "" {
+				t.Errorf("\n%s\nRecordSuccessfulReconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
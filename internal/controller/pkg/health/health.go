@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health serves a summary of the health of installed packages.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+)
+
+// Path at which package health is served, on the metrics server.
+const Path = "/packages/health"
+
+const (
+	errListProviders      = "cannot list providers"
+	errListConfigurations = "cannot list configurations"
+	errListFunctions      = "cannot list functions"
+)
+
+// A Summary of the health of installed packages.
+type Summary struct {
+	// Healthy is true if no installed package is unhealthy.
+	Healthy bool `json:"healthy"`
+
+	Providers      Counts `json:"providers"`
+	Configurations Counts `json:"configurations"`
+	Functions      Counts `json:"functions"`
+}
+
+// Counts of packages of one type, by state.
+type Counts struct {
+	// Total number of installed packages.
+	Total int `json:"total"`
+
+	// Healthy packages are installed and healthy.
+	Healthy int `json:"healthy"`
+
+	// Unhealthy packages have reported that they're unhealthy.
+	Unhealthy int `json:"unhealthy"`
+
+	// Pending packages aren't yet installed, or haven't yet reported
+	// whether they're healthy.
+	Pending int `json:"pending"`
+}
+
+func (c *Counts) add(p v1.Package) {
+	c.Total++
+	switch {
+	case p.GetCondition(v1.TypeHealthy).Status == corev1.ConditionFalse:
+		c.Unhealthy++
+	case p.GetCondition(v1.TypeInstalled).Status == corev1.ConditionTrue && p.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue:
+		c.Healthy++
+	default:
+		c.Pending++
+	}
+}
+
+// A Handler serves a summary of the health of installed packages.
+type Handler struct {
+	client client.Reader
+	log    logging.Logger
+}
+
+// NewHandler returns a Handler that summarizes the packages the supplied
+// client reads. The client should read from a cache, so that serving the
+// summary doesn't list packages from the API server.
+func NewHandler(c client.Reader, l logging.Logger) *Handler {
+	return &Handler{client: c, log: l}
+}
+
+// Summarize the health of installed packages.
+func (h *Handler) Summarize(ctx context.Context) (*Summary, error) {
+	s := &Summary{}
+
+	pl := &v1.ProviderList{}
+	if err := h.client.List(ctx, pl); err != nil {
+		return nil, errors.Wrap(err, errListProviders)
+	}
+	for i := range pl.Items {
+		s.Providers.add(&pl.Items[i])
+	}
+
+	cl := &v1.ConfigurationList{}
+	if err := h.client.List(ctx, cl); err != nil {
+		return nil, errors.Wrap(err, errListConfigurations)
+	}
+	for i := range cl.Items {
+		s.Configurations.add(&cl.Items[i])
+	}
+
+	fl := &v1.FunctionList{}
+	if err := h.client.List(ctx, fl); err != nil {
+		return nil, errors.Wrap(err, errListFunctions)
+	}
+	for i := range fl.Items {
+		s.Functions.add(&fl.Items[i])
+	}
+
+	s.Healthy = s.Providers.Unhealthy+s.Configurations.Unhealthy+s.Functions.Unhealthy == 0
+	return s, nil
+}
+
+// ServeHTTP serves a JSON summary of the health of installed packages.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s, err := h.Summarize(r.Context())
+	if err != nil {
+		h.log.Debug("Cannot summarize package health", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s); err != nil {
+		h.log.Debug("Cannot write package health summary", "error", err)
+	}
+}
+
+// Setup serves a summary of the health of installed packages on the
+// manager's metrics server. The summary is read from the manager's cache,
+// which the package manager controllers already populate, so serving it
+// requires no RBAC beyond what the package manager already has.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	h := NewHandler(mgr.GetClient(), o.Logger.WithValues("handler", "packages/health"))
+	return errors.Wrap(mgr.AddMetricsServerExtraHandler(Path, h), "cannot serve package health")
+}
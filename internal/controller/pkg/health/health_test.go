@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestSummarize(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	provider := func(c ...xpv1.Condition) v1.Provider {
+		p := v1.Provider{}
+		p.SetConditions(c...)
+		return p
+	}
+	function := func(c ...xpv1.Condition) v1.Function {
+		f := v1.Function{}
+		f.SetConditions(c...)
+		return f
+	}
+
+	type want struct {
+		s   *Summary
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		client client.Reader
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered listing packages.",
+			client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				err: errors.Wrap(errBoom, errListProviders),
+			},
+		},
+		"NoPackages": {
+			reason: "A control plane with no packages is healthy.",
+			client: &test.MockClient{MockList: test.NewMockListFn(nil)},
+			want: want{
+				s: &Summary{Healthy: true},
+			},
+		},
+		"SomeUnhealthy": {
+			reason: "We should count packages by type and state, and report unhealthy if any package is unhealthy.",
+			client: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+				switch l := obj.(type) {
+				case *v1.ProviderList:
+					l.Items = []v1.Provider{
+						provider(v1.Active(), v1.Healthy()),
+						provider(v1.Active(), v1.Unhealthy()),
+						provider(v1.Unpacking()),
+					}
+				case *v1.FunctionList:
+					l.Items = []v1.Function{
+						function(v1.Active(), v1.Healthy()),
+						function(v1.Active(), v1.UnknownHealth()),
+					}
+				}
+				return nil
+			})},
+			want: want{
+				s: &Summary{
+					Healthy:   false,
+					Providers: Counts{Total: 3, Healthy: 1, Unhealthy: 1, Pending: 1},
+					Functions: Counts{Total: 2, Healthy: 1, Pending: 1},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.client, logging.NewNopLogger())
+			s, err := h.Summarize(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSummarize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.s, s); diff != "" {
+				t.Errorf("\n%s\nSummarize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	h := NewHandler(&test.MockClient{MockList: test.NewMockListFn(nil)}, logging.NewNopLogger())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+
+	if diff := cmp.Diff(http.StatusOK, rec.Code); diff != "" {
+		t.Errorf("ServeHTTP(...): -want status, +got status:\n%s", diff)
+	}
+
+	got := &Summary{}
+	if err := json.Unmarshal(rec.Body.Bytes(), got); err != nil {
+		t.Fatalf("ServeHTTP(...): cannot unmarshal summary: %s", err)
+	}
+	if diff := cmp.Diff(&Summary{Healthy: true}, got); diff != "" {
+		t.Errorf("ServeHTTP(...): -want, +got:\n%s", diff)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -81,6 +82,13 @@ const (
 	errUnhealthyPackageRevision     = "current package revision is unhealthy"
 	errUnknownPackageRevisionHealth = "current package revision health is unknown"
 
+	errNotPinned = "package is not pinned to a digest"
+
+	errCheckAllowedRegistry  = "cannot check whether package source is from an allowed registry"
+	errFmtRegistryNotAllowed = "package source %q isn't from an allowed registry - allowed registries are %s"
+
+	errFmtRollbackRevisionNotFound = "cannot roll back to package revision %q: revision doesn't exist"
+
 	errCreateK8sClient = "failed to initialize clientset"
 	errBuildFetcher    = "cannot build fetcher"
 )
@@ -94,6 +102,9 @@ const (
 	reasonInstall            event.Reason = "InstallPackageRevision"
 	reasonPaused             event.Reason = "ReconciliationPaused"
 	reasonImageConfig        event.Reason = "ImageConfigSelection"
+	reasonDigestChanged      event.Reason = "DigestChanged"
+	reasonRegistryNotAllowed event.Reason = "RegistryNotAllowed"
+	reasonRollback           event.Reason = "RollbackPackageRevision"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -128,6 +139,14 @@ func WithRevisioner(d Revisioner) ReconcilerOption {
 	}
 }
 
+// WithTagTracker specifies how the Reconciler should resolve the digest a
+// package's tracked tag refers to.
+func WithTagTracker(t TagTracker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.tag = t
+	}
+}
+
 // WithConfigStore specifies the image config store to use.
 func WithConfigStore(c xpkg.ConfigStore) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -135,6 +154,14 @@ func WithConfigStore(c xpkg.ConfigStore) ReconcilerOption {
 	}
 }
 
+// WithAllowedRegistries specifies the registries the Reconciler may install
+// packages from. Packages from other registries aren't unpacked.
+func WithAllowedRegistries(l *xpkg.RegistryAllowList) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.allowed = l
+	}
+}
+
 // WithLogger specifies how the Reconciler should log messages.
 func WithLogger(log logging.Logger) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -151,11 +178,13 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 
 // Reconciler reconciles packages.
 type Reconciler struct {
-	client resource.ClientApplicator
-	pkg    Revisioner
-	config xpkg.ConfigStore
-	log    logging.Logger
-	record event.Recorder
+	client  resource.ClientApplicator
+	pkg     Revisioner
+	tag     TagTracker
+	config  xpkg.ConfigStore
+	allowed *xpkg.RegistryAllowList
+	log     logging.Logger
+	record  event.Recorder
 
 	newPackage             func() v1.Package
 	newPackageRevision     func() v1.PackageRevision
@@ -179,12 +208,15 @@ func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
 	}
 
 	log := o.Logger.WithValues("controller", name)
+	rv := NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))
 	opts := []ReconcilerOption{
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(rv),
+		WithTagTracker(NewCachingTagTracker(rv, pullWait)),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
+		WithAllowedRegistries(o.AllowedRegistries),
 		WithLogger(log),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
@@ -215,12 +247,15 @@ func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
 	}
 
 	log := o.Logger.WithValues("controller", name)
+	rv := NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry))
 	r := NewReconciler(mgr,
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(rv),
+		WithTagTracker(NewCachingTagTracker(rv, pullWait)),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
+		WithAllowedRegistries(o.AllowedRegistries),
 		WithLogger(log),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	)
@@ -251,12 +286,15 @@ func SetupFunction(mgr ctrl.Manager, o controller.Options) error {
 	}
 
 	log := o.Logger.WithValues("controller", name)
+	rv := NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))
 	opts := []ReconcilerOption{
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(rv),
+		WithTagTracker(NewCachingTagTracker(rv, pullWait)),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
+		WithAllowedRegistries(o.AllowedRegistries),
 		WithLogger(log),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
@@ -278,6 +316,7 @@ func NewReconciler(mgr ctrl.Manager, opts ...ReconcilerOption) *Reconciler {
 			Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
 		},
 		pkg:    NewNopRevisioner(),
+		tag:    NewNopTagTracker(),
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
 	}
@@ -329,6 +368,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// Roll back to a previous revision if asked to. We don't pull the package
+	// or create new revisions while it's rolled back.
+	if p.GetRollbackRevision() != nil {
+		return r.rollback(ctx, p, prs.GetRevisions())
+	}
+
+	// Refuse to pull packages from registries that aren't allowed. There's
+	// no need to requeue - we'll be requeued if the package's source changes,
+	// and the allowed registries can't change without a restart.
+	allowed, err := r.allowed.Allowed(p.GetSource())
+	if err != nil {
+		err = errors.Wrap(err, errCheckAllowedRegistry)
+		p.SetConditions(v1.Unpacking().WithMessage(err.Error()))
+		r.record.Event(p, event.Warning(reasonRegistryNotAllowed, err))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+	if !allowed {
+		err := errors.Errorf(errFmtRegistryNotAllowed, p.GetSource(), r.allowed)
+		p.SetConditions(v1.RegistryNotAllowed().WithMessage(err.Error()))
+		r.record.Event(p, event.Warning(reasonRegistryNotAllowed, err))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
 	imageConfig, pullSecretFromConfig, err := r.config.PullSecretFor(ctx, p.GetSource())
 	if err != nil {
 		err = errors.Wrap(err, errGetPullConfig)
@@ -344,10 +406,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if pullSecretFromConfig != "" {
 		secrets = append(secrets, pullSecretFromConfig)
 	}
-	revisionName, err := r.pkg.Revision(ctx, p, secrets...)
+	res, err := r.pkg.Revision(ctx, p, secrets...)
 	if err != nil {
 		err = errors.Wrap(err, errUnpack)
-		p.SetConditions(v1.Unpacking().WithMessage(err.Error()))
+		c := v1.Unpacking()
+		if xpkg.IsProxyConnectError(err) {
+			c = v1.UnpackingProxyUnreachable()
+		}
+		p.SetConditions(c.WithMessage(err.Error()))
 		r.record.Event(p, event.Warning(reasonUnpack, err))
 
 		if updateErr := r.client.Status().Update(ctx, p); updateErr != nil {
@@ -357,16 +423,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
-	if revisionName == "" {
+	if res.Revision == "" {
 		p.SetConditions(v1.Unpacking().WithMessage("Waiting for unpack to complete"))
 		r.record.Event(p, event.Normal(reasonUnpack, "Waiting for unpack to complete"))
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
 	}
 
+	r.checkResolvedDigest(p, res.Digest)
+
 	// Set the current revision and identifier.
-	p.SetCurrentRevision(revisionName)
+	p.SetCurrentRevision(res.Revision)
 	p.SetCurrentIdentifier(p.GetSource())
 
+	if p.GetTrackTag() != nil {
+		p.SetConditions(r.checkTrackedTag(ctx, p, secrets...))
+	}
+
 	pr := r.newPackageRevision()
 	maxRevision := int64(0)
 	oldestRevision := int64(math.MaxInt64)
@@ -430,23 +502,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
-	// TODO(phisco): refactor these conditions to make it clearer
-	if pr.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue {
-		if p.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
-			// NOTE(phisco): We don't want to spam the user with events if the
-			// package is already healthy.
-			r.record.Event(p, event.Normal(reasonInstall, "Successfully installed package revision"))
-		}
-		p.SetConditions(v1.Healthy())
-	}
-	if prHealthy := pr.GetCondition(v1.TypeHealthy); prHealthy.Status == corev1.ConditionFalse {
-		p.SetConditions(v1.Unhealthy().WithMessage(prHealthy.Message))
-		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnhealthyPackageRevision)))
-	}
-	if prHealthy := pr.GetCondition(v1.TypeHealthy); prHealthy.Status == corev1.ConditionUnknown {
-		p.SetConditions(v1.UnknownHealth().WithMessage(prHealthy.Message))
-		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnknownPackageRevisionHealth)))
-	}
+	r.setHealth(p, pr)
 
 	if pr.GetUID() == "" && imageConfig != "" {
 		// We only record this event if the revision is new, as we don't want to
@@ -456,7 +512,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// Create the non-existent package revision.
-	pr.SetName(revisionName)
+	pr.SetName(res.Revision)
 	pr.SetLabels(map[string]string{v1.LabelParentPackage: p.GetName()})
 	pr.SetSource(p.GetSource())
 	pr.SetPackagePullPolicy(p.GetPackagePullPolicy())
@@ -517,7 +573,162 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// package, the health of the package is not set until the revision reports
 	// its health. If updating from an existing revision, the package health
 	// will match the health of the old revision until the next reconcile.
-	return pullBasedRequeue(p.GetPackagePullPolicy()), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	result := pullBasedRequeue(p.GetPackagePullPolicy())
+	if tracksTag(p) {
+		// Periodically check whether the tracked tag has advanced. Our tag
+		// tracker caches digests for pullWait, so reconciling more often
+		// than this wouldn't check the registry again anyway.
+		result = reconcile.Result{RequeueAfter: pullWait}
+	}
+
+	controller.RecordSuccessfulReconcile(p, time.Now())
+	return result, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+}
+
+// setHealth sets the supplied package's health conditions to reflect the
+// health of the supplied package revision.
+func (r *Reconciler) setHealth(p v1.Package, pr v1.PackageRevision) {
+	// TODO(phisco): refactor these conditions to make it clearer
+	if pr.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue {
+		if p.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
+			// NOTE(phisco): We don't want to spam the user with events if the
+			// package is already healthy.
+			r.record.Event(p, event.Normal(reasonInstall, "Successfully installed package revision"))
+		}
+		p.SetConditions(v1.Healthy())
+	}
+	if prHealthy := pr.GetCondition(v1.TypeHealthy); prHealthy.Status == corev1.ConditionFalse {
+		p.SetConditions(v1.Unhealthy().WithMessage(prHealthy.Message))
+		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnhealthyPackageRevision)))
+	}
+	if prHealthy := pr.GetCondition(v1.TypeHealthy); prHealthy.Status == corev1.ConditionUnknown {
+		p.SetConditions(v1.UnknownHealth().WithMessage(prHealthy.Message))
+		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnknownPackageRevisionHealth)))
+	}
+}
+
+// rollback activates the supplied package's rollback revision and deactivates
+// all of its other revisions, regardless of its revision activation policy. It
+// doesn't create a revision for the package's source or garbage collect old
+// revisions, so the rollback revision can't be garbage collected while the
+// package is rolled back.
+func (r *Reconciler) rollback(ctx context.Context, p v1.Package, revisions []v1.PackageRevision) (reconcile.Result, error) {
+	target := *p.GetRollbackRevision()
+
+	var pr v1.PackageRevision
+	for _, rev := range revisions {
+		if rev.GetName() == target {
+			pr = rev
+		}
+	}
+	if pr == nil {
+		// There's no need to requeue. We own our revisions, so we'll be
+		// requeued if one is created.
+		err := errors.Errorf(errFmtRollbackRevisionNotFound, target)
+		p.SetConditions(v1.RollbackRevisionNotFound().WithMessage(err.Error()))
+		r.record.Event(p, event.Warning(reasonRollback, err))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
+	for _, rev := range revisions {
+		if rev.GetName() == target || rev.GetDesiredState() != v1.PackageRevisionActive {
+			continue
+		}
+		rev.SetDesiredState(v1.PackageRevisionInactive)
+		if err := r.client.Apply(ctx, rev, resource.MustBeControllableBy(p.GetUID())); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errUpdateInactivePackageRevision)
+			r.record.Event(p, event.Warning(reasonRollback, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	msg := fmt.Sprintf("Rolled back to package revision %q", target)
+	if pr.GetDesiredState() != v1.PackageRevisionActive {
+		pr.SetDesiredState(v1.PackageRevisionActive)
+		if err := r.client.Apply(ctx, pr, resource.MustBeControllableBy(p.GetUID())); err != nil {
+			if kerrors.IsConflict(err) {
+				return reconcile.Result{Requeue: true}, nil
+			}
+			err = errors.Wrap(err, errApplyPackageRevision)
+			r.record.Event(p, event.Warning(reasonRollback, err))
+			return reconcile.Result{}, err
+		}
+		r.record.Event(p, event.Normal(reasonRollback, msg))
+	}
+
+	// Record the rollback revision as current so the package's status tells
+	// the truth about what's installed. This also means a package with pull
+	// policy IfNotPresent resolves its source again when it's no longer rolled
+	// back, rather than keeping the rollback revision.
+	p.SetCurrentRevision(target)
+	p.SetCurrentIdentifier(pr.GetSource())
+
+	r.setHealth(p, pr)
+	p.SetConditions(v1.RolledBack().WithMessage(msg))
+
+	controller.RecordSuccessfulReconcile(p, time.Now())
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+}
+
+// tracksTag returns true if the supplied package tracks a tag, and its source
+// is pinned to a digest that the tag could advance past.
+func tracksTag(p v1.Package) bool {
+	if p.GetTrackTag() == nil {
+		return false
+	}
+	_, err := name.NewDigest(p.GetSource())
+	return err == nil
+}
+
+// checkTrackedTag returns a condition indicating whether the supplied
+// package's tracked tag resolves to a different digest than the one its
+// source is pinned to. It never changes the package's source.
+func (r *Reconciler) checkTrackedTag(ctx context.Context, p v1.Package, extraPullSecrets ...string) xpv1.Condition {
+	pinned, err := name.NewDigest(p.GetSource())
+	if err != nil {
+		return v1.UnknownUpdateAvailability().WithMessage(errNotPinned)
+	}
+	d, err := r.tag.TrackedDigest(ctx, p, extraPullSecrets...)
+	if err != nil {
+		return v1.UnknownUpdateAvailability().WithMessage(err.Error())
+	}
+	if d == "" {
+		return v1.UnknownUpdateAvailability()
+	}
+	if d != pinned.DigestStr() {
+		return v1.UpdateAvailable(*p.GetTrackTag(), d)
+	}
+	return v1.NoUpdateAvailable()
+}
+
+// checkResolvedDigest records the supplied digest the package's source
+// resolved to. It raises the DigestChanged condition and emits an event if the
+// source is unchanged but now resolves to a different digest than the one
+// previously recorded.
+func (r *Reconciler) checkResolvedDigest(p v1.Package, d string) {
+	if p.GetCurrentIdentifier() != p.GetSource() {
+		// The source changed, so any previously recorded digest is for a
+		// different source.
+		p.SetResolvedDigest("")
+		if p.GetCondition(v1.TypeDigestChanged).Status == corev1.ConditionTrue {
+			p.SetConditions(v1.DigestUnchanged())
+		}
+	}
+
+	// The source doesn't reference a tag, or it wasn't resolved because the
+	// package keeps its current revision. We'll try again next time.
+	if d == "" {
+		return
+	}
+
+	if prev := p.GetResolvedDigest(); prev != "" && prev != d {
+		p.SetConditions(v1.DigestChanged(p.GetSource(), prev, d))
+		r.record.Event(p, event.Normal(reasonDigestChanged, fmt.Sprintf("Package source %q resolved to digest %s, previously %s", p.GetSource(), d, prev)))
+	}
+	p.SetResolvedDigest(d)
 }
 
 func enqueueProvidersForImageConfig(kube client.Client, log logging.Logger) handler.EventHandler {
@@ -19,7 +19,9 @@ package manager
 import (
 	"context"
 	"io"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -27,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -40,31 +43,45 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/pkgtest"
+	"github.com/crossplane/crossplane/internal/xpkg"
 	"github.com/crossplane/crossplane/internal/xpkg/fake"
 )
 
 var _ Revisioner = &MockRevisioner{}
 
 type MockRevisioner struct {
-	MockRevision func() (string, error)
+	MockRevision func() (Resolution, error)
 }
 
-func NewMockRevisionFn(hash string, err error) func() (string, error) {
-	return func() (string, error) {
-		return hash, err
+func NewMockRevisionFn(hash string, err error) func() (Resolution, error) {
+	return func() (Resolution, error) {
+		return Resolution{Revision: hash}, err
 	}
 }
 
-func (m *MockRevisioner) Revision(context.Context, v1.Package, ...string) (string, error) {
+func (m *MockRevisioner) Revision(context.Context, v1.Package, ...string) (Resolution, error) {
 	return m.MockRevision()
 }
 
+var _ TagTracker = &MockTagTracker{}
+
+type MockTagTracker struct {
+	MockTrackedDigest func() (string, error)
+}
+
+func (m *MockTagTracker) TrackedDigest(context.Context, v1.Package, ...string) (string, error) {
+	return m.MockTrackedDigest()
+}
+
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
+	errProxy := &net.OpError{Op: "proxyconnect", Net: "tcp", Err: errors.New("connection refused")}
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 	pullAlways := corev1.PullAlways
 	trueVal := true
 	revHistory := int64(1)
+	allowed, _ := xpkg.NewRegistryAllowList([]string{"xpkg.upbound.io/crossplane-contrib"}, xpkg.DefaultRegistry)
 
 	type args struct {
 		req reconcile.Request
@@ -171,6 +188,43 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errGetPullConfig),
 			},
 		},
+		"RegistryNotAllowed": {
+			reason: "We should not unpack a package whose source isn't from an allowed registry.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.(*v1.Configuration).SetSource("registry.example.org/crossplane-contrib/configuration-cool:v1.0.0")
+								return nil
+							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetSource("registry.example.org/crossplane-contrib/configuration-cool:v1.0.0")
+								want.SetConditions(v1.RegistryNotAllowed().WithMessage(errors.Errorf(errFmtRegistryNotAllowed, "registry.example.org/crossplane-contrib/configuration-cool:v1.0.0", allowed).Error()))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					log:     testLog,
+					record:  event.NewNopRecorder(),
+					allowed: allowed,
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errBoom),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"ErrFetchRevision": {
 			reason: "We should return an error if fetching the revision for a package fails.",
 			args: args{
@@ -209,6 +263,44 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errUnpack),
 			},
 		},
+		"ErrFetchRevisionProxyUnreachable": {
+			reason: "We should report that the registry proxy is unreachable if we can't connect to it while fetching the revision for a package.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  test.NewMockGetFn(nil),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetConditions(v1.UnpackingProxyUnreachable().WithMessage(errors.Wrap(errProxy, errUnpack).Error()))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errProxy),
+					},
+					config: &fake.MockConfigStore{
+						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errProxy, errUnpack),
+			},
+		},
 		"SuccessfulNoExistingRevisionsAutoActivate": {
 			reason: "We should be active and not requeue on successful creation of the first revision with auto activation.",
 			args: args{
@@ -235,7 +327,8 @@ func TestReconcile(t *testing.T) {
 								want.SetActivationPolicy(&v1.AutomaticActivation)
 								want.SetConditions(v1.UnknownHealth())
 								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -287,7 +380,8 @@ func TestReconcile(t *testing.T) {
 								want.SetPackagePullPolicy(&pullAlways)
 								want.SetConditions(v1.UnknownHealth())
 								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -337,7 +431,8 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.UnknownHealth())
 								want.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -398,7 +493,8 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.Healthy())
 								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime(), test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -462,7 +558,8 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.Healthy())
 								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime(), test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -604,7 +701,8 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.Unhealthy().WithMessage("some message"))
 								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime(), test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -686,7 +784,8 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.Healthy())
 								want.SetConditions(v1.Active())
-								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime(), test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -810,6 +909,111 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errGCPackageRevision),
 			},
 		},
+		"RollbackRevisionNotFound": {
+			reason: "We should report that we can't roll back, and not requeue, if the revision to roll back to doesn't exist.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.(*v1.Configuration).SetRollbackRevision(ptr.To("test-1234567"))
+								return nil
+							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetRollbackRevision(ptr.To("test-1234567"))
+								want.SetConditions(v1.RollbackRevisionNotFound().WithMessage(errors.Errorf(errFmtRollbackRevisionNotFound, "test-1234567").Error()))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errBoom),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulRollback": {
+			reason: "We should activate the revision to roll back to, deactivate other revisions, record it as the current revision, and match its health without unpacking the package.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetSource("xpkg.upbound.io/cool/config:v2")
+								p.SetCurrentRevision("test-new")
+								p.SetCurrentIdentifier("xpkg.upbound.io/cool/config:v2")
+								p.SetRollbackRevision(ptr.To("test-old"))
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								old := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-old"}}
+								old.SetSource("xpkg.upbound.io/cool/config:v1")
+								old.SetConditions(v1.Healthy())
+								old.SetDesiredState(v1.PackageRevisionInactive)
+								old.SetRevision(1)
+								cur := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "test-new"}}
+								cur.SetConditions(v1.Unhealthy())
+								cur.SetDesiredState(v1.PackageRevisionActive)
+								cur.SetRevision(2)
+								*o.(*v1.ConfigurationRevisionList) = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{old, cur}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetSource("xpkg.upbound.io/cool/config:v2")
+								want.SetCurrentRevision("test-old")
+								want.SetCurrentIdentifier("xpkg.upbound.io/cool/config:v1")
+								want.SetRollbackRevision(ptr.To("test-old"))
+								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.RolledBack().WithMessage(`Rolled back to package revision "test-old"`))
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime(), test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := map[string]v1.PackageRevisionDesiredState{
+								"test-old": v1.PackageRevisionActive,
+								"test-new": v1.PackageRevisionInactive,
+							}
+							if diff := cmp.Diff(want[o.GetName()], o.(*v1.ConfigurationRevision).GetDesiredState()); diff != "" {
+								t.Errorf("%s: -want, +got:\n%s", o.GetName(), diff)
+							}
+							return nil
+						}),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errBoom),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"PauseReconcile": {
 			reason: "Pause reconciliation if the pause annotation is set",
 			args: args{
@@ -924,3 +1128,170 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckTrackedTag(t *testing.T) {
+	errBoom := errors.New("boom")
+	tag := "v1.0.0"
+	pinned := "crossplane-contrib/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+
+	type args struct {
+		tag TagTracker
+		pkg v1.Package
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   commonv1.Condition
+	}{
+		"NotPinned": {
+			reason: "We should report unknown update availability if the package isn't pinned to a digest.",
+			args: args{
+				pkg: &v1.Provider{Spec: v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: "crossplane-contrib/provider-nop:v0.1.0", TrackTag: &tag}}},
+			},
+			want: v1.UnknownUpdateAvailability().WithMessage(errNotPinned),
+		},
+		"TrackedDigestError": {
+			reason: "We should report unknown update availability if we can't resolve the tracked tag.",
+			args: args{
+				tag: &MockTagTracker{MockTrackedDigest: func() (string, error) { return "", errBoom }},
+				pkg: &v1.Provider{Spec: v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: pinned, TrackTag: &tag}}},
+			},
+			want: v1.UnknownUpdateAvailability().WithMessage(errBoom.Error()),
+		},
+		"UpdateAvailable": {
+			reason: "We should report an update is available if the tracked tag resolves to a different digest.",
+			args: args{
+				tag: &MockTagTracker{MockTrackedDigest: func() (string, error) { return "sha256:d4aafa0da16d", nil }},
+				pkg: &v1.Provider{Spec: v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: pinned, TrackTag: &tag}}},
+			},
+			want: v1.UpdateAvailable(tag, "sha256:d4aafa0da16d"),
+		},
+		"NoUpdateAvailable": {
+			reason: "We should report no update is available if the tracked tag resolves to the pinned digest.",
+			args: args{
+				tag: &MockTagTracker{MockTrackedDigest: func() (string, error) {
+					return "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904", nil
+				}},
+				pkg: &v1.Provider{Spec: v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: pinned, TrackTag: &tag}}},
+			},
+			want: v1.NoUpdateAvailable(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{tag: tc.args.tag}
+			got := r.checkTrackedTag(context.Background(), tc.args.pkg)
+			if diff := cmp.Diff(tc.want, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nr.checkTrackedTag(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckResolvedDigest(t *testing.T) {
+	source := "crossplane-contrib/provider-nop:v0.1.0"
+
+	// GetCondition returns this when a package has no DigestChanged condition.
+	none := commonv1.Condition{Type: v1.TypeDigestChanged, Status: corev1.ConditionUnknown}
+
+	type args struct {
+		digest string
+		pkg    *v1.Provider
+	}
+	type want struct {
+		digest string
+		cond   commonv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FirstResolution": {
+			reason: "We should record the resolved digest without raising a condition the first time a source is resolved.",
+			args: args{
+				digest: "sha256:a",
+				pkg:    &v1.Provider{Spec: v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: source}}},
+			},
+			want: want{
+				digest: "sha256:a",
+				cond:   none,
+			},
+		},
+		"DigestUnchanged": {
+			reason: "We should not raise a condition if the source still resolves to the recorded digest.",
+			args: args{
+				digest: "sha256:a",
+				pkg: &v1.Provider{
+					Spec:   v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: source}},
+					Status: v1.ProviderStatus{PackageStatus: v1.PackageStatus{CurrentIdentifier: source, ResolvedDigest: "sha256:a"}},
+				},
+			},
+			want: want{
+				digest: "sha256:a",
+				cond:   none,
+			},
+		},
+		"DigestChanged": {
+			reason: "We should record the new digest and raise a condition if the source resolves to a different digest.",
+			args: args{
+				digest: "sha256:b",
+				pkg: &v1.Provider{
+					Spec:   v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: source}},
+					Status: v1.ProviderStatus{PackageStatus: v1.PackageStatus{CurrentIdentifier: source, ResolvedDigest: "sha256:a"}},
+				},
+			},
+			want: want{
+				digest: "sha256:b",
+				cond:   v1.DigestChanged(source, "sha256:a", "sha256:b"),
+			},
+		},
+		"SourceChanged": {
+			reason: "We should not raise a condition, and should clear an existing one, if the package's source changed.",
+			args: args{
+				digest: "sha256:b",
+				pkg: func() *v1.Provider {
+					p := &v1.Provider{
+						Spec:   v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: source}},
+						Status: v1.ProviderStatus{PackageStatus: v1.PackageStatus{CurrentIdentifier: "crossplane-contrib/provider-nop:v0.0.1", ResolvedDigest: "sha256:a"}},
+					}
+					p.SetConditions(v1.DigestChanged("crossplane-contrib/provider-nop:v0.0.1", "sha256:0", "sha256:a"))
+					return p
+				}(),
+			},
+			want: want{
+				digest: "sha256:b",
+				cond:   v1.DigestUnchanged(),
+			},
+		},
+		"ResolveError": {
+			reason: "We should leave the recorded digest unchanged if we can't resolve the source.",
+			args: args{
+				pkg: &v1.Provider{
+					Spec:   v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: source}},
+					Status: v1.ProviderStatus{PackageStatus: v1.PackageStatus{CurrentIdentifier: source, ResolvedDigest: "sha256:a"}},
+				},
+			},
+			want: want{
+				digest: "sha256:a",
+				cond:   none,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{log: logging.NewNopLogger(), record: event.NewNopRecorder()}
+			r.checkResolvedDigest(tc.args.pkg, tc.args.digest)
+			if diff := cmp.Diff(tc.want.digest, tc.args.pkg.GetResolvedDigest()); diff != "" {
+				t.Errorf("\n%s\nr.checkResolvedDigest(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cond, tc.args.pkg.GetCondition(v1.TypeDigestChanged), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nr.checkResolvedDigest(...): -want condition, +got condition:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
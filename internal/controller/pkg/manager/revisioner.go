@@ -18,9 +18,12 @@ package manager
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
@@ -29,13 +32,30 @@ import (
 )
 
 const (
-	errBadReference = "package tag is not a valid reference"
-	errFetchPackage = "failed to fetch package digest from remote"
+	errBadReference  = "package tag is not a valid reference"
+	errFetchPackage  = "failed to fetch package digest from remote"
+	errBadTrackedTag = "tracked tag is not a valid tag"
+	errFetchTag      = "failed to fetch tracked tag digest from remote"
 )
 
 // Revisioner extracts a revision name for a package source.
 type Revisioner interface {
-	Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, error)
+	Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (Resolution, error)
+}
+
+// A Resolution is the result of resolving a package source.
+type Resolution struct {
+	// Revision is the name of the package revision the source resolves to.
+	Revision string
+
+	// Digest is the digest the source's tag resolved to. It's empty if the
+	// source doesn't reference a tag, or if the tag wasn't resolved.
+	Digest string
+}
+
+// A TagTracker resolves the digest a package's tracked tag refers to.
+type TagTracker interface {
+	TrackedDigest(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, error)
 }
 
 // PackageRevisioner extracts a revision name for a package source.
@@ -65,31 +85,134 @@ func NewPackageRevisioner(fetcher xpkg.Fetcher, opts ...PackageRevisionerOption)
 	return r
 }
 
-// Revision extracts a revision name for a package source.
-func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, error) {
+// Revision extracts a revision name for a package source. If the source
+// references a tag the Resolution also includes the digest the tag resolved
+// to. Packages with pull policy IfNotPresent keep their current revision
+// without contacting the registry while their source is unchanged, so their
+// tag isn't resolved.
+func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package, extraPullSecrets ...string) (Resolution, error) {
 	pullPolicy := p.GetPackagePullPolicy()
 	if pullPolicy != nil && *pullPolicy == corev1.PullNever {
-		return xpkg.FriendlyID(p.GetName(), p.GetSource()), nil
+		return Resolution{Revision: xpkg.FriendlyID(p.GetName(), p.GetSource())}, nil
 	}
 	if pullPolicy != nil && *pullPolicy == corev1.PullIfNotPresent {
 		if p.GetCurrentIdentifier() == p.GetSource() {
-			return p.GetCurrentRevision(), nil
+			return Resolution{Revision: p.GetCurrentRevision()}, nil
 		}
 	}
 	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(r.registry))
 	if err != nil {
-		return "", errors.Wrap(err, errBadReference)
+		return Resolution{}, errors.Wrap(err, errBadReference)
 	}
+	_, tag := ref.(name.Tag)
 
 	ps := v1.RefNames(p.GetPackagePullSecrets())
 	if len(extraPullSecrets) > 0 {
 		ps = append(ps, extraPullSecrets...)
 	}
 	d, err := r.fetcher.Head(ctx, ref, ps...)
-	if err != nil || d == nil {
-		return "", errors.Wrap(err, errFetchPackage)
+	if err != nil {
+		return Resolution{}, errors.Wrap(err, errFetchPackage)
+	}
+	if d == nil {
+		return Resolution{}, errors.New(errFetchPackage)
+	}
+	res := Resolution{Revision: xpkg.FriendlyID(p.GetName(), d.Digest.Hex)}
+	if tag {
+		res.Digest = d.Digest.String()
+	}
+	return res, nil
+}
+
+// TrackedDigest resolves the digest the supplied package's tracked tag refers
+// to. The tag is resolved within the repository of the package source. An
+// empty digest is returned if the package doesn't track a tag.
+func (r *PackageRevisioner) TrackedDigest(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, error) {
+	tag := p.GetTrackTag()
+	if tag == nil {
+		return "", nil
+	}
+	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		return "", errors.Wrap(err, errBadReference)
+	}
+	t, err := name.NewTag(ref.Context().Tag(*tag).String(), name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		return "", errors.Wrap(err, errBadTrackedTag)
+	}
+
+	ps := v1.RefNames(p.GetPackagePullSecrets())
+	if len(extraPullSecrets) > 0 {
+		ps = append(ps, extraPullSecrets...)
+	}
+	d, err := r.fetcher.Head(ctx, t, ps...)
+	if err != nil {
+		return "", errors.Wrap(err, errFetchTag)
+	}
+	if d == nil {
+		return "", errors.New(errFetchTag)
+	}
+	return d.Digest.String(), nil
+}
+
+// A CachingTagTracker caches the digests resolved by another TagTracker, so
+// that each package's tracked tag is resolved at most once per TTL no matter
+// how often the package is reconciled. Errors aren't cached. Expired digests
+// are evicted whenever a digest is resolved.
+type CachingTagTracker struct {
+	wrapped TagTracker
+	ttl     time.Duration
+	now     func() time.Time
+
+	mx     sync.Mutex
+	digest map[types.UID]trackedDigest
+}
+
+type trackedDigest struct {
+	ref      string
+	digest   string
+	resolved time.Time
+}
+
+// NewCachingTagTracker returns a TagTracker that caches the digests resolved
+// by the supplied TagTracker for the supplied TTL.
+func NewCachingTagTracker(t TagTracker, ttl time.Duration) *CachingTagTracker {
+	return &CachingTagTracker{wrapped: t, ttl: ttl, now: time.Now, digest: make(map[types.UID]trackedDigest)}
+}
+
+// TrackedDigest returns the cached digest the supplied package's tracked tag
+// refers to, if it was resolved within the TTL. Otherwise it resolves and
+// caches the digest.
+func (c *CachingTagTracker) TrackedDigest(ctx context.Context, p v1.Package, extraPullSecrets ...string) (string, error) {
+	tag := p.GetTrackTag()
+	if tag == nil {
+		return "", nil
+	}
+	ref := p.GetSource() + ":" + *tag
+
+	c.mx.Lock()
+	cached, ok := c.digest[p.GetUID()]
+	c.mx.Unlock()
+	if ok && cached.ref == ref && c.now().Sub(cached.resolved) < c.ttl {
+		return cached.digest, nil
+	}
+
+	d, err := c.wrapped.TrackedDigest(ctx, p, extraPullSecrets...)
+	if err != nil {
+		return "", err
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	// Evict expired digests while we hold the lock, so that the cache doesn't
+	// grow without bound as packages are deleted.
+	for uid, cached := range c.digest {
+		if c.now().Sub(cached.resolved) >= c.ttl {
+			delete(c.digest, uid)
+		}
 	}
-	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), nil
+	c.digest[p.GetUID()] = trackedDigest{ref: ref, digest: d, resolved: c.now()}
+	return d, nil
 }
 
 // NopRevisioner returns an empty revision name.
@@ -101,6 +224,19 @@ func NewNopRevisioner() *NopRevisioner {
 }
 
 // Revision returns an empty revision name and no error.
-func (d *NopRevisioner) Revision(context.Context, v1.Package, ...string) (string, error) {
+func (d *NopRevisioner) Revision(context.Context, v1.Package, ...string) (Resolution, error) {
+	return Resolution{}, nil
+}
+
+// NopTagTracker never resolves a tracked tag.
+type NopTagTracker struct{}
+
+// NewNopTagTracker creates a NopTagTracker.
+func NewNopTagTracker() *NopTagTracker {
+	return &NopTagTracker{}
+}
+
+// TrackedDigest returns an empty digest and no error.
+func (t *NopTagTracker) TrackedDigest(context.Context, v1.Package, ...string) (string, error) {
 	return "", nil
 }
@@ -19,6 +19,7 @@ package manager
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	conregv1 "github.com/google/go-containerregistry/pkg/v1"
@@ -45,8 +46,8 @@ func TestPackageRevisioner(t *testing.T) {
 	}
 
 	type want struct {
-		err    error
-		digest string
+		err error
+		res Resolution
 	}
 
 	cases := map[string]struct {
@@ -70,7 +71,7 @@ func TestPackageRevisioner(t *testing.T) {
 				},
 			},
 			want: want{
-				digest: "provider-aws-my-revision",
+				res: Resolution{Revision: "provider-aws-my-revision"},
 			},
 		},
 		"SuccessfulPullIfNotPresentSameSource": {
@@ -95,7 +96,74 @@ func TestPackageRevisioner(t *testing.T) {
 				},
 			},
 			want: want{
-				digest: "return-me",
+				res: Resolution{Revision: "return-me"},
+			},
+		},
+		"PullIfNotPresentSameSourceNoHead": {
+			reason: "Should not resolve the tag of a package with pull policy IfNotPresent if identifier did not change.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead: func() (*conregv1.Descriptor, error) {
+						t.Errorf("Head was called for a package with pull policy IfNotPresent whose identifier did not change")
+						return nil, errBoom
+					},
+				},
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-aws",
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package:           "crossplane/provider-aws:latest",
+							PackagePullPolicy: &pullIfNotPresent,
+						},
+					},
+					Status: v1.ProviderStatus{
+						PackageStatus: v1.PackageStatus{
+							CurrentRevision:   "return-me",
+							CurrentIdentifier: "crossplane/provider-aws:latest",
+						},
+					},
+				},
+			},
+			want: want{
+				res: Resolution{Revision: "return-me"},
+			},
+		},
+		"SuccessfulPullIfNotPresentNewSource": {
+			reason: "Should return the revision and the digest the package source's tag resolves to if identifier changed.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead: fake.NewMockHeadFn(&conregv1.Descriptor{
+						Digest: conregv1.Hash{
+							Algorithm: "sha256",
+							Hex:       "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+						},
+					}, nil),
+				},
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-aws",
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package:           "crossplane/provider-aws:latest",
+							PackagePullPolicy: &pullIfNotPresent,
+						},
+					},
+					Status: v1.ProviderStatus{
+						PackageStatus: v1.PackageStatus{
+							CurrentRevision:   "provider-aws-1234567",
+							CurrentIdentifier: "crossplane/provider-aws:v0.1.0",
+						},
+					},
+				},
+			},
+			want: want{
+				res: Resolution{
+					Revision: "provider-aws-ecc25c121431",
+					Digest:   "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+				},
 			},
 		},
 		"SuccessfulDigest": {
@@ -122,7 +190,36 @@ func TestPackageRevisioner(t *testing.T) {
 				},
 			},
 			want: want{
-				digest: "provider-nop-ecc25c121431",
+				res: Resolution{Revision: "provider-nop-ecc25c121431"},
+			},
+		},
+		"SuccessfulTag": {
+			reason: "Should return the revision and the digest the package source's tag resolves to.",
+			args: args{
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-nop",
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane-contrib/provider-nop:latest",
+						},
+					},
+				},
+				f: &fake.MockFetcher{
+					MockHead: fake.NewMockHeadFn(&conregv1.Descriptor{
+						Digest: conregv1.Hash{
+							Algorithm: "sha256",
+							Hex:       "ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+						},
+					}, nil),
+				},
+			},
+			want: want{
+				res: Resolution{
+					Revision: "provider-nop-ecc25c121431",
+					Digest:   "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+				},
 			},
 		},
 		"ErrParseRef": {
@@ -158,19 +255,278 @@ func TestPackageRevisioner(t *testing.T) {
 				err: errors.Wrap(errBoom, errFetchPackage),
 			},
 		},
+		"ErrNoDescriptor": {
+			reason: "Should return an error if fetching the package image returns no descriptor.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead: fake.NewMockHeadFn(nil, nil),
+				},
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "test/test:test",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.New(errFetchPackage),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			r := NewPackageRevisioner(tc.args.f)
-			h, err := r.Revision(context.TODO(), tc.args.pkg, tc.args.pullSecretFromConfig)
+			res, err := r.Revision(context.TODO(), tc.args.pkg, tc.args.pullSecretFromConfig)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.digest, h, test.EquateErrors()); diff != "" {
+			if diff := cmp.Diff(tc.want.res, res); diff != "" {
 				t.Errorf("\n%s\nr.Name(...): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
+
+func TestPackageRevisionerTrackedDigest(t *testing.T) {
+	errBoom := errors.New("boom")
+	tag := "v1.0.0"
+
+	type args struct {
+		f   xpkg.Fetcher
+		pkg v1.Package
+	}
+
+	type want struct {
+		err    error
+		digest string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoTrackedTag": {
+			reason: "Should return an empty digest if the package doesn't track a tag.",
+			args: args{
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "crossplane-contrib/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+						},
+					},
+				},
+			},
+		},
+		"SuccessfulDigest": {
+			reason: "Should return the digest the tracked tag refers to.",
+			args: args{
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package:  "crossplane-contrib/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+							TrackTag: &tag,
+						},
+					},
+				},
+				f: &fake.MockFetcher{
+					MockHead: fake.NewMockHeadFn(&conregv1.Descriptor{
+						Digest: conregv1.Hash{
+							Algorithm: "sha256",
+							Hex:       "d4aafa0da16d258090e0443904ecc25c121431dfc7058754427f97c034ecde26",
+						},
+					}, nil),
+				},
+			},
+			want: want{
+				digest: "sha256:d4aafa0da16d258090e0443904ecc25c121431dfc7058754427f97c034ecde26",
+			},
+		},
+		"ErrParseRef": {
+			reason: "Should return an error if we cannot parse reference from package source image.",
+			args: args{
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package:  "*THISISNOTVALID",
+							TrackTag: &tag,
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("could not parse reference: *THISISNOTVALID"), errBadReference),
+			},
+		},
+		"ErrNoDescriptor": {
+			reason: "Should return an error if fetching the tracked tag returns no descriptor.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead: fake.NewMockHeadFn(nil, nil),
+				},
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package:  "test/test:test",
+							TrackTag: &tag,
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.New(errFetchTag),
+			},
+		},
+		"ErrBadFetch": {
+			reason: "Should return an error if we fail to fetch the tracked tag.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockHead: fake.NewMockHeadFn(nil, errBoom),
+				},
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package:  "test/test:test",
+							TrackTag: &tag,
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchTag),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewPackageRevisioner(tc.args.f)
+			d, err := r.TrackedDigest(context.TODO(), tc.args.pkg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.TrackedDigest(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.digest, d); diff != "" {
+				t.Errorf("\n%s\nr.TrackedDigest(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type countingTagTracker struct {
+	digest string
+	err    error
+	calls  int
+}
+
+func (t *countingTagTracker) TrackedDigest(_ context.Context, _ v1.Package, _ ...string) (string, error) {
+	t.calls++
+	return t.digest, t.err
+}
+
+func TestCachingTagTracker(t *testing.T) {
+	errBoom := errors.New("boom")
+	tag := "v1.0.0"
+	now := time.Now()
+
+	pkg := func(source string) v1.Package {
+		return &v1.Provider{
+			ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"},
+			Spec:       v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: source, TrackTag: &tag}},
+		}
+	}
+	other := &v1.Provider{
+		ObjectMeta: metav1.ObjectMeta{UID: "other-uid"},
+		Spec:       v1.ProviderSpec{PackageSpec: v1.PackageSpec{Package: "crossplane-contrib/provider-other@sha256:a", TrackTag: &tag}},
+	}
+
+	type call struct {
+		pkg    v1.Package
+		at     time.Time
+		digest string
+		err    error
+	}
+
+	type want struct {
+		calls  int
+		cached int
+	}
+
+	cases := map[string]struct {
+		reason  string
+		wrapped *countingTagTracker
+		calls   []call
+		want    want
+	}{
+		"WithinTTL": {
+			reason:  "We should only resolve the tracked tag once within the TTL.",
+			wrapped: &countingTagTracker{digest: "sha256:cool"},
+			calls: []call{
+				{pkg: pkg("crossplane-contrib/provider-nop@sha256:a"), at: now, digest: "sha256:cool"},
+				{pkg: pkg("crossplane-contrib/provider-nop@sha256:a"), at: now.Add(30 * time.Second), digest: "sha256:cool"},
+			},
+			want: want{calls: 1, cached: 1},
+		},
+		"Expired": {
+			reason:  "We should resolve the tracked tag again once the TTL has passed.",
+			wrapped: &countingTagTracker{digest: "sha256:cool"},
+			calls: []call{
+				{pkg: pkg("crossplane-contrib/provider-nop@sha256:a"), at: now, digest: "sha256:cool"},
+				{pkg: pkg("crossplane-contrib/provider-nop@sha256:a"), at: now.Add(2 * time.Minute), digest: "sha256:cool"},
+			},
+			want: want{calls: 2, cached: 1},
+		},
+		"SourceChanged": {
+			reason:  "We should resolve the tracked tag again if the package's source changed.",
+			wrapped: &countingTagTracker{digest: "sha256:cool"},
+			calls: []call{
+				{pkg: pkg("crossplane-contrib/provider-nop@sha256:a"), at: now, digest: "sha256:cool"},
+				{pkg: pkg("crossplane-contrib/provider-nop@sha256:b"), at: now, digest: "sha256:cool"},
+			},
+			want: want{calls: 2, cached: 1},
+		},
+		"ErrorsNotCached": {
+			reason:  "We shouldn't cache errors resolving the tracked tag.",
+			wrapped: &countingTagTracker{err: errBoom},
+			calls: []call{
+				{pkg: pkg("crossplane-contrib/provider-nop@sha256:a"), at: now, err: errBoom},
+				{pkg: pkg("crossplane-contrib/provider-nop@sha256:a"), at: now, err: errBoom},
+			},
+			want: want{calls: 2},
+		},
+		"ExpiredEvicted": {
+			reason:  "We should evict expired digests, for example those of deleted packages, when we resolve a tracked tag.",
+			wrapped: &countingTagTracker{digest: "sha256:cool"},
+			calls: []call{
+				{pkg: pkg("crossplane-contrib/provider-nop@sha256:a"), at: now, digest: "sha256:cool"},
+				{pkg: other, at: now.Add(2 * time.Minute), digest: "sha256:cool"},
+			},
+			want: want{calls: 2, cached: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewCachingTagTracker(tc.wrapped, time.Minute)
+			for i, call := range tc.calls {
+				c.now = func() time.Time { return call.at }
+				d, err := c.TrackedDigest(context.TODO(), call.pkg)
+				if diff := cmp.Diff(call.err, err, test.EquateErrors()); diff != "" {
+					t.Errorf("\n%s\nc.TrackedDigest(...) call %d: -want error, +got error:\n%s", tc.reason, i, diff)
+				}
+				if diff := cmp.Diff(call.digest, d); diff != "" {
+					t.Errorf("\n%s\nc.TrackedDigest(...) call %d: -want, +got:\n%s", tc.reason, i, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.calls, tc.wrapped.calls); diff != "" {
+				t.Errorf("\n%s\nc.TrackedDigest(...): -want wrapped calls, +got wrapped calls:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cached, len(c.digest)); diff != "" {
+				t.Errorf("\n%s\nc.TrackedDigest(...): -want cached digests, +got cached digests:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
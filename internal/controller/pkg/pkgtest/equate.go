@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pkgtest contains helpers for testing package controllers.
+package pkgtest
+
+import (
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EquateApproxTime returns a cmp.Option that considers two times equal if
+// they're within a few seconds of each other. It's useful for comparing times
+// that a controller sets to the time of reconcile, like the time a package or
+// package revision was last successfully reconciled.
+func EquateApproxTime() cmp.Option {
+	return cmp.Options{
+		cmp.Transformer("MetaTime", func(t *metav1.Time) time.Time {
+			if t == nil {
+				return time.Time{}
+			}
+			return t.Time
+		}),
+		cmpopts.EquateApproxTime(3 * time.Second),
+	}
+}
@@ -84,6 +84,10 @@ const (
 	errFmtDiffConstraintTypes = "a dependency package has different types of parent constraints (%v)"
 	errFmtDiffDigests         = "a dependency package has different digests in parent constraints (%v)"
 	errCannotUpdateStatus     = "cannot update status"
+	errParseUpstream          = "cannot parse upstream dependency package"
+	errMirrorDependency       = "cannot mirror dependency package"
+	errCheckAllowedRegistry   = "cannot check whether dependency package is from an allowed registry"
+	errFmtRegistryNotAllowed  = "dependency package %q isn't from an allowed registry - allowed registries are %s"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -131,6 +135,30 @@ func WithDefaultRegistry(registry string) ReconcilerOption {
 	}
 }
 
+// WithMirror specifies that the Reconciler should copy dependencies to the
+// supplied mirror registry, and install them from there.
+func WithMirror(m *xpkg.Mirror) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.mirror = m
+	}
+}
+
+// WithPusher specifies how the Reconciler should push packages to the mirror
+// registry.
+func WithPusher(p xpkg.Pusher) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.pusher = p
+	}
+}
+
+// WithAllowedRegistries specifies the registries the Reconciler may mirror
+// dependency packages from.
+func WithAllowedRegistries(l *xpkg.RegistryAllowList) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.allowed = l
+	}
+}
+
 // WithFeatures specifies which feature flags should be enabled.
 func WithFeatures(f *feature.Flags) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -145,8 +173,11 @@ type Reconciler struct {
 	lock     resource.Finalizer
 	newDag   internaldag.NewDAGFn
 	fetcher  xpkg.Fetcher
+	pusher   xpkg.Pusher
 	config   xpkg.ConfigStore
 	registry string
+	mirror   *xpkg.Mirror
+	allowed  *xpkg.RegistryAllowList
 	features *feature.Flags
 }
 
@@ -174,6 +205,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		opts = append(opts, WithNewDagFn(internaldag.NewUpgradingMapDag))
 	}
 
+	if o.Features.Enabled(features.EnableAlphaDependencyMirroring) {
+		opts = append(opts, WithMirror(xpkg.NewMirror(o.DependencyMirror, o.DefaultRegistry)), WithPusher(f), WithAllowedRegistries(o.AllowedRegistries))
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1beta1.Lock{}).
@@ -199,6 +234,7 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		log:     logging.NewNopLogger(),
 		newDag:  internaldag.NewMapDag,
 		fetcher: xpkg.NewNopFetcher(),
+		pusher:  xpkg.NewNopFetcher(),
 	}
 
 	for _, f := range opts {
@@ -297,6 +333,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
 	}
 
+	// A dependency in the mirror registry is resolved using its upstream
+	// registry. It's copied to the mirror registry before it's installed.
+	upstream, mirrored := ref, false
+	if r.mirror != nil {
+		var u string
+		if u, mirrored = r.mirror.Upstream(depID); mirrored {
+			upstream, err = name.ParseReference(u, name.WithDefaultRegistry(r.registry))
+			if err != nil {
+				log.Debug(errParseUpstream, "error", err)
+				lock.SetConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errParseUpstream)))
+				return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
+			}
+		}
+	}
+
 	var pkg *unstructured.Unstructured
 	var installedVersion string
 	if r.features.Enabled(features.EnableAlphaDependencyVersionUpgrades) {
@@ -335,7 +386,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// At this point, we know that the dependency is either missing or does not satisfy the constraints.
 		// Package does not exist. We need to create it.
 		var addVer string
-		if addVer, err = r.findDependencyVersionToInstall(ctx, dep, log, ref); err != nil {
+		if addVer, err = r.findDependencyVersionToInstall(ctx, dep, log, upstream); err != nil {
 			log.Debug(errFindDependency, "error", errors.Wrapf(err, depID, dep.Constraints))
 			lock.SetConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errFindDependency)))
 			_ = r.client.Status().Update(ctx, lock)
@@ -350,6 +401,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, lock), errCannotUpdateStatus)
 		}
 
+		if mirrored {
+			if err := r.mirrorDependency(ctx, upstream, ref, addVer); err != nil {
+				log.Debug(errMirrorDependency, "error", err)
+				lock.SetConditions(v1beta1.MirrorFailed(errors.Wrap(err, errMirrorDependency)))
+				_ = r.client.Status().Update(ctx, lock)
+				return reconcile.Result{}, errors.Wrap(err, errMirrorDependency)
+			}
+			lock.SetConditions(v1beta1.MirrorSucceeded(fmt.Sprintf("%s:%s", depID, addVer)))
+		}
+
 		pack, err := NewPackage(dep, addVer, ref)
 		if err != nil {
 			log.Debug(errConstructDependency, "error", err)
@@ -387,7 +448,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, errors.Errorf(errFmtMissingDependency, depID)
 	}
 
-	newVer, err := r.findDependencyVersionToUpgrade(ctx, ref, installedVersion, n, log)
+	newVer, err := r.findDependencyVersionToUpgrade(ctx, upstream, installedVersion, n, log)
 	if err != nil {
 		log.Debug(errFindDependencyUpgrade, "error", errors.Wrapf(err, depID, dep.Constraints))
 		lock.SetConditions(v1beta1.ResolutionFailed(errors.Wrap(err, errFindDependencyUpgrade)))
@@ -395,6 +456,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, errors.Wrap(err, errFindDependencyUpgrade)
 	}
 
+	if mirrored {
+		if err := r.mirrorDependency(ctx, upstream, ref, newVer); err != nil {
+			log.Debug(errMirrorDependency, "error", err)
+			lock.SetConditions(v1beta1.MirrorFailed(errors.Wrap(err, errMirrorDependency)))
+			_ = r.client.Status().Update(ctx, lock)
+			return reconcile.Result{}, errors.Wrap(err, errMirrorDependency)
+		}
+		lock.SetConditions(v1beta1.MirrorSucceeded(fmt.Sprintf("%s:%s", depID, newVer)))
+	}
+
 	// Update the package with the new version.
 	format := packageTagFmt
 	if strings.HasPrefix(newVer, "sha256:") {
@@ -467,6 +538,48 @@ func (r *Reconciler) findDependencyVersionToInstall(ctx context.Context, dep *v1
 	return addVer, nil
 }
 
+// mirrorDependency copies the supplied version of an upstream dependency to the
+// mirror registry.
+func (r *Reconciler) mirrorDependency(ctx context.Context, upstream, mirror name.Reference, version string) error {
+	// Don't pull a dependency from a registry packages may not be installed
+	// from. The package manager would refuse to install it from upstream, so
+	// it mustn't be able to sneak in via the mirror.
+	allowed, err := r.allowed.Allowed(upstream.String())
+	if err != nil {
+		return errors.Wrap(err, errCheckAllowedRegistry)
+	}
+	if !allowed {
+		return errors.Errorf(errFmtRegistryNotAllowed, upstream.String(), r.allowed)
+	}
+
+	format := packageTagFmt
+	if strings.HasPrefix(version, "sha256:") {
+		format = packageDigestFmt
+	}
+	from, err := name.ParseReference(fmt.Sprintf(format, upstream.String(), version), name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		return errors.Wrap(err, errParseUpstream)
+	}
+	to, err := name.ParseReference(fmt.Sprintf(format, mirror.String(), version), name.WithDefaultRegistry(r.registry))
+	if err != nil {
+		return errors.Wrap(err, errInvalidDependency)
+	}
+
+	// The upstream and mirror registries may each need a pull secret.
+	var s []string
+	for _, ref := range []name.Reference{upstream, mirror} {
+		_, ps, err := r.config.PullSecretFor(ctx, ref.String())
+		if err != nil {
+			return errors.Wrap(err, errGetPullConfig)
+		}
+		if ps != "" {
+			s = append(s, ps)
+		}
+	}
+
+	return xpkg.Copy(ctx, r.fetcher, r.pusher, from, to, s...)
+}
+
 // FindValidDependencyVersion finds a valid version with version upgrade capability considering parent constraints.
 func (r *Reconciler) findDependencyVersionToUpgrade(ctx context.Context, ref name.Reference, insVer string, dep internaldag.Node, log logging.Logger) (string, error) {
 	// If there is a digest in the parent constraints, we need to make sure that all other parent constraints are the same.
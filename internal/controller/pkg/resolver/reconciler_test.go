@@ -24,6 +24,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	pkgName "github.com/google/go-containerregistry/pkg/name"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -46,6 +47,7 @@ import (
 	"github.com/crossplane/crossplane/internal/dag"
 	fakedag "github.com/crossplane/crossplane/internal/dag/fake"
 	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/xpkg"
 	fakexpkg "github.com/crossplane/crossplane/internal/xpkg/fake"
 )
 
@@ -59,6 +61,11 @@ var (
 	testLog = logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 )
 
+func allowOnly(registries ...string) *xpkg.RegistryAllowList {
+	l, _ := xpkg.NewRegistryAllowList(registries, "xpkg.upbound.io")
+	return l
+}
+
 func TestReconcile(t *testing.T) {
 	upgradesEnabled := &feature.Flags{}
 	upgradesEnabled.Enable(features.EnableAlphaDependencyVersionUpgrades)
@@ -671,6 +678,183 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"ErrorMirrorMissingDependency": {
+			reason: "We should return an error if we can't copy a missing dependency to the mirror registry.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    ptr.To(v1beta1.ProviderPackageType),
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "registry.example.org/mirror/xpkg.upbound.io/hasheddan/config-nop-c",
+										Constraints: ">v1.0.0",
+										Type:        ptr.To(v1beta1.ConfigurationPackageType),
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags:  fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
+						MockFetch: fakexpkg.NewMockFetchFn(nil, nil),
+					}),
+					WithPusher(&fakexpkg.MockPusher{
+						MockPush: fakexpkg.NewMockPushFn(errBoom),
+					}),
+					WithMirror(xpkg.NewMirror("registry.example.org/mirror", "xpkg.upbound.io")),
+					WithConfigStore(&fakexpkg.MockConfigStore{
+						MockPullSecretFor: fakexpkg.NewMockConfigStorePullSecretForFn("", "", nil),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot push package to mirror registry"), errMirrorDependency),
+			},
+		},
+		"ErrorMirrorDependencyFromDisallowedRegistry": {
+			reason: "We should return an error without pulling a missing dependency if it isn't from an allowed registry.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    ptr.To(v1beta1.ProviderPackageType),
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "registry.example.org/mirror/xpkg.upbound.io/hasheddan/config-nop-c",
+										Constraints: ">v1.0.0",
+										Type:        ptr.To(v1beta1.ConfigurationPackageType),
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags:  fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
+						MockFetch: fakexpkg.NewMockFetchFn(nil, errors.New("should not pull from a disallowed registry")),
+					}),
+					WithPusher(&fakexpkg.MockPusher{
+						MockPush: fakexpkg.NewMockPushFn(errors.New("should not push from a disallowed registry")),
+					}),
+					WithMirror(xpkg.NewMirror("registry.example.org/mirror", "xpkg.upbound.io")),
+					WithAllowedRegistries(allowOnly("registry.example.org")),
+					WithConfigStore(&fakexpkg.MockConfigStore{
+						MockPullSecretFor: fakexpkg.NewMockConfigStorePullSecretForFn("", "", nil),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtRegistryNotAllowed, "xpkg.upbound.io/hasheddan/config-nop-c", "registry.example.org"), errMirrorDependency),
+			},
+		},
+		"SuccessfulMirrorMissingDependency": {
+			reason: "We should copy a missing dependency to the mirror registry and install it from there.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							l := o.(*v1beta1.Lock)
+							l.Packages = append(l.Packages, v1beta1.LockPackage{
+								Name:    "cool-package",
+								Type:    ptr.To(v1beta1.ProviderPackageType),
+								Source:  "cool-repo/cool-image",
+								Version: "v0.0.1",
+							})
+							return nil
+						}),
+						MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+							u := obj.(*unstructured.Unstructured)
+							want := "registry.example.org/mirror/xpkg.upbound.io/hasheddan/config-nop-c:v1.2.0"
+							if got, _ := fieldpath.Pave(u.Object).GetString("spec.package"); got != want {
+								return errors.Errorf("want package %q, got %q", want, got)
+							}
+							return nil
+						},
+						MockUpdate:       test.NewMockUpdateFn(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+				},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewDagFn(func() dag.DAG {
+						return &fakedag.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return []dag.Node{
+									&v1beta1.Dependency{
+										Package:     "registry.example.org/mirror/xpkg.upbound.io/hasheddan/config-nop-c",
+										Constraints: ">v1.0.0",
+										Type:        ptr.To(v1beta1.ConfigurationPackageType),
+									},
+								}, nil
+							},
+							MockSort: func() ([]string, error) {
+								return nil, nil
+							},
+						}
+					}),
+					WithFetcher(&fakexpkg.MockFetcher{
+						MockTags:  fakexpkg.NewMockTagsFn([]string{"v0.2.0", "v0.3.0", "v1.0.0", "v1.2.0"}, nil),
+						MockFetch: fakexpkg.NewMockFetchFn(nil, nil),
+					}),
+					WithPusher(&fakexpkg.MockPusher{
+						MockPush: func(ref pkgName.Reference, _ conregv1.Image) error {
+							want := "registry.example.org/mirror/xpkg.upbound.io/hasheddan/config-nop-c:v1.2.0"
+							if ref.String() != want {
+								return errors.Errorf("want push to %q, got %q", want, ref.String())
+							}
+							return nil
+						},
+					}),
+					WithMirror(xpkg.NewMirror("registry.example.org/mirror", "xpkg.upbound.io")),
+					WithConfigStore(&fakexpkg.MockConfigStore{
+						MockPullSecretFor: fakexpkg.NewMockConfigStorePullSecretForFn("", "", nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"SuccessfulCreateMissingDependencyWithDigest": {
 			reason: "We should not requeue if able to create missing dependency with digest.",
 			args: args{
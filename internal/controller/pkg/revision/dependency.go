@@ -19,6 +19,8 @@ package revision
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/Masterminds/semver"
@@ -49,12 +51,39 @@ const (
 	errFmtMissingDependencies    = "missing dependencies: %+v"
 	errDependencyNotInGraph      = "dependency is not present in graph"
 	errDependencyNotLockPackage  = "dependency in graph is not a lock package"
+	errFmtMirrorDependency       = "cannot determine mirror source of dependency %q"
+	errFmtMirrorConflict         = "cannot determine mirror source of conflicting package %q"
+	errGetLock                   = "cannot get lock"
 )
 
 // DependencyManager is a lock on packages.
 type DependencyManager interface {
 	Resolve(ctx context.Context, meta pkgmetav1.Pkg, pr v1.PackageRevision) (found, installed, invalid int, err error)
 	RemoveSelf(ctx context.Context, pr v1.PackageRevision) error
+	Conflicts(ctx context.Context, meta pkgmetav1.Pkg, pr v1.PackageRevision) ([]string, error)
+}
+
+// NewNopDependencyManager returns a new NopDependencyManager.
+func NewNopDependencyManager() *NopDependencyManager {
+	return &NopDependencyManager{}
+}
+
+// A NopDependencyManager assumes a package has no dependencies or conflicts.
+type NopDependencyManager struct{}
+
+// Resolve always returns that no dependencies were found.
+func (*NopDependencyManager) Resolve(_ context.Context, _ pkgmetav1.Pkg, _ v1.PackageRevision) (found, installed, invalid int, err error) {
+	return 0, 0, 0, nil
+}
+
+// RemoveSelf does nothing.
+func (*NopDependencyManager) RemoveSelf(_ context.Context, _ v1.PackageRevision) error {
+	return nil
+}
+
+// Conflicts always returns no conflicts.
+func (*NopDependencyManager) Conflicts(_ context.Context, _ pkgmetav1.Pkg, _ v1.PackageRevision) ([]string, error) {
+	return nil, nil
 }
 
 // PackageDependencyManager is a resolver for packages.
@@ -62,15 +91,33 @@ type PackageDependencyManager struct {
 	client      client.Client
 	newDag      dag.NewDAGFn
 	packageType schema.GroupVersionKind
+	mirror      *xpkg.Mirror
+}
+
+// A PackageDependencyManagerOption configures a PackageDependencyManager.
+type PackageDependencyManagerOption func(m *PackageDependencyManager)
+
+// WithDependencyMirror specifies that dependencies should be installed from
+// the supplied mirror registry, rather than from their upstream registry.
+func WithDependencyMirror(mr *xpkg.Mirror) PackageDependencyManagerOption {
+	return func(m *PackageDependencyManager) {
+		m.mirror = mr
+	}
 }
 
 // NewPackageDependencyManager creates a new PackageDependencyManager.
-func NewPackageDependencyManager(c client.Client, nd dag.NewDAGFn, pkgType schema.GroupVersionKind) *PackageDependencyManager {
-	return &PackageDependencyManager{
+func NewPackageDependencyManager(c client.Client, nd dag.NewDAGFn, pkgType schema.GroupVersionKind, opts ...PackageDependencyManagerOption) *PackageDependencyManager {
+	m := &PackageDependencyManager{
 		client:      c,
 		newDag:      nd,
 		packageType: pkgType,
 	}
+
+	for _, o := range opts {
+		o(m)
+	}
+
+	return m
 }
 
 // Resolve resolves package dependencies.
@@ -80,6 +127,10 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, meta pkgmetav1.P
 		return 0, 0, 0, nil
 	}
 
+	// A Configuration may pin its dependencies using a dependency lock. We
+	// install locked packages rather than resolving version constraints.
+	locked := lockedDependencies(meta)
+
 	// Copy package dependencies into Lock Dependencies.
 	sources := make([]v1beta1.Dependency, len(meta.GetDependencies()))
 	for i, dep := range meta.GetDependencies() {
@@ -103,11 +154,60 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, meta pkgmetav1.P
 			return 0, 0, 0, errors.Errorf("encountered an invalid dependency: package dependencies must specify either a valid type, or an explicit apiVersion, kind, and package")
 		}
 		pdep.Constraints = dep.Version
+
+		// A locked direct dependency is pinned to its exact digest.
+		if l, ok := locked[pdep.Package]; ok {
+			pdep.Constraints = l.Digest
+			delete(locked, pdep.Package)
+		}
+
+		// The dependency will be copied to and installed from the mirror
+		// registry, so that's where we expect to find it.
+		if m.mirror != nil {
+			src, err := m.mirror.Source(pdep.Package)
+			if err != nil {
+				return 0, 0, 0, errors.Wrapf(err, errFmtMirrorDependency, pdep.Package)
+			}
+			pdep.Package = src
+		}
+
 		sources[i] = pdep
 	}
 
+	// Any remaining locked packages are transitive dependencies. The
+	// packages that depend on them constrain them by semantic version, so we
+	// pin them to their exact version rather than their digest. Sort them so
+	// that we produce a stable lock.
+	transitive := make([]string, 0, len(locked))
+	for pkg := range locked {
+		transitive = append(transitive, pkg)
+	}
+	sort.Strings(transitive)
+	for _, pkg := range transitive {
+		l := locked[pkg]
+		pdep := v1beta1.Dependency{
+			APIVersion:  ptr.To(l.APIVersion),
+			Kind:        ptr.To(l.Kind),
+			Package:     l.Package,
+			Constraints: l.Version,
+		}
+		if m.mirror != nil {
+			src, err := m.mirror.Source(pdep.Package)
+			if err != nil {
+				return 0, 0, 0, errors.Wrapf(err, errFmtMirrorDependency, pdep.Package)
+			}
+			pdep.Package = src
+		}
+		sources = append(sources, pdep)
+	}
+
 	found = len(sources)
 
+	conflicts, err := m.conflictSources(meta)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
 	// Get the lock.
 	lock := &v1beta1.Lock{}
 	err = m.client.Get(ctx, types.NamespacedName{Name: lockName}, lock)
@@ -140,6 +240,7 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, meta pkgmetav1.P
 		Source:       lockRef,
 		Version:      prRef.Identifier(),
 		Dependencies: sources,
+		Conflicts:    conflicts,
 	}
 
 	// Delete packages in lock with same name and distinct source
@@ -253,6 +354,84 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, meta pkgmetav1.P
 	return found, installed, invalid, nil
 }
 
+// Conflicts returns a description of each installed package that the supplied
+// package conflicts with. A package conflicts with an installed package if
+// either package declares that it conflicts with the other. Other revisions of
+// the supplied package never conflict with it.
+func (m *PackageDependencyManager) Conflicts(ctx context.Context, meta pkgmetav1.Pkg, pr v1.PackageRevision) ([]string, error) {
+	lock := &v1beta1.Lock{}
+	err := m.client.Get(ctx, types.NamespacedName{Name: lockName}, lock)
+	if kerrors.IsNotFound(err) {
+		// If the lock doesn't exist no packages are installed.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errGetLock)
+	}
+
+	prRef, err := name.ParseReference(pr.GetSource(), name.WithDefaultRegistry(""))
+	if err != nil {
+		return nil, err
+	}
+	src := xpkg.ParsePackageSourceFromReference(prRef)
+
+	declared, err := m.conflictSources(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []string
+	for _, lp := range lock.Packages {
+		if lp.Name == pr.GetName() || lp.Source == src {
+			continue
+		}
+		switch {
+		case slices.Contains(declared, lp.Source):
+			conflicts = append(conflicts, fmt.Sprintf("package %s declares that it conflicts with installed package %s (%s)", src, lp.Source, lp.Name))
+		case slices.Contains(lp.Conflicts, src):
+			conflicts = append(conflicts, fmt.Sprintf("installed package %s (%s) declares that it conflicts with package %s", lp.Source, lp.Name, src))
+		}
+	}
+	return conflicts, nil
+}
+
+// conflictSources returns the sources of the packages the supplied package
+// declares that it conflicts with.
+func (m *PackageDependencyManager) conflictSources(meta pkgmetav1.Pkg) ([]string, error) {
+	if len(meta.GetConflicts()) == 0 {
+		return nil, nil
+	}
+	sources := make([]string, len(meta.GetConflicts()))
+	for i, c := range meta.GetConflicts() {
+		sources[i] = c.Package
+
+		// Installed packages have the mirror registry as their source, so
+		// that's how we expect to find a conflicting package.
+		if m.mirror != nil {
+			src, err := m.mirror.Source(c.Package)
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtMirrorConflict, c.Package)
+			}
+			sources[i] = src
+		}
+	}
+	return sources, nil
+}
+
+// lockedDependencies returns the supplied package's locked dependencies, keyed
+// by package. Only Configurations may lock their dependencies.
+func lockedDependencies(meta pkgmetav1.Pkg) map[string]pkgmetav1.LockedDependency {
+	c, ok := meta.(*pkgmetav1.Configuration)
+	if !ok {
+		return nil
+	}
+	locked := make(map[string]pkgmetav1.LockedDependency, len(c.Spec.DependencyLock))
+	for _, l := range c.Spec.DependencyLock {
+		locked[l.Package] = l
+	}
+	return locked
+}
+
 // RemoveSelf removes a package from the lock.
 func (m *PackageDependencyManager) RemoveSelf(ctx context.Context, pr v1.PackageRevision) error {
 	// Get the lock.
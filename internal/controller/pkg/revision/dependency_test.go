@@ -234,6 +234,74 @@ func TestResolve(t *testing.T) {
 				err:   errors.Errorf(errFmtMissingDependencies, []string{"not-here-1", "not-here-2"}),
 			},
 		},
+		"ErrorSelfNotExistMissingLockedDependencies": {
+			reason: "Should require locked transitive dependencies as well as direct dependencies.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(_ client.Object) error {
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockNodeExists: func(_ string) bool {
+								return false
+							},
+							MockAddNode: func(_ dag.Node) error {
+								return nil
+							},
+							MockAddOrUpdateNodes: func(_ ...dag.Node) {},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									Provider: ptr.To("not-here-1"),
+									Version:  ">=v0.1.0",
+								},
+							},
+						},
+						DependencyLock: []pkgmetav1.LockedDependency{
+							{
+								APIVersion: "pkg.crossplane.io/v1",
+								Kind:       "Provider",
+								Package:    "not-here-1",
+								Version:    "v0.2.0",
+								Digest:     "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904",
+							},
+							{
+								APIVersion: "pkg.crossplane.io/v1",
+								Kind:       "Function",
+								Package:    "not-here-2",
+								Version:    "v0.3.0",
+								Digest:     "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443905",
+							},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.PackageRevisionSpec{
+						Package:      "hasheddan/config-nop-a:v0.0.1",
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				total: 2,
+				err:   errors.Errorf(errFmtMissingDependencies, []string{"not-here-1", "not-here-2"}),
+			},
+		},
 		"ErrorSelfExistMissingDependencies": {
 			reason: "Should return error if self exists and missing dependencies.",
 			args: args{
@@ -618,3 +686,123 @@ func TestResolve(t *testing.T) {
 		})
 	}
 }
+
+func TestConflicts(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	lock := func(pkgs ...v1beta1.LockPackage) func(client.Object) error {
+		return func(obj client.Object) error {
+			l := obj.(*v1beta1.Lock)
+			l.Packages = pkgs
+			return nil
+		}
+	}
+	pr := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "provider-a-1234"},
+		Spec: v1.ProviderRevisionSpec{
+			PackageRevisionSpec: v1.PackageRevisionSpec{
+				Package: "xpkg.crossplane.io/crossplane-contrib/provider-a:v1.0.0",
+			},
+		},
+	}
+
+	type args struct {
+		dep  *PackageDependencyManager
+		meta pkgmetav1.Pkg
+	}
+	type want struct {
+		conflicts []string
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrGetLock": {
+			reason: "We should return any error encountered getting the lock.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				},
+				meta: &pkgmetav1.Provider{},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetLock),
+			},
+		},
+		"NoLock": {
+			reason: "A package can't conflict with anything if there's no lock.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+				},
+				meta: &pkgmetav1.Provider{},
+			},
+			want: want{},
+		},
+		"DeclaresConflict": {
+			reason: "A package conflicts with an installed package it declares a conflict with.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{MockGet: test.NewMockGetFn(nil, lock(v1beta1.LockPackage{
+						Name:   "provider-b-5678",
+						Source: "xpkg.crossplane.io/crossplane-contrib/provider-b",
+					}))},
+				},
+				meta: &pkgmetav1.Provider{Spec: pkgmetav1.ProviderSpec{MetaSpec: pkgmetav1.MetaSpec{
+					Conflicts: []pkgmetav1.Conflict{{Package: "xpkg.crossplane.io/crossplane-contrib/provider-b"}},
+				}}},
+			},
+			want: want{
+				conflicts: []string{"package xpkg.crossplane.io/crossplane-contrib/provider-a declares that it conflicts with installed package xpkg.crossplane.io/crossplane-contrib/provider-b (provider-b-5678)"},
+			},
+		},
+		"InstalledPackageDeclaresConflict": {
+			reason: "A package conflicts with an installed package that declares a conflict with it.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{MockGet: test.NewMockGetFn(nil, lock(v1beta1.LockPackage{
+						Name:      "provider-b-5678",
+						Source:    "xpkg.crossplane.io/crossplane-contrib/provider-b",
+						Conflicts: []string{"xpkg.crossplane.io/crossplane-contrib/provider-a"},
+					}))},
+				},
+				meta: &pkgmetav1.Provider{},
+			},
+			want: want{
+				conflicts: []string{"installed package xpkg.crossplane.io/crossplane-contrib/provider-b (provider-b-5678) declares that it conflicts with package xpkg.crossplane.io/crossplane-contrib/provider-a"},
+			},
+		},
+		"OtherRevisionOfSelf": {
+			reason: "A package never conflicts with other revisions of itself.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{MockGet: test.NewMockGetFn(nil, lock(v1beta1.LockPackage{
+						Name:      "provider-a-0000",
+						Source:    "xpkg.crossplane.io/crossplane-contrib/provider-a",
+						Conflicts: []string{"xpkg.crossplane.io/crossplane-contrib/provider-a"},
+					}))},
+				},
+				meta: &pkgmetav1.Provider{Spec: pkgmetav1.ProviderSpec{MetaSpec: pkgmetav1.MetaSpec{
+					Conflicts: []pkgmetav1.Conflict{{Package: "xpkg.crossplane.io/crossplane-contrib/provider-a"}},
+				}}},
+			},
+			want: want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			conflicts, err := tc.args.dep.Conflicts(context.TODO(), tc.args.meta, pr)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConflicts(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.conflicts, conflicts); diff != "" {
+				t.Errorf("\n%s\nConflicts(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
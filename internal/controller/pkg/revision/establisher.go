@@ -83,15 +83,35 @@ type APIEstablisher struct {
 	client                           client.Client
 	namespace                        string
 	MaxConcurrentPackageEstablishers int
+
+	preserveCRDConversion bool
+}
+
+// An APIEstablisherOption configures an APIEstablisher.
+type APIEstablisherOption func(*APIEstablisher)
+
+// WithPreservedCRDConversion configures the APIEstablisher to leave the
+// conversion config of existing CRDs untouched, so that it may be managed by
+// other tooling. The rest of each CRD is still updated to match the package,
+// reverting any drift. New CRDs are created with the conversion config the
+// package specifies, without Crossplane's webhook service or CA bundle.
+func WithPreservedCRDConversion() APIEstablisherOption {
+	return func(e *APIEstablisher) {
+		e.preserveCRDConversion = true
+	}
 }
 
 // NewAPIEstablisher creates a new APIEstablisher.
-func NewAPIEstablisher(client client.Client, namespace string, maxConcurrentPackageEstablishers int) *APIEstablisher {
-	return &APIEstablisher{
+func NewAPIEstablisher(client client.Client, namespace string, maxConcurrentPackageEstablishers int, opts ...APIEstablisherOption) *APIEstablisher {
+	e := &APIEstablisher{
 		client:                           client,
 		namespace:                        namespace,
 		MaxConcurrentPackageEstablishers: maxConcurrentPackageEstablishers,
 	}
+	for _, fn := range opts {
+		fn(e)
+	}
+	return e
 }
 
 // currentDesired caches resources while checking for control or ownership so
@@ -339,6 +359,9 @@ func (e *APIEstablisher) enrichControlledResource(res runtime.Object, webhookTLS
 			conf.Webhooks[i].ClientConfig.Service.Port = ptr.To[int32](servicePort)
 		}
 	case *extv1.CustomResourceDefinition:
+		if e.preserveCRDConversion {
+			return nil
+		}
 		if conf.Spec.Conversion != nil && conf.Spec.Conversion.Strategy == extv1.WebhookConverter {
 			if len(webhookTLSCert) == 0 {
 				return errors.New(errConversionWithNoWebhookCA)
@@ -466,6 +489,17 @@ func (e *APIEstablisher) update(ctx context.Context, current, desired resource.O
 		return err
 	}
 	desired.SetResourceVersion(current.GetResourceVersion())
+
+	// Keep the current conversion config of a CRD if it's managed by other
+	// tooling. Updating the rest of the CRD still reverts any drift.
+	if e.preserveCRDConversion {
+		dcrd, dok := desired.(*extv1.CustomResourceDefinition)
+		ccrd, cok := current.(*extv1.CustomResourceDefinition)
+		if dok && cok {
+			dcrd.Spec.Conversion = ccrd.Spec.Conversion
+		}
+	}
+
 	return e.client.Update(ctx, desired, opts...)
 }
 
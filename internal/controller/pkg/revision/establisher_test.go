@@ -294,6 +294,69 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 				err: errors.New(errConversionWithNoWebhookCA),
 			},
 		},
+		"SuccessfulPreserveCRDConversion": {
+			reason: "Establishment should leave the conversion config of an existing CRD untouched if CRD conversion is preserved.",
+			args: args{
+				est: func() *APIEstablisher {
+					e := newAPIEstablisher(&test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+							if crd, ok := obj.(*extv1.CustomResourceDefinition); ok {
+								crd.Spec.Conversion = &extv1.CustomResourceConversion{
+									Strategy: extv1.WebhookConverter,
+									Webhook: &extv1.WebhookConversion{
+										ClientConfig: &extv1.WebhookClientConfig{CABundle: caBundle},
+									},
+								}
+							}
+							return nil
+						},
+						MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							want := &extv1.CustomResourceConversion{
+								Strategy: extv1.WebhookConverter,
+								Webhook: &extv1.WebhookConversion{
+									ClientConfig: &extv1.WebhookClientConfig{CABundle: caBundle},
+								},
+							}
+							if diff := cmp.Diff(want, obj.(*extv1.CustomResourceDefinition).Spec.Conversion); diff != "" {
+								t.Errorf("Update(...): -want conversion, +got conversion:\n%s", diff)
+							}
+							return nil
+						},
+					})
+					WithPreservedCRDConversion()(e)
+					return e
+				}(),
+				objs: []runtime.Object{
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "ref-me",
+						},
+						Spec: extv1.CustomResourceDefinitionSpec{
+							Conversion: &extv1.CustomResourceConversion{
+								Strategy: extv1.WebhookConverter,
+							},
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								Name: "provider-name",
+								UID:  "some-unique-uid-2312",
+							},
+						},
+						Labels: map[string]string{
+							v1.LabelParentPackage: "provider-name",
+						},
+					},
+				},
+				control: true,
+			},
+			want: want{
+				refs: []xpv1.TypedReference{{Name: "ref-me"}},
+			},
+		},
 		"FailedGettingWebhookTLSSecretControl": {
 			reason: "Establishment of a controlling revision should fail if a webhook TLS secret is given but cannot be fetched",
 			args: args{
@@ -39,6 +39,8 @@ const (
 	errFetchLayer              = "failed to fetch annotated base layer from remote"
 	errGetUncompressed         = "failed to get uncompressed contents from layer"
 	errMultipleAnnotatedLayers = "package is invalid due to multiple annotated base layers"
+	errFmtMultipleVariants     = "package is invalid due to multiple annotated base layers for variant %q"
+	errFmtNoDefaultVariant     = "package has no default annotated base layer, and no layer for preferred variant %q"
 	errFmtNoPackageFileFound   = "couldn't find \"" + xpkg.StreamFile + "\" file after checking %d files in the archive (annotated layer: %v)"
 	errFmtMaxManifestLayers    = "package has %d layers, but only %d are allowed"
 	errValidateLayer           = "invalid package layer"
@@ -48,6 +50,10 @@ const (
 const (
 	layerAnnotation     = "io.crossplane.xpkg"
 	baseAnnotationValue = "base"
+	// variantAnnotation distinguishes variants of a package's base layer,
+	// for example a slim variant for constrained clusters. A base layer
+	// without it is the package's default variant.
+	variantAnnotation = "io.crossplane.xpkg.variant"
 	// maxLayers is the maximum number of layers an image can have.
 	maxLayers = 256
 )
@@ -55,6 +61,7 @@ const (
 // ImageBackend is a backend for parser.
 type ImageBackend struct {
 	registry string
+	variant  string
 	fetcher  xpkg.Fetcher
 }
 
@@ -68,6 +75,15 @@ func WithDefaultRegistry(registry string) ImageBackendOption {
 	}
 }
 
+// WithPreferredVariant sets the variant of a package's base layer that an
+// image backend will unpack, if the package offers it. The image backend
+// unpacks the package's default base layer if it doesn't.
+func WithPreferredVariant(variant string) ImageBackendOption {
+	return func(i *ImageBackend) {
+		i.variant = variant
+	}
+}
+
 // NewImageBackend creates a new image backend.
 func NewImageBackend(fetcher xpkg.Fetcher, opts ...ImageBackendOption) *ImageBackend {
 	i := &ImageBackend{
@@ -117,23 +133,56 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 		return nil, errors.Errorf(errFmtMaxManifestLayers, nLayers, maxLayers)
 	}
 
-	// Determine if the image is using annotated layers.
-	var tarc io.ReadCloser
-	foundAnnotated := false
-	for _, l := range manifest.Layers {
+	// Determine if the image is using annotated layers. A package may
+	// annotate more than one base layer, as long as each is a distinct
+	// variant. We unpack the preferred variant if there is one, and the
+	// default (i.e. the layer with no variant annotation) otherwise.
+	base, preferred := -1, -1
+	variants := make(map[string]bool)
+	for idx, l := range manifest.Layers {
 		if a, ok := l.Annotations[layerAnnotation]; !ok || a != baseAnnotationValue {
 			continue
 		}
 		// NOTE(hasheddan): the xpkg specification dictates that only one layer
-		// descriptor may be annotated as xpkg base. Since iterating through all
-		// descriptors is relatively inexpensive, we opt to do so in order to
-		// verify that we aren't just using the first layer annotated as xpkg
-		// base.
-		if foundAnnotated {
-			return nil, errors.New(errMultipleAnnotatedLayers)
+		// descriptor may be annotated as xpkg base (per variant). Since
+		// iterating through all descriptors is relatively inexpensive, we opt
+		// to do so in order to verify that we aren't just using the first
+		// layer annotated as xpkg base.
+		v := l.Annotations[variantAnnotation]
+		if variants[v] {
+			if v == "" {
+				return nil, errors.New(errMultipleAnnotatedLayers)
+			}
+			return nil, errors.Errorf(errFmtMultipleVariants, v)
+		}
+		variants[v] = true
+		switch {
+		case v == "":
+			base = idx
+		case v == i.variant:
+			preferred = idx
 		}
-		foundAnnotated = true
-		layer, err := img.LayerByDigest(l.Digest)
+	}
+
+	selected := base
+	if preferred >= 0 {
+		selected = preferred
+	}
+	if selected < 0 && len(variants) > 0 {
+		return nil, errors.Errorf(errFmtNoDefaultVariant, i.variant)
+	}
+
+	if n.selection != nil {
+		n.selection.preferred = i.variant
+		if preferred >= 0 {
+			n.selection.unpacked = i.variant
+		}
+	}
+
+	var tarc io.ReadCloser
+	foundAnnotated := selected >= 0
+	if foundAnnotated {
+		layer, err := img.LayerByDigest(manifest.Layers[selected].Digest)
 		if err != nil {
 			return nil, errors.Wrap(err, errFetchLayer)
 		}
@@ -184,6 +233,18 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 type nestedBackend struct {
 	pr                   v1.PackageRevision
 	pullSecretFromConfig string
+	selection            *variantSelection
+}
+
+// A variantSelection records which variant of a package's base layer an
+// ImageBackend unpacked.
+type variantSelection struct {
+	// preferred variant, if any.
+	preferred string
+
+	// unpacked variant. Empty if the ImageBackend unpacked the package's
+	// default base layer.
+	unpacked string
 }
 
 // Init is a nop because nestedBackend does not actually meant to act as a
@@ -213,3 +274,15 @@ func PullSecretFromConfig(secret string) parser.BackendOption {
 		i.pullSecretFromConfig = secret
 	}
 }
+
+// recordVariantSelection records which variant of the package's base layer
+// ImageBackend unpacks.
+func recordVariantSelection(s *variantSelection) parser.BackendOption {
+	return func(p parser.Backend) {
+		i, ok := p.(*nestedBackend)
+		if !ok {
+			return
+		}
+		i.selection = s
+	}
+}
@@ -53,6 +53,29 @@ func TestImageBackend(t *testing.T) {
 		},
 	})
 
+	slimLayer, _ := random.Layer(int64(500), types.DockerLayer)
+	slimImg, _ := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: slimLayer,
+		Annotations: map[string]string{
+			layerAnnotation:   baseAnnotationValue,
+			variantAnnotation: "slim",
+		},
+	})
+	slimImgDup, _ := mutate.Append(slimImg, mutate.Addendum{
+		Layer: slimLayer,
+		Annotations: map[string]string{
+			layerAnnotation:   baseAnnotationValue,
+			variantAnnotation: "slim",
+		},
+	})
+	variantsImg, _ := mutate.Append(randImg, mutate.Addendum{
+		Layer: slimLayer,
+		Annotations: map[string]string{
+			layerAnnotation:   baseAnnotationValue,
+			variantAnnotation: "slim",
+		},
+	})
+
 	// TODO(phisco): uncomment when https://github.com/google/go-containerregistry/pull/1758 is merged
 	// streamCont := "somestreamofyaml"
 	// tarBuf := new(bytes.Buffer)
@@ -73,8 +96,9 @@ func TestImageBackend(t *testing.T) {
 	// packImg, _ := mutate.AppendLayers(empty.Image, packLayer)
 
 	type args struct {
-		f    xpkg.Fetcher
-		opts []parser.BackendOption
+		f       xpkg.Fetcher
+		variant string
+		opts    []parser.BackendOption
 	}
 
 	cases := map[string]struct {
@@ -111,6 +135,57 @@ func TestImageBackend(t *testing.T) {
 			},
 			want: errors.New(errMultipleAnnotatedLayers),
 		},
+		"ErrMultipleVariantLayers": {
+			reason: "Should return error if image has multiple layers annotated as the same variant of base.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockFetch: fake.NewMockFetchFn(slimImgDup, nil),
+				},
+				variant: "slim",
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package: "test/test:latest",
+						},
+					},
+				})},
+			},
+			want: errors.Errorf(errFmtMultipleVariants, "slim"),
+		},
+		"ErrNoDefaultVariant": {
+			reason: "Should return error if image has only variants of base, and none is the preferred variant.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockFetch: fake.NewMockFetchFn(slimImg, nil),
+				},
+				variant: "tiny",
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package: "test/test:latest",
+						},
+					},
+				})},
+			},
+			want: errors.Errorf(errFmtNoDefaultVariant, "tiny"),
+		},
+		"ErrFetchedBadPreferredVariant": {
+			reason: "Should unpack the preferred variant of base, and return error if it does not have package.yaml.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockFetch: fake.NewMockFetchFn(variantsImg, nil),
+				},
+				variant: "slim",
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package: "test/test:latest",
+						},
+					},
+				})},
+			},
+			want: errors.Wrapf(io.EOF, errFmtNoPackageFileFound, 1, true),
+		},
 		"ErrFetchedBadPackage": {
 			reason: "Should return error if image with contents does not have package.yaml.",
 			args: args{
@@ -177,7 +252,7 @@ func TestImageBackend(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			b := NewImageBackend(tc.args.f)
+			b := NewImageBackend(tc.args.f, WithPreferredVariant(tc.args.variant))
 			rc, err := b.Init(context.TODO(), tc.args.opts...)
 			if err == nil && rc != nil {
 				_, err = io.ReadAll(rc)
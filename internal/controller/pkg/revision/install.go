@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// installDurationPrecision is the precision with which we record install
+// durations. Anything finer is noise to a human reading the status.
+const installDurationPrecision = time.Millisecond
+
+// An installTimer times the steps of a package install.
+type installTimer struct {
+	start time.Time
+	steps []v1.PackageInstallStep
+}
+
+// newInstallTimer returns an installTimer for an install that started at the
+// supplied time.
+func newInstallTimer(start time.Time) *installTimer {
+	return &installTimer{start: start}
+}
+
+// Step records that the named step, which started at the supplied time,
+// finished at the supplied time. Steps that run more than once accumulate
+// their durations.
+func (t *installTimer) Step(name v1.PackageInstallStepName, start, finish time.Time) {
+	d := finish.Sub(start)
+	for i := range t.steps {
+		if t.steps[i].Name == name {
+			t.steps[i].Duration.Duration += d
+			return
+		}
+	}
+	t.steps = append(t.steps, v1.PackageInstallStep{Name: name, Duration: metav1.Duration{Duration: d}})
+}
+
+// Done returns a record of the install, which completed at the supplied time.
+func (t *installTimer) Done(completed time.Time) *v1.PackageInstall {
+	i := &v1.PackageInstall{
+		CompletionTime: metav1.Time{Time: completed},
+		Duration:       metav1.Duration{Duration: completed.Sub(t.start).Round(installDurationPrecision)},
+		Steps:          make([]v1.PackageInstallStep, len(t.steps)),
+	}
+	for j, s := range t.steps {
+		i.Steps[j] = v1.PackageInstallStep{Name: s.Name, Duration: metav1.Duration{Duration: s.Duration.Round(installDurationPrecision)}}
+	}
+	return i
+}
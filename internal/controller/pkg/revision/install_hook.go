@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+const (
+	errGetInstallHookJob    = "cannot get install hook job"
+	errCreateInstallHookJob = "cannot create install hook job"
+	errFmtInstallHookFailed = "install hook job %q failed with message: %s. Delete the job to run the install hook again"
+	errFmtInstallHookActive = "install hook job %q hasn't completed yet"
+)
+
+const (
+	installHookSuffix = "-install"
+
+	// Defaults for optional install hook fields.
+	installHookDefaultTimeoutSeconds int64 = 600
+	installHookDefaultBackoffLimit   int32 = 3
+)
+
+// installHookJob builds the Job that runs the supplied install hook. The Job's
+// pod is derived from the supplied runtime Deployment's pod, so it runs with
+// the same image, service account, pull secrets, volumes, and environment as
+// the packaged controller. The Job is named after, and controlled by, the
+// supplied revision so that each revision runs the hook exactly once.
+func installHookJob(hook *pkgmetav1.InstallHook, revision string, d *appsv1.Deployment) *batchv1.Job {
+	ps := *d.Spec.Template.Spec.DeepCopy()
+	ps.RestartPolicy = corev1.RestartPolicyNever
+
+	// Only the package runtime container runs the hook. Sidecars would keep
+	// running after the hook finishes, so the Job would never complete.
+	ps.Containers = ps.Containers[:1]
+	ics := make([]corev1.Container, 0, len(ps.InitContainers))
+	for _, ic := range ps.InitContainers {
+		if ptr.Deref(ic.RestartPolicy, "") == corev1.ContainerRestartPolicyAlways {
+			continue
+		}
+		ics = append(ics, ic)
+	}
+	ps.InitContainers = ics
+	if len(ps.InitContainers) == 0 {
+		ps.InitContainers = nil
+	}
+
+	// The hook doesn't serve anything, so the controller's ports and probes
+	// don't apply.
+	c := &ps.Containers[0]
+	c.Command = hook.Command
+	c.Args = hook.Args
+	c.Ports = nil
+	c.LivenessProbe = nil
+	c.ReadinessProbe = nil
+	c.StartupProbe = nil
+
+	timeout := installHookDefaultTimeoutSeconds
+	if hook.TimeoutSeconds != nil {
+		timeout = *hook.TimeoutSeconds
+	}
+	backoff := installHookDefaultBackoffLimit
+	if hook.BackoffLimit != nil {
+		backoff = *hook.BackoffLimit
+	}
+
+	// We don't copy the Deployment's pod labels. The package's Service
+	// selects pods using them, and shouldn't send traffic to the hook.
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            revision + installHookSuffix,
+			Namespace:       d.GetNamespace(),
+			OwnerReferences: d.GetOwnerReferences(),
+		},
+		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds: ptr.To(timeout),
+			BackoffLimit:          ptr.To(backoff),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: d.Spec.Template.GetAnnotations(),
+				},
+				Spec: ps,
+			},
+		},
+	}
+}
+
+// An installHookRunningError indicates that an install hook's Job hasn't
+// finished running yet. It's not a failure - the revision should wait for the
+// Job to finish.
+type installHookRunningError struct {
+	job string
+}
+
+func (e installHookRunningError) Error() string {
+	return fmt.Sprintf(errFmtInstallHookActive, e.job)
+}
+
+// IsInstallHookRunning returns true if the supplied error indicates that an
+// install hook's Job hasn't finished running yet.
+func IsInstallHookRunning(err error) bool {
+	return errors.As(err, &installHookRunningError{})
+}
+
+// runInstallHook runs the supplied install hook, returning true once its Job
+// has completed. It creates the Job if it doesn't exist, and returns false
+// while the Job is running. It returns an error if the Job failed. A failed
+// Job isn't recreated, so a broken hook doesn't run in a loop. Deleting the
+// Job runs the hook again.
+func runInstallHook(ctx context.Context, c client.Client, hook *pkgmetav1.InstallHook, revision string, d *appsv1.Deployment) (bool, error) {
+	if hook == nil {
+		return true, nil
+	}
+
+	want := installHookJob(hook, revision, d)
+	j := &batchv1.Job{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: want.GetNamespace(), Name: want.GetName()}, j)
+	if kerrors.IsNotFound(err) {
+		return false, errors.Wrap(c.Create(ctx, want), errCreateInstallHookJob)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errGetInstallHookJob)
+	}
+
+	for _, cnd := range j.Status.Conditions {
+		if cnd.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cnd.Type { //nolint:exhaustive // We only care about finished jobs.
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			return false, errors.Errorf(errFmtInstallHookFailed, j.GetName(), cnd.Message)
+		}
+	}
+	return false, nil
+}
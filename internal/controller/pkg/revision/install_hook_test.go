@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+func TestInstallHookJob(t *testing.T) {
+	owner := []metav1.OwnerReference{{Name: "provider-nop-abc123", Controller: ptr.To(true)}}
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "provider-nop-abc123",
+			Namespace:       "crossplane-system",
+			OwnerReferences: owner,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"pkg.crossplane.io/revision": "provider-nop-abc123"},
+					Annotations: map[string]string{"cool": "annotation"},
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: "provider-nop",
+					InitContainers: []corev1.Container{
+						{Name: "init"},
+						{Name: "native-sidecar", RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways)},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:           runtimeContainerName,
+							Image:          "xpkg.crossplane.io/crossplane/provider-nop:v1.0.0",
+							Ports:          []corev1.ContainerPort{{Name: webhookPortName, ContainerPort: servicePort}},
+							ReadinessProbe: &corev1.Probe{},
+						},
+						{Name: "sidecar"},
+					},
+				},
+			},
+		},
+	}
+
+	type args struct {
+		hook *pkgmetav1.InstallHook
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *batchv1.Job
+	}{
+		"Defaults": {
+			reason: "We should run the controller's pod without its sidecars with the hook's command, and default the timeout and backoff limit.",
+			args: args{
+				hook: &pkgmetav1.InstallHook{Command: []string{"register-webhook"}},
+			},
+			want: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "provider-nop-abc123-install",
+					Namespace:       "crossplane-system",
+					OwnerReferences: owner,
+				},
+				Spec: batchv1.JobSpec{
+					ActiveDeadlineSeconds: ptr.To(installHookDefaultTimeoutSeconds),
+					BackoffLimit:          ptr.To(installHookDefaultBackoffLimit),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{"cool": "annotation"},
+						},
+						Spec: corev1.PodSpec{
+							ServiceAccountName: "provider-nop",
+							RestartPolicy:      corev1.RestartPolicyNever,
+							InitContainers:     []corev1.Container{{Name: "init"}},
+							Containers: []corev1.Container{{
+								Name:    runtimeContainerName,
+								Image:   "xpkg.crossplane.io/crossplane/provider-nop:v1.0.0",
+								Command: []string{"register-webhook"},
+							}},
+						},
+					},
+				},
+			},
+		},
+		"Overrides": {
+			reason: "We should use the hook's timeout and backoff limit if it specifies them.",
+			args: args{
+				hook: &pkgmetav1.InstallHook{
+					Args:           []string{"--register-webhook"},
+					TimeoutSeconds: ptr.To[int64](30),
+					BackoffLimit:   ptr.To[int32](0),
+				},
+			},
+			want: &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "provider-nop-abc123-install",
+					Namespace:       "crossplane-system",
+					OwnerReferences: owner,
+				},
+				Spec: batchv1.JobSpec{
+					ActiveDeadlineSeconds: ptr.To[int64](30),
+					BackoffLimit:          ptr.To[int32](0),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{"cool": "annotation"},
+						},
+						Spec: corev1.PodSpec{
+							ServiceAccountName: "provider-nop",
+							RestartPolicy:      corev1.RestartPolicyNever,
+							InitContainers:     []corev1.Container{{Name: "init"}},
+							Containers: []corev1.Container{{
+								Name:  runtimeContainerName,
+								Image: "xpkg.crossplane.io/crossplane/provider-nop:v1.0.0",
+								Args:  []string{"--register-webhook"},
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := installHookJob(tc.args.hook, "provider-nop-abc123", d)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ninstallHookJob(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRunInstallHook(t *testing.T) {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "provider-nop-abc123", Namespace: "crossplane-system"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: runtimeContainerName}}},
+			},
+		},
+	}
+	hook := &pkgmetav1.InstallHook{Command: []string{"register-webhook"}}
+
+	withConditions := func(c ...batchv1.JobCondition) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			j := obj.(*batchv1.Job)
+			j.SetName("provider-nop-abc123-install")
+			j.Status.Conditions = c
+			return nil
+		})
+	}
+
+	type args struct {
+		client client.Client
+		hook   *pkgmetav1.InstallHook
+	}
+
+	type want struct {
+		done bool
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoHook": {
+			reason: "We should do nothing if the package doesn't declare an install hook.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				done: true,
+			},
+		},
+		"CreateJob": {
+			reason: "We should create the hook's job if it doesn't exist, and report that it hasn't completed without returning an error.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(nil),
+				},
+				hook: hook,
+			},
+			want: want{
+				done: false,
+			},
+		},
+		"CreateJobError": {
+			reason: "We should return any error encountered creating the hook's job.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				hook: hook,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateInstallHookJob),
+			},
+		},
+		"GetJobError": {
+			reason: "We should return any error encountered getting the hook's job.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				hook:   hook,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetInstallHookJob),
+			},
+		},
+		"JobActive": {
+			reason: "We should report that the hook hasn't completed without returning an error while its job is running.",
+			args: args{
+				client: &test.MockClient{MockGet: withConditions(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionFalse})},
+				hook:   hook,
+			},
+			want: want{
+				done: false,
+			},
+		},
+		"JobComplete": {
+			reason: "We should report that the hook completed once its job completes.",
+			args: args{
+				client: &test.MockClient{MockGet: withConditions(batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})},
+				hook:   hook,
+			},
+			want: want{
+				done: true,
+			},
+		},
+		"JobFailed": {
+			reason: "We should return an error including the job's message if the hook's job failed.",
+			args: args{
+				client: &test.MockClient{MockGet: withConditions(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "boom"})},
+				hook:   hook,
+			},
+			want: want{
+				err: errors.Errorf(errFmtInstallHookFailed, "provider-nop-abc123-install", "boom"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			done, err := runInstallHook(context.Background(), tc.args.client, tc.args.hook, "provider-nop-abc123", d)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrunInstallHook(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.done, done); diff != "" {
+				t.Errorf("\n%s\nrunInstallHook(...): -want done, +got done:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestInstallTimer(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	type step struct {
+		name          v1.PackageInstallStepName
+		start, finish time.Time
+	}
+
+	cases := map[string]struct {
+		reason    string
+		steps     []step
+		completed time.Time
+		want      *v1.PackageInstall
+	}{
+		"NoSteps": {
+			reason:    "We should record the duration of an install with no timed steps.",
+			completed: at(2 * time.Second),
+			want: &v1.PackageInstall{
+				CompletionTime: metav1.Time{Time: at(2 * time.Second)},
+				Duration:       metav1.Duration{Duration: 2 * time.Second},
+				Steps:          []v1.PackageInstallStep{},
+			},
+		},
+		"Steps": {
+			reason: "We should record steps in the order they started, accumulating the durations of steps that run more than once.",
+			steps: []step{
+				{name: v1.PackageInstallStepFetch, start: at(0), finish: at(time.Second)},
+				{name: v1.PackageInstallStepRuntime, start: at(time.Second), finish: at(2 * time.Second)},
+				{name: v1.PackageInstallStepEstablishObjects, start: at(2 * time.Second), finish: at(5 * time.Second)},
+				{name: v1.PackageInstallStepRuntime, start: at(5 * time.Second), finish: at(6 * time.Second)},
+			},
+			completed: at(6 * time.Second),
+			want: &v1.PackageInstall{
+				CompletionTime: metav1.Time{Time: at(6 * time.Second)},
+				Duration:       metav1.Duration{Duration: 6 * time.Second},
+				Steps: []v1.PackageInstallStep{
+					{Name: v1.PackageInstallStepFetch, Duration: metav1.Duration{Duration: time.Second}},
+					{Name: v1.PackageInstallStepRuntime, Duration: metav1.Duration{Duration: 2 * time.Second}},
+					{Name: v1.PackageInstallStepEstablishObjects, Duration: metav1.Duration{Duration: 3 * time.Second}},
+				},
+			},
+		},
+		"Rounded": {
+			reason: "We should round durations to the nearest millisecond.",
+			steps: []step{
+				{name: v1.PackageInstallStepUnpack, start: at(0), finish: at(1500 * time.Microsecond)},
+			},
+			completed: at(1500 * time.Microsecond),
+			want: &v1.PackageInstall{
+				CompletionTime: metav1.Time{Time: at(1500 * time.Microsecond)},
+				Duration:       metav1.Duration{Duration: 2 * time.Millisecond},
+				Steps: []v1.PackageInstallStep{
+					{Name: v1.PackageInstallStepUnpack, Duration: metav1.Duration{Duration: 2 * time.Millisecond}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			it := newInstallTimer(start)
+			for _, s := range tc.steps {
+				it.Step(s.name, s.start, s.finish)
+			}
+			got := it.Done(tc.completed)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nDone(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
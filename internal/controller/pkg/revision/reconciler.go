@@ -26,6 +26,7 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -74,6 +75,8 @@ const (
 
 	errPullPolicyNever = "failed to get pre-cached package with pull policy Never"
 
+	errUnpackInterrupted = "package unpack was interrupted"
+
 	errAddFinalizer    = "cannot add package revision finalizer"
 	errRemoveFinalizer = "cannot remove package revision finalizer"
 
@@ -87,6 +90,8 @@ const (
 	errNotOneMeta        = "cannot install package with multiple meta types"
 	errIncompatible      = "incompatible Crossplane version"
 
+	errFmtNoPreferredVariant = "package doesn't offer preferred variant %q - unpacked its default base layer"
+
 	errManifestBuilderOptions = "cannot prepare runtime manifest builder options"
 	errPreHook                = "pre establish runtime hook failed for package"
 	errPostHook               = "post establish runtime hook failed for package"
@@ -97,8 +102,13 @@ const (
 
 	errUpdateMeta = "cannot update package revision object metadata"
 
-	errRemoveLock  = "cannot remove package revision from Lock"
-	errResolveDeps = "cannot resolve package dependencies"
+	errRemoveLock     = "cannot remove package revision from Lock"
+	errResolveDeps    = "cannot resolve package dependencies"
+	errCheckConflicts = "cannot check for conflicting packages"
+	errCheckAPIs      = "cannot check for required APIs"
+
+	errFmtConflicts   = "conflicting packages: %s. Uninstall the conflicting package, or install a version of this package that doesn't conflict with it"
+	errFmtMissingAPIs = "missing required APIs: %s. Install the APIs this package requires, for example by upgrading Kubernetes or installing their CRDs"
 
 	errConfResourceObject = "cannot convert to resource.Object"
 
@@ -121,9 +131,12 @@ const (
 	reasonParse        event.Reason = "ParsePackage"
 	reasonLint         event.Reason = "LintPackage"
 	reasonDependencies event.Reason = "ResolveDependencies"
+	reasonConflicts    event.Reason = "CheckConflicts"
+	reasonRequiredAPIs event.Reason = "CheckRequiredAPIs"
 	reasonSync         event.Reason = "SyncPackage"
 	reasonDeactivate   event.Reason = "DeactivateRevision"
 	reasonPaused       event.Reason = "ReconciliationPaused"
+	reasonVariant      event.Reason = "SelectVariant"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -179,6 +192,14 @@ func WithDependencyManager(m DependencyManager) ReconcilerOption {
 	}
 }
 
+// WithRequiredAPIChecker specifies how the Reconciler should check whether
+// the Kubernetes APIs a package requires are served.
+func WithRequiredAPIChecker(c RequiredAPIChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.apis = c
+	}
+}
+
 // WithRuntimeHooks specifies how the Reconciler should perform preparations
 // (pre- and post-establishment) and cleanup (deactivate) for package runtime.
 // The hooks are only used when the package has a runtime and the runtime is
@@ -261,12 +282,29 @@ func uniqueResourceIdentifier(ref xpv1.TypedReference) string {
 	return strings.Join([]string{ref.GroupVersionKind().String(), ref.Name}, "/")
 }
 
+// dependencyManagerOptions returns the PackageDependencyManager options
+// implied by the supplied controller options.
+func dependencyManagerOptions(o controller.Options) []PackageDependencyManagerOption {
+	if !o.Features.Enabled(features.EnableAlphaDependencyMirroring) {
+		return nil
+	}
+	return []PackageDependencyManagerOption{WithDependencyMirror(xpkg.NewMirror(o.DependencyMirror, o.DefaultRegistry))}
+}
+
+func establisherOptions(o controller.Options) []APIEstablisherOption {
+	if !o.PreserveCRDConversion {
+		return nil
+	}
+	return []APIEstablisherOption{WithPreservedCRDConversion()}
+}
+
 // Reconciler reconciles packages.
 type Reconciler struct {
 	client         client.Client
 	cache          xpkg.PackageCache
 	revision       resource.Finalizer
 	lock           DependencyManager
+	apis           RequiredAPIChecker
 	runtimeHook    RuntimeHooks
 	objects        Establisher
 	parser         parser.Parser
@@ -311,6 +349,7 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		For(&v1.ProviderRevision{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&batchv1.Job{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ServiceAccount{}).
@@ -321,11 +360,12 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 
 	ro := []ReconcilerOption{
 		WithCache(o.Cache),
-		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.ProviderGroupVersionKind)),
-		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
+		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.ProviderGroupVersionKind, dependencyManagerOptions(o)...)),
+		WithRequiredAPIChecker(NewDiscoveryRequiredAPIChecker(clientset.Discovery())),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers, establisherOptions(o)...)),
 		WithNewPackageRevisionFn(nr),
-		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParser(xpkg.NewAggregatingParser(metaScheme, objScheme)),
+		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry), WithPreferredVariant(o.PackageVariant))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
 		WithLinter(xpkg.NewProviderLinter()),
 		WithLogger(log),
@@ -375,11 +415,12 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 	log := o.Logger.WithValues("controller", name)
 	r := NewReconciler(mgr,
 		WithCache(o.Cache),
-		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.ConfigurationGroupVersionKind)),
+		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.ConfigurationGroupVersionKind, dependencyManagerOptions(o)...)),
+		WithRequiredAPIChecker(NewDiscoveryRequiredAPIChecker(cs.Discovery())),
 		WithNewPackageRevisionFn(nr),
-		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
-		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers, establisherOptions(o)...)),
+		WithParser(xpkg.NewAggregatingParser(metaScheme, objScheme)),
+		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry), WithPreferredVariant(o.PackageVariant))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
 		WithLinter(xpkg.NewConfigurationLinter()),
 		WithLogger(log),
@@ -435,11 +476,12 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 
 	ro := []ReconcilerOption{
 		WithCache(o.Cache),
-		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.FunctionGroupVersionKind)),
-		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
+		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.FunctionGroupVersionKind, dependencyManagerOptions(o)...)),
+		WithRequiredAPIChecker(NewDiscoveryRequiredAPIChecker(clientset.Discovery())),
+		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers, establisherOptions(o)...)),
 		WithNewPackageRevisionFn(nr),
-		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParser(xpkg.NewAggregatingParser(metaScheme, objScheme)),
+		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry), WithPreferredVariant(o.PackageVariant))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
 		WithLinter(xpkg.NewFunctionLinter()),
 		WithLogger(log),
@@ -469,6 +511,8 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		client:    mgr.GetClient(),
 		cache:     xpkg.NewNopCache(),
 		revision:  resource.NewAPIFinalizer(mgr.GetClient(), finalizer),
+		lock:      NewNopDependencyManager(),
+		apis:      NewNopRequiredAPIChecker(),
 		objects:   NewNopEstablisher(),
 		parser:    parser.New(nil, nil),
 		linter:    parser.NewPackageLinter(nil, nil, nil),
@@ -657,6 +701,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 				r.record.Event(pr, event.Normal(reasonSync, "Successfully configured package revision"))
 			}
 			pr.SetConditions(v1.Healthy())
+			controller.RecordSuccessfulReconcile(pr, time.Now())
 			return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
 		}
 	}
@@ -680,6 +725,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	var rc io.ReadCloser
 	cacheWrite := make(chan error)
+	fetched := false
+
+	// We only record an install when we fetch the package. Reconciling
+	// cached package contents isn't an install.
+	it := newInstallTimer(time.Now())
 
 	if r.cache.Has(id) {
 		var err error
@@ -711,7 +761,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	// If we didn't get a ReadCloser from cache, we need to get it from image.
 	if rc == nil {
-		bo := []parser.BackendOption{PackageRevision(pr)}
+		vs := &variantSelection{}
+		bo := []parser.BackendOption{PackageRevision(pr), recordVariantSelection(vs)}
 		if imageConfig != "" {
 			bo = append(bo, PullSecretFromConfig(pullSecretFromConfig))
 			// We only record this event here, package is not in cache, and
@@ -722,10 +773,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 
 		// Initialize parser backend to obtain package contents.
+		start := time.Now()
 		imgrc, err := r.backend.Init(ctx, bo...)
+		it.Step(v1.PackageInstallStepFetch, start, time.Now())
 		if err != nil {
 			err = errors.Wrap(err, errInitParserBackend)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			c := v1.Unhealthy()
+			if xpkg.IsProxyConnectError(err) {
+				c = v1.UnhealthyProxyUnreachable()
+			}
+			pr.SetConditions(c.WithMessage(err.Error()))
 			_ = r.client.Status().Update(ctx, pr)
 
 			r.record.Event(pr, event.Warning(reasonParse, err))
@@ -735,7 +792,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return reconcile.Result{}, err
 		}
 
+		// We only record the variant when we fetch the image. The status
+		// retains it while the package contents are cached.
+		pr.SetVariant(vs.unpacked)
+		if vs.preferred != "" && vs.unpacked != vs.preferred {
+			log.Debug("Package doesn't offer the preferred variant, unpacked its default", "image", pr.GetSource(), "variant", vs.preferred)
+			r.record.Event(pr, event.Warning(reasonVariant, errors.Errorf(errFmtNoPreferredVariant, vs.preferred)))
+		}
+
 		// Package is not in cache, so we write it to the cache while parsing.
+		fetched = true
 		pipeR, pipeW := io.Pipe()
 		rc = xpkg.TeeReadCloser(imgrc, pipeW)
 		go func() {
@@ -750,6 +816,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// Parse package contents.
+	start := time.Now()
 	pkg, err := r.parser.Parse(ctx, struct {
 		io.Reader
 		io.Closer
@@ -766,6 +833,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			log.Debug(errDeleteCache, "error", err)
 		}
 	}
+	it.Step(v1.PackageInstallStepUnpack, start, time.Now())
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		// We were interrupted, most likely because Crossplane is shutting
+		// down or lost leader election during an upgrade. This says nothing
+		// about the package, so we don't mark the revision unhealthy. The
+		// parser may have stopped reading part way through the package, in
+		// which case the cache holds partial contents. We remove them, so
+		// whichever Crossplane reconciles the revision next fetches and
+		// unpacks the package again. Nothing from the package has been
+		// established yet, so there's no other partial state to clean up.
+		if fetched {
+			if err := r.cache.Delete(id); err != nil {
+				log.Debug(errDeleteCache, "error", err)
+			}
+		}
+		log.Debug(errUnpackInterrupted, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errUnpackInterrupted)
+	}
 	if err != nil {
 		err = errors.Wrap(err, errParsePackage)
 		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
@@ -825,7 +910,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if pr.GetIgnoreCrossplaneConstraints() == nil || !*pr.GetIgnoreCrossplaneConstraints() {
 		if err := xpkg.PackageCrossplaneCompatible(r.versioner)(pkgMeta); err != nil {
 			err = errors.Wrap(err, errIncompatible)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			pr.SetConditions(v1.IncompatibleCrossplaneVersion().WithMessage(err.Error()))
 
 			r.record.Event(pr, event.Warning(reasonLint, err))
 
@@ -837,10 +922,63 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
+	// Refuse to activate a package that conflicts with an installed package.
+	// A package can't conflict with an installed package while it's inactive,
+	// because it isn't installed.
+	if pr.GetDesiredState() == v1.PackageRevisionActive {
+		conflicts, err := r.lock.Conflicts(ctx, pkgMeta, pr)
+		if err != nil {
+			err = errors.Wrap(err, errCheckConflicts)
+			pr.SetConditions(v1.UnknownHealth().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
+			r.record.Event(pr, event.Warning(reasonConflicts, err))
+
+			return reconcile.Result{}, err
+		}
+		if len(conflicts) > 0 {
+			err := errors.Errorf(errFmtConflicts, strings.Join(conflicts, "; "))
+			pr.SetConditions(v1.ConflictingPackage().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
+			r.record.Event(pr, event.Warning(reasonConflicts, err))
+
+			// We don't watch the lock, so return an error to check again
+			// with backoff in case the conflicting package is uninstalled.
+			return reconcile.Result{}, err
+		}
+
+		// Refuse to activate a package that requires Kubernetes APIs the
+		// API server doesn't serve, rather than let it crash loop.
+		missing, err := r.apis.MissingAPIs(ctx, pkgMeta)
+		if err != nil {
+			err = errors.Wrap(err, errCheckAPIs)
+			pr.SetConditions(v1.UnknownHealth().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
+			r.record.Event(pr, event.Warning(reasonRequiredAPIs, err))
+
+			return reconcile.Result{}, err
+		}
+		if len(missing) > 0 {
+			err := errors.Errorf(errFmtMissingAPIs, strings.Join(missing, ", "))
+			pr.SetConditions(v1.MissingRequiredAPIs().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
+			r.record.Event(pr, event.Warning(reasonRequiredAPIs, err))
+
+			// We don't watch discovery, so return an error to check again
+			// with backoff in case the required APIs are installed.
+			return reconcile.Result{}, err
+		}
+	}
+
 	// Check status of package dependencies unless package specifies to skip
 	// resolution.
 	if pr.GetSkipDependencyResolution() != nil && !*pr.GetSkipDependencyResolution() {
+		start := time.Now()
 		found, installed, invalid, err := r.lock.Resolve(ctx, pkgMeta, pr)
+		it.Step(v1.PackageInstallStepResolveDependencies, start, time.Now())
 		pr.SetDependencyStatus(int64(found), int64(installed), int64(invalid))
 		if err != nil {
 			if kerrors.IsConflict(err) {
@@ -858,7 +996,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	if hasRuntime && r.runtimeHook != nil {
-		if err := r.runtimeHook.Pre(ctx, pkgMeta, pwr, runtimeManifestBuilder); err != nil {
+		start := time.Now()
+		err := r.runtimeHook.Pre(ctx, pkgMeta, pwr, runtimeManifestBuilder)
+		it.Step(v1.PackageInstallStepRuntime, start, time.Now())
+		if err != nil {
 			if kerrors.IsConflict(err) {
 				return reconcile.Result{Requeue: true}, nil
 			}
@@ -874,7 +1015,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// Establish control or ownership of objects.
+	start = time.Now()
 	refs, err := r.objects.Establish(ctx, pkg.GetObjects(), pr, pr.GetDesiredState() == v1.PackageRevisionActive)
+	it.Step(v1.PackageInstallStepEstablishObjects, start, time.Now())
 	if err != nil {
 		if kerrors.IsConflict(err) {
 			return reconcile.Result{Requeue: true}, nil
@@ -907,11 +1050,21 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	pr.SetObjects(refs)
 
 	if hasRuntime && r.runtimeHook != nil {
-		if err := r.runtimeHook.Post(ctx, pkgMeta, pwr, runtimeManifestBuilder); err != nil {
+		start := time.Now()
+		err := r.runtimeHook.Post(ctx, pkgMeta, pwr, runtimeManifestBuilder)
+		it.Step(v1.PackageInstallStepRuntime, start, time.Now())
+		if err != nil {
 			if kerrors.IsConflict(err) {
 				return reconcile.Result{Requeue: true}, nil
 			}
 
+			// Waiting for the install hook's Job to finish isn't an
+			// error, so we requeue to check on it again.
+			if IsInstallHookRunning(err) {
+				pr.SetConditions(v1.InstallHookRunning().WithMessage(err.Error()))
+				return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+			}
+
 			err = errors.Wrap(err, errPostHook)
 			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 			_ = r.client.Status().Update(ctx, pr)
@@ -928,6 +1081,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(pr, event.Normal(reasonSync, "Successfully configured package revision"))
 	}
 	pr.SetConditions(v1.Healthy())
+	if fetched {
+		pr.SetLastInstall(it.Done(time.Now()))
+	}
+	controller.RecordSuccessfulReconcile(pr, time.Now())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
 }
 
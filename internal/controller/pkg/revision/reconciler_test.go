@@ -21,6 +21,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -46,6 +47,7 @@ import (
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/pkg/pkgtest"
 	"github.com/crossplane/crossplane/internal/features"
 	verfake "github.com/crossplane/crossplane/internal/version/fake"
 	"github.com/crossplane/crossplane/internal/xpkg"
@@ -145,6 +147,7 @@ func (fn MockParseFn) Parse(ctx context.Context, r io.ReadCloser) (*parser.Packa
 type MockDependencyManager struct {
 	MockResolve    func() (int, int, int, error)
 	MockRemoveSelf func() error
+	MockConflicts  func() ([]string, error)
 }
 
 func NewMockResolveFn(total, installed, invalid int, err error) func() (int, int, int, error) {
@@ -163,6 +166,14 @@ func (m *MockDependencyManager) RemoveSelf(_ context.Context, _ v1.PackageRevisi
 	return m.MockRemoveSelf()
 }
 
+func (m *MockDependencyManager) Conflicts(_ context.Context, _ pkgmetav1.Pkg, _ v1.PackageRevision) ([]string, error) {
+	// Most tests don't care about conflicts, so assume there are none.
+	if m.MockConflicts == nil {
+		return nil, nil
+	}
+	return m.MockConflicts()
+}
+
 var providerBytes = []byte(`apiVersion: meta.pkg.crossplane.io/v1
 kind: Provider
 metadata:
@@ -175,6 +186,33 @@ spec:
   crossplane:
     version: ">v0.13.0"`)
 
+// equateInstallSteps compares package installs by the steps they recorded.
+// The time steps take to run in tests is nondeterministic.
+func equateInstallSteps() cmp.Option {
+	return cmp.Transformer("InstallSteps", func(i *v1.PackageInstall) []v1.PackageInstallStepName {
+		if i == nil {
+			return nil
+		}
+		names := make([]v1.PackageInstallStepName, len(i.Steps))
+		for j, s := range i.Steps {
+			names[j] = s.Name
+		}
+		return names
+	})
+}
+
+// fetchedInstall is the install recorded when a revision without a runtime
+// fetches its package and skips dependency resolution.
+func fetchedInstall() *v1.PackageInstall {
+	return &v1.PackageInstall{
+		Steps: []v1.PackageInstallStep{
+			{Name: v1.PackageInstallStepFetch},
+			{Name: v1.PackageInstallStepUnpack},
+			{Name: v1.PackageInstallStepEstablishObjects},
+		},
+	}
+}
+
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
@@ -186,6 +224,7 @@ func TestReconcile(t *testing.T) {
 	objScheme, _ := xpkg.BuildObjectScheme()
 
 	type args struct {
+		ctx context.Context
 		mgr manager.Manager
 		rec []ReconcilerOption
 	}
@@ -645,6 +684,49 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errParsePackage),
 			},
 		},
+		"ErrParseInterrupted": {
+			reason: "We should remove partially cached contents and not mark the revision unhealthy if parsing is interrupted.",
+			args: args{
+				ctx: func() context.Context {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+					return ctx
+				}(),
+				mgr: &fake.Manager{},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							// We know the status wasn't updated because
+							// MockStatusUpdate is nil, and would panic if
+							// it was called.
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(MockParseFn(func(_ context.Context, _ io.ReadCloser) (*parser.Package, error) { return nil, errBoom })),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas:    xpkgfake.NewMockCacheHasFn(false),
+						MockStore:  xpkgfake.NewMockCacheStoreFn(nil),
+						MockDelete: xpkgfake.NewMockCacheDeleteFn(nil),
+					}),
+					WithConfigStore(&xpkgfake.MockConfigStore{
+						MockPullSecretFor: xpkgfake.NewMockConfigStorePullSecretForFn("", "", nil),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUnpackInterrupted),
+			},
+		},
 		"ErrParseFromImageFailedCache": {
 			reason: "We should return an error if we fail to parse the package from the image and fail to cache.",
 			args: args{
@@ -804,7 +886,7 @@ func TestReconcile(t *testing.T) {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
-								want.SetConditions(v1.Unhealthy().WithMessage("incompatible Crossplane version: package is not compatible with Crossplane version (v0.11.0): boom"))
+								want.SetConditions(v1.IncompatibleCrossplaneVersion().WithMessage(`incompatible Crossplane version: cannot check whether Crossplane version "v0.11.0" satisfies the package's required Crossplane version ">v0.13.0": boom`))
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
 
 								if diff := cmp.Diff(want, o); diff != "" {
@@ -1152,6 +1234,76 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errPostHook),
 			},
 		},
+		"InstallHookRunning": {
+			reason: "We should requeue without returning an error while the install hook is running.",
+			args: args{
+				mgr: &fake.Manager{},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								if pr, ok := o.(*v1.ProviderRevision); ok {
+									pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+									pr.SetDesiredState(v1.PackageRevisionActive)
+									pr.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetConditions(v1.InstallHookRunning().WithMessage(installHookRunningError{job: "provider-nop-abc123-install"}.Error()))
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetRuntimeConfigRef(&v1.RuntimeConfigReference{Name: "default"})
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithRuntimeHooks(&MockHook{
+						MockPre:  NewMockPreFn(nil),
+						MockPost: NewMockPostFn(installHookRunningError{job: "provider-nop-abc123-install"}),
+					}),
+					WithEstablisher(NewMockEstablisher()),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+					WithConfigStore(&xpkgfake.MockConfigStore{
+						MockPullSecretFor: xpkgfake.NewMockConfigStorePullSecretForFn("", "", nil),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"SuccessfulActiveRevision": {
 			reason: "An active revision should establish control of all of its resources.",
 			args: args{
@@ -1173,7 +1325,9 @@ func TestReconcile(t *testing.T) {
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
 								want.SetConditions(v1.Healthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetLastInstall(fetchedInstall())
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime(), equateInstallSteps()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1239,7 +1393,9 @@ func TestReconcile(t *testing.T) {
 								want.SetConditions(v1.Healthy())
 								want.SetIgnoreCrossplaneConstraints(&trueVal)
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetLastInstall(fetchedInstall())
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime(), equateInstallSteps()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1442,7 +1598,9 @@ func TestReconcile(t *testing.T) {
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
 								want.SetConditions(v1.Healthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetLastInstall(fetchedInstall())
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime(), equateInstallSteps()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1530,7 +1688,8 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionInactive)
 								want.SetConditions(v1.Healthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1774,7 +1933,9 @@ func TestReconcile(t *testing.T) {
 								want.SetConditions(v1.VerificationSucceeded("foo"))
 								want.SetConditions(v1.Healthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								want.SetLastInstall(fetchedInstall())
+								want.SetLastSuccessfulReconcileTime(&metav1.Time{Time: time.Now()})
+								if diff := cmp.Diff(want, o, pkgtest.EquateApproxTime(), equateInstallSteps()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1823,7 +1984,11 @@ func TestReconcile(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			r := NewReconciler(tc.args.mgr, append(tc.args.rec, WithLogger(testLog))...)
-			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			ctx := tc.args.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			got, err := r.Reconcile(ctx, reconcile.Request{})
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+
+	"k8s.io/client-go/discovery"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+const (
+	errDiscoverAPIs = "cannot discover the APIs served by the API server"
+)
+
+// A RequiredAPIChecker checks whether the Kubernetes APIs a package requires
+// are served.
+type RequiredAPIChecker interface {
+	// MissingAPIs returns the APIs required by the supplied package that
+	// aren't served.
+	MissingAPIs(ctx context.Context, pkg pkgmetav1.Pkg) ([]string, error)
+}
+
+// NewNopRequiredAPIChecker returns a new NopRequiredAPIChecker.
+func NewNopRequiredAPIChecker() *NopRequiredAPIChecker {
+	return &NopRequiredAPIChecker{}
+}
+
+// A NopRequiredAPIChecker assumes all required APIs are served.
+type NopRequiredAPIChecker struct{}
+
+// MissingAPIs always returns no missing APIs.
+func (*NopRequiredAPIChecker) MissingAPIs(_ context.Context, _ pkgmetav1.Pkg) ([]string, error) {
+	return nil, nil
+}
+
+// A DiscoveryRequiredAPIChecker uses the API server's discovery endpoints to
+// check whether the Kubernetes APIs a package requires are served.
+type DiscoveryRequiredAPIChecker struct {
+	client discovery.ServerGroupsInterface
+}
+
+// NewDiscoveryRequiredAPIChecker returns a RequiredAPIChecker that uses the
+// supplied discovery client.
+func NewDiscoveryRequiredAPIChecker(c discovery.ServerGroupsInterface) *DiscoveryRequiredAPIChecker {
+	return &DiscoveryRequiredAPIChecker{client: c}
+}
+
+// MissingAPIs returns the APIs required by the supplied package that aren't
+// served. A required API with a version is only satisfied if that version of
+// its group is served. A required API without a version is satisfied if any
+// version of its group is served.
+func (c *DiscoveryRequiredAPIChecker) MissingAPIs(_ context.Context, pkg pkgmetav1.Pkg) ([]string, error) {
+	required := pkg.GetRequiredAPIs()
+	if len(required) == 0 {
+		return nil, nil
+	}
+
+	gl, err := c.client.ServerGroups()
+	if err != nil {
+		return nil, errors.Wrap(err, errDiscoverAPIs)
+	}
+
+	groups := make(map[string]bool)
+	versions := make(map[string]bool)
+	for _, g := range gl.Groups {
+		groups[g.Name] = true
+		for _, v := range g.Versions {
+			versions[v.GroupVersion] = true
+		}
+	}
+
+	missing := make([]string, 0)
+	for _, r := range required {
+		if r.Version == nil {
+			if !groups[r.Group] {
+				missing = append(missing, requiredAPIString(r))
+			}
+			continue
+		}
+		if !versions[requiredAPIString(r)] {
+			missing = append(missing, requiredAPIString(r))
+		}
+	}
+	return missing, nil
+}
+
+// requiredAPIString returns the API version of the supplied required API, for
+// example gateway.networking.k8s.io/v1, or just its group if it doesn't
+// specify a version. The core API group is represented by its version alone,
+// or "core" if it doesn't specify a version.
+func requiredAPIString(r pkgmetav1.RequiredAPI) string {
+	switch {
+	case r.Version == nil && r.Group == "":
+		return "core"
+	case r.Version == nil:
+		return r.Group
+	case r.Group == "":
+		return *r.Version
+	default:
+		return r.Group + "/" + *r.Version
+	}
+}
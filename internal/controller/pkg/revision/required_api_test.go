@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+type MockServerGroupsFn func() (*metav1.APIGroupList, error)
+
+func (fn MockServerGroupsFn) ServerGroups() (*metav1.APIGroupList, error) {
+	return fn()
+}
+
+func TestMissingAPIs(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	served := MockServerGroupsFn(func() (*metav1.APIGroupList, error) {
+		return &metav1.APIGroupList{Groups: []metav1.APIGroup{
+			{Name: "", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "v1", Version: "v1"}}},
+			{Name: "gateway.networking.k8s.io", Versions: []metav1.GroupVersionForDiscovery{
+				{GroupVersion: "gateway.networking.k8s.io/v1", Version: "v1"},
+				{GroupVersion: "gateway.networking.k8s.io/v1beta1", Version: "v1beta1"},
+			}},
+		}}, nil
+	})
+
+	type args struct {
+		client MockServerGroupsFn
+		apis   []pkgmetav1.RequiredAPI
+	}
+	type want struct {
+		missing []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoRequiredAPIs": {
+			reason: "We shouldn't call discovery if the package doesn't require any APIs.",
+			args: args{
+				client: MockServerGroupsFn(func() (*metav1.APIGroupList, error) { return nil, errBoom }),
+			},
+		},
+		"DiscoveryError": {
+			reason: "We should return any error encountered discovering served APIs.",
+			args: args{
+				client: MockServerGroupsFn(func() (*metav1.APIGroupList, error) { return nil, errBoom }),
+				apis:   []pkgmetav1.RequiredAPI{{Group: "gateway.networking.k8s.io"}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDiscoverAPIs),
+			},
+		},
+		"AllServed": {
+			reason: "We should return no missing APIs if all required APIs are served.",
+			args: args{
+				client: served,
+				apis: []pkgmetav1.RequiredAPI{
+					{Version: ptr.To("v1")},
+					{Group: "gateway.networking.k8s.io"},
+					{Group: "gateway.networking.k8s.io", Version: ptr.To("v1beta1")},
+				},
+			},
+			want: want{
+				missing: []string{},
+			},
+		},
+		"SomeMissing": {
+			reason: "We should return required groups that aren't served, and required versions of served groups that aren't served.",
+			args: args{
+				client: served,
+				apis: []pkgmetav1.RequiredAPI{
+					{Group: "gateway.networking.k8s.io", Version: ptr.To("v1alpha2")},
+					{Group: "networking.istio.io"},
+					{Group: "gateway.networking.k8s.io", Version: ptr.To("v1")},
+				},
+			},
+			want: want{
+				missing: []string{"gateway.networking.k8s.io/v1alpha2", "networking.istio.io"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pkg := &pkgmetav1.Provider{Spec: pkgmetav1.ProviderSpec{MetaSpec: pkgmetav1.MetaSpec{RequiredAPIs: tc.args.apis}}}
+			c := NewDiscoveryRequiredAPIChecker(tc.args.client)
+			missing, err := c.MissingAPIs(context.Background(), pkg)
+
+			if diff := cmp.Diff(tc.want.missing, missing); diff != "" {
+				t.Errorf("\n%s\nMissingAPIs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMissingAPIs(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
@@ -56,6 +57,9 @@ const (
 	tlsClientCertDirEnvVar   = "TLS_CLIENT_CERTS_DIR"
 	tlsClientCertsVolumeName = "tls-client-certs"
 	tlsClientCertsDir        = "/tls/client"
+
+	writablePathVolumeNameFmt = "writable-path-%d"
+	tmpDir                    = "/tmp"
 )
 
 //nolint:gochecknoglobals // We treat these as constants, but take their addresses.
@@ -218,6 +222,9 @@ func (b *RuntimeManifestBuilder) Deployment(serviceAccount string, overrides ...
 			RunAsNonRoot:             &runAsNonRoot,
 		}),
 		DeploymentWithOptionalServiceAccount(serviceAccount),
+		// Package runtimes need their service account token to talk to the
+		// API server, even if their service account disables automounting.
+		DeploymentWithOptionalAutomountServiceAccountToken(true),
 
 		// Overrides that we are opinionated about.
 		DeploymentWithNamespace(b.namespace),
@@ -250,6 +257,24 @@ func (b *RuntimeManifestBuilder) Deployment(serviceAccount string, overrides ...
 		allOverrides = append(allOverrides, DeploymentRuntimeWithTLSServerSecret(*b.revision.GetTLSServerSecretName()))
 	}
 
+	if b.runtimeConfig != nil && b.runtimeConfig.Spec.AutomountServiceAccountToken != nil {
+		allOverrides = append(allOverrides, DeploymentWithAutomountServiceAccountToken(*b.runtimeConfig.Spec.AutomountServiceAccountToken))
+	}
+
+	if b.runtimeConfig != nil && ptr.Deref(b.runtimeConfig.Spec.ReadOnlyRootFilesystem, false) {
+		allOverrides = append(allOverrides, DeploymentRuntimeWithReadOnlyRootFilesystem())
+	}
+
+	// Most runtimes expect to be able to write temporary files, so /tmp is
+	// always writable. This does nothing unless the runtime container has a
+	// read-only root filesystem. Paths the deployment template already mounts
+	// a volume at are skipped, so the template can replace these volumes.
+	writable := []string{tmpDir}
+	if b.runtimeConfig != nil {
+		writable = append(writable, b.runtimeConfig.Spec.WritablePaths...)
+	}
+	allOverrides = append(allOverrides, DeploymentRuntimeWithWritablePaths(writable))
+
 	// We append the overrides passed to the function last so that they can
 	// override the above ones.
 	allOverrides = append(allOverrides, overrides...)
@@ -129,7 +129,7 @@ func (h *FunctionHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 		return errors.Wrap(err, errParseFunctionImage)
 	}
 
-	d := build.Deployment(sa.Name, functionDeploymentOverrides(image)...)
+	d := build.Deployment(sa.Name, functionDeploymentOverrides(functionMeta, image)...)
 	// Create/Apply the SA only if the deployment references it.
 	// This is to avoid creating a SA that is NOT used by the deployment when
 	// the SA is managed externally by the user and configured by setting
@@ -139,6 +139,8 @@ func (h *FunctionHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 		if err := applySA(ctx, h.client, sa); err != nil {
 			return errors.Wrap(err, errApplyFunctionSA)
 		}
+	} else if err := requireExternalSA(ctx, h.client, d); err != nil {
+		return err
 	}
 	if err := h.client.Apply(ctx, d); err != nil {
 		return errors.Wrap(err, errApplyFunctionDeployment)
@@ -178,7 +180,7 @@ func (h *FunctionHooks) Deactivate(ctx context.Context, _ v1.PackageRevisionWith
 	return nil
 }
 
-func functionDeploymentOverrides(image string) []DeploymentOverride {
+func functionDeploymentOverrides(fm *pkgmetav1.Function, image string) []DeploymentOverride {
 	do := []DeploymentOverride{
 		DeploymentRuntimeWithAdditionalPorts([]corev1.ContainerPort{
 			{
@@ -190,6 +192,10 @@ func functionDeploymentOverrides(image string) []DeploymentOverride {
 
 	do = append(do, DeploymentRuntimeWithOptionalImage(image))
 
+	// The paths the function declares it writes to must remain writable if
+	// the runtime config makes its root filesystem read-only.
+	do = append(do, DeploymentRuntimeWithWritablePaths(fm.Spec.WritablePaths))
+
 	return do
 }
 
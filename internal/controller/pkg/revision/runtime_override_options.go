@@ -17,9 +17,13 @@ limitations under the License.
 package revision
 
 import (
+	"fmt"
+	"path"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	"github.com/crossplane/crossplane/internal/initializer"
@@ -151,6 +155,24 @@ func DeploymentWithOptionalServiceAccount(sa string) DeploymentOverride {
 	}
 }
 
+// DeploymentWithOptionalAutomountServiceAccountToken sets whether the pods of
+// a Deployment automount their service account token, if it is unset.
+func DeploymentWithOptionalAutomountServiceAccountToken(automount bool) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		if d.Spec.Template.Spec.AutomountServiceAccountToken == nil {
+			d.Spec.Template.Spec.AutomountServiceAccountToken = &automount
+		}
+	}
+}
+
+// DeploymentWithAutomountServiceAccountToken overrides whether the pods of a
+// Deployment automount their service account token.
+func DeploymentWithAutomountServiceAccountToken(automount bool) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.AutomountServiceAccountToken = &automount
+	}
+}
+
 // DeploymentWithImagePullSecrets overrides the image pull secrets of a
 // Deployment.
 func DeploymentWithImagePullSecrets(secrets []corev1.LocalObjectReference) DeploymentOverride {
@@ -250,6 +272,59 @@ func DeploymentRuntimeWithOptionalSecurityContext(securityContext *corev1.Securi
 	}
 }
 
+// DeploymentRuntimeWithReadOnlyRootFilesystem runs the runtime container of a
+// Deployment with a read-only root filesystem.
+func DeploymentRuntimeWithReadOnlyRootFilesystem() DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		if d.Spec.Template.Spec.Containers[0].SecurityContext == nil {
+			d.Spec.Template.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{}
+		}
+		d.Spec.Template.Spec.Containers[0].SecurityContext.ReadOnlyRootFilesystem = ptr.To(true)
+	}
+}
+
+// DeploymentRuntimeWithWritablePaths mounts an emptyDir volume at each of the
+// supplied paths of the runtime container of a Deployment, if the runtime
+// container has a read-only root filesystem. Paths that already have a volume
+// mounted are skipped.
+func DeploymentRuntimeWithWritablePaths(paths []string) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		c := &d.Spec.Template.Spec.Containers[0]
+		if c.SecurityContext == nil || !ptr.Deref(c.SecurityContext.ReadOnlyRootFilesystem, false) {
+			return
+		}
+
+		mounted := make(map[string]bool, len(c.VolumeMounts))
+		for _, vm := range c.VolumeMounts {
+			mounted[path.Clean(vm.MountPath)] = true
+		}
+		volumes := make(map[string]bool, len(d.Spec.Template.Spec.Volumes))
+		for _, v := range d.Spec.Template.Spec.Volumes {
+			volumes[v.Name] = true
+		}
+
+		i := 0
+		for _, p := range paths {
+			p = path.Clean(p)
+			if mounted[p] {
+				continue
+			}
+			name := fmt.Sprintf(writablePathVolumeNameFmt, i)
+			for volumes[name] {
+				i++
+				name = fmt.Sprintf(writablePathVolumeNameFmt, i)
+			}
+			d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, corev1.Volume{
+				Name:         name,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			})
+			c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{Name: name, MountPath: p})
+			mounted[p] = true
+			volumes[name] = true
+		}
+	}
+}
+
 // DeploymentWithRuntimeContainer ensures that the runtime container exists and
 // is the first container.
 func DeploymentWithRuntimeContainer() DeploymentOverride {
@@ -6,6 +6,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 )
 
 func TestDeploymentWithRuntimeContainer(t *testing.T) {
@@ -211,3 +212,151 @@ func TestDeploymentWithRuntimeContainer(t *testing.T) {
 		})
 	}
 }
+
+func TestDeploymentRuntimeWithReadOnlyRootFilesystem(t *testing.T) {
+	type args struct {
+		deployment *appsv1.Deployment
+	}
+	type want struct {
+		deployment *appsv1.Deployment
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoSecurityContext": {
+			reason: "Should add a security context with a read-only root filesystem if there is none",
+			args: args{
+				deployment: runtimeDeployment(corev1.Container{Name: runtimeContainerName}),
+			},
+			want: want{
+				deployment: runtimeDeployment(corev1.Container{
+					Name:            runtimeContainerName,
+					SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(true)},
+				}),
+			},
+		},
+		"ExistingSecurityContext": {
+			reason: "Should preserve the rest of an existing security context",
+			args: args{
+				deployment: runtimeDeployment(corev1.Container{
+					Name:            runtimeContainerName,
+					SecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr.To(true), ReadOnlyRootFilesystem: ptr.To(false)},
+				}),
+			},
+			want: want{
+				deployment: runtimeDeployment(corev1.Container{
+					Name:            runtimeContainerName,
+					SecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr.To(true), ReadOnlyRootFilesystem: ptr.To(true)},
+				}),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			DeploymentRuntimeWithReadOnlyRootFilesystem()(tc.args.deployment)
+			if diff := cmp.Diff(tc.want.deployment, tc.args.deployment); diff != "" {
+				t.Errorf("\n%s\nDeploymentRuntimeWithReadOnlyRootFilesystem(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDeploymentRuntimeWithWritablePaths(t *testing.T) {
+	readOnly := &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(true)}
+	emptyDir := corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+
+	type args struct {
+		paths      []string
+		deployment *appsv1.Deployment
+	}
+	type want struct {
+		deployment *appsv1.Deployment
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"WritableRootFilesystem": {
+			reason: "Should do nothing if the root filesystem is writable",
+			args: args{
+				paths:      []string{"/tmp"},
+				deployment: runtimeDeployment(corev1.Container{Name: runtimeContainerName}),
+			},
+			want: want{
+				deployment: runtimeDeployment(corev1.Container{Name: runtimeContainerName}),
+			},
+		},
+		"ReadOnlyRootFilesystem": {
+			reason: "Should mount an emptyDir volume at each path if the root filesystem is read-only",
+			args: args{
+				paths:      []string{"/tmp", "/var/cache/"},
+				deployment: runtimeDeployment(corev1.Container{Name: runtimeContainerName, SecurityContext: readOnly}),
+			},
+			want: want{
+				deployment: runtimeDeployment(corev1.Container{
+					Name:            runtimeContainerName,
+					SecurityContext: readOnly,
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "writable-path-0", MountPath: "/tmp"},
+						{Name: "writable-path-1", MountPath: "/var/cache"},
+					},
+				},
+					corev1.Volume{Name: "writable-path-0", VolumeSource: emptyDir},
+					corev1.Volume{Name: "writable-path-1", VolumeSource: emptyDir},
+				),
+			},
+		},
+		"AlreadyMounted": {
+			reason: "Should skip paths that already have a volume mounted, and avoid existing volume names",
+			args: args{
+				paths: []string{"/tmp", "/data", "/data"},
+				deployment: runtimeDeployment(corev1.Container{
+					Name:            runtimeContainerName,
+					SecurityContext: readOnly,
+					VolumeMounts:    []corev1.VolumeMount{{Name: "writable-path-0", MountPath: "/tmp"}},
+				},
+					corev1.Volume{Name: "writable-path-0", VolumeSource: emptyDir},
+				),
+			},
+			want: want{
+				deployment: runtimeDeployment(corev1.Container{
+					Name:            runtimeContainerName,
+					SecurityContext: readOnly,
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "writable-path-0", MountPath: "/tmp"},
+						{Name: "writable-path-1", MountPath: "/data"},
+					},
+				},
+					corev1.Volume{Name: "writable-path-0", VolumeSource: emptyDir},
+					corev1.Volume{Name: "writable-path-1", VolumeSource: emptyDir},
+				),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			DeploymentRuntimeWithWritablePaths(tc.args.paths)(tc.args.deployment)
+			if diff := cmp.Diff(tc.want.deployment, tc.args.deployment); diff != "" {
+				t.Errorf("\n%s\nDeploymentRuntimeWithWritablePaths(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func runtimeDeployment(c corev1.Container, vols ...corev1.Volume) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{c},
+					Volumes:    vols,
+				},
+			},
+		},
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,6 +50,9 @@ const (
 	errFmtUnavailableProviderDeployment       = "provider package deployment is unavailable with message: %s"
 	errNoAvailableConditionProviderDeployment = "provider package deployment has no condition of type \"Available\" yet"
 	errParseProviderImage                     = "cannot parse provider package image"
+
+	errGetExternalSA        = "cannot get externally managed service account"
+	errFmtMissingExternalSA = "service account %q doesn't exist in namespace %q - create it, or remove deploymentTemplate.spec.template.spec.serviceAccountName from the runtime config"
 )
 
 // ProviderHooks performs runtime operations for provider packages.
@@ -82,6 +86,7 @@ func (h *ProviderHooks) Pre(ctx context.Context, pkg runtime.Object, pr v1.Packa
 	}
 
 	provRev.Status.PermissionRequests = providerMeta.Spec.Controller.PermissionRequests
+	provRev.Status.Capabilities = providerMeta.Spec.Capabilities
 
 	// TODO(hasheddan): update any status fields relevant to package revisions.
 
@@ -158,6 +163,8 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 		if err := applySA(ctx, h.client, sa); err != nil {
 			return errors.Wrap(err, errApplyProviderSA)
 		}
+	} else if err := requireExternalSA(ctx, h.client, d); err != nil {
+		return err
 	}
 	if err := h.client.Apply(ctx, d); err != nil {
 		return errors.Wrap(err, errApplyProviderDeployment)
@@ -166,6 +173,15 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 	for _, c := range d.Status.Conditions {
 		if c.Type == appsv1.DeploymentAvailable {
 			if c.Status == corev1.ConditionTrue {
+				// The install hook runs once the controller is
+				// available, e.g. so it can register its webhook.
+				done, err := runInstallHook(ctx, h.client, providerMeta.Spec.Controller.InstallHook, pr.GetName(), d)
+				if err != nil {
+					return err
+				}
+				if !done {
+					return installHookRunningError{job: pr.GetName() + installHookSuffix}
+				}
 				return nil
 			}
 			return errors.Errorf(errFmtUnavailableProviderDeployment, c.Message)
@@ -239,6 +255,10 @@ func providerDeploymentOverrides(pm *pkgmetav1.Provider, pr v1.PackageRevisionWi
 
 	do = append(do, DeploymentRuntimeWithOptionalImage(image))
 
+	// The paths the provider declares it writes to must remain writable if
+	// the runtime config makes its root filesystem read-only.
+	do = append(do, DeploymentRuntimeWithWritablePaths(pm.Spec.Controller.WritablePaths))
+
 	if pr.GetTLSClientSecretName() != nil {
 		do = append(do, DeploymentRuntimeWithAdditionalEnvironments([]corev1.EnvVar{
 			// for backward compatibility with existing providers, we set the
@@ -313,3 +333,16 @@ func applySA(ctx context.Context, cl resource.ClientApplicator, sa *corev1.Servi
 	}
 	return cl.Apply(ctx, sa)
 }
+
+// requireExternalSA returns an error if the externally managed ServiceAccount
+// the supplied Deployment references doesn't exist. We don't deploy the
+// package runtime until it does, rather than let its ReplicaSet fail to create
+// pods.
+func requireExternalSA(ctx context.Context, cl client.Reader, d *appsv1.Deployment) error {
+	saName := d.Spec.Template.Spec.ServiceAccountName
+	err := cl.Get(ctx, types.NamespacedName{Name: saName, Namespace: d.GetNamespace()}, &corev1.ServiceAccount{})
+	if kerrors.IsNotFound(err) {
+		return errors.Errorf(errFmtMissingExternalSA, saName, d.GetNamespace())
+	}
+	return errors.Wrap(err, errGetExternalSA)
+}
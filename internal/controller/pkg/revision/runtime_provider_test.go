@@ -129,6 +129,24 @@ func TestProviderPreHook(t *testing.T) {
 				},
 			},
 		},
+		"CapabilitiesPropagated": {
+			reason: "Should propagate capabilities from provider to revision",
+			args: args{
+				pkg: &pkgmetav1.Provider{
+					Spec: pkgmetav1.ProviderSpec{
+						Capabilities: []string{pkgmetav1.ProviderCapabilityManagementPolicies},
+					},
+				},
+				rev: &v1.ProviderRevision{},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Status: v1.PackageRevisionStatus{
+						Capabilities: []string{pkgmetav1.ProviderCapabilityManagementPolicies},
+					},
+				},
+			},
+		},
 		"Success": {
 			reason: "Successful run of pre hook.",
 			args: args{
@@ -595,6 +613,63 @@ func TestProviderPostHook(t *testing.T) {
 				},
 			},
 		},
+		"ErrExternallyManagedSAMissing": {
+			reason: "Should return an error without applying the deployment, when the externally managed SA doesn't exist",
+			args: args{
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      providerImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{
+							ObjectMeta: metav1.ObjectMeta{
+								Name: "xp-managed-sa",
+							},
+						}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: "crossplane-system",
+							},
+							Spec: appsv1.DeploymentSpec{
+								Template: corev1.PodTemplateSpec{
+									Spec: corev1.PodSpec{
+										ServiceAccountName: "external-sa",
+									},
+								},
+							},
+						}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+						return kerrors.NewNotFound(corev1.Resource("serviceaccount"), key.Name)
+					},
+					MockPatch: func(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						t.Error("unexpected call to patch when the externally managed SA doesn't exist")
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.ProviderRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							Package:      providerImage,
+							DesiredState: v1.PackageRevisionActive,
+						},
+					},
+				},
+				err: errors.Errorf(errFmtMissingExternalSA, "external-sa", "crossplane-system"),
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -222,6 +222,149 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 				}),
 			},
 		},
+		"ProviderDeploymentWithoutServiceAccountToken": {
+			reason: "The runtime config should be able to disable automounting the service account token",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							AutomountServiceAccountToken: ptr.To(false),
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), DeploymentWithAutomountServiceAccountToken(false)),
+			},
+		},
+		"ProviderDeploymentServiceAccountTokenOverridesTemplate": {
+			reason: "The runtime config's automountServiceAccountToken should take precedence over its deployment template",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											AutomountServiceAccountToken: ptr.To(false),
+										},
+									},
+								},
+							},
+							AutomountServiceAccountToken: ptr.To(true),
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				})),
+			},
+		},
+		"ProviderDeploymentTemplateWithoutServiceAccountToken": {
+			reason: "The deployment template should be able to disable automounting the service account token if the runtime config doesn't set it",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											AutomountServiceAccountToken: ptr.To(false),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), DeploymentWithAutomountServiceAccountToken(false)),
+			},
+		},
+		"ProviderDeploymentTemplateReplacesWritablePath": {
+			reason: "A volume the deployment template mounts at a writable path should replace the emptyDir volume that would otherwise be mounted there, and writable path volumes shouldn't reuse the template's volume names",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							ReadOnlyRootFilesystem: ptr.To(true),
+							WritablePaths:          []string{"/cache"},
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											Containers: []corev1.Container{
+												{
+													Name: runtimeContainerName,
+													VolumeMounts: []corev1.VolumeMount{
+														{Name: "tmp", MountPath: "/tmp"},
+														{Name: "writable-path-0", MountPath: "/data"},
+													},
+												},
+											},
+											Volumes: []corev1.Volume{
+												{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}}},
+												{Name: "writable-path-0", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), func(d *appsv1.Deployment) {
+					c := &d.Spec.Template.Spec.Containers[0]
+					c.SecurityContext.ReadOnlyRootFilesystem = ptr.To(true)
+					c.VolumeMounts = append([]corev1.VolumeMount{
+						{Name: "tmp", MountPath: "/tmp"},
+						{Name: "writable-path-0", MountPath: "/data"},
+					}, c.VolumeMounts...)
+					c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{Name: "writable-path-1", MountPath: "/cache"})
+					d.Spec.Template.Spec.Volumes = append([]corev1.Volume{
+						{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}}},
+						{Name: "writable-path-0", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					}, d.Spec.Template.Spec.Volumes...)
+					d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, corev1.Volume{
+						Name:         "writable-path-1",
+						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					})
+				}),
+			},
+		},
 		"ProviderDeploymentNoScrapeAnnotation": {
 			reason: "It should be possible to disable default scrape annotations",
 			args: args{
@@ -259,6 +402,38 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 				}),
 			},
 		},
+		"ProviderDeploymentWithPriorityClassName": {
+			reason: "The priority class name provided by the runtime config should be applied to the deployment",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:  providerRevision,
+					namespace: namespace,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											PriorityClassName: "system-cluster-critical",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: providerRevisionName,
+				overrides:          providerDeploymentOverrides(&pkgmetav1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerMetaName}}, providerRevision, providerImage),
+			},
+			want: want{
+				want: deploymentProvider(providerName, providerRevisionName, providerImage, DeploymentWithSelectors(map[string]string{
+					"pkg.crossplane.io/provider": providerMetaName,
+					"pkg.crossplane.io/revision": providerRevisionName,
+				}), func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.PriorityClassName = "system-cluster-critical"
+				}),
+			},
+		},
 		"ProviderDeploymentWithAdvancedRuntimeConfig": {
 			reason: "Baseline provided by the runtime config should be applied to the deployment for advanced use cases",
 			args: args{
@@ -366,7 +541,7 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 					namespace: namespace,
 				},
 				serviceAccountName: functionRevisionName,
-				overrides:          functionDeploymentOverrides(functionImage),
+				overrides:          functionDeploymentOverrides(&pkgmetav1.Function{}, functionImage),
 			},
 			want: want{
 				want: deploymentFunction(functionName, functionRevisionName, functionImage),
@@ -385,7 +560,7 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 					},
 				},
 				serviceAccountName: functionRevisionName,
-				overrides:          functionDeploymentOverrides(functionImage),
+				overrides:          functionDeploymentOverrides(&pkgmetav1.Function{}, functionImage),
 			},
 			want: want{
 				want: deploymentFunction(functionName, functionRevisionName, functionImage, func(deployment *appsv1.Deployment) {
@@ -440,7 +615,8 @@ func deploymentProvider(provider string, revision string, image string, override
 					},
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: revision,
+					ServiceAccountName:           revision,
+					AutomountServiceAccountToken: ptr.To(true),
 					SecurityContext: &corev1.PodSecurityContext{
 						RunAsNonRoot: &runAsNonRoot,
 						RunAsUser:    &runAsUser,
@@ -597,7 +773,8 @@ func deploymentFunction(function string, revision string, image string, override
 					},
 				},
 				Spec: corev1.PodSpec{
-					ServiceAccountName: revision,
+					ServiceAccountName:           revision,
+					AutomountServiceAccountToken: ptr.To(true),
 					SecurityContext: &corev1.PodSecurityContext{
 						RunAsNonRoot: &runAsNonRoot,
 						RunAsUser:    &runAsUser,
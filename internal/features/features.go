@@ -42,6 +42,30 @@ const (
 
 	// EnableAlphaSignatureVerification enables alpha support for verifying the package signatures via ImageConfig API.
 	EnableAlphaSignatureVerification feature.Flag = "EnableAlphaSignatureVerification"
+
+	// EnableAlphaCompositionStepAnnotations enables alpha support for
+	// annotating composed resources with the Composition pipeline step that
+	// last modified them.
+	EnableAlphaCompositionStepAnnotations feature.Flag = "EnableAlphaCompositionStepAnnotations"
+
+	// EnableAlphaDependencyMirroring enables alpha support for copying
+	// dependency packages to a mirror registry, and installing them from
+	// there.
+	EnableAlphaDependencyMirroring feature.Flag = "EnableAlphaDependencyMirroring"
+
+	// EnableAlphaTracing enables alpha support for emitting OpenTelemetry
+	// traces of composite resource reconciles.
+	EnableAlphaTracing feature.Flag = "EnableAlphaTracing"
+
+	// EnableAlphaComposedDryRunApply enables alpha support for dry-run
+	// applying all of a composite resource's composed resources before
+	// applying any of them.
+	EnableAlphaComposedDryRunApply feature.Flag = "EnableAlphaComposedDryRunApply"
+
+	// EnableAlphaComposedClaimNamespace enables alpha support for creating
+	// namespaced composed resources that don't specify a namespace in the
+	// namespace of the claim.
+	EnableAlphaComposedClaimNamespace feature.Flag = "EnableAlphaComposedClaimNamespace"
 )
 
 // Beta Feature Flags.
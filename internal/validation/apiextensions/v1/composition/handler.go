@@ -26,6 +26,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -34,6 +35,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/pkg/validation/apiextensions/v1/composition"
 )
@@ -87,6 +89,7 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 
 	// Validate the composition itself, we'll disable it on the Validator below.
 	warns, validationErrs := comp.Validate()
+	validationErrs = append(validationErrs, validateMatchConditions(comp)...)
 	if len(validationErrs) != 0 {
 		return warns, kerrors.NewInvalid(comp.GroupVersionKind().GroupKind(), comp.GetName(), validationErrs)
 	}
@@ -154,6 +157,38 @@ func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) (admissi
 	return nil, nil
 }
 
+// validateMatchConditions checks that every patch and pipeline step's match
+// condition compiles.
+func validateMatchConditions(comp *v1.Composition) (errs field.ErrorList) {
+	validate := func(p v1.Patch, path *field.Path) {
+		if p.MatchCondition == nil {
+			return
+		}
+		if _, err := composite.CompileMatchCondition(*p.MatchCondition); err != nil {
+			errs = append(errs, field.Invalid(path.Child("matchCondition"), *p.MatchCondition, err.Error()))
+		}
+	}
+	for i, s := range comp.Spec.PatchSets {
+		for j, p := range s.Patches {
+			validate(p, field.NewPath("spec", "patchSets").Index(i).Child("patches").Index(j))
+		}
+	}
+	for i, r := range comp.Spec.Resources {
+		for j, p := range r.Patches {
+			validate(p, field.NewPath("spec", "resources").Index(i).Child("patches").Index(j))
+		}
+	}
+	for i, s := range comp.Spec.Pipeline {
+		if s.MatchCondition == nil {
+			continue
+		}
+		if _, err := composite.CompileMatchCondition(*s.MatchCondition); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "pipeline").Index(i).Child("matchCondition"), *s.MatchCondition, err.Error()))
+		}
+	}
+	return errs
+}
+
 // containsOtherThanNotFound returns true if the given slice of errors contains
 // any error other than a not found error.
 func containsOtherThanNotFound(errs []error) bool {
@@ -64,7 +64,7 @@ func getAllCRDsForXRD(in *v1.CompositeResourceDefinition) (out []*apiextv1.Custo
 	}
 	out = append(out, crd)
 	// if claim enabled, validate claim CRD
-	if in.Spec.ClaimNames == nil {
+	if !in.OffersClaim() {
 		return out, nil
 	}
 	crdClaim, err := xcrd.ForCompositeResourceClaim(in)
@@ -18,6 +18,9 @@ limitations under the License.
 package version
 
 import (
+	"strings"
+	"unicode"
+
 	"github.com/Masterminds/semver"
 )
 
@@ -59,9 +62,38 @@ func (v *Versioner) InConstraints(c string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	constraint, err := semver.NewConstraint(c)
+	constraint, err := NewConstraint(c)
 	if err != nil {
 		return false, err
 	}
 	return constraint.Check(ver), nil
 }
+
+// NewConstraint parses the supplied semantic version constraints. In addition
+// to the comma separated constraints supported by semver.NewConstraint, it
+// supports space separated constraints. For example the range
+// ">=v1.14.0 <v2.0.0" is equivalent to ">=v1.14.0, <v2.0.0".
+func NewConstraint(c string) (*semver.Constraints, error) {
+	ors := strings.Split(c, "||")
+	for i, or := range ors {
+		fields := strings.FieldsFunc(or, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+		ands := make([]string, 0, len(fields))
+		for j := 0; j < len(fields); j++ {
+			f := fields[j]
+			switch {
+			// A hyphen range, e.g. "v1.0.0 - v2.0.0", is a single constraint.
+			case f == "-" && len(ands) > 0 && j+1 < len(fields):
+				j++
+				ands[len(ands)-1] += " - " + fields[j]
+				continue
+			// An operator separated from its version, e.g. ">= v1.0.0".
+			case strings.Trim(f, "<>=!~^") == "" && j+1 < len(fields):
+				j++
+				f += fields[j]
+			}
+			ands = append(ands, f)
+		}
+		ors[i] = strings.Join(ands, ",")
+	}
+	return semver.NewConstraint(strings.Join(ors, "||"))
+}
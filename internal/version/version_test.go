@@ -20,6 +20,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/Masterminds/semver"
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -59,6 +60,36 @@ func TestInRange(t *testing.T) {
 				is: false,
 			},
 		},
+		"ValidInSpaceSeparatedRange": {
+			reason: "Should return true when a valid semantic version is in a valid space separated range.",
+			args: args{
+				version: "v1.15.0",
+				r:       ">=v1.14.0 <v2.0.0",
+			},
+			want: want{
+				is: true,
+			},
+		},
+		"ValidAboveSpaceSeparatedRange": {
+			reason: "Should return false when a valid semantic version is above a valid space separated range.",
+			args: args{
+				version: "v2.0.0",
+				r:       ">=v1.14.0 <v2.0.0",
+			},
+			want: want{
+				is: false,
+			},
+		},
+		"ValidInAlternativeRange": {
+			reason: "Should return true when a valid semantic version is in one of several alternative ranges.",
+			args: args{
+				version: "v2.1.0",
+				r:       ">= v1.14.0 < v2.0.0 || >=v2.1.0",
+			},
+			want: want{
+				is: true,
+			},
+		},
 		"InvalidVersion": {
 			reason: "Should return error when version is invalid.",
 			args: args{
@@ -94,3 +125,84 @@ func TestInRange(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConstraint(t *testing.T) {
+	type want struct {
+		check map[string]bool
+		err   error
+	}
+	cases := map[string]struct {
+		reason string
+		c      string
+		want   want
+	}{
+		"Single": {
+			reason: "A single constraint should be parsed as is.",
+			c:      ">=v1.14.0",
+			want: want{
+				check: map[string]bool{"v1.13.0": false, "v1.14.0": true, "v2.0.0": true},
+			},
+		},
+		"SpaceSeparated": {
+			reason: "Space separated constraints should all be satisfied.",
+			c:      ">=v1.14.0 <v2.0.0",
+			want: want{
+				check: map[string]bool{"v1.13.0": false, "v1.14.0": true, "v2.0.0": false},
+			},
+		},
+		"CommaSeparated": {
+			reason: "Comma separated constraints should all be satisfied.",
+			c:      ">=v1.14.0,<v2.0.0",
+			want: want{
+				check: map[string]bool{"v1.13.0": false, "v1.14.0": true, "v2.0.0": false},
+			},
+		},
+		"SeparateOperators": {
+			reason: "Operators separated from their versions by a space should be parsed.",
+			c:      ">= v1.14.0 < v2.0.0",
+			want: want{
+				check: map[string]bool{"v1.13.0": false, "v1.14.0": true, "v2.0.0": false},
+			},
+		},
+		"HyphenRange": {
+			reason: "Hyphen ranges should be parsed as a single constraint.",
+			c:      "v1.14.0 - v2.0.0 !=v1.15.0",
+			want: want{
+				check: map[string]bool{"v1.13.0": false, "v1.14.0": true, "v1.15.0": false, "v2.0.0": true, "v2.0.1": false},
+			},
+		},
+		"Alternatives": {
+			reason: "Alternatives should be parsed separately.",
+			c:      ">=v1.14.0 <v2.0.0 || >=v2.1.0",
+			want: want{
+				check: map[string]bool{"v1.14.0": true, "v2.0.0": false, "v2.1.0": true},
+			},
+		},
+		"Invalid": {
+			reason: "Invalid constraints should return an error.",
+			c:      ">=v1.14.0 <a2",
+			want: want{
+				err: errors.New("improper constraint: <a2"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewConstraint(tc.c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nNewConstraint(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			check := make(map[string]bool, len(tc.want.check))
+			for v := range tc.want.check {
+				check[v] = c.Check(semver.MustParse(v))
+			}
+			if diff := cmp.Diff(tc.want.check, check); diff != "" {
+				t.Errorf("\n%s\nNewConstraint(...).Check(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ package xcrd
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,18 +43,37 @@ const (
 	CategoryComposite = "composite"
 )
 
+// reservedCategories may not be used as short names, because kubectl would
+// resolve them ambiguously. These are the categories of generated CRDs, those
+// used by Crossplane providers, and kubectl's built-in "all" category.
+var reservedCategories = map[string]bool{
+	CategoryClaim:     true,
+	CategoryComposite: true,
+	"crossplane":      true,
+	"managed":         true,
+	"all":             true,
+}
+
 const (
 	errFmtGenCrd                   = "cannot generate CRD for %q %q"
 	errParseValidation             = "cannot parse validation schema"
 	errInvalidClaimNames           = "invalid resource claim names"
 	errMissingClaimNames           = "missing names"
+	errClaimsDisallowed            = "composite resource definition disallows claims"
 	errFmtConflictingClaimName     = "%q conflicts with composite resource name"
+	errInvalidShortNames           = "invalid resource short names"
+	errFmtReservedShortName        = "short name %q conflicts with a reserved category"
+	errFmtConflictingShortName     = "short name %q conflicts with resource name"
 	errCustomResourceValidationNil = "custom resource validation cannot be nil"
 )
 
 // ForCompositeResource derives the CustomResourceDefinition for a composite
 // resource from the supplied CompositeResourceDefinition.
 func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+	if err := validateShortNames(xrd.Spec.Names); err != nil {
+		return nil, errors.Wrap(err, errInvalidShortNames)
+	}
+
 	crd := &extv1.CustomResourceDefinition{
 		Spec: extv1.CustomResourceDefinitionSpec{
 			Scope:      extv1.ClusterScoped,
@@ -83,6 +103,11 @@ func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomRes
 		}
 		crdv.AdditionalPrinterColumns = append(crdv.AdditionalPrinterColumns, CompositeResourcePrinterColumns()...)
 		props := CompositeResourceSpecProps()
+		if xrd.DisallowsClaim() {
+			// The XR can't be claimed, so there's no need for a reference
+			// to its claim.
+			delete(props, "claimRef")
+		}
 		if xrd.Spec.DefaultCompositionUpdatePolicy != nil {
 			cup := props["compositionUpdatePolicy"]
 			cup.Default = &extv1.JSON{Raw: []byte(fmt.Sprintf("\"%s\"", *xrd.Spec.DefaultCompositionUpdatePolicy))}
@@ -91,6 +116,11 @@ func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomRes
 		for k, v := range props {
 			crdv.Schema.OpenAPIV3Schema.Properties["spec"].Properties[k] = v
 		}
+		if xrd.LocksCompositionRef() {
+			spec := crdv.Schema.OpenAPIV3Schema.Properties["spec"]
+			spec.XValidations = append(spec.XValidations, CompositionRefImmutableValidations()...)
+			crdv.Schema.OpenAPIV3Schema.Properties["spec"] = spec
+		}
 		crd.Spec.Versions[i] = *crdv
 	}
 
@@ -100,6 +130,9 @@ func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomRes
 // ForCompositeResourceClaim derives the CustomResourceDefinition for a
 // composite resource claim from the supplied CompositeResourceDefinition.
 func ForCompositeResourceClaim(xrd *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+	if xrd.DisallowsClaim() {
+		return nil, errors.New(errClaimsDisallowed)
+	}
 	if err := validateClaimNames(xrd); err != nil {
 		return nil, errors.Wrap(err, errInvalidClaimNames)
 	}
@@ -142,6 +175,11 @@ func ForCompositeResourceClaim(xrd *v1.CompositeResourceDefinition) (*extv1.Cust
 		for k, v := range props {
 			crdv.Schema.OpenAPIV3Schema.Properties["spec"].Properties[k] = v
 		}
+		if xrd.LocksCompositionRef() {
+			spec := crdv.Schema.OpenAPIV3Schema.Properties["spec"]
+			spec.XValidations = append(spec.XValidations, CompositionRefImmutableValidations()...)
+			crdv.Schema.OpenAPIV3Schema.Properties["spec"] = spec
+		}
 		crd.Spec.Versions[i] = *crdv
 	}
 
@@ -234,6 +272,42 @@ func validateClaimNames(d *v1.CompositeResourceDefinition) error {
 		return errors.Errorf(errFmtConflictingClaimName, n)
 	}
 
+	if err := validateShortNames(*d.Spec.ClaimNames); err != nil {
+		return err
+	}
+
+	// A claim short name must not resolve to the composite resource, and vice
+	// versa, otherwise kubectl could not tell which one was meant.
+	xr := map[string]bool{d.Spec.Names.Plural: true, d.Spec.Names.Singular: true}
+	for _, sn := range d.Spec.Names.ShortNames {
+		xr[sn] = true
+	}
+	for _, sn := range d.Spec.ClaimNames.ShortNames {
+		if xr[sn] {
+			return errors.Errorf(errFmtConflictingClaimName, sn)
+		}
+	}
+	for _, sn := range d.Spec.Names.ShortNames {
+		if sn == d.Spec.ClaimNames.Plural || sn == d.Spec.ClaimNames.Singular {
+			return errors.Errorf(errFmtConflictingClaimName, sn)
+		}
+	}
+
+	return nil
+}
+
+// validateShortNames returns an error if any of the supplied short names
+// collides with a category Crossplane or kubectl reserves, or with one of the
+// resource's own names.
+func validateShortNames(n extv1.CustomResourceDefinitionNames) error {
+	for _, sn := range n.ShortNames {
+		if reservedCategories[sn] {
+			return errors.Errorf(errFmtReservedShortName, sn)
+		}
+		if sn == n.Plural || sn == n.Singular || sn == strings.ToLower(n.Kind) {
+			return errors.Errorf(errFmtConflictingShortName, sn)
+		}
+	}
 	return nil
 }
 
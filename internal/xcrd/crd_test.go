@@ -335,6 +335,10 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRevisionHash": {
+													Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+													Type:        "string",
+												},
 												"compositionUpdatePolicy": {
 													Type: "string",
 													Enum: []extv1.JSON{
@@ -461,6 +465,69 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"lastHandledReconcileAt": {
+													Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+													Type:        "string",
+												},
+												"functionState": {
+													Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+													Type:                   "object",
+													XPreserveUnknownFields: ptr.To(true),
+												},
+												"pipelineDebug": {
+													Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"request": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"response": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"step":      {Type: "string"},
+																"truncated": {Type: "boolean"},
+															},
+														},
+													},
+												},
+												"pipelineResults": {
+													Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"message", "severity", "step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"message":  {Type: "string"},
+																"reason":   {Type: "string"},
+																"severity": {Type: "string"},
+																"step":     {Type: "string"},
+															},
+														},
+													},
+												},
+												"resourceReadiness": {
+													Description: "Readiness of the composed resources as of the most recent reconcile.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"ready": {Type: "integer"},
+														"total": {Type: "integer"},
+														"unready": {
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -646,6 +713,10 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRevisionHash": {
+													Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+													Type:        "string",
+												},
 												"compositionUpdatePolicy": {
 													Type:    "string",
 													Default: &extv1.JSON{Raw: []byte(fmt.Sprintf("\"%s\"", defaultCompositionUpdatePolicy))},
@@ -773,6 +844,69 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"lastHandledReconcileAt": {
+													Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+													Type:        "string",
+												},
+												"functionState": {
+													Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+													Type:                   "object",
+													XPreserveUnknownFields: ptr.To(true),
+												},
+												"pipelineDebug": {
+													Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"request": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"response": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"step":      {Type: "string"},
+																"truncated": {Type: "boolean"},
+															},
+														},
+													},
+												},
+												"pipelineResults": {
+													Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"message", "severity", "step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"message":  {Type: "string"},
+																"reason":   {Type: "string"},
+																"severity": {Type: "string"},
+																"step":     {Type: "string"},
+															},
+														},
+													},
+												},
+												"resourceReadiness": {
+													Description: "Readiness of the composed resources as of the most recent reconcile.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"ready": {Type: "integer"},
+														"total": {Type: "integer"},
+														"unready": {
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -922,6 +1056,10 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRevisionHash": {
+													Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+													Type:        "string",
+												},
 												"compositionUpdatePolicy": {
 													Type: "string",
 													Enum: []extv1.JSON{
@@ -1035,6 +1173,69 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"lastHandledReconcileAt": {
+													Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+													Type:        "string",
+												},
+												"functionState": {
+													Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+													Type:                   "object",
+													XPreserveUnknownFields: ptr.To(true),
+												},
+												"pipelineDebug": {
+													Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"request": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"response": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"step":      {Type: "string"},
+																"truncated": {Type: "boolean"},
+															},
+														},
+													},
+												},
+												"pipelineResults": {
+													Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"message", "severity", "step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"message":  {Type: "string"},
+																"reason":   {Type: "string"},
+																"severity": {Type: "string"},
+																"step":     {Type: "string"},
+															},
+														},
+													},
+												},
+												"resourceReadiness": {
+													Description: "Readiness of the composed resources as of the most recent reconcile.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"ready": {Type: "integer"},
+														"total": {Type: "integer"},
+														"unready": {
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -1187,6 +1388,10 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRevisionHash": {
+													Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+													Type:        "string",
+												},
 												"compositionUpdatePolicy": {
 													Type: "string",
 													Enum: []extv1.JSON{
@@ -1313,6 +1518,69 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"lastHandledReconcileAt": {
+													Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+													Type:        "string",
+												},
+												"functionState": {
+													Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+													Type:                   "object",
+													XPreserveUnknownFields: ptr.To(true),
+												},
+												"pipelineDebug": {
+													Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"request": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"response": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"step":      {Type: "string"},
+																"truncated": {Type: "boolean"},
+															},
+														},
+													},
+												},
+												"pipelineResults": {
+													Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"message", "severity", "step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"message":  {Type: "string"},
+																"reason":   {Type: "string"},
+																"severity": {Type: "string"},
+																"step":     {Type: "string"},
+															},
+														},
+													},
+												},
+												"resourceReadiness": {
+													Description: "Readiness of the composed resources as of the most recent reconcile.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"ready": {Type: "integer"},
+														"total": {Type: "integer"},
+														"unready": {
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -1475,6 +1743,10 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRevisionHash": {
+													Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+													Type:        "string",
+												},
 												"compositionUpdatePolicy": {
 													Type: "string",
 													Enum: []extv1.JSON{
@@ -1601,6 +1873,69 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"lastHandledReconcileAt": {
+													Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+													Type:        "string",
+												},
+												"functionState": {
+													Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+													Type:                   "object",
+													XPreserveUnknownFields: ptr.To(true),
+												},
+												"pipelineDebug": {
+													Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"request": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"response": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"step":      {Type: "string"},
+																"truncated": {Type: "boolean"},
+															},
+														},
+													},
+												},
+												"pipelineResults": {
+													Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"message", "severity", "step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"message":  {Type: "string"},
+																"reason":   {Type: "string"},
+																"severity": {Type: "string"},
+																"step":     {Type: "string"},
+															},
+														},
+													},
+												},
+												"resourceReadiness": {
+													Description: "Readiness of the composed resources as of the most recent reconcile.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"ready": {Type: "integer"},
+														"total": {Type: "integer"},
+														"unready": {
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -1774,6 +2109,10 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"compositionRevisionHash": {
+													Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+													Type:        "string",
+												},
 												"compositionUpdatePolicy": {
 													Type: "string",
 													Enum: []extv1.JSON{
@@ -1900,6 +2239,69 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"lastHandledReconcileAt": {
+													Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+													Type:        "string",
+												},
+												"functionState": {
+													Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+													Type:                   "object",
+													XPreserveUnknownFields: ptr.To(true),
+												},
+												"pipelineDebug": {
+													Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"request": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"response": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"step":      {Type: "string"},
+																"truncated": {Type: "boolean"},
+															},
+														},
+													},
+												},
+												"pipelineResults": {
+													Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"message", "severity", "step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"message":  {Type: "string"},
+																"reason":   {Type: "string"},
+																"severity": {Type: "string"},
+																"step":     {Type: "string"},
+															},
+														},
+													},
+												},
+												"resourceReadiness": {
+													Description: "Readiness of the composed resources as of the most recent reconcile.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"ready": {Type: "integer"},
+														"total": {Type: "integer"},
+														"unready": {
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -2035,6 +2437,67 @@ func TestValidateClaimNames(t *testing.T) {
 			},
 			want: errors.Errorf(errFmtConflictingClaimName, "a"),
 		},
+		"ClaimShortNameConflictsWithCompositeShortName": {
+			d: &v1.CompositeResourceDefinition{
+				Spec: v1.CompositeResourceDefinitionSpec{
+					ClaimNames: &extv1.CustomResourceDefinitionNames{
+						Kind:       "a",
+						ListKind:   "a",
+						Singular:   "a",
+						Plural:     "a",
+						ShortNames: []string{"c"},
+					},
+					Names: extv1.CustomResourceDefinitionNames{
+						Kind:       "b",
+						ListKind:   "b",
+						Singular:   "b",
+						Plural:     "b",
+						ShortNames: []string{"c"},
+					},
+				},
+			},
+			want: errors.Errorf(errFmtConflictingClaimName, "c"),
+		},
+		"CompositeShortNameConflictsWithClaimPlural": {
+			d: &v1.CompositeResourceDefinition{
+				Spec: v1.CompositeResourceDefinitionSpec{
+					ClaimNames: &extv1.CustomResourceDefinitionNames{
+						Kind:     "a",
+						ListKind: "a",
+						Singular: "a",
+						Plural:   "as",
+					},
+					Names: extv1.CustomResourceDefinitionNames{
+						Kind:       "b",
+						ListKind:   "b",
+						Singular:   "b",
+						Plural:     "b",
+						ShortNames: []string{"as"},
+					},
+				},
+			},
+			want: errors.Errorf(errFmtConflictingClaimName, "as"),
+		},
+		"ClaimShortNameReserved": {
+			d: &v1.CompositeResourceDefinition{
+				Spec: v1.CompositeResourceDefinitionSpec{
+					ClaimNames: &extv1.CustomResourceDefinitionNames{
+						Kind:       "a",
+						ListKind:   "a",
+						Singular:   "a",
+						Plural:     "a",
+						ShortNames: []string{CategoryClaim},
+					},
+					Names: extv1.CustomResourceDefinitionNames{
+						Kind:     "b",
+						ListKind: "b",
+						Singular: "b",
+						Plural:   "b",
+					},
+				},
+			},
+			want: errors.Errorf(errFmtReservedShortName, CategoryClaim),
+		},
 	}
 
 	for name, tc := range cases {
@@ -2047,6 +2510,86 @@ func TestValidateClaimNames(t *testing.T) {
 	}
 }
 
+func TestValidateShortNames(t *testing.T) {
+	cases := map[string]struct {
+		n    extv1.CustomResourceDefinitionNames
+		want error
+	}{
+		"NoShortNames": {
+			n: extv1.CustomResourceDefinitionNames{Kind: kind, Plural: plural, Singular: singular},
+		},
+		"ValidShortNames": {
+			n: extv1.CustomResourceDefinitionNames{Kind: kind, Plural: plural, Singular: singular, ShortNames: []string{"cc", "ccs"}},
+		},
+		"ReservedCategory": {
+			n:    extv1.CustomResourceDefinitionNames{Kind: kind, Plural: plural, Singular: singular, ShortNames: []string{"cc", "all"}},
+			want: errors.Errorf(errFmtReservedShortName, "all"),
+		},
+		"ConflictsWithPlural": {
+			n:    extv1.CustomResourceDefinitionNames{Kind: kind, Plural: plural, Singular: singular, ShortNames: []string{plural}},
+			want: errors.Errorf(errFmtConflictingShortName, plural),
+		},
+		"ConflictsWithKind": {
+			n:    extv1.CustomResourceDefinitionNames{Kind: kind, Plural: plural, Singular: "cool", ShortNames: []string{"coolcomposite"}},
+			want: errors.Errorf(errFmtConflictingShortName, "coolcomposite"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := validateShortNames(tc.n)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("validateShortNames(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShortNames(t *testing.T) {
+	xrd := &v1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group: group,
+			Names: extv1.CustomResourceDefinitionNames{
+				Plural:     plural,
+				Singular:   singular,
+				Kind:       kind,
+				ListKind:   listKind,
+				ShortNames: []string{"cc"},
+			},
+			ClaimNames: &extv1.CustomResourceDefinitionNames{
+				Plural:     "coolclaims",
+				Singular:   "coolclaim",
+				Kind:       "CoolClaim",
+				ListKind:   "CoolClaimList",
+				ShortNames: []string{"ccl"},
+			},
+			Versions: []v1.CompositeResourceDefinitionVersion{{
+				Name:          version,
+				Referenceable: true,
+				Served:        true,
+				Schema:        &v1.CompositeResourceValidation{OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(schema)}},
+			}},
+		},
+	}
+
+	xr, err := ForCompositeResource(xrd)
+	if err != nil {
+		t.Fatalf("ForCompositeResource(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"cc"}, xr.Spec.Names.ShortNames); diff != "" {
+		t.Errorf("ForCompositeResource(...): -want short names, +got short names:\n%s", diff)
+	}
+
+	claim, err := ForCompositeResourceClaim(xrd)
+	if err != nil {
+		t.Fatalf("ForCompositeResourceClaim(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"ccl"}, claim.Spec.Names.ShortNames); diff != "" {
+		t.Errorf("ForCompositeResourceClaim(...): -want short names, +got short names:\n%s", diff)
+	}
+}
+
 func TestForCompositeResourceClaim(t *testing.T) {
 	name := "coolcomposites.example.org"
 	labels := map[string]string{"cool": "very"}
@@ -2288,6 +2831,10 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"compositionRevisionHash": {
+													Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+													Type:        "string",
+												},
 												"compositionUpdatePolicy": {
 													Type: "string",
 													Enum: []extv1.JSON{
@@ -2392,6 +2939,69 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"lastHandledReconcileAt": {
+													Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+													Type:        "string",
+												},
+												"functionState": {
+													Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+													Type:                   "object",
+													XPreserveUnknownFields: ptr.To(true),
+												},
+												"pipelineDebug": {
+													Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"request": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"response": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"step":      {Type: "string"},
+																"truncated": {Type: "boolean"},
+															},
+														},
+													},
+												},
+												"pipelineResults": {
+													Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"message", "severity", "step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"message":  {Type: "string"},
+																"reason":   {Type: "string"},
+																"severity": {Type: "string"},
+																"step":     {Type: "string"},
+															},
+														},
+													},
+												},
+												"resourceReadiness": {
+													Description: "Readiness of the composed resources as of the most recent reconcile.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"ready": {Type: "integer"},
+														"total": {Type: "integer"},
+														"unready": {
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -2583,6 +3193,10 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"compositionRevisionHash": {
+													Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+													Type:        "string",
+												},
 												"compositionUpdatePolicy": {
 													Type: "string",
 													Enum: []extv1.JSON{
@@ -2687,6 +3301,69 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"lastHandledReconcileAt": {
+													Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+													Type:        "string",
+												},
+												"functionState": {
+													Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+													Type:                   "object",
+													XPreserveUnknownFields: ptr.To(true),
+												},
+												"pipelineDebug": {
+													Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"request": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"response": {
+																	Type:                   "object",
+																	XPreserveUnknownFields: ptr.To(true),
+																},
+																"step":      {Type: "string"},
+																"truncated": {Type: "boolean"},
+															},
+														},
+													},
+												},
+												"pipelineResults": {
+													Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+													Type:        "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"message", "severity", "step"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"message":  {Type: "string"},
+																"reason":   {Type: "string"},
+																"severity": {Type: "string"},
+																"step":     {Type: "string"},
+															},
+														},
+													},
+												},
+												"resourceReadiness": {
+													Description: "Readiness of the composed resources as of the most recent reconcile.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"ready": {Type: "integer"},
+														"total": {Type: "integer"},
+														"unready": {
+															Type: "array",
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
@@ -2900,6 +3577,10 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 												},
 											},
 										},
+										"compositionRevisionHash": {
+											Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+											Type:        "string",
+										},
 										"compositionUpdatePolicy": {
 											Type: "string",
 											Enum: []extv1.JSON{
@@ -2996,6 +3677,69 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 												},
 											},
 										},
+										"lastHandledReconcileAt": {
+											Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+											Type:        "string",
+										},
+										"functionState": {
+											Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+											Type:                   "object",
+											XPreserveUnknownFields: ptr.To(true),
+										},
+										"pipelineDebug": {
+											Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+											Type:        "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type:     "object",
+													Required: []string{"step"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"request": {
+															Type:                   "object",
+															XPreserveUnknownFields: ptr.To(true),
+														},
+														"response": {
+															Type:                   "object",
+															XPreserveUnknownFields: ptr.To(true),
+														},
+														"step":      {Type: "string"},
+														"truncated": {Type: "boolean"},
+													},
+												},
+											},
+										},
+										"pipelineResults": {
+											Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+											Type:        "array",
+											Items: &extv1.JSONSchemaPropsOrArray{
+												Schema: &extv1.JSONSchemaProps{
+													Type:     "object",
+													Required: []string{"message", "severity", "step"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"message":  {Type: "string"},
+														"reason":   {Type: "string"},
+														"severity": {Type: "string"},
+														"step":     {Type: "string"},
+													},
+												},
+											},
+										},
+										"resourceReadiness": {
+											Description: "Readiness of the composed resources as of the most recent reconcile.",
+											Type:        "object",
+											Properties: map[string]extv1.JSONSchemaProps{
+												"ready": {Type: "integer"},
+												"total": {Type: "integer"},
+												"unready": {
+													Type: "array",
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+											},
+										},
 										"connectionDetails": {
 											Type: "object",
 											Properties: map[string]extv1.JSONSchemaProps{
@@ -3207,3 +3951,151 @@ func TestSetCrdMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestClaimPolicy(t *testing.T) {
+	newXRD := func(p *v1.ClaimPolicy, claimNames *extv1.CustomResourceDefinitionNames) *v1.CompositeResourceDefinition {
+		return &v1.CompositeResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "coolcomposites.example.org"},
+			Spec: v1.CompositeResourceDefinitionSpec{
+				Group: "example.org",
+				Names: extv1.CustomResourceDefinitionNames{
+					Plural: "coolcomposites",
+					Kind:   "CoolComposite",
+				},
+				ClaimNames:  claimNames,
+				ClaimPolicy: p,
+				Versions: []v1.CompositeResourceDefinitionVersion{{
+					Name:          "v1",
+					Referenceable: true,
+					Served:        true,
+					Schema: &v1.CompositeResourceValidation{
+						OpenAPIV3Schema: runtime.RawExtension{Raw: []byte("{}")},
+					},
+				}},
+			},
+		}
+	}
+	claimNames := &extv1.CustomResourceDefinitionNames{Plural: "coolclaims", Kind: "CoolClaim"}
+
+	type want struct {
+		claimRef bool
+		claimErr error
+	}
+
+	cases := map[string]struct {
+		reason string
+		xrd    *v1.CompositeResourceDefinition
+		want   want
+	}{
+		"Optional": {
+			reason: "An XR that may be claimed should have a claim reference, and a claim CRD.",
+			xrd:    newXRD(ptr.To(v1.ClaimPolicyOptional), claimNames),
+			want: want{
+				claimRef: true,
+			},
+		},
+		"Required": {
+			reason: "An XR that must be claimable should have a claim reference, and a claim CRD.",
+			xrd:    newXRD(ptr.To(v1.ClaimPolicyRequired), claimNames),
+			want: want{
+				claimRef: true,
+			},
+		},
+		"Disallowed": {
+			reason: "An XR that may not be claimed should have no claim reference, and no claim CRD.",
+			xrd:    newXRD(ptr.To(v1.ClaimPolicyDisallowed), nil),
+			want: want{
+				claimRef: false,
+				claimErr: errors.New(errClaimsDisallowed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			crd, err := ForCompositeResource(tc.xrd)
+			if err != nil {
+				t.Fatalf("ForCompositeResource(...): %s", err)
+			}
+			_, got := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["claimRef"]
+			if diff := cmp.Diff(tc.want.claimRef, got); diff != "" {
+				t.Errorf("\n%s\nForCompositeResource(...): -want claimRef, +got claimRef:\n%s", tc.reason, diff)
+			}
+
+			_, err = ForCompositeResourceClaim(tc.xrd)
+			if diff := cmp.Diff(tc.want.claimErr, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nForCompositeResourceClaim(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionRefPolicy(t *testing.T) {
+	newXRD := func(p *v1.CompositionRefPolicy) *v1.CompositeResourceDefinition {
+		return &v1.CompositeResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "coolcomposites.example.org"},
+			Spec: v1.CompositeResourceDefinitionSpec{
+				Group: "example.org",
+				Names: extv1.CustomResourceDefinitionNames{
+					Plural: "coolcomposites",
+					Kind:   "CoolComposite",
+				},
+				ClaimNames:           &extv1.CustomResourceDefinitionNames{Plural: "coolclaims", Kind: "CoolClaim"},
+				CompositionRefPolicy: p,
+				Versions: []v1.CompositeResourceDefinitionVersion{{
+					Name:          "v1",
+					Referenceable: true,
+					Served:        true,
+					Schema: &v1.CompositeResourceValidation{
+						OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(`{"properties":{"spec":{"x-kubernetes-validations":[{"rule":"true"}]}}}`)},
+					},
+				}},
+			},
+		}
+	}
+	userRules := extv1.ValidationRules{{Rule: "true"}}
+
+	cases := map[string]struct {
+		reason string
+		xrd    *v1.CompositeResourceDefinition
+		want   extv1.ValidationRules
+	}{
+		"Unset": {
+			reason: "An XR whose definition doesn't specify a policy may switch Compositions.",
+			xrd:    newXRD(nil),
+			want:   userRules,
+		},
+		"Mutable": {
+			reason: "An XR whose definition allows it to switch Compositions should have no extra validation rules.",
+			xrd:    newXRD(ptr.To(v1.CompositionRefPolicyMutable)),
+			want:   userRules,
+		},
+		"Immutable": {
+			reason: "An XR whose definition prevents it from switching Compositions should have validation rules that lock its compositionRef and compositionSelector.",
+			xrd:    newXRD(ptr.To(v1.CompositionRefPolicyImmutable)),
+			want:   append(userRules, CompositionRefImmutableValidations()...),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			crd, err := ForCompositeResource(tc.xrd)
+			if err != nil {
+				t.Fatalf("ForCompositeResource(...): %s", err)
+			}
+			got := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].XValidations
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nForCompositeResource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			crd, err = ForCompositeResourceClaim(tc.xrd)
+			if err != nil {
+				t.Fatalf("ForCompositeResourceClaim(...): %s", err)
+			}
+			got = crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].XValidations
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nForCompositeResourceClaim(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
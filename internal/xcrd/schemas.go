@@ -26,6 +26,16 @@ const (
 	LabelKeyNamePrefixForComposed = "crossplane.io/composite"
 	LabelKeyClaimName             = "crossplane.io/claim-name"
 	LabelKeyClaimNamespace        = "crossplane.io/claim-namespace"
+
+	// LabelKeyCompositionDepth is how deeply a resource is nested under a
+	// top-level composite resource. A top-level composite resource is at
+	// depth zero, and the resources it composes are at depth one.
+	LabelKeyCompositionDepth = "crossplane.io/composition-depth"
+
+	// LabelKeyCompositeUID is the UID of the composite resource that composed
+	// a resource. It's only set on composed resources that don't have a
+	// controller reference to their composite resource.
+	LabelKeyCompositeUID = "crossplane.io/composite-uid"
 )
 
 // CompositionRevisionRef should be propagated dynamically.
@@ -33,7 +43,7 @@ const CompositionRevisionRef = "compositionRevisionRef"
 
 // PropagateSpecProps is the list of XRC spec properties to propagate
 // when translating an XRC into an XR.
-var PropagateSpecProps = []string{"compositionRef", "compositionSelector", "compositionUpdatePolicy", "compositionRevisionSelector"} //nolint:gochecknoglobals // We treat this as a constant.
+var PropagateSpecProps = []string{"compositionRef", "compositionSelector", "compositionUpdatePolicy", "compositionRevisionSelector", "compositionRevisionHash"} //nolint:gochecknoglobals // We treat this as a constant.
 
 // TODO(negz): Add descriptions to schema fields.
 
@@ -112,6 +122,10 @@ func CompositeResourceSpecProps() map[string]extv1.JSONSchemaProps {
 				},
 			},
 		},
+		"compositionRevisionHash": {
+			Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+			Type:        "string",
+		},
 		"compositionUpdatePolicy": {
 			Type: "string",
 			Enum: []extv1.JSON{
@@ -239,6 +253,10 @@ func CompositeResourceClaimSpecProps() map[string]extv1.JSONSchemaProps {
 				},
 			},
 		},
+		"compositionRevisionHash": {
+			Description: "The crossplane.io/composition-hash label of the composition revision this resource is pinned to. Crossplane refuses to reconcile the composite resource against a composition revision with a different hash.",
+			Type:        "string",
+		},
 		"compositionUpdatePolicy": {
 			Type: "string",
 			Enum: []extv1.JSON{
@@ -310,6 +328,24 @@ func CompositeResourceClaimSpecProps() map[string]extv1.JSONSchemaProps {
 	}
 }
 
+// CompositionRefImmutableValidations are CEL validation rules for the spec of
+// a composite resource or claim whose definition prevents it from switching
+// Compositions. The compositionRef may be set once, by the user or when
+// Crossplane selects a Composition, but can't then be changed or removed. The
+// compositionSelector can't be added, changed, or removed after creation.
+func CompositionRefImmutableValidations() extv1.ValidationRules {
+	return extv1.ValidationRules{
+		{
+			Rule:    "!has(oldSelf.compositionRef) || (has(self.compositionRef) && self.compositionRef == oldSelf.compositionRef)",
+			Message: "compositionRef is immutable once set",
+		},
+		{
+			Rule:    "has(self.compositionSelector) == has(oldSelf.compositionSelector) && (!has(self.compositionSelector) || self.compositionSelector == oldSelf.compositionSelector)",
+			Message: "compositionSelector is immutable",
+		},
+	}
+}
+
 // CompositeResourceStatusProps is a partial OpenAPIV3Schema for the status
 // fields that Crossplane expects to be present for all defined or published
 // infrastructure resources.
@@ -351,6 +387,69 @@ func CompositeResourceStatusProps() map[string]extv1.JSONSchemaProps {
 				},
 			},
 		},
+		"lastHandledReconcileAt": {
+			Description: "The last value of the crossplane.io/reconcile-requested-at annotation that was handled.",
+			Type:        "string",
+		},
+		"functionState": {
+			Description:            "State persisted by Composition Functions between reconciles, keyed by pipeline step. It's a non-authoritative cache.",
+			Type:                   "object",
+			XPreserveUnknownFields: ptr.To(true),
+		},
+		"pipelineDebug": {
+			Description: "Redacted requests and responses of the Composition Function pipeline steps that ran during the most recent reconcile. Only recorded while the crossplane.io/composition-debug-until annotation is set to a future time.",
+			Type:        "array",
+			Items: &extv1.JSONSchemaPropsOrArray{
+				Schema: &extv1.JSONSchemaProps{
+					Type:     "object",
+					Required: []string{"step"},
+					Properties: map[string]extv1.JSONSchemaProps{
+						"request": {
+							Type:                   "object",
+							XPreserveUnknownFields: ptr.To(true),
+						},
+						"response": {
+							Type:                   "object",
+							XPreserveUnknownFields: ptr.To(true),
+						},
+						"step":      {Type: "string"},
+						"truncated": {Type: "boolean"},
+					},
+				},
+			},
+		},
+		"pipelineResults": {
+			Description: "Results returned by Composition Function pipeline steps during the most recent reconcile.",
+			Type:        "array",
+			Items: &extv1.JSONSchemaPropsOrArray{
+				Schema: &extv1.JSONSchemaProps{
+					Type:     "object",
+					Required: []string{"message", "severity", "step"},
+					Properties: map[string]extv1.JSONSchemaProps{
+						"message":  {Type: "string"},
+						"reason":   {Type: "string"},
+						"severity": {Type: "string"},
+						"step":     {Type: "string"},
+					},
+				},
+			},
+		},
+		"resourceReadiness": {
+			Description: "Readiness of the composed resources as of the most recent reconcile.",
+			Type:        "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"ready": {Type: "integer"},
+				"total": {Type: "integer"},
+				"unready": {
+					Type: "array",
+					Items: &extv1.JSONSchemaPropsOrArray{
+						Schema: &extv1.JSONSchemaProps{
+							Type: "string",
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
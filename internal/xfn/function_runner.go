@@ -23,6 +23,9 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -82,6 +85,7 @@ type PackagedFunctionRunner struct {
 	client       client.Reader
 	creds        credentials.TransportCredentials
 	interceptors []InterceptorCreator
+	tracing      trace.TracerProvider
 
 	connsMx sync.RWMutex
 	conns   map[string]*grpc.ClientConn
@@ -122,6 +126,16 @@ func WithInterceptorCreators(ics ...InterceptorCreator) PackagedFunctionRunnerOp
 	}
 }
 
+// WithTracerProvider configures the PackagedFunctionRunner to trace gRPC calls
+// to functions using the supplied OpenTelemetry tracer provider. The caller's
+// span context is propagated to functions using W3C trace context metadata,
+// so that spans functions emit are linked to the caller's trace.
+func WithTracerProvider(tp trace.TracerProvider) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.tracing = tp
+	}
+}
+
 // NewPackagedFunctionRunner returns a FunctionRunner that runs a Function by
 // making a gRPC call to a Function package's runtime.
 func NewPackagedFunctionRunner(c client.Reader, o ...PackagedFunctionRunnerOption) *PackagedFunctionRunner {
@@ -232,10 +246,19 @@ func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string)
 		is[i] = r.interceptors[i].CreateInterceptor(name, active.Spec.Package)
 	}
 
-	conn, err := grpc.NewClient(active.Status.Endpoint,
+	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(r.creds),
 		grpc.WithDefaultServiceConfig(svcConfig),
-		grpc.WithChainUnaryInterceptor(is...))
+		grpc.WithChainUnaryInterceptor(is...),
+	}
+	if r.tracing != nil {
+		opts = append(opts, grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(r.tracing),
+			otelgrpc.WithPropagators(propagation.TraceContext{}),
+		)))
+	}
+
+	conn, err := grpc.NewClient(active.Status.Endpoint, opts...)
 	if err != nil {
 		return nil, errors.Wrapf(err, errFmtDialFunction, active.Status.Endpoint, active.GetName())
 	}
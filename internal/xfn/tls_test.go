@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM encoded certificate and private key signed by the CA.
+func (ca *testCA) issue(t *testing.T, serial int64, dnsNames ...string) (certPEM, keyPEM []byte, der []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err = x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kder, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder}), der
+}
+
+func writeFile(t *testing.T, path string, data []byte, mod time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadingClientTLS(t *testing.T) {
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.crt")
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	if _, err := NewReloadingClientTLS(caPath, certPath, keyPath); err == nil {
+		t.Errorf("NewReloadingClientTLS(...): want error loading missing certificates, got nil")
+	}
+
+	const server = "function-cool.crossplane-system"
+	verify := func(cfg *tls.Config, ca *testCA, serverName string) error {
+		_, _, der := ca.issue(t, 100, server)
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cfg.VerifyConnection(tls.ConnectionState{ServerName: serverName, PeerCertificates: []*x509.Certificate{cert}})
+	}
+
+	before := time.Now().Add(-time.Minute)
+	ca1 := newTestCA(t, "ca-1")
+	cert1, key1, der1 := ca1.issue(t, 1)
+	writeFile(t, caPath, ca1.pem, before)
+	writeFile(t, certPath, cert1, before)
+	writeFile(t, keyPath, key1, before)
+
+	r, err := NewReloadingClientTLS(caPath, certPath, keyPath)
+	if err != nil {
+		t.Fatalf("NewReloadingClientTLS(...): %v", err)
+	}
+	cfg := r.Config()
+
+	got, err := cfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	if err != nil {
+		t.Fatalf("GetClientCertificate(...): %v", err)
+	}
+	if diff := cmp.Diff(der1, got.Certificate[0]); diff != "" {
+		t.Errorf("GetClientCertificate(...): -want, +got:\n%s", diff)
+	}
+
+	if err := verify(cfg, ca1, server); err != nil {
+		t.Errorf("VerifyConnection(...): want a server certificate signed by the CA to be valid, got %v", err)
+	}
+	if err := verify(cfg, ca1, "function-other.crossplane-system"); err == nil {
+		t.Errorf("VerifyConnection(...): want a server certificate for another name to be invalid, got nil")
+	}
+	ca2 := newTestCA(t, "ca-2")
+	if err := verify(cfg, ca2, server); err == nil {
+		t.Errorf("VerifyConnection(...): want a server certificate signed by another CA to be invalid, got nil")
+	}
+
+	// Rotate the CA and client certificate.
+	after := time.Now()
+	cert2, key2, der2 := ca2.issue(t, 2)
+	writeFile(t, caPath, ca2.pem, after)
+	writeFile(t, certPath, cert2, after)
+	writeFile(t, keyPath, key2, after)
+
+	got, err = cfg.GetClientCertificate(&tls.CertificateRequestInfo{})
+	if err != nil {
+		t.Fatalf("GetClientCertificate(...): %v", err)
+	}
+	if diff := cmp.Diff(der2, got.Certificate[0]); diff != "" {
+		t.Errorf("GetClientCertificate(...): want rotated certificate: -want, +got:\n%s", diff)
+	}
+	if err := verify(cfg, ca2, server); err != nil {
+		t.Errorf("VerifyConnection(...): want a server certificate signed by the rotated CA to be valid, got %v", err)
+	}
+	if err := verify(cfg, ca1, server); err == nil {
+		t.Errorf("VerifyConnection(...): want a server certificate signed by the old CA to be invalid, got nil")
+	}
+}
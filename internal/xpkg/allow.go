@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errFmtInvalidAllowedRegistry = "invalid allowed registry %q"
+	errParseAllowSource          = "cannot parse package source"
+)
+
+// A RegistryAllowList restricts the registries packages may be installed from.
+//
+// Each entry is either a registry host, optionally with a port, or a registry
+// host followed by a repository path prefix. For example:
+//
+//   - xpkg.upbound.io allows any package from xpkg.upbound.io, but not from
+//     xpkg.upbound.io.example.org or registry.xpkg.upbound.io.
+//   - xpkg.upbound.io/crossplane-contrib allows any package in or under the
+//     crossplane-contrib repository path, but not crossplane-contrib-fork.
+//
+// Registry hosts must match exactly. Repository paths must match whole path
+// segments. A package source's tag or digest is ignored - only the registry
+// and repository it refers to are evaluated, so a source like
+// xpkg.upbound.io/crossplane-contrib/provider-nop@sha256:... is allowed if
+// xpkg.upbound.io is. Sources that don't specify a registry are evaluated as
+// if they were from the default registry. The docker.io registry is evaluated
+// as index.docker.io, as it is when packages are pulled.
+type RegistryAllowList struct {
+	allowed         []string
+	defaultRegistry string
+}
+
+// NewRegistryAllowList returns a RegistryAllowList that allows packages from
+// the supplied registries. Sources that don't specify a registry are assumed
+// to be from the supplied default registry. An allow list with no allowed
+// registries allows all packages.
+func NewRegistryAllowList(allowed []string, defaultRegistry string) (*RegistryAllowList, error) {
+	l := &RegistryAllowList{allowed: make([]string, 0, len(allowed)), defaultRegistry: defaultRegistry}
+	for _, a := range allowed {
+		// A registry with a URL scheme, e.g. https://xpkg.upbound.io, would
+		// otherwise be parsed as the host "https:".
+		if strings.Contains(a, "://") {
+			return nil, errors.Errorf(errFmtInvalidAllowedRegistry, a)
+		}
+		host, path, _ := strings.Cut(strings.TrimSuffix(a, "/"), "/")
+		r, err := name.NewRegistry(host, name.StrictValidation)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtInvalidAllowedRegistry, a)
+		}
+		entry := r.RegistryStr()
+		if path != "" {
+			entry += "/" + path
+		}
+		l.allowed = append(l.allowed, entry)
+	}
+	return l, nil
+}
+
+// Allowed returns true if packages may be installed from the supplied source.
+// A nil RegistryAllowList allows all packages.
+func (l *RegistryAllowList) Allowed(source string) (bool, error) {
+	if l == nil || len(l.allowed) == 0 {
+		return true, nil
+	}
+	ref, err := name.ParseReference(source, name.WithDefaultRegistry(l.defaultRegistry))
+	if err != nil {
+		return false, errors.Wrap(err, errParseAllowSource)
+	}
+	repo := ref.Context().RegistryStr() + "/" + ref.Context().RepositoryStr()
+	for _, a := range l.allowed {
+		if repo == a || strings.HasPrefix(repo, a+"/") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// String returns the allowed registries, separated by commas.
+func (l *RegistryAllowList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(l.allowed, ", ")
+}
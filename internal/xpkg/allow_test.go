@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestNewRegistryAllowList(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		allowed []string
+		err     error
+	}{
+		"Valid": {
+			reason:  "Registry hosts, with or without ports and repository path prefixes, should be valid.",
+			allowed: []string{"xpkg.upbound.io", "registry.example.org:5000/team/", "docker.io/crossplane"},
+		},
+		"Scheme": {
+			reason:  "An allowed registry with a URL scheme should be invalid.",
+			allowed: []string{"https://xpkg.upbound.io"},
+			err:     errors.Errorf(errFmtInvalidAllowedRegistry, "https://xpkg.upbound.io"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewRegistryAllowList(tc.allowed, DefaultRegistry)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nNewRegistryAllowList(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRegistryAllowListAllowed(t *testing.T) {
+	l, err := NewRegistryAllowList([]string{"xpkg.upbound.io/crossplane-contrib", "registry.example.org:5000", "docker.io/crossplane"}, DefaultRegistry)
+	if err != nil {
+		t.Fatalf("NewRegistryAllowList(...): %v", err)
+	}
+	_, errInvalidSource := name.ParseReference("Not A Valid Source!", name.WithDefaultRegistry(DefaultRegistry))
+
+	type want struct {
+		allowed bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		l      *RegistryAllowList
+		source string
+		want   want
+	}{
+		"NilAllowList": {
+			reason: "A nil allow list should allow all packages.",
+			source: "registry.example.org/cool/provider",
+			want:   want{allowed: true},
+		},
+		"RepositoryPrefix": {
+			reason: "A source under an allowed repository path should be allowed.",
+			l:      l,
+			source: "xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1",
+			want:   want{allowed: true},
+		},
+		"PartialPathSegment": {
+			reason: "Repository path prefixes should only match whole path segments.",
+			l:      l,
+			source: "xpkg.upbound.io/crossplane-contrib-fork/provider-nop:v0.2.1",
+			want:   want{allowed: false},
+		},
+		"DefaultRegistry": {
+			reason: "A source without a registry should be evaluated as if it were from the default registry.",
+			l:      l,
+			source: "crossplane-contrib/provider-nop:v0.2.1",
+			want:   want{allowed: true},
+		},
+		"Digest": {
+			reason: "A source's digest should be ignored.",
+			l:      l,
+			source: "registry.example.org:5000/cool/provider@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d5d3a8beb8f1c1e",
+			want:   want{allowed: true},
+		},
+		"ExactHost": {
+			reason: "Registry hosts should match exactly.",
+			l:      l,
+			source: "registry.example.org/cool/provider:v1.0.0",
+			want:   want{allowed: false},
+		},
+		"DockerHub": {
+			reason: "Sources from docker.io should match allowed registries for index.docker.io, and vice versa.",
+			l:      l,
+			source: "index.docker.io/crossplane/provider-nop:v0.2.1",
+			want:   want{allowed: true},
+		},
+		"Invalid": {
+			reason: "We should return an error if the source can't be parsed.",
+			l:      l,
+			source: "Not A Valid Source!",
+			want:   want{err: errors.Wrap(errInvalidSource, errParseAllowSource)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			allowed, err := tc.l.Allowed(tc.source)
+			if diff := cmp.Diff(tc.want.allowed, allowed); diff != "" {
+				t.Errorf("\n%s\nAllowed(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAllowed(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package xpkg
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"os"
 	"strings"
@@ -27,13 +30,16 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/parser"
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	"github.com/crossplane/crossplane/apis/pkg/meta/v1beta1"
+	"github.com/crossplane/crossplane/internal/version"
 	"github.com/crossplane/crossplane/internal/xpkg/parser/examples"
 )
 
@@ -49,6 +55,12 @@ const (
 	errConfigFile        = "failed to get config file from image"
 	errMutateConfig      = "failed to mutate config for image"
 	errBuildObjectScheme = "failed to build scheme for package encoder"
+	errHashPackage       = "failed to hash package contents"
+	errGetCachedPackage  = "failed to get cached package contents"
+	errStorePackage      = "failed to cache package contents"
+	errDecodeCachedMeta  = "failed to decode package metadata from cached package contents"
+
+	errFmtReservedAnnotation = "annotation %q is reserved for use by Crossplane"
 )
 
 // annotatedTeeReadCloser is a copy of io.TeeReader that implements
@@ -110,7 +122,9 @@ func New(packageSource, exampleSource parser.Backend, packageParser parser.Parse
 }
 
 type buildOpts struct {
-	base v1.Image
+	base        v1.Image
+	annotations map[string]string
+	cache       PackageCache
 }
 
 // A BuildOpt modifies how a package is built.
@@ -123,6 +137,25 @@ func WithBase(img v1.Image) BuildOpt {
 	}
 }
 
+// WithAnnotations sets OCI annotations of the package's image manifest. A
+// package file stores them as labels in its config file, because the tarball
+// format doesn't support manifest annotations. AnnotateManifest propagates
+// them to the manifest when the package is pushed.
+func WithAnnotations(a map[string]string) BuildOpt {
+	return func(o *buildOpts) {
+		o.annotations = a
+	}
+}
+
+// WithCache caches the package's contents, keyed by a hash of the files they
+// were built from. Building a package whose files haven't changed since it was
+// cached skips parsing, linting, and encoding them.
+func WithCache(c PackageCache) BuildOpt {
+	return func(o *buildOpts) {
+		o.cache = c
+	}
+}
+
 // Build compiles a Crossplane package from an on-disk package.
 func (b *Builder) Build(ctx context.Context, opts ...BuildOpt) (v1.Image, runtime.Object, error) {
 	bOpts := &buildOpts{
@@ -132,15 +165,13 @@ func (b *Builder) Build(ctx context.Context, opts ...BuildOpt) (v1.Image, runtim
 		o(bOpts)
 	}
 
-	// assume examples exist
-	examplesExist := true
-	// Get package YAML stream.
-	pkgReader, err := b.packageSource.Init(ctx)
+	pkgBytes, meta, err := b.content(ctx, bOpts.cache)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, errInitBackend)
+		return nil, nil, err
 	}
-	defer func() { _ = pkgReader.Close() }()
 
+	// assume examples exist
+	examplesExist := true
 	// Get examples YAML stream.
 	exReader, err := b.exampleSource.Init(ctx)
 	if err != nil && !os.IsNotExist(err) {
@@ -152,31 +183,6 @@ func (b *Builder) Build(ctx context.Context, opts ...BuildOpt) (v1.Image, runtim
 		examplesExist = false
 	}
 
-	pkg, err := b.packageParser.Parse(ctx, pkgReader)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, errParserPackage)
-	}
-
-	metas := pkg.GetMeta()
-	if len(metas) != 1 {
-		return nil, nil, errors.New(errNotExactlyOneMeta)
-	}
-
-	// TODO(hasheddan): make linter selection logic configurable.
-	meta := metas[0]
-	var linter parser.Linter
-	switch meta.GetObjectKind().GroupVersionKind().Kind {
-	case pkgmetav1.ConfigurationKind:
-		linter = NewConfigurationLinter()
-	case v1beta1.FunctionKind:
-		linter = NewFunctionLinter()
-	case pkgmetav1.ProviderKind:
-		linter = NewProviderLinter()
-	}
-	if err := linter.Lint(pkg); err != nil {
-		return nil, nil, errors.Wrap(err, errLintPackage)
-	}
-
 	layers := make([]v1.Layer, 0)
 	cfgFile, err := bOpts.base.ConfigFile()
 	if err != nil {
@@ -185,10 +191,11 @@ func (b *Builder) Build(ctx context.Context, opts ...BuildOpt) (v1.Image, runtim
 
 	cfg := cfgFile.Config
 	cfg.Labels = make(map[string]string)
-
-	pkgBytes, err := encode(pkg)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, errConfigFile)
+	for k, v := range bOpts.annotations {
+		if strings.HasPrefix(k, AnnotationKey) {
+			return nil, nil, errors.Errorf(errFmtReservedAnnotation, k)
+		}
+		cfg.Labels[k] = v
 	}
 
 	pkgLayer, err := Layer(pkgBytes, StreamFile, PackageAnnotation, int64(pkgBytes.Len()), StreamFileMode, &cfg)
@@ -226,6 +233,130 @@ func (b *Builder) Build(ctx context.Context, opts ...BuildOpt) (v1.Image, runtim
 	return bOpts.base, meta, nil
 }
 
+// content returns the package's encoded contents and its metadata. If the
+// supplied cache isn't nil contents are read from the cache when the package's
+// files haven't changed since they were cached, and stored in the cache when
+// they have.
+func (b *Builder) content(ctx context.Context, cache PackageCache) (*bytes.Buffer, runtime.Object, error) {
+	var key string
+	if cache != nil {
+		k, err := b.contentKey(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errHashPackage)
+		}
+		key = k
+		if cache.Has(key) {
+			return cachedContent(cache, key)
+		}
+	}
+
+	// Get package YAML stream.
+	pkgReader, err := b.packageSource.Init(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errInitBackend)
+	}
+	defer func() { _ = pkgReader.Close() }()
+
+	pkg, err := b.packageParser.Parse(ctx, pkgReader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errParserPackage)
+	}
+
+	metas := pkg.GetMeta()
+	if len(metas) != 1 {
+		return nil, nil, errors.New(errNotExactlyOneMeta)
+	}
+
+	// TODO(hasheddan): make linter selection logic configurable.
+	meta := metas[0]
+	var linter parser.Linter
+	switch meta.GetObjectKind().GroupVersionKind().Kind {
+	case pkgmetav1.ConfigurationKind:
+		linter = NewConfigurationLinter()
+	case v1beta1.FunctionKind:
+		linter = NewFunctionLinter()
+	case pkgmetav1.ProviderKind:
+		linter = NewProviderLinter()
+	}
+	if err := linter.Lint(pkg); err != nil {
+		return nil, nil, errors.Wrap(err, errLintPackage)
+	}
+
+	pkgBytes, err := encode(pkg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errConfigFile)
+	}
+
+	if cache != nil {
+		if err := cache.Store(key, io.NopCloser(bytes.NewReader(pkgBytes.Bytes()))); err != nil {
+			return nil, nil, errors.Wrap(err, errStorePackage)
+		}
+	}
+
+	return pkgBytes, meta, nil
+}
+
+// contentKey returns a hash of the package's files. The package's contents are
+// encoded using the schemas of the Crossplane version that builds them, so the
+// version is part of the hash.
+func (b *Builder) contentKey(ctx context.Context) (string, error) {
+	r, err := b.packageSource.Init(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, errInitBackend)
+	}
+	defer func() { _ = r.Close() }()
+
+	h := sha256.New()
+	_, _ = h.Write([]byte(version.New().GetVersionString() + "\n"))
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cachedContent returns the cached contents of a package, and the package
+// metadata they contain.
+func cachedContent(cache PackageCache, key string) (*bytes.Buffer, runtime.Object, error) {
+	rc, err := cache.Get(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errGetCachedPackage)
+	}
+	defer func() { _ = rc.Close() }()
+
+	pkgBytes := new(bytes.Buffer)
+	if _, err := io.Copy(pkgBytes, rc); err != nil {
+		return nil, nil, errors.Wrap(err, errGetCachedPackage)
+	}
+
+	meta, err := decodeMeta(pkgBytes.Bytes())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errDecodeCachedMeta)
+	}
+	return pkgBytes, meta, nil
+}
+
+// decodeMeta decodes the package metadata from the supplied package contents.
+// encode always writes the metadata first, so only the first document of the
+// YAML stream is decoded.
+func decodeMeta(b []byte) (runtime.Object, error) {
+	s, err := BuildMetaScheme()
+	if err != nil {
+		return nil, err
+	}
+	r := k8syaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(b)))
+	for {
+		doc, err := r.Read()
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		obj, _, err := serializer.NewCodecFactory(s).UniversalDeserializer().Decode(doc, nil, nil)
+		return obj, err
+	}
+}
+
 // encode encodes a package as a YAML stream.  Does not check meta existence
 // or quantity i.e. it should be linted first to ensure that it is valid.
 func encode(pkg parser.Lintable) (*bytes.Buffer, error) {
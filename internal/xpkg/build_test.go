@@ -361,3 +361,43 @@ func yamlParser() (*parser.PackageParser, error) {
 
 	return parser.New(metaScheme, objScheme), nil
 }
+
+func TestBuildCache(t *testing.T) {
+	pkgp, _ := yamlParser()
+
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/ws/crossplane.yaml", testMeta, os.ModePerm)
+	_ = afero.WriteFile(fs, "/ws/crds/crd.yaml", testCRD, os.ModePerm)
+	pkgBe := parser.NewFsBackend(fs, parser.FsDir("/ws"), parser.FsFilters(parser.SkipDirs(), parser.SkipNotYAML(), parser.SkipEmpty()))
+	exBe := parser.NewFsBackend(fs, parser.FsDir("/ws/examples"))
+
+	cache := NewFsPackageCache("/cache", afero.NewMemMapFs())
+
+	img, meta, err := New(pkgBe, exBe, pkgp, examples.New()).Build(context.TODO(), WithCache(cache))
+	if err != nil {
+		t.Fatalf("Build(...): unexpected error building uncached package: %s", err)
+	}
+	want, _ := img.Digest()
+
+	// The package's files haven't changed, so it should be built from the
+	// cache without parsing them.
+	p := &MockParser{MockParse: NewMockParseFn(nil, errors.New("boom"))}
+	img, cachedMeta, err := New(pkgBe, exBe, p, examples.New()).Build(context.TODO(), WithCache(cache))
+	if err != nil {
+		t.Fatalf("Build(...): unexpected error building cached package: %s", err)
+	}
+	got, _ := img.Digest()
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Build(...): -want digest, +got digest:\n%s", diff)
+	}
+	if diff := cmp.Diff(meta, cachedMeta); diff != "" {
+		t.Errorf("Build(...): -want meta, +got meta:\n%s", diff)
+	}
+
+	// The package's files have changed, so it should be parsed again.
+	_ = afero.WriteFile(fs, "/ws/crds/another.yaml", testCRD, os.ModePerm)
+	if _, _, err := New(pkgBe, exBe, p, examples.New()).Build(context.TODO(), WithCache(cache)); err == nil {
+		t.Errorf("Build(...): expected an error parsing changed package files")
+	}
+}
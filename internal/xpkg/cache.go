@@ -31,7 +31,10 @@ const (
 	errGetNopCache = "cannot get content from a NopCache"
 )
 
-const cacheContentExt = ".gz"
+const (
+	cacheContentExt = ".gz"
+	cacheTempExt    = ".tmp"
+)
 
 // A PackageCache caches package content.
 type PackageCache interface {
@@ -76,11 +79,23 @@ func (c *FsPackageCache) Get(id string) (io.ReadCloser, error) {
 	return GzipReadCloser(f)
 }
 
-// Store saves the package contents to the cache.
+// Store saves the package contents to the cache. Contents are written to a
+// temporary file that is renamed into place once fully written, so an
+// interrupted Store never leaves partial contents in the cache.
 func (c *FsPackageCache) Store(id string, content io.ReadCloser) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	cf, err := c.fs.Create(BuildPath(c.dir, id, cacheContentExt))
+	path := BuildPath(c.dir, id, cacheContentExt)
+	tmp := path + cacheTempExt
+	if err := c.write(tmp, content); err != nil {
+		_ = c.fs.Remove(tmp)
+		return err
+	}
+	return c.fs.Rename(tmp, path)
+}
+
+func (c *FsPackageCache) write(path string, content io.Reader) error {
+	cf, err := c.fs.Create(path)
 	if err != nil {
 		return err
 	}
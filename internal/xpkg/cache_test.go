@@ -23,10 +23,12 @@ import (
 	"os"
 	"syscall"
 	"testing"
+	"testing/iotest"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
@@ -140,40 +142,70 @@ func TestGet(t *testing.T) {
 
 func TestStore(t *testing.T) {
 	fs := afero.NewMemMapFs()
+	errBoom := errors.New("boom")
 
 	type args struct {
-		cache PackageCache
-		id    string
+		cache   PackageCache
+		id      string
+		content io.Reader
+	}
+	type want struct {
+		err error
+		has bool
 	}
 	cases := map[string]struct {
 		reason string
 		args   args
-		want   error
+		want   want
 	}{
 		"Success": {
 			reason: "Should not return an error if package is created at path.",
 			args: args{
-				cache: NewFsPackageCache("/cache", fs),
-				id:    "exists-1234567",
+				cache:   NewFsPackageCache("/cache", fs),
+				id:      "exists-1234567",
+				content: new(bytes.Buffer),
+			},
+			want: want{
+				has: true,
 			},
 		},
 		"ErrFailedCreate": {
 			reason: "Should return an error if file creation fails.",
 			args: args{
-				cache: NewFsPackageCache("/cache", afero.NewReadOnlyFs(fs)),
-				id:    "exists-1234567",
+				cache:   NewFsPackageCache("/cache", afero.NewReadOnlyFs(fs)),
+				id:      "not-exist-1234567",
+				content: new(bytes.Buffer),
+			},
+			want: want{
+				err: syscall.EPERM,
+			},
+		},
+		"ErrInterrupted": {
+			reason: "Should return an error and not cache partial contents if reading the contents fails.",
+			args: args{
+				cache:   NewFsPackageCache("/cache", fs),
+				id:      "partial-1234567",
+				content: io.MultiReader(bytes.NewBufferString("partial"), iotest.ErrReader(errBoom)),
+			},
+			want: want{
+				err: errBoom,
 			},
-			want: syscall.EPERM,
 		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			err := tc.args.cache.Store(tc.args.id, io.NopCloser(new(bytes.Buffer)))
+			err := tc.args.cache.Store(tc.args.id, io.NopCloser(tc.args.content))
 
-			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nStore(...): -want err, +got err:\n%s", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.has, tc.args.cache.Has(tc.args.id)); diff != "" {
+				t.Errorf("\n%s\nHas(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if _, err := fs.Stat(BuildPath("/cache", tc.args.id, cacheContentExt) + cacheTempExt); !os.IsNotExist(err) {
+				t.Errorf("\n%s\nStore(...): temporary file was not removed", tc.reason)
+			}
 		})
 	}
 }
@@ -23,6 +23,9 @@ type ConfigStore interface {
 	PullSecretFor(ctx context.Context, image string) (imageConfig, pullSecret string, err error)
 	// ImageVerificationConfigFor returns the ImageConfig for a given image.
 	ImageVerificationConfigFor(ctx context.Context, image string) (imageConfig string, iv *v1beta1.ImageVerification, err error)
+	// ProxyFor returns the name of the selected image config and the proxy
+	// configuration for a given image.
+	ProxyFor(ctx context.Context, image string) (imageConfig string, proxy *v1beta1.RegistryProxy, err error)
 }
 
 // isValidConfig is a function that determines if an ImageConfig is valid while
@@ -94,6 +97,25 @@ func (s *ImageConfigStore) ImageVerificationConfigFor(ctx context.Context, image
 	return config.Name, config.Spec.Verification, nil
 }
 
+// ProxyFor returns the proxy configuration for a given image as well as the
+// name of the ImageConfig resource that contains it.
+func (s *ImageConfigStore) ProxyFor(ctx context.Context, image string) (imageConfig string, proxy *v1beta1.RegistryProxy, err error) {
+	config, err := s.bestMatch(ctx, image, func(c *v1beta1.ImageConfig) bool {
+		return c.Spec.Registry != nil && c.Spec.Registry.Proxy != nil
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, errFindBestMatch)
+	}
+
+	if config == nil {
+		// No ImageConfig with a proxy found for this image, this is not an
+		// error.
+		return "", nil, nil
+	}
+
+	return config.Name, config.Spec.Registry.Proxy, nil
+}
+
 // bestMatch finds the best matching ImageConfig for an image based on the
 // longest prefix match.
 func (s *ImageConfigStore) bestMatch(ctx context.Context, image string, valid isValidConfig) (*v1beta1.ImageConfig, error) {
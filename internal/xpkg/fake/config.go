@@ -13,6 +13,7 @@ var _ xpkg.ConfigStore = &MockConfigStore{}
 type MockConfigStore struct {
 	MockPullSecretFor              func(ctx context.Context, image string) (imageConfig string, pullSecret string, err error)
 	MockImageVerificationConfigFor func(ctx context.Context, image string) (imageConfig string, verificationConfig *v1beta1.ImageVerification, err error)
+	MockProxyFor                   func(ctx context.Context, image string) (imageConfig string, proxy *v1beta1.RegistryProxy, err error)
 }
 
 // PullSecretFor calls the underlying MockPullSecretFor.
@@ -25,6 +26,11 @@ func (s *MockConfigStore) ImageVerificationConfigFor(ctx context.Context, image
 	return s.MockImageVerificationConfigFor(ctx, image)
 }
 
+// ProxyFor calls the underlying MockProxyFor.
+func (s *MockConfigStore) ProxyFor(ctx context.Context, image string) (imageConfig string, proxy *v1beta1.RegistryProxy, err error) {
+	return s.MockProxyFor(ctx, image)
+}
+
 // NewMockConfigStorePullSecretForFn creates a new MockPullSecretFor function for MockConfigStore.
 func NewMockConfigStorePullSecretForFn(imageConfig, pullSecret string, err error) func(context.Context, string) (string, string, error) {
 	return func(context.Context, string) (string, string, error) {
@@ -38,3 +44,10 @@ func NewMockConfigStoreImageVerificationConfigForFn(imageConfig string, verifica
 		return imageConfig, verificationConfig, err
 	}
 }
+
+// NewMockConfigStoreProxyForFn creates a new MockProxyFor function for MockConfigStore.
+func NewMockConfigStoreProxyForFn(imageConfig string, proxy *v1beta1.RegistryProxy, err error) func(context.Context, string) (string, *v1beta1.RegistryProxy, error) {
+	return func(context.Context, string) (string, *v1beta1.RegistryProxy, error) {
+		return imageConfig, proxy, err
+	}
+}
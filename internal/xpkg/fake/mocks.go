@@ -115,3 +115,20 @@ func NewMockTagsFn(tags []string, err error) func() ([]string, error) {
 func (m *MockFetcher) Tags(_ context.Context, _ name.Reference, _ ...string) ([]string, error) {
 	return m.MockTags()
 }
+
+var _ xpkg.Pusher = &MockPusher{}
+
+// MockPusher is a mock pusher.
+type MockPusher struct {
+	MockPush func(ref name.Reference, img v1.Image) error
+}
+
+// NewMockPushFn creates a new MockPush function for MockPusher.
+func NewMockPushFn(err error) func(name.Reference, v1.Image) error {
+	return func(_ name.Reference, _ v1.Image) error { return err }
+}
+
+// Push calls the underlying MockPush.
+func (m *MockPusher) Push(_ context.Context, ref name.Reference, img v1.Image, _ ...string) error {
+	return m.MockPush(ref, img)
+}
@@ -21,7 +21,10 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"sync"
 
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -32,6 +35,16 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errGetProxyConfig     = "cannot get registry proxy configuration"
+	errNotHTTPTransport   = "Fetcher transport is not an HTTP transport"
+	errFmtProxyURLMissing = "ImageConfig %q configures an HTTP proxy without a URL"
+	errFmtParseProxyURL   = "cannot parse proxy URL configured by ImageConfig %q"
+	errFmtProxyMode       = "unsupported proxy mode %q configured by ImageConfig %q"
 )
 
 func init() { //nolint:gochecknoinits // See comment below.
@@ -57,6 +70,20 @@ type K8sFetcher struct {
 	serviceAccount string
 	transport      http.RoundTripper
 	userAgent      string
+
+	proxies ProxyConfigStore
+
+	// Transports that connect via a proxy configured by an ImageConfig,
+	// keyed by proxy URL, or by mode for direct connections.
+	mu      sync.Mutex
+	proxied map[string]http.RoundTripper
+}
+
+// A ProxyConfigStore returns the proxy configuration for an image.
+type ProxyConfigStore interface {
+	// ProxyFor returns the name of the selected image config and the proxy
+	// configuration for a given image.
+	ProxyFor(ctx context.Context, image string) (imageConfig string, proxy *v1beta1.RegistryProxy, err error)
 }
 
 // FetcherOpt can be used to add optional parameters to NewK8sFetcher.
@@ -67,7 +94,7 @@ func WithCustomCA(rootCAs *x509.CertPool) FetcherOpt {
 	return func(k *K8sFetcher) error {
 		t, ok := k.transport.(*http.Transport)
 		if !ok {
-			return errors.New("Fetcher transport is not an HTTP transport")
+			return errors.New(errNotHTTPTransport)
 		}
 
 		t.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
@@ -106,6 +133,16 @@ func WithServiceAccount(sa string) FetcherOpt {
 	}
 }
 
+// WithRegistryProxies is a FetcherOpt that connects to each registry using the
+// proxy configuration returned by the supplied store. Images without proxy
+// configuration use the proxy configured using environment variables.
+func WithRegistryProxies(s ProxyConfigStore) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.proxies = s
+		return nil
+	}
+}
+
 // NewK8sFetcher creates a new K8sFetcher.
 func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher, error) {
 	dt, ok := remote.DefaultTransport.(*http.Transport)
@@ -115,6 +152,7 @@ func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher
 	k := &K8sFetcher{
 		client:    client,
 		transport: dt.Clone(),
+		proxied:   make(map[string]http.RoundTripper),
 	}
 
 	for _, o := range opts {
@@ -136,9 +174,13 @@ func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...s
 	if err != nil {
 		return nil, err
 	}
+	t, err := i.transportFor(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
 	return remote.Image(ref,
 		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
+		remote.WithTransport(t),
 		remote.WithContext(ctx),
 		remote.WithUserAgent(i.userAgent),
 	)
@@ -154,16 +196,20 @@ func (i *K8sFetcher) Head(ctx context.Context, ref name.Reference, secrets ...st
 	if err != nil {
 		return nil, err
 	}
+	t, err := i.transportFor(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
 	d, err := remote.Head(ref,
 		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
+		remote.WithTransport(t),
 		remote.WithContext(ctx),
 		remote.WithUserAgent(i.userAgent),
 	)
 	if err != nil || d == nil {
 		rd, gErr := remote.Get(ref,
 			remote.WithAuthFromKeychain(auth),
-			remote.WithTransport(i.transport),
+			remote.WithTransport(t),
 			remote.WithContext(ctx),
 			remote.WithUserAgent(i.userAgent),
 		)
@@ -185,14 +231,72 @@ func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...st
 	if err != nil {
 		return nil, err
 	}
+	t, err := i.transportFor(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
 	return remote.List(ref.Context(),
 		remote.WithAuthFromKeychain(auth),
-		remote.WithTransport(i.transport),
+		remote.WithTransport(t),
 		remote.WithContext(ctx),
 		remote.WithUserAgent(i.userAgent),
 	)
 }
 
+// transportFor returns the transport that should be used to connect to the
+// registry serving the supplied image.
+func (i *K8sFetcher) transportFor(ctx context.Context, ref name.Reference) (http.RoundTripper, error) {
+	if i.proxies == nil {
+		return i.transport, nil
+	}
+	ic, p, err := i.proxies.ProxyFor(ctx, ref.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, errGetProxyConfig)
+	}
+	if p == nil {
+		return i.transport, nil
+	}
+
+	key := string(p.Mode)
+	var proxy func(*http.Request) (*url.URL, error)
+	switch p.Mode {
+	case v1beta1.RegistryProxyModeDirect:
+	case v1beta1.RegistryProxyModeHTTP:
+		if p.URL == nil {
+			return nil, errors.Errorf(errFmtProxyURLMissing, ic)
+		}
+		u, err := url.Parse(*p.URL)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseProxyURL, ic)
+		}
+		proxy = http.ProxyURL(u)
+		key = u.String()
+	default:
+		return nil, errors.Errorf(errFmtProxyMode, p.Mode, ic)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if t, ok := i.proxied[key]; ok {
+		return t, nil
+	}
+	dt, ok := i.transport.(*http.Transport)
+	if !ok {
+		return nil, errors.New(errNotHTTPTransport)
+	}
+	t := dt.Clone()
+	t.Proxy = proxy
+	i.proxied[key] = t
+	return t, nil
+}
+
+// IsProxyConnectError returns true if the supplied error indicates that an
+// HTTP proxy couldn't be reached while connecting to a registry.
+func IsProxyConnectError(err error) bool {
+	oe := &net.OpError{}
+	return errors.As(err, &oe) && oe.Op == "proxyconnect"
+}
+
 // NopFetcher always returns an empty image and never returns error.
 type NopFetcher struct{}
 
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+type proxyConfigStoreFn func(ctx context.Context, image string) (string, *v1beta1.RegistryProxy, error)
+
+func (fn proxyConfigStoreFn) ProxyFor(ctx context.Context, image string) (string, *v1beta1.RegistryProxy, error) {
+	return fn(ctx, image)
+}
+
+func TestTransportFor(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	proxyFor := func(p *v1beta1.RegistryProxy) ProxyConfigStore {
+		return proxyConfigStoreFn(func(_ context.Context, _ string) (string, *v1beta1.RegistryProxy, error) {
+			if p == nil {
+				return "", nil, nil
+			}
+			return "cool-config", p, nil
+		})
+	}
+
+	type want struct {
+		// Whether the fetcher's default transport should be used.
+		dflt bool
+		// The proxy the transport should use, if any.
+		proxy string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		proxies ProxyConfigStore
+		want    want
+	}{
+		"NoProxyConfigStore": {
+			reason: "We should use the default transport if no proxy config store is configured.",
+			want: want{
+				dflt: true,
+			},
+		},
+		"NoMatchingImageConfig": {
+			reason:  "We should use the default transport if no ImageConfig configures a proxy for the image.",
+			proxies: proxyFor(nil),
+			want: want{
+				dflt: true,
+			},
+		},
+		"Direct": {
+			reason:  "We should use a transport without a proxy if an ImageConfig configures a direct connection.",
+			proxies: proxyFor(&v1beta1.RegistryProxy{Mode: v1beta1.RegistryProxyModeDirect}),
+			want:    want{},
+		},
+		"HTTP": {
+			reason:  "We should use a transport with the proxy configured by an ImageConfig.",
+			proxies: proxyFor(&v1beta1.RegistryProxy{Mode: v1beta1.RegistryProxyModeHTTP, URL: ptr.To("http://proxy.example.org:3128")}),
+			want: want{
+				proxy: "http://proxy.example.org:3128",
+			},
+		},
+		"HTTPMissingURL": {
+			reason:  "We should return an error if an ImageConfig configures an HTTP proxy without a URL.",
+			proxies: proxyFor(&v1beta1.RegistryProxy{Mode: v1beta1.RegistryProxyModeHTTP}),
+			want: want{
+				err: errors.Errorf(errFmtProxyURLMissing, "cool-config"),
+			},
+		},
+		"ProxyConfigStoreError": {
+			reason: "We should return any error encountered getting the proxy configuration.",
+			proxies: proxyConfigStoreFn(func(_ context.Context, _ string) (string, *v1beta1.RegistryProxy, error) {
+				return "", nil, errBoom
+			}),
+			want: want{
+				err: errors.Wrap(errBoom, errGetProxyConfig),
+			},
+		},
+	}
+
+	ref := name.MustParseReference("registry.example.org/cool/package:v1.0.0")
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewK8sFetcher(nil, WithRegistryProxies(tc.proxies))
+			if err != nil {
+				t.Fatalf("NewK8sFetcher(...): %v", err)
+			}
+
+			got, err := f.transportFor(context.Background(), ref)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ntransportFor(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.want.dflt, got == f.transport); diff != "" {
+				t.Errorf("\n%s\ntransportFor(...): -want default transport, +got default transport:\n%s", tc.reason, diff)
+			}
+			if tc.want.dflt {
+				return
+			}
+
+			again, _ := f.transportFor(context.Background(), ref)
+			if got != again {
+				t.Errorf("\n%s\ntransportFor(...): want the same transport to be reused", tc.reason)
+			}
+
+			proxy := ""
+			if fn := got.(*http.Transport).Proxy; fn != nil {
+				req, _ := http.NewRequest(http.MethodGet, "https://registry.example.org/v2/", nil)
+				u, _ := fn(req)
+				proxy = u.String()
+			}
+			if diff := cmp.Diff(tc.want.proxy, proxy); diff != "" {
+				t.Errorf("\n%s\ntransportFor(...): -want proxy, +got proxy:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsProxyConnectError(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   bool
+	}{
+		"ProxyConnect": {
+			reason: "A wrapped proxyconnect error should be a proxy connect error.",
+			err:    errors.Wrap(&net.OpError{Op: "proxyconnect", Net: "tcp", Err: errors.New("connection refused")}, "cannot fetch package"),
+			want:   true,
+		},
+		"Dial": {
+			reason: "An error dialing the registry itself shouldn't be a proxy connect error.",
+			err:    &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			want:   false,
+		},
+		"Other": {
+			reason: "An unrelated error shouldn't be a proxy connect error.",
+			err:    errors.New("boom"),
+			want:   false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsProxyConnectError(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsProxyConnectError(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
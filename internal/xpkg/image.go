@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+)
+
+const (
+	errGetManifest     = "failed to get package OCI manifest"
+	errNoBaseLayer     = "package has no layer annotated as the package base layer"
+	errFmtGetBaseLayer = "failed to get package base layer %s"
+	errReadBaseLayer   = "failed to read package base layer"
+	errNoPackageStream = "failed to find " + StreamFile + " in package base layer"
+	errBuildMetaScheme = "failed to build package meta scheme"
+	errParseContents   = "failed to parse package contents"
+)
+
+// ReadPackage reads and parses the package.yaml file in the supplied image's
+// base layer. It doesn't read any other layer.
+func ReadPackage(img v1.Image) (*parser.Package, error) {
+	l, err := BaseLayer(img)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := l.Uncompressed()
+	if err != nil {
+		return nil, errors.Wrap(err, errReadBaseLayer)
+	}
+	defer rc.Close() //nolint:errcheck // Only reading.
+
+	t := tar.NewReader(rc)
+	for {
+		h, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New(errNoPackageStream)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errReadBaseLayer)
+		}
+		if h.Name == StreamFile {
+			break
+		}
+	}
+
+	ms, err := BuildMetaScheme()
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildMetaScheme)
+	}
+	obs, err := BuildObjectScheme()
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildObjectScheme)
+	}
+
+	pkg, err := parser.New(ms, obs).Parse(context.Background(), io.NopCloser(t))
+	return pkg, errors.Wrap(err, errParseContents)
+}
+
+// BaseLayer returns the supplied image's base layer - the layer that contains
+// the package.yaml file. Package files store layer annotations as labels in
+// their OCI config file, while images in a registry annotate their layers
+// directly, so we check both.
+func BaseLayer(img v1.Image) (v1.Layer, error) {
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrap(err, errConfigFile)
+	}
+	for label, a := range cfg.Config.Labels {
+		d, ok := strings.CutPrefix(label, AnnotationKey+":")
+		if !ok || a != PackageAnnotation {
+			continue
+		}
+		h, err := v1.NewHash(d)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetBaseLayer, d)
+		}
+		l, err := img.LayerByDigest(h)
+		return l, errors.Wrapf(err, errFmtGetBaseLayer, d)
+	}
+
+	m, err := img.Manifest()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetManifest)
+	}
+	for _, desc := range m.Layers {
+		if desc.Annotations[AnnotationKey] != PackageAnnotation {
+			continue
+		}
+		l, err := img.LayerByDigest(desc.Digest)
+		return l, errors.Wrapf(err, errFmtGetBaseLayer, desc.Digest)
+	}
+
+	return nil, errors.New(errNoBaseLayer)
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
@@ -33,8 +34,9 @@ import (
 
 // Error strings.
 const (
-	errLayer  = "cannot get image layers"
-	errDigest = "cannot get image digest"
+	errLayer            = "cannot get image layers"
+	errDigest           = "cannot get image digest"
+	errAnnotateManifest = "cannot annotate image manifest"
 )
 
 // Layer creates a v1.Layer that represents the layer contents for the xpkg and
@@ -156,3 +158,32 @@ func AnnotateLayers(i v1.Image) (v1.Image, error) {
 
 	return mutate.ConfigFile(img, cfgFile)
 }
+
+// AnnotateManifest propagates labels from the supplied image's config file to
+// annotations on its manifest, except for labels that AnnotateLayers
+// propagates to its layers.
+func AnnotateManifest(i v1.Image) (v1.Image, error) {
+	cfgFile, err := i.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrap(err, errConfigFile)
+	}
+
+	anns := make(map[string]string)
+	for k, v := range cfgFile.Config.Labels {
+		if strings.HasPrefix(k, AnnotationKey+":") {
+			continue
+		}
+		anns[k] = v
+	}
+
+	// we didn't find any annotations, return original image
+	if len(anns) == 0 {
+		return i, nil
+	}
+
+	img, ok := mutate.Annotations(i, anns).(v1.Image)
+	if !ok {
+		return nil, errors.New(errAnnotateManifest)
+	}
+	return img, nil
+}
@@ -17,17 +17,19 @@ limitations under the License.
 package xpkg
 
 import (
-	"github.com/Masterminds/semver"
 	admv1 "k8s.io/api/admissionregistration/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/parser"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	pkgv1beta1 "github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/version"
 )
 
@@ -43,13 +45,70 @@ const (
 	errNotValidatingWebhookConfiguration = "object is not an ValidatingWebhookConfiguration"
 	errNotComposition                    = "object is not a Composition"
 	errBadConstraints                    = "package version constraints are poorly formatted"
-	errFmtCrossplaneIncompatible         = "package is not compatible with Crossplane version (%s)"
+	errFmtCrossplaneIncompatible         = "package requires Crossplane version %q, but this is Crossplane version %q"
+	errFmtCheckCrossplaneCompatible      = "cannot check whether Crossplane version %q satisfies the package's required Crossplane version %q"
+	errFmtDependencyNoType               = "dependsOn[%d] must set one of provider, configuration, or function, or an apiVersion, kind, and package"
+	errFmtDependencyMultipleTypes        = "dependsOn[%d] (%q) must set only one of provider, configuration, or function"
+	errFmtDependencyIncompleteGVK        = "dependsOn[%d] (%q) must set all of apiVersion, kind, and package"
+	errFmtDependencyUnknownKind          = "dependsOn[%d] (%q) has apiVersion %q and kind %q, which isn't a kind of package"
+	errFmtDependencyTypeMismatch         = "dependsOn[%d] (%q) has kind %q, but sets the %s field"
+	errInstallHookNoCommand              = "controller.installHook must set a command, because the controller image's entrypoint runs the controller, which never exits"
 )
 
+// An AggregatingLinter lints packages. Unlike a PackageLinter it doesn't stop
+// at the first object that fails linting. Instead it returns an error that
+// describes every object that failed linting. Package linter functions are
+// still run first, and stop linting if they fail.
+type AggregatingLinter struct {
+	pre       []parser.PackageLinterFn
+	perMeta   []parser.ObjectLinterFn
+	perObject []parser.ObjectLinterFn
+}
+
+// NewAggregatingLinter returns a linter that reports every object in a package
+// that fails linting.
+func NewAggregatingLinter(pre []parser.PackageLinterFn, perMeta, perObject []parser.ObjectLinterFn) *AggregatingLinter {
+	return &AggregatingLinter{
+		pre:       pre,
+		perMeta:   perMeta,
+		perObject: perObject,
+	}
+}
+
+// Lint the supplied package.
+func (l *AggregatingLinter) Lint(pkg parser.Lintable) error {
+	for _, fn := range l.pre {
+		if err := fn(pkg); err != nil {
+			return err
+		}
+	}
+	errs := make([]error, 0)
+	for i, o := range pkg.GetMeta() {
+		for _, fn := range l.perMeta {
+			if err := fn(o); err != nil {
+				errs = append(errs, errors.Wrapf(err, errFmtLintObject, describeObject("meta object", i+1, o)))
+				break
+			}
+		}
+	}
+	for i, o := range pkg.GetObjects() {
+		for _, fn := range l.perObject {
+			if err := fn(o); err != nil {
+				errs = append(errs, errors.Wrapf(err, errFmtLintObject, describeObject("object", i+1, o)))
+				break
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
 // NewProviderLinter is a convenience function for creating a package linter for
 // providers.
 func NewProviderLinter() parser.Linter {
-	return parser.NewPackageLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsProvider, PackageValidSemver),
+	return NewAggregatingLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsProvider, PackageValidSemver, PackageValidDependencies, ProviderValidInstallHook),
 		parser.ObjectLinterFns(parser.Or(
 			IsCRD,
 			IsValidatingWebhookConfiguration,
@@ -60,13 +119,13 @@ func NewProviderLinter() parser.Linter {
 // NewConfigurationLinter is a convenience function for creating a package linter for
 // configurations.
 func NewConfigurationLinter() parser.Linter {
-	return parser.NewPackageLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsConfiguration, PackageValidSemver), parser.ObjectLinterFns(parser.Or(IsXRD, IsComposition)))
+	return NewAggregatingLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsConfiguration, PackageValidSemver, PackageValidDependencies, ConfigurationDependencyLockValid), parser.ObjectLinterFns(parser.Or(IsXRD, IsComposition)))
 }
 
 // NewFunctionLinter is a convenience function for creating a package linter for
 // functions.
 func NewFunctionLinter() parser.Linter {
-	return parser.NewPackageLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsFunction, PackageValidSemver), parser.ObjectLinterFns())
+	return NewAggregatingLinter(parser.PackageLinterFns(OneMeta), parser.ObjectLinterFns(IsFunction, PackageValidSemver, PackageValidDependencies), parser.ObjectLinterFns())
 }
 
 // OneMeta checks that there is only one meta object in the package.
@@ -116,12 +175,13 @@ func PackageCrossplaneCompatible(v version.Operations) parser.ObjectLinterFn {
 		if p.GetCrossplaneConstraints() == nil {
 			return nil
 		}
-		in, err := v.InConstraints(p.GetCrossplaneConstraints().Version)
+		required := p.GetCrossplaneConstraints().Version
+		in, err := v.InConstraints(required)
 		if err != nil {
-			return errors.Wrapf(err, errFmtCrossplaneIncompatible, v.GetVersionString())
+			return errors.Wrapf(err, errFmtCheckCrossplaneCompatible, v.GetVersionString(), required)
 		}
 		if !in {
-			return errors.Errorf(errFmtCrossplaneIncompatible, v.GetVersionString())
+			return errors.Errorf(errFmtCrossplaneIncompatible, required, v.GetVersionString())
 		}
 		return nil
 	}
@@ -137,12 +197,84 @@ func PackageValidSemver(o runtime.Object) error {
 	if p.GetCrossplaneConstraints() == nil {
 		return nil
 	}
-	if _, err := semver.NewConstraint(p.GetCrossplaneConstraints().Version); err != nil {
+	if _, err := version.NewConstraint(p.GetCrossplaneConstraints().Version); err != nil {
 		return errors.Wrap(err, errBadConstraints)
 	}
 	return nil
 }
 
+// PackageValidDependencies checks that each of the package's dependencies
+// specifies exactly one kind of package, either using the provider,
+// configuration, or function field, or using an apiVersion, kind, and package.
+func PackageValidDependencies(o runtime.Object) error {
+	p, ok := TryConvertToPkg(o, &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+	if !ok {
+		return errors.New(errNotMeta)
+	}
+
+	// The kinds of package a dependency can be, and the field that may be
+	// used to specify a dependency of each kind instead of a GVK.
+	kinds := map[schema.GroupVersionKind]string{
+		pkgv1.ProviderGroupVersionKind:      "provider",
+		pkgv1.ConfigurationGroupVersionKind: "configuration",
+		pkgv1.FunctionGroupVersionKind:      "function",
+		pkgv1beta1.FunctionGroupVersionKind: "function",
+	}
+
+	for i, d := range p.GetDependencies() {
+		set := make([]string, 0, 3)
+		if d.Provider != nil {
+			set = append(set, "provider")
+		}
+		if d.Configuration != nil {
+			set = append(set, "configuration")
+		}
+		if d.Function != nil {
+			set = append(set, "function")
+		}
+		pkg := DependencyPackage(d)
+
+		if len(set) > 1 {
+			return errors.Errorf(errFmtDependencyMultipleTypes, i, pkg)
+		}
+
+		if d.APIVersion == nil && d.Kind == nil {
+			if len(set) == 0 {
+				return errors.Errorf(errFmtDependencyNoType, i)
+			}
+			continue
+		}
+
+		if d.APIVersion == nil || d.Kind == nil || (d.Package == nil && len(set) == 0) {
+			return errors.Errorf(errFmtDependencyIncompleteGVK, i, pkg)
+		}
+
+		field, ok := kinds[schema.FromAPIVersionAndKind(*d.APIVersion, *d.Kind)]
+		if !ok {
+			return errors.Errorf(errFmtDependencyUnknownKind, i, pkg, *d.APIVersion, *d.Kind)
+		}
+		if len(set) == 1 && set[0] != field {
+			return errors.Errorf(errFmtDependencyTypeMismatch, i, pkg, *d.Kind, set[0])
+		}
+	}
+
+	return nil
+}
+
+// ProviderValidInstallHook checks that a Provider's install hook, if any, sets
+// a command to run.
+func ProviderValidInstallHook(o runtime.Object) error {
+	po, _ := TryConvert(o, &pkgmetav1.Provider{})
+	p, ok := po.(*pkgmetav1.Provider)
+	if !ok {
+		return errors.New(errNotMetaProvider)
+	}
+	if h := p.Spec.Controller.InstallHook; h != nil && len(h.Command) == 0 {
+		return errors.New(errInstallHookNoCommand)
+	}
+	return nil
+}
+
 // IsCRD checks that an object is a CustomResourceDefinition.
 func IsCRD(o runtime.Object) error {
 	switch o.(type) {
@@ -26,6 +26,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -308,7 +309,7 @@ func TestPackageCrossplaneCompatible(t *testing.T) {
 					MockGetVersionString: fake.NewMockGetVersionStringFn("v0.12.0"),
 				},
 			},
-			err: errors.Wrapf(errBoom, errFmtCrossplaneIncompatible, "v0.12.0"),
+			err: errors.Wrapf(errBoom, errFmtCheckCrossplaneCompatible, "v0.12.0", crossplaneConstraint),
 		},
 		"ErrOutsideConstraints": {
 			reason: "Should return error if Crossplane version outside constraints.",
@@ -327,7 +328,7 @@ func TestPackageCrossplaneCompatible(t *testing.T) {
 					MockGetVersionString: fake.NewMockGetVersionStringFn("v0.12.0"),
 				},
 			},
-			err: errors.Errorf(errFmtCrossplaneIncompatible, "v0.12.0"),
+			err: errors.Errorf(errFmtCrossplaneIncompatible, crossplaneConstraint, "v0.12.0"),
 		},
 		"ErrNotMeta": {
 			reason: "Should return error if object is not a meta package type.",
@@ -351,6 +352,7 @@ func TestPackageCrossplaneCompatible(t *testing.T) {
 
 func TestPackageValidSemver(t *testing.T) {
 	validConstraint := ">v0.13.0"
+	validRange := ">=v1.14.0 <v2.0.0"
 	invalidConstraint := ">a0.13.0"
 
 	type args struct {
@@ -375,6 +377,20 @@ func TestPackageValidSemver(t *testing.T) {
 				},
 			},
 		},
+		"ValidRange": {
+			reason: "Should not return error if constraints are a valid space separated range.",
+			args: args{
+				obj: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							Crossplane: &pkgmetav1.CrossplaneConstraints{
+								Version: validRange,
+							},
+						},
+					},
+				},
+			},
+		},
 		"ErrInvalidConstraints": {
 			reason: "Should return error if constraints are invalid.",
 			args: args{
@@ -403,6 +419,157 @@ func TestPackageValidSemver(t *testing.T) {
 	}
 }
 
+func TestPackageValidDependencies(t *testing.T) {
+	conf := func(deps ...pkgmetav1.Dependency) *pkgmetav1.Configuration {
+		return &pkgmetav1.Configuration{Spec: pkgmetav1.ConfigurationSpec{MetaSpec: pkgmetav1.MetaSpec{DependsOn: deps}}}
+	}
+
+	type args struct {
+		obj runtime.Object
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		err    error
+	}{
+		"ValidTypeField": {
+			reason: "Should not return error if a dependency sets one type field.",
+			args: args{
+				obj: conf(pkgmetav1.Dependency{Provider: ptr.To("xpkg.crossplane.io/crossplane-contrib/provider-nop"), Version: ">=v0.1.0"}),
+			},
+		},
+		"ValidGVK": {
+			reason: "Should not return error if a dependency sets the apiVersion and kind of a package.",
+			args: args{
+				obj: conf(pkgmetav1.Dependency{
+					APIVersion: ptr.To("pkg.crossplane.io/v1"),
+					Kind:       ptr.To("Function"),
+					Package:    ptr.To("xpkg.crossplane.io/crossplane-contrib/function-auto-ready"),
+					Version:    ">=v0.1.0",
+				}),
+			},
+		},
+		"ErrNoType": {
+			reason: "Should return error if a dependency doesn't specify what kind of package it is.",
+			args: args{
+				obj: conf(pkgmetav1.Dependency{Package: ptr.To("xpkg.crossplane.io/crossplane-contrib/provider-nop"), Version: ">=v0.1.0"}),
+			},
+			err: errors.Errorf(errFmtDependencyNoType, 0),
+		},
+		"ErrMultipleTypes": {
+			reason: "Should return error if a dependency sets more than one type field.",
+			args: args{
+				obj: conf(
+					pkgmetav1.Dependency{Provider: ptr.To("xpkg.crossplane.io/crossplane-contrib/provider-nop"), Version: ">=v0.1.0"},
+					pkgmetav1.Dependency{
+						Provider: ptr.To("xpkg.crossplane.io/crossplane-contrib/function-auto-ready"),
+						Function: ptr.To("xpkg.crossplane.io/crossplane-contrib/function-auto-ready"),
+						Version:  ">=v0.1.0",
+					},
+				),
+			},
+			err: errors.Errorf(errFmtDependencyMultipleTypes, 1, "xpkg.crossplane.io/crossplane-contrib/function-auto-ready"),
+		},
+		"ErrIncompleteGVK": {
+			reason: "Should return error if a dependency sets a kind but no apiVersion.",
+			args: args{
+				obj: conf(pkgmetav1.Dependency{
+					Kind:    ptr.To("Provider"),
+					Package: ptr.To("xpkg.crossplane.io/crossplane-contrib/provider-nop"),
+					Version: ">=v0.1.0",
+				}),
+			},
+			err: errors.Errorf(errFmtDependencyIncompleteGVK, 0, "xpkg.crossplane.io/crossplane-contrib/provider-nop"),
+		},
+		"ErrUnknownKind": {
+			reason: "Should return error if a dependency's apiVersion and kind aren't a kind of package.",
+			args: args{
+				obj: conf(pkgmetav1.Dependency{
+					APIVersion: ptr.To("meta.pkg.crossplane.io/v1"),
+					Kind:       ptr.To("Provider"),
+					Package:    ptr.To("xpkg.crossplane.io/crossplane-contrib/provider-nop"),
+					Version:    ">=v0.1.0",
+				}),
+			},
+			err: errors.Errorf(errFmtDependencyUnknownKind, 0, "xpkg.crossplane.io/crossplane-contrib/provider-nop", "meta.pkg.crossplane.io/v1", "Provider"),
+		},
+		"ErrTypeMismatch": {
+			reason: "Should return error if a dependency's kind doesn't match the type field it sets.",
+			args: args{
+				obj: conf(pkgmetav1.Dependency{
+					APIVersion: ptr.To("pkg.crossplane.io/v1"),
+					Kind:       ptr.To("Function"),
+					Provider:   ptr.To("xpkg.crossplane.io/crossplane-contrib/function-auto-ready"),
+					Version:    ">=v0.1.0",
+				}),
+			},
+			err: errors.Errorf(errFmtDependencyTypeMismatch, 0, "xpkg.crossplane.io/crossplane-contrib/function-auto-ready", "Function", "provider"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := PackageValidDependencies(tc.args.obj)
+
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPackageValidDependencies(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderValidInstallHook(t *testing.T) {
+	prov := func(hook *pkgmetav1.InstallHook) *pkgmetav1.Provider {
+		return &pkgmetav1.Provider{Spec: pkgmetav1.ProviderSpec{Controller: pkgmetav1.ControllerSpec{InstallHook: hook}}}
+	}
+
+	type args struct {
+		obj runtime.Object
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		err    error
+	}{
+		"NoInstallHook": {
+			reason: "Should not return error if the provider doesn't declare an install hook.",
+			args: args{
+				obj: prov(nil),
+			},
+		},
+		"ValidInstallHook": {
+			reason: "Should not return error if the install hook sets a command.",
+			args: args{
+				obj: prov(&pkgmetav1.InstallHook{Command: []string{"/usr/local/bin/provider", "register"}}),
+			},
+		},
+		"ErrNoCommand": {
+			reason: "Should return error if the install hook doesn't set a command, since it would run the controller.",
+			args: args{
+				obj: prov(&pkgmetav1.InstallHook{Args: []string{"register"}}),
+			},
+			err: errors.New(errInstallHookNoCommand),
+		},
+		"ErrNotProvider": {
+			reason: "Should return error if the object isn't a provider.",
+			args: args{
+				obj: &pkgmetav1.Configuration{},
+			},
+			err: errors.New(errNotMetaProvider),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ProviderValidInstallHook(tc.args.obj)
+
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nProviderValidInstallHook(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestIsCRD(t *testing.T) {
 	cases := map[string]struct {
 		reason string
@@ -490,3 +657,46 @@ func TestIsComposition(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregatingLinter(t *testing.T) {
+	validR := bytes.NewReader(bytes.Join([][]byte{v1ConfBytes, v1XRDBytes, v1CompBytes}, []byte("\n---\n")))
+	valid, _ := p.Parse(context.TODO(), io.NopCloser(validR))
+	invalidR := bytes.NewReader(bytes.Join([][]byte{v1ConfBytes, v1CRDBytes, v1CompBytes, v1CRDBytes}, []byte("\n---\n")))
+	invalid, _ := p.Parse(context.TODO(), io.NopCloser(invalidR))
+	noMetaR := bytes.NewReader(bytes.Join([][]byte{v1CRDBytes, v1CompBytes}, []byte("\n---\n")))
+	noMeta, _ := p.Parse(context.TODO(), io.NopCloser(noMetaR))
+
+	cases := map[string]struct {
+		reason string
+		pkg    *parser.Package
+		err    error
+	}{
+		"Successful": {
+			reason: "Should not return error if all objects are valid.",
+			pkg:    valid,
+		},
+		"ErrPackageLinter": {
+			reason: "Should return only the package linter error if the package is invalid.",
+			pkg:    noMeta,
+			err:    errors.New(errNotExactlyOneMeta),
+		},
+		"ErrInvalidObjects": {
+			reason: "Should return an error describing every invalid object.",
+			pkg:    invalid,
+			err: errors.Join(
+				errors.Wrapf(errors.Errorf("object did not pass any of the linters with following errors: %s, %s", errNotXRD, errNotComposition), errFmtLintObject, `object 1 (CustomResourceDefinition "test")`),
+				errors.Wrapf(errors.Errorf("object did not pass any of the linters with following errors: %s, %s", errNotXRD, errNotComposition), errFmtLintObject, `object 3 (CustomResourceDefinition "test")`),
+			),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewConfigurationLinter().Lint(tc.pkg)
+
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nLint(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"github.com/Masterminds/semver"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+const (
+	errFmtLockedDependencyInvalid   = "locked dependency %q is invalid"
+	errFmtLockedDependencyDuplicate = "dependency %q is locked more than once"
+	errFmtDependencyNotLocked       = "dependency %q isn't locked - regenerate the dependency lock"
+	errFmtLockViolatesConstraint    = "dependency %q is locked to %s, which doesn't satisfy its constraint %q - regenerate the dependency lock"
+	errFmtInvalidConstraint         = "dependency %q has invalid version constraint %q"
+)
+
+// DependencyPackage returns the package OCI reference of the supplied
+// dependency, regardless of how the dependency specifies it.
+func DependencyPackage(d pkgmetav1.Dependency) string {
+	switch {
+	case d.Package != nil:
+		return *d.Package
+	case d.Provider != nil:
+		return *d.Provider
+	case d.Configuration != nil:
+		return *d.Configuration
+	case d.Function != nil:
+		return *d.Function
+	}
+	return ""
+}
+
+// ConfigurationDependencyLockValid checks that a Configuration's dependency
+// lock, if any, is well formed and agrees with the Configuration's declared
+// dependencies. Every declared dependency must be locked to a package that
+// satisfies its version constraint.
+func ConfigurationDependencyLockValid(o runtime.Object) error {
+	po, _ := TryConvert(o, &pkgmetav1.Configuration{})
+	c, ok := po.(*pkgmetav1.Configuration)
+	if !ok {
+		return errors.New(errNotMetaConfiguration)
+	}
+	return ValidateDependencyLock(c.GetDependencies(), c.Spec.DependencyLock)
+}
+
+// ValidateDependencyLock validates the supplied dependency lock against the
+// supplied declared dependencies. An empty lock is valid.
+func ValidateDependencyLock(deps []pkgmetav1.Dependency, lock []pkgmetav1.LockedDependency) error {
+	if len(lock) == 0 {
+		return nil
+	}
+
+	locked := make(map[string]pkgmetav1.LockedDependency, len(lock))
+	for _, l := range lock {
+		if l.APIVersion == "" || l.Kind == "" || l.Package == "" {
+			return errors.Errorf(errFmtLockedDependencyInvalid, l.Package)
+		}
+		if _, err := semver.NewVersion(l.Version); err != nil {
+			return errors.Wrapf(err, errFmtLockedDependencyInvalid, l.Package)
+		}
+		if _, err := conregv1.NewHash(l.Digest); err != nil {
+			return errors.Wrapf(err, errFmtLockedDependencyInvalid, l.Package)
+		}
+		if _, dup := locked[l.Package]; dup {
+			return errors.Errorf(errFmtLockedDependencyDuplicate, l.Package)
+		}
+		locked[l.Package] = l
+	}
+
+	for _, d := range deps {
+		pkg := DependencyPackage(d)
+		l, ok := locked[pkg]
+		if !ok {
+			return errors.Errorf(errFmtDependencyNotLocked, pkg)
+		}
+
+		// A dependency may be constrained to a digest rather than a
+		// semantic version range.
+		if h, err := conregv1.NewHash(d.Version); err == nil {
+			if h.String() != l.Digest {
+				return errors.Errorf(errFmtLockViolatesConstraint, pkg, l.Digest, d.Version)
+			}
+			continue
+		}
+
+		c, err := semver.NewConstraint(d.Version)
+		if err != nil {
+			return errors.Wrapf(err, errFmtInvalidConstraint, pkg, d.Version)
+		}
+		if !c.Check(semver.MustParse(l.Version)) {
+			return errors.Errorf(errFmtLockViolatesConstraint, pkg, l.Version, d.Version)
+		}
+	}
+
+	return nil
+}
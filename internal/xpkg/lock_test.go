@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-cmp/cmp"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+func TestValidateDependencyLock(t *testing.T) {
+	digest := "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+	otherDigest := "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443905"
+
+	_, errInvalidVersion := semver.NewVersion(">=v0.2.0")
+	_, errInvalidDigest := conregv1.NewHash("latest")
+
+	locked := func(pkg, version, digest string) pkgmetav1.LockedDependency {
+		return pkgmetav1.LockedDependency{
+			APIVersion: "pkg.crossplane.io/v1",
+			Kind:       "Provider",
+			Package:    pkg,
+			Version:    version,
+			Digest:     digest,
+		}
+	}
+
+	type args struct {
+		deps []pkgmetav1.Dependency
+		lock []pkgmetav1.LockedDependency
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoLock": {
+			reason: "A Configuration without a lock should be valid.",
+			args: args{
+				deps: []pkgmetav1.Dependency{{Provider: ptr.To("provider-nop"), Version: ">=v0.1.0"}},
+			},
+		},
+		"Valid": {
+			reason: "A lock that satisfies every declared dependency, plus transitive dependencies, should be valid.",
+			args: args{
+				deps: []pkgmetav1.Dependency{
+					{Provider: ptr.To("provider-nop"), Version: ">=v0.1.0"},
+					{Function: ptr.To("function-nop"), Version: digest},
+				},
+				lock: []pkgmetav1.LockedDependency{
+					locked("provider-nop", "v0.2.0", otherDigest),
+					locked("function-nop", "v0.3.0", digest),
+					locked("provider-transitive", "v1.0.0", otherDigest),
+				},
+			},
+		},
+		"MissingFields": {
+			reason: "A locked dependency must specify its apiVersion, kind, and package.",
+			args: args{
+				lock: []pkgmetav1.LockedDependency{{Package: "provider-nop", Version: "v0.2.0", Digest: digest}},
+			},
+			want: errors.Errorf(errFmtLockedDependencyInvalid, "provider-nop"),
+		},
+		"InvalidVersion": {
+			reason: "A locked dependency must be locked to a semantic version.",
+			args: args{
+				lock: []pkgmetav1.LockedDependency{locked("provider-nop", ">=v0.2.0", digest)},
+			},
+			want: errors.Wrapf(errInvalidVersion, errFmtLockedDependencyInvalid, "provider-nop"),
+		},
+		"InvalidDigest": {
+			reason: "A locked dependency must be locked to a valid digest.",
+			args: args{
+				lock: []pkgmetav1.LockedDependency{locked("provider-nop", "v0.2.0", "latest")},
+			},
+			want: errors.Wrapf(errInvalidDigest, errFmtLockedDependencyInvalid, "provider-nop"),
+		},
+		"Duplicate": {
+			reason: "A dependency may only be locked once.",
+			args: args{
+				lock: []pkgmetav1.LockedDependency{
+					locked("provider-nop", "v0.2.0", digest),
+					locked("provider-nop", "v0.3.0", otherDigest),
+				},
+			},
+			want: errors.Errorf(errFmtLockedDependencyDuplicate, "provider-nop"),
+		},
+		"NotLocked": {
+			reason: "Every declared dependency must be locked.",
+			args: args{
+				deps: []pkgmetav1.Dependency{
+					{Provider: ptr.To("provider-nop"), Version: ">=v0.1.0"},
+					{Provider: ptr.To("provider-new"), Version: ">=v0.1.0"},
+				},
+				lock: []pkgmetav1.LockedDependency{locked("provider-nop", "v0.2.0", digest)},
+			},
+			want: errors.Errorf(errFmtDependencyNotLocked, "provider-new"),
+		},
+		"ViolatesConstraint": {
+			reason: "A locked version must satisfy the dependency's version constraint.",
+			args: args{
+				deps: []pkgmetav1.Dependency{{Provider: ptr.To("provider-nop"), Version: ">=v0.3.0"}},
+				lock: []pkgmetav1.LockedDependency{locked("provider-nop", "v0.2.0", digest)},
+			},
+			want: errors.Errorf(errFmtLockViolatesConstraint, "provider-nop", "v0.2.0", ">=v0.3.0"),
+		},
+		"ViolatesDigest": {
+			reason: "A locked digest must match the dependency's digest.",
+			args: args{
+				deps: []pkgmetav1.Dependency{{Provider: ptr.To("provider-nop"), Version: digest}},
+				lock: []pkgmetav1.LockedDependency{locked("provider-nop", "v0.2.0", otherDigest)},
+			},
+			want: errors.Errorf(errFmtLockViolatesConstraint, "provider-nop", otherDigest, digest),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateDependencyLock(tc.args.deps, tc.args.lock)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateDependencyLock(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn/k8schain"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errParseMirrorSource = "cannot parse package source"
+	errFetchUpstream     = "cannot fetch package from upstream registry"
+	errPushMirror        = "cannot push package to mirror registry"
+)
+
+// A Pusher pushes package images.
+type Pusher interface {
+	Push(ctx context.Context, ref name.Reference, img v1.Image, secrets ...string) error
+}
+
+// Push pushes a package image.
+func (i *K8sFetcher) Push(ctx context.Context, ref name.Reference, img v1.Image, secrets ...string) error {
+	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
+		Namespace:          i.namespace,
+		ServiceAccountName: i.serviceAccount,
+		ImagePullSecrets:   secrets,
+	})
+	if err != nil {
+		return err
+	}
+	t, err := i.transportFor(ctx, ref)
+	if err != nil {
+		return err
+	}
+	return remote.Write(ref, img,
+		remote.WithAuthFromKeychain(auth),
+		remote.WithTransport(t),
+		remote.WithContext(ctx),
+		remote.WithUserAgent(i.userAgent),
+	)
+}
+
+// Push does nothing and does not return error.
+func (n *NopFetcher) Push(_ context.Context, _ name.Reference, _ v1.Image, _ ...string) error {
+	return nil
+}
+
+// A Mirror maps package sources to their equivalent in a mirror registry. A
+// package is mirrored to <mirror>/<registry>/<repository>. For example, with
+// the mirror registry.example.org/mirror the package source
+// xpkg.upbound.io/crossplane-contrib/provider-nop is mirrored to
+// registry.example.org/mirror/xpkg.upbound.io/crossplane-contrib/provider-nop.
+type Mirror struct {
+	registry        string
+	defaultRegistry string
+}
+
+// NewMirror returns a Mirror that maps package sources to the supplied mirror
+// registry. Sources that don't specify a registry are assumed to be from the
+// supplied default registry.
+func NewMirror(registry, defaultRegistry string) *Mirror {
+	return &Mirror{registry: strings.TrimSuffix(registry, "/"), defaultRegistry: defaultRegistry}
+}
+
+// Source returns the mirror source of the supplied upstream package source,
+// e.g. xpkg.upbound.io/crossplane-contrib/provider-nop. Sources that are
+// already in the mirror registry are returned unchanged.
+func (m *Mirror) Source(upstream string) (string, error) {
+	if _, ok := m.Upstream(upstream); ok {
+		return upstream, nil
+	}
+	r, err := name.NewRepository(upstream, name.WithDefaultRegistry(m.defaultRegistry))
+	if err != nil {
+		return "", errors.Wrap(err, errParseMirrorSource)
+	}
+	return m.registry + "/" + r.RegistryStr() + "/" + r.RepositoryStr(), nil
+}
+
+// Upstream returns the upstream source of the supplied mirror package source.
+// It returns false if the supplied source isn't in the mirror registry.
+func (m *Mirror) Upstream(source string) (string, bool) {
+	upstream, ok := strings.CutPrefix(source, m.registry+"/")
+	if !ok || upstream == "" {
+		return "", false
+	}
+	return upstream, true
+}
+
+// Copy fetches the supplied package from its upstream registry and pushes it to
+// the supplied mirror reference.
+func Copy(ctx context.Context, f Fetcher, p Pusher, upstream, mirror name.Reference, secrets ...string) error {
+	img, err := f.Fetch(ctx, upstream, secrets...)
+	if err != nil {
+		return errors.Wrap(err, errFetchUpstream)
+	}
+	return errors.Wrap(p.Push(ctx, mirror, img, secrets...), errPushMirror)
+}
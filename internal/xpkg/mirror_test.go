@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestMirrorSource(t *testing.T) {
+	m := NewMirror("registry.example.org/mirror/", "xpkg.upbound.io")
+
+	type want struct {
+		source string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		upstream string
+		want     want
+	}{
+		"ExplicitRegistry": {
+			reason:   "A source with a registry should be mirrored under that registry.",
+			upstream: "index.docker.io/crossplane/provider-nop",
+			want: want{
+				source: "registry.example.org/mirror/index.docker.io/crossplane/provider-nop",
+			},
+		},
+		"DefaultRegistry": {
+			reason:   "A source without a registry should be mirrored under the default registry.",
+			upstream: "crossplane-contrib/provider-nop",
+			want: want{
+				source: "registry.example.org/mirror/xpkg.upbound.io/crossplane-contrib/provider-nop",
+			},
+		},
+		"AlreadyMirrored": {
+			reason:   "A source that's already in the mirror registry should be returned unchanged.",
+			upstream: "registry.example.org/mirror/xpkg.upbound.io/crossplane-contrib/provider-nop",
+			want: want{
+				source: "registry.example.org/mirror/xpkg.upbound.io/crossplane-contrib/provider-nop",
+			},
+		},
+		"Invalid": {
+			reason:   "An invalid source should return an error.",
+			upstream: "NOT/a/VALID/source",
+			want: want{
+				err: cmpopts.AnyError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := m.Source(tc.upstream)
+			if diff := cmp.Diff(tc.want.err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSource(...): -want err, +got err:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.source, got); diff != "" {
+				t.Errorf("\n%s\nSource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMirrorUpstream(t *testing.T) {
+	m := NewMirror("registry.example.org/mirror", "xpkg.upbound.io")
+
+	type want struct {
+		upstream string
+		ok       bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		source string
+		want   want
+	}{
+		"Mirrored": {
+			reason: "A source in the mirror registry should return its upstream source.",
+			source: "registry.example.org/mirror/xpkg.upbound.io/crossplane-contrib/provider-nop",
+			want: want{
+				upstream: "xpkg.upbound.io/crossplane-contrib/provider-nop",
+				ok:       true,
+			},
+		},
+		"NotMirrored": {
+			reason: "A source that isn't in the mirror registry has no upstream source.",
+			source: "xpkg.upbound.io/crossplane-contrib/provider-nop",
+			want: want{
+				ok: false,
+			},
+		},
+		"SimilarPrefix": {
+			reason: "A source in a repository that merely shares a prefix with the mirror isn't mirrored.",
+			source: "registry.example.org/mirrored/crossplane-contrib/provider-nop",
+			want: want{
+				ok: false,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			upstream, ok := m.Upstream(tc.source)
+			if diff := cmp.Diff(tc.want, want{upstream: upstream, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nUpstream(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	kyaml "sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+)
+
+const (
+	errReadPackageStream = "cannot read package stream"
+
+	errFmtParseObject = "cannot parse %s"
+	errFmtLintObject  = "%s is invalid"
+)
+
+// An AggregatingParser parses package contents. Unlike a PackageParser it
+// doesn't stop at the first object it can't parse. Instead it returns an error
+// that describes every object it couldn't parse.
+type AggregatingParser struct {
+	metaScheme parser.ObjectCreaterTyper
+	objScheme  parser.ObjectCreaterTyper
+	wrapped    parser.Parser
+}
+
+// NewAggregatingParser returns a parser that reports every object in a
+// package that can't be parsed.
+func NewAggregatingParser(meta, obj parser.ObjectCreaterTyper) *AggregatingParser {
+	return &AggregatingParser{
+		metaScheme: meta,
+		objScheme:  obj,
+		wrapped:    parser.New(meta, obj),
+	}
+}
+
+// Parse the supplied package contents. If any object can't be parsed Parse
+// returns an error describing all of the objects that couldn't be parsed.
+func (p *AggregatingParser) Parse(ctx context.Context, rc io.ReadCloser) (*parser.Package, error) {
+	if rc == nil {
+		return parser.NewPackage(), nil
+	}
+	defer func() { _ = rc.Close() }()
+
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadPackageStream)
+	}
+
+	// A parser.Package can only be built by the wrapped parser, so we let it
+	// decode the package. We only decode the package's objects ourselves if it
+	// fails, to describe every object that can't be parsed.
+	pkg, err := p.wrapped.Parse(ctx, io.NopCloser(bytes.NewReader(b)))
+	if err == nil {
+		return pkg, nil
+	}
+	if errs := p.parseErrors(b); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return nil, err
+}
+
+// parseErrors returns an error for every object in the supplied package
+// stream that can't be parsed.
+func (p *AggregatingParser) parseErrors(b []byte) []error {
+	yr := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(b)))
+	dm := json.NewSerializerWithOptions(json.DefaultMetaFactory, p.metaScheme, p.metaScheme, json.SerializerOptions{Yaml: true})
+	do := json.NewSerializerWithOptions(json.DefaultMetaFactory, p.objScheme, p.objScheme, json.SerializerOptions{Yaml: true})
+
+	errs := make([]error, 0)
+	for i := 1; ; i++ {
+		content, err := yr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return append(errs, errors.Wrap(err, errReadPackageStream))
+		}
+		if isEmptyYAML(content) {
+			continue
+		}
+		if err := decode(dm, do, content); err != nil {
+			errs = append(errs, errors.Wrapf(err, errFmtParseObject, describeDocument(i, content)))
+		}
+	}
+	return errs
+}
+
+// decode the supplied content using the meta scheme, falling back to the
+// object scheme if the content isn't a package meta type.
+func decode(dm, do runtime.Decoder, content []byte) error {
+	_, _, err := dm.Decode(content, nil, nil)
+	if err == nil {
+		return nil
+	}
+	if !runtime.IsNotRegisteredError(err) {
+		return err
+	}
+	_, _, err = do.Decode(content, nil, nil)
+	return err
+}
+
+// describeDocument returns a human-readable description of the YAML document
+// at the supplied index of a package stream. It includes the document's kind
+// and name, if they can be determined.
+func describeDocument(i int, content []byte) string {
+	o := &metav1.PartialObjectMetadata{}
+	_ = kyaml.Unmarshal(content, o)
+	return describe(fmt.Sprintf("document %d", i), o.Kind, o.GetName())
+}
+
+// describeObject returns a human-readable description of the object at the
+// supplied index of a package.
+func describeObject(prefix string, i int, o runtime.Object) string {
+	name := ""
+	if a, err := kmeta.Accessor(o); err == nil {
+		name = a.GetName()
+	}
+	return describe(fmt.Sprintf("%s %d", prefix, i), o.GetObjectKind().GroupVersionKind().Kind, name)
+}
+
+func describe(what, kind, name string) string {
+	switch {
+	case kind != "" && name != "":
+		return fmt.Sprintf("%s (%s %q)", what, kind, name)
+	case kind != "":
+		return fmt.Sprintf("%s (%s)", what, kind)
+	case name != "":
+		return fmt.Sprintf("%s (%q)", what, name)
+	default:
+		return what
+	}
+}
+
+// isEmptyYAML checks whether the provided YAML can be considered empty.
+func isEmptyYAML(y []byte) bool {
+	for _, line := range strings.Split(string(y), "\n") {
+		trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+		if trimmed != "" && trimmed != "---" && trimmed != "..." && !strings.HasPrefix(trimmed, "#") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestAggregatingParser(t *testing.T) {
+	badCompBytes := []byte(`apiVersion: apiextensions.crossplane.io/v1
+kind: Composition
+metadata:
+  name: bad
+spec:
+  resources: "not-a-list"`)
+
+	badXRDBytes := []byte(`apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: bad
+spec:
+  versions: 42`)
+
+	type want struct {
+		meta    []runtime.Object
+		objects []runtime.Object
+		err     string
+	}
+
+	cases := map[string]struct {
+		reason string
+		docs   [][]byte
+		want   want
+	}{
+		"Successful": {
+			reason: "Should parse a package in which every object is valid.",
+			docs:   [][]byte{v1ConfBytes, v1XRDBytes, v1CompBytes},
+			want: want{
+				meta:    []runtime.Object{v1ConfMeta},
+				objects: []runtime.Object{v1XRD, v1Comp},
+			},
+		},
+		"InvalidObjects": {
+			reason: "Should return an error describing every object that can't be parsed.",
+			docs:   [][]byte{v1ConfBytes, badCompBytes, v1XRDBytes, badXRDBytes},
+			want: want{
+				err: `[cannot parse document 2 (Composition "bad"): json: cannot unmarshal string into Go struct field CompositionSpec.spec.resources of type []v1.ComposedTemplate, ` +
+					`cannot parse document 4 (CompositeResourceDefinition "bad"): json: cannot unmarshal number into Go struct field CompositeResourceDefinitionSpec.spec.versions of type []v1.CompositeResourceDefinitionVersion]`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := bytes.NewReader(bytes.Join(tc.docs, []byte("\n---\n")))
+			pkg, err := NewAggregatingParser(meta, obj).Parse(context.TODO(), io.NopCloser(r))
+
+			if tc.want.err != "" {
+				if diff := cmp.Diff(tc.want.err, fmt.Sprint(err)); diff != "" {
+					t.Errorf("\n%s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
+				}
+				return
+			}
+			if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.meta, pkg.GetMeta()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want meta, +got meta:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objects, pkg.GetObjects()); diff != "" {
+				t.Errorf("\n%s\nParse(...): -want objects, +got objects:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}